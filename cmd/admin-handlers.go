@@ -18,8 +18,12 @@ package cmd
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/url"
+	"runtime"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -42,7 +46,7 @@ func (adminAPI adminAPIHandlers) ServiceStatusHandler(w http.ResponseWriter, r *
 	jsonBytes, err := json.Marshal(storageInfo)
 	if err != nil {
 		writeErrorResponse(w, ErrInternalError, r.URL)
-		errorIf(err, "Failed to marshal storage info into json.")
+		requestErrorIf(r, err, "Failed to marshal storage info into json.")
 		return
 	}
 	// Reply with storage information (across nodes in a
@@ -50,6 +54,53 @@ func (adminAPI adminAPIHandlers) ServiceStatusHandler(w http.ResponseWriter, r *
 	writeSuccessResponseJSON(w, jsonBytes)
 }
 
+// ServerInfo - build information and uptime of a server.
+type ServerInfo struct {
+	// Address of the server, as in the endpoints.
+	Addr       string        `json:"addr"`
+	Version    string        `json:"version"`
+	ReleaseTag string        `json:"releaseTag"`
+	CommitID   string        `json:"commitID"`
+	GoVersion  string        `json:"goVersion"`
+	BootTime   time.Time     `json:"bootTime"`
+	Uptime     time.Duration `json:"uptime"`
+	// Set if the server could not be reached.
+	Error string `json:"error,omitempty"`
+}
+
+// getServerInfo - returns build information and uptime of this server.
+func getServerInfo() ServerInfo {
+	return ServerInfo{
+		Addr:       globalMinioAddr,
+		Version:    Version,
+		ReleaseTag: ReleaseTag,
+		CommitID:   CommitID,
+		GoVersion:  runtime.Version(),
+		BootTime:   globalBootTime,
+		Uptime:     time.Now().UTC().Sub(globalBootTime),
+	}
+}
+
+// ServerInfoHandler - GET /?service
+// HTTP header x-minio-operation: info
+// ----------
+// Returns build information and uptime of each server in the cluster.
+func (adminAPI adminAPIHandlers) ServerInfoHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(getPeerServerInfo(globalAdminPeers))
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		requestErrorIf(r, err, "Failed to marshal server info into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
 // ServiceRestartHandler - POST /?service
 // HTTP header x-minio-operation: restart
 // ----------
@@ -133,7 +184,7 @@ func (adminAPI adminAPIHandlers) ListLocksHandler(w http.ResponseWriter, r *http
 	volLocks, err := listPeerLocksInfo(globalAdminPeers, bucket, prefix, relTime)
 	if err != nil {
 		writeErrorResponse(w, ErrInternalError, r.URL)
-		errorIf(err, "Failed to fetch lock information from remote nodes.")
+		requestErrorIf(r, err, "Failed to fetch lock information from remote nodes.")
 		return
 	}
 
@@ -141,7 +192,7 @@ func (adminAPI adminAPIHandlers) ListLocksHandler(w http.ResponseWriter, r *http
 	jsonBytes, err := json.Marshal(volLocks)
 	if err != nil {
 		writeErrorResponse(w, ErrInternalError, r.URL)
-		errorIf(err, "Failed to marshal lock information into json.")
+		requestErrorIf(r, err, "Failed to marshal lock information into json.")
 		return
 	}
 
@@ -175,7 +226,7 @@ func (adminAPI adminAPIHandlers) ClearLocksHandler(w http.ResponseWriter, r *htt
 	volLocks, err := listPeerLocksInfo(globalAdminPeers, bucket, prefix, relTime)
 	if err != nil {
 		writeErrorResponse(w, ErrInternalError, r.URL)
-		errorIf(err, "Failed to fetch lock information from remote nodes.")
+		requestErrorIf(r, err, "Failed to fetch lock information from remote nodes.")
 		return
 	}
 
@@ -183,7 +234,7 @@ func (adminAPI adminAPIHandlers) ClearLocksHandler(w http.ResponseWriter, r *htt
 	jsonBytes, err := json.Marshal(volLocks)
 	if err != nil {
 		writeErrorResponse(w, ErrInternalError, r.URL)
-		errorIf(err, "Failed to marshal lock information into json.")
+		requestErrorIf(r, err, "Failed to marshal lock information into json.")
 		return
 	}
 
@@ -195,3 +246,699 @@ func (adminAPI adminAPIHandlers) ClearLocksHandler(w http.ResponseWriter, r *htt
 	// Reply with list of locks cleared, as json.
 	writeSuccessResponseJSON(w, jsonBytes)
 }
+
+// validateAvoidDiskRequest - validates avoid disk query params, the
+// endpoint must be one of the disks of the cluster, and verifies that
+// the backend can afford another avoided disk without losing write
+// quorum.
+func validateAvoidDiskRequest(vars url.Values, avoid bool) (string, APIErrorCode) {
+	endpoint := vars.Get("endpoint")
+	eps, err := parseStorageEndpoints([]string{endpoint})
+	if err != nil {
+		return "", ErrInvalidEndpoint
+	}
+	if err = checkEndpointURL(eps[0]); err != nil {
+		return "", ErrInvalidEndpoint
+	}
+
+	objAPI := newObjectLayerFn()
+	storageInfo := objAPI.StorageInfo()
+	if storageInfo.Backend.Type != XL {
+		// Disk placement is only meaningful for erasure coded backend.
+		return "", ErrNotImplemented
+	}
+	if !isClusterEndpoint(objAPI, eps[0]) {
+		return "", ErrEndpointNotFound
+	}
+	if avoid && globalAvoidedDisks.isAvoidedEndpoint(eps[0]) {
+		// Disk is already avoided, nothing to validate.
+		return endpoint, ErrNone
+	}
+
+	// Remaining disks should satisfy write quorum.
+	remainingDisks := storageInfo.Backend.OnlineDisks - storageInfo.Backend.AvoidedDisks
+	if avoid && remainingDisks-1 < storageInfo.Backend.WriteQuorum {
+		return "", ErrWriteQuorum
+	}
+	return endpoint, ErrNone
+}
+
+// AvoidDiskHandler - POST /?disk&endpoint=http://host:port/path
+// HTTP header x-minio-operation: avoid
+// ----------
+// Excludes a disk from placement of new writes on all the servers in
+// the cluster, disk continues to be online for reads.
+func (adminAPI adminAPIHandlers) AvoidDiskHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPI.avoidDisk(w, r, true)
+}
+
+// UnavoidDiskHandler - POST /?disk&endpoint=http://host:port/path
+// HTTP header x-minio-operation: unavoid
+// ----------
+// Includes a previously avoided disk back for placement of new writes.
+func (adminAPI adminAPIHandlers) UnavoidDiskHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPI.avoidDisk(w, r, false)
+}
+
+// avoidDisk - common handler for avoid and unavoid disk APIs.
+func (adminAPI adminAPIHandlers) avoidDisk(w http.ResponseWriter, r *http.Request, avoid bool) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	endpoint, adminAPIErr := validateAvoidDiskRequest(r.URL.Query(), avoid)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	if err := sendAvoidDiskCmd(globalAdminPeers, endpoint, avoid); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		requestErrorIf(r, err, "Failed to update avoided disks on remote nodes.")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// TenantAccountingHandler - GET /?accounting
+// HTTP header x-minio-operation: tenants
+// ----------
+// Returns request count, bytes in/out and error count per access key
+// aggregated across all servers in the cluster.
+func (adminAPI adminAPIHandlers) TenantAccountingHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	tenants, err := getPeerTenantStats(globalAdminPeers)
+	if err != nil {
+		requestErrorIf(r, err, "Failed to fetch request accounting from peers.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(tenants)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		requestErrorIf(r, err, "Failed to marshal request accounting into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// Number of objects read from the object layer at a time by
+// ListAllObjectsHandler.
+const adminListObjectsBatch = 100
+
+// Default maximum number of objects per second listed by
+// ListAllObjectsHandler, protects disks serving regular requests.
+const defaultAdminListObjectsRate = 1000
+
+// AdminObjectInfo - single object listed by ListAllObjectsHandler.
+type AdminObjectInfo struct {
+	Bucket  string    `json:"bucket"`
+	Key     string    `json:"key"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+}
+
+// AdminListObjectsEnd - last record of ListAllObjectsHandler response,
+// NextMarker continues the listing when IsTruncated is set.
+type AdminListObjectsEnd struct {
+	IsTruncated bool   `json:"isTruncated"`
+	NextMarker  string `json:"nextMarker,omitempty"`
+}
+
+// parseAdminListObjectsMarker - splits a marker of the form
+// bucket/object into bucket and object.
+func parseAdminListObjectsMarker(marker string) (bucket, object string) {
+	if i := strings.Index(marker, slashSeparator); i >= 0 {
+		return marker[:i], marker[i+1:]
+	}
+	return marker, ""
+}
+
+// ListAllObjectsHandler - GET /?object&marker=bucket/object&max-keys=N&rate=N
+// - marker, max-keys and rate are optional query parameters
+// HTTP header x-minio-operation: list
+// ----------
+// Streams objects of all buckets in lexical order as one json record
+// per line, followed by an AdminListObjectsEnd record. Without
+// max-keys all the objects are listed. Objects are read in batches
+// from the object layer and listing is paced to rate objects per
+// second, defaultAdminListObjectsRate unless set.
+func (adminAPI adminAPIHandlers) ListAllObjectsHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := r.URL.Query()
+	maxKeys := -1
+	if maxKeysStr := vars.Get("max-keys"); maxKeysStr != "" {
+		var err error
+		if maxKeys, err = strconv.Atoi(maxKeysStr); err != nil || maxKeys <= 0 {
+			writeErrorResponse(w, ErrInvalidMaxKeys, r.URL)
+			return
+		}
+	}
+	rate := defaultAdminListObjectsRate
+	if rateStr := vars.Get("rate"); rateStr != "" {
+		var err error
+		if rate, err = strconv.Atoi(rateStr); err != nil || rate <= 0 {
+			writeErrorResponse(w, ErrInvalidListRate, r.URL)
+			return
+		}
+	}
+	markerBucket, markerObject := parseAdminListObjectsMarker(vars.Get("marker"))
+
+	buckets, err := objectAPI.ListBuckets()
+	if err != nil {
+		requestErrorIf(r, err, "Failed to list buckets.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	w.Header().Set("Content-Type", string(mimeJSON))
+	w.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(w)
+
+	startTime := time.Now()
+	listed := 0
+	for _, bucket := range buckets {
+		if bucket.Name < markerBucket {
+			continue
+		}
+		marker := ""
+		if bucket.Name == markerBucket {
+			marker = markerObject
+		}
+		for {
+			batch := adminListObjectsBatch
+			if maxKeys >= 0 && maxKeys-listed < batch {
+				batch = maxKeys - listed
+			}
+			if batch == 0 {
+				// Listing is truncated, objects are left to be listed
+				// in this bucket or in the following buckets.
+				encoder.Encode(AdminListObjectsEnd{
+					IsTruncated: true,
+					NextMarker:  bucket.Name + slashSeparator + marker,
+				})
+				return
+			}
+
+			result, err := objectAPI.ListObjects(bucket.Name, "", marker, "", batch)
+			if err != nil {
+				// Response is already started, the missing
+				// end record indicates the failure.
+				requestErrorIf(r, err, "Failed to list objects of %s.", bucket.Name)
+				return
+			}
+			for _, object := range result.Objects {
+				if err = encoder.Encode(AdminObjectInfo{
+					Bucket:  object.Bucket,
+					Key:     object.Name,
+					Size:    object.Size,
+					ModTime: object.ModTime,
+				}); err != nil {
+					// Client went away.
+					return
+				}
+				marker = object.Name
+			}
+			listed += len(result.Objects)
+			if flusher, ok := w.(http.Flusher); ok {
+				flusher.Flush()
+			}
+
+			// Pace listing to the requested rate.
+			if elapsed, expected := time.Since(startTime), time.Duration(listed)*time.Second/time.Duration(rate); elapsed < expected {
+				time.Sleep(expected - elapsed)
+			}
+
+			if !result.IsTruncated {
+				break
+			}
+		}
+	}
+	encoder.Encode(AdminListObjectsEnd{})
+}
+
+// HealObjectResult - state of an object healed by HealObjectsHandler,
+// Error is set instead of Status if the object could not be healed.
+type HealObjectResult struct {
+	Object string     `json:"object"`
+	Status HealStatus `json:"status,omitempty"`
+	Error  string     `json:"error,omitempty"`
+}
+
+// HealObjectsResponse - response of HealObjectsHandler, NextMarker
+// continues healing when IsTruncated is set.
+type HealObjectsResponse struct {
+	Objects     []HealObjectResult `json:"objects"`
+	IsTruncated bool               `json:"isTruncated"`
+	NextMarker  string             `json:"nextMarker,omitempty"`
+}
+
+// healObjects - verifies and heals objects of a bucket through the
+// object layer of this server.
+func healObjects(bucket string, objects []string) ([]HealObjectResult, error) {
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		return nil, errServerNotInitialized
+	}
+
+	results := make([]HealObjectResult, len(objects))
+	for i, object := range objects {
+		results[i].Object = object
+		status, err := objectAPI.VerifyHealObject(bucket, object)
+		if err != nil {
+			errorIf(err, "Failed to heal %s/%s.", bucket, object)
+			results[i].Error = errorCause(err).Error()
+			continue
+		}
+		results[i].Status = status
+	}
+	return results, nil
+}
+
+// HealObjectsHandler - POST /?object&bucket=mybucket&prefix=myprefix&marker=object&max-keys=N
+// - prefix, marker and max-keys are optional query parameters
+// HTTP header x-minio-operation: heal
+// ----------
+// Verifies the parts of the objects of a bucket matching prefix
+// against their checksums and rebuilds the missing and corrupted ones
+// from parity. Up to max-keys objects, 1000 by default, are healed per
+// request, shared out between the servers of the cluster. Replies with
+// the state of each object, healthy, healed or corrupt.
+func (adminAPI adminAPIHandlers) HealObjectsHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	vars := r.URL.Query()
+	bucket := vars.Get("bucket")
+	if !IsValidBucketName(bucket) {
+		writeErrorResponse(w, ErrInvalidBucketName, r.URL)
+		return
+	}
+	maxKeys := maxObjectList
+	if maxKeysStr := vars.Get("max-keys"); maxKeysStr != "" {
+		var err error
+		if maxKeys, err = strconv.Atoi(maxKeysStr); err != nil || maxKeys <= 0 || maxKeys > maxObjectList {
+			writeErrorResponse(w, ErrInvalidMaxKeys, r.URL)
+			return
+		}
+	}
+
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	result, err := objectAPI.ListObjects(bucket, vars.Get("prefix"), vars.Get("marker"), "", maxKeys)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	objects := make([]string, len(result.Objects))
+	for i, object := range result.Objects {
+		objects[i] = object.Name
+	}
+
+	response := HealObjectsResponse{
+		Objects:     []HealObjectResult{},
+		IsTruncated: result.IsTruncated,
+	}
+	if len(objects) > 0 {
+		if response.Objects, err = sendHealObjectsCmd(globalAdminPeers, bucket, objects); err != nil {
+			requestErrorIf(r, err, "Failed to heal objects on remote nodes.")
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+		if result.IsTruncated {
+			response.NextMarker = objects[len(objects)-1]
+		}
+	}
+
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		requestErrorIf(r, err, "Failed to marshal heal objects response into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// ListPendingHealsHandler - GET /?object
+// HTTP header x-minio-operation: heal
+// ----------
+// Lists objects written without laggard disks which are yet to be
+// healed on them, including the ones healing in background gave up
+// on. Objects are recorded on the disks, the listing covers writes
+// of all the servers in the cluster.
+func (adminAPI adminAPIHandlers) ListPendingHealsHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	infos, err := objectAPI.ListPendingHeals()
+	if err != nil {
+		requestErrorIf(r, err, "Failed to list objects pending heal.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	if infos == nil {
+		infos = []PendingHealInfo{}
+	}
+
+	jsonBytes, err := json.Marshal(infos)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		requestErrorIf(r, err, "Failed to marshal objects pending heal into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// DecommissionObjectResult - state of an object drained by
+// DecommissionDiskHandler, Relocated is set if its shards were moved
+// off the disk and Error if the object could not be relocated.
+type DecommissionObjectResult struct {
+	Bucket    string `json:"bucket"`
+	Object    string `json:"object"`
+	Relocated bool   `json:"relocated"`
+	Error     string `json:"error,omitempty"`
+}
+
+// DecommissionDiskResponse - response of DecommissionDiskHandler,
+// Relocated counts the objects of this response moved off the disk.
+// NextMarker continues draining the disk when IsTruncated is set.
+type DecommissionDiskResponse struct {
+	Objects     []DecommissionObjectResult `json:"objects"`
+	Relocated   int                        `json:"relocated"`
+	IsTruncated bool                       `json:"isTruncated"`
+	NextMarker  string                     `json:"nextMarker,omitempty"`
+}
+
+// drainObjects - relocates shards of objects of a bucket off the
+// avoided disks through the object layer of this server.
+func drainObjects(bucket string, objects []string) ([]DecommissionObjectResult, error) {
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		return nil, errServerNotInitialized
+	}
+
+	results := make([]DecommissionObjectResult, len(objects))
+	for i, object := range objects {
+		results[i].Bucket = bucket
+		results[i].Object = object
+		relocated, err := objectAPI.DrainObject(bucket, object)
+		if err != nil {
+			errorIf(err, "Failed to relocate %s/%s.", bucket, object)
+			results[i].Error = errorCause(err).Error()
+			continue
+		}
+		results[i].Relocated = relocated
+	}
+	return results, nil
+}
+
+// DecommissionDiskHandler - POST /?disk&endpoint=http://host:port/path&marker=bucket/object&max-keys=N
+// - marker and max-keys are optional query parameters
+// HTTP header x-minio-operation: decommission
+// ----------
+// Marks a disk for decommission by excluding it from placement of new
+// writes on all the servers in the cluster, then relocates the shards
+// of objects held by the disk to the remaining disks. Objects of all
+// buckets are drained in lexical order, up to max-keys objects, 1000
+// by default, per request shared out between the servers. Replies
+// with the state of each object, the disk can be removed once a
+// response is not truncated.
+func (adminAPI adminAPIHandlers) DecommissionDiskHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	vars := r.URL.Query()
+	endpoint, adminAPIErr := validateAvoidDiskRequest(vars, true)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+	maxKeys := maxObjectList
+	if maxKeysStr := vars.Get("max-keys"); maxKeysStr != "" {
+		var err error
+		if maxKeys, err = strconv.Atoi(maxKeysStr); err != nil || maxKeys <= 0 || maxKeys > maxObjectList {
+			writeErrorResponse(w, ErrInvalidMaxKeys, r.URL)
+			return
+		}
+	}
+
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	// No new shards are placed on the disk from now on.
+	if err := sendAvoidDiskCmd(globalAdminPeers, endpoint, true); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		requestErrorIf(r, err, "Failed to update avoided disks on remote nodes.")
+		return
+	}
+
+	buckets, err := objectAPI.ListBuckets()
+	if err != nil {
+		requestErrorIf(r, err, "Failed to list buckets.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	markerBucket, markerObject := parseAdminListObjectsMarker(vars.Get("marker"))
+	response := DecommissionDiskResponse{
+		Objects: []DecommissionObjectResult{},
+	}
+	for _, bucket := range buckets {
+		if bucket.Name < markerBucket {
+			continue
+		}
+		if len(response.Objects) == maxKeys {
+			// Objects are left to be drained in the following buckets.
+			response.IsTruncated = true
+			break
+		}
+		marker := ""
+		if bucket.Name == markerBucket {
+			marker = markerObject
+		}
+
+		result, err := objectAPI.ListObjects(bucket.Name, "", marker, "", maxKeys-len(response.Objects))
+		if err != nil {
+			requestErrorIf(r, err, "Failed to list objects of %s.", bucket.Name)
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+		objects := make([]string, len(result.Objects))
+		for i, object := range result.Objects {
+			objects[i] = object.Name
+		}
+		if len(objects) > 0 {
+			results, err := sendDrainObjectsCmd(globalAdminPeers, bucket.Name, objects)
+			if err != nil {
+				requestErrorIf(r, err, "Failed to relocate objects on remote nodes.")
+				writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+				return
+			}
+			response.Objects = append(response.Objects, results...)
+			response.NextMarker = bucket.Name + slashSeparator + objects[len(objects)-1]
+		}
+		if result.IsTruncated {
+			response.IsTruncated = true
+			break
+		}
+	}
+	if !response.IsTruncated {
+		response.NextMarker = ""
+	}
+	for _, result := range response.Objects {
+		if result.Relocated {
+			response.Relocated++
+		}
+	}
+
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		requestErrorIf(r, err, "Failed to marshal decommission disk response into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// RebuildBucketIndexHandler - POST /?bucket-index
+// HTTP header x-minio-operation: rebuild
+// ----------
+// Reconciles buckets found on the disks with their metadata. Buckets
+// missing on some of the disks are healed and metadata left behind by
+// deleted buckets is removed. Returns a json formatted
+// BucketIndexReport listing the discrepancies found.
+func (adminAPI adminAPIHandlers) RebuildBucketIndexHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	report, err := objectAPI.RebuildBucketIndex()
+	if err != nil {
+		requestErrorIf(r, err, "Failed to rebuild bucket index.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(report)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		requestErrorIf(r, err, "Failed to marshal bucket index report into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// validateBucketQuotaRequest - validates set bucket quota query params.
+func validateBucketQuotaRequest(vars url.Values) (string, int64, APIErrorCode) {
+	bucket := vars.Get("bucket")
+	if !IsValidBucketName(bucket) {
+		return "", 0, ErrInvalidBucketName
+	}
+	quota, err := strconv.ParseInt(vars.Get("quota"), 10, 64)
+	if err != nil || quota < 0 {
+		return "", 0, ErrInvalidBucketQuota
+	}
+	return bucket, quota, ErrNone
+}
+
+// SetBucketQuotaHandler - POST /?bucket-quota&bucket=mybucket&quota=bytes
+// HTTP header x-minio-operation: set
+// ----------
+// Sets the maximum number of bytes stored in a bucket on all the
+// servers in the cluster, writes beyond it are rejected. Quota of
+// zero removes the quota of the bucket.
+func (adminAPI adminAPIHandlers) SetBucketQuotaHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	bucket, quota, adminAPIErr := validateBucketQuotaRequest(r.URL.Query())
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	// Usage of buckets is only known per server.
+	if globalIsDistXL && quota != 0 {
+		writeErrorResponse(w, ErrBucketQuotaNotSupported, r.URL)
+		return
+	}
+
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+	if _, err := objectAPI.GetBucketInfo(bucket); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	if err := sendSetBucketQuotaCmd(globalAdminPeers, bucket, quota); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		requestErrorIf(r, err, "Failed to set bucket quota on remote nodes.")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// SetBucketLifecycleHandler - POST /?bucket-lifecycle&bucket=mybucket
+// HTTP header x-minio-operation: set
+// ----------
+// Sets expiration rules of objects of a bucket on all the servers in
+// the cluster, sent as a JSON list of rules in the request body. An
+// empty list removes the rules of the bucket.
+func (adminAPI adminAPIHandlers) SetBucketLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	bucket := r.URL.Query().Get("bucket")
+	if !IsValidBucketName(bucket) {
+		writeErrorResponse(w, ErrInvalidBucketName, r.URL)
+		return
+	}
+
+	var rules []lifecycleRule
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxLifecycleConfigSize)).Decode(&rules); err != nil {
+		writeErrorResponse(w, ErrInvalidBucketLifecycle, r.URL)
+		return
+	}
+	if !isValidLifecycleRules(rules) {
+		writeErrorResponse(w, ErrInvalidBucketLifecycle, r.URL)
+		return
+	}
+
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+	if _, err := objectAPI.GetBucketInfo(bucket); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	if err := sendSetBucketLifecycleCmd(globalAdminPeers, bucket, rules); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		requestErrorIf(r, err, "Failed to set bucket lifecycle on remote nodes.")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
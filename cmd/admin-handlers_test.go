@@ -17,12 +17,19 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	router "github.com/gorilla/mux"
 )
@@ -167,7 +174,7 @@ func testServicesCmdHandler(cmd cmdType, t *testing.T) {
 		if jsonErr := json.Unmarshal(rec.Body.Bytes(), &receivedInfo); jsonErr != nil {
 			t.Errorf("Failed to unmarshal StorageInfo - %v", jsonErr)
 		}
-		if expectedInfo != receivedInfo {
+		if !reflect.DeepEqual(expectedInfo, receivedInfo) {
 			t.Errorf("Expected storage info and received storage info differ, %v %v", expectedInfo, receivedInfo)
 		}
 	}
@@ -410,3 +417,594 @@ func TestValidateLockQueryParams(t *testing.T) {
 		}
 	}
 }
+
+// Test for avoid and unavoid disk management REST APIs.
+func TestAvoidDiskHandler(t *testing.T) {
+	// reset globals.
+	// this is to make sure that the tests are not affected by modified globals.
+	resetTestGlobals()
+	// initialize NSLock.
+	initNSLock(false)
+
+	rootPath, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	objLayer, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatalf("Failed to initialize XL based object layer - %v.", err)
+	}
+	defer removeRoots(fsDirs)
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = objLayer
+	globalObjLayerMutex.Unlock()
+
+	// Initialize admin peers to make admin RPC calls.
+	eps, err := parseStorageEndpoints([]string{"http://localhost"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	// 16 disks with a write quorum of 9 can afford 7 avoided disks.
+	testCases := []struct {
+		endpoint       string
+		op             string
+		expectedStatus int
+		expectedCount  int
+	}{
+		// Test 1 - avoid a valid disk.
+		{fsDirs[0], "avoid", http.StatusOK, 1},
+		// Test 2 - avoiding the same disk again is idempotent.
+		{fsDirs[0], "avoid", http.StatusOK, 1},
+		// Test 3 - invalid endpoint.
+		{"", "avoid", http.StatusBadRequest, 1},
+		// Test 4 - avoid up to the quorum limit.
+		{fsDirs[1], "avoid", http.StatusOK, 2},
+		{fsDirs[2], "avoid", http.StatusOK, 3},
+		{fsDirs[3], "avoid", http.StatusOK, 4},
+		{fsDirs[4], "avoid", http.StatusOK, 5},
+		{fsDirs[5], "avoid", http.StatusOK, 6},
+		{fsDirs[6], "avoid", http.StatusOK, 7},
+		// Test 10 - avoiding one more disk should fail write quorum.
+		{fsDirs[7], "avoid", http.StatusServiceUnavailable, 7},
+		// Test 11 - unavoid a disk.
+		{fsDirs[0], "unavoid", http.StatusOK, 6},
+		// Test 12 - endpoints which are not disks of the cluster.
+		{filepath.Join(globalTestTmpDir, "minio-"+nextSuffix()), "avoid", http.StatusBadRequest, 6},
+		{"http://remote-host:9000" + fsDirs[1], "unavoid", http.StatusBadRequest, 6},
+	}
+
+	adminRouter := router.NewRouter()
+	registerAdminRouter(adminRouter)
+
+	for i, test := range testCases {
+		queryVal := url.Values{}
+		queryVal.Set("endpoint", test.endpoint)
+		req, err := newTestRequest("POST", "/?disk&"+queryVal.Encode(), 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d - Failed to construct avoid disk request - %v", i+1, err)
+		}
+		req.Header.Set(minioAdminOpHeader, test.op)
+
+		cred := serverConfig.GetCredential()
+		err = signRequestV4(req, cred.AccessKey, cred.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d - Failed to sign avoid disk request - %v", i+1, err)
+		}
+		rec := httptest.NewRecorder()
+		adminRouter.ServeHTTP(rec, req)
+		if test.expectedStatus != rec.Code {
+			t.Errorf("Test %d - Expected HTTP status code %d but received %d", i+1, test.expectedStatus, rec.Code)
+		}
+		if count := objLayer.StorageInfo().Backend.AvoidedDisks; count != test.expectedCount {
+			t.Errorf("Test %d - Expected %d avoided disks but found %d", i+1, test.expectedCount, count)
+		}
+	}
+}
+
+// Test for listing objects of all buckets with pagination.
+func TestListAllObjectsHandler(t *testing.T) {
+	// reset globals.
+	// this is to make sure that the tests are not affected by modified globals.
+	resetTestGlobals()
+	// initialize NSLock.
+	initNSLock(false)
+
+	rootPath, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatalf("Failed to initialize FS based object layer - %v.", err)
+	}
+	defer removeRoots([]string{fsDir})
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = objLayer
+	globalObjLayerMutex.Unlock()
+
+	var expected []AdminObjectInfo
+	for _, bucket := range []string{"bucket-a", "bucket-b", "bucket-c"} {
+		if err = objLayer.MakeBucket(bucket); err != nil {
+			t.Fatal(err)
+		}
+		// bucket-b is left empty.
+		if bucket == "bucket-b" {
+			continue
+		}
+		for _, object := range []string{"dir/object", "object-1", "object-2"} {
+			objInfo, err := objLayer.PutObject(bucket, object, 4, bytes.NewReader([]byte("abcd")), nil, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected = append(expected, AdminObjectInfo{bucket, object, objInfo.Size, objInfo.ModTime})
+		}
+	}
+
+	adminRouter := router.NewRouter()
+	registerAdminRouter(adminRouter)
+
+	listAll := func(query string) ([]AdminObjectInfo, AdminListObjectsEnd, int) {
+		req, err := newTestRequest("GET", "/?object"+query, 0, nil)
+		if err != nil {
+			t.Fatalf("Failed to construct list objects request - %v", err)
+		}
+		req.Header.Set(minioAdminOpHeader, "list")
+		cred := serverConfig.GetCredential()
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Failed to sign list objects request - %v", err)
+		}
+		rec := httptest.NewRecorder()
+		adminRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			return nil, AdminListObjectsEnd{}, rec.Code
+		}
+
+		var objects []AdminObjectInfo
+		var end AdminListObjectsEnd
+		lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+		for i, line := range lines {
+			if i == len(lines)-1 {
+				if err = json.Unmarshal([]byte(line), &end); err != nil {
+					t.Fatalf("Failed to parse end record %s - %v", line, err)
+				}
+				break
+			}
+			var object AdminObjectInfo
+			if err = json.Unmarshal([]byte(line), &object); err != nil {
+				t.Fatalf("Failed to parse object record %s - %v", line, err)
+			}
+			objects = append(objects, object)
+		}
+		return objects, end, rec.Code
+	}
+
+	// List all the objects at once.
+	objects, end, code := listAll("")
+	if code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, code)
+	}
+	if end.IsTruncated || len(objects) != len(expected) {
+		t.Fatalf("Expected %d objects, got %d, truncated %t", len(expected), len(objects), end.IsTruncated)
+	}
+	for i := range objects {
+		if objects[i].Bucket != expected[i].Bucket || objects[i].Key != expected[i].Key ||
+			objects[i].Size != expected[i].Size || !objects[i].ModTime.Equal(expected[i].ModTime) {
+			t.Errorf("Object %d - expected %v, got %v", i+1, expected[i], objects[i])
+		}
+	}
+
+	// List objects two at a time, pages span across buckets.
+	var paged []AdminObjectInfo
+	marker := ""
+	for {
+		objects, end, code = listAll("&max-keys=2&rate=100000&marker=" + url.QueryEscape(marker))
+		if code != http.StatusOK {
+			t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, code)
+		}
+		if len(objects) > 2 {
+			t.Fatalf("Expected at most 2 objects per page, got %d", len(objects))
+		}
+		paged = append(paged, objects...)
+		if !end.IsTruncated {
+			break
+		}
+		marker = end.NextMarker
+	}
+	if len(paged) != len(expected) {
+		t.Fatalf("Expected %d objects, got %d", len(expected), len(paged))
+	}
+	for i := range paged {
+		if paged[i].Bucket != expected[i].Bucket || paged[i].Key != expected[i].Key {
+			t.Errorf("Object %d - expected %s/%s, got %s/%s", i+1, expected[i].Bucket, expected[i].Key, paged[i].Bucket, paged[i].Key)
+		}
+	}
+
+	// Invalid max-keys.
+	if _, _, code = listAll("&max-keys=-1"); code != http.StatusBadRequest {
+		t.Errorf("Expected HTTP status code %d but received %d", http.StatusBadRequest, code)
+	}
+	// Invalid rate.
+	for _, rate := range []string{"0", "-1", "fast"} {
+		if _, _, code = listAll("&rate=" + rate); code != http.StatusBadRequest {
+			t.Errorf("Rate %s: expected HTTP status code %d but received %d", rate, http.StatusBadRequest, code)
+		}
+	}
+}
+
+// Test for healing the objects of a bucket matching a prefix.
+func TestHealObjectsHandler(t *testing.T) {
+	// reset globals.
+	// this is to make sure that the tests are not affected by modified globals.
+	resetTestGlobals()
+	// initialize NSLock.
+	initNSLock(false)
+
+	rootPath, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	objLayer, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatalf("Failed to initialize XL based object layer - %v.", err)
+	}
+	defer removeRoots(fsDirs)
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = objLayer
+	globalObjLayerMutex.Unlock()
+
+	// Initialize admin peers to make admin RPC calls.
+	eps, err := parseStorageEndpoints([]string{"http://localhost"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	bucket := "bucket"
+	if err = objLayer.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	for _, object := range []string{"dir/object-1", "dir/object-2", "dir/object-3", "object"} {
+		if _, err = objLayer.PutObject(bucket, object, 4, bytes.NewReader([]byte("abcd")), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Corrupt a data block of dir/object-2 on one disk.
+	xl := objLayer.(*xlObjects)
+	corruptObjectPart(t, xl.storageDisks[0], bucket, "dir/object-2")
+	// Record dir/object-2 as given up on by healing in background.
+	pendingHeal := PendingHealInfo{Bucket: bucket, Object: "dir/object-2", Failed: true}
+	if err = saveLaggardHealRecord(xl.storageDisks, pendingHeal); err != nil {
+		t.Fatal(err)
+	}
+
+	adminRouter := router.NewRouter()
+	registerAdminRouter(adminRouter)
+
+	listPendingHeals := func() []PendingHealInfo {
+		req, err := newTestRequest("GET", "/?object", 0, nil)
+		if err != nil {
+			t.Fatalf("Failed to construct list pending heals request - %v", err)
+		}
+		req.Header.Set(minioAdminOpHeader, "heal")
+		cred := serverConfig.GetCredential()
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Failed to sign list pending heals request - %v", err)
+		}
+		rec := httptest.NewRecorder()
+		adminRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, rec.Code)
+		}
+		var infos []PendingHealInfo
+		if err = json.Unmarshal(rec.Body.Bytes(), &infos); err != nil {
+			t.Fatalf("Failed to parse list pending heals response - %v", err)
+		}
+		return infos
+	}
+	if infos := listPendingHeals(); !reflect.DeepEqual(infos, []PendingHealInfo{pendingHeal}) {
+		t.Fatalf("Expected %v pending heal, got %v", pendingHeal, infos)
+	}
+
+	healObjects := func(query string) (HealObjectsResponse, int) {
+		req, err := newTestRequest("POST", "/?object&"+query, 0, nil)
+		if err != nil {
+			t.Fatalf("Failed to construct heal objects request - %v", err)
+		}
+		req.Header.Set(minioAdminOpHeader, "heal")
+		cred := serverConfig.GetCredential()
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Failed to sign heal objects request - %v", err)
+		}
+		rec := httptest.NewRecorder()
+		adminRouter.ServeHTTP(rec, req)
+		var response HealObjectsResponse
+		if rec.Code == http.StatusOK {
+			if err = json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse heal objects response - %v", err)
+			}
+		}
+		return response, rec.Code
+	}
+
+	// Invalid bucket name.
+	if _, code := healObjects("bucket=ab"); code != http.StatusBadRequest {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusBadRequest, code)
+	}
+
+	// Heal objects under dir/ two at a time.
+	response, code := healObjects("bucket=bucket&prefix=dir/&max-keys=2")
+	if code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, code)
+	}
+	expected := HealObjectsResponse{
+		Objects: []HealObjectResult{
+			{Object: "dir/object-1", Status: HealStatusHealthy},
+			{Object: "dir/object-2", Status: HealStatusHealed},
+		},
+		IsTruncated: true,
+		NextMarker:  "dir/object-2",
+	}
+	if !reflect.DeepEqual(response, expected) {
+		t.Fatalf("Expected %v, got %v", expected, response)
+	}
+
+	response, code = healObjects("bucket=bucket&prefix=dir/&max-keys=2&marker=" + url.QueryEscape(response.NextMarker))
+	if code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, code)
+	}
+	expected = HealObjectsResponse{
+		Objects: []HealObjectResult{
+			{Object: "dir/object-3", Status: HealStatusHealthy},
+		},
+	}
+	if !reflect.DeepEqual(response, expected) {
+		t.Fatalf("Expected %v, got %v", expected, response)
+	}
+
+	// The corrupted object is healthy once healed.
+	status, err := objLayer.VerifyHealObject(bucket, "dir/object-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != HealStatusHealthy {
+		t.Fatalf("Expected %s, got %s", HealStatusHealthy, status)
+	}
+	// Healed object is no longer pending heal.
+	if infos := listPendingHeals(); len(infos) != 0 {
+		t.Fatalf("Expected no pending heals, got %v", infos)
+	}
+}
+
+// Test for draining a disk with pagination across buckets.
+func TestDecommissionDiskHandler(t *testing.T) {
+	// reset globals.
+	// this is to make sure that the tests are not affected by modified globals.
+	resetTestGlobals()
+	resetGlobalAvoidedDisks()
+	defer resetGlobalAvoidedDisks()
+	// initialize NSLock.
+	initNSLock(false)
+
+	rootPath, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	objLayer, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatalf("Failed to initialize XL based object layer - %v.", err)
+	}
+	defer removeRoots(fsDirs)
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = objLayer
+	globalObjLayerMutex.Unlock()
+
+	// Initialize admin peers to make admin RPC calls.
+	eps, err := parseStorageEndpoints([]string{"http://localhost"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	objects := map[string][]string{
+		"bucket-1": {"object-1", "object-2", "object-3"},
+		"bucket-2": {"object-4"},
+	}
+	for bucket, names := range objects {
+		if err = objLayer.MakeBucket(bucket); err != nil {
+			t.Fatal(err)
+		}
+		for _, object := range names {
+			if _, err = objLayer.PutObject(bucket, object, 4, bytes.NewReader([]byte("abcd")), nil, ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	adminRouter := router.NewRouter()
+	registerAdminRouter(adminRouter)
+
+	decommissionDisk := func(query string) (DecommissionDiskResponse, int) {
+		req, err := newTestRequest("POST", "/?disk&"+query, 0, nil)
+		if err != nil {
+			t.Fatalf("Failed to construct decommission disk request - %v", err)
+		}
+		req.Header.Set(minioAdminOpHeader, "decommission")
+		cred := serverConfig.GetCredential()
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Failed to sign decommission disk request - %v", err)
+		}
+		rec := httptest.NewRecorder()
+		adminRouter.ServeHTTP(rec, req)
+		var response DecommissionDiskResponse
+		if rec.Code == http.StatusOK {
+			if err = json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse decommission disk response - %v", err)
+			}
+		}
+		return response, rec.Code
+	}
+
+	queryVal := url.Values{}
+	queryVal.Set("endpoint", fsDirs[0])
+	endpointQuery := queryVal.Encode()
+
+	// Invalid endpoint and max-keys.
+	if _, code := decommissionDisk("endpoint="); code != http.StatusBadRequest {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusBadRequest, code)
+	}
+	if _, code := decommissionDisk(endpointQuery + "&max-keys=0"); code != http.StatusBadRequest {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusBadRequest, code)
+	}
+
+	// Drain the disk two objects at a time.
+	response, code := decommissionDisk(endpointQuery + "&max-keys=2")
+	if code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, code)
+	}
+	expected := DecommissionDiskResponse{
+		Objects: []DecommissionObjectResult{
+			{Bucket: "bucket-1", Object: "object-1", Relocated: true},
+			{Bucket: "bucket-1", Object: "object-2", Relocated: true},
+		},
+		Relocated:   2,
+		IsTruncated: true,
+		NextMarker:  "bucket-1/object-2",
+	}
+	if !reflect.DeepEqual(response, expected) {
+		t.Fatalf("Expected %v, got %v", expected, response)
+	}
+	if count := objLayer.StorageInfo().Backend.AvoidedDisks; count != 1 {
+		t.Fatalf("Expected 1 avoided disk but found %d", count)
+	}
+
+	response, code = decommissionDisk(endpointQuery + "&max-keys=2&marker=" + url.QueryEscape(response.NextMarker))
+	if code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, code)
+	}
+	expected = DecommissionDiskResponse{
+		Objects: []DecommissionObjectResult{
+			{Bucket: "bucket-1", Object: "object-3", Relocated: true},
+			{Bucket: "bucket-2", Object: "object-4", Relocated: true},
+		},
+		Relocated: 2,
+	}
+	if !reflect.DeepEqual(response, expected) {
+		t.Fatalf("Expected %v, got %v", expected, response)
+	}
+
+	// Draining again finds no shards left on the disk.
+	response, code = decommissionDisk(endpointQuery)
+	if code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, code)
+	}
+	if response.Relocated != 0 || len(response.Objects) != 4 || response.IsTruncated {
+		t.Fatalf("Expected 4 objects without relocation, got %v", response)
+	}
+
+	// Remove the drained disk, all objects are still readable.
+	xl := objLayer.(*xlObjects)
+	posixDisk, ok := xl.storageDisks[0].(*retryStorage)
+	if !ok {
+		t.Fatal("storage disk is not *retryStorage type")
+	}
+	xl.storageDisks[0] = newNaughtyDisk(posixDisk, nil, errDiskNotFound)
+	for bucket, names := range objects {
+		for _, object := range names {
+			var buf bytes.Buffer
+			if err = objLayer.GetObject(bucket, object, 0, 4, &buf); err != nil {
+				t.Fatalf("%s/%s: %s", bucket, object, err)
+			}
+			if buf.String() != "abcd" {
+				t.Fatalf("%s/%s: Object content mismatch after draining the disk", bucket, object)
+			}
+		}
+	}
+}
+
+// Test for server info management REST API.
+func TestServerInfoHandler(t *testing.T) {
+	// reset globals.
+	// this is to make sure that the tests are not affected by modified globals.
+	resetTestGlobals()
+	defer func(bootTime time.Time) { globalBootTime = bootTime }(globalBootTime)
+	globalBootTime = time.Now().UTC()
+
+	rootPath, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	// Initialize admin peers with an unreachable remote peer, ports of
+	// endpoints are only accepted along with the host of the server.
+	defer func(addr, host, port string) {
+		globalMinioAddr, globalMinioHost, globalMinioPort = addr, host, port
+	}(globalMinioAddr, globalMinioHost, globalMinioPort)
+	globalMinioHost, globalMinioPort = "localhost", getFreePort()
+	globalMinioAddr = net.JoinHostPort(globalMinioHost, globalMinioPort)
+	eps, err := parseStorageEndpoints([]string{"http://" + globalMinioAddr + "/d1", "http://127.0.0.1:" + getFreePort() + "/d2"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+	initGlobalAdminPeers(eps)
+
+	adminRouter := router.NewRouter()
+	registerAdminRouter(adminRouter)
+
+	// getInfo - returns the server info returned by the handler.
+	getInfo := func() []ServerInfo {
+		req, err := newTestRequest("GET", "/?service", 0, nil)
+		if err != nil {
+			t.Fatalf("Failed to construct server info request - %v", err)
+		}
+		req.Header.Set(minioAdminOpHeader, "info")
+		cred := serverConfig.GetCredential()
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Failed to sign server info request - %v", err)
+		}
+		rec := httptest.NewRecorder()
+		adminRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, rec.Code)
+		}
+		var infos []ServerInfo
+		if err = json.Unmarshal(rec.Body.Bytes(), &infos); err != nil {
+			t.Fatalf("Failed to unmarshal server info - %v", err)
+		}
+		if len(infos) != 2 {
+			t.Fatalf("Expected info of 2 servers, got %v", infos)
+		}
+		return infos
+	}
+
+	infos := getInfo()
+	local := infos[0]
+	if local.Addr != globalMinioAddr || local.Version != Version || local.CommitID != CommitID || local.GoVersion != runtime.Version() {
+		t.Errorf("Expected build info of this server, got %#v", local)
+	}
+	if !local.BootTime.Equal(globalBootTime) || local.Error != "" {
+		t.Errorf("Expected boot time %s without error, got %#v", globalBootTime, local)
+	}
+	if remote := infos[1]; remote.Addr != eps[1].Host || remote.Error == "" {
+		t.Errorf("Expected unreachable server %s to be reported with an error, got %#v", eps[1].Host, remote)
+	}
+
+	// Uptime increases.
+	time.Sleep(10 * time.Millisecond)
+	if uptime := getInfo()[0].Uptime; uptime <= local.Uptime {
+		t.Errorf("Expected uptime to increase from %s, got %s", local.Uptime, uptime)
+	}
+}
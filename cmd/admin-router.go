@@ -34,6 +34,9 @@ func registerAdminRouter(mux *router.Router) {
 	// Service status
 	adminRouter.Methods("GET").Queries("service", "").Headers(minioAdminOpHeader, "status").HandlerFunc(adminAPI.ServiceStatusHandler)
 
+	// Service info
+	adminRouter.Methods("GET").Queries("service", "").Headers(minioAdminOpHeader, "info").HandlerFunc(adminAPI.ServerInfoHandler)
+
 	// Service restart
 	adminRouter.Methods("POST").Queries("service", "").Headers(minioAdminOpHeader, "restart").HandlerFunc(adminAPI.ServiceRestartHandler)
 
@@ -44,4 +47,42 @@ func registerAdminRouter(mux *router.Router) {
 
 	// Clear locks
 	adminRouter.Methods("POST").Queries("lock", "").Headers(minioAdminOpHeader, "clear").HandlerFunc(adminAPI.ClearLocksHandler)
+
+	/// Disk operations
+
+	// Avoid disk for new writes
+	adminRouter.Methods("POST").Queries("disk", "").Headers(minioAdminOpHeader, "avoid").HandlerFunc(adminAPI.AvoidDiskHandler)
+
+	// Unavoid disk for new writes
+	adminRouter.Methods("POST").Queries("disk", "").Headers(minioAdminOpHeader, "unavoid").HandlerFunc(adminAPI.UnavoidDiskHandler)
+
+	// Decommission disk, relocating its objects to the other disks
+	adminRouter.Methods("POST").Queries("disk", "").Headers(minioAdminOpHeader, "decommission").HandlerFunc(adminAPI.DecommissionDiskHandler)
+
+	/// Accounting operations
+
+	// Per access key request accounting
+	adminRouter.Methods("GET").Queries("accounting", "").Headers(minioAdminOpHeader, "tenants").HandlerFunc(adminAPI.TenantAccountingHandler)
+
+	/// Object operations
+
+	// List objects of all buckets
+	adminRouter.Methods("GET").Queries("object", "").Headers(minioAdminOpHeader, "list").HandlerFunc(adminAPI.ListAllObjectsHandler)
+
+	// Heal objects of a bucket matching a prefix
+	adminRouter.Methods("POST").Queries("object", "").Headers(minioAdminOpHeader, "heal").HandlerFunc(adminAPI.HealObjectsHandler)
+
+	// List objects pending heal
+	adminRouter.Methods("GET").Queries("object", "").Headers(minioAdminOpHeader, "heal").HandlerFunc(adminAPI.ListPendingHealsHandler)
+
+	/// Bucket operations
+
+	// Rebuild bucket index from the disks
+	adminRouter.Methods("POST").Queries("bucket-index", "").Headers(minioAdminOpHeader, "rebuild").HandlerFunc(adminAPI.RebuildBucketIndexHandler)
+
+	// Set quota of a bucket
+	adminRouter.Methods("POST").Queries("bucket-quota", "").Headers(minioAdminOpHeader, "set").HandlerFunc(adminAPI.SetBucketQuotaHandler)
+
+	// Set expiration rules of a bucket
+	adminRouter.Methods("POST").Queries("bucket-lifecycle", "").Headers(minioAdminOpHeader, "set").HandlerFunc(adminAPI.SetBucketLifecycleHandler)
 }
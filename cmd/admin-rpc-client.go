@@ -38,6 +38,14 @@ type remoteAdminClient struct {
 type adminCmdRunner interface {
 	Restart() error
 	ListLocks(bucket, prefix string, relTime time.Duration) ([]VolumeLockInfo, error)
+	AvoidDisk(endpoint string, avoid bool) error
+	TenantStats() (map[string]TenantStats, error)
+	SetBucketQuota(bucket string, quota int64) error
+	SetBucketLifecycle(bucket string, rules []lifecycleRule) error
+	SetBucketCors(bucket string, rules []corsRule) error
+	HealObjects(bucket string, objects []string) ([]HealObjectResult, error)
+	DrainObjects(bucket string, objects []string) ([]DecommissionObjectResult, error)
+	ServerInfo() (ServerInfo, error)
 }
 
 // Restart - Sends a message over channel to the go-routine
@@ -52,6 +60,48 @@ func (lc localAdminClient) ListLocks(bucket, prefix string, relTime time.Duratio
 	return listLocksInfo(bucket, prefix, relTime), nil
 }
 
+// AvoidDisk - Excludes (or includes back) disk from placement of new
+// writes on this server.
+func (lc localAdminClient) AvoidDisk(endpoint string, avoid bool) error {
+	return setDiskAvoided(endpoint, avoid)
+}
+
+// TenantStats - Fetches request accounting of this server.
+func (lc localAdminClient) TenantStats() (map[string]TenantStats, error) {
+	return globalTenantAccounting.Snapshot(), nil
+}
+
+// SetBucketQuota - Sets quota of a bucket on this server.
+func (lc localAdminClient) SetBucketQuota(bucket string, quota int64) error {
+	return setBucketQuota(bucket, quota)
+}
+
+// SetBucketLifecycle - Sets expiration rules of a bucket on this server.
+func (lc localAdminClient) SetBucketLifecycle(bucket string, rules []lifecycleRule) error {
+	return setBucketLifecycle(bucket, rules)
+}
+
+// SetBucketCors - Sets CORS rules of a bucket on this server.
+func (lc localAdminClient) SetBucketCors(bucket string, rules []corsRule) error {
+	return setBucketCors(bucket, rules)
+}
+
+// HealObjects - Heals objects of a bucket from this server.
+func (lc localAdminClient) HealObjects(bucket string, objects []string) ([]HealObjectResult, error) {
+	return healObjects(bucket, objects)
+}
+
+// DrainObjects - Relocates objects of a bucket off the avoided disks
+// from this server.
+func (lc localAdminClient) DrainObjects(bucket string, objects []string) ([]DecommissionObjectResult, error) {
+	return drainObjects(bucket, objects)
+}
+
+// ServerInfo - Fetches build information and uptime of this server.
+func (lc localAdminClient) ServerInfo() (ServerInfo, error) {
+	return getServerInfo(), nil
+}
+
 // Restart - Sends restart command to remote server via RPC.
 func (rc remoteAdminClient) Restart() error {
 	args := AuthRPCArgs{}
@@ -73,6 +123,92 @@ func (rc remoteAdminClient) ListLocks(bucket, prefix string, relTime time.Durati
 	return reply.volLocks, nil
 }
 
+// AvoidDisk - Sends avoid disk command to remote server via RPC.
+func (rc remoteAdminClient) AvoidDisk(endpoint string, avoid bool) error {
+	args := AvoidDiskArgs{
+		Endpoint: endpoint,
+		Avoid:    avoid,
+	}
+	reply := AuthRPCReply{}
+	return rc.Call("Admin.AvoidDisk", &args, &reply)
+}
+
+// TenantStats - Fetches request accounting of remote server via RPC.
+func (rc remoteAdminClient) TenantStats() (map[string]TenantStats, error) {
+	args := AuthRPCArgs{}
+	var reply TenantStatsReply
+	if err := rc.Call("Admin.TenantStats", &args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Tenants, nil
+}
+
+// SetBucketQuota - Sends set bucket quota command to remote server via RPC.
+func (rc remoteAdminClient) SetBucketQuota(bucket string, quota int64) error {
+	args := SetBucketQuotaArgs{
+		Bucket: bucket,
+		Quota:  quota,
+	}
+	reply := AuthRPCReply{}
+	return rc.Call("Admin.SetBucketQuota", &args, &reply)
+}
+
+// SetBucketLifecycle - Sends set bucket lifecycle command to remote server via RPC.
+func (rc remoteAdminClient) SetBucketLifecycle(bucket string, rules []lifecycleRule) error {
+	args := SetBucketLifecycleArgs{
+		Bucket: bucket,
+		Rules:  rules,
+	}
+	reply := AuthRPCReply{}
+	return rc.Call("Admin.SetBucketLifecycle", &args, &reply)
+}
+
+// SetBucketCors - Sends set bucket CORS command to remote server via RPC.
+func (rc remoteAdminClient) SetBucketCors(bucket string, rules []corsRule) error {
+	args := SetBucketCorsArgs{
+		Bucket: bucket,
+		Rules:  rules,
+	}
+	reply := AuthRPCReply{}
+	return rc.Call("Admin.SetBucketCors", &args, &reply)
+}
+
+// HealObjects - Sends heal objects command to remote server via RPC.
+func (rc remoteAdminClient) HealObjects(bucket string, objects []string) ([]HealObjectResult, error) {
+	args := HealObjectsArgs{
+		Bucket:  bucket,
+		Objects: objects,
+	}
+	var reply HealObjectsReply
+	if err := rc.Call("Admin.HealObjects", &args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Results, nil
+}
+
+// DrainObjects - Sends drain objects command to remote server via RPC.
+func (rc remoteAdminClient) DrainObjects(bucket string, objects []string) ([]DecommissionObjectResult, error) {
+	args := DrainObjectsArgs{
+		Bucket:  bucket,
+		Objects: objects,
+	}
+	var reply DrainObjectsReply
+	if err := rc.Call("Admin.DrainObjects", &args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Results, nil
+}
+
+// ServerInfo - Fetches build information and uptime of remote server via RPC.
+func (rc remoteAdminClient) ServerInfo() (ServerInfo, error) {
+	args := AuthRPCArgs{}
+	var reply ServerInfoReply
+	if err := rc.Call("Admin.ServerInfo", &args, &reply); err != nil {
+		return ServerInfo{}, err
+	}
+	return reply.Info, nil
+}
+
 // adminPeer - represents an entity that implements Restart methods.
 type adminPeer struct {
 	addr      string
@@ -113,6 +249,7 @@ func makeAdminPeers(eps []*url.URL) adminPeers {
 				secureConn:      globalIsSSL,
 				serviceEndpoint: path.Join(reservedBucket, adminPath),
 				serviceName:     "Admin",
+				retry:           newPeerRPCRetryPolicy(),
 			}
 
 			servicePeers = append(servicePeers, adminPeer{
@@ -206,3 +343,222 @@ func listPeerLocksInfo(peers adminPeers, bucket, prefix string, relTime time.Dur
 	}
 	return groupedLockInfos, nil
 }
+
+// sendAvoidDiskCmd - Invoke AvoidDisk command on all peers, each
+// peer excludes the disk from its own placement of new writes.
+func sendAvoidDiskCmd(peers adminPeers, endpoint string, avoid bool) error {
+	errs := make([]error, len(peers))
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			errs[idx] = peer.cmdRunner.AvoidDisk(endpoint, avoid)
+		}(i, peer)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendSetBucketQuotaCmd - Invoke SetBucketQuota command on all peers,
+// each peer saves the quota to its own config.
+func sendSetBucketQuotaCmd(peers adminPeers, bucket string, quota int64) error {
+	errs := make([]error, len(peers))
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			errs[idx] = peer.cmdRunner.SetBucketQuota(bucket, quota)
+		}(i, peer)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendSetBucketLifecycleCmd - Invoke SetBucketLifecycle command on all
+// peers, each peer saves the rules to its own config.
+func sendSetBucketLifecycleCmd(peers adminPeers, bucket string, rules []lifecycleRule) error {
+	errs := make([]error, len(peers))
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			errs[idx] = peer.cmdRunner.SetBucketLifecycle(bucket, rules)
+		}(i, peer)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendSetBucketCorsCmd - Invoke SetBucketCors command on all peers,
+// each peer saves the rules to its own config.
+func sendSetBucketCorsCmd(peers adminPeers, bucket string, rules []corsRule) error {
+	errs := make([]error, len(peers))
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			errs[idx] = peer.cmdRunner.SetBucketCors(bucket, rules)
+		}(i, peer)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getPeerTenantStats - Fetches request accounting from all peers and
+// aggregates it per access key for a cluster-wide view.
+func getPeerTenantStats(peers adminPeers) (map[string]TenantStats, error) {
+	allStats := make([]map[string]TenantStats, len(peers))
+	errs := make([]error, len(peers))
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			allStats[idx], errs[idx] = peer.cmdRunner.TenantStats()
+		}(i, peer)
+	}
+	wg.Wait()
+
+	tenants := make(map[string]TenantStats)
+	for idx, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+		for tenant, stats := range allStats[idx] {
+			tenantStats := tenants[tenant]
+			tenantStats.add(stats)
+			tenants[tenant] = tenantStats
+		}
+	}
+	return tenants, nil
+}
+
+// sendHealObjectsCmd - Invoke HealObjects command on all peers, the
+// objects are shared out between the peers to heal them in parallel.
+// Results are returned in the order of objects.
+func sendHealObjectsCmd(peers adminPeers, bucket string, objects []string) ([]HealObjectResult, error) {
+	peerObjects := make([][]string, len(peers))
+	for i, object := range objects {
+		peerObjects[i%len(peers)] = append(peerObjects[i%len(peers)], object)
+	}
+
+	peerResults := make([][]HealObjectResult, len(peers))
+	errs := make([]error, len(peers))
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		if len(peerObjects[i]) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			peerResults[idx], errs[idx] = peer.cmdRunner.HealObjects(bucket, peerObjects[idx])
+		}(i, peer)
+	}
+	wg.Wait()
+
+	for idx, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+		if len(peerResults[idx]) != len(peerObjects[idx]) {
+			return nil, errUnexpected
+		}
+	}
+
+	results := make([]HealObjectResult, len(objects))
+	for i := range objects {
+		results[i] = peerResults[i%len(peers)][i/len(peers)]
+	}
+	return results, nil
+}
+
+// sendDrainObjectsCmd - Invoke DrainObjects command on all peers, the
+// objects are shared out between the peers to relocate them in
+// parallel. Results are returned in the order of objects.
+func sendDrainObjectsCmd(peers adminPeers, bucket string, objects []string) ([]DecommissionObjectResult, error) {
+	peerObjects := make([][]string, len(peers))
+	for i, object := range objects {
+		peerObjects[i%len(peers)] = append(peerObjects[i%len(peers)], object)
+	}
+
+	peerResults := make([][]DecommissionObjectResult, len(peers))
+	errs := make([]error, len(peers))
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		if len(peerObjects[i]) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			peerResults[idx], errs[idx] = peer.cmdRunner.DrainObjects(bucket, peerObjects[idx])
+		}(i, peer)
+	}
+	wg.Wait()
+
+	for idx, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+		if len(peerResults[idx]) != len(peerObjects[idx]) {
+			return nil, errUnexpected
+		}
+	}
+
+	results := make([]DecommissionObjectResult, len(objects))
+	for i := range objects {
+		results[i] = peerResults[i%len(peers)][i/len(peers)]
+	}
+	return results, nil
+}
+
+// getPeerServerInfo - Fetches build information and uptime from all
+// peers, in the order of peers. Peers which could not be reached are
+// reported with their error instead of failing the whole request.
+func getPeerServerInfo(peers adminPeers) []ServerInfo {
+	infos := make([]ServerInfo, len(peers))
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			info, err := peer.cmdRunner.ServerInfo()
+			if err != nil {
+				info = ServerInfo{Error: err.Error()}
+			}
+			info.Addr = peer.addr
+			infos[idx] = info
+		}(i, peer)
+	}
+	wg.Wait()
+	return infos
+}
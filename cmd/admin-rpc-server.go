@@ -45,6 +45,72 @@ type ListLocksReply struct {
 	volLocks []VolumeLockInfo
 }
 
+// AvoidDiskArgs - wraps AvoidDisk API's arguments to send over RPC.
+type AvoidDiskArgs struct {
+	AuthRPCArgs
+	Endpoint string
+	Avoid    bool
+}
+
+// SetBucketQuotaArgs - wraps SetBucketQuota API's arguments to send over RPC.
+type SetBucketQuotaArgs struct {
+	AuthRPCArgs
+	Bucket string
+	Quota  int64
+}
+
+// SetBucketLifecycleArgs - wraps SetBucketLifecycle API's arguments to send over RPC.
+type SetBucketLifecycleArgs struct {
+	AuthRPCArgs
+	Bucket string
+	Rules  []lifecycleRule
+}
+
+// SetBucketCorsArgs - wraps SetBucketCors API's arguments to send over RPC.
+type SetBucketCorsArgs struct {
+	AuthRPCArgs
+	Bucket string
+	Rules  []corsRule
+}
+
+// HealObjectsArgs - wraps HealObjects API's arguments to send over RPC.
+type HealObjectsArgs struct {
+	AuthRPCArgs
+	Bucket  string
+	Objects []string
+}
+
+// HealObjectsReply - wraps HealObjects response over RPC.
+type HealObjectsReply struct {
+	AuthRPCReply
+	Results []HealObjectResult
+}
+
+// DrainObjectsArgs - wraps DrainObjects API's arguments to send over RPC.
+type DrainObjectsArgs struct {
+	AuthRPCArgs
+	Bucket  string
+	Objects []string
+}
+
+// DrainObjectsReply - wraps DrainObjects response over RPC.
+type DrainObjectsReply struct {
+	AuthRPCReply
+	Results []DecommissionObjectResult
+}
+
+// TenantStatsReply - wraps TenantStats response over RPC.
+type TenantStatsReply struct {
+	AuthRPCReply
+	Tenants map[string]TenantStats
+}
+
+// ServerInfoReply - wraps ServerInfo response over RPC.
+type ServerInfoReply struct {
+	AuthRPCReply
+	Info ServerInfo
+}
+
 // Restart - Restart this instance of minio server.
 func (s *adminCmd) Restart(args *AuthRPCArgs, reply *AuthRPCReply) error {
 	if err := args.IsAuthenticated(); err != nil {
@@ -62,6 +128,92 @@ func (s *adminCmd) ListLocks(query *ListLocksQuery, reply *ListLocksReply) error
 	return nil
 }
 
+// AvoidDisk - excludes (or includes back) a disk from placement of
+// new writes on this server.
+func (s *adminCmd) AvoidDisk(args *AvoidDiskArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return setDiskAvoided(args.Endpoint, args.Avoid)
+}
+
+// TenantStats - returns request accounting of this server per access key.
+func (s *adminCmd) TenantStats(args *AuthRPCArgs, reply *TenantStatsReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	reply.Tenants = globalTenantAccounting.Snapshot()
+	return nil
+}
+
+// SetBucketQuota - sets quota of a bucket on this server.
+func (s *adminCmd) SetBucketQuota(args *SetBucketQuotaArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return setBucketQuota(args.Bucket, args.Quota)
+}
+
+// SetBucketLifecycle - sets expiration rules of a bucket on this server.
+func (s *adminCmd) SetBucketLifecycle(args *SetBucketLifecycleArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return setBucketLifecycle(args.Bucket, args.Rules)
+}
+
+// SetBucketCors - sets CORS rules of a bucket on this server.
+func (s *adminCmd) SetBucketCors(args *SetBucketCorsArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return setBucketCors(args.Bucket, args.Rules)
+}
+
+// HealObjects - heals objects of a bucket from this server.
+func (s *adminCmd) HealObjects(args *HealObjectsArgs, reply *HealObjectsReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	results, err := healObjects(args.Bucket, args.Objects)
+	if err != nil {
+		return err
+	}
+	reply.Results = results
+	return nil
+}
+
+// DrainObjects - relocates objects of a bucket off the avoided disks
+// from this server.
+func (s *adminCmd) DrainObjects(args *DrainObjectsArgs, reply *DrainObjectsReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	results, err := drainObjects(args.Bucket, args.Objects)
+	if err != nil {
+		return err
+	}
+	reply.Results = results
+	return nil
+}
+
+// ServerInfo - returns build information and uptime of this server.
+func (s *adminCmd) ServerInfo(args *AuthRPCArgs, reply *ServerInfoReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	reply.Info = getServerInfo()
+	return nil
+}
+
 // registerAdminRPCRouter - registers RPC methods for service status,
 // stop and restart commands.
 func registerAdminRPCRouter(mux *router.Router) error {
@@ -50,6 +50,7 @@ const (
 	ErrBadDigest
 	ErrEntityTooSmall
 	ErrEntityTooLarge
+	ErrMetadataTooLarge
 	ErrIncompleteBody
 	ErrInternalError
 	ErrInvalidAccessKeyID
@@ -57,11 +58,14 @@ const (
 	ErrInvalidDigest
 	ErrInvalidRange
 	ErrInvalidMaxKeys
+	ErrIncorrectContinuationToken
 	ErrInvalidMaxUploads
 	ErrInvalidMaxParts
 	ErrInvalidPartNumberMarker
 	ErrInvalidRequestBody
 	ErrInvalidCopySource
+	ErrInvalidCopyPartRange
+	ErrInvalidCopyPartRangeSource
 	ErrInvalidMetadataDirective
 	ErrInvalidCopyDest
 	ErrInvalidPolicyDocument
@@ -72,6 +76,8 @@ const (
 	ErrMissingRequestBodyError
 	ErrNoSuchBucket
 	ErrNoSuchBucketPolicy
+	ErrNoSuchLifecycleConfiguration
+	ErrNoSuchCORSConfiguration
 	ErrNoSuchKey
 	ErrNoSuchUpload
 	ErrNotImplemented
@@ -102,6 +108,7 @@ const (
 	ErrMalformedCredentialRegion
 	ErrMalformedExpires
 	ErrNegativeExpires
+	ErrMaximumExpires
 	ErrAuthHeaderEmpty
 	ErrExpiredPresignRequest
 	ErrRequestNotReadyYet
@@ -111,6 +118,26 @@ const (
 	ErrInvalidQueryParams
 	ErrBucketAlreadyOwnedByYou
 	ErrInvalidDuration
+	ErrInvalidEncodingMethod
+	ErrInvalidExpressionType
+	ErrUnsupportedSQLStructure
+	ErrUnsupportedSelectSerialization
+	ErrInvalidTag
+	ErrTooManyTags
+	ErrInvalidTaggingDirective
+	ErrInvalidStorageClass
+	ErrInvalidEncryptionMethod
+	ErrInsecureSSECustomerRequest
+	ErrInvalidSSECustomerAlgorithm
+	ErrMissingSSECustomerKey
+	ErrInvalidSSECustomerKey
+	ErrMissingSSECustomerKeyMD5
+	ErrSSECustomerKeyMD5Mismatch
+	ErrSSECustomerKeyMismatch
+	ErrSSEEncryptedObject
+	ErrInvalidEncryptionParameters
+	ErrIncompatibleEncryptionMethod
+	ErrCORSForbidden
 	// Add new error codes here.
 
 	// Bucket notification related errors.
@@ -137,6 +164,20 @@ const (
 	ErrPolicyNesting
 	ErrInvalidObjectName
 	ErrServerNotInitialized
+	ErrInvalidEndpoint
+	ErrInvalidTruncateLength
+	ErrInvalidModTime
+	ErrBucketQuotaExceeded
+	ErrInvalidBucketQuota
+	ErrBucketQuotaNotSupported
+	ErrObjectRetained
+	ErrInvalidBucketLifecycle
+	ErrObjectCorrupted
+	ErrOperationTimedOut
+	ErrServerSideEncryptionNotConfigured
+	ErrMultipartEncryptionNotSupported
+	ErrEndpointNotFound
+	ErrInvalidListRate
 	// Add new extended error codes here.
 	// Please open a https://github.com/minio/minio/issues before adding
 	// new error codes here.
@@ -155,6 +196,16 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Copy Source must mention the source bucket and key: sourcebucket/sourcekey.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrInvalidCopyPartRange: {
+		Code:           "InvalidArgument",
+		Description:    "The x-amz-copy-source-range value must be of the form bytes=first-last where first and last are the zero-based offsets of the first and last bytes to copy",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidCopyPartRangeSource: {
+		Code:           "InvalidArgument",
+		Description:    "Range specified is not valid for source object",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrInvalidMetadataDirective: {
 		Code:           "InvalidArgument",
 		Description:    "Unknown metadata directive.",
@@ -175,6 +226,11 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Argument maxKeys must be an integer between 0 and 2147483647",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrIncorrectContinuationToken: {
+		Code:           "InvalidArgument",
+		Description:    "The continuation token provided is incorrect",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrInvalidMaxParts: {
 		Code:           "InvalidArgument",
 		Description:    "Argument max-parts must be an integer between 0 and 2147483647",
@@ -210,6 +266,11 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Your proposed upload exceeds the maximum allowed object size.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrMetadataTooLarge: {
+		Code:           "MetadataTooLarge",
+		Description:    "Your metadata headers exceed the maximum allowed metadata size.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrIncompleteBody: {
 		Code:           "IncompleteBody",
 		Description:    "You did not provide the number of bytes specified by the Content-Length HTTP header.",
@@ -270,6 +331,16 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "The bucket policy does not exist",
 		HTTPStatusCode: http.StatusNotFound,
 	},
+	ErrNoSuchLifecycleConfiguration: {
+		Code:           "NoSuchLifecycleConfiguration",
+		Description:    "The lifecycle configuration does not exist",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrNoSuchCORSConfiguration: {
+		Code:           "NoSuchCORSConfiguration",
+		Description:    "The CORS configuration does not exist",
+		HTTPStatusCode: http.StatusNotFound,
+	},
 	ErrNoSuchKey: {
 		Code:           "NoSuchKey",
 		Description:    "The specified key does not exist.",
@@ -437,6 +508,11 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "X-Amz-Expires must be non-negative",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrMaximumExpires: {
+		Code:           "AuthorizationQueryParametersError",
+		Description:    "X-Amz-Expires must be less than the maximum expiry of presigned URLs, a week by default",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrAuthHeaderEmpty: {
 		Code:           "InvalidArgument",
 		Description:    "Authorization header is invalid -- one and only one ' ' (space) required.",
@@ -483,6 +559,106 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Relative duration provided in the request is invalid.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrInvalidEncodingMethod: {
+		Code:           "InvalidArgument",
+		Description:    "Invalid Encoding Method specified in Request",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidExpressionType: {
+		Code:           "InvalidExpressionType",
+		Description:    "The ExpressionType is invalid. Only SQL expressions are supported.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrUnsupportedSQLStructure: {
+		Code:           "UnsupportedSqlStructure",
+		Description:    "Encountered an unsupported SQL structure. Check the SQL Reference.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrUnsupportedSelectSerialization: {
+		Code:           "InvalidArgument",
+		Description:    "Only uncompressed CSV input and CSV output are supported, with single character field delimiters and newline or single character record delimiters.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidTag: {
+		Code:           "InvalidTag",
+		Description:    "The tag provided was not a valid tag. Tag keys must be unique, of at most 128 characters, and tag values of at most 256 characters.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrTooManyTags: {
+		Code:           "BadRequest",
+		Description:    "Object tags cannot be greater than 10",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidTaggingDirective: {
+		Code:           "InvalidArgument",
+		Description:    "Unknown tagging directive.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidEncryptionMethod: {
+		Code:           "InvalidArgument",
+		Description:    "The encryption method specified is not supported",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInsecureSSECustomerRequest: {
+		Code:           "InvalidRequest",
+		Description:    "Requests specifying Server Side Encryption with Customer provided keys must be made over a secure connection.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidSSECustomerAlgorithm: {
+		Code:           "InvalidArgument",
+		Description:    "Requests specifying Server Side Encryption with Customer provided keys must provide a valid encryption algorithm.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrMissingSSECustomerKey: {
+		Code:           "InvalidArgument",
+		Description:    "Requests specifying Server Side Encryption with Customer provided keys must provide an appropriate secret key.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidSSECustomerKey: {
+		Code:           "InvalidArgument",
+		Description:    "The secret key was invalid for the specified algorithm.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrMissingSSECustomerKeyMD5: {
+		Code:           "InvalidArgument",
+		Description:    "Requests specifying Server Side Encryption with Customer provided keys must provide the client calculated MD5 of the secret key.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrSSECustomerKeyMD5Mismatch: {
+		Code:           "InvalidArgument",
+		Description:    "The calculated MD5 hash of the key did not match the hash that was provided.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrSSECustomerKeyMismatch: {
+		Code:           "AccessDenied",
+		Description:    "The provided customer key does not match the key the object was encrypted with.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrSSEEncryptedObject: {
+		Code:           "InvalidRequest",
+		Description:    "The object was stored using a form of Server Side Encryption. The correct parameters must be provided to retrieve the object.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidEncryptionParameters: {
+		Code:           "InvalidRequest",
+		Description:    "The encryption parameters are not applicable to this object.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrIncompatibleEncryptionMethod: {
+		Code:           "InvalidArgument",
+		Description:    "Server Side Encryption with Customer provided key is incompatible with the encryption method specified.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrCORSForbidden: {
+		Code:           "AccessForbidden",
+		Description:    "CORSResponse: This CORS request is not allowed. This is usually because the evaluation of Origin, request method / Access-Control-Request-Method or Access-Control-Request-Headers are not whitelisted by the resource's CORS spec.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrInvalidStorageClass: {
+		Code:           "InvalidStorageClass",
+		Description:    "The storage class you specified is not valid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 
 	/// Bucket notification related errors.
 	ErrEventNotification: {
@@ -574,6 +750,76 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Server not initialized, please try again.",
 		HTTPStatusCode: http.StatusServiceUnavailable,
 	},
+	ErrInvalidEndpoint: {
+		Code:           "XMinioInvalidEndpoint",
+		Description:    "Endpoint provided in the request is invalid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidTruncateLength: {
+		Code:           "XMinioInvalidTruncateLength",
+		Description:    "Truncate length must be a non-negative integer not larger than the object size.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidModTime: {
+		Code:           "XMinioInvalidModTime",
+		Description:    "Modification time must be an RFC 3339 timestamp after the Unix epoch and not in the future.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrBucketQuotaExceeded: {
+		Code:           "XMinioBucketQuotaExceeded",
+		Description:    "Bucket has reached its quota. Please delete few objects to proceed.",
+		HTTPStatusCode: http.StatusInsufficientStorage,
+	},
+	ErrInvalidBucketQuota: {
+		Code:           "XMinioInvalidBucketQuota",
+		Description:    "Bucket quota must be a non-negative number of bytes.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrBucketQuotaNotSupported: {
+		Code:           "XMinioBucketQuotaNotSupported",
+		Description:    "Bucket quotas are not supported in distributed setups.",
+		HTTPStatusCode: http.StatusNotImplemented,
+	},
+	ErrObjectRetained: {
+		Code:           "AccessDenied",
+		Description:    "Object is retained in WORM mode and cannot be overwritten or deleted until its retention period expires.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrInvalidBucketLifecycle: {
+		Code:           "XMinioInvalidBucketLifecycle",
+		Description:    "Lifecycle rules must be a JSON list of at most 1000 rules with a prefix and a non-negative number of days.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrObjectCorrupted: {
+		Code:           "XMinioObjectCorrupted",
+		Description:    "Object content does not match its checksum, the object is corrupted.",
+		HTTPStatusCode: http.StatusInternalServerError,
+	},
+	ErrOperationTimedOut: {
+		Code:           "XMinioOperationTimedOut",
+		Description:    "A timeout occurred while trying to lock a resource, please reduce your request rate.",
+		HTTPStatusCode: http.StatusServiceUnavailable,
+	},
+	ErrServerSideEncryptionNotConfigured: {
+		Code:           "XMinioServerSideEncryptionNotConfigured",
+		Description:    "Server-side encryption requires a master key, MINIO_SSE_MASTER_KEY is not set.",
+		HTTPStatusCode: http.StatusNotImplemented,
+	},
+	ErrMultipartEncryptionNotSupported: {
+		Code:           "XMinioMultipartEncryptionNotSupported",
+		Description:    "Multipart uploads cannot be encrypted, upload the object with a single PutObject instead.",
+		HTTPStatusCode: http.StatusNotImplemented,
+	},
+	ErrEndpointNotFound: {
+		Code:           "XMinioEndpointNotFound",
+		Description:    "Endpoint provided in the request is not a disk of this cluster.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidListRate: {
+		Code:           "XMinioInvalidListRate",
+		Description:    "Listing rate must be a positive number of objects per second.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	// Add your error structure here.
 }
 
@@ -620,6 +866,10 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 		apiErr = ErrBucketAlreadyOwnedByYou
 	case ObjectNotFound:
 		apiErr = ErrNoSuchKey
+	case ObjectCorrupted:
+		apiErr = ErrObjectCorrupted
+	case OperationTimedOut:
+		apiErr = ErrOperationTimedOut
 	case ObjectNameInvalid:
 		apiErr = ErrInvalidObjectName
 	case InvalidUploadID:
@@ -646,6 +896,14 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 		apiErr = ErrEntityTooLarge
 	case ObjectTooSmall:
 		apiErr = ErrEntityTooSmall
+	case InvalidTruncateLength:
+		apiErr = ErrInvalidTruncateLength
+	case BucketQuotaExceeded:
+		apiErr = ErrBucketQuotaExceeded
+	case ObjectRetained:
+		apiErr = ErrObjectRetained
+	case MultipartEncryptionNotSupported:
+		apiErr = ErrMultipartEncryptionNotSupported
 	default:
 		apiErr = ErrInternalError
 	}
@@ -22,9 +22,14 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 )
 
+// Prefix of metadata keys of an object internal to the server, such as
+// its tags and keys, never returned as headers.
+const minioInternalMetaPrefix = "X-Minio-Internal-"
+
 // Returns a hexadecimal representation of time at the
 // time response is sent to the client.
 func mustGetRequestID(t time.Time) string {
@@ -33,8 +38,11 @@ func mustGetRequestID(t time.Time) string {
 
 // Write http common headers
 func setCommonHeaders(w http.ResponseWriter) {
-	// Set unique request ID for each reply.
-	w.Header().Set(responseRequestIDKey, mustGetRequestID(time.Now().UTC()))
+	// Set unique request ID for each reply, unless already set by the
+	// request ID handler.
+	if w.Header().Get(responseRequestIDKey) == "" {
+		w.Header().Set(responseRequestIDKey, mustGetRequestID(time.Now().UTC()))
+	}
 	w.Header().Set("Server", globalServerUserAgent)
 	w.Header().Set("Accept-Ranges", "bytes")
 }
@@ -65,10 +73,16 @@ func setObjectHeaders(w http.ResponseWriter, objInfo ObjectInfo, contentRange *h
 		w.Header().Set("ETag", "\""+objInfo.MD5Sum+"\"")
 	}
 
-	// Set all other user defined metadata.
+	// Set all other user defined metadata, tags are only counted and
+	// internal metadata such as keys of encrypted objects is not sent.
+	// Modification time requested on upload is sent as Last-Modified.
 	for k, v := range objInfo.UserDefined {
+		if strings.HasPrefix(k, minioInternalMetaPrefix) || k == modTimeMetaKey {
+			continue
+		}
 		w.Header().Set(k, v)
 	}
+	setObjectTagsHeader(w, objInfo.UserDefined)
 
 	// for providing ranged content
 	if contentRange != nil && contentRange.offsetBegin > -1 {
@@ -0,0 +1,44 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "testing"
+
+// Tests validate S3 URL encoding of key names.
+func TestS3EncodeName(t *testing.T) {
+	testCases := []struct {
+		name         string
+		encodingType string
+		expected     string
+	}{
+		{"a&b", "", "a&b"},
+		{"a&b", "url", "a%26b"},
+		{"dir with space/obj", "url", "dir+with+space/obj"},
+		{"unicode-ü", "url", "unicode-%C3%BC"},
+		{"ctrl\x01char", "url", "ctrl%01char"},
+		{"plain-name_1.txt~", "url", "plain-name_1.txt~"},
+		{"a+b=c", "url", "a%2Bb%3Dc"},
+		{"a&b", "unknown", "a&b"},
+	}
+
+	for i, testCase := range testCases {
+		result := s3EncodeName(testCase.name, testCase.encodingType)
+		if result != testCase.expected {
+			t.Errorf("Test %d: Expected `%s`, but found `%s`", i+1, testCase.expected, result)
+		}
+	}
+}
@@ -33,23 +33,54 @@ func registerAPIRouter(mux *router.Router) {
 	// API Router
 	apiRouter := mux.NewRoute().PathPrefix("/").Subrouter()
 
-	// Bucket router
-	bucket := apiRouter.PathPrefix("/{bucket}").Subrouter()
+	// Bucket routers, virtual-hosted-style requests addressing the
+	// bucket in the host are routed first if a domain is configured.
+	// The port is only kept in the host of absolute request URLs.
+	var routers []*router.Router
+	if globalDomainName != "" {
+		routers = append(routers, apiRouter.Host("{bucket:.+}."+globalDomainName+"{port:(?::[0-9]+)?}").Subrouter())
+	}
+	routers = append(routers, apiRouter.PathPrefix("/{bucket}").Subrouter())
+
+	for _, bucket := range routers {
+		registerBucketRoutes(bucket, api)
+	}
+
+	/// Root operation
+
+	// ListBuckets
+	apiRouter.Methods("GET").HandlerFunc(api.ListBucketsHandler)
+}
 
+// registerBucketRoutes - registers bucket and object operations on a
+// bucket router, providing the bucket name in its variables.
+func registerBucketRoutes(bucket *router.Router, api objectAPIHandlers) {
 	/// Object operations
 
 	// HeadObject
 	bucket.Methods("HEAD").Path("/{object:.+}").HandlerFunc(api.HeadObjectHandler)
+	// CopyObjectPart
+	bucket.Methods("PUT").Path("/{object:.+}").HeadersRegexp("X-Amz-Copy-Source", ".*?(\\/|%2F).*?").HandlerFunc(api.CopyObjectPartHandler).Queries("partNumber", "{partNumber:[0-9]+}", "uploadId", "{uploadId:.*}")
 	// PutObjectPart
 	bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(api.PutObjectPartHandler).Queries("partNumber", "{partNumber:[0-9]+}", "uploadId", "{uploadId:.*}")
 	// ListObjectPxarts
 	bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.ListObjectPartsHandler).Queries("uploadId", "{uploadId:.*}")
+	// SelectObjectContent
+	bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(api.SelectObjectContentHandler).Queries("select", "", "select-type", "2")
 	// CompleteMultipartUpload
 	bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(api.CompleteMultipartUploadHandler).Queries("uploadId", "{uploadId:.*}")
+	// TruncateObject - minio extension, not part of S3 API.
+	bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(api.TruncateObjectHandler).Queries("truncate", "{length:.*}")
 	// NewMultipartUpload
 	bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(api.NewMultipartUploadHandler).Queries("uploads", "")
 	// AbortMultipartUpload
 	bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(api.AbortMultipartUploadHandler).Queries("uploadId", "{uploadId:.*}")
+	// GetObjectTagging
+	bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.GetObjectTaggingHandler).Queries("tagging", "")
+	// PutObjectTagging
+	bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(api.PutObjectTaggingHandler).Queries("tagging", "")
+	// DeleteObjectTagging
+	bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(api.DeleteObjectTaggingHandler).Queries("tagging", "")
 	// GetObject
 	bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.GetObjectHandler)
 	// CopyObject
@@ -67,6 +98,10 @@ func registerAPIRouter(mux *router.Router) {
 	bucket.Methods("GET").HandlerFunc(api.GetBucketPolicyHandler).Queries("policy", "")
 	// GetBucketNotification
 	bucket.Methods("GET").HandlerFunc(api.GetBucketNotificationHandler).Queries("notification", "")
+	// GetBucketLifecycle
+	bucket.Methods("GET").HandlerFunc(api.GetBucketLifecycleHandler).Queries("lifecycle", "")
+	// GetBucketCors
+	bucket.Methods("GET").HandlerFunc(api.GetBucketCorsHandler).Queries("cors", "")
 	// ListenBucketNotification
 	bucket.Methods("GET").HandlerFunc(api.ListenBucketNotificationHandler).Queries("events", "{events:.*}")
 	// ListMultipartUploads
@@ -79,21 +114,26 @@ func registerAPIRouter(mux *router.Router) {
 	bucket.Methods("PUT").HandlerFunc(api.PutBucketPolicyHandler).Queries("policy", "")
 	// PutBucketNotification
 	bucket.Methods("PUT").HandlerFunc(api.PutBucketNotificationHandler).Queries("notification", "")
+	// PutBucketLifecycle - not implemented, see the SetBucketLifecycle admin API.
+	bucket.Methods("PUT").HandlerFunc(api.PutBucketLifecycleHandler).Queries("lifecycle", "")
+	// PutBucketCors
+	bucket.Methods("PUT").HandlerFunc(api.PutBucketCorsHandler).Queries("cors", "")
 	// PutBucket
 	bucket.Methods("PUT").HandlerFunc(api.PutBucketHandler)
 	// HeadBucket
 	bucket.Methods("HEAD").HandlerFunc(api.HeadBucketHandler)
 	// PostPolicy
 	bucket.Methods("POST").HeadersRegexp("Content-Type", "multipart/form-data*").HandlerFunc(api.PostPolicyBucketHandler)
+	// PutObjectAssignKey - minio extension, not part of S3 API.
+	bucket.Methods("POST").HandlerFunc(api.PutObjectAssignKeyHandler).Queries("assign-key", "")
 	// DeleteMultipleObjects
 	bucket.Methods("POST").HandlerFunc(api.DeleteMultipleObjectsHandler)
 	// DeleteBucketPolicy
 	bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketPolicyHandler).Queries("policy", "")
+	// DeleteBucketLifecycle
+	bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketLifecycleHandler).Queries("lifecycle", "")
+	// DeleteBucketCors
+	bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketCorsHandler).Queries("cors", "")
 	// DeleteBucket
 	bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketHandler)
-
-	/// Root operation
-
-	// ListBuckets
-	apiRouter.Methods("GET").HandlerFunc(api.ListBucketsHandler)
 }
@@ -33,6 +33,27 @@ func isRequestJWT(r *http.Request) bool {
 	return strings.HasPrefix(r.Header.Get("Authorization"), jwtAlgorithm)
 }
 
+// getClientCertIdentity - returns the access key the verified TLS client
+// certificate of the request authenticates as, empty if there is none.
+func getClientCertIdentity(r *http.Request) string {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return serverConfig.GetClientCertIdentity(r.TLS.VerifiedChains[0][0].Subject.CommonName)
+}
+
+// Verify if request is authenticated by a TLS client certificate of
+// the identity of the server credentials. Certificates only stand in
+// for the server credentials, there are no other identities to map
+// their subject to.
+func isRequestClientCert(r *http.Request) bool {
+	if _, ok := r.Header["Authorization"]; ok {
+		return false
+	}
+	accessKey := getClientCertIdentity(r)
+	return accessKey != "" && accessKey == serverConfig.GetCredential().AccessKey
+}
+
 // Verify if request has AWS Signature Version '4'.
 func isRequestSignatureV4(r *http.Request) bool {
 	return strings.HasPrefix(r.Header.Get("Authorization"), signV4Algorithm)
@@ -80,6 +101,7 @@ const (
 	authTypeSigned
 	authTypeSignedV2
 	authTypeJWT
+	authTypeClientCert
 )
 
 // Get request authentication type.
@@ -98,6 +120,8 @@ func getRequestAuthType(r *http.Request) authType {
 		return authTypeJWT
 	} else if isRequestPostPolicySignatureV4(r) {
 		return authTypePostPolicy
+	} else if isRequestClientCert(r) {
+		return authTypeClientCert
 	} else if _, ok := r.Header["Authorization"]; !ok {
 		return authTypeAnonymous
 	}
@@ -121,11 +145,14 @@ func checkRequestAuthType(r *http.Request, bucket, policyAction, region string)
 			errorIf(errSignatureMismatch, dumpRequest(r))
 		}
 		return s3Error
+	case authTypeClientCert:
+		// Client certificate is already verified by the TLS handshake.
+		return ErrNone
 	}
 
 	if reqAuthType == authTypeAnonymous && policyAction != "" {
 		// http://docs.aws.amazon.com/AmazonS3/latest/dev/using-with-s3-actions.html
-		return enforceBucketPolicy(bucket, policyAction, r.URL)
+		return enforceBucketPolicy(bucket, policyAction, pathStyleURL(r))
 	}
 
 	// By default return ErrAccessDenied
@@ -160,6 +187,37 @@ func isReqAuthenticated(r *http.Request, region string) (s3Error APIErrorCode) {
 	if r == nil {
 		return ErrInternalError
 	}
+	// Skips calculating sha256 on the payload on server, if client requested for it.
+	sha256sum := unsignedPayload
+	if !skipContentSha256Cksum(r) {
+		sha256sum = r.Header.Get("X-Amz-Content-Sha256")
+		if isRequestPresignedSignatureV4(r) {
+			sha256sum = r.URL.Query().Get("X-Amz-Content-Sha256")
+		}
+	}
+	// Verify the signature against the sha256 claimed by the client
+	// before reading the payload, such that clients sending
+	// Expect: 100-continue are not asked to send the payload of a
+	// request which is rejected anyways.
+	if isRequestSignatureV4(r) {
+		s3Error = doesSignatureMatch(sha256sum, r, region)
+	} else if isRequestPresignedSignatureV4(r) {
+		s3Error = doesPresignedSignatureMatch(sha256sum, r, region)
+	} else {
+		return ErrAccessDenied
+	}
+	if s3Error != ErrNone {
+		// Payload of requests without Expect: 100-continue is sent
+		// regardless, report a payload not matching the claimed
+		// sha256 as such.
+		if s3Error == ErrSignatureDoesNotMatch && sha256sum != unsignedPayload &&
+			!strings.EqualFold(r.Header.Get("Expect"), "100-continue") {
+			if payload, err := ioutil.ReadAll(r.Body); err == nil && getSHA256Hash(payload) != sha256sum {
+				return ErrContentSHA256Mismatch
+			}
+		}
+		return s3Error
+	}
 	payload, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		errorIf(err, "Unable to read request body for signature verification")
@@ -171,21 +229,13 @@ func isReqAuthenticated(r *http.Request, region string) (s3Error APIErrorCode) {
 			return ErrBadDigest
 		}
 	}
+	// Verify the payload matches the signed sha256.
+	if sha256sum != unsignedPayload && sha256sum != getSHA256Hash(payload) {
+		return ErrContentSHA256Mismatch
+	}
 	// Populate back the payload.
 	r.Body = ioutil.NopCloser(bytes.NewReader(payload))
-	// Skips calculating sha256 on the payload on server, if client requested for it.
-	var sha256sum string
-	if skipContentSha256Cksum(r) {
-		sha256sum = unsignedPayload
-	} else {
-		sha256sum = getSHA256Hash(payload)
-	}
-	if isRequestSignatureV4(r) {
-		return doesSignatureMatch(sha256sum, r, region)
-	} else if isRequestPresignedSignatureV4(r) {
-		return doesPresignedSignatureMatch(sha256sum, r, region)
-	}
-	return ErrAccessDenied
+	return ErrNone
 }
 
 // authHandler - handles all the incoming authorization headers and validates them if possible.
@@ -207,6 +257,7 @@ var supportedS3AuthTypes = map[authType]struct{}{
 	authTypeSignedV2:        {},
 	authTypePostPolicy:      {},
 	authTypeStreamingSigned: {},
+	authTypeClientCert:      {},
 }
 
 // Validate if the authType is valid and supported.
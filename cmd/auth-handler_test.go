@@ -171,9 +171,14 @@ func TestS3SupportedAuthType(t *testing.T) {
 			authT: authTypeUnknown,
 			pass:  false,
 		},
-		// Test 10 - some new auth type is not supported s3 type.
+		// Test 10 - client certificate is supported s3 type.
 		{
-			authT: authType(9),
+			authT: authTypeClientCert,
+			pass:  true,
+		},
+		// Test 11 - some new auth type is not supported s3 type.
+		{
+			authT: authType(10),
 			pass:  false,
 		},
 	}
@@ -317,6 +322,14 @@ func TestIsReqAuthenticated(t *testing.T) {
 
 	serverConfig.SetCredential(credential{"myuser", "mypassword"})
 
+	// Properly signed request with a bad Content-MD5 header.
+	badMD5Req := mustNewRequest("PUT", "http://localhost:9000", 5, bytes.NewReader([]byte("hello")), t)
+	badMD5Req.Header.Set("Content-Md5", "garbage")
+	cred := serverConfig.GetCredential()
+	if err = signRequestV4(badMD5Req, cred.AccessKey, cred.SecretKey); err != nil {
+		t.Fatalf("Unable to sign request %s", err)
+	}
+
 	// List of test cases for validating http request authentication.
 	testCases := []struct {
 		req     *http.Request
@@ -327,16 +340,13 @@ func TestIsReqAuthenticated(t *testing.T) {
 		// When request is unsigned, access denied is returned.
 		{mustNewRequest("GET", "http://localhost:9000", 0, nil, t), ErrAccessDenied},
 		// When request is properly signed, but has bad Content-MD5 header.
-		{mustNewSignedRequest("PUT", "http://localhost:9000", 5, bytes.NewReader([]byte("hello")), t), ErrBadDigest},
+		{badMD5Req, ErrBadDigest},
 		// When request is properly signed, error is none.
 		{mustNewSignedRequest("GET", "http://localhost:9000", 0, nil, t), ErrNone},
 	}
 
 	// Validates all testcases.
 	for _, testCase := range testCases {
-		if testCase.s3Error == ErrBadDigest {
-			testCase.req.Header.Set("Content-Md5", "garbage")
-		}
 		if s3Error := isReqAuthenticated(testCase.req, serverConfig.GetRegion()); s3Error != testCase.s3Error {
 			t.Fatalf("Unexpected s3error returned wanted %d, got %d", testCase.s3Error, s3Error)
 		}
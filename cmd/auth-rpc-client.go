@@ -17,6 +17,8 @@
 package cmd
 
 import (
+	"io"
+	"net"
 	"net/rpc"
 	"sync"
 	"time"
@@ -26,6 +28,40 @@ import (
 // giving up on the remote RPC entirely.
 const globalAuthRPCRetryThreshold = 1
 
+// Initial delay between retries of calls to peers, doubled on each
+// retry.
+var peerRPCRetryUnit = 100 * time.Millisecond
+
+// rpcRetryPolicy - retries of calls failing with connection errors,
+// such as a connection reset during a brief network blip.
+type rpcRetryPolicy struct {
+	maxRetries int           // Retries after the first attempt, zero disables them.
+	unit       time.Duration // Initial delay between retries, doubled on each retry.
+	timeout    time.Duration // Time after which calls are not retried anymore, zero for no limit.
+}
+
+// newPeerRPCRetryPolicy - returns the retry policy of calls to S3 and
+// admin peers, set via command line.
+func newPeerRPCRetryPolicy() *rpcRetryPolicy {
+	return &rpcRetryPolicy{
+		maxRetries: globalPeerRPCRetries,
+		unit:       peerRPCRetryUnit,
+		timeout:    globalPeerRPCTimeout,
+	}
+}
+
+// isRetriableRPCError - returns true for errors of the connection to
+// the RPC server, as opposed to errors returned by the server.
+func isRetriableRPCError(err error) bool {
+	switch err.(type) {
+	case rpc.ServerError:
+		return false
+	case *net.OpError:
+		return true
+	}
+	return err == rpc.ErrShutdown || err == io.EOF || err == io.ErrUnexpectedEOF
+}
+
 // authConfig requires to make new AuthRPCClient.
 type authConfig struct {
 	accessKey        string // Access key (like username) for authentication.
@@ -35,6 +71,9 @@ type authConfig struct {
 	secureConn       bool   // Make TLS connection to RPC server or not.
 	serviceName      string // Service name of auth server.
 	disableReconnect bool   // Disable reconnect on failure or not.
+	// Retries of calls failing with connection errors, nil retries
+	// only once on ErrShutdown.
+	retry *rpcRetryPolicy
 }
 
 // AuthRPCClient is a authenticated RPC client which does authentication before doing Call().
@@ -101,10 +140,16 @@ func (authClient *AuthRPCClient) call(serviceMethod string, args interface {
 }
 
 // Call executes RPC call till success or globalAuthRPCRetryThreshold on ErrShutdown.
+// With a retry policy, calls failing with any connection error are
+// retried with exponential backoff within its bounds.
 func (authClient *AuthRPCClient) Call(serviceMethod string, args interface {
 	SetAuthToken(authToken string)
 	SetRequestTime(requestTime time.Time)
 }, reply interface{}) (err error) {
+	if authClient.config.retry != nil {
+		return authClient.callWithRetry(serviceMethod, args, reply)
+	}
+
 	doneCh := make(chan struct{})
 	defer close(doneCh)
 	for i := range newRetryTimer(time.Second, 30*time.Second, MaxJitter, doneCh) {
@@ -125,6 +170,35 @@ func (authClient *AuthRPCClient) Call(serviceMethod string, args interface {
 	return err
 }
 
+// callWithRetry - executes RPC call retrying connection errors until
+// the retries or the timeout of the retry policy are exhausted.
+func (authClient *AuthRPCClient) callWithRetry(serviceMethod string, args interface {
+	SetAuthToken(authToken string)
+	SetRequestTime(requestTime time.Time)
+}, reply interface{}) (err error) {
+	policy := authClient.config.retry
+	start := time.Now()
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	for i := range newRetryTimer(policy.unit, 30*time.Second, NoJitter, doneCh) {
+		if err = authClient.call(serviceMethod, args, reply); err == nil || !isRetriableRPCError(err) {
+			break
+		}
+
+		// The connection is broken, reconnect on the next call.
+		authClient.Close()
+
+		if authClient.config.disableReconnect || i >= policy.maxRetries {
+			break
+		}
+		if policy.timeout > 0 && time.Since(start) >= policy.timeout {
+			break
+		}
+	}
+	return err
+}
+
 // Close closes underlying RPC Client.
 func (authClient *AuthRPCClient) Close() error {
 	authClient.Lock()
@@ -16,7 +16,15 @@
 
 package cmd
 
-import "testing"
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/rpc"
+	"sync/atomic"
+	"testing"
+	"time"
+)
 
 // Tests authorized RPC client.
 func TestAuthRPCClient(t *testing.T) {
@@ -53,3 +61,114 @@ func TestAuthRPCClient(t *testing.T) {
 		t.Fatalf("Unexpected node value %s, but expected %s", authRPC.ServiceEndpoint(), authCfg.serviceEndpoint)
 	}
 }
+
+// flakyRPCServer - RPC service counting the calls it serves.
+type flakyRPCServer struct {
+	AuthRPCServer
+	calls int32
+}
+
+// Ping - succeeds for authenticated calls.
+func (s *flakyRPCServer) Ping(args *AuthRPCArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+	atomic.AddInt32(&s.calls, 1)
+	return nil
+}
+
+// Fail - always fails, as opposed to the connection.
+func (s *flakyRPCServer) Fail(args *AuthRPCArgs, reply *AuthRPCReply) error {
+	atomic.AddInt32(&s.calls, 1)
+	return errors.New("failed")
+}
+
+// flakyListener - closes the first failures connections it accepts.
+type flakyListener struct {
+	net.Listener
+	failures int32
+}
+
+func (l *flakyListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		if atomic.AddInt32(&l.failures, -1) >= 0 {
+			conn.Close()
+			continue
+		}
+		return conn, nil
+	}
+}
+
+// Tests calls failing with connection errors are retried.
+func TestAuthRPCClientRetry(t *testing.T) {
+	rootPath, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+	creds := serverConfig.GetCredential()
+
+	testCases := []struct {
+		// Connections closed by the server before it serves calls.
+		failures   int32
+		maxRetries int
+		method     string
+		success    bool
+		// Calls served by the server.
+		expectedCalls int32
+	}{
+		// Test case - 1.
+		// Transient failures are retried.
+		{2, 3, "Flaky.Ping", true, 1},
+		// Test case - 2.
+		// Retries are exhausted.
+		{4, 3, "Flaky.Ping", false, 0},
+		// Test case - 3.
+		// Retries are disabled.
+		{1, 0, "Flaky.Ping", false, 0},
+		// Test case - 4.
+		// Errors returned by the server are not retried.
+		{0, 3, "Flaky.Fail", false, 1},
+	}
+	for i, testCase := range testCases {
+		service := &flakyRPCServer{}
+		rpcServer := rpc.NewServer()
+		if err = rpcServer.RegisterName("Flaky", service); err != nil {
+			t.Fatal(err)
+		}
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		go http.Serve(&flakyListener{ln, testCase.failures}, rpcServer)
+
+		authRPC := newAuthRPCClient(authConfig{
+			accessKey:       creds.AccessKey,
+			secretKey:       creds.SecretKey,
+			serverAddr:      ln.Addr().String(),
+			serviceEndpoint: "/rpc",
+			serviceName:     "Flaky",
+			retry: &rpcRetryPolicy{
+				maxRetries: testCase.maxRetries,
+				unit:       time.Millisecond,
+				timeout:    5 * time.Second,
+			},
+		})
+		err = authRPC.Call(testCase.method, &AuthRPCArgs{}, &AuthRPCReply{})
+		if testCase.success && err != nil {
+			t.Errorf("Test %d: Expected call to succeed, got %v", i+1, err)
+		}
+		if !testCase.success && err == nil {
+			t.Errorf("Test %d: Expected call to fail", i+1)
+		}
+		if calls := atomic.LoadInt32(&service.calls); calls != testCase.expectedCalls {
+			t.Errorf("Test %d: Expected %d calls to be served, got %d", i+1, testCase.expectedCalls, calls)
+		}
+		authRPC.Close()
+		ln.Close()
+	}
+}
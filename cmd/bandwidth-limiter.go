@@ -0,0 +1,157 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Largest number of bytes transferred at once through a limiter,
+// larger transfers are split such that concurrent requests share
+// the bandwidth smoothly.
+const bandwidthChunkSize = 32 * 1024
+
+// bandwidthLimiter - token bucket bounding the aggregate rate of bytes
+// transferred by all the requests sharing it.
+type bandwidthLimiter struct {
+	mu    sync.Mutex
+	rate  float64 // Bytes per second.
+	burst float64 // Bytes transferred without waiting after idling.
+
+	// Bytes which may be transferred right away, negative when
+	// transfers are waiting for the bucket to refill.
+	tokens float64
+	last   time.Time
+}
+
+// newBandwidthLimiter - returns a limiter allowing rate bytes per
+// second, rate of 0 means unlimited and returns nil.
+func newBandwidthLimiter(rate uint64) *bandwidthLimiter {
+	if rate == 0 {
+		return nil
+	}
+	// Allow bursts of a tenth of a second, at least one chunk.
+	burst := float64(rate) / 10
+	if burst < bandwidthChunkSize {
+		burst = bandwidthChunkSize
+	}
+	return &bandwidthLimiter{
+		rate:   float64(rate),
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// parseBandwidthLimit - returns a limiter for a rate in bytes per
+// second such as "100MiB", nil if rate is empty.
+func parseBandwidthLimit(rate string) (*bandwidthLimiter, error) {
+	if rate == "" {
+		return nil, nil
+	}
+	bytesPerSec, err := humanize.ParseBytes(rate)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid bandwidth %s, %s", rate, err)
+	}
+	if bytesPerSec == 0 {
+		return nil, fmt.Errorf("Bandwidth must be at least 1 byte per second")
+	}
+	return newBandwidthLimiter(bytesPerSec), nil
+}
+
+// wait - blocks until n bytes may be transferred. Bytes are reserved
+// right away, such that concurrent callers wait in turn.
+func (l *bandwidthLimiter) wait(n int) {
+	if l == nil || n <= 0 {
+		return
+	}
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	time.Sleep(delay)
+}
+
+// limitedReader - io.ReadCloser waiting on its limiter for the bytes read.
+type limitedReader struct {
+	io.ReadCloser
+	limiter *bandwidthLimiter
+}
+
+func (r limitedReader) Read(p []byte) (int, error) {
+	if len(p) > bandwidthChunkSize {
+		p = p[:bandwidthChunkSize]
+	}
+	n, err := r.ReadCloser.Read(p)
+	r.limiter.wait(n)
+	return n, err
+}
+
+// limitedWriter - io.Writer waiting on its limiter before writing.
+type limitedWriter struct {
+	io.Writer
+	limiter *bandwidthLimiter
+}
+
+func (w limitedWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > bandwidthChunkSize {
+			chunk = chunk[:bandwidthChunkSize]
+		}
+		w.limiter.wait(len(chunk))
+		n, err := w.Writer.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[len(chunk):]
+	}
+	return written, nil
+}
+
+// reader - returns r bounded by the limiter, r itself if unlimited.
+func (l *bandwidthLimiter) reader(r io.ReadCloser) io.ReadCloser {
+	if l == nil {
+		return r
+	}
+	return limitedReader{r, l}
+}
+
+// writer - returns w bounded by the limiter, w itself if unlimited.
+func (l *bandwidthLimiter) writer(w io.Writer) io.Writer {
+	if l == nil {
+		return w
+	}
+	return limitedWriter{w, l}
+}
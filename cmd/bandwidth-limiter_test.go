@@ -0,0 +1,158 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Tests parsing of bandwidth limits.
+func TestParseBandwidthLimit(t *testing.T) {
+	testCases := []struct {
+		rate         string
+		expectedRate float64
+		shouldPass   bool
+	}{
+		{"", 0, true},
+		{"100MiB", 100 * humanize.MiByte, true},
+		{"1kB", 1000, true},
+		{"0", 0, false},
+		{"fast", 0, false},
+	}
+	for i, testCase := range testCases {
+		limiter, err := parseBandwidthLimit(testCase.rate)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: unexpected error %v", i+1, err)
+			continue
+		}
+		if !testCase.shouldPass {
+			if err == nil {
+				t.Errorf("Test %d: expected %s to be rejected", i+1, testCase.rate)
+			}
+			continue
+		}
+		if testCase.expectedRate == 0 && limiter != nil {
+			t.Errorf("Test %d: expected no limit, got %v", i+1, limiter.rate)
+		}
+		if testCase.expectedRate != 0 && (limiter == nil || limiter.rate != testCase.expectedRate) {
+			t.Errorf("Test %d: expected rate %v, got %v", i+1, testCase.expectedRate, limiter)
+		}
+	}
+}
+
+// Tests object downloads and uploads stay within the bandwidth limits,
+// shared by concurrent requests.
+func TestBandwidthLimitedObjectHandlers(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(fsDir)
+	initNSLock(false)
+
+	const rate = humanize.MiByte
+	defer func() { globalEgressLimiter, globalIngressLimiter = nil, nil }()
+	globalEgressLimiter = newBandwidthLimiter(rate)
+	globalIngressLimiter = newBandwidthLimiter(rate)
+	burst := globalEgressLimiter.burst
+
+	bucketName := getRandomBucketName()
+	if err = obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Repeat([]byte("a"), 768*humanize.KiByte)
+	if _, err = obj.PutObject(bucketName, "object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	apiRouter := initTestAPIEndPoints(obj, []string{"GetObject", "PutObjectPart"})
+	credentials := serverConfig.GetCredential()
+	do := func(method, url string, body []byte, header http.Header) *httptest.ResponseRecorder {
+		req, rerr := newTestSignedRequestV4(method, url, int64(len(body)), bytes.NewReader(body),
+			credentials.AccessKey, credentials.SecretKey)
+		if rerr != nil {
+			t.Fatal(rerr)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// checkRate - verifies transferring n bytes took long enough to
+	// stay under the limit, beyond the initial burst.
+	checkRate := func(name string, n int, elapsed time.Duration) {
+		minElapsed := time.Duration((float64(n) - burst) / rate * float64(time.Second))
+		if elapsed < minElapsed {
+			t.Errorf("%s: %d bytes took %s, at least %s expected at %d bytes per second",
+				name, n, elapsed, minElapsed, rate)
+		}
+	}
+
+	// Two concurrent downloads share the limit.
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := do("GET", getGetObjectURL("", bucketName, "object"), nil, nil)
+			if rec.Code != http.StatusOK || !bytes.Equal(rec.Body.Bytes(), data) {
+				t.Errorf("Unexpected download, status %d with %d bytes", rec.Code, rec.Body.Len())
+			}
+		}()
+	}
+	wg.Wait()
+	checkRate("Concurrent downloads", 2*len(data), time.Since(start))
+
+	// Range reads are counted by the bytes sent.
+	start = time.Now()
+	rec := do("GET", getGetObjectURL("", bucketName, "object"), nil,
+		http.Header{"Range": {"bytes=131072-655359"}})
+	if rec.Code != http.StatusPartialContent || rec.Body.Len() != 512*humanize.KiByte {
+		t.Fatalf("Unexpected range download, status %d with %d bytes", rec.Code, rec.Body.Len())
+	}
+	checkRate("Range download", rec.Body.Len(), time.Since(start))
+
+	// Parts of multipart uploads are limited by the ingress limit.
+	uploadID, err := obj.NewMultipartUpload(bucketName, "multipart", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part := data[:512*humanize.KiByte]
+	start = time.Now()
+	rec = do("PUT", getPutObjectPartURL("", bucketName, "multipart", uploadID, "1"), part, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Unexpected part upload status %d: %s", rec.Code, rec.Body.String())
+	}
+	checkRate("Part upload", len(part), time.Since(start))
+}
@@ -0,0 +1,106 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "sync"
+
+// bucketInfoCache - in-memory cache of the info of existing buckets,
+// serving GetBucketLocation and HeadBucket without reading the disks.
+// Only existing buckets are cached, entries are removed on all the
+// servers when a bucket is created or deleted.
+type bucketInfoCache struct {
+	mu      sync.RWMutex
+	buckets map[string]BucketInfo
+
+	// Incremented on every removal, a lookup racing with a removal
+	// does not cache what it read.
+	generation uint64
+}
+
+// newBucketInfoCache - returns an empty bucket info cache.
+func newBucketInfoCache() *bucketInfoCache {
+	return &bucketInfoCache{buckets: make(map[string]BucketInfo)}
+}
+
+// get - returns the cached info of bucket, if any.
+func (c *bucketInfoCache) get(bucket string) (BucketInfo, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	info, ok := c.buckets[bucket]
+	return info, ok
+}
+
+// remove - forgets bucket.
+func (c *bucketInfoCache) remove(bucket string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.buckets, bucket)
+	c.generation++
+}
+
+// getBucketInfo - returns the info of bucket from the cache, reading it
+// from the object layer on a miss.
+func (c *bucketInfoCache) getBucketInfo(objAPI ObjectLayer, bucket string) (BucketInfo, error) {
+	if info, ok := c.get(bucket); ok {
+		return info, nil
+	}
+
+	c.mu.RLock()
+	generation := c.generation
+	c.mu.RUnlock()
+
+	info, err := objAPI.GetBucketInfo(bucket)
+	if err != nil {
+		return BucketInfo{}, err
+	}
+
+	// The bucket may have been deleted after it was read, in which
+	// case the removal is seen here and the info is stale.
+	c.mu.Lock()
+	if c.generation == generation {
+		c.buckets[bucket] = info
+	}
+	c.mu.Unlock()
+	return info, nil
+}
+
+// invalidateBucketCache - removes bucket from the cache of this server
+// and of all its peers. Failures to reach a peer are logged.
+func invalidateBucketCache(bucket string) {
+	globalBucketCache.remove(bucket)
+
+	// This server is not reached through RPC.
+	var peerIndex []int
+	for idx, peer := range globalS3Peers {
+		if peer.addr != globalMinioAddr {
+			peerIndex = append(peerIndex, idx)
+		}
+	}
+	if len(peerIndex) == 0 {
+		return
+	}
+
+	args := &InvalidateBucketCachePeerArgs{Bucket: bucket}
+	errs := globalS3Peers.SendUpdate(peerIndex, args)
+	for idx, err := range errs {
+		errorIf(
+			err,
+			"Error sending bucket cache invalidation to %s - %v",
+			globalS3Peers[idx].addr, err,
+		)
+	}
+}
@@ -0,0 +1,158 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// bucketInfoCountingLayer - object layer counting GetBucketInfo calls.
+type bucketInfoCountingLayer struct {
+	ObjectLayer
+	calls int32
+}
+
+func (l *bucketInfoCountingLayer) GetBucketInfo(bucket string) (BucketInfo, error) {
+	atomic.AddInt32(&l.calls, 1)
+	return l.ObjectLayer.GetBucketInfo(bucket)
+}
+
+// Tests GetBucketLocation and HeadBucket are served from the cache and
+// see deleted and re-created buckets.
+func TestBucketInfoCache(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(fsDir)
+	initNSLock(false)
+	globalBucketCache = newBucketInfoCache()
+
+	obj := &bucketInfoCountingLayer{ObjectLayer: objLayer}
+	apiRouter := initTestAPIEndPoints(obj, []string{"GetBucketLocation", "HeadBucket"})
+	credentials := serverConfig.GetCredential()
+
+	bucketName := getRandomBucketName()
+	if err = makeBucket(bucketName, obj); err != nil {
+		t.Fatal(err)
+	}
+
+	// checkRequests - sends GetBucketLocation and HeadBucket n times
+	// each, verifying their status and the number of reads of the
+	// object layer.
+	checkRequests := func(name string, n int, expectedStatus int, expectedCalls int32) {
+		atomic.StoreInt32(&obj.calls, 0)
+		for i := 0; i < n; i++ {
+			for method, u := range map[string]string{
+				"GET":  getBucketLocationURL("", bucketName),
+				"HEAD": getHEADBucketURL("", bucketName),
+			} {
+				req, rerr := newTestSignedRequestV4(method, u, 0, nil, credentials.AccessKey, credentials.SecretKey)
+				if rerr != nil {
+					t.Fatal(rerr)
+				}
+				rec := httptest.NewRecorder()
+				apiRouter.ServeHTTP(rec, req)
+				if rec.Code != expectedStatus {
+					t.Errorf("%s: Expected %s %s to reply %d, got %d", name, method, u, expectedStatus, rec.Code)
+				}
+			}
+		}
+		if calls := atomic.LoadInt32(&obj.calls); calls != expectedCalls {
+			t.Errorf("%s: Expected %d bucket info reads, got %d", name, expectedCalls, calls)
+		}
+	}
+
+	// The bucket is read once, then served from the cache.
+	checkRequests("Existing bucket", 5, http.StatusOK, 1)
+	checkRequests("Cached bucket", 5, http.StatusOK, 0)
+
+	// Missing buckets are not cached.
+	if err = deleteBucket(bucketName, obj); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := globalBucketCache.get(bucketName); ok {
+		t.Fatal("Expected deleted bucket to be removed from the cache")
+	}
+	checkRequests("Deleted bucket", 2, http.StatusNotFound, 4)
+
+	// A re-created bucket is read again.
+	if err = makeBucket(bucketName, obj); err != nil {
+		t.Fatal(err)
+	}
+	checkRequests("Re-created bucket", 2, http.StatusOK, 1)
+
+	// Invalidation sent by a peer.
+	bms := &localBucketMetaState{ObjectAPI: newObjectLayerFn}
+	if err = bms.InvalidateBucketCache(&InvalidateBucketCachePeerArgs{Bucket: bucketName}); err != nil {
+		t.Fatal(err)
+	}
+	checkRequests("Invalidated bucket", 2, http.StatusOK, 1)
+}
+
+// Tests a lookup racing with the deletion of a bucket does not cache
+// the bucket.
+func TestBucketInfoCacheRemoveDuringLookup(t *testing.T) {
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(fsDir)
+
+	bucketName := getRandomBucketName()
+	if err = objLayer.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := newBucketInfoCache()
+	obj := &removingLayer{ObjectLayer: objLayer, remove: func() { cache.remove(bucketName) }}
+	if _, err = cache.getBucketInfo(obj, bucketName); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.get(bucketName); ok {
+		t.Fatal("Expected bucket removed during lookup not to be cached")
+	}
+
+	obj.remove = func() {}
+	if _, err = cache.getBucketInfo(obj, bucketName); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.get(bucketName); !ok {
+		t.Fatal("Expected bucket to be cached")
+	}
+}
+
+// removingLayer - object layer calling remove after reading a bucket.
+type removingLayer struct {
+	ObjectLayer
+	remove func()
+}
+
+func (l *removingLayer) GetBucketInfo(bucket string) (BucketInfo, error) {
+	info, err := l.ObjectLayer.GetBucketInfo(bucket)
+	l.remove()
+	return info, err
+}
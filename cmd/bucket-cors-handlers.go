@@ -0,0 +1,180 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	humanize "github.com/dustin/go-humanize"
+	mux "github.com/gorilla/mux"
+)
+
+// Maximum size of a CORS configuration, same as S3.
+const maxCORSConfigSize = 64 * humanize.KiByte
+
+// CORSConfiguration - format of PutBucketCors requests and of the
+// response of GetBucketCors.
+type CORSConfiguration struct {
+	XMLName xml.Name   `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CORSConfiguration" json:"-"`
+	Rules   []corsRule `xml:"CORSRule"`
+}
+
+// GetBucketCorsHandler - GET Bucket cors
+// -----------------
+// This operation uses the cors subresource to return the CORS rules
+// of a bucket.
+func (api objectAPIHandlers) GetBucketCorsHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	if _, err := objAPI.GetBucketInfo(bucket); err != nil {
+		requestErrorIf(r, err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	rules := serverConfig.GetBucketCors(bucket)
+	if len(rules) == 0 {
+		writeErrorResponse(w, ErrNoSuchCORSConfiguration, r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseXML(w, encodeResponse(CORSConfiguration{Rules: rules}))
+}
+
+// PutBucketCorsHandler - PUT Bucket cors
+// -----------------
+// This operation uses the cors subresource to set the CORS rules of a
+// bucket on all the servers in the cluster, replacing previous rules.
+func (api objectAPIHandlers) PutBucketCorsHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	if _, err := objAPI.GetBucketInfo(bucket); err != nil {
+		requestErrorIf(r, err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// If Content-Length is unknown or zero, deny the request.
+	// PutBucketCors always needs a Content-Length if incoming
+	// request is not chunked.
+	if !contains(r.TransferEncoding, "chunked") {
+		if r.ContentLength == -1 || r.ContentLength == 0 {
+			writeErrorResponse(w, ErrMissingContentLength, r.URL)
+			return
+		}
+		// If Content-Length is greater than maximum allowed config size.
+		if r.ContentLength > maxCORSConfigSize {
+			writeErrorResponse(w, ErrEntityTooLarge, r.URL)
+			return
+		}
+	}
+
+	// Read CORS configuration up to maxCORSConfigSize.
+	configBytes, err := ioutil.ReadAll(io.LimitReader(r.Body, maxCORSConfigSize))
+	if err != nil {
+		requestErrorIf(r, err, "Unable to read from client.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	var config CORSConfiguration
+	if err = xml.Unmarshal(configBytes, &config); err != nil {
+		requestErrorIf(r, err, "Unable to parse CORS configuration XML.")
+		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		return
+	}
+	if !isValidCORSRules(config.Rules) {
+		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		return
+	}
+
+	if err = sendSetBucketCorsCmd(globalAdminPeers, bucket, config.Rules); err != nil {
+		requestErrorIf(r, err, "Failed to set bucket CORS on remote nodes.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// DeleteBucketCorsHandler - DELETE Bucket cors
+// -----------------
+// This operation uses the cors subresource to remove the CORS rules of
+// a bucket on all the servers in the cluster.
+func (api objectAPIHandlers) DeleteBucketCorsHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	if _, err := objAPI.GetBucketInfo(bucket); err != nil {
+		requestErrorIf(r, err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// No rules removes them.
+	if err := sendSetBucketCorsCmd(globalAdminPeers, bucket, nil); err != nil {
+		requestErrorIf(r, err, "Failed to remove bucket CORS on remote nodes.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessNoContent(w)
+}
@@ -0,0 +1,218 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// Tests CORS rules put with the S3 bucket cors API answer preflight
+// requests and set the Access-Control headers of cross-origin requests.
+func TestBucketCorsHandlers(t *testing.T) {
+	ts, stop := startTestServerWithPeers(t, "XL")
+	defer stop()
+
+	bucketName := getRandomBucketName()
+	if err := ts.Obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+	objectName := "object"
+	if _, err := ts.Obj.PutObject(bucketName, objectName, 5, bytes.NewReader([]byte("hello")), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	do := func(req *http.Request) (*http.Response, []byte) {
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		respBody, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp, respBody
+	}
+	signed := func(method, url string, body []byte, headers map[string]string) (*http.Response, []byte) {
+		req, err := newTestSignedRequestV4(method, url, int64(len(body)), bytes.NewReader(body), ts.AccessKey, ts.SecretKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		return do(req)
+	}
+	preflight := func(url, origin, method, headers string) *http.Response {
+		req, err := http.NewRequest("OPTIONS", url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", method)
+		if headers != "" {
+			req.Header.Set("Access-Control-Request-Headers", headers)
+		}
+		resp, _ := do(req)
+		return resp
+	}
+
+	// getErrorCode - returns the code of an S3 error response.
+	getErrorCode := func(body []byte) string {
+		var apiErr APIErrorResponse
+		if err := xml.Unmarshal(body, &apiErr); err != nil {
+			t.Fatalf("Failed to parse error response: <ERROR> %v", err)
+		}
+		return apiErr.Code
+	}
+
+	corsURL := ts.Server.URL + "/" + bucketName + "?cors"
+	objectURL := ts.Server.URL + "/" + bucketName + "/" + objectName
+
+	// Buckets without rules have no CORS configuration and allow any
+	// origin, as before: the origin of the request is allowed.
+	resp, body := signed("GET", corsURL, nil, nil)
+	if resp.StatusCode != http.StatusNotFound || getErrorCode(body) != "NoSuchCORSConfiguration" {
+		t.Fatalf("Expected NoSuchCORSConfiguration, got %d: %s", resp.StatusCode, body)
+	}
+	resp, _ = signed("GET", objectURL, nil, map[string]string{"Origin": "http://example.com"})
+	if origin := resp.Header.Get("Access-Control-Allow-Origin"); origin != "http://example.com" {
+		t.Errorf("Expected any origin to be allowed without rules, got %q", origin)
+	}
+
+	// Invalid configurations are rejected.
+	invalidConfig := []byte(`<CORSConfiguration><CORSRule><AllowedOrigin>*</AllowedOrigin><AllowedMethod>PATCH</AllowedMethod></CORSRule></CORSConfiguration>`)
+	if resp, body = signed("PUT", corsURL, invalidConfig, nil); resp.StatusCode != http.StatusBadRequest || getErrorCode(body) != "MalformedXML" {
+		t.Fatalf("Expected MalformedXML, got %d: %s", resp.StatusCode, body)
+	}
+
+	config := []byte(`<CORSConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <CORSRule>
+    <AllowedOrigin>http://www.example.com</AllowedOrigin>
+    <AllowedMethod>GET</AllowedMethod>
+    <AllowedMethod>PUT</AllowedMethod>
+    <AllowedHeader>x-amz-*</AllowedHeader>
+    <ExposeHeader>ETag</ExposeHeader>
+    <MaxAgeSeconds>3000</MaxAgeSeconds>
+  </CORSRule>
+  <CORSRule>
+    <AllowedOrigin>*</AllowedOrigin>
+    <AllowedMethod>HEAD</AllowedMethod>
+  </CORSRule>
+</CORSConfiguration>`)
+	if resp, body = signed("PUT", corsURL, config, nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected PutBucketCors to succeed, got %d: %s", resp.StatusCode, body)
+	}
+	resp, body = signed("GET", corsURL, nil, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected GetBucketCors to succeed, got %d: %s", resp.StatusCode, body)
+	}
+	var corsConfig CORSConfiguration
+	if err := xml.Unmarshal(body, &corsConfig); err != nil {
+		t.Fatalf("Failed to parse CORS configuration: <ERROR> %v", err)
+	}
+	expectedRules := []corsRule{
+		{
+			AllowedOrigins: []string{"http://www.example.com"},
+			AllowedMethods: []string{"GET", "PUT"},
+			AllowedHeaders: []string{"x-amz-*"},
+			ExposeHeaders:  []string{"ETag"},
+			MaxAgeSeconds:  3000,
+		},
+		{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"HEAD"}},
+	}
+	if !reflect.DeepEqual(corsConfig.Rules, expectedRules) {
+		t.Errorf("Expected rules %v, got %v", expectedRules, corsConfig.Rules)
+	}
+
+	// Preflight requests allowed by a rule.
+	resp = preflight(objectURL, "http://www.example.com", "PUT", "X-Amz-Date, x-amz-content-sha256")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected preflight request to be allowed, got %d", resp.StatusCode)
+	}
+	expectedHeaders := map[string]string{
+		"Access-Control-Allow-Origin":      "http://www.example.com",
+		"Access-Control-Allow-Credentials": "true",
+		"Access-Control-Allow-Methods":     "GET, PUT",
+		"Access-Control-Allow-Headers":     "X-Amz-Date, x-amz-content-sha256",
+		"Access-Control-Max-Age":           "3000",
+	}
+	for header, value := range expectedHeaders {
+		if got := resp.Header.Get(header); got != value {
+			t.Errorf("Expected %s: %q, got %q", header, value, got)
+		}
+	}
+	resp = preflight(objectURL, "http://other.com", "HEAD", "")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Access-Control-Allow-Origin") != "*" {
+		t.Errorf("Expected preflight request from any origin to be allowed, got %d, %q",
+			resp.StatusCode, resp.Header.Get("Access-Control-Allow-Origin"))
+	}
+
+	// Preflight requests not allowed by any rule.
+	for _, testCase := range []struct{ origin, method, headers string }{
+		{"http://other.com", "GET", ""},
+		{"http://www.example.com", "DELETE", ""},
+		{"http://www.example.com", "PUT", "Content-Type"},
+	} {
+		resp = preflight(objectURL, testCase.origin, testCase.method, testCase.headers)
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected preflight request %v to be forbidden, got %d", testCase, resp.StatusCode)
+		}
+		if resp.Header.Get("Access-Control-Allow-Origin") != "" {
+			t.Errorf("Expected no allowed origin for preflight request %v", testCase)
+		}
+	}
+
+	// Cross-origin requests allowed by a rule.
+	resp, body = signed("GET", objectURL, nil, map[string]string{"Origin": "http://www.example.com"})
+	if resp.StatusCode != http.StatusOK || string(body) != "hello" {
+		t.Fatalf("Expected cross-origin GET to succeed, got %d: %s", resp.StatusCode, body)
+	}
+	if origin := resp.Header.Get("Access-Control-Allow-Origin"); origin != "http://www.example.com" {
+		t.Errorf("Expected origin to be allowed, got %q", origin)
+	}
+	if exposed := resp.Header.Get("Access-Control-Expose-Headers"); exposed != "ETag" {
+		t.Errorf("Expected ETag to be exposed, got %q", exposed)
+	}
+
+	// Cross-origin requests not allowed by any rule are served without
+	// Access-Control headers.
+	resp, _ = signed("GET", objectURL, nil, map[string]string{"Origin": "http://other.com"})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected GET to succeed, got %d", resp.StatusCode)
+	}
+	if origin := resp.Header.Get("Access-Control-Allow-Origin"); origin != "" {
+		t.Errorf("Expected origin not to be allowed, got %q", origin)
+	}
+
+	// Rules are deleted from the config of all the servers.
+	if resp, body = signed("DELETE", corsURL, nil, nil); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("Expected DeleteBucketCors to succeed, got %d: %s", resp.StatusCode, body)
+	}
+	if rules := serverConfig.GetBucketCors(bucketName); len(rules) != 0 {
+		t.Fatalf("Expected rules to be removed from config, got %v", rules)
+	}
+	resp, body = signed("GET", corsURL, nil, nil)
+	if resp.StatusCode != http.StatusNotFound || getErrorCode(body) != "NoSuchCORSConfiguration" {
+		t.Fatalf("Expected NoSuchCORSConfiguration, got %d: %s", resp.StatusCode, body)
+	}
+}
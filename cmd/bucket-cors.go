@@ -0,0 +1,132 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Maximum number of CORS rules of a bucket, same as S3.
+const maxCORSRules = 100
+
+// corsRule - cross-origin requests allowed for a bucket, origins and
+// headers may contain a single * wildcard.
+type corsRule struct {
+	ID             string   `xml:"ID,omitempty" json:"id,omitempty"`
+	AllowedOrigins []string `xml:"AllowedOrigin" json:"allowedOrigins"`
+	AllowedMethods []string `xml:"AllowedMethod" json:"allowedMethods"`
+	AllowedHeaders []string `xml:"AllowedHeader,omitempty" json:"allowedHeaders,omitempty"`
+	ExposeHeaders  []string `xml:"ExposeHeader,omitempty" json:"exposeHeaders,omitempty"`
+	MaxAgeSeconds  int      `xml:"MaxAgeSeconds,omitempty" json:"maxAgeSeconds,omitempty"`
+}
+
+// bucketCors - CORS rules of each bucket.
+type bucketCors map[string][]corsRule
+
+// validate - verifies CORS rules of all buckets.
+func (c bucketCors) validate() error {
+	for bucket, rules := range c {
+		if !isValidCORSRules(rules) {
+			return fmt.Errorf("Invalid CORS rules of bucket %s", bucket)
+		}
+	}
+	return nil
+}
+
+// Methods of cross-origin requests, same as S3.
+var corsMethods = []string{"GET", "PUT", "HEAD", "POST", "DELETE"}
+
+// isValidCORSRules - returns true if there are between 1 and
+// maxCORSRules rules, each allowing at least an origin and a method
+// of corsMethods, with at most one wildcard per origin and header.
+func isValidCORSRules(rules []corsRule) bool {
+	if len(rules) == 0 || len(rules) > maxCORSRules {
+		return false
+	}
+	for _, rule := range rules {
+		if len(rule.AllowedOrigins) == 0 || len(rule.AllowedMethods) == 0 || rule.MaxAgeSeconds < 0 {
+			return false
+		}
+		for _, method := range rule.AllowedMethods {
+			if !contains(corsMethods, method) {
+				return false
+			}
+		}
+		for _, pattern := range append(append([]string{}, rule.AllowedOrigins...), rule.AllowedHeaders...) {
+			if strings.Count(pattern, "*") > 1 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// corsWildcardMatch - returns true if value matches pattern, which
+// contains at most one * matching any characters, case insensitive.
+func corsWildcardMatch(pattern, value string) bool {
+	pattern, value = strings.ToLower(pattern), strings.ToLower(value)
+	i := strings.Index(pattern, "*")
+	if i < 0 {
+		return pattern == value
+	}
+	prefix, suffix := pattern[:i], pattern[i+1:]
+	return len(value) >= len(prefix)+len(suffix) &&
+		strings.HasPrefix(value, prefix) && strings.HasSuffix(value, suffix)
+}
+
+// corsWildcardMatchAny - returns true if value matches any of patterns.
+func corsWildcardMatchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if corsWildcardMatch(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// match - returns true if the rule allows requests of method from
+// origin with headers.
+func (r corsRule) match(origin, method string, headers []string) bool {
+	if !corsWildcardMatchAny(r.AllowedOrigins, origin) || !contains(r.AllowedMethods, method) {
+		return false
+	}
+	for _, header := range headers {
+		if !corsWildcardMatchAny(r.AllowedHeaders, header) {
+			return false
+		}
+	}
+	return true
+}
+
+// findCORSRule - returns the first rule allowing requests of method
+// from origin with headers, as with S3.
+func findCORSRule(rules []corsRule, origin, method string, headers []string) (corsRule, bool) {
+	for _, rule := range rules {
+		if rule.match(origin, method, headers) {
+			return rule, true
+		}
+	}
+	return corsRule{}, false
+}
+
+// setBucketCors - saves CORS rules of bucket to the config of this
+// server, no rules removes them.
+func setBucketCors(bucket string, rules []corsRule) error {
+	serverConfig.SetBucketCors(bucket, rules)
+	return serverConfig.Save()
+}
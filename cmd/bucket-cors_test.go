@@ -0,0 +1,78 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "testing"
+
+// Tests validation of CORS rules.
+func TestIsValidCORSRules(t *testing.T) {
+	testCases := []struct {
+		rules    []corsRule
+		expected bool
+	}{
+		{nil, false},
+		{[]corsRule{{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}}}, true},
+		{[]corsRule{{AllowedOrigins: []string{"http://*.example.com"}, AllowedMethods: []string{"GET", "PUT", "HEAD", "POST", "DELETE"}, AllowedHeaders: []string{"*"}}}, true},
+		// Rules without origins or methods.
+		{[]corsRule{{AllowedMethods: []string{"GET"}}}, false},
+		{[]corsRule{{AllowedOrigins: []string{"*"}}}, false},
+		// Methods are case sensitive, as with S3.
+		{[]corsRule{{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"get"}}}, false},
+		{[]corsRule{{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"OPTIONS"}}}, false},
+		// More than one wildcard.
+		{[]corsRule{{AllowedOrigins: []string{"http://*.*.com"}, AllowedMethods: []string{"GET"}}}, false},
+		{[]corsRule{{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}, AllowedHeaders: []string{"**"}}}, false},
+		{[]corsRule{{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}, MaxAgeSeconds: -1}}, false},
+		{make([]corsRule, maxCORSRules+1), false},
+	}
+	for i, testCase := range testCases {
+		if valid := isValidCORSRules(testCase.rules); valid != testCase.expected {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expected, valid)
+		}
+	}
+}
+
+// Tests the first rule allowing a cross-origin request is found.
+func TestFindCORSRule(t *testing.T) {
+	rules := []corsRule{
+		{ID: "1", AllowedOrigins: []string{"https://*.example.com"}, AllowedMethods: []string{"GET"}},
+		{ID: "2", AllowedOrigins: []string{"http://www.example.com"}, AllowedMethods: []string{"PUT"}, AllowedHeaders: []string{"x-amz-*", "Content-Type"}},
+		{ID: "3", AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET", "HEAD"}},
+	}
+	testCases := []struct {
+		origin     string
+		method     string
+		headers    []string
+		expectedID string
+	}{
+		{"https://www.example.com", "GET", nil, "1"},
+		{"HTTPS://WWW.EXAMPLE.COM", "GET", nil, "1"},
+		{"https://example.com", "GET", nil, "3"},
+		{"http://www.example.com", "PUT", []string{"X-Amz-Date", "content-type"}, "2"},
+		{"http://www.example.com", "PUT", []string{"Authorization"}, ""},
+		{"http://other.com", "PUT", nil, ""},
+		{"http://other.com", "HEAD", nil, "3"},
+		{"http://other.com", "HEAD", []string{"Range"}, ""},
+		{"http://other.com", "DELETE", nil, ""},
+	}
+	for i, testCase := range testCases {
+		rule, ok := findCORSRule(rules, testCase.origin, testCase.method, testCase.headers)
+		if ok != (testCase.expectedID != "") || rule.ID != testCase.expectedID {
+			t.Errorf("Test %d: Expected rule %q, got %q", i+1, testCase.expectedID, rule.ID)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// Wrapper for calling ListObjects handler tests for both XL multiple disks and single node setup.
+func TestListObjectsEncodingTypeHandler(t *testing.T) {
+	ExecObjectLayerAPITest(t, testListObjectsEncodingTypeHandler, []string{"ListObjectsV2", "ListObjectsV1"})
+}
+
+// testListObjectsEncodingTypeHandler - Tests validate encoding-type=url
+// handling of ListObjects V1 and V2.
+func testListObjectsEncodingTypeHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	objectNames := []string{"a&b", "dir with space/obj", "unicode-ü"}
+	for _, objectName := range objectNames {
+		_, err := obj.PutObject(bucketName, objectName, int64(len("hello")), bytes.NewReader([]byte("hello")), nil, "")
+		if err != nil {
+			t.Fatalf("%s: Failed to put object %s: <ERROR> %s", instanceType, objectName, err)
+		}
+	}
+
+	testCases := []struct {
+		listType           string
+		encodingType       string
+		delimiter          string
+		expectedRespStatus int
+		expectedKeys       []string
+		expectedPrefixes   []string
+	}{
+		// Test case - 1.
+		// Keys are returned as is without encoding-type.
+		{"1", "", "", http.StatusOK, []string{"a&b", "dir with space/obj", "unicode-ü"}, nil},
+		// Test case - 2.
+		// Keys are URL encoded with encoding-type=url.
+		{"1", "url", "", http.StatusOK, []string{"a%26b", "dir+with+space/obj", "unicode-%C3%BC"}, nil},
+		// Test case - 3.
+		// Common prefixes are URL encoded with encoding-type=url.
+		{"1", "url", "/", http.StatusOK, []string{"a%26b", "unicode-%C3%BC"}, []string{"dir+with+space/"}},
+		// Test case - 4.
+		// ListObjectsV2 without encoding-type.
+		{"2", "", "/", http.StatusOK, []string{"a&b", "unicode-ü"}, []string{"dir with space/"}},
+		// Test case - 5.
+		// ListObjectsV2 with encoding-type=url.
+		{"2", "url", "", http.StatusOK, []string{"a%26b", "dir+with+space/obj", "unicode-%C3%BC"}, nil},
+		// Test case - 6.
+		// Invalid encoding-type.
+		{"1", "invalid", "", http.StatusBadRequest, nil, nil},
+		// Test case - 7.
+		// Invalid encoding-type for ListObjectsV2.
+		{"2", "invalid", "", http.StatusBadRequest, nil, nil},
+	}
+
+	for i, testCase := range testCases {
+		queryVal := url.Values{}
+		if testCase.listType == "2" {
+			queryVal.Set("list-type", "2")
+		}
+		if testCase.encodingType != "" {
+			queryVal.Set("encoding-type", testCase.encodingType)
+		}
+		if testCase.delimiter != "" {
+			queryVal.Set("delimiter", testCase.delimiter)
+		}
+		req, err := newTestSignedRequestV4("GET", "/"+bucketName+"?"+queryVal.Encode(), 0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for ListObjects: <ERROR> %v", i+1, instanceType, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+
+		var keys, prefixes []string
+		var encodingType string
+		if testCase.listType == "2" {
+			var resp ListObjectsV2Response
+			if err = xml.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Test %d: %s: Failed to parse ListObjectsV2 response: <ERROR> %v", i+1, instanceType, err)
+			}
+			for _, content := range resp.Contents {
+				keys = append(keys, content.Key)
+			}
+			for _, prefix := range resp.CommonPrefixes {
+				prefixes = append(prefixes, prefix.Prefix)
+			}
+			encodingType = resp.EncodingType
+		} else {
+			var resp ListObjectsResponse
+			if err = xml.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Test %d: %s: Failed to parse ListObjects response: <ERROR> %v", i+1, instanceType, err)
+			}
+			for _, content := range resp.Contents {
+				keys = append(keys, content.Key)
+			}
+			for _, prefix := range resp.CommonPrefixes {
+				prefixes = append(prefixes, prefix.Prefix)
+			}
+			encodingType = resp.EncodingType
+		}
+
+		if encodingType != testCase.encodingType {
+			t.Errorf("Test %d: %s: Expected EncodingType `%s`, but found `%s`", i+1, instanceType, testCase.encodingType, encodingType)
+		}
+		if len(keys) != len(testCase.expectedKeys) {
+			t.Fatalf("Test %d: %s: Expected keys %v, but found %v", i+1, instanceType, testCase.expectedKeys, keys)
+		}
+		for j := range keys {
+			if keys[j] != testCase.expectedKeys[j] {
+				t.Errorf("Test %d: %s: Expected key `%s`, but found `%s`", i+1, instanceType, testCase.expectedKeys[j], keys[j])
+			}
+		}
+		if len(prefixes) != len(testCase.expectedPrefixes) {
+			t.Fatalf("Test %d: %s: Expected prefixes %v, but found %v", i+1, instanceType, testCase.expectedPrefixes, prefixes)
+		}
+		for j := range prefixes {
+			if prefixes[j] != testCase.expectedPrefixes[j] {
+				t.Errorf("Test %d: %s: Expected prefix `%s`, but found `%s`", i+1, instanceType, testCase.expectedPrefixes[j], prefixes[j])
+			}
+		}
+	}
+}
+
+// Wrapper for calling ListObjects pagination tests for both XL multiple disks and single node setup.
+func TestListObjectsV1V2Pagination(t *testing.T) {
+	ExecObjectLayerAPITest(t, testListObjectsV1V2Pagination, []string{"ListObjectsV2", "ListObjectsV1"})
+}
+
+// listObjectsPage - keys and common prefixes of a page of a listing.
+type listObjectsPage struct {
+	Keys     []string
+	Prefixes []string
+}
+
+// testListObjectsV1V2Pagination - Tests ListObjects V1 and V2 return
+// the same pages over the same bucket.
+func testListObjectsV1V2Pagination(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	objectNames := []string{"a/1", "a/2", "b", "c/d/1", "c/e", "d", "e", "f/1"}
+	for _, objectName := range objectNames {
+		_, err := obj.PutObject(bucketName, objectName, int64(len("hello")), bytes.NewReader([]byte("hello")), nil, "")
+		if err != nil {
+			t.Fatalf("%s: Failed to put object %s: <ERROR> %s", instanceType, objectName, err)
+		}
+	}
+
+	listObjects := func(queryVal url.Values) *httptest.ResponseRecorder {
+		req, err := newTestSignedRequestV4("GET", "/"+bucketName+"?"+queryVal.Encode(), 0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for ListObjects: <ERROR> %v", instanceType, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`: %s", instanceType, http.StatusOK, rec.Code, rec.Body)
+		}
+		return rec
+	}
+
+	// listV1 - lists all pages of ListObjects V1, continued by NextMarker.
+	listV1 := func(prefix, delimiter string, maxKeys int) (pages []listObjectsPage) {
+		marker := ""
+		for {
+			queryVal := url.Values{}
+			queryVal.Set("prefix", prefix)
+			queryVal.Set("delimiter", delimiter)
+			queryVal.Set("max-keys", strconv.Itoa(maxKeys))
+			queryVal.Set("marker", marker)
+			var resp ListObjectsResponse
+			if err := xml.Unmarshal(listObjects(queryVal).Body.Bytes(), &resp); err != nil {
+				t.Fatalf("%s: Failed to parse ListObjects response: <ERROR> %v", instanceType, err)
+			}
+			var page listObjectsPage
+			for _, content := range resp.Contents {
+				page.Keys = append(page.Keys, content.Key)
+				if content.Owner == nil {
+					t.Errorf("%s: Expected ListObjects to return the owner of %s", instanceType, content.Key)
+				}
+			}
+			for _, prefix := range resp.CommonPrefixes {
+				page.Prefixes = append(page.Prefixes, prefix.Prefix)
+			}
+			pages = append(pages, page)
+			if !resp.IsTruncated {
+				return pages
+			}
+			marker = resp.NextMarker
+		}
+	}
+
+	// listV2 - lists all pages of ListObjects V2, continued by
+	// NextContinuationToken.
+	listV2 := func(prefix, delimiter string, maxKeys int) (pages []listObjectsPage) {
+		token := ""
+		for {
+			queryVal := url.Values{}
+			queryVal.Set("list-type", "2")
+			queryVal.Set("prefix", prefix)
+			queryVal.Set("delimiter", delimiter)
+			queryVal.Set("max-keys", strconv.Itoa(maxKeys))
+			if token != "" {
+				queryVal.Set("continuation-token", token)
+			}
+			var resp ListObjectsV2Response
+			if err := xml.Unmarshal(listObjects(queryVal).Body.Bytes(), &resp); err != nil {
+				t.Fatalf("%s: Failed to parse ListObjectsV2 response: <ERROR> %v", instanceType, err)
+			}
+			if resp.ContinuationToken != token {
+				t.Errorf("%s: Expected ContinuationToken `%s`, but found `%s`", instanceType, token, resp.ContinuationToken)
+			}
+			var page listObjectsPage
+			for _, content := range resp.Contents {
+				page.Keys = append(page.Keys, content.Key)
+				if content.Owner != nil {
+					t.Errorf("%s: Expected ListObjectsV2 not to return the owner of %s without fetch-owner", instanceType, content.Key)
+				}
+			}
+			for _, prefix := range resp.CommonPrefixes {
+				page.Prefixes = append(page.Prefixes, prefix.Prefix)
+			}
+			if resp.KeyCount != len(page.Keys)+len(page.Prefixes) {
+				t.Errorf("%s: Expected KeyCount %d, but found %d", instanceType, len(page.Keys)+len(page.Prefixes), resp.KeyCount)
+			}
+			pages = append(pages, page)
+			if !resp.IsTruncated {
+				if resp.NextContinuationToken != "" {
+					t.Errorf("%s: Expected no NextContinuationToken for the last page, but found `%s`", instanceType, resp.NextContinuationToken)
+				}
+				return pages
+			}
+			token = resp.NextContinuationToken
+		}
+	}
+
+	testCases := []struct {
+		prefix    string
+		delimiter string
+		maxKeys   int
+	}{
+		// Test case - 1.
+		// Recursive listing of all objects.
+		{"", "", 3},
+		// Test case - 2.
+		// Objects and common prefixes share max-keys.
+		{"", "/", 2},
+		// Test case - 3.
+		// Listing of a prefix.
+		{"c/", "/", 1},
+		// Test case - 4.
+		// Single page.
+		{"", "/", 1000},
+	}
+	for i, testCase := range testCases {
+		v1Pages := listV1(testCase.prefix, testCase.delimiter, testCase.maxKeys)
+		v2Pages := listV2(testCase.prefix, testCase.delimiter, testCase.maxKeys)
+		if !reflect.DeepEqual(v1Pages, v2Pages) {
+			t.Errorf("Test %d: %s: Expected the same pages for ListObjects V1 and V2, but found %v and %v", i+1, instanceType, v1Pages, v2Pages)
+		}
+	}
+
+	// start-after skips keys, continuation-token takes precedence over it.
+	queryVal := url.Values{}
+	queryVal.Set("list-type", "2")
+	queryVal.Set("start-after", "d")
+	queryVal.Set("fetch-owner", "true")
+	var resp ListObjectsV2Response
+	if err := xml.Unmarshal(listObjects(queryVal).Body.Bytes(), &resp); err != nil {
+		t.Fatalf("%s: Failed to parse ListObjectsV2 response: <ERROR> %v", instanceType, err)
+	}
+	if len(resp.Contents) != 2 || resp.Contents[0].Key != "e" || resp.Contents[1].Key != "f/1" {
+		t.Errorf("%s: Expected the keys after `d`, but found %v", instanceType, resp.Contents)
+	}
+	if resp.StartAfter != "d" {
+		t.Errorf("%s: Expected StartAfter `d`, but found `%s`", instanceType, resp.StartAfter)
+	}
+	for _, content := range resp.Contents {
+		if content.Owner == nil || content.Owner.ID != "minio" {
+			t.Errorf("%s: Expected ListObjectsV2 to return the owner of %s with fetch-owner", instanceType, content.Key)
+		}
+	}
+	queryVal.Set("continuation-token", encodeContinuationToken("e"))
+	resp = ListObjectsV2Response{}
+	if err := xml.Unmarshal(listObjects(queryVal).Body.Bytes(), &resp); err != nil {
+		t.Fatalf("%s: Failed to parse ListObjectsV2 response: <ERROR> %v", instanceType, err)
+	}
+	if len(resp.Contents) != 1 || resp.Contents[0].Key != "f/1" {
+		t.Errorf("%s: Expected the keys after `e`, but found %v", instanceType, resp.Contents)
+	}
+
+	// Invalid continuation tokens are rejected.
+	queryVal.Set("continuation-token", "!invalid")
+	req, err := newTestSignedRequestV4("GET", "/"+bucketName+"?"+queryVal.Encode(), 0, nil, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request for ListObjects: <ERROR> %v", instanceType, err)
+	}
+	rec := httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+	}
+}
@@ -100,22 +100,23 @@ func (api objectAPIHandlers) GetBucketLocationHandler(w http.ResponseWriter, r *
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, bucket, "s3:GetBucketLocation", "us-east-1"); s3Error != ErrNone {
+	if s3Error := checkRequestAuthType(r, bucket, "s3:GetBucketLocation", getBucketLocationAuthRegion(r)); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
 
-	if _, err := objectAPI.GetBucketInfo(bucket); err != nil {
-		errorIf(err, "Unable to fetch bucket info.")
+	if _, err := globalBucketCache.getBucketInfo(objectAPI, bucket); err != nil {
+		requestErrorIf(r, err, "Unable to fetch bucket info.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
 
-	// Generate response.
+	// Generate response, us-east-1 is returned as an empty location
+	// constraint just like S3 does.
 	encodedSuccessResponse := encodeResponse(LocationResponse{})
 	// Get current region.
 	region := serverConfig.GetRegion()
-	if region != "us-east-1" {
+	if !isValidRegion(region, "us-east-1") {
 		encodedSuccessResponse = encodeResponse(LocationResponse{
 			Location: region,
 		})
@@ -125,6 +126,29 @@ func (api objectAPIHandlers) GetBucketLocationHandler(w http.ResponseWriter, r *
 	writeSuccessResponseXML(w, encodedSuccessResponse)
 }
 
+// getBucketLocationAuthRegion - returns the region to validate the
+// signature of GetBucketLocation with. Clients which do not know the
+// region of the bucket yet sign for us-east-1, clients which already
+// know it sign for the server region, both are accepted.
+func getBucketLocationAuthRegion(r *http.Request) string {
+	var reqRegion string
+	switch getRequestAuthType(r) {
+	case authTypeSigned:
+		if signV4Values, s3Error := parseSignV4(r.Header.Get("Authorization")); s3Error == ErrNone {
+			reqRegion = signV4Values.Credential.scope.region
+		}
+	case authTypePresigned:
+		if preSignValues, s3Error := parsePreSignV4(r.URL.Query()); s3Error == ErrNone {
+			reqRegion = preSignValues.Credential.scope.region
+		}
+	}
+	region := serverConfig.GetRegion()
+	if isValidRegion(reqRegion, region) {
+		return region
+	}
+	return "us-east-1"
+}
+
 // ListMultipartUploadsHandler - GET Bucket (List Multipart uploads)
 // -------------------------
 // This operation lists in-progress multipart uploads. An in-progress
@@ -148,11 +172,15 @@ func (api objectAPIHandlers) ListMultipartUploadsHandler(w http.ResponseWriter,
 		return
 	}
 
-	prefix, keyMarker, uploadIDMarker, delimiter, maxUploads, _ := getBucketMultipartResources(r.URL.Query())
+	prefix, keyMarker, uploadIDMarker, delimiter, maxUploads, encodingType := getBucketMultipartResources(r.URL.Query())
 	if maxUploads < 0 {
 		writeErrorResponse(w, ErrInvalidMaxUploads, r.URL)
 		return
 	}
+	if encodingType != "" && encodingType != urlEncodingType {
+		writeErrorResponse(w, ErrInvalidEncodingMethod, r.URL)
+		return
+	}
 	if keyMarker != "" {
 		// Marker not common with prefix is not implemented.
 		if !strings.HasPrefix(keyMarker, prefix) {
@@ -163,10 +191,12 @@ func (api objectAPIHandlers) ListMultipartUploadsHandler(w http.ResponseWriter,
 
 	listMultipartsInfo, err := objectAPI.ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter, maxUploads)
 	if err != nil {
-		errorIf(err, "Unable to list multipart uploads.")
+		requestErrorIf(r, err, "Unable to list multipart uploads.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
+	listMultipartsInfo.EncodingType = encodingType
+
 	// generate response
 	response := generateListMultipartUploadsResponse(bucket, listMultipartsInfo)
 	encodedSuccessResponse := encodeResponse(response)
@@ -199,7 +229,7 @@ func (api objectAPIHandlers) ListBucketsHandler(w http.ResponseWriter, r *http.R
 	// Invoke the list buckets.
 	bucketsInfo, err := objectAPI.ListBuckets()
 	if err != nil {
-		errorIf(err, "Unable to list buckets.")
+		requestErrorIf(r, err, "Unable to list buckets.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -212,6 +242,9 @@ func (api objectAPIHandlers) ListBucketsHandler(w http.ResponseWriter, r *http.R
 	writeSuccessResponseXML(w, encodedSuccessResponse)
 }
 
+// Maximum number of objects deleted concurrently by a DeleteMultipleObjects request.
+const deleteObjectsConcurrency = 64
+
 // DeleteMultipleObjectsHandler - deletes multiple objects.
 func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -247,7 +280,7 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 
 	// Read incoming body XML bytes.
 	if _, err := io.ReadFull(r.Body, deleteXMLBytes); err != nil {
-		errorIf(err, "Unable to read HTTP body.")
+		requestErrorIf(r, err, "Unable to read HTTP body.")
 		writeErrorResponse(w, ErrInternalError, r.URL)
 		return
 	}
@@ -255,23 +288,48 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 	// Unmarshal list of keys to be deleted.
 	deleteObjects := &DeleteObjectsRequest{}
 	if err := xml.Unmarshal(deleteXMLBytes, deleteObjects); err != nil {
-		errorIf(err, "Unable to unmarshal delete objects request XML.")
+		requestErrorIf(r, err, "Unable to unmarshal delete objects request XML.")
+		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		return
+	}
+	// As in S3 between 1 and 1000 objects are deleted at once.
+	if len(deleteObjects.Objects) == 0 || len(deleteObjects.Objects) > maxDeleteList {
 		writeErrorResponse(w, ErrMalformedXML, r.URL)
 		return
 	}
 
 	var wg = &sync.WaitGroup{} // Allocate a new wait group.
 	var dErrs = make([]error, len(deleteObjects.Objects))
+	var deleteSlots = make(chan struct{}, deleteObjectsConcurrency)
 
-	// Delete all requested objects in parallel.
+	// Delete all requested objects in parallel, at most
+	// deleteObjectsConcurrency at a time.
 	for index, object := range deleteObjects.Objects {
 		wg.Add(1)
 		go func(i int, obj ObjectIdentifier) {
 			defer wg.Done()
+			deleteSlots <- struct{}{}
+			defer func() { <-deleteSlots }()
+
+			objectLock := globalNSMutex.NewNSLock(bucket, obj.ObjectName)
+			if dErr := objectLock.GetLock(globalLockTimeout); dErr != nil {
+				dErrs[i] = dErr
+				return
+			}
+			defer objectLock.Unlock()
+
+			// Retained objects cannot be deleted.
+			if dErr := checkObjectRetention(objectAPI, bucket, obj.ObjectName); dErr != nil {
+				dErrs[i] = dErr
+				return
+			}
+			size := getTrackedObjectSize(objectAPI, bucket, obj.ObjectName)
 			dErr := objectAPI.DeleteObject(bucket, obj.ObjectName)
 			if dErr != nil {
 				dErrs[i] = dErr
+				return
 			}
+			globalBucketUsage.add(bucket, -size)
 		}(index, object)
 	}
 	wg.Wait()
@@ -292,7 +350,7 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 			deletedObjects = append(deletedObjects, object)
 			continue
 		}
-		errorIf(err, "Unable to delete object. %s", object.ObjectName)
+		requestErrorIf(r, err, "Unable to delete object. %s", object.ObjectName)
 		// Error during delete should be collected separately.
 		deleteErrors = append(deleteErrors, DeleteError{
 			Code:    errorCodeResponse[toAPIErrorCode(err)].Code,
@@ -349,14 +407,17 @@ func (api objectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	bucketLock := globalNSMutex.NewNSLock(bucket, "")
-	bucketLock.Lock()
-	defer bucketLock.Unlock()
-
 	// Proceed to creating a bucket.
-	err := objectAPI.MakeBucket(bucket)
+	err := makeBucket(bucket, objectAPI)
 	if err != nil {
-		errorIf(err, "Unable to create a bucket.")
+		requestErrorIf(r, err, "Unable to create a bucket.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Apply server configured bucket template, if any.
+	if err = applyBucketTemplate(bucket, objectAPI, getRequestID(r)); err != nil {
+		requestErrorIf(r, err, "Unable to apply bucket template to %s.", bucket)
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -382,14 +443,14 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 	// be loaded in memory, the remaining being put in temporary files.
 	reader, err := r.MultipartReader()
 	if err != nil {
-		errorIf(err, "Unable to initialize multipart reader.")
+		requestErrorIf(r, err, "Unable to initialize multipart reader.")
 		writeErrorResponse(w, ErrMalformedPOSTRequest, r.URL)
 		return
 	}
 
 	fileBody, fileName, formValues, err := extractPostPolicyFormValues(reader)
 	if err != nil {
-		errorIf(err, "Unable to parse form values.")
+		requestErrorIf(r, err, "Unable to parse form values.")
 		writeErrorResponse(w, ErrMalformedPOSTRequest, r.URL)
 		return
 	}
@@ -409,6 +470,7 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 		writeErrorResponse(w, apiErr, r.URL)
 		return
 	}
+	setRequestTenant(r, serverConfig.GetCredential().AccessKey)
 
 	policyBytes, err := base64.StdEncoding.DecodeString(formValues["Policy"])
 	if err != nil {
@@ -448,19 +510,43 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 
 	// Extract metadata to be saved from received Form.
 	metadata := extractMetadataFromForm(formValues)
+	if isMaxUserMetadataSize(metadata) {
+		writeErrorResponse(w, ErrMetadataTooLarge, r.URL)
+		return
+	}
+	setObjectRetention(bucket, metadata)
 
 	sha256sum := ""
 
 	objectLock := globalNSMutex.NewNSLock(bucket, object)
-	objectLock.Lock()
+	if err = objectLock.GetLock(globalLockTimeout); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
 	defer objectLock.Unlock()
 
+	// Retained objects cannot be overwritten.
+	if err = checkObjectRetention(objectAPI, bucket, object); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Size of the object is not known upfront, uploads to full buckets
+	// are rejected.
+	quota, err := checkBucketQuota(objectAPI, bucket, object, -1)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
 	objInfo, err := objectAPI.PutObject(bucket, object, -1, fileBody, metadata, sha256sum)
 	if err != nil {
-		errorIf(err, "Unable to create object.")
+		quota.cancel()
+		requestErrorIf(r, err, "Unable to create object.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
+	quota.commit(objInfo.Size)
 	w.Header().Set("ETag", "\""+objInfo.MD5Sum+"\"")
 	w.Header().Set("Location", getObjectLocation(bucket, object))
 
@@ -529,11 +615,14 @@ func (api objectAPIHandlers) HeadBucketHandler(w http.ResponseWriter, r *http.Re
 	}
 
 	bucketLock := globalNSMutex.NewNSLock(bucket, "")
-	bucketLock.RLock()
+	if err := bucketLock.GetRLock(globalLockTimeout); err != nil {
+		writeErrorResponseHeadersOnly(w, toAPIErrorCode(err))
+		return
+	}
 	defer bucketLock.RUnlock()
 
-	if _, err := objectAPI.GetBucketInfo(bucket); err != nil {
-		errorIf(err, "Unable to fetch bucket info.")
+	if _, err := globalBucketCache.getBucketInfo(objectAPI, bucket); err != nil {
+		requestErrorIf(r, err, "Unable to fetch bucket info.")
 		writeErrorResponseHeadersOnly(w, toAPIErrorCode(err))
 		return
 	}
@@ -541,6 +630,59 @@ func (api objectAPIHandlers) HeadBucketHandler(w http.ResponseWriter, r *http.Re
 	writeSuccessResponseHeadersOnly(w)
 }
 
+// makeBucket - creates a bucket holding the bucket lock, such that
+// concurrent creates and deletes of the same bucket are serialized
+// across all the servers. The loser of a race fails with BucketExists.
+func makeBucket(bucket string, objAPI ObjectLayer) error {
+	bucketLock := globalNSMutex.NewNSLock(bucket, "")
+	if err := bucketLock.GetLock(globalLockTimeout); err != nil {
+		return err
+	}
+	defer bucketLock.Unlock()
+
+	if err := objAPI.MakeBucket(bucket); err != nil {
+		return err
+	}
+
+	// Forget any earlier bucket of the same name.
+	invalidateBucketCache(bucket)
+	return nil
+}
+
+// deleteBucket - deletes a bucket along with its metadata holding the
+// bucket lock, such that concurrent creates and deletes of the same
+// bucket are serialized across all the servers. The loser of a race
+// fails with BucketNotFound.
+func deleteBucket(bucket string, objAPI ObjectLayer) error {
+	bucketLock := globalNSMutex.NewNSLock(bucket, "")
+	if err := bucketLock.GetLock(globalLockTimeout); err != nil {
+		return err
+	}
+	defer bucketLock.Unlock()
+
+	if err := objAPI.DeleteBucket(bucket); err != nil {
+		return err
+	}
+
+	// Stop serving the bucket from the cache of all the servers.
+	invalidateBucketCache(bucket)
+
+	// Delete bucket access policy, if present - ignore any errors.
+	_ = removeBucketPolicy(bucket, objAPI)
+
+	// Delete notification config, if present - ignore any errors.
+	_ = removeNotificationConfig(bucket, objAPI)
+
+	// Delete listener config, if present - ignore any errors.
+	_ = removeListenerConfig(bucket, objAPI)
+
+	// Bucket is empty, forget its usage. Its quota, if any, applies
+	// to a new bucket of the same name.
+	globalBucketUsage.reset(bucket)
+
+	return nil
+}
+
 // DeleteBucketHandler - Delete bucket
 func (api objectAPIHandlers) DeleteBucketHandler(w http.ResponseWriter, r *http.Request) {
 	objectAPI := api.ObjectAPI()
@@ -558,26 +700,13 @@ func (api objectAPIHandlers) DeleteBucketHandler(w http.ResponseWriter, r *http.
 	vars := mux.Vars(r)
 	bucket := vars["bucket"]
 
-	bucketLock := globalNSMutex.NewNSLock(bucket, "")
-	bucketLock.Lock()
-	defer bucketLock.Unlock()
-
 	// Attempt to delete bucket.
-	if err := objectAPI.DeleteBucket(bucket); err != nil {
-		errorIf(err, "Unable to delete a bucket.")
+	if err := deleteBucket(bucket, objectAPI); err != nil {
+		requestErrorIf(r, err, "Unable to delete a bucket.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
 
-	// Delete bucket access policy, if present - ignore any errors.
-	_ = removeBucketPolicy(bucket, objectAPI)
-
-	// Delete notification config, if present - ignore any errors.
-	_ = removeNotificationConfig(bucket, objectAPI)
-
-	// Delete listener config, if present - ignore any errors.
-	_ = removeListenerConfig(bucket, objectAPI)
-
 	// Write success response.
 	writeSuccessNoContent(w)
 }
@@ -22,7 +22,9 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strconv"
+	"sync"
 	"testing"
 )
 
@@ -31,6 +33,76 @@ func TestGetBucketLocationHandler(t *testing.T) {
 	ExecObjectLayerAPITest(t, testGetBucketLocationHandler, []string{"GetBucketLocation"})
 }
 
+// Tests GetBucketLocation returns the server region, accepting requests
+// signed for us-east-1 as well as for the server region, and the
+// returned region can be used to sign follow-up requests.
+func TestGetBucketLocationRegion(t *testing.T) {
+	ExecObjectLayerAPITest(t, testGetBucketLocationRegion, []string{"GetBucketLocation", "ListObjectsV1"})
+}
+
+func testGetBucketLocationRegion(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	defer serverConfig.SetRegion(serverConfig.GetRegion())
+
+	// signedRequest - returns a request signed for the given region.
+	signedRequest := func(urlStr, region string) *http.Request {
+		confRegion := serverConfig.GetRegion()
+		defer serverConfig.SetRegion(confRegion)
+		serverConfig.SetRegion(region)
+		req, err := newTestSignedRequestV4("GET", urlStr, 0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+		}
+		return req
+	}
+
+	testCases := []struct {
+		confRegion     string
+		signRegion     string
+		expectedStatus int
+		expectedRegion string
+	}{
+		// us-east-1 is returned as an empty location constraint.
+		{"us-east-1", "us-east-1", http.StatusOK, ""},
+		{"", "us-east-1", http.StatusOK, ""},
+		// Clients which do not know the region yet sign for us-east-1.
+		{"us-west-2", "us-east-1", http.StatusOK, "us-west-2"},
+		// Clients which know the region sign for it.
+		{"us-west-2", "us-west-2", http.StatusOK, "us-west-2"},
+		// Any other region is rejected.
+		{"us-west-2", "eu-west-1", http.StatusBadRequest, ""},
+	}
+	for i, testCase := range testCases {
+		serverConfig.SetRegion(testCase.confRegion)
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, signedRequest(getBucketLocationURL("", bucketName), testCase.signRegion))
+		if rec.Code != testCase.expectedStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedStatus, rec.Code)
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+		locationResponse := LocationResponse{}
+		if err := xml.Unmarshal(rec.Body.Bytes(), &locationResponse); err != nil {
+			t.Fatalf("Test %d: %s: Unable to parse location response: <ERROR> %v", i+1, instanceType, err)
+		}
+		if locationResponse.Location != testCase.expectedRegion {
+			t.Fatalf("Test %d: %s: Expected region %q, got %q", i+1, instanceType, testCase.expectedRegion, locationResponse.Location)
+		}
+
+		// Follow-up request signed for the returned region succeeds.
+		region := locationResponse.Location
+		if region == "" {
+			region = "us-east-1"
+		}
+		rec = httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, signedRequest(getListObjectsV1URL("", bucketName, "1000"), region))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Test %d: %s: Expected request signed for %s to succeed, got `%d`", i+1, instanceType, region, rec.Code)
+		}
+	}
+}
+
 func testGetBucketLocationHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
 	credentials credential, t *testing.T) {
 	initBucketPolicies(obj)
@@ -516,6 +588,116 @@ func testListMultipartUploadsHandler(obj ObjectLayer, instanceType, bucketName s
 	ExecObjectLayerAPINilTest(t, nilBucket, "", instanceType, apiRouter, nilReq)
 }
 
+// Wrapper for calling ListMultipartUploads and ListParts pagination
+// tests for both XL multiple disks and single node setup.
+func TestListMultipartUploadsPagination(t *testing.T) {
+	ExecObjectLayerAPITest(t, testListMultipartUploadsPagination, []string{"ListMultipartUploads", "ListObjectParts"})
+}
+
+// Tests incomplete uploads are enumerated page by page with key-marker,
+// upload-id-marker and max-uploads, and their parts with
+// part-number-marker and max-parts.
+func testListMultipartUploadsPagination(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	type upload struct {
+		key, uploadID string
+	}
+	var uploads []upload
+	for _, object := range []string{"a", "a", "b", "c/d"} {
+		uploadID, err := obj.NewMultipartUpload(bucketName, object, nil)
+		if err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+		uploads = append(uploads, upload{object, uploadID})
+	}
+	for partID := 1; partID <= 2; partID++ {
+		if _, err := obj.PutObjectPart(bucketName, "b", uploads[2].uploadID, partID, 4, bytes.NewReader([]byte("abcd")), "", ""); err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+	}
+
+	// List one upload at a time.
+	var listed []upload
+	keyMarker, uploadIDMarker := "", ""
+	for i := 0; i <= len(uploads); i++ {
+		rec := httptest.NewRecorder()
+		u := getListMultipartUploadsURLWithParams("", bucketName, "", keyMarker, uploadIDMarker, "", "1")
+		req, err := newTestSignedRequestV4("GET", u, 0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for ListMultipartUploadsHandler: <ERROR> %v", instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+		}
+		var response ListMultipartUploadsResponse
+		if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%s: Failed to parse ListMultipartUploads response: <ERROR> %v", instanceType, err)
+		}
+		if len(response.Uploads) > 1 {
+			t.Fatalf("%s: Expected at most 1 upload per page, got %d", instanceType, len(response.Uploads))
+		}
+		for _, u := range response.Uploads {
+			if u.StorageClass != "STANDARD" || u.Owner.ID == "" || u.Initiator.ID == "" {
+				t.Errorf("%s: Expected storage class, owner and initiator of upload %s, got %v", instanceType, u.UploadID, u)
+			}
+			listed = append(listed, upload{u.Key, u.UploadID})
+		}
+		if !response.IsTruncated {
+			break
+		}
+		keyMarker, uploadIDMarker = response.NextKeyMarker, response.NextUploadIDMarker
+	}
+	if len(listed) != len(uploads) {
+		t.Fatalf("%s: Expected %d uploads, got %v", instanceType, len(uploads), listed)
+	}
+	seen := make(map[upload]bool)
+	for i, u := range listed {
+		if seen[u] {
+			t.Errorf("%s: Upload %v listed twice", instanceType, u)
+		}
+		seen[u] = true
+		if u.key != uploads[i].key {
+			t.Errorf("%s: Expected upload %d of %s, got %s", instanceType, i+1, uploads[i].key, u.key)
+		}
+	}
+	for _, u := range uploads {
+		if !seen[u] {
+			t.Errorf("%s: Upload %v not listed", instanceType, u)
+		}
+	}
+
+	// List one part at a time.
+	var partNumbers []int
+	partNumberMarker := ""
+	for i := 0; i <= 2; i++ {
+		rec := httptest.NewRecorder()
+		u := getListMultipartURLWithParams("", bucketName, "b", uploads[2].uploadID, "1", partNumberMarker, "")
+		req, err := newTestSignedRequestV4("GET", u, 0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for ListObjectPartsHandler: <ERROR> %v", instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+		}
+		var response ListPartsResponse
+		if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%s: Failed to parse ListParts response: <ERROR> %v", instanceType, err)
+		}
+		for _, part := range response.Parts {
+			partNumbers = append(partNumbers, part.PartNumber)
+		}
+		if !response.IsTruncated {
+			break
+		}
+		partNumberMarker = strconv.Itoa(response.NextPartNumberMarker)
+	}
+	if !reflect.DeepEqual(partNumbers, []int{1, 2}) {
+		t.Errorf("%s: Expected parts [1 2], got %v", instanceType, partNumbers)
+	}
+}
+
 // Wrapper for calling TestListBucketsHandler tests for both XL multiple disks and single node setup.
 func TestListBucketsHandler(t *testing.T) {
 	ExecObjectLayerAPITest(t, testListBucketsHandler, []string{"ListBuckets"})
@@ -753,6 +935,68 @@ func testAPIDeleteMultipleObjectsHandler(obj ObjectLayer, instanceType, bucketNa
 		}
 	}
 
+	// deleteObjects - sends a DeleteMultipleObjects request, returns the recorded response.
+	deleteObjects := func(request DeleteObjectsRequest) *httptest.ResponseRecorder {
+		body := encodeResponse(request)
+		req, rErr := newTestSignedRequestV4("POST", getDeleteMultipleObjectsURL("", bucketName),
+			int64(len(body)), bytes.NewReader(body), credentials.AccessKey, credentials.SecretKey)
+		if rErr != nil {
+			t.Fatalf("Failed to create HTTP request for DeleteMultipleObjects: <ERROR> %v", rErr)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Existing, missing and invalid keys are reported each.
+	for _, objectName := range []string{"mixed-object-0", "mixed-object-1"} {
+		_, err = obj.PutObject(bucketName, objectName, int64(len(contentBytes)), bytes.NewBuffer(contentBytes),
+			make(map[string]string), sha256sum)
+		if err != nil {
+			t.Fatalf("Minio %s: Error uploading object: <ERROR> %v", instanceType, err)
+		}
+	}
+	mixedObjects := getObjectIdentifierList([]string{"mixed-object-0", "missing-object", "/invalid-object", "mixed-object-1"})
+	for _, quiet := range []bool{false, true} {
+		rec := deleteObjects(DeleteObjectsRequest{Quiet: quiet, Objects: mixedObjects})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Minio %s: Expected DeleteMultipleObjects to succeed, got %d", instanceType, rec.Code)
+		}
+		var response DeleteObjectsResponse
+		if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Minio %s: Failed to parse DeleteMultipleObjects response: <ERROR> %v", instanceType, err)
+		}
+		// As in S3 missing objects are reported as deleted, deleted
+		// objects are only reported if not quiet.
+		var expectedDeleted []ObjectIdentifier
+		if !quiet {
+			expectedDeleted = getObjectIdentifierList([]string{"mixed-object-0", "missing-object", "mixed-object-1"})
+		}
+		if !reflect.DeepEqual(response.DeletedObjects, expectedDeleted) {
+			t.Errorf("Minio %s: Quiet %t: Expected deleted objects %v, got %v", instanceType, quiet, expectedDeleted, response.DeletedObjects)
+		}
+		if len(response.Errors) != 1 || response.Errors[0].Key != "/invalid-object" || response.Errors[0].Code == "" {
+			t.Errorf("Minio %s: Quiet %t: Expected an error for the invalid object only, got %v", instanceType, quiet, response.Errors)
+		}
+	}
+	for _, objectName := range []string{"mixed-object-0", "mixed-object-1"} {
+		if _, err = obj.GetObjectInfo(bucketName, objectName); err == nil {
+			t.Errorf("Minio %s: Expected %s to be deleted", instanceType, objectName)
+		}
+	}
+
+	// At most 1000 objects are deleted at once.
+	var tooManyObjects []string
+	for i := 0; i <= maxDeleteList; i++ {
+		tooManyObjects = append(tooManyObjects, "object-"+strconv.Itoa(i))
+	}
+	if rec := deleteObjects(DeleteObjectsRequest{Objects: getObjectIdentifierList(tooManyObjects)}); rec.Code != http.StatusBadRequest {
+		t.Errorf("Minio %s: Expected deleting %d objects to fail with %d, got %d", instanceType, len(tooManyObjects), http.StatusBadRequest, rec.Code)
+	}
+	if rec := deleteObjects(DeleteObjectsRequest{}); rec.Code != http.StatusBadRequest {
+		t.Errorf("Minio %s: Expected deleting no objects to fail with %d, got %d", instanceType, http.StatusBadRequest, rec.Code)
+	}
+
 	// Currently anonymous user cannot delete multiple objects in Minio server, hence no test case is required.
 
 	// HTTP request to test the case of `objectLayer` being set to `nil`.
@@ -771,3 +1015,73 @@ func testAPIDeleteMultipleObjectsHandler(obj ObjectLayer, instanceType, bucketNa
 	// `ExecObjectLayerAPINilTest` manages the operation.
 	ExecObjectLayerAPINilTest(t, nilBucket, nilObject, instanceType, apiRouter, nilReq)
 }
+
+// Tests concurrent creates and deletes of the same bucket are
+// serialized, leaving the bucket either on all the disks or on none.
+func TestConcurrentMakeDeleteBucket(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	bucket := "racing-bucket"
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var created, deleted int
+	errs := make(chan error, 80)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				if (i+j)%2 == 0 {
+					err := makeBucket(bucket, obj)
+					if err == nil {
+						mu.Lock()
+						created++
+						mu.Unlock()
+					} else if _, ok := errorCause(err).(BucketExists); !ok {
+						errs <- err
+					}
+					continue
+				}
+				err := deleteBucket(bucket, obj)
+				if err == nil {
+					mu.Lock()
+					deleted++
+					mu.Unlock()
+				} else if _, ok := errorCause(err).(BucketNotFound); !ok {
+					errs <- err
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err = range errs {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	// Every successful delete follows a successful create.
+	exists := created - deleted
+	if exists != 0 && exists != 1 {
+		t.Fatalf("Expected at most one outstanding create, got %d creates and %d deletes", created, deleted)
+	}
+	for i, disk := range xl.storageDisks {
+		_, err = disk.StatVol(bucket)
+		if exists == 1 && err != nil {
+			t.Errorf("Disk %d: expected bucket to exist, got %s", i, err)
+		}
+		if exists == 0 && err != errVolumeNotFound {
+			t.Errorf("Disk %d: expected bucket to be absent, got %v", i, err)
+		}
+	}
+}
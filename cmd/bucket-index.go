@@ -0,0 +1,162 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sort"
+	"strings"
+)
+
+// BucketIndexReport - discrepancies found while rebuilding the list of
+// buckets from the disks.
+type BucketIndexReport struct {
+	// Buckets present on a read quorum of disks, listed by ListBuckets.
+	Buckets []string `json:"buckets"`
+
+	// Buckets which were missing on some of the disks and were
+	// healed back onto them.
+	HealedBuckets []string `json:"healedBuckets"`
+
+	// Buckets present only on less than a read quorum of disks,
+	// left behind by partial disk loss. These are not listed and are
+	// left untouched for manual recovery.
+	OrphanBuckets []string `json:"orphanBuckets"`
+
+	// Buckets whose metadata (policy, notification configuration)
+	// was present without the bucket, such metadata is removed.
+	OrphanMetadata []string `json:"orphanMetadata"`
+}
+
+// listBucketMetadataNames - lists names of all the buckets which have
+// metadata on any of the disks.
+func listBucketMetadataNames(storageDisks []StorageAPI) (map[string]struct{}, error) {
+	bucketNames := make(map[string]struct{})
+	for _, disk := range storageDisks {
+		if disk == nil {
+			continue
+		}
+		entries, err := disk.ListDir(minioMetaBucket, bucketMetaPrefix)
+		if err != nil {
+			if err == errFileNotFound || isErrIgnored(err, bucketMetadataOpIgnoredErrs...) {
+				continue
+			}
+			return nil, err
+		}
+		for _, entry := range entries {
+			if !strings.HasSuffix(entry, slashSeparator) {
+				continue
+			}
+			bucketNames[strings.TrimSuffix(entry, slashSeparator)] = struct{}{}
+		}
+	}
+	return bucketNames, nil
+}
+
+// statBucketOnDisks - returns number of disks the bucket is present
+// on and number of online disks it is missing on.
+func statBucketOnDisks(storageDisks []StorageAPI, bucket string) (present, missing int) {
+	for _, disk := range storageDisks {
+		if disk == nil {
+			continue
+		}
+		_, err := disk.StatVol(bucket)
+		switch err {
+		case nil:
+			present++
+		case errVolumeNotFound:
+			missing++
+		}
+	}
+	return present, missing
+}
+
+// removeBucketMetadata - removes metadata of a bucket from all the
+// disks and notifies all the servers to drop it from memory.
+func removeBucketMetadata(storageDisks []StorageAPI, bucket string) error {
+	for _, disk := range storageDisks {
+		if disk == nil {
+			continue
+		}
+		err := cleanupDir(disk, minioMetaBucket, pathJoin(bucketMetaPrefix, bucket))
+		if err != nil && !isErrIgnored(err, bucketMetadataOpIgnoredErrs...) {
+			return err
+		}
+	}
+	S3PeersUpdateBucketPolicy(bucket, policyChange{true, nil}, "")
+	S3PeersUpdateBucketNotification(bucket, nil, "")
+	S3PeersUpdateBucketListener(bucket, []listenerConfig{}, "")
+	return nil
+}
+
+// rebuildBucketIndex - scans all the disks for buckets and bucket
+// metadata and reconciles them. Buckets on a read quorum of disks are
+// healed onto the disks missing them, metadata without a bucket is
+// removed. Each bucket is locked while it is reconciled such that it
+// is safe on a live server.
+func rebuildBucketIndex(storageDisks []StorageAPI, readQuorum, writeQuorum int) (report BucketIndexReport, err error) {
+	bucketNames, err := listBucketNames(storageDisks)
+	if err != nil {
+		return report, err
+	}
+	metaBucketNames, err := listBucketMetadataNames(storageDisks)
+	if err != nil {
+		return report, err
+	}
+	for bucket := range metaBucketNames {
+		bucketNames[bucket] = struct{}{}
+	}
+
+	var names []string
+	for bucket := range bucketNames {
+		names = append(names, bucket)
+	}
+	sort.Strings(names)
+
+	for _, bucket := range names {
+		bucketLock := globalNSMutex.NewNSLock(bucket, "")
+		bucketLock.Lock()
+		present, missing := statBucketOnDisks(storageDisks, bucket)
+		if present == 0 {
+			err = removeBucketMetadata(storageDisks, bucket)
+		}
+		bucketLock.Unlock()
+		if err != nil {
+			return report, err
+		}
+
+		switch {
+		case present >= readQuorum:
+			report.Buckets = append(report.Buckets, bucket)
+			if missing == 0 {
+				continue
+			}
+			// healBucket locks the bucket by itself.
+			if err = healBucket(storageDisks, bucket, writeQuorum); err != nil {
+				return report, err
+			}
+			if err = healBucketMetadata(storageDisks, bucket, readQuorum); err != nil {
+				return report, err
+			}
+			report.HealedBuckets = append(report.HealedBuckets, bucket)
+		case present > 0:
+			report.OrphanBuckets = append(report.OrphanBuckets, bucket)
+		default:
+			report.OrphanMetadata = append(report.OrphanMetadata, bucket)
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,100 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Tests reconciling buckets on the disks with their metadata.
+func TestRebuildBucketIndex(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	// Bucket present everywhere.
+	if err = obj.MakeBucket("healthy"); err != nil {
+		t.Fatal(err)
+	}
+	// Bucket lost on a couple of disks.
+	if err = obj.MakeBucket("lost"); err != nil {
+		t.Fatal(err)
+	}
+	for _, disk := range xl.storageDisks[:2] {
+		if err = disk.DeleteVol("lost"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Bucket left behind on a couple of disks only.
+	for _, disk := range xl.storageDisks[:2] {
+		if err = disk.MakeVol("partial"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Metadata left behind by a deleted bucket.
+	for _, disk := range xl.storageDisks {
+		if err = disk.AppendFile(minioMetaBucket, pathJoin(bucketMetaPrefix, "deleted", policyJSON), []byte("{}")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := obj.RebuildBucketIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := BucketIndexReport{
+		Buckets:        []string{"healthy", "lost"},
+		HealedBuckets:  []string{"lost"},
+		OrphanBuckets:  []string{"partial"},
+		OrphanMetadata: []string{"deleted"},
+	}
+	if !reflect.DeepEqual(report, expected) {
+		t.Fatalf("Expected %#v, got %#v", expected, report)
+	}
+
+	for i, disk := range xl.storageDisks {
+		if _, err = disk.StatVol("lost"); err != nil {
+			t.Fatalf("Disk %d: expected healed bucket, got %s", i, err)
+		}
+		if _, err = disk.StatFile(minioMetaBucket, pathJoin(bucketMetaPrefix, "deleted", policyJSON)); err != errFileNotFound {
+			t.Fatalf("Disk %d: expected orphan metadata to be removed, got %v", i, err)
+		}
+	}
+
+	// Rebuilding again finds only the partial bucket.
+	report, err = obj.RebuildBucketIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = BucketIndexReport{
+		Buckets:       []string{"healthy", "lost"},
+		OrphanBuckets: []string{"partial"},
+	}
+	if !reflect.DeepEqual(report, expected) {
+		t.Fatalf("Expected %#v, got %#v", expected, report)
+	}
+}
@@ -0,0 +1,137 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	mux "github.com/gorilla/mux"
+)
+
+// lifecycleExpiration - expiration of the objects matching a rule.
+type lifecycleExpiration struct {
+	Days int `xml:"Days"`
+}
+
+// lifecycleConfigurationRule - expiration rule of a lifecycle configuration.
+type lifecycleConfigurationRule struct {
+	Prefix     string              `xml:"Prefix"`
+	Status     string              `xml:"Status"`
+	Expiration lifecycleExpiration `xml:"Expiration"`
+}
+
+// LifecycleConfiguration - format of the response of GetBucketLifecycle.
+type LifecycleConfiguration struct {
+	XMLName xml.Name                     `xml:"http://s3.amazonaws.com/doc/2006-03-01/ LifecycleConfiguration" json:"-"`
+	Rules   []lifecycleConfigurationRule `xml:"Rule"`
+}
+
+// generateLifecycleConfiguration - returns the expiration rules of a
+// bucket as a lifecycle configuration, only enabled rules are stored.
+func generateLifecycleConfiguration(rules []lifecycleRule) LifecycleConfiguration {
+	var config LifecycleConfiguration
+	for _, rule := range rules {
+		config.Rules = append(config.Rules, lifecycleConfigurationRule{
+			Prefix:     rule.Prefix,
+			Status:     "Enabled",
+			Expiration: lifecycleExpiration{Days: rule.Days},
+		})
+	}
+	return config
+}
+
+// GetBucketLifecycleHandler - GET Bucket lifecycle
+// -----------------
+// This operation uses the lifecycle subresource to return the
+// expiration rules of a bucket, set with the SetBucketLifecycle admin API.
+func (api objectAPIHandlers) GetBucketLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	if _, err := objAPI.GetBucketInfo(bucket); err != nil {
+		requestErrorIf(r, err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	rules := serverConfig.GetBucketLifecycles()[bucket]
+	if len(rules) == 0 {
+		writeErrorResponse(w, ErrNoSuchLifecycleConfiguration, r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseXML(w, encodeResponse(generateLifecycleConfiguration(rules)))
+}
+
+// PutBucketLifecycleHandler - PUT Bucket lifecycle
+// -----------------
+// Not implemented, expiration rules are set with the SetBucketLifecycle
+// admin API.
+func (api objectAPIHandlers) PutBucketLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	writeErrorResponse(w, ErrNotImplemented, r.URL)
+}
+
+// DeleteBucketLifecycleHandler - DELETE Bucket lifecycle
+// -----------------
+// This operation uses the lifecycle subresource to remove the
+// expiration rules of a bucket on all the servers in the cluster.
+func (api objectAPIHandlers) DeleteBucketLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	if _, err := objAPI.GetBucketInfo(bucket); err != nil {
+		requestErrorIf(r, err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// No rules removes them.
+	if err := sendSetBucketLifecycleCmd(globalAdminPeers, bucket, nil); err != nil {
+		requestErrorIf(r, err, "Failed to remove bucket lifecycle on remote nodes.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessNoContent(w)
+}
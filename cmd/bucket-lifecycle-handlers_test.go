@@ -0,0 +1,122 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// Tests expiration rules set through the admin API are read and
+// deleted with the S3 bucket lifecycle API.
+func TestBucketLifecycleHandlers(t *testing.T) {
+	ts, stop := startTestServerWithPeers(t, "XL")
+	defer stop()
+
+	bucketName := getRandomBucketName()
+	if err := ts.Obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+
+	do := func(method, url string, body []byte, adminOp string) (int, []byte) {
+		req, err := newTestSignedRequestV4(method, url, int64(len(body)), bytes.NewReader(body), ts.AccessKey, ts.SecretKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if adminOp != "" {
+			req.Header.Set(minioAdminOpHeader, adminOp)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		respBody, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, respBody
+	}
+
+	// getErrorCode - returns the code of an S3 error response.
+	getErrorCode := func(body []byte) string {
+		var apiErr APIErrorResponse
+		if err := xml.Unmarshal(body, &apiErr); err != nil {
+			t.Fatalf("Failed to parse error response: <ERROR> %v", err)
+		}
+		return apiErr.Code
+	}
+
+	lifecycleURL := ts.Server.URL + "/" + bucketName + "?lifecycle"
+
+	// Buckets without rules have no lifecycle configuration.
+	status, body := do("GET", lifecycleURL, nil, "")
+	if status != http.StatusNotFound || getErrorCode(body) != "NoSuchLifecycleConfiguration" {
+		t.Fatalf("Expected NoSuchLifecycleConfiguration, got %d: %s", status, body)
+	}
+	status, body = do("GET", ts.Server.URL+"/missing-bucket?lifecycle", nil, "")
+	if status != http.StatusNotFound || getErrorCode(body) != "NoSuchBucket" {
+		t.Fatalf("Expected NoSuchBucket, got %d: %s", status, body)
+	}
+
+	// Rules set through the admin API are returned.
+	rules := []byte(`[{"prefix": "tmp/", "days": 1}, {"prefix": "logs/", "days": 30}]`)
+	if status, body = do("POST", ts.Server.URL+"/?bucket-lifecycle&bucket="+bucketName, rules, "set"); status != http.StatusOK {
+		t.Fatalf("Expected rules to be set, got %d: %s", status, body)
+	}
+	status, body = do("GET", lifecycleURL, nil, "")
+	if status != http.StatusOK {
+		t.Fatalf("Expected GetBucketLifecycle to succeed, got %d: %s", status, body)
+	}
+	var config LifecycleConfiguration
+	if err := xml.Unmarshal(body, &config); err != nil {
+		t.Fatalf("Failed to parse lifecycle configuration: <ERROR> %v", err)
+	}
+	expectedRules := []lifecycleConfigurationRule{
+		{Prefix: "tmp/", Status: "Enabled", Expiration: lifecycleExpiration{Days: 1}},
+		{Prefix: "logs/", Status: "Enabled", Expiration: lifecycleExpiration{Days: 30}},
+	}
+	if !reflect.DeepEqual(config.Rules, expectedRules) {
+		t.Errorf("Expected rules %v, got %v", expectedRules, config.Rules)
+	}
+
+	// Lifecycle configurations cannot be put with the S3 API.
+	if status, _ = do("PUT", lifecycleURL, body, ""); status != http.StatusNotImplemented {
+		t.Errorf("Expected PutBucketLifecycle not to be implemented, got %d", status)
+	}
+
+	// Rules are deleted from the config of all the servers.
+	if status, body = do("DELETE", lifecycleURL, nil, ""); status != http.StatusNoContent {
+		t.Fatalf("Expected DeleteBucketLifecycle to succeed, got %d: %s", status, body)
+	}
+	if _, ok := serverConfig.GetBucketLifecycles()[bucketName]; ok {
+		t.Fatal("Expected rules to be removed from config")
+	}
+	status, body = do("GET", lifecycleURL, nil, "")
+	if status != http.StatusNotFound || getErrorCode(body) != "NoSuchLifecycleConfiguration" {
+		t.Fatalf("Expected NoSuchLifecycleConfiguration after delete, got %d: %s", status, body)
+	}
+
+	// Deleting missing rules succeeds, as with S3.
+	if status, body = do("DELETE", lifecycleURL, nil, ""); status != http.StatusNoContent {
+		t.Fatalf("Expected DeleteBucketLifecycle without rules to succeed, got %d: %s", status, body)
+	}
+}
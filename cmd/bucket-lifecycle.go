@@ -0,0 +1,179 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// Maximum number of expiration rules of a bucket, same as S3.
+	maxLifecycleRules = 1000
+
+	// Maximum size of the expiration rules sent to the admin API.
+	maxLifecycleConfigSize = 1 << 20 // 1MiB.
+
+	// Number of objects listed at a time while scanning a bucket.
+	lifecycleListBatch = 1000
+)
+
+// lifecycleRule - expires objects whose name starts with Prefix once
+// they have not been modified for Days days.
+type lifecycleRule struct {
+	Prefix string `json:"prefix"`
+	Days   int    `json:"days"`
+}
+
+// isExpired - returns true if objInfo matches the rule and is expired at now.
+func (r lifecycleRule) isExpired(objInfo ObjectInfo, now time.Time) bool {
+	if !strings.HasPrefix(objInfo.Name, r.Prefix) {
+		return false
+	}
+	return !objInfo.ModTime.Add(time.Duration(r.Days) * 24 * time.Hour).After(now)
+}
+
+// bucketLifecycles - expiration rules of each bucket.
+type bucketLifecycles map[string][]lifecycleRule
+
+// validate - verifies expiration rules of all buckets.
+func (l bucketLifecycles) validate() error {
+	for bucket, rules := range l {
+		if !isValidLifecycleRules(rules) {
+			return fmt.Errorf("Invalid lifecycle rules of bucket %s", bucket)
+		}
+	}
+	return nil
+}
+
+// isValidLifecycleRules - returns true if there are at most
+// maxLifecycleRules rules, none of them with negative days.
+func isValidLifecycleRules(rules []lifecycleRule) bool {
+	if len(rules) > maxLifecycleRules {
+		return false
+	}
+	for _, rule := range rules {
+		if rule.Days < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// setBucketLifecycle - saves expiration rules of bucket to the config
+// of this server, no rules removes them.
+func setBucketLifecycle(bucket string, rules []lifecycleRule) error {
+	serverConfig.SetBucketLifecycle(bucket, rules)
+	return serverConfig.Save()
+}
+
+// startLifecycleScanner - deletes expired objects every interval until
+// doneCh is closed. Read-only servers never delete objects, servers of
+// a distributed setup share the buckets, only the server of the first
+// endpoint scans them.
+func startLifecycleScanner(objAPI ObjectLayer, interval time.Duration, doneCh <-chan struct{}) {
+	if globalIsReadOnly {
+		return
+	}
+	if globalIsDistXL && globalLockOwnNode != 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			expireObjects(objAPI, time.Now().UTC())
+		case <-doneCh:
+			return
+		}
+	}
+}
+
+// expireObjects - deletes objects of all buckets expired at now.
+func expireObjects(objAPI ObjectLayer, now time.Time) {
+	for bucket, rules := range serverConfig.GetBucketLifecycles() {
+		err := expireBucketObjects(objAPI, bucket, rules, now)
+		if isErrBucketNotFound(err) {
+			continue
+		}
+		errorIf(err, "Unable to expire objects of bucket %s.", bucket)
+	}
+}
+
+// expireBucketObjects - deletes objects of bucket matching any of the
+// rules and expired at now. Objects retained in WORM mode are skipped.
+func expireBucketObjects(objAPI ObjectLayer, bucket string, rules []lifecycleRule, now time.Time) error {
+	for _, rule := range rules {
+		marker := ""
+		for {
+			result, err := objAPI.ListObjects(bucket, rule.Prefix, marker, "", lifecycleListBatch)
+			if err != nil {
+				return err
+			}
+			for _, objInfo := range result.Objects {
+				if !rule.isExpired(objInfo, now) {
+					continue
+				}
+				err = expireObject(objAPI, bucket, objInfo.Name)
+				if _, ok := errorCause(err).(ObjectRetained); ok {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+			}
+			if !result.IsTruncated {
+				break
+			}
+			marker = result.NextMarker
+		}
+	}
+	return nil
+}
+
+// expireObject - deletes an expired object like a DeleteObject request,
+// unless the object is retained.
+func expireObject(objAPI ObjectLayer, bucket, object string) error {
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	if err := checkObjectRetention(objAPI, bucket, object); err != nil {
+		return err
+	}
+
+	size := getTrackedObjectSize(objAPI, bucket, object)
+	if err := objAPI.DeleteObject(bucket, object); err != nil {
+		if isErrObjectNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	globalBucketUsage.add(bucket, -size)
+
+	// Notify object deleted event.
+	eventNotify(eventData{
+		Type:   ObjectRemovedDelete,
+		Bucket: bucket,
+		ObjInfo: ObjectInfo{
+			Name: object,
+		},
+	})
+	return nil
+}
@@ -0,0 +1,195 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Tests expiration rules set through the admin API delete matching
+// objects on the next scan, except retained ones.
+func TestBucketLifecycle(t *testing.T) {
+	ts, stop := startTestServerWithPeers(t, "XL")
+	defer stop()
+	initNSLock(false)
+
+	bucketName := getRandomBucketName()
+	if err := ts.Obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+
+	do := func(method, url string, body []byte, adminOp string) int {
+		req, err := newTestSignedRequestV4(method, url, int64(len(body)), bytes.NewReader(body), ts.AccessKey, ts.SecretKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if adminOp != "" {
+			req.Header.Set(minioAdminOpHeader, adminOp)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// Rules are set through the admin API.
+	lifecycleURL := ts.Server.URL + "/?bucket-lifecycle&bucket=" + bucketName
+	invalidRules := [][]byte{
+		[]byte(`{"prefix": "tmp/"}`),
+		[]byte(`[{"prefix": "tmp/", "days": -1}]`),
+		[]byte(`[{"prefix": "tmp/", "days": "1"}]`),
+	}
+	for i, body := range invalidRules {
+		if status := do("POST", lifecycleURL, body, "set"); status != http.StatusBadRequest {
+			t.Errorf("Test %d: expected invalid rules to be rejected, got %d", i+1, status)
+		}
+	}
+	rules := []byte(`[{"prefix": "tmp/", "days": 0}, {"prefix": "logs/", "days": 30}]`)
+	if status := do("POST", ts.Server.URL+"/?bucket-lifecycle&bucket=missing-bucket", rules, "set"); status != http.StatusNotFound {
+		t.Fatalf("Expected rules of missing bucket to be rejected, got %d", status)
+	}
+	if status := do("POST", lifecycleURL, rules, "set"); status != http.StatusOK {
+		t.Fatalf("Expected rules to be set, got %d", status)
+	}
+	expectedRules := []lifecycleRule{{Prefix: "tmp/", Days: 0}, {Prefix: "logs/", Days: 30}}
+	if saved := serverConfig.GetBucketLifecycles()[bucketName]; !reflect.DeepEqual(saved, expectedRules) {
+		t.Fatalf("Expected rules %v in config, got %v", expectedRules, saved)
+	}
+
+	data := []byte("expiring")
+	for _, object := range []string{"tmp/object1", "tmp/dir/object2", "logs/object3", "object4"} {
+		if status := do("PUT", getPutObjectURL(ts.Server.URL, bucketName, object), data, ""); status != http.StatusOK {
+			t.Fatalf("Expected upload of %s to succeed, got %d", object, status)
+		}
+	}
+	// Objects retained in WORM mode are never expired.
+	serverConfig.SetBucketRetention(bucketName, time.Hour)
+	defer serverConfig.SetBucketRetention(bucketName, 0)
+	if status := do("PUT", getPutObjectURL(ts.Server.URL, bucketName, "tmp/retained"), data, ""); status != http.StatusOK {
+		t.Fatalf("Expected upload of retained object to succeed, got %d", status)
+	}
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	go startLifecycleScanner(ts.Obj, 10*time.Millisecond, doneCh)
+
+	expired := map[string]bool{
+		"tmp/object1":     true,
+		"tmp/dir/object2": true,
+		"logs/object3":    false,
+		"object4":         false,
+		"tmp/retained":    false,
+	}
+	for i := 0; ; i++ {
+		remaining := 0
+		for object, isExpired := range expired {
+			if _, err := ts.Obj.GetObjectInfo(bucketName, object); err == nil && isExpired {
+				remaining++
+			} else if err != nil && !isExpired {
+				t.Fatalf("Expected %s to be kept, got %v", object, err)
+			}
+		}
+		if remaining == 0 {
+			break
+		}
+		if i == 500 {
+			t.Fatalf("Timed out waiting for %d expired objects to be deleted", remaining)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// An empty list removes the rules.
+	if status := do("POST", lifecycleURL, []byte(`[]`), "set"); status != http.StatusOK {
+		t.Fatalf("Expected rules to be removed, got %d", status)
+	}
+	if _, ok := serverConfig.GetBucketLifecycles()[bucketName]; ok {
+		t.Fatal("Expected rules to be removed from config")
+	}
+}
+
+// Tests the lifecycle scanner of a read-only server does not delete
+// expired objects.
+func TestLifecycleScannerReadOnly(t *testing.T) {
+	rootPath, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatalf("Unable to initialize config file, %s", err)
+	}
+	defer removeAll(rootPath)
+
+	objAPI, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots([]string{fsDir})
+
+	bucketName := getRandomBucketName()
+	if err = objAPI.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("expiring")
+	if _, err = objAPI.PutObject(bucketName, "tmp/object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	serverConfig.SetBucketLifecycle(bucketName, []lifecycleRule{{Prefix: "tmp/", Days: 0}})
+	defer serverConfig.SetBucketLifecycle(bucketName, nil)
+
+	globalIsReadOnly = true
+	defer func() { globalIsReadOnly = false }()
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	scannerDoneCh := make(chan struct{})
+	go func() {
+		startLifecycleScanner(objAPI, time.Millisecond, doneCh)
+		close(scannerDoneCh)
+	}()
+	select {
+	case <-scannerDoneCh:
+	case <-time.After(time.Second):
+		t.Fatal("Expected lifecycle scanner of a read-only server to return")
+	}
+	if _, err = objAPI.GetObjectInfo(bucketName, "tmp/object"); err != nil {
+		t.Fatalf("Expected expired object to be kept, got %v", err)
+	}
+}
+
+// Tests expiry of objects by their last modification time.
+func TestLifecycleRuleIsExpired(t *testing.T) {
+	now := time.Date(2017, 3, 10, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		rule     lifecycleRule
+		objInfo  ObjectInfo
+		expected bool
+	}{
+		{lifecycleRule{"tmp/", 0}, ObjectInfo{Name: "tmp/a", ModTime: now}, true},
+		{lifecycleRule{"tmp/", 1}, ObjectInfo{Name: "tmp/a", ModTime: now.Add(-23 * time.Hour)}, false},
+		{lifecycleRule{"tmp/", 1}, ObjectInfo{Name: "tmp/a", ModTime: now.Add(-24 * time.Hour)}, true},
+		{lifecycleRule{"tmp/", 1}, ObjectInfo{Name: "other/a", ModTime: now.Add(-48 * time.Hour)}, false},
+		{lifecycleRule{"", 2}, ObjectInfo{Name: "a", ModTime: now.Add(-72 * time.Hour)}, true},
+	}
+	for i, testCase := range testCases {
+		if expired := testCase.rule.isExpired(testCase.objInfo, now); expired != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, expired)
+		}
+	}
+}
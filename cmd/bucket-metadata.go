@@ -16,7 +16,10 @@
 
 package cmd
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 // BucketMetaState - Interface to update bucket metadata in-memory
 // state.
@@ -32,6 +35,15 @@ type BucketMetaState interface {
 
 	// Sends event
 	SendEvent(args *EventArgs) error
+
+	// Returns current time of the server
+	ServerTime() (time.Time, error)
+
+	// Removes a bucket from the bucket info cache
+	InvalidateBucketCache(args *InvalidateBucketCachePeerArgs) error
+
+	// Removes an object from the metadata store
+	InvalidateObjectMeta(args *InvalidateObjectMetaPeerArgs) error
 }
 
 // BucketUpdater - Interface implementer calls one of BucketMetaState's methods.
@@ -100,6 +112,25 @@ func (lc *localBucketMetaState) SendEvent(args *EventArgs) error {
 	return globalEventNotifier.SendListenerEvent(args.Arn, args.Event)
 }
 
+// localBucketMetaState.ServerTime - returns current time of this server.
+func (lc *localBucketMetaState) ServerTime() (time.Time, error) {
+	return time.Now().UTC(), nil
+}
+
+// localBucketMetaState.InvalidateBucketCache - removes a bucket from the
+// in-memory bucket info cache.
+func (lc *localBucketMetaState) InvalidateBucketCache(args *InvalidateBucketCachePeerArgs) error {
+	globalBucketCache.remove(args.Bucket)
+	return nil
+}
+
+// localBucketMetaState.InvalidateObjectMeta - removes an object from the
+// metadata store.
+func (lc *localBucketMetaState) InvalidateObjectMeta(args *InvalidateObjectMetaPeerArgs) error {
+	globalMetaStore.remove(args.Bucket, args.Object)
+	return nil
+}
+
 // Type that implements BucketMetaState for remote node.
 type remoteBucketMetaState struct {
 	*AuthRPCClient
@@ -132,3 +163,28 @@ func (rc *remoteBucketMetaState) SendEvent(args *EventArgs) error {
 	reply := AuthRPCReply{}
 	return rc.Call("S3.Event", args, &reply)
 }
+
+// remoteBucketMetaState.ServerTime - returns current time of remote peer
+// via RPC call. The call is not authenticated as peers with skewed clocks
+// fail to login.
+func (rc *remoteBucketMetaState) ServerTime() (time.Time, error) {
+	reply := ServerTimeReply{}
+	if err := rc.rpcClient.Call("S3.ServerTime", &ServerTimeArgs{}, &reply); err != nil {
+		return time.Time{}, err
+	}
+	return reply.ServerTime, nil
+}
+
+// remoteBucketMetaState.InvalidateBucketCache - sends bucket cache
+// invalidation to remote peer via RPC call.
+func (rc *remoteBucketMetaState) InvalidateBucketCache(args *InvalidateBucketCachePeerArgs) error {
+	reply := AuthRPCReply{}
+	return rc.Call("S3.InvalidateBucketCachePeer", args, &reply)
+}
+
+// remoteBucketMetaState.InvalidateObjectMeta - sends metadata store
+// invalidation to remote peer via RPC call.
+func (rc *remoteBucketMetaState) InvalidateObjectMeta(args *InvalidateObjectMetaPeerArgs) error {
+	reply := AuthRPCReply{}
+	return rc.Call("S3.InvalidateObjectMetaPeer", args, &reply)
+}
@@ -56,7 +56,7 @@ func (api objectAPIHandlers) GetBucketNotificationHandler(w http.ResponseWriter,
 
 	_, err := objAPI.GetBucketInfo(bucket)
 	if err != nil {
-		errorIf(err, "Unable to find bucket info.")
+		requestErrorIf(r, err, "Unable to find bucket info.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -64,7 +64,7 @@ func (api objectAPIHandlers) GetBucketNotificationHandler(w http.ResponseWriter,
 	// Attempt to successfully load notification config.
 	nConfig, err := loadNotificationConfig(bucket, objAPI)
 	if err != nil && err != errNoSuchNotifications {
-		errorIf(err, "Unable to read notification configuration.")
+		requestErrorIf(r, err, "Unable to read notification configuration.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -76,7 +76,7 @@ func (api objectAPIHandlers) GetBucketNotificationHandler(w http.ResponseWriter,
 	notificationBytes, err := xml.Marshal(nConfig)
 	if err != nil {
 		// For any marshalling failure.
-		errorIf(err, "Unable to marshal notification configuration into XML.", err)
+		requestErrorIf(r, err, "Unable to marshal notification configuration into XML.", err)
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -110,7 +110,7 @@ func (api objectAPIHandlers) PutBucketNotificationHandler(w http.ResponseWriter,
 
 	_, err := objectAPI.GetBucketInfo(bucket)
 	if err != nil {
-		errorIf(err, "Unable to find bucket info.")
+		requestErrorIf(r, err, "Unable to find bucket info.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -132,7 +132,7 @@ func (api objectAPIHandlers) PutBucketNotificationHandler(w http.ResponseWriter,
 		_, err = io.Copy(&buffer, r.Body)
 	}
 	if err != nil {
-		errorIf(err, "Unable to read incoming body.")
+		requestErrorIf(r, err, "Unable to read incoming body.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -141,7 +141,7 @@ func (api objectAPIHandlers) PutBucketNotificationHandler(w http.ResponseWriter,
 	// Unmarshal notification bytes.
 	notificationConfigBytes := buffer.Bytes()
 	if err = xml.Unmarshal(notificationConfigBytes, &notificationCfg); err != nil {
-		errorIf(err, "Unable to parse notification configuration XML.")
+		requestErrorIf(r, err, "Unable to parse notification configuration XML.")
 		writeErrorResponse(w, ErrMalformedXML, r.URL)
 		return
 	} // Successfully marshalled notification configuration.
@@ -153,7 +153,7 @@ func (api objectAPIHandlers) PutBucketNotificationHandler(w http.ResponseWriter,
 	}
 
 	// Put bucket notification config.
-	err = PutBucketNotificationConfig(bucket, &notificationCfg, objectAPI)
+	err = PutBucketNotificationConfig(bucket, &notificationCfg, objectAPI, getRequestID(r))
 	if err != nil {
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
@@ -167,7 +167,7 @@ func (api objectAPIHandlers) PutBucketNotificationHandler(w http.ResponseWriter,
 // bucket (overwrites any previous config) persistently, updates
 // global in-memory state, and notify other nodes in the cluster (if
 // any)
-func PutBucketNotificationConfig(bucket string, ncfg *notificationConfig, objAPI ObjectLayer) error {
+func PutBucketNotificationConfig(bucket string, ncfg *notificationConfig, objAPI ObjectLayer, requestID string) error {
 	if ncfg == nil {
 		return errInvalidArgument
 	}
@@ -186,7 +186,7 @@ func PutBucketNotificationConfig(bucket string, ncfg *notificationConfig, objAPI
 	}
 
 	// All servers (including local) are told to update in-memory config
-	S3PeersUpdateBucketNotification(bucket, ncfg)
+	S3PeersUpdateBucketNotification(bucket, ncfg, requestID)
 
 	return nil
 }
@@ -285,7 +285,7 @@ func (api objectAPIHandlers) ListenBucketNotificationHandler(w http.ResponseWrit
 
 	_, err := objAPI.GetBucketInfo(bucket)
 	if err != nil {
-		errorIf(err, "Unable to get bucket info.")
+		requestErrorIf(r, err, "Unable to get bucket info.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -338,7 +338,7 @@ func (api objectAPIHandlers) ListenBucketNotificationHandler(w http.ResponseWrit
 	defer close(nEventCh)
 	// Add channel for listener events
 	if err = globalEventNotifier.AddListenerChan(accountARN, nEventCh); err != nil {
-		errorIf(err, "Error adding a listener!")
+		requestErrorIf(r, err, "Error adding a listener!")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -354,12 +354,12 @@ func (api objectAPIHandlers) ListenBucketNotificationHandler(w http.ResponseWrit
 		TargetServer: globalMinioAddr,
 	}
 
-	err = AddBucketListenerConfig(bucket, &lc, objAPI)
+	err = AddBucketListenerConfig(bucket, &lc, objAPI, getRequestID(r))
 	if err != nil {
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
-	defer RemoveBucketListenerConfig(bucket, &lc, objAPI)
+	defer RemoveBucketListenerConfig(bucket, &lc, objAPI, getRequestID(r))
 
 	// Add all common headers.
 	setCommonHeaders(w)
@@ -370,7 +370,7 @@ func (api objectAPIHandlers) ListenBucketNotificationHandler(w http.ResponseWrit
 
 // AddBucketListenerConfig - Updates on disk state of listeners, and
 // updates all peers with the change in listener config.
-func AddBucketListenerConfig(bucket string, lcfg *listenerConfig, objAPI ObjectLayer) error {
+func AddBucketListenerConfig(bucket string, lcfg *listenerConfig, objAPI ObjectLayer, requestID string) error {
 	if lcfg == nil {
 		return errInvalidArgument
 	}
@@ -390,19 +390,19 @@ func AddBucketListenerConfig(bucket string, lcfg *listenerConfig, objAPI ObjectL
 	if globalIsDistXL {
 		err := persistListenerConfig(bucket, listenerCfgs, objAPI)
 		if err != nil {
-			errorIf(err, "Error persisting listener config when adding a listener.")
+			requestIDErrorIf(requestID, err, "Error persisting listener config when adding a listener.")
 			return err
 		}
 	}
 
 	// persistence success - now update in-memory globals on all
 	// peers (including local)
-	S3PeersUpdateBucketListener(bucket, listenerCfgs)
+	S3PeersUpdateBucketListener(bucket, listenerCfgs, requestID)
 	return nil
 }
 
 // RemoveBucketListenerConfig - removes a given bucket notification config
-func RemoveBucketListenerConfig(bucket string, lcfg *listenerConfig, objAPI ObjectLayer) {
+func RemoveBucketListenerConfig(bucket string, lcfg *listenerConfig, objAPI ObjectLayer, requestID string) {
 	listenerCfgs := globalEventNotifier.GetBucketListenerConfig(bucket)
 
 	// remove listener with matching ARN - if not found ignore and exit.
@@ -431,12 +431,12 @@ func RemoveBucketListenerConfig(bucket string, lcfg *listenerConfig, objAPI Obje
 	if globalIsDistXL {
 		err := persistListenerConfig(bucket, updatedLcfgs, objAPI)
 		if err != nil {
-			errorIf(err, "Error persisting listener config when removing a listener.")
+			requestIDErrorIf(requestID, err, "Error persisting listener config when removing a listener.")
 			return
 		}
 	}
 
 	// persistence success - now update in-memory globals on all
 	// peers (including local)
-	S3PeersUpdateBucketListener(bucket, updatedLcfgs)
+	S3PeersUpdateBucketListener(bucket, updatedLcfgs, requestID)
 }
@@ -226,12 +226,12 @@ func writeBucketPolicy(bucket string, objAPI ObjectLayer, bpy *bucketPolicy) err
 	return nil
 }
 
-func parseAndPersistBucketPolicy(bucket string, policyBytes []byte, objAPI ObjectLayer) APIErrorCode {
+func parseAndPersistBucketPolicy(bucket string, policyBytes []byte, objAPI ObjectLayer, requestID string) APIErrorCode {
 	// Parse bucket policy.
 	var policy = &bucketPolicy{}
 	err := parseBucketPolicy(bytes.NewReader(policyBytes), policy)
 	if err != nil {
-		errorIf(err, "Unable to parse bucket policy.")
+		requestIDErrorIf(requestID, err, "Unable to parse bucket policy.")
 		return ErrInvalidPolicyDocument
 	}
 
@@ -247,14 +247,14 @@ func parseAndPersistBucketPolicy(bucket string, policyBytes []byte, objAPI Objec
 	defer bucketLock.Unlock()
 
 	// Save bucket policy.
-	if err = persistAndNotifyBucketPolicyChange(bucket, policyChange{false, policy}, objAPI); err != nil {
+	if err = persistAndNotifyBucketPolicyChange(bucket, policyChange{false, policy}, objAPI, requestID); err != nil {
 		switch err.(type) {
 		case BucketNameInvalid:
 			return ErrInvalidBucketName
 		case BucketNotFound:
 			return ErrNoSuchBucket
 		default:
-			errorIf(err, "Unable to save bucket policy.")
+			requestIDErrorIf(requestID, err, "Unable to save bucket policy.")
 			return ErrInternalError
 		}
 	}
@@ -264,7 +264,7 @@ func parseAndPersistBucketPolicy(bucket string, policyBytes []byte, objAPI Objec
 // persistAndNotifyBucketPolicyChange - takes a policyChange argument,
 // persists it to storage, and notify nodes in the cluster about the
 // change. In-memory state is updated in response to the notification.
-func persistAndNotifyBucketPolicyChange(bucket string, pCh policyChange, objAPI ObjectLayer) error {
+func persistAndNotifyBucketPolicyChange(bucket string, pCh policyChange, objAPI ObjectLayer, requestID string) error {
 	if pCh.IsRemove {
 		if err := removeBucketPolicy(bucket, objAPI); err != nil {
 			return err
@@ -279,6 +279,6 @@ func persistAndNotifyBucketPolicyChange(bucket string, pCh policyChange, objAPI
 	}
 
 	// Notify all peers (including self) to update in-memory state
-	S3PeersUpdateBucketPolicy(bucket, pCh)
+	S3PeersUpdateBucketPolicy(bucket, pCh, requestID)
 	return nil
 }
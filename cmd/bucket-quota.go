@@ -0,0 +1,225 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "sync"
+
+// bucketUsage - number of bytes stored in buckets with a quota. Usage
+// of a bucket is listed once on first use and then updated by the
+// writes and deletes served by this server, quotas are therefore not
+// supported in distributed setups.
+type bucketUsage struct {
+	mutex *sync.Mutex
+	usage map[string]int64
+}
+
+// newBucketUsage - initialize an empty bucket usage.
+func newBucketUsage() *bucketUsage {
+	return &bucketUsage{
+		mutex: &sync.Mutex{},
+		usage: make(map[string]int64),
+	}
+}
+
+// isTracked - returns true if usage of bucket is known.
+func (u *bucketUsage) isTracked(bucket string) bool {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	_, ok := u.usage[bucket]
+	return ok
+}
+
+// get - returns number of bytes stored in bucket, the bucket is listed
+// if its usage is not known yet.
+func (u *bucketUsage) get(objAPI ObjectLayer, bucket string) (int64, error) {
+	u.mutex.Lock()
+	used, ok := u.usage[bucket]
+	u.mutex.Unlock()
+	if ok {
+		return used, nil
+	}
+
+	used, err := getBucketSize(objAPI, bucket)
+	if err != nil {
+		return 0, err
+	}
+
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	if current, ok := u.usage[bucket]; ok {
+		// Listed concurrently by another request.
+		return current, nil
+	}
+	u.usage[bucket] = used
+	return used, nil
+}
+
+// reserve - accounts delta bytes to bucket if its usage stays within
+// quota, returns false otherwise. Checked and accounted at once such
+// that concurrent writes cannot exceed the quota together. Buckets
+// whose usage is not known are not accounted.
+func (u *bucketUsage) reserve(bucket string, delta, quota int64) bool {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	used, ok := u.usage[bucket]
+	if !ok {
+		return true
+	}
+	if used+delta > quota {
+		return false
+	}
+	u.usage[bucket] = used + delta
+	return true
+}
+
+// add - accounts delta bytes to bucket, if its usage is known.
+func (u *bucketUsage) add(bucket string, delta int64) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	if used, ok := u.usage[bucket]; ok {
+		u.usage[bucket] = used + delta
+	}
+}
+
+// reset - forgets usage of bucket, it is listed again on next use.
+func (u *bucketUsage) reset(bucket string) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	delete(u.usage, bucket)
+}
+
+// getBucketSize - lists all objects of bucket and returns the sum of
+// their sizes.
+func getBucketSize(objAPI ObjectLayer, bucket string) (size int64, err error) {
+	marker := ""
+	for {
+		result, err := objAPI.ListObjects(bucket, "", marker, "", maxObjectList)
+		if err != nil {
+			return 0, err
+		}
+		for _, objInfo := range result.Objects {
+			size += objInfo.Size
+		}
+		if !result.IsTruncated {
+			return size, nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+// getTrackedObjectSize - returns size of object if usage of its bucket
+// is known, zero otherwise or if the object does not exist.
+func getTrackedObjectSize(objAPI ObjectLayer, bucket, object string) int64 {
+	if !globalBucketUsage.isTracked(bucket) {
+		return 0
+	}
+	objInfo, err := objAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		return 0
+	}
+	return objInfo.Size
+}
+
+// getCompletedObjectSize - returns size of the object completing a
+// multipart upload from the given parts.
+func getCompletedObjectSize(objAPI ObjectLayer, bucket, object, uploadID string, parts []completePart) (int64, error) {
+	partSizes := make(map[int]int64)
+	partNumberMarker := 0
+	for {
+		result, err := objAPI.ListObjectParts(bucket, object, uploadID, partNumberMarker, maxPartsList)
+		if err != nil {
+			return 0, err
+		}
+		for _, part := range result.Parts {
+			partSizes[part.PartNumber] = part.Size
+		}
+		if !result.IsTruncated {
+			break
+		}
+		partNumberMarker = result.NextPartNumberMarker
+	}
+
+	var size int64
+	for _, part := range parts {
+		size += partSizes[part.PartNumber]
+	}
+	return size, nil
+}
+
+// getBucketQuota - returns quota of bucket in bytes, zero if not set.
+// Quotas are not enforced in distributed setups.
+func getBucketQuota(bucket string) int64 {
+	if globalIsDistXL {
+		return 0
+	}
+	return serverConfig.GetBucketQuota(bucket)
+}
+
+// quotaReservation - bytes accounted to a bucket for a write before it
+// is done, replacing an object of oldSize bytes.
+type quotaReservation struct {
+	bucket   string
+	oldSize  int64
+	reserved int64
+}
+
+// commit - accounts the written object of size bytes instead of the
+// reserved bytes.
+func (q quotaReservation) commit(size int64) {
+	globalBucketUsage.add(q.bucket, size-q.oldSize-q.reserved)
+}
+
+// cancel - releases the reserved bytes of a failed write.
+func (q quotaReservation) cancel() {
+	if q.reserved != 0 {
+		globalBucketUsage.add(q.bucket, -q.reserved)
+	}
+}
+
+// checkBucketQuota - verifies an object of size bytes, replacing an
+// existing object if any, fits in the quota of bucket and reserves
+// the bytes for the write. The reservation is committed once the
+// write is done, or cancelled if it fails.
+func checkBucketQuota(objAPI ObjectLayer, bucket, object string, size int64) (quotaReservation, error) {
+	q := quotaReservation{bucket: bucket}
+	quota := getBucketQuota(bucket)
+	if quota == 0 {
+		return q, nil
+	}
+
+	if _, err := globalBucketUsage.get(objAPI, bucket); err != nil {
+		return q, err
+	}
+	q.oldSize = getTrackedObjectSize(objAPI, bucket, object)
+	if size < 0 {
+		// Size is not known upfront, only full buckets are rejected.
+		size = 0
+	}
+	if !globalBucketUsage.reserve(bucket, size-q.oldSize, quota) {
+		return q, traceError(BucketQuotaExceeded{Bucket: bucket})
+	}
+	q.reserved = size - q.oldSize
+	return q, nil
+}
+
+// setBucketQuota - sets quota of bucket in bytes on this server and
+// saves it to the config, zero removes the quota.
+func setBucketQuota(bucket string, quota int64) error {
+	serverConfig.SetBucketQuota(bucket, quota)
+	globalBucketUsage.reset(bucket)
+	return serverConfig.Save()
+}
@@ -0,0 +1,162 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// Tests writes are rejected once a bucket reaches its quota and
+// deletes free space back up.
+func TestBucketQuota(t *testing.T) {
+	ts, stop := startTestServerWithPeers(t, "XL")
+	defer stop()
+
+	bucketName := getRandomBucketName()
+	if err := ts.Obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+
+	do := func(method, url string, body []byte, adminOp string) int {
+		req, err := newTestSignedRequestV4(method, url, int64(len(body)), bytes.NewReader(body), ts.AccessKey, ts.SecretKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if adminOp != "" {
+			req.Header.Set(minioAdminOpHeader, adminOp)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// Quota is set through the admin API.
+	quotaURL := ts.Server.URL + "/?bucket-quota&bucket=" + bucketName + "&quota="
+	if status := do("POST", quotaURL+"-1", nil, "set"); status != http.StatusBadRequest {
+		t.Fatalf("Expected invalid quota to be rejected, got %d", status)
+	}
+	if status := do("POST", ts.Server.URL+"/?bucket-quota&bucket=missing-bucket&quota=20", nil, "set"); status != http.StatusNotFound {
+		t.Fatalf("Expected quota of missing bucket to be rejected, got %d", status)
+	}
+	if status := do("POST", quotaURL+"20", nil, "set"); status != http.StatusOK {
+		t.Fatalf("Expected quota to be set, got %d", status)
+	}
+	if quota := serverConfig.GetBucketQuota(bucketName); quota != 20 {
+		t.Fatalf("Expected quota of 20 bytes in config, got %d", quota)
+	}
+
+	data := []byte("0123456789")
+	testCases := []struct {
+		method         string
+		object         string
+		body           []byte
+		expectedStatus int
+	}{
+		// Fill the quota.
+		{"PUT", "object1", data, http.StatusOK},
+		{"PUT", "object2", data, http.StatusOK},
+		{"PUT", "object3", data[:1], http.StatusInsufficientStorage},
+		// Overwrites only account the difference.
+		{"PUT", "object1", data, http.StatusOK},
+		// Deletes free space back up.
+		{"DELETE", "object2", nil, http.StatusNoContent},
+		{"PUT", "object3", data[:5], http.StatusOK},
+		{"PUT", "object4", data[:6], http.StatusInsufficientStorage},
+	}
+	for i, testCase := range testCases {
+		var url string
+		if testCase.method == "PUT" {
+			url = getPutObjectURL(ts.Server.URL, bucketName, testCase.object)
+		} else {
+			url = getDeleteObjectURL(ts.Server.URL, bucketName, testCase.object)
+		}
+		if status := do(testCase.method, url, testCase.body, ""); status != testCase.expectedStatus {
+			t.Errorf("Test %d: %s %s expected %d, got %d", i+1, testCase.method, testCase.object,
+				testCase.expectedStatus, status)
+		}
+	}
+
+	// Multipart uploads are checked on completion.
+	uploadID, err := ts.Obj.NewMultipartUpload(bucketName, "object4", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md5Hex, err := ts.Obj.PutObjectPart(bucketName, "object4", uploadID, 1, 6, bytes.NewReader(data[:6]), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	completeBytes, err := xml.Marshal(completeMultipartUpload{Parts: []completePart{{PartNumber: 1, ETag: md5Hex}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	completeURL := getCompleteMultipartUploadURL(ts.Server.URL, bucketName, "object4", uploadID)
+	if status := do("POST", completeURL, completeBytes, ""); status != http.StatusInsufficientStorage {
+		t.Errorf("Expected multipart upload beyond quota to be rejected, got %d", status)
+	}
+
+	// Removing the quota allows the write.
+	if status := do("POST", quotaURL+"0", nil, "set"); status != http.StatusOK {
+		t.Fatalf("Expected quota to be removed, got %d", status)
+	}
+	if status := do("POST", completeURL, completeBytes, ""); status != http.StatusOK {
+		t.Errorf("Expected multipart upload without quota to succeed, got %d", status)
+	}
+
+	// Quotas are rejected in distributed setups.
+	defer func(isDistXL bool) { globalIsDistXL = isDistXL }(globalIsDistXL)
+	globalIsDistXL = true
+	if status := do("POST", quotaURL+"20", nil, "set"); status != http.StatusNotImplemented {
+		t.Errorf("Expected quota to be rejected in distributed setups, got %d", status)
+	}
+}
+
+// Tests reservations are checked and accounted at once, such that
+// concurrent writes cannot exceed the quota together.
+func TestBucketUsageReserve(t *testing.T) {
+	u := newBucketUsage()
+	if !u.reserve("bucket", 10, 20) {
+		t.Fatal("Expected reservation of a bucket with unknown usage to pass")
+	}
+	if _, ok := u.usage["bucket"]; ok {
+		t.Fatal("Expected bucket with unknown usage not to be accounted")
+	}
+
+	u.usage["bucket"] = 5
+	var wg sync.WaitGroup
+	var reserved int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if u.reserve("bucket", 5, 20) {
+				atomic.AddInt32(&reserved, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	if reserved != 3 || u.usage["bucket"] != 20 {
+		t.Errorf("Expected 3 reservations up to 20 bytes, got %d and %d bytes", reserved, u.usage["bucket"])
+	}
+}
@@ -0,0 +1,124 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/minio/minio-go/pkg/policy"
+)
+
+// bucketTemplatePolicy - canned policy applied on a prefix of every
+// newly created bucket.
+type bucketTemplatePolicy struct {
+	Prefix string              `json:"prefix"`
+	Policy policy.BucketPolicy `json:"policy"`
+}
+
+// bucketTemplate - configuration applied to every newly created
+// bucket, clients may override any of it afterwards with the regular
+// bucket policy, lifecycle and notification APIs.
+type bucketTemplate struct {
+	Policies  []bucketTemplatePolicy `json:"policies,omitempty"`
+	Lifecycle []lifecycleRule        `json:"lifecycle,omitempty"`
+	Queues    []queueConfig          `json:"queues,omitempty"`
+}
+
+// isEmpty - returns true if the template has nothing to apply.
+func (t *bucketTemplate) isEmpty() bool {
+	return t == nil || (len(t.Policies) == 0 && len(t.Lifecycle) == 0 && len(t.Queues) == 0)
+}
+
+// validate - validates all the canned policies, expiration rules and
+// notification queue configs of the template.
+func (t *bucketTemplate) validate() error {
+	if t.isEmpty() {
+		return nil
+	}
+	for _, p := range t.Policies {
+		if !p.Policy.IsValidBucketPolicy() {
+			return fmt.Errorf("Invalid policy type %s for prefix %s", p.Policy, p.Prefix)
+		}
+	}
+	if !isValidLifecycleRules(t.Lifecycle) {
+		return errors.New("Invalid lifecycle rules")
+	}
+	if s3Error := validateNotificationConfig(notificationConfig{QueueConfigs: t.Queues}); s3Error != ErrNone {
+		return errors.New(getAPIError(s3Error).Description)
+	}
+	return nil
+}
+
+// applyBucketTemplate - applies the server configured bucket template
+// to a newly created bucket. If any part of the template cannot be
+// applied the bucket is deleted again, such that clients can retry the
+// create. Must be called without holding the bucket lock, since
+// persisting bucket configuration acquires it.
+func applyBucketTemplate(bucket string, objAPI ObjectLayer, requestID string) error {
+	template := serverConfig.GetBucketTemplate()
+	if template.isEmpty() {
+		return nil
+	}
+
+	if err := template.apply(bucket, objAPI, requestID); err != nil {
+		// Expiration rules live in the server config, unlike the
+		// policy and notification config they are not removed
+		// along with the bucket.
+		if len(template.Lifecycle) > 0 {
+			errorIf(sendSetBucketLifecycleCmd(globalAdminPeers, bucket, nil), "Unable to remove lifecycle of bucket %s.", bucket)
+		}
+		errorIf(deleteBucket(bucket, objAPI), "Unable to delete bucket %s.", bucket)
+		return err
+	}
+	return nil
+}
+
+// apply - persists the policies, expiration rules and notification
+// config of the template for bucket on all the servers.
+func (t *bucketTemplate) apply(bucket string, objAPI ObjectLayer, requestID string) error {
+	if len(t.Policies) > 0 {
+		policyInfo := policy.BucketAccessPolicy{Version: "2012-10-17"}
+		for _, p := range t.Policies {
+			policyInfo.Statements = policy.SetPolicy(policyInfo.Statements, p.Policy, bucket, p.Prefix)
+		}
+		if len(policyInfo.Statements) > 0 {
+			data, err := json.Marshal(policyInfo)
+			if err != nil {
+				return err
+			}
+			if s3Error := parseAndPersistBucketPolicy(bucket, data, objAPI, requestID); s3Error != ErrNone {
+				return errors.New(getAPIError(s3Error).Description)
+			}
+		}
+	}
+
+	if len(t.Lifecycle) > 0 {
+		if err := sendSetBucketLifecycleCmd(globalAdminPeers, bucket, t.Lifecycle); err != nil {
+			return err
+		}
+	}
+
+	if len(t.Queues) > 0 {
+		ncfg := &notificationConfig{QueueConfigs: t.Queues}
+		if err := PutBucketNotificationConfig(bucket, ncfg, objAPI, requestID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
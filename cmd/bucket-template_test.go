@@ -0,0 +1,160 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/minio/minio-go/pkg/policy"
+)
+
+// Tests validation of bucket templates.
+func TestBucketTemplateValidate(t *testing.T) {
+	rootPath, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	defer removeAll(rootPath)
+
+	testCases := []struct {
+		template *bucketTemplate
+		valid    bool
+	}{
+		{nil, true},
+		{&bucketTemplate{}, true},
+		{&bucketTemplate{Policies: []bucketTemplatePolicy{{"", policy.BucketPolicyReadOnly}, {"public", policy.BucketPolicyReadWrite}}}, true},
+		{&bucketTemplate{Policies: []bucketTemplatePolicy{{"", "foo"}}}, false},
+		{&bucketTemplate{Lifecycle: []lifecycleRule{{"tmp/", 7}}}, true},
+		{&bucketTemplate{Lifecycle: []lifecycleRule{{"tmp/", -1}}}, false},
+		{&bucketTemplate{Queues: []queueConfig{{
+			ServiceConfig: ServiceConfig{Events: []string{"s3:ObjectCreated:Put"}},
+			QueueARN:      "arn:minio:sqs:us-east-1:1:webhook",
+		}}}, false},
+	}
+	for i, testCase := range testCases {
+		err := testCase.template.validate()
+		if testCase.valid && err != nil {
+			t.Errorf("Test %d: Expected template to be valid, got %v", i+1, err)
+		}
+		if !testCase.valid && err == nil {
+			t.Errorf("Test %d: Expected template to be invalid", i+1)
+		}
+	}
+}
+
+// Tests bucket template is applied to newly created buckets.
+func TestApplyBucketTemplate(t *testing.T) {
+	ExecObjectLayerTest(t, testApplyBucketTemplate)
+}
+
+func testApplyBucketTemplate(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	rootPath, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	defer removeAll(rootPath)
+
+	// No template leaves the bucket without a policy.
+	bucket := getRandomBucketName()
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	if err = applyBucketTemplate(bucket, obj, ""); err != nil {
+		t.Fatal(err)
+	}
+	policyInfo, err := readBucketAccessPolicy(obj, bucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policyInfo.Statements) != 0 {
+		t.Fatalf("%s: Expected no policy, got %v", instanceType, policyInfo.Statements)
+	}
+
+	serverConfig.SetBucketTemplate(&bucketTemplate{
+		Policies: []bucketTemplatePolicy{{"public", policy.BucketPolicyReadOnly}},
+	})
+	bucket = getRandomBucketName()
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	if err = applyBucketTemplate(bucket, obj, ""); err != nil {
+		t.Fatal(err)
+	}
+	policyInfo, err = readBucketAccessPolicy(obj, bucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := policy.GetPolicy(policyInfo.Statements, bucket, "public"); p != policy.BucketPolicyReadOnly {
+		t.Fatalf("%s: Expected %s policy on public prefix, got %s", instanceType, policy.BucketPolicyReadOnly, p)
+	}
+	if p := policy.GetPolicy(policyInfo.Statements, bucket, "private"); p != policy.BucketPolicyNone {
+		t.Fatalf("%s: Expected %s policy on private prefix, got %s", instanceType, policy.BucketPolicyNone, p)
+	}
+
+	// Expiration rules are saved on all the servers.
+	defer func(peers adminPeers) { globalAdminPeers = peers }(globalAdminPeers)
+	initGlobalAdminPeers(nil)
+	serverConfig.SetBucketTemplate(&bucketTemplate{
+		Lifecycle: []lifecycleRule{{"tmp/", 7}},
+	})
+	bucket = getRandomBucketName()
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	if err = applyBucketTemplate(bucket, obj, ""); err != nil {
+		t.Fatal(err)
+	}
+	if rules := serverConfig.GetBucketLifecycles()[bucket]; len(rules) != 1 || rules[0] != (lifecycleRule{"tmp/", 7}) {
+		t.Fatalf("%s: Expected lifecycle rules of the template, got %v", instanceType, rules)
+	}
+
+	// A template which cannot be applied deletes the bucket along
+	// with the parts of the template applied already. Saving the
+	// expiration rules fails with a directory in place of the config
+	// file.
+	serverConfig.SetBucketTemplate(&bucketTemplate{
+		Policies:  []bucketTemplatePolicy{{"public", policy.BucketPolicyReadOnly}},
+		Lifecycle: []lifecycleRule{{"tmp/", 7}},
+	})
+	bucket = getRandomBucketName()
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	configFile, err := getConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Remove(configFile); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Mkdir(configFile, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err = applyBucketTemplate(bucket, obj, ""); err == nil {
+		t.Fatalf("%s: Expected applying the template to fail", instanceType)
+	}
+	if _, err = obj.GetBucketInfo(bucket); !isErrBucketNotFound(err) {
+		t.Fatalf("%s: Expected bucket to be deleted, got %v", instanceType, err)
+	}
+	if _, err = readBucketPolicyJSON(bucket, obj); !isErrBucketPolicyNotFound(err) {
+		t.Fatalf("%s: Expected bucket policy to be deleted, got %v", instanceType, err)
+	}
+	if rules := serverConfig.GetBucketLifecycles()[bucket]; len(rules) != 0 {
+		t.Fatalf("%s: Expected no lifecycle rules, got %v", instanceType, rules)
+	}
+}
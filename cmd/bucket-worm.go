@@ -0,0 +1,89 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+)
+
+// Metadata key of the time until which an object of a bucket in WORM
+// mode is retained, returned as a header along with the object.
+const retainUntilMetaKey = "X-Minio-Retain-Until"
+
+// wormBuckets - retention period of objects of each bucket in WORM
+// (write once read many) mode, in time.Duration format e.g. "8760h".
+type wormBuckets map[string]string
+
+// validate - verifies retention periods are positive durations.
+func (b wormBuckets) validate() error {
+	for bucket, value := range b {
+		retention, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("Invalid retention period %s of bucket %s, %s", value, bucket, err)
+		}
+		if retention <= 0 {
+			return fmt.Errorf("Retention period of bucket %s must be positive", bucket)
+		}
+	}
+	return nil
+}
+
+// getRetention - returns retention period of objects of bucket, zero
+// if the bucket is not in WORM mode.
+func (b wormBuckets) getRetention(bucket string) time.Duration {
+	retention, err := time.ParseDuration(b[bucket])
+	if err != nil {
+		return 0
+	}
+	return retention
+}
+
+// setObjectRetention - saves into metadata the time until which an
+// object written now to bucket is retained, if the bucket is in WORM
+// mode. Retention copied over from another object is never kept.
+func setObjectRetention(bucket string, metadata map[string]string) {
+	delete(metadata, retainUntilMetaKey)
+	if retention := serverConfig.GetBucketRetention(bucket); retention > 0 {
+		metadata[retainUntilMetaKey] = time.Now().UTC().Add(retention).Format(time.RFC3339)
+	}
+}
+
+// checkObjectRetention - returns ObjectRetained if object exists and
+// is retained, such that it cannot be overwritten or deleted. Only
+// objects of buckets in WORM mode are retained, objects written before
+// WORM mode was enabled are not.
+func checkObjectRetention(objAPI ObjectLayer, bucket, object string) error {
+	if serverConfig.GetBucketRetention(bucket) == 0 {
+		return nil
+	}
+	objInfo, err := objAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		if isErrObjectNotFound(errorCause(err)) {
+			return nil
+		}
+		return err
+	}
+	retainUntil, err := time.Parse(time.RFC3339, objInfo.UserDefined[retainUntilMetaKey])
+	if err != nil {
+		return nil
+	}
+	if time.Now().UTC().Before(retainUntil) {
+		return traceError(ObjectRetained{Bucket: bucket, Object: object, RetainUntil: retainUntil})
+	}
+	return nil
+}
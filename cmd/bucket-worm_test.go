@@ -0,0 +1,153 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// Tests validation of retention periods of WORM buckets.
+func TestWORMBucketsValidate(t *testing.T) {
+	testCases := []struct {
+		buckets wormBuckets
+		success bool
+	}{
+		{nil, true},
+		{wormBuckets{"bucket": "8760h"}, true},
+		{wormBuckets{"bucket": "1y"}, false},
+		{wormBuckets{"bucket": "-1h"}, false},
+		{wormBuckets{"bucket": "0s"}, false},
+	}
+	for i, testCase := range testCases {
+		if err := testCase.buckets.validate(); testCase.success != (err == nil) {
+			t.Errorf("Test %d: Unexpected error %v", i+1, err)
+		}
+	}
+}
+
+// Tests objects of buckets in WORM mode cannot be overwritten or
+// deleted until their retention period expires.
+func TestAPIWORMBucketHandler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPIWORMBucketHandler, []string{"CopyObject", "PutObject", "DeleteObject", "DeleteMultipleObjects"})
+}
+
+func testAPIWORMBucketHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	serverConfig.SetBucketRetention(bucketName, time.Hour)
+	defer serverConfig.SetBucketRetention(bucketName, 0)
+
+	// execRequest - sends a signed request and validates the response.
+	execRequest := func(method, targetURL string, data []byte, header http.Header, expectedStatus int) {
+		rec := httptest.NewRecorder()
+		req, err := newTestRequest(method, targetURL, int64(len(data)), bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+		}
+		for key := range header {
+			req.Header.Set(key, header.Get(key))
+		}
+		if err = signRequestV4(req, credentials.AccessKey, credentials.SecretKey); err != nil {
+			t.Fatalf("%s: Failed to sign HTTP request: <ERROR> %v", instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != expectedStatus {
+			t.Fatalf("%s: %s %s: Expected the response status to be `%d`, but instead found `%d`",
+				instanceType, method, targetURL, expectedStatus, rec.Code)
+		}
+		if expectedStatus == http.StatusForbidden {
+			var errXML APIErrorResponse
+			if err = xml.Unmarshal(rec.Body.Bytes(), &errXML); err != nil {
+				t.Fatalf("%s: Failed to unmarshal error response: <ERROR> %v", instanceType, err)
+			}
+			if errXML.Code != "AccessDenied" || errXML.Message != getAPIError(ErrObjectRetained).Description {
+				t.Errorf("%s: %s %s: Expected retention error, got %s: %s", instanceType, method, targetURL, errXML.Code, errXML.Message)
+			}
+		}
+	}
+
+	data := []byte("hello")
+	objectName := "retained-object"
+	putURL := getPutObjectURL("", bucketName, objectName)
+
+	// Object written under WORM is retained for the retention period.
+	execRequest("PUT", putURL, data, nil, http.StatusOK)
+	objInfo, err := obj.GetObjectInfo(bucketName, objectName)
+	if err != nil {
+		t.Fatalf("%s: <ERROR> %v", instanceType, err)
+	}
+	retainUntil, err := time.Parse(time.RFC3339, objInfo.UserDefined[retainUntilMetaKey])
+	if err != nil {
+		t.Fatalf("%s: Expected retention to be saved, got %v", instanceType, objInfo.UserDefined)
+	}
+	if d := retainUntil.Sub(time.Now()); d < 59*time.Minute || d > time.Hour {
+		t.Errorf("%s: Expected object to be retained for an hour, retained until %s", instanceType, retainUntil)
+	}
+
+	// Overwrite, copy onto and delete of the retained object are rejected.
+	execRequest("PUT", putURL, []byte("world"), nil, http.StatusForbidden)
+	copyHeader := http.Header{}
+	copyHeader.Set("X-Amz-Copy-Source", url.QueryEscape("/"+bucketName+"/"+objectName))
+	copyHeader.Set("X-Amz-Metadata-Directive", "REPLACE")
+	execRequest("PUT", getCopyObjectURL("", bucketName, objectName), nil, copyHeader, http.StatusForbidden)
+	execRequest("DELETE", getDeleteObjectURL("", bucketName, objectName), nil, nil, http.StatusForbidden)
+
+	deleteBytes, err := xml.Marshal(DeleteObjectsRequest{Objects: []ObjectIdentifier{{ObjectName: objectName}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	req, err := newTestSignedRequestV4("POST", getMultiDeleteObjectURL("", bucketName), int64(len(deleteBytes)),
+		bytes.NewReader(deleteBytes), credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	var deleteResp DeleteObjectsResponse
+	if err = xml.Unmarshal(rec.Body.Bytes(), &deleteResp); err != nil {
+		t.Fatalf("%s: Failed to unmarshal multi delete response: <ERROR> %v", instanceType, err)
+	}
+	if len(deleteResp.Errors) != 1 || deleteResp.Errors[0].Code != "AccessDenied" {
+		t.Errorf("%s: Expected multi delete of retained object to fail, got %+v", instanceType, deleteResp)
+	}
+
+	if _, err = obj.GetObjectInfo(bucketName, objectName); err != nil {
+		t.Errorf("%s: Expected retained object to exist, got %v", instanceType, err)
+	}
+
+	// Once retention expires the object can be overwritten and deleted.
+	expiredName := "expired-object"
+	expired := map[string]string{retainUntilMetaKey: time.Now().UTC().Add(-time.Second).Format(time.RFC3339)}
+	if _, err = obj.PutObject(bucketName, expiredName, int64(len(data)), bytes.NewReader(data), expired, ""); err != nil {
+		t.Fatalf("%s: <ERROR> %v", instanceType, err)
+	}
+	execRequest("PUT", getPutObjectURL("", bucketName, expiredName), []byte("world"), nil, http.StatusOK)
+	// The overwrite is retained in turn.
+	execRequest("DELETE", getDeleteObjectURL("", bucketName, expiredName), nil, nil, http.StatusForbidden)
+
+	if _, err = obj.PutObject(bucketName, expiredName+"-delete", int64(len(data)), bytes.NewReader(data), expired, ""); err != nil {
+		t.Fatalf("%s: <ERROR> %v", instanceType, err)
+	}
+	execRequest("DELETE", getDeleteObjectURL("", bucketName, expiredName+"-delete"), nil, nil, http.StatusNoContent)
+}
@@ -0,0 +1,129 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/tls"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// certReloader - serves the certificate of certFile and keyFile to TLS
+// handshakes, reloaded when either file is modified, e.g. by a renewal,
+// or on SIGHUP. Connections are not dropped by a reload, only new
+// handshakes use the new certificate.
+type certReloader struct {
+	certFile, keyFile string
+
+	mutex       *sync.RWMutex // guards all the fields below.
+	cert        *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+}
+
+// newCertReloader - loads the certificate of certFile and keyFile.
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		mutex:    &sync.RWMutex{},
+	}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// getModTimes - returns the modification times of the certificate
+// and the key.
+func (r *certReloader) getModTimes() (certModTime, keyModTime time.Time, err error) {
+	certFi, err := os.Stat(r.certFile)
+	if err != nil {
+		return certModTime, keyModTime, err
+	}
+	keyFi, err := os.Stat(r.keyFile)
+	if err != nil {
+		return certModTime, keyModTime, err
+	}
+	return certFi.ModTime(), keyFi.ModTime(), nil
+}
+
+// isModified - returns true if the certificate or the key were
+// modified since they were last loaded.
+func (r *certReloader) isModified() bool {
+	certModTime, keyModTime, err := r.getModTimes()
+	if err != nil {
+		// Files are being replaced, keep the loaded certificate.
+		return false
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return !certModTime.Equal(r.certModTime) || !keyModTime.Equal(r.keyModTime)
+}
+
+// reload - loads the certificate and the key, the loaded certificate
+// is kept if they are not a valid pair, e.g. while only one of them
+// is replaced.
+func (r *certReloader) reload() error {
+	certModTime, keyModTime, err := r.getModTimes()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.cert = &cert
+	r.certModTime, r.keyModTime = certModTime, keyModTime
+	return nil
+}
+
+// GetCertificate - returns the current certificate, used as
+// tls.Config.GetCertificate.
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if r.isModified() {
+		errorIf(r.reload(), "Unable to reload the certificate %s.", r.certFile)
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.cert, nil
+}
+
+// reloadOnSignal - reloads the certificate on every SIGHUP until
+// doneCh is closed.
+func (r *certReloader) reloadOnSignal(doneCh <-chan struct{}) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+
+	for {
+		select {
+		case <-sigCh:
+			errorIf(r.reload(), "Unable to reload the certificate %s.", r.certFile)
+		case <-doneCh:
+			return
+		}
+	}
+}
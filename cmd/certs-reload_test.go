@@ -0,0 +1,157 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+// Tests new connections are served a certificate replaced on disk.
+func TestListenAndServeCertReload(t *testing.T) {
+	if err := createCertsPath(); err != nil {
+		t.Fatal(err)
+	}
+	certFile := mustGetCertFile()
+	keyFile := mustGetKeyFile()
+	defer os.RemoveAll(certFile)
+	defer os.RemoveAll(keyFile)
+	if err := generateTestCert("127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Initialize done channel specifically for each tests.
+	globalServiceDoneCh = make(chan struct{}, 1)
+	// Initialize signal channel specifically for each tests.
+	globalServiceSignalCh = make(chan serviceSignal, 1)
+
+	addr := net.JoinHostPort("127.0.0.1", getFreePort())
+	m := NewServerMux([]string{addr}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	errc := make(chan error, 1)
+	go func() { errc <- m.ListenAndServe(certFile, keyFile) }()
+	defer m.Close()
+
+	// getServedSerial - returns the serial number of the certificate
+	// served to a new connection.
+	getServedSerial := func() *big.Int {
+		for i := 0; ; i++ {
+			conn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+			if err == nil {
+				defer conn.Close()
+				return conn.ConnectionState().PeerCertificates[0].SerialNumber
+			}
+			select {
+			case serr := <-errc:
+				t.Fatalf("Server failed to start, %v", serr)
+			default:
+			}
+			if i == 100 {
+				t.Fatalf("Unable to connect to the server, %s", err)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	// A connection kept open across the reload.
+	conn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+	for i := 0; err != nil && i < 100; i++ {
+		time.Sleep(10 * time.Millisecond)
+		conn, err = tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	oldSerial := getServedSerial()
+	if served := conn.ConnectionState().PeerCertificates[0].SerialNumber; served.Cmp(oldSerial) != 0 {
+		t.Fatalf("Expected serial %s, got %s", oldSerial, served)
+	}
+
+	// Renew the certificate, modification times are moved forward
+	// for file systems with a coarse time resolution.
+	if err = generateTestCert("127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Now().Add(time.Second)
+	for _, file := range []string{certFile, keyFile} {
+		if err = os.Chtimes(file, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	newSerial := getServedSerial()
+	if newSerial.Cmp(oldSerial) == 0 {
+		t.Fatalf("Expected the renewed certificate to be served, got the old one %s", oldSerial)
+	}
+	// The renewed certificate keeps being served.
+	if served := getServedSerial(); served.Cmp(newSerial) != 0 {
+		t.Fatalf("Expected serial %s, got %s", newSerial, served)
+	}
+}
+
+// Tests an invalid certificate on disk keeps the loaded one served.
+func TestCertReloaderInvalidCert(t *testing.T) {
+	if err := createCertsPath(); err != nil {
+		t.Fatal(err)
+	}
+	certFile := mustGetCertFile()
+	keyFile := mustGetKeyFile()
+	defer os.RemoveAll(certFile)
+	defer os.RemoveAll(keyFile)
+	if err := generateTestCert("127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+
+	reloader, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := reloader.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the key is replaced so far.
+	if err = generateTestCert("127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(certFile, []byte("invalid"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Now().Add(time.Second)
+	if err = os.Chtimes(certFile, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	served, err := reloader.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if served != cert {
+		t.Fatal("Expected the loaded certificate to be kept")
+	}
+	if err = reloader.reload(); err == nil {
+		t.Fatal("Expected reloading an invalid certificate to fail")
+	}
+}
@@ -35,7 +35,11 @@ func createCertsPath() error {
 		return err
 	}
 	rootCAsPath := filepath.Join(certsPath, globalMinioCertsCADir)
-	return os.MkdirAll(rootCAsPath, 0700)
+	if err := os.MkdirAll(rootCAsPath, 0700); err != nil {
+		return err
+	}
+	clientCAsPath := filepath.Join(certsPath, globalMinioCertsClientCADir)
+	return os.MkdirAll(clientCAsPath, 0700)
 }
 
 // getCertsPath get certs path.
@@ -76,6 +80,17 @@ func mustGetCAFiles() (caCerts []string) {
 	return
 }
 
+// mustGetClientCAFiles must get the list of the CA certificates client
+// certificates are verified against, stored in minio config dir
+func mustGetClientCAFiles() (caCerts []string) {
+	CAsDir := filepath.Join(mustGetCertsPath(), globalMinioCertsClientCADir)
+	caFiles, _ := ioutil.ReadDir(CAsDir)
+	for _, cert := range caFiles {
+		caCerts = append(caCerts, filepath.Join(CAsDir, cert.Name()))
+	}
+	return
+}
+
 // mustGetSystemCertPool returns empty cert pool in case of error (windows)
 func mustGetSystemCertPool() *x509.CertPool {
 	pool, err := x509.SystemCertPool()
@@ -148,19 +163,35 @@ func parseCertificateChain(bytes []byte) ([]*x509.Certificate, error) {
 
 // loadRootCAs fetches CA files provided in minio config and adds them to globalRootCAs
 // Currently under Windows, there is no way to load system + user CAs at the same time
+// CA files client certificates are verified against are added to globalClientCAs.
 func loadRootCAs() {
-	caFiles := mustGetCAFiles()
-	if len(caFiles) == 0 {
+	if caFiles := mustGetCAFiles(); len(caFiles) > 0 {
+		// Get system cert pool, and empty cert pool under Windows because it is not supported
+		globalRootCAs = mustGetSystemCertPool()
+		// Load custom root CAs for client requests
+		for _, caFile := range caFiles {
+			caCert, err := ioutil.ReadFile(caFile)
+			if err != nil {
+				fatalIf(err, "Unable to load a CA file")
+			}
+			globalRootCAs.AppendCertsFromPEM(caCert)
+		}
+	}
+
+	// Only these CAs are trusted for client certificates, not the
+	// system ones.
+	clientCAFiles := mustGetClientCAFiles()
+	if len(clientCAFiles) == 0 {
 		return
 	}
-	// Get system cert pool, and empty cert pool under Windows because it is not supported
-	globalRootCAs = mustGetSystemCertPool()
-	// Load custom root CAs for client requests
-	for _, caFile := range caFiles {
+	globalClientCAs = x509.NewCertPool()
+	for _, caFile := range clientCAFiles {
 		caCert, err := ioutil.ReadFile(caFile)
 		if err != nil {
-			fatalIf(err, "Unable to load a CA file")
+			fatalIf(err, "Unable to load a client CA file")
+		}
+		if !globalClientCAs.AppendCertsFromPEM(caCert) {
+			fatalIf(errInvalidArgument, "No certificate found in client CA file %s", caFile)
 		}
-		globalRootCAs.AppendCertsFromPEM(caCert)
 	}
 }
@@ -0,0 +1,73 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Maximum clock skew allowed between the servers of a distributed
+// setup, RPC calls between servers further apart are rejected.
+const maxPeerClockSkew = rpcSkewTimeAllowed
+
+// getPeerClockOffset - returns how far the clock of a peer is ahead of
+// the clock of this server, half of the round trip time is accounted
+// for the time the reply took to arrive.
+func getPeerClockOffset(client BucketMetaState) (time.Duration, error) {
+	start := time.Now().UTC()
+	peerTime, err := client.ServerTime()
+	if err != nil {
+		return 0, err
+	}
+	end := time.Now().UTC()
+	return peerTime.Sub(start.Add(end.Sub(start) / 2)), nil
+}
+
+// checkPeersClockSkew - verifies the clocks of all the peers are within
+// maxSkew of the clock of this server. Peers which can not be reached
+// are skipped, they are reported by the endpoint reachability check.
+func checkPeersClockSkew(peers s3Peers, maxSkew time.Duration) error {
+	offsets := make([]time.Duration, len(peers))
+	errs := make([]error, len(peers))
+	var wg = &sync.WaitGroup{}
+	for index, peer := range peers {
+		wg.Add(1)
+		go func(index int, peer s3Peer) {
+			defer wg.Done()
+			offsets[index], errs[index] = getPeerClockOffset(peer.bmsClient)
+		}(index, peer)
+	}
+	wg.Wait()
+
+	var skewed []string
+	for index, peer := range peers {
+		if errs[index] != nil {
+			continue
+		}
+		offset := offsets[index]
+		if offset > maxSkew || -offset > maxSkew {
+			skewed = append(skewed, fmt.Sprintf("%s (offset %s)", peer.addr, offset))
+		}
+	}
+	if len(skewed) > 0 {
+		return fmt.Errorf("Clock skew of more than %s with %s", maxSkew, strings.Join(skewed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"testing"
+	"time"
+)
+
+// skewedBucketMetaState - peer whose clock is off by offset.
+type skewedBucketMetaState struct {
+	localBucketMetaState
+	offset time.Duration
+	err    error
+}
+
+func (s *skewedBucketMetaState) ServerTime() (time.Time, error) {
+	if s.err != nil {
+		return time.Time{}, s.err
+	}
+	return time.Now().UTC().Add(s.offset), nil
+}
+
+// Tests clock skew of peers is detected.
+func TestCheckPeersClockSkew(t *testing.T) {
+	newPeers := func(offsets ...time.Duration) s3Peers {
+		peers := s3Peers{{"localhost:9000", &localBucketMetaState{ObjectAPI: newObjectLayerFn}}}
+		for i, offset := range offsets {
+			peers = append(peers, s3Peer{
+				addr:      fmt.Sprintf("192.168.1.%d:9000", 11+i),
+				bmsClient: &skewedBucketMetaState{offset: offset},
+			})
+		}
+		return peers
+	}
+
+	testCases := []struct {
+		peers       s3Peers
+		skewedPeers []string
+	}{
+		// Test case - 1.
+		// Clocks within the allowed skew.
+		{newPeers(time.Second, -time.Second), nil},
+		// Test case - 2.
+		// Clock of a peer ahead.
+		{newPeers(time.Second, 10*time.Second), []string{"192.168.1.12:9000"}},
+		// Test case - 3.
+		// Clocks of peers behind and ahead.
+		{newPeers(-time.Minute, time.Hour), []string{"192.168.1.11:9000", "192.168.1.12:9000"}},
+		// Test case - 4.
+		// Unreachable peer is skipped.
+		{append(newPeers(), s3Peer{"192.168.1.11:9000", &skewedBucketMetaState{err: errors.New("connection refused")}}), nil},
+	}
+
+	for i, testCase := range testCases {
+		err := checkPeersClockSkew(testCase.peers, 5*time.Second)
+		if testCase.skewedPeers == nil {
+			if err != nil {
+				t.Errorf("Test %d: Expected no error, got %s", i+1, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Fatalf("Test %d: Expected clock skew to be detected", i+1)
+		}
+		for _, skewedPeer := range testCase.skewedPeers {
+			if !strings.Contains(err.Error(), skewedPeer+" (offset ") {
+				t.Errorf("Test %d: Expected %s to be reported, got %s", i+1, skewedPeer, err)
+			}
+		}
+		if strings.Count(err.Error(), "(offset ") != len(testCase.skewedPeers) {
+			t.Errorf("Test %d: Expected only %v to be reported, got %s", i+1, testCase.skewedPeers, err)
+		}
+	}
+}
+
+// Tests time of a remote peer is fetched over RPC.
+func TestRemoteServerTime(t *testing.T) {
+	testServer, disks := StartTestS3PeerRPCServer(t)
+	defer removeRoots(disks)
+	defer removeAll(testServer.Root)
+	defer testServer.Stop()
+
+	// Login is not required, even invalid credentials are allowed.
+	client := &remoteBucketMetaState{newAuthRPCClient(authConfig{
+		serverAddr:      testServer.Server.Listener.Addr().String(),
+		accessKey:       "invalid",
+		secretKey:       "invalid",
+		serviceEndpoint: path.Join(reservedBucket, s3Path),
+		serviceName:     "S3",
+	})}
+	defer client.Close()
+
+	offset, err := getPeerClockOffset(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offset > time.Second || -offset > time.Second {
+		t.Errorf("Expected offset of the test server to be close to zero, got %s", offset)
+	}
+}
@@ -0,0 +1,192 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Responses smaller than this are not worth compressing.
+const minCompressSize = 1024
+
+// compressHandler - compresses XML responses of bucket and service
+// requests, such as listings, with gzip or deflate for clients
+// accepting it when the server runs with --compress. Object payloads
+// and requests to the reserved bucket, i.e. browser, admin and RPC
+// requests, are never compressed.
+type compressHandler struct {
+	handler http.Handler
+}
+
+func setCompressHandler(h http.Handler) http.Handler {
+	return compressHandler{handler: h}
+}
+
+func (h compressHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !globalIsCompressionEnabled || r.Method != "GET" || strings.HasPrefix(r.URL.Path, reservedBucket+"/") {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+	if _, object := urlPath2BucketObjectName(pathStyleURL(r)); object != "" {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+	encoding := getCompressEncoding(r.Header.Get("Accept-Encoding"))
+	if encoding == "" {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	w.Header().Add("Vary", "Accept-Encoding")
+	cw := &compressResponseWriter{ResponseWriter: w, encoding: encoding}
+	defer cw.close()
+	h.handler.ServeHTTP(cw, r)
+}
+
+// getCompressEncoding - returns the encoding of responses preferred
+// among the ones accepted by Accept-Encoding, empty if none.
+func getCompressEncoding(acceptEncoding string) string {
+	accepted := make(map[string]bool)
+	for _, value := range strings.Split(acceptEncoding, ",") {
+		params := strings.Split(value, ";")
+		encoding := strings.ToLower(strings.TrimSpace(params[0]))
+		accepted[encoding] = true
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+			// Encodings with zero quality are refused.
+			if q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err != nil || q == 0 {
+				accepted[encoding] = false
+			}
+		}
+	}
+	for _, encoding := range []string{"gzip", "deflate"} {
+		if accepted[encoding] {
+			return encoding
+		}
+	}
+	return ""
+}
+
+// compressResponseWriter - compresses successful XML responses of at
+// least minCompressSize bytes, the response is buffered until its size
+// is known or it is flushed.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding string
+
+	statusCode int
+	// Response is eligible for compression, not decided yet if pending.
+	eligible bool
+	pending  bool
+	buf      []byte
+
+	compressor io.WriteCloser
+}
+
+func (cw *compressResponseWriter) WriteHeader(statusCode int) {
+	if cw.statusCode != 0 {
+		return
+	}
+	cw.statusCode = statusCode
+	header := cw.Header()
+	cw.eligible = statusCode == http.StatusOK &&
+		strings.HasPrefix(header.Get("Content-Type"), string(mimeXML)) &&
+		header.Get("Content-Encoding") == ""
+	if !cw.eligible {
+		cw.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+	cw.pending = true
+}
+
+func (cw *compressResponseWriter) Write(p []byte) (int, error) {
+	if cw.statusCode == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.pending {
+		cw.buf = append(cw.buf, p...)
+		if len(cw.buf) < minCompressSize {
+			return len(p), nil
+		}
+		if err := cw.decide(); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if cw.compressor != nil {
+		return cw.compressor.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// decide - writes the header and the buffered response, compressed if
+// it is large enough.
+func (cw *compressResponseWriter) decide() error {
+	cw.pending = false
+	if len(cw.buf) >= minCompressSize {
+		cw.Header().Set("Content-Encoding", cw.encoding)
+		cw.Header().Del("Content-Length")
+		if cw.encoding == "gzip" {
+			cw.compressor = gzip.NewWriter(cw.ResponseWriter)
+		} else {
+			cw.compressor, _ = flate.NewWriter(cw.ResponseWriter, flate.DefaultCompression)
+		}
+	}
+	cw.ResponseWriter.WriteHeader(cw.statusCode)
+	buf := cw.buf
+	cw.buf = nil
+	if cw.compressor != nil {
+		_, err := cw.compressor.Write(buf)
+		return err
+	}
+	_, err := cw.ResponseWriter.Write(buf)
+	return err
+}
+
+// Flush - sends the response written so far, compressed if it is large
+// enough.
+func (cw *compressResponseWriter) Flush() {
+	if cw.pending {
+		cw.decide()
+	}
+	if f, ok := cw.compressor.(interface {
+		Flush() error
+	}); ok {
+		f.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// close - sends the rest of the response, ends the compressed stream.
+func (cw *compressResponseWriter) close() {
+	if cw.pending {
+		cw.decide()
+	}
+	if cw.compressor != nil {
+		cw.compressor.Close()
+	}
+}
@@ -0,0 +1,179 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests selection of the encoding of responses from Accept-Encoding.
+func TestGetCompressEncoding(t *testing.T) {
+	testCases := []struct {
+		acceptEncoding string
+		encoding       string
+	}{
+		// Test case - 1.
+		{"", ""},
+		// Test case - 2.
+		{"gzip", "gzip"},
+		// Test case - 3.
+		// gzip is preferred.
+		{"deflate, gzip", "gzip"},
+		// Test case - 4.
+		{"deflate", "deflate"},
+		// Test case - 5.
+		// Encodings with zero quality are refused.
+		{"gzip;q=0, deflate;q=0.5", "deflate"},
+		// Test case - 6.
+		{"GZIP;q=1.0", "gzip"},
+		// Test case - 7.
+		{"br, identity", ""},
+	}
+	for i, testCase := range testCases {
+		if encoding := getCompressEncoding(testCase.acceptEncoding); encoding != testCase.encoding {
+			t.Errorf("Test %d: Expected encoding `%s`, got `%s`", i+1, testCase.encoding, encoding)
+		}
+	}
+}
+
+// Wrapper for calling response compression tests for both XL multiple disks and single node setup.
+func TestCompressHandler(t *testing.T) {
+	defer func() { globalIsCompressionEnabled = false }()
+	// Handlers are registered in the order of registerAPIRouter, such
+	// that object requests do not match ListObjectsV1.
+	ExecObjectLayerAPITest(t, testCompressHandler, []string{"GetObject", "HeadBucket", "ListObjectsV1"})
+}
+
+func testCompressHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	handler := setCompressHandler(apiRouter)
+
+	// Objects with an XML payload, such that listings are large.
+	data := []byte("<xml>hello</xml>")
+	for i := 0; i < 100; i++ {
+		_, err := obj.PutObject(bucketName, fmt.Sprintf("object-%d", i), int64(len(data)), bytes.NewReader(data),
+			map[string]string{"content-type": "application/xml"}, "")
+		if err != nil {
+			t.Fatalf("%s: Unexpected err: %v", instanceType, err)
+		}
+	}
+
+	// do - signs and sends a request accepting acceptEncoding, returns the recorded response.
+	do := func(method, urlStr, acceptEncoding string) *httptest.ResponseRecorder {
+		req, err := newTestSignedRequestV4(method, urlStr, 0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s %s: <ERROR> %v", instanceType, method, urlStr, err)
+		}
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// listKeys - returns the number of keys listed by ListObjects.
+	listKeys := func(body []byte) int {
+		var listing ListObjectsResponse
+		if err := xml.Unmarshal(body, &listing); err != nil {
+			t.Fatalf("%s: Failed to parse ListObjects response: <ERROR> %v", instanceType, err)
+		}
+		return len(listing.Contents)
+	}
+
+	globalIsCompressionEnabled = true
+
+	// Large listings are compressed if requested.
+	rec := do("GET", "/"+bucketName, "gzip")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected ListObjects to succeed, got %d: %s", instanceType, rec.Code, rec.Body)
+	}
+	if encoding := rec.Header().Get("Content-Encoding"); encoding != "gzip" {
+		t.Fatalf("%s: Expected listing to be gzip encoded, got `%s`", instanceType, encoding)
+	}
+	gzipSize := rec.Body.Len()
+	gr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("%s: Unexpected err: %v", instanceType, err)
+	}
+	body, err := ioutil.ReadAll(gr)
+	if err != nil {
+		t.Fatalf("%s: Unexpected err: %v", instanceType, err)
+	}
+	if keys := listKeys(body); keys != 100 {
+		t.Errorf("%s: Expected 100 keys to be listed, got %d", instanceType, keys)
+	}
+	if gzipSize >= len(body) {
+		t.Errorf("%s: Expected listing of %d bytes to be compressed, got %d bytes", instanceType, len(body), gzipSize)
+	}
+
+	rec = do("GET", "/"+bucketName, "deflate")
+	if encoding := rec.Header().Get("Content-Encoding"); encoding != "deflate" {
+		t.Fatalf("%s: Expected listing to be deflate encoded, got `%s`", instanceType, encoding)
+	}
+	if body, err = ioutil.ReadAll(flate.NewReader(rec.Body)); err != nil {
+		t.Fatalf("%s: Unexpected err: %v", instanceType, err)
+	}
+	if keys := listKeys(body); keys != 100 {
+		t.Errorf("%s: Expected 100 keys to be listed, got %d", instanceType, keys)
+	}
+
+	// Listings are not compressed unless requested.
+	rec = do("GET", "/"+bucketName, "")
+	if encoding := rec.Header().Get("Content-Encoding"); encoding != "" {
+		t.Errorf("%s: Expected listing not to be encoded, got `%s`", instanceType, encoding)
+	}
+	if keys := listKeys(rec.Body.Bytes()); keys != 100 {
+		t.Errorf("%s: Expected 100 keys to be listed, got %d", instanceType, keys)
+	}
+
+	// Small responses are not compressed.
+	rec = do("GET", "/"+bucketName+"?max-keys=1", "gzip")
+	if encoding := rec.Header().Get("Content-Encoding"); encoding != "" {
+		t.Errorf("%s: Expected small listing not to be encoded, got `%s`", instanceType, encoding)
+	}
+	if keys := listKeys(rec.Body.Bytes()); keys != 1 {
+		t.Errorf("%s: Expected 1 key to be listed, got %d", instanceType, keys)
+	}
+
+	// Objects are returned as stored, even with an XML payload.
+	rec = do("GET", "/"+bucketName+"/object-0", "gzip")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected GetObject to succeed, got %d: %s", instanceType, rec.Code, rec.Body)
+	}
+	if encoding := rec.Header().Get("Content-Encoding"); encoding != "" {
+		t.Errorf("%s: Expected object not to be encoded, got `%s`", instanceType, encoding)
+	}
+	if !bytes.Equal(rec.Body.Bytes(), data) {
+		t.Errorf("%s: Expected object content %s, got %s", instanceType, data, rec.Body)
+	}
+
+	// Listings are not compressed if disabled.
+	globalIsCompressionEnabled = false
+	rec = do("GET", "/"+bucketName, "gzip")
+	if encoding := rec.Header().Get("Content-Encoding"); encoding != "" {
+		t.Errorf("%s: Expected listing not to be encoded if disabled, got `%s`", instanceType, encoding)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+)
+
+// Serializes reloads of the config file.
+var configReloadMu sync.Mutex
+
+// checkConfigReloadable - returns an error if newCfg changes settings
+// of curCfg which are only applied at startup. Credentials are changed
+// at runtime with the admin API instead, which also saves them.
+func checkConfigReloadable(curCfg, newCfg *serverConfigV13) error {
+	if !reflect.DeepEqual(curCfg.GetCredential(), newCfg.GetCredential()) {
+		return errors.New("Changing the credential requires a restart of the server")
+	}
+	if !reflect.DeepEqual(curCfg.Logger, newCfg.Logger) {
+		return errors.New("Changing the logger requires a restart of the server")
+	}
+	return checkEncryptedBuckets(newCfg)
+}
+
+// reloadConfig - re-reads the config file and applies it as a whole,
+// such as region, notification targets and bucket quotas. The config
+// in use is kept if the file is not valid, changes settings requiring
+// a restart or its notification targets cannot be initialized.
+func reloadConfig() error {
+	configReloadMu.Lock()
+	defer configReloadMu.Unlock()
+
+	srvCfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	// The config in use is updated in place, such that holders of
+	// serverConfig see the new settings as well.
+	serverConfigMu.Lock()
+	prevCfg := *serverConfig
+	serverConfigMu.Unlock()
+	if err = checkConfigReloadable(&prevCfg, srvCfg); err != nil {
+		return err
+	}
+
+	serverConfigMu.Lock()
+	*serverConfig = *srvCfg
+	serverConfigMu.Unlock()
+	loadAvoidedDisks()
+
+	// Queue ARNs of notification targets contain the region.
+	if globalEventNotifier == nil || (reflect.DeepEqual(prevCfg.Notify, srvCfg.Notify) &&
+		prevCfg.GetRegion() == srvCfg.GetRegion()) {
+		return nil
+	}
+	queueTargets, err := loadAllQueueTargets()
+	if err != nil {
+		serverConfigMu.Lock()
+		*serverConfig = prevCfg
+		serverConfigMu.Unlock()
+		loadAvoidedDisks()
+		return err
+	}
+	// Replaced targets keep connections and workers of their own.
+	closeQueueTargets(globalEventNotifier.SetExternalTargets(queueTargets))
+	return nil
+}
+
+// reloadConfigOnSignal - reloads the config file on every SIGHUP until
+// doneCh is closed. SIGHUP is trapped once this returns.
+func reloadConfigOnSignal(doneCh <-chan struct{}) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(sigCh)
+		for {
+			select {
+			case <-sigCh:
+				errorIf(reloadConfig(), "Unable to reload the config file.")
+			case <-doneCh:
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,128 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// Tests reloadable settings of the config file are applied and the
+// others rejected.
+func TestReloadConfig(t *testing.T) {
+	resetGlobalAvoidedDisks()
+	defer resetGlobalAvoidedDisks()
+
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	// Reloadable settings are applied, to the config in use.
+	srvCfg := serverConfig
+	newCfg := *serverConfig
+	newCfg.Region = "eu-west-1"
+	newCfg.BucketQuotas = map[string]int64{"bucket": 1024}
+	newCfg.AvoidedDisks = []string{"/mnt/disk1"}
+	if err = newCfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err = reloadConfig(); err != nil {
+		t.Fatalf("Expected reload to succeed, got %v", err)
+	}
+	if region := srvCfg.GetRegion(); region != "eu-west-1" {
+		t.Errorf("Expected region eu-west-1, got %s", region)
+	}
+	if quota := serverConfig.GetBucketQuota("bucket"); quota != 1024 {
+		t.Errorf("Expected bucket quota 1024, got %d", quota)
+	}
+	if _, ok := globalAvoidedDisks.disks["/mnt/disk1"]; !ok {
+		t.Error("Expected avoided disk of the config to be applied")
+	}
+
+	// Changes requiring a restart are rejected as a whole.
+	newCfg = *serverConfig
+	newCfg.Region = "us-west-2"
+	newCfg.Credential = newCredential()
+	if err = newCfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err = reloadConfig(); err == nil {
+		t.Fatal("Expected reload changing the credential to fail")
+	}
+	if region := serverConfig.GetRegion(); region != "eu-west-1" {
+		t.Errorf("Expected region eu-west-1 to be kept, got %s", region)
+	}
+
+	// Config files which are not valid are rejected.
+	configFile, err := getConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(configFile, []byte(`{"version": "`+globalMinioConfigVersion+`", "region": `), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = reloadConfig(); err == nil {
+		t.Fatal("Expected reload of a truncated config file to fail")
+	}
+	if region := serverConfig.GetRegion(); region != "eu-west-1" {
+		t.Errorf("Expected region eu-west-1 to be kept, got %s", region)
+	}
+}
+
+// Tests the config file is reloaded on SIGHUP.
+func TestReloadConfigOnSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGHUP is not supported on windows")
+	}
+
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	reloadConfigOnSignal(doneCh)
+
+	newCfg := *serverConfig
+	newCfg.Region = "eu-west-1"
+	if err = newCfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	proc, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = proc.Signal(syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for serverConfig.GetRegion() != "eu-west-1" {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected region eu-west-1 after SIGHUP, got %s", serverConfig.GetRegion())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -19,6 +19,7 @@ package cmd
 import (
 	"os"
 	"sync"
+	"time"
 
 	"github.com/minio/minio/pkg/quick"
 )
@@ -40,6 +41,55 @@ type serverConfigV13 struct {
 
 	// Notification queue configuration.
 	Notify notifier `json:"notify"`
+
+	// Configuration applied to newly created buckets.
+	BucketTemplate *bucketTemplate `json:"bucketTemplate,omitempty"`
+
+	// Maximum number of bytes stored per bucket.
+	BucketQuotas map[string]int64 `json:"bucketQuotas,omitempty"`
+
+	// Disks excluded from placement of new writes.
+	AvoidedDisks []string `json:"avoidedDisks,omitempty"`
+
+	// Maximum size of user metadata per object, zero uses the S3 default.
+	MaxUserMetadataSize int64 `json:"maxUserMetadataSize,omitempty"`
+
+	// Retention period of objects of buckets in WORM mode.
+	WORMBuckets wormBuckets `json:"wormBuckets,omitempty"`
+
+	// Maximum X-Amz-Expires of presigned URLs in seconds, zero uses
+	// the S3 default of a week.
+	MaxPresignExpires int64 `json:"maxPresignExpires,omitempty"`
+
+	// Expiration rules of objects of each bucket.
+	BucketLifecycles bucketLifecycles `json:"bucketLifecycles,omitempty"`
+
+	// Cross-origin requests allowed for each bucket.
+	BucketCors bucketCors `json:"bucketCors,omitempty"`
+
+	// Age in seconds of temporary files purged at startup, zero uses
+	// the default of a day.
+	TmpCleanupAge int64 `json:"tmpCleanupAge,omitempty"`
+
+	// Age in seconds of the last activity of incomplete multipart
+	// uploads aborted, zero uses the default of a week.
+	StaleUploadsExpiry int64 `json:"staleUploadsExpiry,omitempty"`
+
+	// Duration in milliseconds above which completed requests are
+	// logged, zero disables logging of slow requests.
+	SlowRequestThreshold int64 `json:"slowRequestThreshold,omitempty"`
+
+	// Address ranges of clients allowed to access the admin API and
+	// the S3 API.
+	IPAllowList *ipAllowList `json:"ipAllowList,omitempty"`
+
+	// Access keys of the identities authenticated by TLS client
+	// certificates, by subject common name of the certificate.
+	ClientCertIdentities map[string]string `json:"clientCertIdentities,omitempty"`
+
+	// Buckets whose objects are encrypted with the master key of
+	// MINIO_SSE_MASTER_KEY by default.
+	EncryptedBuckets []string `json:"encryptedBuckets,omitempty"`
 }
 
 // initConfig - initialize server config and indicate if we are
@@ -90,32 +140,41 @@ func initConfig() (bool, error) {
 		// Save config into file.
 		return true, serverConfig.Save()
 	}
-	configFile, err := getConfigFile()
+	srvCfg, err := loadConfig()
 	if err != nil {
 		return false, err
 	}
+
+	// hold the mutex lock before a new config is assigned.
+	serverConfigMu.Lock()
+	// Save the loaded config globally.
+	serverConfig = srvCfg
+	serverConfigMu.Unlock()
+
+	return false, nil
+}
+
+// loadConfig - reads the config file without applying it.
+func loadConfig() (*serverConfigV13, error) {
+	configFile, err := getConfigFile()
+	if err != nil {
+		return nil, err
+	}
 	if _, err = os.Stat(configFile); err != nil {
-		return false, err
+		return nil, err
 	}
 	srvCfg := &serverConfigV13{}
 	srvCfg.Version = globalMinioConfigVersion
 	qc, err := quick.New(srvCfg)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	if err := qc.Load(configFile); err != nil {
-		return false, err
+	if err = qc.Load(configFile); err != nil {
+		return nil, err
 	}
-
-	// hold the mutex lock before a new config is assigned.
-	serverConfigMu.Lock()
-	// Save the loaded config globally.
-	serverConfig = srvCfg
-	serverConfigMu.Unlock()
 	// Set the version properly after the unmarshalled json is loaded.
-	serverConfig.Version = globalMinioConfigVersion
-
-	return false, nil
+	srvCfg.Version = globalMinioConfigVersion
+	return srvCfg, nil
 }
 
 // serverConfig server config.
@@ -332,6 +391,358 @@ func (s serverConfigV13) GetRegion() string {
 	return s.Region
 }
 
+// SetBucketTemplate set new bucket template.
+func (s *serverConfigV13) SetBucketTemplate(template *bucketTemplate) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.BucketTemplate = template
+}
+
+// GetBucketTemplate get current bucket template.
+func (s serverConfigV13) GetBucketTemplate() *bucketTemplate {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.BucketTemplate
+}
+
+// SetBucketQuota set new quota of a bucket, zero removes the quota.
+func (s *serverConfigV13) SetBucketQuota(bucket string, quota int64) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	if quota == 0 {
+		delete(s.BucketQuotas, bucket)
+		return
+	}
+	if s.BucketQuotas == nil {
+		s.BucketQuotas = make(map[string]int64)
+	}
+	s.BucketQuotas[bucket] = quota
+}
+
+// GetBucketQuota get current quota of a bucket, zero if not set.
+func (s serverConfigV13) GetBucketQuota(bucket string) int64 {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.BucketQuotas[bucket]
+}
+
+// SetDiskAvoided set whether a disk is excluded from placement of
+// new writes.
+func (s *serverConfigV13) SetDiskAvoided(disk string, avoid bool) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	for i, avoidedDisk := range s.AvoidedDisks {
+		if avoidedDisk != disk {
+			continue
+		}
+		if !avoid {
+			s.AvoidedDisks = append(s.AvoidedDisks[:i], s.AvoidedDisks[i+1:]...)
+		}
+		return
+	}
+	if avoid {
+		s.AvoidedDisks = append(s.AvoidedDisks, disk)
+	}
+}
+
+// GetAvoidedDisks get current disks excluded from new writes.
+func (s serverConfigV13) GetAvoidedDisks() []string {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return append([]string(nil), s.AvoidedDisks...)
+}
+
+// SetBucketRetention set new retention period of objects of a bucket in
+// WORM mode, zero disables WORM mode of the bucket.
+func (s *serverConfigV13) SetBucketRetention(bucket string, retention time.Duration) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	if retention == 0 {
+		delete(s.WORMBuckets, bucket)
+		return
+	}
+	if s.WORMBuckets == nil {
+		s.WORMBuckets = make(wormBuckets)
+	}
+	s.WORMBuckets[bucket] = retention.String()
+}
+
+// GetBucketRetention get current retention period of objects of a
+// bucket, zero if the bucket is not in WORM mode.
+func (s serverConfigV13) GetBucketRetention(bucket string) time.Duration {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.WORMBuckets.getRetention(bucket)
+}
+
+// GetWORMBuckets get current retention periods of buckets in WORM mode.
+func (s serverConfigV13) GetWORMBuckets() wormBuckets {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.WORMBuckets
+}
+
+// SetMaxUserMetadataSize set new maximum size of user metadata, zero
+// restores the default.
+func (s *serverConfigV13) SetMaxUserMetadataSize(size int64) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.MaxUserMetadataSize = size
+}
+
+// GetMaxUserMetadataSize get current maximum size of user metadata.
+func (s serverConfigV13) GetMaxUserMetadataSize() int64 {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	if s.MaxUserMetadataSize <= 0 {
+		return defaultMaxUserMetadataSize
+	}
+	return s.MaxUserMetadataSize
+}
+
+// SetMaxPresignExpiry set new maximum expiry of presigned URLs, zero
+// restores the default.
+func (s *serverConfigV13) SetMaxPresignExpiry(expiry time.Duration) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.MaxPresignExpires = int64(expiry / time.Second)
+}
+
+// GetMaxPresignExpiry get current maximum expiry of presigned URLs.
+func (s serverConfigV13) GetMaxPresignExpiry() time.Duration {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	if s.MaxPresignExpires <= 0 {
+		return defaultMaxPresignExpiry
+	}
+	return time.Duration(s.MaxPresignExpires) * time.Second
+}
+
+// SetTmpCleanupAge set new age of temporary files purged at startup,
+// zero restores the default.
+func (s *serverConfigV13) SetTmpCleanupAge(age time.Duration) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.TmpCleanupAge = int64(age / time.Second)
+}
+
+// GetTmpCleanupAge get current age of temporary files purged at startup.
+func (s serverConfigV13) GetTmpCleanupAge() time.Duration {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	if s.TmpCleanupAge <= 0 {
+		return defaultTmpCleanupAge
+	}
+	return time.Duration(s.TmpCleanupAge) * time.Second
+}
+
+// SetStaleUploadsExpiry set new age of the last activity of multipart
+// uploads aborted, zero restores the default.
+func (s *serverConfigV13) SetStaleUploadsExpiry(expiry time.Duration) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.StaleUploadsExpiry = int64(expiry / time.Second)
+}
+
+// GetStaleUploadsExpiry get current age of the last activity of
+// multipart uploads aborted.
+func (s serverConfigV13) GetStaleUploadsExpiry() time.Duration {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	if s.StaleUploadsExpiry <= 0 {
+		return defaultStaleUploadsExpiry
+	}
+	return time.Duration(s.StaleUploadsExpiry) * time.Second
+}
+
+// SetSlowRequestThreshold set new duration above which completed
+// requests are logged, zero disables logging of slow requests.
+func (s *serverConfigV13) SetSlowRequestThreshold(threshold time.Duration) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.SlowRequestThreshold = int64(threshold / time.Millisecond)
+}
+
+// GetSlowRequestThreshold get current duration above which completed
+// requests are logged, zero if disabled.
+func (s serverConfigV13) GetSlowRequestThreshold() time.Duration {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	if s.SlowRequestThreshold <= 0 {
+		return 0
+	}
+	return time.Duration(s.SlowRequestThreshold) * time.Millisecond
+}
+
+// SetIPAllowList set new address ranges of clients allowed to access
+// the admin API and the S3 API, nil allows all clients.
+func (s *serverConfigV13) SetIPAllowList(allowList *ipAllowList) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.IPAllowList = allowList
+}
+
+// GetIPAllowList get current address ranges of allowed clients.
+func (s serverConfigV13) GetIPAllowList() ipAllowList {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	if s.IPAllowList == nil {
+		return ipAllowList{}
+	}
+	return *s.IPAllowList
+}
+
+// SetClientCertIdentity set the access key of the identity a client
+// certificate with subject common name authenticates as, an empty
+// access key removes it.
+func (s *serverConfigV13) SetClientCertIdentity(commonName, accessKey string) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	if accessKey == "" {
+		delete(s.ClientCertIdentities, commonName)
+		return
+	}
+	if s.ClientCertIdentities == nil {
+		s.ClientCertIdentities = make(map[string]string)
+	}
+	s.ClientCertIdentities[commonName] = accessKey
+}
+
+// GetClientCertIdentity get the access key of the identity a client
+// certificate with subject common name authenticates as, empty if none.
+func (s serverConfigV13) GetClientCertIdentity(commonName string) string {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.ClientCertIdentities[commonName]
+}
+
+// SetBucketLifecycle set new expiration rules of objects of a bucket,
+// no rules removes them.
+func (s *serverConfigV13) SetBucketLifecycle(bucket string, rules []lifecycleRule) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	if len(rules) == 0 {
+		delete(s.BucketLifecycles, bucket)
+		return
+	}
+	if s.BucketLifecycles == nil {
+		s.BucketLifecycles = make(bucketLifecycles)
+	}
+	s.BucketLifecycles[bucket] = rules
+}
+
+// GetBucketLifecycles get current expiration rules of all buckets.
+func (s serverConfigV13) GetBucketLifecycles() bucketLifecycles {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	lifecycles := make(bucketLifecycles, len(s.BucketLifecycles))
+	for bucket, rules := range s.BucketLifecycles {
+		lifecycles[bucket] = rules
+	}
+	return lifecycles
+}
+
+// SetBucketCors set new CORS rules of a bucket, no rules removes them.
+func (s *serverConfigV13) SetBucketCors(bucket string, rules []corsRule) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	if len(rules) == 0 {
+		delete(s.BucketCors, bucket)
+		return
+	}
+	if s.BucketCors == nil {
+		s.BucketCors = make(bucketCors)
+	}
+	s.BucketCors[bucket] = rules
+}
+
+// GetBucketCors get current CORS rules of a bucket.
+func (s serverConfigV13) GetBucketCors(bucket string) []corsRule {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.BucketCors[bucket]
+}
+
+// GetBucketCorsAll get current CORS rules of all buckets.
+func (s serverConfigV13) GetBucketCorsAll() bucketCors {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	cors := make(bucketCors, len(s.BucketCors))
+	for bucket, rules := range s.BucketCors {
+		cors[bucket] = rules
+	}
+	return cors
+}
+
+// SetBucketEncryption set whether objects of a bucket are encrypted by
+// default.
+func (s *serverConfigV13) SetBucketEncryption(bucket string, encrypted bool) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	for i, encryptedBucket := range s.EncryptedBuckets {
+		if encryptedBucket == bucket {
+			if !encrypted {
+				s.EncryptedBuckets = append(s.EncryptedBuckets[:i], s.EncryptedBuckets[i+1:]...)
+			}
+			return
+		}
+	}
+	if encrypted {
+		s.EncryptedBuckets = append(s.EncryptedBuckets, bucket)
+	}
+}
+
+// IsBucketEncrypted get whether objects of a bucket are encrypted by
+// default.
+func (s serverConfigV13) IsBucketEncrypted(bucket string) bool {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	for _, encryptedBucket := range s.EncryptedBuckets {
+		if encryptedBucket == bucket {
+			return true
+		}
+	}
+	return false
+}
+
+// GetEncryptedBuckets get current buckets encrypted by default.
+func (s serverConfigV13) GetEncryptedBuckets() []string {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.EncryptedBuckets
+}
+
 // SetCredentials set new credentials.
 func (s *serverConfigV13) SetCredential(creds credential) {
 	serverConfigMu.Lock()
@@ -0,0 +1,192 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// Time a refused connection has to send the first byte of its
+	// request and read the 503 response.
+	connRefuseTimeout = time.Second
+
+	// Maximum number of refused connections answered at a time, others
+	// are closed right away such that a storm of connections does not
+	// hold more file descriptors.
+	maxRefusingConns = 64
+
+	// First byte of a TLS handshake.
+	tlsHandshakeRecord = 0x16
+)
+
+// Response of connections refused beyond the limit.
+var connRefusedResponse = []byte("HTTP/1.1 503 Service Unavailable\r\n" +
+	"Connection: close\r\nRetry-After: 1\r\nContent-Length: 0\r\n\r\n")
+
+// connLimiter - bounds the number of concurrently open client
+// connections and keeps track of the current and peak counts.
+// Connections from the servers of a distributed setup carry storage,
+// lock and peer RPCs and are exempt.
+type connLimiter struct {
+	mu      sync.Mutex
+	max     int // 0 means unlimited.
+	current int
+	peak    int
+	refused uint64
+	// Number of refused connections being answered.
+	refusing int32
+	// IP addresses of servers.
+	exempt map[string]struct{}
+}
+
+// newConnLimiter - returns a limiter allowing max open connections,
+// max of 0 means unlimited.
+func newConnLimiter(max int) *connLimiter {
+	return &connLimiter{max: max}
+}
+
+// acquire - reserves a slot for a new connection, returns false if
+// the limit is reached.
+func (cl *connLimiter) acquire() bool {
+	if cl == nil {
+		return true
+	}
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	if cl.max > 0 && cl.current >= cl.max {
+		cl.refused++
+		return false
+	}
+	cl.current++
+	if cl.current > cl.peak {
+		cl.peak = cl.current
+	}
+	return true
+}
+
+// release - frees the slot of a closed connection.
+func (cl *connLimiter) release() {
+	if cl == nil {
+		return
+	}
+	cl.mu.Lock()
+	cl.current--
+	cl.mu.Unlock()
+}
+
+// stats - returns current and peak number of open connections and
+// number of connections refused.
+func (cl *connLimiter) stats() (current, peak int, refused uint64) {
+	if cl == nil {
+		return 0, 0, 0
+	}
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	return cl.current, cl.peak, cl.refused
+}
+
+// limitedConn - net.Conn releasing its connLimiter slot on Close.
+type limitedConn struct {
+	net.Conn
+	limiter *connLimiter
+	once    sync.Once
+}
+
+// Close - closes the connection and releases its slot.
+func (c *limitedConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(c.limiter.release)
+	return err
+}
+
+// exemptPeers - exempts connections from the hosts of endpoints from
+// the limit. Hosts which cannot be resolved are skipped.
+func (cl *connLimiter) exemptPeers(endpoints []*url.URL) {
+	if cl == nil {
+		return
+	}
+	exempt := make(map[string]struct{})
+	for _, ep := range endpoints {
+		host, _, err := net.SplitHostPort(ep.Host)
+		if err != nil {
+			host = ep.Host
+		}
+		addrs, err := net.LookupHost(host)
+		if err != nil {
+			errorIf(err, "Unable to resolve %s, its connections are not exempt from --max-connections.", host)
+			continue
+		}
+		for _, addr := range addrs {
+			exempt[addr] = struct{}{}
+		}
+	}
+	cl.mu.Lock()
+	cl.exempt = exempt
+	cl.mu.Unlock()
+}
+
+// isExempt - returns true if conn is from a server exempt from the
+// limit.
+func (cl *connLimiter) isExempt(conn net.Conn) bool {
+	host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return false
+	}
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	_, ok := cl.exempt[host]
+	return ok
+}
+
+// limitConn - reserves a slot for an accepted connection, if the limit
+// is reached the connection is refused and nil returned.
+func (cl *connLimiter) limitConn(conn net.Conn) net.Conn {
+	if cl == nil || cl.isExempt(conn) {
+		return conn
+	}
+	if !cl.acquire() {
+		cl.refuse(conn)
+		return nil
+	}
+	return &limitedConn{Conn: conn, limiter: cl}
+}
+
+// refuse - answers a connection beyond the limit with a 503 in the
+// background and closes it. TLS clients cannot read a plain response,
+// their connections are only closed.
+func (cl *connLimiter) refuse(conn net.Conn) {
+	if atomic.AddInt32(&cl.refusing, 1) > maxRefusingConns {
+		atomic.AddInt32(&cl.refusing, -1)
+		conn.Close()
+		return
+	}
+	go func() {
+		defer atomic.AddInt32(&cl.refusing, -1)
+		defer conn.Close()
+
+		conn.SetDeadline(time.Now().Add(connRefuseTimeout))
+		buf := make([]byte, 1)
+		if _, err := conn.Read(buf); err == nil && buf[0] != tlsHandshakeRecord {
+			conn.Write(connRefusedResponse)
+		}
+	}()
+}
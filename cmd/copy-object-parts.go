@@ -0,0 +1,84 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "io"
+
+// isMultipartCopy - returns true if an object of size bytes is copied
+// as a multipart upload rather than in a single PutObject.
+func isMultipartCopy(size int64) bool {
+	return size > globalCopyPartSize
+}
+
+// copyPartSize - returns the size of the parts of a copy of size
+// bytes, the configured copy part size unless the copy would need
+// more parts than allowed per upload.
+func copyPartSize(size int64) int64 {
+	partSize := globalCopyPartSize
+	maxParts := int64(globalMaxPartID)
+	if minSize := (size + maxParts - 1) / maxParts; minSize > partSize {
+		partSize = minSize
+	}
+	return partSize
+}
+
+// copyObjectParts - copies size bytes of the source object as a
+// multipart upload of the destination through the object layer. Parts
+// are streamed from the source one after the other, such that no more
+// than a part of the object is copied at once whatever its size. The
+// upload is aborted if any of the parts fails.
+func copyObjectParts(objAPI ObjectLayer, srcBucket, srcObject string, size int64, dstBucket, dstObject string, metadata map[string]string) (ObjectInfo, error) {
+	uploadID, err := objAPI.NewMultipartUpload(dstBucket, dstObject, metadata)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	partSize := copyPartSize(size)
+	var parts []completePart
+	for offset := int64(0); offset < size; offset += partSize {
+		length := partSize
+		if size-offset < length {
+			length = size - offset
+		}
+		partID := len(parts) + 1
+		md5Hex, err := copyObjectPart(objAPI, srcBucket, srcObject, offset, length, dstBucket, dstObject, uploadID, partID)
+		if err != nil {
+			objAPI.AbortMultipartUpload(dstBucket, dstObject, uploadID)
+			return ObjectInfo{}, err
+		}
+		parts = append(parts, completePart{PartNumber: partID, ETag: md5Hex})
+	}
+
+	if _, err = objAPI.CompleteMultipartUpload(dstBucket, dstObject, uploadID, parts); err != nil {
+		objAPI.AbortMultipartUpload(dstBucket, dstObject, uploadID)
+		return ObjectInfo{}, err
+	}
+	return objAPI.GetObjectInfo(dstBucket, dstObject)
+}
+
+// copyObjectPart - streams length bytes of the source object at offset
+// into a part of a multipart upload, returns the MD5 sum of the part.
+func copyObjectPart(objAPI ObjectLayer, srcBucket, srcObject string, offset, length int64, dstBucket, dstObject, uploadID string, partID int) (string, error) {
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		pipeWriter.CloseWithError(objAPI.GetObject(srcBucket, srcObject, offset, length, pipeWriter))
+	}()
+	md5Hex, err := objAPI.PutObjectPart(dstBucket, dstObject, uploadID, partID, length, pipeReader, "", "")
+	// Unblock the source if the part failed.
+	pipeReader.CloseWithError(err)
+	return md5Hex, err
+}
@@ -0,0 +1,248 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// copyPartsObjects - object layer serving a generated source object of
+// size bytes and discarding uploaded parts, records the peak amount of
+// data read from the source but not yet written to a part.
+type copyPartsObjects struct {
+	ObjectLayer
+	size     int64
+	failPart int
+
+	mu        sync.Mutex
+	inFlight  int64
+	peak      int64
+	maxRead   int64
+	partSizes []int64
+	completed []completePart
+	aborted   bool
+}
+
+func (c *copyPartsObjects) addInFlight(n int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inFlight += n
+	if c.inFlight > c.peak {
+		c.peak = c.inFlight
+	}
+}
+
+func (c *copyPartsObjects) GetObject(bucket, object string, startOffset int64, length int64, writer io.Writer) error {
+	if startOffset+length > c.size {
+		return errUnexpected
+	}
+	c.mu.Lock()
+	if length > c.maxRead {
+		c.maxRead = length
+	}
+	c.mu.Unlock()
+
+	buf := make([]byte, 32*humanize.KiByte)
+	for length > 0 {
+		n := int64(len(buf))
+		if length < n {
+			n = length
+		}
+		c.addInFlight(n)
+		if _, err := writer.Write(buf[:n]); err != nil {
+			return err
+		}
+		length -= n
+	}
+	return nil
+}
+
+func (c *copyPartsObjects) GetObjectInfo(bucket, object string) (ObjectInfo, error) {
+	return ObjectInfo{Bucket: bucket, Name: object, Size: c.size}, nil
+}
+
+func (c *copyPartsObjects) NewMultipartUpload(bucket, object string, metadata map[string]string) (string, error) {
+	return "upload-id", nil
+}
+
+func (c *copyPartsObjects) PutObjectPart(bucket, object, uploadID string, partID int, size int64, data io.Reader, md5Hex string, sha256sum string) (string, error) {
+	if partID == c.failPart {
+		return "", errors.New("part failed")
+	}
+	buf := make([]byte, 32*humanize.KiByte)
+	var written int64
+	for {
+		n, err := data.Read(buf)
+		c.addInFlight(-int64(n))
+		written += int64(n)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	if written != size {
+		return "", IncompleteBody{}
+	}
+	c.partSizes = append(c.partSizes, written)
+	return fmt.Sprintf("%032x", partID), nil
+}
+
+func (c *copyPartsObjects) CompleteMultipartUpload(bucket, object, uploadID string, parts []completePart) (string, error) {
+	c.completed = parts
+	return "", nil
+}
+
+func (c *copyPartsObjects) AbortMultipartUpload(bucket, object, uploadID string) error {
+	c.aborted = true
+	return nil
+}
+
+// Tests copies of large objects are streamed part by part, keeping no
+// more than a part of the object in memory.
+func TestCopyObjectPartsMemory(t *testing.T) {
+	defer func(copyPartSize int64) { globalCopyPartSize = copyPartSize }(globalCopyPartSize)
+	globalCopyPartSize = 64 * humanize.MiByte
+
+	size := int64(humanize.GiByte + 1)
+	objAPI := &copyPartsObjects{size: size}
+	objInfo, err := copyObjectParts(objAPI, "bucket", "source", size, "bucket", "copy", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.Size != size {
+		t.Errorf("Expected a copy of %d bytes, got %d", size, objInfo.Size)
+	}
+	if len(objAPI.completed) != 17 || len(objAPI.partSizes) != 17 {
+		t.Fatalf("Expected 17 parts, got %d uploaded and %d completed", len(objAPI.partSizes), len(objAPI.completed))
+	}
+	for i, part := range objAPI.completed {
+		if part.PartNumber != i+1 {
+			t.Errorf("Expected part %d, got %d", i+1, part.PartNumber)
+		}
+	}
+	if last := objAPI.partSizes[16]; last != 1 {
+		t.Errorf("Expected a last part of 1 byte, got %d", last)
+	}
+	if objAPI.maxRead > globalCopyPartSize {
+		t.Errorf("Expected reads of at most %d bytes, got %d", globalCopyPartSize, objAPI.maxRead)
+	}
+	if objAPI.peak > globalCopyPartSize {
+		t.Errorf("Expected at most %d bytes in flight, got %d", globalCopyPartSize, objAPI.peak)
+	}
+	if objAPI.aborted {
+		t.Error("Expected the upload not to be aborted")
+	}
+
+	// A failed part aborts the upload.
+	objAPI = &copyPartsObjects{size: size, failPart: 3}
+	if _, err = copyObjectParts(objAPI, "bucket", "source", size, "bucket", "copy", nil); err == nil {
+		t.Fatal("Expected the copy to fail")
+	}
+	if !objAPI.aborted || objAPI.completed != nil {
+		t.Error("Expected the upload to be aborted")
+	}
+}
+
+// Tests part size grows for copies needing more parts than allowed.
+func TestCopyPartSize(t *testing.T) {
+	defer func(copyPartSize int64, maxParts int) {
+		globalCopyPartSize, globalMaxPartID = copyPartSize, maxParts
+	}(globalCopyPartSize, globalMaxPartID)
+	globalCopyPartSize = 64 * humanize.MiByte
+	globalMaxPartID = 10
+
+	testCases := []struct {
+		size             int64
+		expectedPartSize int64
+		expectedCopyMPU  bool
+	}{
+		{64 * humanize.MiByte, 64 * humanize.MiByte, false},
+		{64*humanize.MiByte + 1, 64 * humanize.MiByte, true},
+		{640 * humanize.MiByte, 64 * humanize.MiByte, true},
+		{640*humanize.MiByte + 10, 64*humanize.MiByte + 1, true},
+	}
+	for i, testCase := range testCases {
+		if partSize := copyPartSize(testCase.size); partSize != testCase.expectedPartSize {
+			t.Errorf("Test %d: Expected part size %d, got %d", i+1, testCase.expectedPartSize, partSize)
+		}
+		if isMultipartCopy(testCase.size) != testCase.expectedCopyMPU {
+			t.Errorf("Test %d: Expected multipart copy to be %v", i+1, testCase.expectedCopyMPU)
+		}
+	}
+}
+
+// Wrapper for calling copyObjectParts tests for both XL multiple disks
+// and single node setup.
+func TestCopyObjectParts(t *testing.T) {
+	ExecObjectLayerTest(t, testCopyObjectParts)
+}
+
+// Tests copies in parts through the object layer.
+func testCopyObjectParts(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	defer func(minPartSize, copyPartSize int64) {
+		globalMinPartSize, globalCopyPartSize = minPartSize, copyPartSize
+	}(globalMinPartSize, globalCopyPartSize)
+	globalMinPartSize = humanize.KiByte
+	globalCopyPartSize = humanize.KiByte
+
+	bucket := "bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	data := bytes.Repeat([]byte("abcdefgh"), 5*humanize.KiByte/8+1)
+	if _, err := obj.PutObject(bucket, "source", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	metadata := map[string]string{"content-type": "application/x-copy"}
+	objInfo, err := copyObjectParts(obj, bucket, "source", int64(len(data)), bucket, "copy", metadata)
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if objInfo.Size != int64(len(data)) || !strings.HasSuffix(objInfo.MD5Sum, "-6") {
+		t.Errorf("%s: Expected a copy of %d bytes in 6 parts, got %d bytes with ETag %s", instanceType, len(data), objInfo.Size, objInfo.MD5Sum)
+	}
+	if objInfo.ContentType != "application/x-copy" {
+		t.Errorf("%s: Expected content type of the copy to be kept, got %s", instanceType, objInfo.ContentType)
+	}
+
+	var buf bytes.Buffer
+	if err = obj.GetObject(bucket, "copy", 0, objInfo.Size, &buf); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Errorf("%s: Content of the copy does not match the source", instanceType)
+	}
+
+	result, err := obj.ListMultipartUploads(bucket, "", "", "", "", maxUploadsList)
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if len(result.Uploads) != 0 {
+		t.Errorf("%s: Expected no uploads left, got %d", instanceType, len(result.Uploads))
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Size of the canary object written to each disk by the startup
+// self-test, large enough to span a few sectors.
+const selfTestCanarySize = 64 * 1024
+
+// errSelfTestMismatch - canary object read back differs from the one written.
+var errSelfTestMismatch = errors.New("canary object read back differs from the one written")
+
+// selfTestDisk - writes, reads back, verifies and deletes a canary
+// object in the temporary directory of the meta bucket of disk.
+func selfTestDisk(disk StorageAPI) error {
+	canary := make([]byte, selfTestCanarySize)
+	if _, err := rand.Read(canary); err != nil {
+		return err
+	}
+	canaryPath := "self-test-" + mustGetUUID()
+	if err := disk.AppendFile(minioMetaTmpBucket, canaryPath, canary); err != nil {
+		// Partially written canaries are purged on next startup.
+		return err
+	}
+	buf, err := disk.ReadAll(minioMetaTmpBucket, canaryPath)
+	if err == nil && !bytes.Equal(buf, canary) {
+		err = errSelfTestMismatch
+	}
+	if err != nil {
+		disk.DeleteFile(minioMetaTmpBucket, canaryPath)
+		return err
+	}
+	return disk.DeleteFile(minioMetaTmpBucket, canaryPath)
+}
+
+// selfTestLocalDisks - runs the self-test on all the disks local to
+// this server concurrently, disks of other servers are tested by
+// them. Returns an error listing each disk which failed.
+func selfTestLocalDisks(endpoints []*url.URL, disks []StorageAPI) error {
+	errs := make([]error, len(disks))
+	var wg sync.WaitGroup
+	for index, disk := range disks {
+		if disk == nil || !isLocalStorage(endpoints[index]) {
+			continue
+		}
+		wg.Add(1)
+		go func(index int, disk StorageAPI) {
+			defer wg.Done()
+			errs[index] = selfTestDisk(disk)
+		}(index, disk)
+	}
+	wg.Wait()
+
+	var failed []string
+	for index, err := range errs {
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", disks[index], errorCause(err)))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("Self-test failed on %d disk(s): %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
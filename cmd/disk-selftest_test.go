@@ -0,0 +1,93 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// Tests the startup self-test reports disks failing to write or read
+// the canary object.
+func TestSelfTestLocalDisks(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	nDisks := 16
+	fsDirs, err := getRandomDisks(nDisks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	endpoints, err := parseStorageEndpoints(fsDirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, _, err := initObjectLayer(endpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	disks := obj.(*xlObjects).storageDisks
+
+	// All the disks pass and no canary is left behind.
+	if err = selfTestLocalDisks(endpoints, disks); err != nil {
+		t.Fatalf("Expected self-test to pass, got %v", err)
+	}
+	for _, disk := range disks {
+		entries, lerr := disk.ListDir(minioMetaTmpBucket, "")
+		if lerr != nil {
+			t.Fatal(lerr)
+		}
+		for _, entry := range entries {
+			if strings.HasPrefix(entry, "self-test-") {
+				t.Errorf("Expected canary to be deleted from %s, found %s", disk, entry)
+			}
+		}
+	}
+
+	// Offline disks are skipped.
+	disks[0] = nil
+
+	// Disk failing to write, e.g. mounted read-only.
+	posixDisk, ok := disks[1].(*retryStorage)
+	if !ok {
+		t.Fatal("storage disk is not *retryStorage type")
+	}
+	disks[1] = newNaughtyDisk(posixDisk, map[int]error{1: errDiskAccessDenied}, nil)
+
+	// Disk failing to read back, e.g. with bad sectors.
+	posixDisk, ok = disks[2].(*retryStorage)
+	if !ok {
+		t.Fatal("storage disk is not *retryStorage type")
+	}
+	disks[2] = newNaughtyDisk(posixDisk, map[int]error{2: errFaultyDisk}, nil)
+
+	err = selfTestLocalDisks(endpoints, disks)
+	if err == nil {
+		t.Fatal("Expected self-test to fail")
+	}
+	for _, expected := range []string{"2 disk(s)", disks[1].String(), errDiskAccessDenied.Error(),
+		disks[2].String(), errFaultyDisk.Error()} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected self-test error to contain `%s`, got %v", expected, err)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/console"
+)
+
+const (
+	// With --require-full-mesh remote endpoints which are down are
+	// retried until this timeout elapses, peers are expected to be
+	// started around the same time.
+	endpointReachabilityTimeout = 30 * time.Second
+
+	// Timeout of a single connection attempt to a remote endpoint.
+	endpointDialTimeout = 2 * time.Second
+
+	// Interval between connection attempts to a remote endpoint.
+	endpointRetryInterval = 1 * time.Second
+)
+
+// errUnreachableEndpoints - one or more of the remote endpoints can
+// not be connected to.
+var errUnreachableEndpoints = errors.New("One or more remote endpoints are unreachable")
+
+// getRemoteHosts - returns the sorted list of unique `host:port` of
+// all the remote endpoints.
+func getRemoteHosts(endpoints []*url.URL) []string {
+	hostSet := make(map[string]struct{})
+	for _, ep := range endpoints {
+		if isLocalStorage(ep) {
+			continue
+		}
+		hostSet[ep.Host] = struct{}{}
+	}
+	var hosts []string
+	for host := range hostSet {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// checkHostReachable - connects to a remote host, retrying until it
+// accepts a connection or the timeout elapses. A zero timeout makes
+// a single attempt.
+func checkHostReachable(host string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		conn, err := net.DialTimeout("tcp", host, endpointDialTimeout)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		if time.Now().Add(endpointRetryInterval).After(deadline) {
+			return err
+		}
+		time.Sleep(endpointRetryInterval)
+	}
+}
+
+// checkHostsReachable - connects to all the remote hosts in parallel,
+// returns the error of each host which can not be connected to.
+func checkHostsReachable(hosts []string, timeout time.Duration) []error {
+	errs := make([]error, len(hosts))
+	var wg = &sync.WaitGroup{}
+	for index, host := range hosts {
+		wg.Add(1)
+		go func(index int, host string) {
+			defer wg.Done()
+			errs[index] = checkHostReachable(host, timeout)
+		}(index, host)
+	}
+	wg.Wait()
+	return errs
+}
+
+// printReachabilityMatrix - prints reachability of each remote host
+// from this server, together with the output of the other servers
+// this forms the reachability matrix of the cluster.
+func printReachabilityMatrix(localAddr string, hosts []string, errs []error) {
+	console.Println(colorBlue("\nEndpoint reachability from %s:", localAddr))
+	for index, host := range hosts {
+		status := colorGreen("OK")
+		if errs[index] != nil {
+			status = colorRed(fmt.Sprintf("UNREACHABLE (%s)", errs[index]))
+		}
+		console.Println(fmt.Sprintf("   %s  %s", host, status))
+	}
+}
+
+// checkEndpointsReachable - verifies this server can connect to all
+// the remote endpoints, prints the reachability matrix and returns
+// errUnreachableEndpoints if any of them is down.
+func checkEndpointsReachable(localAddr string, endpoints []*url.URL, timeout time.Duration) error {
+	hosts := getRemoteHosts(endpoints)
+	errs := checkHostsReachable(hosts, timeout)
+	if !globalQuiet {
+		printReachabilityMatrix(localAddr, hosts, errs)
+	}
+	for _, err := range errs {
+		if err != nil {
+			return errUnreachableEndpoints
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Tests unique remote hosts are extracted from endpoints.
+func TestGetRemoteHosts(t *testing.T) {
+	defer func(host, port string) {
+		globalMinioHost, globalMinioPort = host, port
+	}(globalMinioHost, globalMinioPort)
+	globalMinioHost, globalMinioPort = "192.168.1.11", "9000"
+
+	endpoints, err := parseStorageEndpoints([]string{
+		"http://192.168.1.11:9000/mnt/export1",
+		"http://192.168.1.13:9000/mnt/export1",
+		"http://192.168.1.12:9000/mnt/export1",
+		"http://192.168.1.12:9000/mnt/export2",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"192.168.1.12:9000", "192.168.1.13:9000"}
+	if hosts := getRemoteHosts(endpoints); !reflect.DeepEqual(hosts, expected) {
+		t.Errorf("Expected %v, got %v", expected, hosts)
+	}
+}
+
+// Tests reachability of remote endpoints.
+func TestCheckEndpointsReachable(t *testing.T) {
+	defer func(host, port string, quiet bool) {
+		globalMinioHost, globalMinioPort, globalQuiet = host, port, quiet
+	}(globalMinioHost, globalMinioPort, globalQuiet)
+	globalMinioHost, globalMinioPort, globalQuiet = "192.168.1.11", "9000", true
+
+	// Remote endpoint listening for connections.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	// Remote endpoint which is down.
+	downListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	downAddr := downListener.Addr().String()
+	downListener.Close()
+
+	upEndpoint := &url.URL{Scheme: "http", Host: listener.Addr().String(), Path: "/mnt/export"}
+	downEndpoint := &url.URL{Scheme: "http", Host: downAddr, Path: "/mnt/export"}
+
+	if err = checkEndpointsReachable("192.168.1.11:9000", []*url.URL{upEndpoint}, time.Second); err != nil {
+		t.Errorf("Expected all endpoints to be reachable, got %v", err)
+	}
+	if err = checkEndpointsReachable("192.168.1.11:9000", []*url.URL{upEndpoint, downEndpoint}, time.Second); err != errUnreachableEndpoints {
+		t.Errorf("Expected %v, got %v", errUnreachableEndpoints, err)
+	}
+
+	// Without a timeout endpoints which are down are not retried.
+	start := time.Now()
+	if err = checkEndpointsReachable("192.168.1.11:9000", []*url.URL{downEndpoint}, 0); err != errUnreachableEndpoints {
+		t.Errorf("Expected %v, got %v", errUnreachableEndpoints, err)
+	}
+	if elapsed := time.Since(start); elapsed >= endpointRetryInterval {
+		t.Errorf("Expected a single connection attempt, took %s", elapsed)
+	}
+}
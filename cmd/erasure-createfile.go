@@ -21,6 +21,7 @@ import (
 	"hash"
 	"io"
 	"sync"
+	"time"
 
 	"github.com/klauspost/reedsolomon"
 )
@@ -29,11 +30,26 @@ import (
 // all the disks, writes also calculate individual block's checksum
 // for future bit-rot protection.
 func erasureCreateFile(disks []StorageAPI, volume, path string, reader io.Reader, blockSize int64, dataBlocks int, parityBlocks int, algo string, writeQuorum int) (bytesWritten int64, checkSums []string, err error) {
+	bytesWritten, checkSums, _, err = erasureCreateFileSkipLaggards(disks, volume, path, reader, blockSize, dataBlocks, parityBlocks, algo, writeQuorum, 0, nil)
+	return bytesWritten, checkSums, err
+}
+
+// erasureCreateFileSkipLaggards - same as erasureCreateFile, except
+// disks still writing a block laggardTimeout after write quorum was
+// reached are skipped for the rest of the stream, a zero timeout
+// waits for all the disks. Returns the disks which received the whole
+// stream, writes still in progress on skipped disks are tracked by
+// laggardWrites.
+func erasureCreateFileSkipLaggards(disks []StorageAPI, volume, path string, reader io.Reader, blockSize int64, dataBlocks int, parityBlocks int, algo string, writeQuorum int, laggardTimeout time.Duration, laggardWrites *sync.WaitGroup) (bytesWritten int64, checkSums []string, onlineDisks []StorageAPI, err error) {
 	// Allocated blockSized buffer for reading from incoming stream.
 	buf := make([]byte, blockSize)
 
 	hashWriters := newHashWriters(len(disks), algo)
 
+	// Disks are skipped in a copy, input slice is left untouched.
+	onlineDisks = make([]StorageAPI, len(disks))
+	copy(onlineDisks, disks)
+
 	// Read until io.EOF, erasure codes data and writes to all disks.
 	for {
 		var blocks [][]byte
@@ -41,7 +57,7 @@ func erasureCreateFile(disks []StorageAPI, volume, path string, reader io.Reader
 		// FIXME: this is a bug in Golang, n == 0 and err ==
 		// io.ErrUnexpectedEOF for io.ReadFull function.
 		if n == 0 && rErr == io.ErrUnexpectedEOF {
-			return 0, nil, traceError(rErr)
+			return 0, nil, nil, traceError(rErr)
 		}
 		if rErr == io.EOF {
 			// We have reached EOF on the first byte read, io.Reader
@@ -49,28 +65,28 @@ func erasureCreateFile(disks []StorageAPI, volume, path string, reader io.Reader
 			// data. Will create a 0byte file instead.
 			if bytesWritten == 0 {
 				blocks = make([][]byte, len(disks))
-				rErr = appendFile(disks, volume, path, blocks, hashWriters, writeQuorum)
+				rErr = appendFileSkipLaggards(onlineDisks, volume, path, blocks, hashWriters, writeQuorum, laggardTimeout, laggardWrites)
 				if rErr != nil {
-					return 0, nil, rErr
+					return 0, nil, nil, rErr
 				}
 			} // else we have reached EOF after few reads, no need to
 			// add an additional 0bytes at the end.
 			break
 		}
 		if rErr != nil && rErr != io.ErrUnexpectedEOF {
-			return 0, nil, traceError(rErr)
+			return 0, nil, nil, traceError(rErr)
 		}
 		if n > 0 {
 			// Returns encoded blocks.
 			var enErr error
 			blocks, enErr = encodeData(buf[0:n], dataBlocks, parityBlocks)
 			if enErr != nil {
-				return 0, nil, enErr
+				return 0, nil, nil, enErr
 			}
 
 			// Write to all disks.
-			if err = appendFile(disks, volume, path, blocks, hashWriters, writeQuorum); err != nil {
-				return 0, nil, err
+			if err = appendFileSkipLaggards(onlineDisks, volume, path, blocks, hashWriters, writeQuorum, laggardTimeout, laggardWrites); err != nil {
+				return 0, nil, nil, err
 			}
 			bytesWritten += int64(n)
 		}
@@ -78,9 +94,13 @@ func erasureCreateFile(disks []StorageAPI, volume, path string, reader io.Reader
 
 	checkSums = make([]string, len(disks))
 	for i := range checkSums {
+		if disks[i] != nil && onlineDisks[i] == nil {
+			// Skipped disk, its hash may still be written to.
+			continue
+		}
 		checkSums[i] = hex.EncodeToString(hashWriters[i].Sum(nil))
 	}
-	return bytesWritten, checkSums, nil
+	return bytesWritten, checkSums, onlineDisks, nil
 }
 
 // encodeData - encodes incoming data buffer into
@@ -109,20 +129,37 @@ func encodeData(dataBuffer []byte, dataBlocks, parityBlocks int) ([][]byte, erro
 
 // appendFile - append data buffer at path.
 func appendFile(disks []StorageAPI, volume, path string, enBlocks [][]byte, hashWriters []hash.Hash, writeQuorum int) (err error) {
+	return appendFileSkipLaggards(disks, volume, path, enBlocks, hashWriters, writeQuorum, 0, nil)
+}
+
+// appendResult - result of appending a block to a single disk.
+type appendResult struct {
+	index int
+	err   error
+}
+
+// appendFileSkipLaggards - append data buffer at path. With a non
+// zero laggardTimeout, disks which have not finished the append
+// laggardTimeout after write quorum was reached are set to nil in
+// disks and their pending appends are added to laggardWrites.
+func appendFileSkipLaggards(disks []StorageAPI, volume, path string, enBlocks [][]byte, hashWriters []hash.Hash, writeQuorum int, laggardTimeout time.Duration, laggardWrites *sync.WaitGroup) (err error) {
 	var wg = &sync.WaitGroup{}
 	var wErrs = make([]error, len(disks))
+	var resultCh = make(chan appendResult, len(disks))
+	var pending = make(map[int]struct{})
 	// Write encoded data to quorum disks in parallel.
 	for index, disk := range disks {
 		if disk == nil {
 			continue
 		}
+		pending[index] = struct{}{}
 		wg.Add(1)
 		// Write encoded data in routine.
 		go func(index int, disk StorageAPI) {
 			defer wg.Done()
 			wErr := disk.AppendFile(volume, path, enBlocks[index])
 			if wErr != nil {
-				wErrs[index] = traceError(wErr)
+				resultCh <- appendResult{index, traceError(wErr)}
 				return
 			}
 
@@ -130,12 +167,43 @@ func appendFile(disks []StorageAPI, volume, path string, enBlocks [][]byte, hash
 			hashWriters[index].Write(enBlocks[index])
 
 			// Successfully wrote.
-			wErrs[index] = nil
+			resultCh <- appendResult{index, nil}
 		}(index, disk)
 	}
 
-	// Wait for all the appends to finish.
-	wg.Wait()
+	// Wait for all the appends to finish, or for the laggards to
+	// time out once write quorum is reached.
+	var timeoutCh <-chan time.Time
+	successes := 0
+	for len(pending) > 0 {
+		select {
+		case result := <-resultCh:
+			delete(pending, result.index)
+			wErrs[result.index] = result.err
+			if result.err != nil {
+				continue
+			}
+			successes++
+			if laggardTimeout > 0 && successes == writeQuorum {
+				timer := time.NewTimer(laggardTimeout)
+				defer timer.Stop()
+				timeoutCh = timer.C
+			}
+		case <-timeoutCh:
+			// Skip laggards for the rest of the stream, the blocks
+			// written to them are discarded.
+			for index := range pending {
+				disks[index] = nil
+				wErrs[index] = errDiskNotFound
+			}
+			pending = nil
+			laggardWrites.Add(1)
+			go func() {
+				wg.Wait()
+				laggardWrites.Done()
+			}()
+		}
+	}
 
 	// Do we have write quorum?.
 	if !isDiskQuorum(wErrs, writeQuorum) {
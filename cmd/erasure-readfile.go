@@ -110,6 +110,77 @@ func getReadDisks(orderedDisks []StorageAPI, index int, dataBlocks int) (readDis
 	return nil, 0, traceError(errXLReadQuorum)
 }
 
+// Return readable disks slice from which we can read parallelly,
+// preferring disks in the local zone. Disks in other zones are read
+// only when the local zone does not have enough shards left.
+func getPreferredReadDisks(orderedDisks []StorageAPI, enBlocks [][]byte, preferred []bool, dataBlocks int) (readDisks []StorageAPI, err error) {
+	readDisks = make([]StorageAPI, len(orderedDisks))
+	// Count already read data and parity chunks.
+	needed := dataBlocks
+	for _, enBlock := range enBlocks {
+		if enBlock != nil {
+			needed--
+		}
+	}
+
+	// Sanity check - we should never have this situation.
+	if needed <= 0 {
+		return nil, traceError(errUnexpected)
+	}
+
+	// Local disks are picked first, data disks come before parity
+	// disks within each zone such that decoding is avoided when
+	// possible.
+	for _, local := range []bool{true, false} {
+		for i := range orderedDisks {
+			if needed == 0 {
+				return readDisks, nil
+			}
+			if orderedDisks[i] == nil || enBlocks[i] != nil || preferred[i] != local {
+				continue
+			}
+			readDisks[i] = orderedDisks[i]
+			needed--
+		}
+	}
+	if needed > 0 {
+		return nil, traceError(errXLReadQuorum)
+	}
+	return readDisks, nil
+}
+
+// Return the data disks holding chunks firstChunk to lastChunk of a
+// block, if the requested range can be read from only those disks.
+// Returns nil if all the data disks are needed, any of them is not
+// available or, when disks are preferred, outside the preferred disks.
+func getRangeReadDisks(orderedDisks []StorageAPI, preferred []bool, firstChunk, lastChunk, dataBlocks int) []StorageAPI {
+	if lastChunk-firstChunk+1 >= dataBlocks {
+		return nil
+	}
+	readDisks := make([]StorageAPI, len(orderedDisks))
+	for i := firstChunk; i <= lastChunk; i++ {
+		if orderedDisks[i] == nil {
+			return nil
+		}
+		if preferred != nil && !preferred[i] {
+			return nil
+		}
+		readDisks[i] = orderedDisks[i]
+	}
+	return readDisks
+}
+
+// isSuccessRangeBlocks - returns true if chunks firstChunk to
+// lastChunk of a block were read successfully.
+func isSuccessRangeBlocks(enBlocks [][]byte, firstChunk, lastChunk int) bool {
+	for i := firstChunk; i <= lastChunk; i++ {
+		if enBlocks[i] == nil {
+			return false
+		}
+	}
+	return true
+}
+
 // parallelRead - reads chunks in parallel from the disks specified in []readDisks.
 func parallelRead(volume, path string, readDisks []StorageAPI, orderedDisks []StorageAPI, enBlocks [][]byte, blockOffset int64, curChunkSize int64, bitRotVerify func(diskIndex int) bool, pool *bpool.BytePool) {
 	// WaitGroup to synchronise the read go-routines.
@@ -159,6 +230,13 @@ func parallelRead(volume, path string, readDisks []StorageAPI, orderedDisks []St
 // then written to given writer. This function also supports bit-rot detection by
 // verifying checksum of individual block's checksum.
 func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path string, offset int64, length int64, totalLength int64, blockSize int64, dataBlocks int, parityBlocks int, checkSums []string, algo string, pool *bpool.BytePool) (int64, error) {
+	return erasureReadFilePreferred(writer, disks, volume, path, offset, length, totalLength, blockSize, dataBlocks, parityBlocks, checkSums, algo, pool, nil)
+}
+
+// erasureReadFilePreferred - same as erasureReadFile, except that shards
+// are read from disks marked in preferred[] whenever enough of them are
+// available. A nil preferred reads disks in order.
+func erasureReadFilePreferred(writer io.Writer, disks []StorageAPI, volume string, path string, offset int64, length int64, totalLength int64, blockSize int64, dataBlocks int, parityBlocks int, checkSums []string, algo string, pool *bpool.BytePool, preferred []bool) (int64, error) {
 	// Offset and length cannot be negative.
 	if offset < 0 || length < 0 {
 		return 0, traceError(errUnexpected)
@@ -227,26 +305,87 @@ func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path s
 		// then it can result in wrong offset for the last block.
 		blockOffset := block * chunkSize
 
+		// Offset in enBlocks from where data should be read from.
+		enBlocksOffset := int64(0)
+
+		// Total data to be read from enBlocks.
+		enBlocksLength := curBlockSize
+
+		// If this is the start block then enBlocksOffset might not be 0.
+		if block == startBlock {
+			enBlocksOffset = offset % blockSize
+			enBlocksLength -= enBlocksOffset
+		}
+
+		remaining := length - bytesWritten
+		if remaining < enBlocksLength {
+			// We should not send more data than what was requested.
+			enBlocksLength = remaining
+		}
+
+		// Blocks to write data from, all the data blocks unless the
+		// requested range was read from only some of them.
+		writeBlocks := enBlocks
+		writeDataCount := dataBlocks
+		writeOffset := enBlocksOffset
+
+		// Disks to prefer while reading, once a range read fails all
+		// the disks not yet read are preferred equally.
+		readFrom := preferred
+
+		// A range within a few data chunks of the block, e.g. probes
+		// of the first byte, is read only from the data disks holding
+		// those chunks. Other disks are read only if one of them fails.
+		rangeRead := false
+		if enBlocksLength > 0 {
+			firstChunk := int(enBlocksOffset / curChunkSize)
+			lastChunk := int((enBlocksOffset + enBlocksLength - 1) / curChunkSize)
+			if readDisks := getRangeReadDisks(disks, preferred, firstChunk, lastChunk, dataBlocks); readDisks != nil {
+				if preferred != nil {
+					globalEndpointZones.countReads(readDisks, preferred)
+				}
+				parallelRead(volume, path, readDisks, disks, enBlocks, blockOffset, curChunkSize, bitRotVerify, pool)
+				rangeRead = isSuccessRangeBlocks(enBlocks, firstChunk, lastChunk)
+				if rangeRead {
+					writeBlocks = enBlocks[firstChunk : lastChunk+1]
+					writeDataCount = lastChunk - firstChunk + 1
+					writeOffset = enBlocksOffset - int64(firstChunk)*curChunkSize
+				} else if readFrom == nil {
+					readFrom = make([]bool, len(disks))
+					for index := range readFrom {
+						readFrom[index] = true
+					}
+				}
+			}
+		}
+
 		// nextIndex - index from which next set of parallel reads
 		// should happen.
 		nextIndex := 0
 
-		for {
+		for !rangeRead {
 			// readDisks - disks from which we need to read in parallel.
 			var readDisks []StorageAPI
 			var err error
 			// get readable disks slice from which we can read parallelly.
-			readDisks, nextIndex, err = getReadDisks(disks, nextIndex, dataBlocks)
+			if readFrom != nil {
+				readDisks, err = getPreferredReadDisks(disks, enBlocks, readFrom, dataBlocks)
+			} else {
+				readDisks, nextIndex, err = getReadDisks(disks, nextIndex, dataBlocks)
+			}
 			if err != nil {
 				return bytesWritten, err
 			}
+			if preferred != nil {
+				globalEndpointZones.countReads(readDisks, preferred)
+			}
 			// Issue a parallel read across the disks specified in readDisks.
 			parallelRead(volume, path, readDisks, disks, enBlocks, blockOffset, curChunkSize, bitRotVerify, pool)
 			if isSuccessDecodeBlocks(enBlocks, dataBlocks) {
 				// If enough blocks are available to do rs.Reconstruct()
 				break
 			}
-			if nextIndex == len(disks) {
+			if readFrom == nil && nextIndex == len(disks) {
 				// No more disks to read from.
 				return bytesWritten, traceError(errXLReadQuorum)
 			}
@@ -255,33 +394,15 @@ func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path s
 		}
 
 		// If we have all the data blocks no need to decode, continue to write.
-		if !isSuccessDataBlocks(enBlocks, dataBlocks) {
+		if !rangeRead && !isSuccessDataBlocks(enBlocks, dataBlocks) {
 			// Reconstruct the missing data blocks.
 			if err := decodeData(enBlocks, dataBlocks, parityBlocks); err != nil {
 				return bytesWritten, err
 			}
 		}
 
-		// Offset in enBlocks from where data should be read from.
-		enBlocksOffset := int64(0)
-
-		// Total data to be read from enBlocks.
-		enBlocksLength := curBlockSize
-
-		// If this is the start block then enBlocksOffset might not be 0.
-		if block == startBlock {
-			enBlocksOffset = offset % blockSize
-			enBlocksLength -= enBlocksOffset
-		}
-
-		remaining := length - bytesWritten
-		if remaining < enBlocksLength {
-			// We should not send more data than what was requested.
-			enBlocksLength = remaining
-		}
-
 		// Write data blocks.
-		n, err := writeDataBlocks(writer, enBlocks, dataBlocks, enBlocksOffset, enBlocksLength)
+		n, err := writeDataBlocks(writer, writeBlocks, writeDataCount, writeOffset, enBlocksLength)
 		if err != nil {
 			return bytesWritten, err
 		}
@@ -469,3 +469,70 @@ func TestErasureReadFileRandomOffsetLength(t *testing.T) {
 		buf.Reset()
 	}
 }
+
+// Tests small ranges are read only from the data disks holding them,
+// falling back to the other disks when one of them is not available.
+func TestErasureReadFileSmallRange(t *testing.T) {
+	dataBlocks, parityBlocks := 4, 4
+	setup, err := newErasureTestSetup(dataBlocks, parityBlocks, blockSizeV1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer setup.Remove()
+
+	data := make([]byte, 1*humanize.MiByte)
+	if _, err = rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	length := int64(len(data))
+	_, checkSums, err := erasureCreateFile(setup.disks, "testbucket", "testobject", bytes.NewReader(data), blockSizeV1, dataBlocks, parityBlocks, bitRotAlgo, dataBlocks+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := bpool.NewBytePool(getChunkSize(blockSizeV1, dataBlocks), len(setup.disks))
+
+	testCases := []struct {
+		offset, length int64
+		failed         int   // disk not available, -1 for none.
+		readDisks      []int // only disks expected to be read, nil for any.
+	}{
+		// First byte is read from the first data disk.
+		{0, 1, -1, []int{0}},
+		// Last byte is read from the last data disk.
+		{length - 1, 1, -1, []int{3}},
+		// Range spanning two data chunks.
+		{length/4 - 1, 2, -1, []int{0, 1}},
+		// First data disk is down, other disks are read.
+		{0, 1, 0, nil},
+	}
+	for i, testCase := range testCases {
+		counting := make([]*readCountingDisk, len(setup.disks))
+		disks := make([]StorageAPI, len(setup.disks))
+		for index := range setup.disks {
+			counting[index] = &readCountingDisk{StorageAPI: setup.disks[index]}
+			disks[index] = counting[index]
+		}
+		if testCase.failed >= 0 {
+			disks[testCase.failed] = nil
+		}
+		buf := &bytes.Buffer{}
+		if _, err = erasureReadFile(buf, disks, "testbucket", "testobject", testCase.offset, testCase.length, length, blockSizeV1, dataBlocks, parityBlocks, checkSums, bitRotAlgo, pool); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if !bytes.Equal(buf.Bytes(), data[testCase.offset:testCase.offset+testCase.length]) {
+			t.Fatalf("Test %d: Contents of the range read are not the same", i+1)
+		}
+		if testCase.readDisks == nil {
+			continue
+		}
+		expected := make(map[int]bool)
+		for _, index := range testCase.readDisks {
+			expected[index] = true
+		}
+		for index, disk := range counting {
+			if (disk.shardReads != 0) != expected[index] {
+				t.Errorf("Test %d: Disk %d read %d times, expected to be read %v", i+1, index, disk.shardReads, expected[index])
+			}
+		}
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"net"
 	"net/url"
 	"path"
@@ -162,12 +163,41 @@ func newNotificationEvent(event eventData) NotificationEvent {
 	return nEvent
 }
 
-// Fetch the external target. No locking needed here since this map is
-// never written after initial startup.
-func (en eventNotifier) GetExternalTarget(queueARN string) *logrus.Logger {
+// Fetch the external target.
+func (en *eventNotifier) GetExternalTarget(queueARN string) *logrus.Logger {
+	en.external.rwMutex.RLock()
+	defer en.external.rwMutex.RUnlock()
 	return en.external.targets[queueARN]
 }
 
+// Replace all the external targets, done on reload of the config.
+// Returns the replaced targets.
+func (en *eventNotifier) SetExternalTargets(targets map[string]*logrus.Logger) map[string]*logrus.Logger {
+	en.external.rwMutex.Lock()
+	defer en.external.rwMutex.Unlock()
+	prevTargets := en.external.targets
+	en.external.targets = targets
+	return prevTargets
+}
+
+// closeQueueTargets - closes the connections of queue targets which
+// are no longer in use, and stops their background workers. Hooks of
+// all targets fire on the info level.
+func closeQueueTargets(targets map[string]*logrus.Logger) {
+	for _, target := range targets {
+		for _, hook := range target.Hooks[logrus.InfoLevel] {
+			switch h := hook.(type) {
+			case io.Closer:
+				h.Close()
+			case interface {
+				Close()
+			}:
+				h.Close()
+			}
+		}
+	}
+}
+
 func (en eventNotifier) GetInternalTarget(arn string) *listenerLogger {
 	en.internal.rwMutex.RLock()
 	defer en.internal.rwMutex.RUnlock()
@@ -595,7 +595,7 @@ func TestAddRemoveBucketListenerConfig(t *testing.T) {
 	}
 
 	for i, test := range testCases {
-		err := AddBucketListenerConfig(randBucket, test.lCfg, obj)
+		err := AddBucketListenerConfig(randBucket, test.lCfg, obj, "")
 		if err != test.expectedErr {
 			t.Errorf(
 				"Test %d: Failed with error %v, expected to fail with %v",
@@ -605,7 +605,7 @@ func TestAddRemoveBucketListenerConfig(t *testing.T) {
 	}
 
 	// test remove listener actually removes a listener
-	RemoveBucketListenerConfig(randBucket, sampleListenerCfg, obj)
+	RemoveBucketListenerConfig(randBucket, sampleListenerCfg, obj, "")
 	// since it does not return errors we fetch the config and
 	// check
 	lcSlice := globalEventNotifier.GetBucketListenerConfig(randBucket)
@@ -21,7 +21,13 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/console"
 )
 
 // fsFormat - structure holding 'fs' format.
@@ -36,6 +42,9 @@ type xlFormat struct {
 	// JBOD field carries the input disk order generated the first
 	// time when fresh disks were supplied.
 	JBOD []string `json:"jbod"`
+	// Parity field carries the parity blocks objects are erasure
+	// coded with, zero for half the disks.
+	Parity int `json:"parity,omitempty"`
 }
 
 // formatConfigV1 - structure holds format config version '1'.
@@ -163,7 +172,42 @@ func loadFormatFS(storageDisk StorageAPI) (format *formatConfigV1, err error) {
 	return loadFormat(storageDisk)
 }
 
-// loadAllFormats - load all format config from all input disks in parallel.
+// Maximum number of disks format.json is loaded from concurrently.
+const formatLoadConcurrency = 16
+
+// Maximum time to load format.json from a disk, a disk not responding
+// in time is treated as offline. Variable to allow tests to change it.
+var formatLoadTimeout = 20 * time.Second
+
+// loadFormatTimeout - loads format.json from disk, returns errDiskNotFound
+// if the disk does not respond within timeout.
+func loadFormatTimeout(disk StorageAPI, timeout time.Duration) (*formatConfigV1, error) {
+	type loadResult struct {
+		format *formatConfigV1
+		err    error
+	}
+	// Buffered so that a load completing after the timeout
+	// does not block forever.
+	resultCh := make(chan loadResult, 1)
+	go func() {
+		format, err := loadFormat(disk)
+		resultCh <- loadResult{format, err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case result := <-resultCh:
+		return result.format, result.err
+	case <-timer.C:
+		errorIf(errDiskNotFound, "Timed out loading format.json from %s after %s.", disk, timeout)
+		return nil, errDiskNotFound
+	}
+}
+
+// loadAllFormats - load all format config from all input disks in parallel,
+// at most formatLoadConcurrency disks at a time. Disks not responding
+// within formatLoadTimeout are reported with errDiskNotFound.
 func loadAllFormats(bootstrapDisks []StorageAPI) ([]*formatConfigV1, []error) {
 	// Initialize sync waitgroup.
 	var wg = &sync.WaitGroup{}
@@ -174,17 +218,23 @@ func loadAllFormats(bootstrapDisks []StorageAPI) ([]*formatConfigV1, []error) {
 	// Initialize format configs.
 	var formatConfigs = make([]*formatConfigV1, len(bootstrapDisks))
 
-	// Make a volume entry on all underlying storage disks.
+	// Bounds the number of disks loaded concurrently.
+	var loadSlots = make(chan struct{}, formatLoadConcurrency)
+
+	// Load format.json from all underlying storage disks.
 	for index, disk := range bootstrapDisks {
 		if disk == nil {
 			sErrs[index] = errDiskNotFound
 			continue
 		}
 		wg.Add(1)
-		// Make a volume inside a go-routine.
+		// Load format.json inside a go-routine.
 		go func(index int, disk StorageAPI) {
 			defer wg.Done()
-			formatConfig, lErr := loadFormat(disk)
+			loadSlots <- struct{}{}
+			defer func() { <-loadSlots }()
+
+			formatConfig, lErr := loadFormatTimeout(disk, formatLoadTimeout)
 			if lErr != nil {
 				sErrs[index] = lErr
 				return
@@ -193,16 +243,10 @@ func loadAllFormats(bootstrapDisks []StorageAPI) ([]*formatConfigV1, []error) {
 		}(index, disk)
 	}
 
-	// Wait for all make vol to finish.
+	// Wait for all loads to finish or time out.
 	wg.Wait()
 
-	for _, err := range sErrs {
-		if err != nil {
-			// Return all formats and errors.
-			return formatConfigs, sErrs
-		}
-	}
-	// Return all formats and nil
+	// Return all formats and errors.
 	return formatConfigs, sErrs
 }
 
@@ -525,6 +569,7 @@ func healFormatXLFreshDisks(storageDisks []StorageAPI) error {
 				Version: referenceConfig.XL.Version,
 				Disk:    newJBOD[index],
 				JBOD:    newJBOD,
+				Parity:  referenceConfig.XL.Parity,
 			},
 		}
 		newFormatConfigs[index] = config
@@ -733,6 +778,7 @@ func healFormatXLCorruptedDisks(storageDisks []StorageAPI) error {
 				Version: referenceConfig.XL.Version,
 				Disk:    newJBOD[index],
 				JBOD:    newJBOD,
+				Parity:  referenceConfig.XL.Parity,
 			},
 		}
 		newFormatConfigs[index] = config
@@ -806,7 +852,7 @@ func checkFormatXLValues(formatConfigs []*formatConfigV1) error {
 		if formatXL.Format != "xl" {
 			return fmt.Errorf("Unsupported backend format [%s] found", formatXL.Format)
 		}
-		if formatXL.XL.Version != "1" {
+		if !isSupportedFormatXLVersion(formatXL.XL.Version) {
 			return fmt.Errorf("Unsupported XL backend format found [%s]", formatXL.XL.Version)
 		}
 		if len(formatConfigs) != len(formatXL.XL.JBOD) {
@@ -816,6 +862,125 @@ func checkFormatXLValues(formatConfigs []*formatConfigV1) error {
 	return nil
 }
 
+// checkFormatXLParity - verifies formatted disks are erasure coded
+// with the configured parity, zero stands for half the disks.
+func checkFormatXLParity(formatConfigs []*formatConfigV1, parity int) error {
+	_, parity = getDataParityBlocks(len(formatConfigs), parity)
+	for _, formatXL := range formatConfigs {
+		if formatXL == nil || formatXL.XL == nil {
+			continue
+		}
+		_, formatParity := getDataParityBlocks(len(formatConfigs), formatXL.XL.Parity)
+		if formatParity != parity {
+			return fmt.Errorf("Storage class parity %d did not match the backend format parity %d", parity, formatParity)
+		}
+	}
+	return nil
+}
+
+// List of XL backend format versions supported by this server.
+var supportedFormatXLVersions = []string{"1"}
+
+// isSupportedFormatXLVersion - returns true if the XL backend format
+// version is understood by this server.
+func isSupportedFormatXLVersion(version string) bool {
+	for _, supportedVersion := range supportedFormatXLVersions {
+		if version == supportedVersion {
+			return true
+		}
+	}
+	return false
+}
+
+// formatXLVersionMismatch - returned when formatted disks carry
+// different XL backend format versions.
+type formatXLVersionMismatch struct {
+	// List of disks for each of the versions found.
+	disksByVersion map[string][]string
+}
+
+func (e formatXLVersionMismatch) Error() string {
+	var versions []string
+	for version := range e.disksByVersion {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	var report []string
+	for _, version := range versions {
+		report = append(report, fmt.Sprintf("version %s on %s", version, strings.Join(e.disksByVersion[version], ", ")))
+	}
+	return fmt.Sprintf("XL backend format version mismatch found: %s", strings.Join(report, "; "))
+}
+
+// checkFormatXLVersions - verifies that all formatted disks are on the
+// same XL backend format version. Disks on a lower version are upgraded
+// to the highest version found only when autoUpgrade is set and a quorum
+// of disks is already on the highest version, this prevents a minority
+// of disks from dictating the migration. In all other cases an error
+// reporting the version of each disk is returned.
+func checkFormatXLVersions(storageDisks []StorageAPI, formatConfigs []*formatConfigV1, autoUpgrade bool) error {
+	disksByVersion := make(map[string][]string)
+	highestVersion, highestNumber := "", -1
+	for index, format := range formatConfigs {
+		if format == nil || format.XL == nil {
+			continue
+		}
+		number, err := strconv.Atoi(format.XL.Version)
+		if err != nil {
+			return fmt.Errorf("Unsupported XL backend format found [%s]", format.XL.Version)
+		}
+		disksByVersion[format.XL.Version] = append(disksByVersion[format.XL.Version], storageDisks[index].String())
+		if number > highestNumber {
+			highestVersion, highestNumber = format.XL.Version, number
+		}
+	}
+
+	// All formatted disks agree on the version.
+	if len(disksByVersion) <= 1 {
+		return nil
+	}
+
+	mismatchErr := formatXLVersionMismatch{disksByVersion}
+	if !autoUpgrade {
+		return mismatchErr
+	}
+	quorum := len(storageDisks)/2 + 1
+	if len(disksByVersion[highestVersion]) < quorum {
+		return fmt.Errorf("%s, not upgrading since only %d disks of required %d are on version %s",
+			mismatchErr, len(disksByVersion[highestVersion]), quorum, highestVersion)
+	}
+	if !isSupportedFormatXLVersion(highestVersion) {
+		return fmt.Errorf("Unsupported XL backend format found [%s]", highestVersion)
+	}
+
+	// Upgrade disks on lower versions, all the other fields are retained.
+	upgradeDisks := make([]StorageAPI, len(storageDisks))
+	upgradeFormats := make([]*formatConfigV1, len(storageDisks))
+	for index, format := range formatConfigs {
+		if format == nil || format.XL == nil || format.XL.Version == highestVersion {
+			continue
+		}
+		newFormat := *format
+		newXLFormat := *format.XL
+		newXLFormat.Version = highestVersion
+		newFormat.XL = &newXLFormat
+		upgradeDisks[index] = storageDisks[index]
+		upgradeFormats[index] = &newFormat
+	}
+	if err := saveFormatXL(upgradeDisks, upgradeFormats); err != nil {
+		return err
+	}
+	for index, disk := range upgradeDisks {
+		if disk == nil {
+			continue
+		}
+		console.Printf("Upgraded XL backend format of disk %s from version %s to %s\n",
+			disk, formatConfigs[index].XL.Version, highestVersion)
+		formatConfigs[index] = upgradeFormats[index]
+	}
+	return nil
+}
+
 // checkFormatXL - verifies if format.json format is intact.
 func checkFormatXL(formatConfigs []*formatConfigV1) error {
 	if err := checkFormatXLValues(formatConfigs); err != nil {
@@ -898,6 +1063,7 @@ func initFormatXL(storageDisks []StorageAPI) (err error) {
 			XL: &xlFormat{
 				Version: "1",
 				Disk:    mustGetUUID(),
+				Parity:  globalStorageClassParity,
 			},
 		}
 		jbod[index] = formats[index].XL.Disk
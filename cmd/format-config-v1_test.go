@@ -18,7 +18,10 @@ package cmd
 
 import (
 	"bytes"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 // generates a valid format.json for XL backend.
@@ -1146,3 +1149,221 @@ func TestIsFormatNotFound(t *testing.T) {
 		t.Fatal("isFormatFound() should not return false")
 	}
 }
+
+// Tests for checkFormatXLVersions()
+func TestCheckFormatXLVersions(t *testing.T) {
+	nDisks := 4
+	fsDirs, err := getRandomDisks(nDisks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	endpoints, err := parseStorageEndpoints(fsDirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storageDisks, err := initStorageDisks(endpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = initFormatXL(storageDisks); err != nil {
+		t.Fatal(err)
+	}
+
+	// Pretend this server understands a newer version.
+	savedVersions := supportedFormatXLVersions
+	defer func() { supportedFormatXLVersions = savedVersions }()
+	supportedFormatXLVersions = []string{"1", "2"}
+
+	// setVersions - saves `format.json` with given versions on all disks.
+	setVersions := func(versions ...string) {
+		formatConfigs, sErrs := loadAllFormats(storageDisks)
+		for i, sErr := range sErrs {
+			if sErr != nil {
+				t.Fatal(sErr)
+			}
+			formatConfigs[i].XL.Version = versions[i]
+		}
+		if err = saveFormatXL(storageDisks, formatConfigs); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// All disks on the same version.
+	formatConfigs, _ := loadAllFormats(storageDisks)
+	if err = checkFormatXLVersions(storageDisks, formatConfigs, false); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	// Mismatch without auto upgrade is reported.
+	setVersions("1", "2", "2", "2")
+	formatConfigs, _ = loadAllFormats(storageDisks)
+	err = checkFormatXLVersions(storageDisks, formatConfigs, false)
+	if _, ok := err.(formatXLVersionMismatch); !ok {
+		t.Fatal("Expected version mismatch error, got: ", err)
+	}
+	if !strings.Contains(err.Error(), storageDisks[0].String()) {
+		t.Fatal("Expected disk on older version in the report, got: ", err)
+	}
+
+	// Higher version without quorum is not upgraded.
+	setVersions("1", "1", "2", "2")
+	formatConfigs, _ = loadAllFormats(storageDisks)
+	if err = checkFormatXLVersions(storageDisks, formatConfigs, true); err == nil {
+		t.Fatal("Expected error when higher version has no quorum")
+	}
+
+	// Higher version with quorum is upgraded.
+	setVersions("1", "2", "2", "2")
+	formatConfigs, _ = loadAllFormats(storageDisks)
+	if err = checkFormatXLVersions(storageDisks, formatConfigs, true); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	formatConfigs, _ = loadAllFormats(storageDisks)
+	for i, format := range formatConfigs {
+		if format.XL.Version != "2" {
+			t.Fatalf("Disk %d: expected version 2, got %s", i, format.XL.Version)
+		}
+	}
+	if err = checkFormatXL(formatConfigs); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+}
+
+// slowFormatStorage - storage whose reads take delay, or block until
+// unblock is closed if set. Concurrent reads are counted if inFlight
+// and maxInFlight are set.
+type slowFormatStorage struct {
+	StorageAPI
+	delay   time.Duration
+	unblock chan struct{}
+
+	inFlight, maxInFlight *int32
+}
+
+func (d slowFormatStorage) ReadAll(volume, path string) ([]byte, error) {
+	if d.inFlight != nil {
+		n := atomic.AddInt32(d.inFlight, 1)
+		defer atomic.AddInt32(d.inFlight, -1)
+		for {
+			curMax := atomic.LoadInt32(d.maxInFlight)
+			if n <= curMax || atomic.CompareAndSwapInt32(d.maxInFlight, curMax, n) {
+				break
+			}
+		}
+	}
+	if d.unblock != nil {
+		<-d.unblock
+	}
+	time.Sleep(d.delay)
+	// Without underlying disk the disk is offline.
+	if d.StorageAPI == nil {
+		return nil, errDiskNotFound
+	}
+	return d.StorageAPI.ReadAll(volume, path)
+}
+
+// Tests loading format.json from disks of varying response times.
+func TestLoadAllFormatsSlowDisks(t *testing.T) {
+	nDisks := 16
+	fsDirs, err := getRandomDisks(nDisks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	endpoints, err := parseStorageEndpoints(fsDirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storageDisks, err := initStorageDisks(endpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = initFormatXL(storageDisks); err != nil {
+		t.Fatal(err)
+	}
+
+	savedTimeout := formatLoadTimeout
+	defer func() { formatLoadTimeout = savedTimeout }()
+	formatLoadTimeout = 500 * time.Millisecond
+
+	// Stuck disks never respond until the test ends.
+	unblock := make(chan struct{})
+	defer close(unblock)
+
+	testCases := []struct {
+		slowDisks      int
+		stuckDisks     int
+		expectedAction InitActions
+	}{
+		// Test case - 1.
+		// All disks respond in time.
+		{0, 0, InitObjectLayer},
+		// Test case - 2.
+		// Slow disks still respond in time.
+		{8, 0, InitObjectLayer},
+		// Test case - 3.
+		// Stuck disks are offline, remaining disks have quorum.
+		{4, 7, InitObjectLayer},
+		// Test case - 4.
+		// Stuck disks are offline, no quorum.
+		{0, 9, WaitForQuorum},
+	}
+	for i, testCase := range testCases {
+		disks := make([]StorageAPI, nDisks)
+		for j, disk := range storageDisks {
+			switch {
+			case j < testCase.stuckDisks:
+				disks[j] = slowFormatStorage{StorageAPI: disk, unblock: unblock}
+			case j < testCase.stuckDisks+testCase.slowDisks:
+				disks[j] = slowFormatStorage{StorageAPI: disk, delay: 100 * time.Millisecond}
+			default:
+				disks[j] = disk
+			}
+		}
+
+		startTime := time.Now()
+		formatConfigs, sErrs := loadAllFormats(disks)
+		// Disks are loaded in parallel, total time is bounded by the timeout.
+		if elapsed := time.Since(startTime); elapsed > 2*formatLoadTimeout {
+			t.Errorf("Test %d: Expected formats to be loaded within %s, took %s", i+1, 2*formatLoadTimeout, elapsed)
+		}
+		for j, sErr := range sErrs {
+			if j < testCase.stuckDisks {
+				if sErr != errDiskNotFound {
+					t.Errorf("Test %d: Expected stuck disk %d to be offline, got %v", i+1, j, sErr)
+				}
+				continue
+			}
+			if sErr != nil || formatConfigs[j] == nil {
+				t.Errorf("Test %d: Expected format of disk %d to be loaded, got %v", i+1, j, sErr)
+			}
+		}
+		if action := prepForInitXL(true, sErrs, nDisks); action != testCase.expectedAction {
+			t.Errorf("Test %d: Expected action %v, got %v", i+1, testCase.expectedAction, action)
+		}
+	}
+}
+
+// Tests that the number of disks loaded concurrently is bounded.
+func TestLoadAllFormatsConcurrency(t *testing.T) {
+	nDisks := 2 * formatLoadConcurrency
+	var inFlight, maxInFlight int32
+	disks := make([]StorageAPI, nDisks)
+	for i := range disks {
+		disks[i] = slowFormatStorage{
+			delay:       50 * time.Millisecond,
+			inFlight:    &inFlight,
+			maxInFlight: &maxInFlight,
+		}
+	}
+	_, sErrs := loadAllFormats(disks)
+	for i, sErr := range sErrs {
+		if sErr != errDiskNotFound {
+			t.Errorf("Disk %d: Expected error %v, got %v", i, errDiskNotFound, sErr)
+		}
+	}
+	if maxInFlight > formatLoadConcurrency {
+		t.Errorf("Expected at most %d disks loaded concurrently, got %d", formatLoadConcurrency, maxInFlight)
+	}
+}
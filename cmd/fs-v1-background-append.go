@@ -130,6 +130,9 @@ func (b *backgroundAppend) appendParts(disk StorageAPI, bucket, object, uploadID
 	appendMeta := fsMetaV1{}
 	// Allocate staging read buffer.
 	buf := make([]byte, readSizeV1)
+	// An append file left by an earlier run of the server, kept by
+	// houseKeeping as the upload is active, is started over.
+	disk.DeleteFile(minioMetaTmpBucket, uploadID)
 	for {
 		select {
 		case input := <-info.inputCh:
@@ -0,0 +1,141 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// errFSChecksumMismatch - content of an object does not match its checksum.
+var errFSChecksumMismatch = errors.New("object content does not match its checksum")
+
+// fsChecksumInfo - checksum of the whole content of an object in FS
+// mode, saved in `fs.json` to detect bit-rot on reads.
+type fsChecksumInfo struct {
+	Algorithm string `json:"algorithm"`
+	Hash      string `json:"hash"`
+}
+
+// isFSChecksumAlgoSupported - returns true if objects can be
+// checksummed with algo in FS mode.
+func isFSChecksumAlgoSupported(algo string) bool {
+	switch algo {
+	case "blake2b", "sha256":
+		return true
+	}
+	return false
+}
+
+// parseFSChecksumAlgo - validates the checksum algorithm set by
+// `--fs-checksum`, empty disables checksums.
+func parseFSChecksumAlgo(algo string) (string, error) {
+	if algo != "" && !isFSChecksumAlgoSupported(algo) {
+		return "", fmt.Errorf("Unsupported checksum algorithm %s, expected blake2b or sha256", algo)
+	}
+	return algo, nil
+}
+
+// newFSChecksumWriter - returns a hash computing the checksum of new
+// objects, nil if checksums are disabled.
+func newFSChecksumWriter() hash.Hash {
+	if globalFSChecksumAlgo == "" {
+		return nil
+	}
+	return newHash(globalFSChecksumAlgo)
+}
+
+// fsChecksumFromWriter - returns the checksum computed by h, nil if
+// checksums are disabled.
+func fsChecksumFromWriter(h hash.Hash) *fsChecksumInfo {
+	if h == nil {
+		return nil
+	}
+	return &fsChecksumInfo{
+		Algorithm: globalFSChecksumAlgo,
+		Hash:      hex.EncodeToString(h.Sum(nil)),
+	}
+}
+
+// fsChecksumVerifier - writer verifying the checksum of a whole object
+// read through it. The last write is held back until the checksum is
+// verified, such that clients never receive a corrupted object in full.
+type fsChecksumVerifier struct {
+	writer  io.Writer
+	hash    hash.Hash
+	pending []byte
+}
+
+// newFSChecksumVerifier - returns a writer verifying data written to
+// writer against checksum.
+func newFSChecksumVerifier(writer io.Writer, checksum fsChecksumInfo) *fsChecksumVerifier {
+	return &fsChecksumVerifier{
+		writer: writer,
+		hash:   newHash(checksum.Algorithm),
+	}
+}
+
+func (v *fsChecksumVerifier) Write(p []byte) (int, error) {
+	v.hash.Write(p)
+	if len(v.pending) > 0 {
+		if _, err := v.writer.Write(v.pending); err != nil {
+			return 0, err
+		}
+	}
+	// Buffers of callers are reused, keep a copy.
+	v.pending = append(v.pending[:0], p...)
+	return len(p), nil
+}
+
+// verify - returns errFSChecksumMismatch if the data written does not
+// match the checksum, writes the held back data otherwise.
+func (v *fsChecksumVerifier) verify(checksum fsChecksumInfo) error {
+	if hex.EncodeToString(v.hash.Sum(nil)) != checksum.Hash {
+		return errFSChecksumMismatch
+	}
+	if len(v.pending) > 0 {
+		if _, err := v.writer.Write(v.pending); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fsComputeChecksum - returns the checksum of the content of an object,
+// nil if checksums are disabled.
+func fsComputeChecksum(disk StorageAPI, bucket, object string) (*fsChecksumInfo, error) {
+	checksumWriter := newFSChecksumWriter()
+	if checksumWriter == nil {
+		return nil, nil
+	}
+	fi, err := disk.StatFile(bucket, object)
+	if err != nil {
+		return nil, traceError(err)
+	}
+	bufSize := int64(readSizeV1)
+	if fi.Size > 0 && bufSize > fi.Size {
+		bufSize = fi.Size
+	}
+	buf := make([]byte, int(bufSize))
+	if err = fsReadFile(disk, bucket, object, checksumWriter, fi.Size, 0, buf); err != nil {
+		return nil, err
+	}
+	return fsChecksumFromWriter(checksumWriter), nil
+}
@@ -0,0 +1,173 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"testing"
+)
+
+// Tests validation of the checksum algorithm of `--fs-checksum`.
+func TestParseFSChecksumAlgo(t *testing.T) {
+	testCases := []struct {
+		algo       string
+		shouldPass bool
+	}{
+		// Test case - 1.
+		// Checksums disabled.
+		{"", true},
+		// Test case - 2.
+		{"blake2b", true},
+		// Test case - 3.
+		{"sha256", true},
+		// Test case - 4.
+		// Unsupported algorithm.
+		{"md5", false},
+	}
+	for i, testCase := range testCases {
+		algo, err := parseFSChecksumAlgo(testCase.algo)
+		if testCase.shouldPass && (err != nil || algo != testCase.algo) {
+			t.Errorf("Test %d: Expected %s to be valid, got %s, %v", i+1, testCase.algo, algo, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected %s to be invalid", i+1, testCase.algo)
+		}
+	}
+}
+
+// Tests corruption of objects in FS mode is detected on reads.
+func TestFSChecksumCorruptedObject(t *testing.T) {
+	// Reset so that we don't affect other tests.
+	defer func(algo string) { globalFSChecksumAlgo = algo }(globalFSChecksumAlgo)
+
+	disk := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	defer removeAll(disk)
+	obj := initFSObjects(disk, t)
+	fs := obj.(fsObjects)
+
+	bucketName := "bucket"
+	if err := obj.MakeBucket(bucketName); err != nil {
+		t.Fatal("Unexpected err: ", err)
+	}
+	data := bytes.Repeat([]byte("abcd"), readSizeV1/2)
+
+	// putObject - creates object with data, returns its fs.json.
+	putObject := func(object string) fsMetaV1 {
+		if _, err := obj.PutObject(bucketName, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatal("Unexpected err: ", err)
+		}
+		fsMeta, err := readFSMetadata(fs.storage, minioMetaBucket, path.Join(bucketMetaPrefix, bucketName, object, fsMetaJSONFile))
+		if err != nil {
+			t.Fatal("Unexpected err: ", err)
+		}
+		return fsMeta
+	}
+
+	// corruptObject - flips bytes of object on disk, its size is unchanged.
+	corruptObject := func(object string) {
+		corrupted := append([]byte("dcba"), data[4:]...)
+		if err := ioutil.WriteFile(filepath.Join(disk, bucketName, object), corrupted, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, algo := range []string{"blake2b", "sha256"} {
+		globalFSChecksumAlgo = algo
+		object := "object-" + algo
+
+		fsMeta := putObject(object)
+		if fsMeta.Checksum == nil || fsMeta.Checksum.Algorithm != algo {
+			t.Fatalf("%s: Expected object to be checksummed, got %v", algo, fsMeta.Checksum)
+		}
+
+		// Intact object is read.
+		var buf bytes.Buffer
+		if err := obj.GetObject(bucketName, object, 0, int64(len(data)), &buf); err != nil {
+			t.Fatalf("%s: Unexpected err: %v", algo, err)
+		}
+		if !bytes.Equal(buf.Bytes(), data) {
+			t.Errorf("%s: Expected object content to be read", algo)
+		}
+
+		// Corrupted object is not read in full.
+		corruptObject(object)
+		buf.Reset()
+		err := obj.GetObject(bucketName, object, 0, int64(len(data)), &buf)
+		if _, ok := errorCause(err).(ObjectCorrupted); !ok {
+			t.Fatalf("%s: Expected ObjectCorrupted, got %v", algo, err)
+		}
+		if buf.Len() >= len(data) {
+			t.Errorf("%s: Expected corrupted object not to be written in full, got %d bytes", algo, buf.Len())
+		}
+		if toAPIErrorCode(err) != ErrObjectCorrupted {
+			t.Errorf("%s: Expected API error ErrObjectCorrupted, got %v", algo, toAPIErrorCode(err))
+		}
+
+		// Ranged reads are not verified.
+		buf.Reset()
+		if err = obj.GetObject(bucketName, object, 4, 4, &buf); err != nil {
+			t.Errorf("%s: Expected ranged read to succeed, got %v", algo, err)
+		}
+
+		// Updating metadata keeps the checksum.
+		if _, err = obj.CopyObject(bucketName, object, bucketName, object, map[string]string{"X-Amz-Meta-Key": "value"}); err != nil {
+			t.Fatalf("%s: Unexpected err: %v", algo, err)
+		}
+		if err = obj.GetObject(bucketName, object, 0, int64(len(data)), ioutil.Discard); err == nil {
+			t.Errorf("%s: Expected checksum to be kept by metadata update", algo)
+		}
+
+		// Truncation computes the checksum of the retained content.
+		if _, err = obj.TruncateObject(bucketName, object, 4); err != nil {
+			t.Fatalf("%s: Unexpected err: %v", algo, err)
+		}
+		if err = obj.GetObject(bucketName, object, 0, 4, ioutil.Discard); err != nil {
+			t.Errorf("%s: Expected truncated object to be read, got %v", algo, err)
+		}
+	}
+
+	// Completed multipart uploads are checksummed.
+	globalFSChecksumAlgo = "blake2b"
+	uploadID, err := obj.NewMultipartUpload(bucketName, "multipart", nil)
+	if err != nil {
+		t.Fatal("Unexpected err: ", err)
+	}
+	md5Hex, err := obj.PutObjectPart(bucketName, "multipart", uploadID, 1, int64(len(data)), bytes.NewReader(data), "", "")
+	if err != nil {
+		t.Fatal("Unexpected err: ", err)
+	}
+	if _, err = obj.CompleteMultipartUpload(bucketName, "multipart", uploadID, []completePart{{PartNumber: 1, ETag: md5Hex}}); err != nil {
+		t.Fatal("Unexpected err: ", err)
+	}
+	corruptObject("multipart")
+	if err = obj.GetObject(bucketName, "multipart", 0, int64(len(data)), ioutil.Discard); err == nil {
+		t.Errorf("Expected corruption of multipart object to be detected")
+	}
+
+	// Objects are not checksummed if disabled.
+	globalFSChecksumAlgo = ""
+	if fsMeta := putObject("unchecked"); fsMeta.Checksum != nil {
+		t.Fatalf("Expected object not to be checksummed, got %v", fsMeta.Checksum)
+	}
+	corruptObject("unchecked")
+	if err = obj.GetObject(bucketName, "unchecked", 0, int64(len(data)), ioutil.Discard); err != nil {
+		t.Errorf("Expected object without checksum to be read, got %v", err)
+	}
+}
@@ -36,6 +36,8 @@ type fsMetaV1 struct {
 	// Metadata map for current object `fs.json`.
 	Meta  map[string]string `json:"meta,omitempty"`
 	Parts []objectPartInfo  `json:"parts,omitempty"`
+	// Checksum of the object content, if enabled with `--fs-checksum`.
+	Checksum *fsChecksumInfo `json:"checksum,omitempty"`
 }
 
 // ObjectPartIndex - returns the index of matching object part number.
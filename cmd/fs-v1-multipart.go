@@ -501,6 +501,45 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 		return "", toObjectErr(err, minioMetaMultipartBucket, fsMetaPath)
 	}
 
+	// Validate all the parts before they are concatenated, this is
+	// done upfront as parts may already be appended in background.
+	for i, part := range parts {
+		partIdx := fsMeta.ObjectPartIndex(part.PartNumber)
+		if partIdx == -1 {
+			return "", traceError(InvalidPart{})
+		}
+		if fsMeta.Parts[partIdx].ETag != part.ETag {
+			return "", traceError(BadDigest{})
+		}
+		// All parts except the last part has to be atleast 5MB.
+		if (i < len(parts)-1) && !isMinAllowedPartSize(fsMeta.Parts[partIdx].Size) {
+			return "", traceError(PartTooSmall{
+				PartNumber: part.PartNumber,
+				PartSize:   fsMeta.Parts[partIdx].Size,
+				PartETag:   part.ETag,
+			})
+		}
+	}
+
+	// Metadata of the completed object, no need to save part info,
+	// since all the parts are concatenated.
+	objMeta := fsMeta
+	objMeta.Parts = nil
+	objMeta.Meta = make(map[string]string, len(fsMeta.Meta)+1)
+	for k, v := range fsMeta.Meta {
+		objMeta.Meta[k] = v
+	}
+	objMeta.Meta["md5Sum"] = s3MD5
+
+	// Log rename of the concatenated parts in tmp to the actual location.
+	walEntry := fsWALEntry{
+		Op:       fsWALOpPut,
+		Bucket:   bucket,
+		Object:   object,
+		UploadID: uploadID,
+		FSMeta:   &objMeta,
+	}
+
 	// This lock is held during rename of the appended tmp file to the actual
 	// location so that any competing GetObject/PutObject/DeleteObject do not race.
 	appendFallback := true // In case background-append did not append the required parts.
@@ -508,6 +547,12 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 		err = fs.bgAppend.complete(fs.storage, bucket, object, uploadID, fsMeta)
 		if err == nil {
 			appendFallback = false
+			walEntry.TmpObject = uploadID
+			var walCommit func()
+			if walCommit, err = fs.walBegin(walEntry); err != nil {
+				return "", toObjectErr(err, bucket, object)
+			}
+			defer walCommit()
 			if err = fs.storage.RenameFile(minioMetaTmpBucket, uploadID, bucket, object); err != nil {
 				return "", toObjectErr(traceError(err), minioMetaTmpBucket, uploadID)
 			}
@@ -534,23 +579,9 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 			}
 		}
 
-		// Loop through all parts, validate them and then commit to disk.
-		for i, part := range parts {
+		// Loop through all the validated parts and commit them to disk.
+		for _, part := range parts {
 			partIdx := fsMeta.ObjectPartIndex(part.PartNumber)
-			if partIdx == -1 {
-				return "", traceError(InvalidPart{})
-			}
-			if fsMeta.Parts[partIdx].ETag != part.ETag {
-				return "", traceError(BadDigest{})
-			}
-			// All parts except the last part has to be atleast 5MB.
-			if (i < len(parts)-1) && !isMinAllowedPartSize(fsMeta.Parts[partIdx].Size) {
-				return "", traceError(PartTooSmall{
-					PartNumber: part.PartNumber,
-					PartSize:   fsMeta.Parts[partIdx].Size,
-					PartETag:   part.ETag,
-				})
-			}
 			// Construct part suffix.
 			partSuffix := fmt.Sprintf("object%d", part.PartNumber)
 			multipartPartFile := path.Join(bucket, object, uploadID, partSuffix)
@@ -582,6 +613,13 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 			}
 		}
 
+		walEntry.TmpObject = tempObj
+		var walCommit func()
+		if walCommit, err = fs.walBegin(walEntry); err != nil {
+			return "", toObjectErr(err, bucket, object)
+		}
+		defer walCommit()
+
 		// Rename the file back to original location, if not delete the temporary object.
 		err = fs.storage.RenameFile(minioMetaTmpBucket, tempObj, bucket, object)
 		if err != nil {
@@ -592,18 +630,14 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 		}
 	}
 
-	// No need to save part info, since we have concatenated all parts.
-	fsMeta.Parts = nil
-
-	// Save additional metadata.
-	if len(fsMeta.Meta) == 0 {
-		fsMeta.Meta = make(map[string]string)
+	// Checksum of the completed object, parts are not checksummed.
+	if objMeta.Checksum, err = fsComputeChecksum(fs.storage, bucket, object); err != nil {
+		return "", toObjectErr(err, bucket, object)
 	}
-	fsMeta.Meta["md5Sum"] = s3MD5
 
 	fsMetaPath = path.Join(bucketMetaPrefix, bucket, object, fsMetaJSONFile)
 	// Write the metadata to a temp file and rename it to the actual location.
-	if err = writeFSMetadata(fs.storage, minioMetaBucket, fsMetaPath, fsMeta); err != nil {
+	if err = writeFSMetadata(fs.storage, minioMetaBucket, fsMetaPath, objMeta); err != nil {
 		return "", toObjectErr(err, bucket, object)
 	}
 
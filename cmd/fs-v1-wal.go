@@ -0,0 +1,333 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+const (
+	// Write-ahead log file name inside the WAL directory.
+	fsWALFile = "fs.wal"
+
+	// Write-ahead log is compacted when it grows beyond this size.
+	fsWALMaxSize = 4 * humanize.MiByte
+)
+
+// Operations recorded in the FS write-ahead log.
+const (
+	// Rename of a fully written temporary object to its final
+	// location followed by saving its `fs.json`.
+	fsWALOpPut = "put"
+
+	// Removal of an object and its `fs.json`.
+	fsWALOpDelete = "delete"
+)
+
+// errFSWALNotFS - write-ahead log is only supported on a single disk.
+var errFSWALNotFS = errors.New("Write-ahead log is only supported in FS mode")
+
+// fsWALEntry - single record of the FS write-ahead log. An operation
+// is logged before it starts, a later record with the same ID and
+// Committed set marks its completion. IDs are sequence numbers
+// increasing in the order operations are logged.
+type fsWALEntry struct {
+	ID        uint64    `json:"id"`
+	Op        string    `json:"op,omitempty"`
+	Committed bool      `json:"committed,omitempty"`
+	Bucket    string    `json:"bucket,omitempty"`
+	Object    string    `json:"object,omitempty"`
+	TmpObject string    `json:"tmpObject,omitempty"`
+	UploadID  string    `json:"uploadId,omitempty"`
+	FSMeta    *fsMetaV1 `json:"fsMeta,omitempty"`
+}
+
+// fsWAL - write-ahead log of metadata mutating FS operations, kept
+// outside of the FS export such that operations interrupted by a
+// crash can be completed on startup.
+type fsWAL struct {
+	mutex *sync.Mutex
+	dir   string
+	file  *os.File
+	size  int64
+
+	// ID of the last logged operation.
+	seq uint64
+
+	// Operations which are logged but not yet committed.
+	pending map[uint64]fsWALEntry
+}
+
+// newFSWAL - opens the write-ahead log in walDir, creating it if
+// necessary, and loads all the operations which were not committed.
+func newFSWAL(walDir string) (*fsWAL, error) {
+	if err := os.MkdirAll(walDir, 0700); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filepath.Join(walDir, fsWALFile), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	wal := &fsWAL{
+		mutex:   &sync.Mutex{},
+		dir:     walDir,
+		file:    file,
+		pending: make(map[uint64]fsWALEntry),
+	}
+
+	decoder := json.NewDecoder(file)
+	for {
+		var entry fsWALEntry
+		if err = decoder.Decode(&entry); err != nil {
+			break
+		}
+		if entry.ID > wal.seq {
+			wal.seq = entry.ID
+		}
+		if entry.Committed {
+			delete(wal.pending, entry.ID)
+		} else {
+			wal.pending[entry.ID] = entry
+		}
+	}
+	// A partially written record at the end of the log is left behind
+	// by a crash while logging, its operation was never started.
+	if _, ok := err.(*json.SyntaxError); err != io.EOF && err != io.ErrUnexpectedEOF && !ok {
+		file.Close()
+		return nil, err
+	}
+
+	fi, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	wal.size = fi.Size()
+	return wal, nil
+}
+
+// append - writes a record to the log and syncs it to disk.
+func (w *fsWAL) append(entry fsWALEntry) error {
+	entryBytes, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	entryBytes = append(entryBytes, '\n')
+	n, err := w.file.Write(entryBytes)
+	w.size += int64(n)
+	if err != nil {
+		return err
+	}
+	return w.file.Sync()
+}
+
+// begin - logs an operation before it is started, returns the ID to
+// commit the operation with once it is complete.
+func (w *fsWAL) begin(entry fsWALEntry) (uint64, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.seq++
+	entry.ID = w.seq
+	entry.Committed = false
+	if err := w.append(entry); err != nil {
+		return 0, err
+	}
+	w.pending[entry.ID] = entry
+	return entry.ID, nil
+}
+
+// commit - marks a logged operation as complete. Log is truncated
+// when no operations are pending and compacted to only the pending
+// operations when it grows beyond fsWALMaxSize.
+func (w *fsWAL) commit(id uint64) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	delete(w.pending, id)
+	if len(w.pending) == 0 {
+		return w.truncate()
+	}
+	if err := w.append(fsWALEntry{ID: id, Committed: true}); err != nil {
+		return err
+	}
+	if w.size > fsWALMaxSize {
+		return w.compact()
+	}
+	return nil
+}
+
+// truncate - removes all records from the log.
+func (w *fsWAL) truncate() error {
+	if err := w.file.Truncate(0); err != nil {
+		return err
+	}
+	w.size = 0
+	return w.file.Sync()
+}
+
+// compact - rewrites the log with only the pending operations.
+func (w *fsWAL) compact() error {
+	tmpPath := filepath.Join(w.dir, fsWALFile+".tmp")
+	tmpFile, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	var size int64
+	for _, entry := range w.pending {
+		entryBytes, err := json.Marshal(entry)
+		if err != nil {
+			tmpFile.Close()
+			return err
+		}
+		n, err := tmpFile.Write(append(entryBytes, '\n'))
+		size += int64(n)
+		if err != nil {
+			tmpFile.Close()
+			return err
+		}
+	}
+	if err = tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err = os.Rename(tmpPath, filepath.Join(w.dir, fsWALFile)); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	w.file.Close()
+	w.file = tmpFile
+	w.size = size
+	return nil
+}
+
+// byLogSeq - sorts IDs of logged operations in the order they were
+// logged.
+type byLogSeq []uint64
+
+func (s byLogSeq) Len() int           { return len(s) }
+func (s byLogSeq) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byLogSeq) Less(i, j int) bool { return s[i] < s[j] }
+
+// replay - completes all the pending operations on disk in the order
+// they were logged, operations which had not yet reached the point of
+// no return are rolled back. Log is truncated once all of them are
+// applied.
+func (w *fsWAL) replay(disk StorageAPI) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	var ids []uint64
+	for id := range w.pending {
+		ids = append(ids, id)
+	}
+	sort.Sort(byLogSeq(ids))
+	for _, id := range ids {
+		if err := replayFSWALEntry(disk, w.pending[id]); err != nil {
+			return err
+		}
+		delete(w.pending, id)
+	}
+	return w.truncate()
+}
+
+// replayFSWALEntry - applies a single logged operation, all the steps
+// are idempotent such that partially completed operations are
+// completed.
+func replayFSWALEntry(disk StorageAPI, entry fsWALEntry) error {
+	fsMetaPath := path.Join(bucketMetaPrefix, entry.Bucket, entry.Object, fsMetaJSONFile)
+	switch entry.Op {
+	case fsWALOpPut:
+		if entry.TmpObject != "" {
+			err := disk.RenameFile(minioMetaTmpBucket, entry.TmpObject, entry.Bucket, entry.Object)
+			if err != nil && err != errFileNotFound {
+				return err
+			}
+		}
+		if _, err := disk.StatFile(entry.Bucket, entry.Object); err != nil {
+			if err == errFileNotFound {
+				// Object was never renamed in place, nothing to complete.
+				return nil
+			}
+			return err
+		}
+		if entry.FSMeta != nil {
+			if err := writeFSMetadata(disk, minioMetaBucket, fsMetaPath, *entry.FSMeta); err != nil {
+				return err
+			}
+		}
+		if entry.UploadID != "" {
+			if err := cleanupUploadedParts(entry.Bucket, entry.Object, entry.UploadID, disk); err != nil {
+				return err
+			}
+			fs := fsObjects{storage: disk}
+			if err := fs.removeUploadID(entry.Bucket, entry.Object, entry.UploadID); err != nil && errorCause(err) != errFileNotFound {
+				return err
+			}
+		}
+	case fsWALOpDelete:
+		if err := disk.DeleteFile(minioMetaBucket, fsMetaPath); err != nil && err != errFileNotFound {
+			return err
+		}
+		if err := disk.DeleteFile(entry.Bucket, entry.Object); err != nil && err != errFileNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// initFSWAL - opens the write-ahead log and completes operations
+// interrupted by a crash, should be called before temporary files
+// are purged at startup.
+func initFSWAL(walDir string, storageDisks []StorageAPI) (*fsWAL, error) {
+	if len(storageDisks) != 1 {
+		return nil, errFSWALNotFS
+	}
+	wal, err := newFSWAL(walDir)
+	if err != nil {
+		return nil, err
+	}
+	if err = wal.replay(storageDisks[0]); err != nil {
+		return nil, err
+	}
+	return wal, nil
+}
+
+// walBegin - logs an operation if write-ahead log is enabled, returns
+// a function to be called once the operation is complete.
+func (fs fsObjects) walBegin(entry fsWALEntry) (func(), error) {
+	if fs.wal == nil {
+		return func() {}, nil
+	}
+	id, err := fs.wal.begin(entry)
+	if err != nil {
+		return nil, traceError(err)
+	}
+	return func() {
+		errorIf(fs.wal.commit(id), "Unable to commit write-ahead log entry of %s/%s", entry.Bucket, entry.Object)
+	}, nil
+}
@@ -0,0 +1,232 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+)
+
+// Tests FS operations with write-ahead log enabled leave no pending
+// operations behind and the log is truncated.
+func TestFSWALOperations(t *testing.T) {
+	disk := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	defer removeAll(disk)
+	walDir := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	defer removeAll(walDir)
+
+	obj := initFSObjects(disk, t)
+	fs := obj.(fsObjects)
+	wal, err := newFSWAL(walDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs.wal = wal
+
+	bucket, object := "bucket", "object"
+	if err = fs.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = fs.PutObject(bucket, object, 5, bytes.NewReader([]byte("abcde")), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if len(wal.pending) != 0 || wal.size != 0 {
+		t.Fatalf("Expected empty log after PutObject, got %d pending and size %d", len(wal.pending), wal.size)
+	}
+	if err = fs.DeleteObject(bucket, object); err != nil {
+		t.Fatal(err)
+	}
+	if len(wal.pending) != 0 || wal.size != 0 {
+		t.Fatalf("Expected empty log after DeleteObject, got %d pending and size %d", len(wal.pending), wal.size)
+	}
+}
+
+// Tests pending operations are completed by replay on startup.
+func TestFSWALReplay(t *testing.T) {
+	disk := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	defer removeAll(disk)
+	walDir := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	defer removeAll(walDir)
+
+	obj := initFSObjects(disk, t)
+	fs := obj.(fsObjects)
+	bucket := "bucket"
+	if err := fs.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.PutObject(bucket, "deleted", 5, bytes.NewReader([]byte("abcde")), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	wal, err := newFSWAL(walDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Crash after the object was written to tmp, but before rename.
+	if err = fs.storage.AppendFile(minioMetaTmpBucket, "tmp-object", []byte("abcde")); err != nil {
+		t.Fatal(err)
+	}
+	fsMeta := newFSMetaV1()
+	fsMeta.Meta = map[string]string{"md5Sum": getMD5Hash([]byte("abcde"))}
+	if _, err = wal.begin(fsWALEntry{Op: fsWALOpPut, Bucket: bucket, Object: "renamed", TmpObject: "tmp-object", FSMeta: &fsMeta}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Crash before the temporary object was completely written, the
+	// operation is never logged.
+	if err = fs.storage.AppendFile(minioMetaTmpBucket, "tmp-partial", []byte("ab")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Crash after fs.json of an object was removed.
+	if _, err = wal.begin(fsWALEntry{Op: fsWALOpDelete, Bucket: bucket, Object: "deleted"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = fs.storage.DeleteFile(minioMetaBucket, path.Join(bucketMetaPrefix, bucket, "deleted", fsMetaJSONFile)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Operations on the same object are replayed in the order they
+	// were logged.
+	for _, tmpObject := range []string{"tmp-put-deleted", "tmp-deleted-put"} {
+		if err = fs.storage.AppendFile(minioMetaTmpBucket, tmpObject, []byte("abcde")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, entry := range []fsWALEntry{
+		{Op: fsWALOpPut, Bucket: bucket, Object: "put-deleted", TmpObject: "tmp-put-deleted", FSMeta: &fsMeta},
+		{Op: fsWALOpDelete, Bucket: bucket, Object: "put-deleted"},
+		{Op: fsWALOpDelete, Bucket: bucket, Object: "deleted-put"},
+		{Op: fsWALOpPut, Bucket: bucket, Object: "deleted-put", TmpObject: "tmp-deleted-put", FSMeta: &fsMeta},
+	} {
+		if _, err = wal.begin(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Committed operations are not replayed.
+	id, err := wal.begin(fsWALEntry{Op: fsWALOpDelete, Bucket: bucket, Object: "committed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = wal.commit(id); err != nil {
+		t.Fatal(err)
+	}
+
+	// Crash while logging, leaves behind a partial record.
+	if _, err = wal.file.Write([]byte(`{"id":100,"op":"del`)); err != nil {
+		t.Fatal(err)
+	}
+	wal.file.Close()
+
+	wal, err = initFSWAL(walDir, []StorageAPI{fs.storage})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(wal.pending) != 0 || wal.size != 0 {
+		t.Fatalf("Expected empty log after replay, got %d pending and size %d", len(wal.pending), wal.size)
+	}
+	if fi, err := os.Stat(filepath.Join(walDir, fsWALFile)); err != nil || fi.Size() != 0 {
+		t.Fatalf("Expected log to be truncated, got %v", err)
+	}
+
+	objInfo, err := fs.GetObjectInfo(bucket, "renamed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.MD5Sum != fsMeta.Meta["md5Sum"] {
+		t.Errorf("Expected md5sum %s, got %s", fsMeta.Meta["md5Sum"], objInfo.MD5Sum)
+	}
+	if _, err = fs.storage.StatFile(minioMetaTmpBucket, "tmp-object"); err != errFileNotFound {
+		t.Errorf("Expected temporary object to be renamed, got %v", err)
+	}
+	if _, err = fs.GetObjectInfo(bucket, "deleted"); !isErrObjectNotFound(err) {
+		t.Errorf("Expected deleted object to be removed, got %v", err)
+	}
+	if _, err = fs.GetObjectInfo(bucket, "put-deleted"); !isErrObjectNotFound(err) {
+		t.Errorf("Expected object deleted after its put to be removed, got %v", err)
+	}
+	if _, err = fs.GetObjectInfo(bucket, "deleted-put"); err != nil {
+		t.Errorf("Expected object put after its delete to exist, got %v", err)
+	}
+
+	// Write-ahead log is only supported in FS mode.
+	if _, err = initFSWAL(walDir, []StorageAPI{fs.storage, fs.storage}); err != errFSWALNotFS {
+		t.Errorf("Expected %v, got %v", errFSWALNotFS, err)
+	}
+}
+
+// Tests the log is compacted to only the pending operations.
+func TestFSWALCompact(t *testing.T) {
+	walDir := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	defer removeAll(walDir)
+
+	wal, err := newFSWAL(walDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pendingID, err := wal.begin(fsWALEntry{Op: fsWALOpDelete, Bucket: "bucket", Object: "pending"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		id, err := wal.begin(fsWALEntry{Op: fsWALOpDelete, Bucket: "bucket", Object: "object"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = wal.commit(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	size := wal.size
+	if err = wal.compact(); err != nil {
+		t.Fatal(err)
+	}
+	if wal.size >= size {
+		t.Fatalf("Expected log to be compacted, got size %d", wal.size)
+	}
+
+	// Log continues to be appended after compaction.
+	if _, err = wal.begin(fsWALEntry{Op: fsWALOpDelete, Bucket: "bucket", Object: "next"}); err != nil {
+		t.Fatal(err)
+	}
+
+	wal.file.Close()
+	if wal, err = newFSWAL(walDir); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := wal.pending[pendingID]; !ok || len(wal.pending) != 2 {
+		t.Fatalf("Expected %d and one more to be pending, got %v", pendingID, wal.pending)
+	}
+
+	// Operations logged after reopening are ordered after the
+	// pending ones.
+	id, err := wal.begin(fsWALEntry{Op: fsWALOpDelete, Bucket: "bucket", Object: "reopened"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for pendingID := range wal.pending {
+		if pendingID > id {
+			t.Fatalf("Expected ID %d to be after pending ID %d", id, pendingID)
+		}
+	}
+	wal.file.Close()
+}
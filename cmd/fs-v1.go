@@ -40,6 +40,9 @@ type fsObjects struct {
 
 	// To manage the appendRoutine go0routines
 	bgAppend *backgroundAppend
+
+	// Write-ahead log of metadata mutating operations, nil if disabled.
+	wal *fsWAL
 }
 
 // list of all errors that can be ignored in tree walk operation in FS
@@ -72,6 +75,7 @@ func newFSObjects(storage StorageAPI) (ObjectLayer, error) {
 		bgAppend: &backgroundAppend{
 			infoMap: make(map[string]bgAppendPartsInfo),
 		},
+		wal: globalFSWAL,
 	}
 
 	// Return successfully initialized object layer.
@@ -128,8 +132,11 @@ func (fs fsObjects) StorageInfo() StorageInfo {
 	info, err := fs.storage.DiskInfo()
 	errorIf(err, "Unable to get disk info %#v", fs.storage)
 	storageInfo := StorageInfo{
-		Total: info.Total,
-		Free:  info.Free,
+		Total:    info.Total,
+		Free:     info.Free,
+		RawTotal: info.Total,
+		RawFree:  info.Free,
+		Disks:    getDisksLatency([]StorageAPI{fs.storage}),
 	}
 	storageInfo.Backend.Type = FS
 	return storageInfo
@@ -230,11 +237,16 @@ func (fs fsObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 	// Check if this request is only metadata update.
 	cpMetadataOnly := strings.EqualFold(pathJoin(srcBucket, srcObject), pathJoin(dstBucket, dstObject))
 	if cpMetadataOnly {
-		// Save objects' metadata in `fs.json`.
+		fsMetaPath := pathJoin(bucketMetaPrefix, dstBucket, dstObject, fsMetaJSONFile)
+
+		// Save objects' metadata in `fs.json`, the content and
+		// hence its checksum are unchanged.
 		fsMeta := newFSMetaV1()
+		if oldMeta, rerr := readFSMetadata(fs.storage, minioMetaBucket, fsMetaPath); rerr == nil {
+			fsMeta.Checksum = oldMeta.Checksum
+		}
 		fsMeta.Meta = metadata
 
-		fsMetaPath := pathJoin(bucketMetaPrefix, dstBucket, dstObject, fsMetaJSONFile)
 		if err = writeFSMetadata(fs.storage, minioMetaBucket, fsMetaPath, fsMeta); err != nil {
 			return ObjectInfo{}, toObjectErr(err, dstBucket, dstObject)
 		}
@@ -310,12 +322,34 @@ func (fs fsObjects) GetObject(bucket, object string, offset int64, length int64,
 	if length > 0 && bufSize > length {
 		bufSize = length
 	}
+	// Reads of whole objects are verified against their checksum, if
+	// any, ranged reads are not.
+	var checksum *fsChecksumInfo
+	if bucket != minioMetaBucket && offset == 0 && length == fi.Size {
+		fsMeta, rerr := readFSMetadata(fs.storage, minioMetaBucket, path.Join(bucketMetaPrefix, bucket, object, fsMetaJSONFile))
+		if rerr == nil {
+			checksum = fsMeta.Checksum
+		}
+	}
+	var verifier *fsChecksumVerifier
+	if checksum != nil {
+		verifier = newFSChecksumVerifier(writer, *checksum)
+		writer = verifier
+	}
+
 	// Allocate a staging buffer.
 	buf := make([]byte, int(bufSize))
 	if err = fsReadFile(fs.storage, bucket, object, writer, totalLeft, offset, buf); err != nil {
 		// Returns any error.
 		return toObjectErr(err, bucket, object)
 	}
+	if verifier != nil {
+		if err = verifier.verify(*checksum); err == errFSChecksumMismatch {
+			return traceError(ObjectCorrupted{Bucket: bucket, Object: object})
+		} else if err != nil {
+			return toObjectErr(traceError(err), bucket, object)
+		}
+	}
 	return nil
 }
 
@@ -355,6 +389,10 @@ func (fs fsObjects) getObjectInfo(bucket, object string) (ObjectInfo, error) {
 		ContentEncoding: fsMeta.Meta["content-encoding"],
 	}
 
+	// Modification time requested on upload overrides the one of
+	// the file.
+	objInfo.ModTime = modTimeFromMetadata(fsMeta.Meta, fi.ModTime)
+
 	// md5Sum has already been extracted into objInfo.MD5Sum.  We
 	// need to remove it from fsMeta.Meta to avoid it from appearing as
 	// part of response headers. e.g, X-Minio-* or X-Amz-*.
@@ -402,6 +440,10 @@ func (fs fsObjects) PutObject(bucket string, object string, size int64, data io.
 		sha256Writer = sha256.New()
 		hashWriters = append(hashWriters, sha256Writer)
 	}
+	checksumWriter := newFSChecksumWriter()
+	if checksumWriter != nil {
+		hashWriters = append(hashWriters, checksumWriter)
+	}
 	multiWriter := io.MultiWriter(hashWriters...)
 
 	// Limit the reader to its provided size if specified.
@@ -472,21 +514,38 @@ func (fs fsObjects) PutObject(bucket string, object string, size int64, data io.
 		}
 	}
 
+	// Save objects' metadata in `fs.json`.
+	// Skip creating fs.json if bucket is .minio.sys as the object would have been created
+	// by minio's S3 layer (ex. policy.json)
+	var fsMeta *fsMetaV1
+	if bucket != minioMetaBucket {
+		meta := newFSMetaV1()
+		meta.Meta = metadata
+		meta.Checksum = fsChecksumFromWriter(checksumWriter)
+		fsMeta = &meta
+	}
+
+	walCommit, err := fs.walBegin(fsWALEntry{
+		Op:        fsWALOpPut,
+		Bucket:    bucket,
+		Object:    object,
+		TmpObject: tempObj,
+		FSMeta:    fsMeta,
+	})
+	if err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+	defer walCommit()
+
 	// Entire object was written to the temp location, now it's safe to rename it to the actual location.
 	err = fs.storage.RenameFile(minioMetaTmpBucket, tempObj, bucket, object)
 	if err != nil {
 		return ObjectInfo{}, toObjectErr(traceError(err), bucket, object)
 	}
 
-	if bucket != minioMetaBucket {
-		// Save objects' metadata in `fs.json`.
-		// Skip creating fs.json if bucket is .minio.sys as the object would have been created
-		// by minio's S3 layer (ex. policy.json)
-		fsMeta := newFSMetaV1()
-		fsMeta.Meta = metadata
-
+	if fsMeta != nil {
 		fsMetaPath := path.Join(bucketMetaPrefix, bucket, object, fsMetaJSONFile)
-		if err = writeFSMetadata(fs.storage, minioMetaBucket, fsMetaPath, fsMeta); err != nil {
+		if err = writeFSMetadata(fs.storage, minioMetaBucket, fsMetaPath, *fsMeta); err != nil {
 			return ObjectInfo{}, toObjectErr(traceError(err), bucket, object)
 		}
 	}
@@ -501,26 +560,103 @@ func (fs fsObjects) DeleteObject(bucket, object string) error {
 		return err
 	}
 
+	walCommit, err := fs.walBegin(fsWALEntry{
+		Op:     fsWALOpDelete,
+		Bucket: bucket,
+		Object: object,
+	})
+	if err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+	defer walCommit()
+
 	if bucket != minioMetaBucket {
 		// We don't store fs.json for minio-S3-layer created files like policy.json,
 		// hence we don't try to delete fs.json for such files.
-		err := fs.storage.DeleteFile(minioMetaBucket, path.Join(bucketMetaPrefix, bucket, object, fsMetaJSONFile))
+		err = fs.storage.DeleteFile(minioMetaBucket, path.Join(bucketMetaPrefix, bucket, object, fsMetaJSONFile))
 		if err != nil && err != errFileNotFound {
 			return toObjectErr(traceError(err), bucket, object)
 		}
 	}
-	if err := fs.storage.DeleteFile(bucket, object); err != nil {
+	if err = fs.storage.DeleteFile(bucket, object); err != nil {
 		return toObjectErr(traceError(err), bucket, object)
 	}
 	return nil
 }
 
-// ListObjects - list all objects at prefix upto maxKeys., optionally delimited by '/'. Maintains the list pool
+// TruncateObject - truncates an object to the requested size and
+// updates its md5sum in `fs.json`.
+func (fs fsObjects) TruncateObject(bucket, object string, size int64) (ObjectInfo, error) {
+	if err := checkTruncateObjArgs(bucket, object); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	objInfo, err := fs.getObjectInfo(bucket, object)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	if size < 0 || size > objInfo.Size {
+		return ObjectInfo{}, traceError(InvalidTruncateLength{bucket, object, size, objInfo.Size})
+	}
+
+	// Nothing to do.
+	if size == objInfo.Size {
+		return objInfo, nil
+	}
+
+	if err = fs.storage.TruncateFile(bucket, object, size); err != nil {
+		return ObjectInfo{}, toObjectErr(traceError(err), bucket, object)
+	}
+
+	if bucket != minioMetaBucket {
+		// Calculate md5sum and checksum of the retained content, the
+		// content is read directly as the saved checksum is stale.
+		md5Writer := md5.New()
+		hashWriters := []io.Writer{md5Writer}
+		checksumWriter := newFSChecksumWriter()
+		if checksumWriter != nil {
+			hashWriters = append(hashWriters, checksumWriter)
+		}
+		bufSize := int64(readSizeV1)
+		if size > 0 && bufSize > size {
+			bufSize = size
+		}
+		buf := make([]byte, int(bufSize))
+		if err = fsReadFile(fs.storage, bucket, object, io.MultiWriter(hashWriters...), size, 0, buf); err != nil {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
+
+		fsMetaPath := path.Join(bucketMetaPrefix, bucket, object, fsMetaJSONFile)
+		fsMeta, err := readFSMetadata(fs.storage, minioMetaBucket, fsMetaPath)
+		if err != nil && errorCause(err) != errFileNotFound {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
+		if err != nil {
+			fsMeta = newFSMetaV1()
+		}
+		if len(fsMeta.Meta) == 0 {
+			fsMeta.Meta = make(map[string]string)
+		}
+		fsMeta.Meta["md5Sum"] = hex.EncodeToString(md5Writer.Sum(nil))
+		// Truncated object is modified now, drop the modification
+		// time requested on upload.
+		delete(fsMeta.Meta, modTimeMetaKey)
+		fsMeta.Checksum = fsChecksumFromWriter(checksumWriter)
+		if err = writeFSMetadata(fs.storage, minioMetaBucket, fsMetaPath, fsMeta); err != nil {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
+	}
+
+	return fs.getObjectInfo(bucket, object)
+}
+
+// ListObjects - list all objects at prefix upto maxKeys., optionally delimited by delimiter. Maintains the list pool
 // state for future re-entrant list requests.
 func (fs fsObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
 	// Convert entry to ObjectInfo
-	entryToObjectInfo := func(entry string) (objInfo ObjectInfo, err error) {
-		if strings.HasSuffix(entry, slashSeparator) {
+	entryToObjectInfo := func(entry string, isPrefix bool) (objInfo ObjectInfo, err error) {
+		if isPrefix {
 			// Object name needs to be full path.
 			objInfo.Name = entry
 			objInfo.IsDir = true
@@ -576,7 +712,7 @@ func (fs fsObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKey
 	}
 	var objInfos []ObjectInfo
 	var eof bool
-	var nextMarker string
+	var nextMarker, lastPrefix string
 	for i := 0; i < maxKeys; {
 		walkResult, ok := <-walkResultCh
 		if !ok {
@@ -592,7 +728,18 @@ func (fs fsObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKey
 			}
 			return ListObjectsInfo{}, toObjectErr(walkResult.err, bucket, prefix)
 		}
-		objInfo, err := entryToObjectInfo(walkResult.entry)
+		entry := walkResult.entry
+		isPrefix := strings.HasSuffix(entry, slashSeparator)
+		if recursive && delimiter != "" {
+			commonPrefix, skip := groupByDelimiter(entry, prefix, marker, delimiter, lastPrefix)
+			if skip {
+				continue
+			}
+			if commonPrefix != "" {
+				entry, isPrefix, lastPrefix = commonPrefix, true, commonPrefix
+			}
+		}
+		objInfo, err := entryToObjectInfo(entry, isPrefix)
 		if err != nil {
 			return ListObjectsInfo{}, nil
 		}
@@ -626,11 +773,32 @@ func (fs fsObjects) HealObject(bucket, object string) error {
 	return traceError(NotImplemented{})
 }
 
+// VerifyHealObject - no-op for fs. Valid only for XL.
+func (fs fsObjects) VerifyHealObject(bucket, object string) (HealStatus, error) {
+	return "", traceError(NotImplemented{})
+}
+
 // HealBucket - no-op for fs, Valid only for XL.
 func (fs fsObjects) HealBucket(bucket string) error {
 	return traceError(NotImplemented{})
 }
 
+// RebuildBucketIndex - reconciles buckets with their metadata on the
+// backend disk.
+func (fs fsObjects) RebuildBucketIndex() (BucketIndexReport, error) {
+	return rebuildBucketIndex([]StorageAPI{fs.storage}, 1, 1)
+}
+
+// DrainObject - no-op for fs. Valid only for XL.
+func (fs fsObjects) DrainObject(bucket, object string) (bool, error) {
+	return false, traceError(NotImplemented{})
+}
+
+// ListPendingHeals - list objects pending heal. Valid only for XL
+func (fs fsObjects) ListPendingHeals() ([]PendingHealInfo, error) {
+	return nil, traceError(NotImplemented{})
+}
+
 // ListObjectsHeal - list all objects to be healed. Valid only for XL
 func (fs fsObjects) ListObjectsHeal(bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
 	return ListObjectsInfo{}, traceError(NotImplemented{})
@@ -65,7 +65,7 @@ func TestNewFS(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Unable to format XL %s", err)
 	}
-	_, err = newXLObjects(formattedDisks)
+	_, err = newXLObjects(formattedDisks, 0)
 	if err != nil {
 		t.Fatalf("Unable to initialize XL object, %s", err)
 	}
@@ -19,6 +19,7 @@ package cmd
 import (
 	"net/http"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
@@ -175,6 +176,50 @@ func (h minioPrivateBucketHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 	h.handler.ServeHTTP(w, r)
 }
 
+// readOnlyHandler - rejects mutating S3 API requests when the server
+// runs with --read-only. Requests to the reserved bucket, i.e. browser
+// and RPC requests, and admin requests are passed through.
+type readOnlyHandler struct {
+	handler http.Handler
+}
+
+func setReadOnlyHandler(h http.Handler) http.Handler {
+	return readOnlyHandler{handler: h}
+}
+
+func (h readOnlyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if globalIsReadOnly && !isReadOnlyReq(r) {
+		writeErrorResponse(w, ErrMethodNotAllowed, r.URL)
+		return
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
+// isReadOnlyReq - returns if a request is allowed in read-only mode,
+// i.e. it does not modify data or is not an S3 API request.
+func isReadOnlyReq(r *http.Request) bool {
+	if isReadMethod(r.Method) {
+		return true
+	}
+	urlPath := path.Clean(r.URL.Path)
+	if strings.HasPrefix(urlPath+slashSeparator, reservedBucket+slashSeparator) || isAdminReq(r, urlPath) {
+		return true
+	}
+	// SelectObjectContent only reads the object it queries.
+	query := r.URL.Query()
+	_, isSelect := query["select"]
+	return r.Method == "POST" && isSelect && query.Get("select-type") == "2"
+}
+
+// isReadMethod - returns if a request method does not modify data.
+func isReadMethod(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS":
+		return true
+	}
+	return false
+}
+
 type timeValidityHandler struct {
 	handler http.Handler
 }
@@ -249,6 +294,14 @@ type resourceHandler struct {
 	handler http.Handler
 }
 
+// corsHandler - answers preflight requests and sets the Access-Control
+// headers of cross-origin requests to buckets with CORS rules. Other
+// requests allow any origin, as before CORS rules of buckets.
+type corsHandler struct {
+	handler        http.Handler
+	defaultHandler http.Handler
+}
+
 // setCorsHandler handler for CORS (Cross Origin Resource Sharing)
 func setCorsHandler(h http.Handler) http.Handler {
 	c := cors.New(cors.Options{
@@ -257,7 +310,75 @@ func setCorsHandler(h http.Handler) http.Handler {
 		AllowedHeaders: []string{"*"},
 		ExposedHeaders: []string{"ETag"},
 	})
-	return c.Handler(h)
+	return corsHandler{handler: h, defaultHandler: c.Handler(h)}
+}
+
+// corsHandler ServeHTTP() wrapper
+func (h corsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, _ := urlPath2BucketObjectName(pathStyleURL(r))
+	var rules []corsRule
+	if bucket != "" {
+		rules = serverConfig.GetBucketCors(bucket)
+	}
+	if len(rules) == 0 {
+		h.defaultHandler.ServeHTTP(w, r)
+		return
+	}
+
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		// Not a cross-origin request.
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	// Preflight requests are answered without being authenticated.
+	if r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != "" {
+		method := r.Header.Get("Access-Control-Request-Method")
+		var headers []string
+		for _, header := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+			if header = strings.TrimSpace(header); header != "" {
+				headers = append(headers, header)
+			}
+		}
+		rule, ok := findCORSRule(rules, origin, method, headers)
+		if !ok {
+			writeErrorResponse(w, ErrCORSForbidden, r.URL)
+			return
+		}
+		setCORSHeaders(w, rule, origin)
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(rule.AllowedMethods, ", "))
+		if len(headers) > 0 {
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+		}
+		if rule.MaxAgeSeconds > 0 {
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(rule.MaxAgeSeconds))
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Responses to origins not allowed have no Access-Control headers,
+	// such that browsers do not expose them.
+	if rule, ok := findCORSRule(rules, origin, r.Method, nil); ok {
+		setCORSHeaders(w, rule, origin)
+		if len(rule.ExposeHeaders) > 0 {
+			w.Header().Set("Access-Control-Expose-Headers", strings.Join(rule.ExposeHeaders, ", "))
+		}
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
+// setCORSHeaders - sets the origin allowed by rule for requests from
+// origin, credentials are allowed unless any origin is.
+func setCORSHeaders(w http.ResponseWriter, rule corsRule, origin string) {
+	w.Header().Add("Vary", "Origin")
+	if contains(rule.AllowedOrigins, "*") {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Set("Access-Control-Allow-Credentials", "true")
 }
 
 // setIgnoreResourcesHandler -
@@ -291,8 +412,6 @@ func ignoreNotImplementedObjectResources(req *http.Request) bool {
 // List of not implemented bucket queries
 var notimplementedBucketResourceNames = map[string]bool{
 	"acl":            true,
-	"cors":           true,
-	"lifecycle":      true,
 	"logging":        true,
 	"replication":    true,
 	"tagging":        true,
@@ -311,7 +430,7 @@ var notimplementedObjectResourceNames = map[string]bool{
 
 // Resource handler ServeHTTP() wrapper
 func (h resourceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	bucketName, objectName := urlPath2BucketObjectName(r.URL)
+	bucketName, objectName := urlPath2BucketObjectName(pathStyleURL(r))
 
 	// If bucketName is present and not objectName check for bucket level resource queries.
 	if bucketName != "" && objectName == "" {
@@ -328,7 +447,7 @@ func (h resourceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	// A put method on path "/" doesn't make sense, ignore it.
-	if r.Method == "PUT" && r.URL.Path == "/" {
+	if r.Method == "PUT" && bucketName == "" {
 		writeErrorResponse(w, ErrNotImplemented, r.URL)
 		return
 	}
@@ -17,6 +17,8 @@
 package cmd
 
 import (
+	"bytes"
+	"io/ioutil"
 	"net/http"
 	"testing"
 )
@@ -88,3 +90,93 @@ func TestGuessIsBrowser(t *testing.T) {
 		t.Fatal("Test shouldn't report as browser for a non browser request.")
 	}
 }
+
+// Tests mutating S3 API requests are rejected in read-only mode and
+// reads are served.
+func TestReadOnlyHandler(t *testing.T) {
+	ts, stop := startTestServerWithPeers(t, "XL")
+	defer stop()
+
+	bucketName, objectName := getRandomBucketName(), "object"
+	if err := ts.Obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("hello, world")
+	if _, err := ts.Obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	csvData := []byte("name,age\nalice,34\n")
+	if _, err := ts.Obj.PutObject(bucketName, "people.csv", int64(len(csvData)), bytes.NewReader(csvData), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(readOnly bool) { globalIsReadOnly = readOnly }(globalIsReadOnly)
+	globalIsReadOnly = true
+
+	testCases := []struct {
+		method             string
+		url                string
+		body               []byte
+		rangeHeader        string
+		adminOp            string
+		expectedRespStatus int
+		expectedBody       []byte
+	}{
+		// Writes are rejected.
+		{"PUT", getPutObjectURL(ts.Server.URL, bucketName, "new-object"), data, "", "", http.StatusMethodNotAllowed, nil},
+		{"PUT", getMakeBucketURL(ts.Server.URL, getRandomBucketName()), nil, "", "", http.StatusMethodNotAllowed, nil},
+		{"DELETE", getDeleteObjectURL(ts.Server.URL, bucketName, objectName), nil, "", "", http.StatusMethodNotAllowed, nil},
+		{"DELETE", getDeleteBucketURL(ts.Server.URL, bucketName), nil, "", "", http.StatusMethodNotAllowed, nil},
+		{"POST", getNewMultipartURL(ts.Server.URL, bucketName, "new-object"), nil, "", "", http.StatusMethodNotAllowed, nil},
+		// Requests with the admin operation header not matching an
+		// admin route are S3 requests.
+		{"POST", getNewMultipartURL(ts.Server.URL, bucketName, "new-object"), nil, "", "set", http.StatusMethodNotAllowed, nil},
+		// Reads are served.
+		{"GET", getGetObjectURL(ts.Server.URL, bucketName, objectName), nil, "", "", http.StatusOK, data},
+		{"GET", getGetObjectURL(ts.Server.URL, bucketName, objectName), nil, "bytes=7-11", "", http.StatusPartialContent, data[7:]},
+		{"HEAD", getHeadObjectURL(ts.Server.URL, bucketName, objectName), nil, "", "", http.StatusOK, nil},
+		{"GET", getListObjectsV1URL(ts.Server.URL, bucketName, "10"), nil, "", "", http.StatusOK, nil},
+		{"GET", getListBucketURL(ts.Server.URL), nil, "", "", http.StatusOK, nil},
+		// SelectObjectContent only reads the object.
+		{"POST", getSelectObjectContentURL(ts.Server.URL, bucketName, "people.csv"),
+			selectRequestBody("SELECT name FROM S3Object", "<FileHeaderInfo>USE</FileHeaderInfo>", ""), "", "", http.StatusOK, nil},
+		// Admin requests are served.
+		{"POST", ts.Server.URL + "/?bucket-quota&bucket=missing-bucket&quota=20", nil, "", "set", http.StatusNotFound, nil},
+	}
+	for i, testCase := range testCases {
+		req, err := newTestSignedRequestV4(testCase.method, testCase.url, int64(len(testCase.body)),
+			bytes.NewReader(testCase.body), ts.AccessKey, ts.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if testCase.rangeHeader != "" {
+			req.Header.Set("Range", testCase.rangeHeader)
+		}
+		if testCase.adminOp != "" {
+			req.Header.Set(minioAdminOpHeader, testCase.adminOp)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if resp.StatusCode != testCase.expectedRespStatus {
+			t.Errorf("Test %d: expected status %d, got %d", i+1, testCase.expectedRespStatus, resp.StatusCode)
+		}
+		if testCase.expectedBody != nil && !bytes.Equal(body, testCase.expectedBody) {
+			t.Errorf("Test %d: expected body %q, got %q", i+1, testCase.expectedBody, body)
+		}
+	}
+
+	// Nothing was modified.
+	if _, err := ts.Obj.GetObjectInfo(bucketName, "new-object"); !isErrObjectNotFound(err) {
+		t.Errorf("Expected new-object not to be created, got %v", err)
+	}
+	if _, err := ts.Obj.GetObjectInfo(bucketName, objectName); err != nil {
+		t.Errorf("Expected object not to be deleted, got %v", err)
+	}
+}
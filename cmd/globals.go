@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"crypto/tls"
 	"crypto/x509"
 	"os"
 	"runtime"
@@ -26,6 +27,7 @@ import (
 	humanize "github.com/dustin/go-humanize"
 	"github.com/fatih/color"
 	"github.com/minio/cli"
+	"github.com/minio/dsync"
 	"github.com/minio/mc/pkg/console"
 	"github.com/minio/minio/pkg/objcache"
 )
@@ -41,6 +43,7 @@ const (
 	globalMinioConfigDir          = ".minio"
 	globalMinioCertsDir           = "certs"
 	globalMinioCertsCADir         = "CAs"
+	globalMinioCertsClientCADir   = "client-CAs"
 	globalMinioCertFile           = "public.crt"
 	globalMinioKeyFile            = "private.key"
 	globalMinioConfigFile         = "config.json"
@@ -59,11 +62,96 @@ const (
 
 var (
 	globalQuiet     = false               // quiet flag set via command line.
+	globalIsLogJSON = false               // json flag set via command line or env.
 	globalConfigDir = mustGetConfigPath() // config-dir flag set via command line
+	// Upgrade XL backend format of disks on a lower version, set via command line.
+	globalAutoFormatUpgrade = false
+	// Directory of the FS write-ahead log, set via command line.
+	globalFSWALDir = ""
+	// Accept signatures calculated for any region, set via command line.
+	globalIgnoreSignatureRegion = false
+	// Multipart upload limits, set via command line.
+	globalMaxPartID   = maxPartID
+	globalMinPartSize = int64(minPartSize)
+	globalMaxPartSize = int64(maxObjectSize)
+	// Objects larger than this are copied in parts of this size, set via command line.
+	globalCopyPartSize = int64(defaultCopyPartSize)
+	// Reclaim deleted objects in background, set via command line.
+	globalAsyncDelete = false
+	// Fail startup if any remote endpoint is unreachable, set via command line.
+	globalRequireFullMesh = false
+	// Fail startup if a local disk is on a network filesystem, set via command line.
+	globalRejectNetworkDisks = false
+	// Time to wait for laggard disks once write quorum is reached, set via command line.
+	globalWriteLaggardTimeout = time.Duration(0)
+	// Log durations of startup phases, set via command line.
+	globalStartupTiming = false
+	// Back-off between retries of distributed lock acquisition, set via command line.
+	globalLockRetryBackoff = dsync.RetryBackoff{}
+	// Time S3 requests wait for namespace locks before failing, zero
+	// waits indefinitely, set via command line.
+	globalLockTimeout = 30 * time.Second
+	// Retries and timeout of calls to peers failing with connection
+	// errors, set via command line.
+	globalPeerRPCRetries = 3
+	globalPeerRPCTimeout = 10 * time.Second
+	// Maximum number of open client connections, set via command line.
+	globalMaxConnections = 0
+	// Number of erasure blocks read ahead by GetObject, set via command line.
+	globalReadAheadBlocks = 0
+	// Maximum number of erasure blocks buffered by read-ahead of all requests, set via command line.
+	globalReadAheadMaxBlocks = 100
+	// Directory of the object metadata store, set via command line.
+	globalMetadataStoreDir = ""
+	// Reject mutating S3 API requests, set via command line.
+	globalIsReadOnly = false
+	// Time in-flight requests are drained for on shutdown, set via command line.
+	globalShutdownTimeout = 5 * time.Second
+	// Timeouts of client connections, zero disables them, set via
+	// command line.
+	globalReadHeaderTimeout = 30 * time.Second
+	globalReadTimeout       = time.Duration(0)
+	globalWriteTimeout      = time.Duration(0)
+	globalIdleTimeout       = 5 * time.Minute
+	// Serve Prometheus metrics without admin credentials, set via command line.
+	globalIsMetricsAnonymous = false
+	// Take client addresses from X-Forwarded-For, set via command line.
+	globalIsTrustedProxy = false
+	// Serve HTTP/2 on cleartext connections, set via command line.
+	globalIsH2CEnabled = false
+	// Interval between scans for expired objects, set via command line.
+	globalLifecycleInterval = time.Hour
+	// Aggregate bandwidth of downloads and uploads, nil if unlimited,
+	// set via command line.
+	globalEgressLimiter  *bandwidthLimiter
+	globalIngressLimiter *bandwidthLimiter
+	// Parity blocks of the standard storage class, zero for half the
+	// disks of an erasure set, set via env.
+	globalStorageClassParity = 0
+	// Checksum algorithm of objects in FS mode, empty if disabled,
+	// set via command line.
+	globalFSChecksumAlgo = ""
+	// Domain of virtual-hosted-style requests, empty if only path-style
+	// requests are served, set via env.
+	globalDomainName = ""
+	// Compress XML responses such as listings, set via command line.
+	globalIsCompressionEnabled = false
+	// Test local disks with a canary object at startup, set via command line.
+	globalIsSelfTest = false
+	// Master key sealing the keys of objects encrypted by the server,
+	// nil if server-side encryption is disabled, set via env.
+	globalSSEMasterKey []byte
+	// Maximum concurrent I/O operations per local disk, zero if
+	// unlimited, set via env.
+	globalDiskIOConcurrency = 0
 	// Add new global flags here.
 
 	globalIsDistXL = false // "Is Distributed?" flag.
 
+	// Object metadata store opened from globalMetadataStoreDir,
+	// nil if disabled.
+	globalMetaStore *metaStore
+
 	// This flag is set to 'true' by default, it is set to `false`
 	// when MINIO_BROWSER env is set to 'off'.
 	globalIsBrowserEnabled = !strings.EqualFold(os.Getenv("MINIO_BROWSER"), "off")
@@ -75,6 +163,9 @@ var (
 	// Cache expiry.
 	globalCacheExpiry = objcache.DefaultExpiry
 
+	// FS write-ahead log, initialized at startup when enabled.
+	globalFSWAL *fsWAL
+
 	// Minio local server address (in `host:port` format)
 	globalMinioAddr = ""
 	// Minio default port, can be changed through command line.
@@ -91,12 +182,52 @@ var (
 	// CA root certificates, a nil value means system certs pool will be used
 	globalRootCAs *x509.CertPool
 
+	// CAs client certificates are verified against, nil if there are
+	// none in the client-CAs certs dir.
+	globalClientCAs *x509.CertPool
+
+	// Require client certificates signed by globalClientCAs, set via
+	// command line.
+	globalIsClientCertRequired = false
+
+	// Certificate of this server presented to other servers, which
+	// require client certificates, by RPC clients.
+	globalRPCClientCerts []tls.Certificate
+
 	// IsSSL indicates if the server is configured with SSL.
 	globalIsSSL bool
 
 	// List of admin peers.
 	globalAdminPeers = adminPeers{}
 
+	// Time the server process started, set in serverMain.
+	globalBootTime = time.Now().UTC()
+
+	// Set of disks excluded from placement of new writes.
+	globalAvoidedDisks = newAvoidedDisks()
+
+	// Zones of endpoints, used to prefer reads from the local zone.
+	globalEndpointZones *endpointZones
+
+	// Per access key request accounting.
+	globalTenantAccounting = newTenantAccounting()
+
+	// S3 request counters served to Prometheus.
+	globalHTTPStats = newHTTPStats()
+
+	// Bytes stored in buckets with a quota.
+	globalBucketUsage = newBucketUsage()
+
+	// Info of existing buckets, served by GetBucketLocation and HeadBucket.
+	globalBucketCache = newBucketInfoCache()
+
+	// Set to 'true' to hash access keys used as metric labels, it
+	// is unset when MINIO_TENANT_LABEL_HASH env is set to 'off'.
+	globalTenantLabelHash = !strings.EqualFold(os.Getenv("MINIO_TENANT_LABEL_HASH"), "off")
+
+	// Limiter of open client connections of the API server.
+	globalConnLimiter *connLimiter
+
 	// Minio server user agent string.
 	globalServerUserAgent = "Minio/" + ReleaseTag + " (" + runtime.GOOS + "; " + runtime.GOARCH + ")"
 
@@ -132,4 +263,7 @@ func setGlobalsFromContext(c *cli.Context) {
 
 	// Set global quiet flag.
 	globalQuiet = c.Bool("quiet") || c.GlobalBool("quiet")
+
+	// Set global JSON logging flag, before loggers are enabled.
+	globalIsLogJSON = c.Bool("json") || c.GlobalBool("json") || strings.EqualFold(os.Getenv("MINIO_LOG_JSON"), "on")
 }
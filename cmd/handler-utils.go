@@ -22,6 +22,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"strings"
+	"time"
 )
 
 // Validates location constraint in PutBucket request body.
@@ -62,6 +63,7 @@ var supportedHeaders = []string{
 	"cache-control",
 	"content-encoding",
 	"content-disposition",
+	"content-language",
 	// Add more supported headers here.
 }
 
@@ -131,6 +133,46 @@ func extractMetadataFromHeader(header http.Header) map[string]string {
 	return metadata
 }
 
+// Minio extension header setting modification time of an uploaded
+// object, instead of the time of upload.
+const minioModTimeHeader = "X-Minio-Mtime"
+
+// Metadata key saving the requested modification time in user defined
+// metadata, the modification time in `xl.json` remains the time of
+// upload as it is used for quorum and healing.
+const modTimeMetaKey = "mtime"
+
+// extractModTimeFromHeader - validates modification time set by
+// x-minio-mtime in RFC 3339 format and saves it into metadata. Times
+// before the Unix epoch or further in the future than the allowed
+// clock skew are rejected.
+func extractModTimeFromHeader(header http.Header, metadata map[string]string) APIErrorCode {
+	value := header.Get(minioModTimeHeader)
+	if value == "" {
+		return ErrNone
+	}
+	modTime, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return ErrInvalidModTime
+	}
+	if modTime.Before(time.Unix(0, 0)) || modTime.After(time.Now().Add(globalMaxSkewTime)) {
+		return ErrInvalidModTime
+	}
+	metadata[modTimeMetaKey] = modTime.UTC().Format(time.RFC3339Nano)
+	return ErrNone
+}
+
+// modTimeFromMetadata - returns modification time saved in metadata
+// by extractModTimeFromHeader, defaults to modTime.
+func modTimeFromMetadata(metadata map[string]string, modTime time.Time) time.Time {
+	if value, ok := metadata[modTimeMetaKey]; ok {
+		if requested, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return requested
+		}
+	}
+	return modTime
+}
+
 // extractMetadataFromForm extracts metadata from Post Form.
 func extractMetadataFromForm(formValues map[string]string) map[string]string {
 	metadata := make(map[string]string)
@@ -157,6 +199,19 @@ func extractMetadataFromForm(formValues map[string]string) map[string]string {
 	return metadata
 }
 
+// isMaxUserMetadataSize - verify if user metadata is larger than the
+// configured maximum. As in S3 the size is the sum of the length of
+// each X-Amz-Meta- key, without the prefix, and its value.
+func isMaxUserMetadataSize(metadata map[string]string) bool {
+	var size int64
+	for key, value := range metadata {
+		if strings.HasPrefix(key, "X-Amz-Meta-") {
+			size += int64(len(strings.TrimPrefix(key, "X-Amz-Meta-")) + len(value))
+		}
+	}
+	return size > serverConfig.GetMaxUserMetadataSize()
+}
+
 // Extract form fields and file data from a HTTP POST Policy
 func extractPostPolicyFormValues(reader *multipart.Reader) (filePart io.Reader, fileName string, formValues map[string]string, err error) {
 	/// HTML Form values
@@ -98,6 +98,21 @@ func TestExtractMetadataHeaders(t *testing.T) {
 				"content-type": "image/png",
 			},
 		},
+		// Validate if standard headers served back on download are saved.
+		{
+			header: http.Header{
+				"Cache-Control":       []string{"max-age=3600"},
+				"Content-Encoding":    []string{"gzip"},
+				"Content-Disposition": []string{"attachment"},
+				"Content-Language":    []string{"en-US"},
+			},
+			metadata: map[string]string{
+				"cache-control":       "max-age=3600",
+				"content-encoding":    "gzip",
+				"content-disposition": "attachment",
+				"content-language":    "en-US",
+			},
+		},
 		// Validate if there are no keys to extract.
 		{
 			header: http.Header{
@@ -0,0 +1,90 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+
+	router "github.com/gorilla/mux"
+)
+
+// Health check paths.
+const (
+	healthPath      = "/health"
+	healthLivePath  = "/live"
+	healthReadyPath = "/ready"
+)
+
+// countOnlineDisks - returns the number of disks which report their
+// disk info. Unlike getDisksInfo() offline disks are not logged, as
+// health checks are polled often.
+func countOnlineDisks(disks []StorageAPI) (onlineDisks int) {
+	for _, disk := range disks {
+		if disk == nil {
+			continue
+		}
+		if _, err := disk.DiskInfo(); err == nil {
+			onlineDisks++
+		}
+	}
+	return onlineDisks
+}
+
+// isReadQuorumOnline - returns true if enough disks of the object layer
+// are online to serve reads, every erasure set must have read quorum.
+func isReadQuorumOnline(objAPI ObjectLayer) bool {
+	switch obj := objAPI.(type) {
+	case fsObjects:
+		return countOnlineDisks([]StorageAPI{obj.storage}) == 1
+	case *xlObjects:
+		return countOnlineDisks(obj.storageDisks) >= obj.readQuorum
+	case sseObjects:
+		return isReadQuorumOnline(obj.ObjectLayer)
+	case *xlSets:
+		for _, xl := range obj.sets {
+			if !isReadQuorumOnline(xl) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// healthLiveHandler - replies 200 OK as long as the server is up.
+func healthLiveHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+// healthReadyHandler - replies 200 OK when the object layer is
+// initialized and has read quorum, 503 Service Unavailable otherwise.
+func healthReadyHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := newObjectLayerFn()
+	if objAPI == nil || !isReadQuorumOnline(objAPI) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// registerHealthRouter - registers unauthenticated liveness and
+// readiness endpoints for load balancers and orchestrators.
+func registerHealthRouter(mux *router.Router) {
+	healthRouter := mux.NewRoute().PathPrefix(reservedBucket + healthPath).Subrouter()
+	healthRouter.Methods("GET", "HEAD").Path(healthLivePath).HandlerFunc(healthLiveHandler)
+	healthRouter.Methods("GET", "HEAD").Path(healthReadyPath).HandlerFunc(healthReadyHandler)
+}
@@ -0,0 +1,96 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"os"
+	"testing"
+)
+
+// Tests liveness and readiness reflect the read quorum of the disks.
+func TestHealthHandlers(t *testing.T) {
+	ts, stop := startTestServerWithPeers(t, "XL")
+	defer stop()
+
+	probe := func(method, path string) int {
+		req, err := http.NewRequest(method, ts.Server.URL+reservedBucket+healthPath+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	expectStatus := func(path string, expected int) {
+		for _, method := range []string{"GET", "HEAD"} {
+			if status := probe(method, path); status != expected {
+				t.Errorf("%s %s: Expected %d, got %d", method, path, expected, status)
+			}
+		}
+	}
+
+	expectStatus(healthLivePath, http.StatusOK)
+	expectStatus(healthReadyPath, http.StatusOK)
+
+	// Take disks offline one by one, readiness is lost once less
+	// than read quorum of the disks are online.
+	readQuorum := len(ts.Disks) / 2
+	for i, disk := range ts.Disks {
+		if err := os.RemoveAll(disk.Path); err != nil {
+			t.Fatal(err)
+		}
+		expected := http.StatusOK
+		if len(ts.Disks)-(i+1) < readQuorum {
+			expected = http.StatusServiceUnavailable
+		}
+		expectStatus(healthReadyPath, expected)
+		// Server stays live regardless of its disks.
+		expectStatus(healthLivePath, http.StatusOK)
+		if expected != http.StatusOK {
+			break
+		}
+	}
+
+	// Not ready without an object layer.
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = nil
+	globalObjLayerMutex.Unlock()
+	expectStatus(healthReadyPath, http.StatusServiceUnavailable)
+}
+
+// Tests readiness of a single disk FS backend.
+func TestIsReadQuorumOnlineFS(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(fsDir)
+
+	if !isReadQuorumOnline(obj) {
+		t.Fatal("Expected FS backend to be ready")
+	}
+	if err = os.RemoveAll(fsDir); err != nil {
+		t.Fatal(err)
+	}
+	if isReadQuorumOnline(obj) {
+		t.Fatal("Expected FS backend without its disk not to be ready")
+	}
+}
@@ -118,6 +118,11 @@ func parseRequestRange(rangeString string, resourceSize int64) (hrange *httpRang
 			return nil, errInvalidRange
 		}
 
+		if resourceSize == 0 {
+			// An empty resource has no last bytes to return.
+			return nil, errInvalidRange
+		}
+
 		if offsetEnd >= resourceSize {
 			offsetBegin = 0
 		} else {
@@ -132,3 +137,38 @@ func parseRequestRange(rangeString string, resourceSize int64) (hrange *httpRang
 
 	return &httpRange{offsetBegin, offsetEnd, resourceSize}, nil
 }
+
+// parseCopyPartRange - parses the x-amz-copy-source-range of
+// UploadPartCopy, which unlike the Range header always holds both
+// positions, eg. "bytes=0-4095". Ranges beyond the end of the source
+// are rejected with errInvalidRange instead of being truncated.
+func parseCopyPartRange(rangeString string, resourceSize int64) (hrange *httpRange, err error) {
+	// Return error if given range string doesn't start with byte range prefix.
+	if !strings.HasPrefix(rangeString, byteRangePrefix) {
+		return nil, fmt.Errorf("'%s' does not start with '%s'", rangeString, byteRangePrefix)
+	}
+
+	offsets := strings.SplitN(strings.TrimPrefix(rangeString, byteRangePrefix), "-", 2)
+	if len(offsets) != 2 || !validBytePos.MatchString(offsets[0]) || !validBytePos.MatchString(offsets[1]) {
+		return nil, fmt.Errorf("'%s' does not have valid first and last byte positions", rangeString)
+	}
+
+	offsetBegin, err := strconv.ParseInt(offsets[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' does not have a valid first byte position value", rangeString)
+	}
+	offsetEnd, err := strconv.ParseInt(offsets[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' does not have a valid last byte position value", rangeString)
+	}
+	if offsetBegin > offsetEnd {
+		return nil, fmt.Errorf("'%s' does not have valid range value", rangeString)
+	}
+
+	// Both positions must lie within the source object.
+	if offsetEnd >= resourceSize {
+		return nil, errInvalidRange
+	}
+
+	return &httpRange{offsetBegin, offsetEnd, resourceSize}, nil
+}
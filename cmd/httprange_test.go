@@ -34,6 +34,8 @@ func TestParseRequestRange(t *testing.T) {
 		{"bytes=2-", 2, 9, 8},
 		{"bytes=-4", 6, 9, 4},
 		{"bytes=-20", 0, 9, 10},
+		{"bytes=0-0", 0, 0, 1},
+		{"bytes=-1", 9, 9, 1},
 	}
 
 	for _, successCase := range successCases {
@@ -87,4 +89,71 @@ func TestParseRequestRange(t *testing.T) {
 			t.Fatalf("expected: %s, got: %s", errInvalidRange, err)
 		}
 	}
+
+	// Test ranges of an empty resource.
+	for _, rangeString := range []string{"bytes=0-0", "bytes=-1", "bytes=0-"} {
+		if _, err := parseRequestRange(rangeString, 0); err != errInvalidRange {
+			t.Fatalf("expected: %s, got: %s", errInvalidRange, err)
+		}
+	}
+}
+
+// Test parseCopyPartRange()
+func TestParseCopyPartRange(t *testing.T) {
+	// Test success cases.
+	successCases := []struct {
+		rangeString string
+		offsetBegin int64
+		offsetEnd   int64
+		length      int64
+	}{
+		{"bytes=2-5", 2, 5, 4},
+		{"bytes=0-9", 0, 9, 10},
+		{"bytes=2-2", 2, 2, 1},
+		{"bytes=0000-0006", 0, 6, 7},
+	}
+
+	for _, successCase := range successCases {
+		hrange, err := parseCopyPartRange(successCase.rangeString, 10)
+		if err != nil {
+			t.Fatalf("expected: <nil>, got: %s", err)
+		}
+
+		if hrange.offsetBegin != successCase.offsetBegin {
+			t.Fatalf("expected: %d, got: %d", successCase.offsetBegin, hrange.offsetBegin)
+		}
+
+		if hrange.offsetEnd != successCase.offsetEnd {
+			t.Fatalf("expected: %d, got: %d", successCase.offsetEnd, hrange.offsetEnd)
+		}
+		if hrange.getLength() != successCase.length {
+			t.Fatalf("expected: %d, got: %d", successCase.length, hrange.getLength())
+		}
+	}
+
+	// Test invalid range strings, copy ranges need both positions.
+	invalidRangeStrings := []string{
+		"bytes=8",
+		"bytes=5-2",
+		"bytes=2-",
+		"bytes=-4",
+		"bytes=-",
+		"bytes=2--5",
+		"",
+		"2-5",
+		"bytes = 2-5",
+		"bytes=0-0,2-3",
+	}
+	for _, rangeString := range invalidRangeStrings {
+		if _, err := parseCopyPartRange(rangeString, 10); err == nil || err == errInvalidRange {
+			t.Fatalf("%s: expected: a parse error, got: %v", rangeString, err)
+		}
+	}
+
+	// Test ranges beyond the end of the resource, which are not truncated.
+	for _, rangeString := range []string{"bytes=2-10", "bytes=10-10", "bytes=20-30"} {
+		if _, err := parseCopyPartRange(rangeString, 10); err != errInvalidRange {
+			t.Fatalf("%s: expected: %s, got: %v", rangeString, errInvalidRange, err)
+		}
+	}
 }
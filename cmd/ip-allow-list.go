@@ -0,0 +1,168 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+
+	router "github.com/gorilla/mux"
+)
+
+// ipAllowList - address ranges in CIDR notation, e.g. "10.0.0.0/8",
+// or single addresses of clients allowed to access the admin API and
+// the S3 API. An empty list allows all clients.
+type ipAllowList struct {
+	Admin []string `json:"admin,omitempty"`
+	S3    []string `json:"s3,omitempty"`
+}
+
+// validate - verifies all the address ranges are valid.
+func (l ipAllowList) validate() error {
+	if _, err := parseIPRanges(l.Admin); err != nil {
+		return err
+	}
+	_, err := parseIPRanges(l.S3)
+	return err
+}
+
+// parseIPRanges - parses address ranges in CIDR notation, single
+// addresses are ranges of their own, nil if ranges is empty.
+func parseIPRanges(ranges []string) ([]*net.IPNet, error) {
+	var ipNets []*net.IPNet
+	for _, ipRange := range ranges {
+		if ip := net.ParseIP(ipRange); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			ipNets = append(ipNets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(ipRange)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid address range %s, expected an address or a range in CIDR notation", ipRange)
+		}
+		ipNets = append(ipNets, ipNet)
+	}
+	return ipNets, nil
+}
+
+// getClientIP - returns the address of the client of r, taken from
+// the last X-Forwarded-For entry, added by the proxy in front of the
+// server, if the proxy is trusted. Nil if the address is not valid.
+func getClientIP(r *http.Request) net.IP {
+	if globalIsTrustedProxy {
+		if forwardedFor := r.Header[http.CanonicalHeaderKey("X-Forwarded-For")]; len(forwardedFor) > 0 {
+			addrs := strings.Split(forwardedFor[len(forwardedFor)-1], ",")
+			return net.ParseIP(strings.TrimSpace(addrs[len(addrs)-1]))
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+// isInternodeReq - returns true for paths of RPC between the servers
+// of a distributed setup and of health checks, which are never
+// restricted by address.
+func isInternodeReq(urlPath string) bool {
+	for _, prefix := range []string{
+		reservedBucket + adminPath,
+		lockRPCPath,
+		storageRPCPath,
+		reservedBucket + s3Path,
+		reservedBucket + browserPeerPath,
+		reservedBucket + healthPath,
+	} {
+		if urlPath == prefix || strings.HasPrefix(urlPath, prefix+slashSeparator) {
+			return true
+		}
+	}
+	return false
+}
+
+// Routes of the admin API, requests carrying the admin operation
+// header are only admin requests if they match one of them.
+var adminRoutes = newAdminRoutes()
+
+func newAdminRoutes() *router.Router {
+	mux := router.NewRouter()
+	registerAdminRouter(mux)
+	return mux
+}
+
+// isAdminReq - returns true for admin API and metrics requests, other
+// requests are served by the S3 API whatever their headers.
+func isAdminReq(r *http.Request, urlPath string) bool {
+	if urlPath == reservedBucket+metricsPath {
+		return true
+	}
+	if r.Header.Get(minioAdminOpHeader) == "" {
+		return false
+	}
+	var match router.RouteMatch
+	return adminRoutes.Match(r, &match)
+}
+
+// ipAllowListHandler - rejects requests of clients outside of the
+// address ranges allowed to access the admin API and the S3 API, the
+// latter also apply to the browser. Ranges are read from the config
+// on every request, such that a config reload applies to them.
+type ipAllowListHandler struct {
+	handler http.Handler
+}
+
+func setIPAllowListHandler(h http.Handler) http.Handler {
+	return ipAllowListHandler{handler: h}
+}
+
+func (h ipAllowListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	urlPath := path.Clean(r.URL.Path)
+	allowList := serverConfig.GetIPAllowList()
+	ranges := allowList.S3
+	if isAdminReq(r, urlPath) {
+		ranges = allowList.Admin
+	} else if isInternodeReq(urlPath) {
+		ranges = nil
+	}
+	// Address ranges are validated when config is loaded.
+	allowed, _ := parseIPRanges(ranges)
+	if len(allowed) > 0 && !isIPAllowed(getClientIP(r), allowed) {
+		writeErrorResponse(w, ErrAccessDenied, r.URL)
+		return
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
+// isIPAllowed - returns true if ip is in any of the address ranges.
+func isIPAllowed(ip net.IP, allowed []*net.IPNet) bool {
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range allowed {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests validation of address ranges.
+func TestIPAllowListValidate(t *testing.T) {
+	testCases := []struct {
+		allowList ipAllowList
+		success   bool
+	}{
+		{ipAllowList{}, true},
+		{ipAllowList{Admin: []string{"10.0.0.0/8", "192.168.1.10"}, S3: []string{"::1", "fd00::/8"}}, true},
+		{ipAllowList{Admin: []string{"10.0.0.0/33"}}, false},
+		{ipAllowList{S3: []string{"localhost"}}, false},
+	}
+	for i, testCase := range testCases {
+		if err := testCase.allowList.validate(); (err == nil) != testCase.success {
+			t.Errorf("Test %d: expected success %t, got error %v", i+1, testCase.success, err)
+		}
+	}
+}
+
+// Tests requests from allowed and disallowed addresses.
+func TestIPAllowListHandler(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+	defer func(trustedProxy bool) { globalIsTrustedProxy = trustedProxy }(globalIsTrustedProxy)
+
+	serverConfig.SetIPAllowList(&ipAllowList{
+		Admin: []string{"10.0.0.1"},
+		S3:    []string{"10.0.0.0/8", "192.168.0.0/16"},
+	})
+	handler := setIPAllowListHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	testCases := []struct {
+		method         string
+		url            string
+		adminOp        string
+		remoteAddr     string
+		forwardedFor   string
+		trustedProxy   bool
+		expectedStatus int
+	}{
+		// S3 API.
+		{"GET", "/bucket/object", "", "10.1.2.3:1234", "", false, http.StatusOK},
+		{"PUT", "/bucket", "", "192.168.5.5:1234", "", false, http.StatusOK},
+		{"GET", "/bucket/object", "", "172.16.0.1:1234", "", false, http.StatusForbidden},
+		{"GET", "/", "", "[::1]:1234", "", false, http.StatusForbidden},
+		// Browser is restricted like the S3 API.
+		{"POST", "/minio/webrpc", "", "172.16.0.1:1234", "", false, http.StatusForbidden},
+		{"POST", "/minio/webrpc", "", "10.1.2.3:1234", "", false, http.StatusOK},
+		// Admin API.
+		{"GET", "/?service", "status", "10.0.0.1:1234", "", false, http.StatusOK},
+		{"GET", "/?service", "status", "10.1.2.3:1234", "", false, http.StatusForbidden},
+		{"GET", "/minio/metrics", "", "10.1.2.3:1234", "", false, http.StatusForbidden},
+		// Requests with the admin operation header not matching an
+		// admin route are S3 requests.
+		{"GET", "/bucket/object", "x", "10.1.2.3:1234", "", false, http.StatusOK},
+		{"GET", "/bucket/object", "status", "172.16.0.1:1234", "", false, http.StatusForbidden},
+		{"POST", "/?service", "status", "10.1.2.3:1234", "", false, http.StatusOK},
+		// Internode RPC and health checks are never restricted.
+		{"POST", "/minio/lock/data", "", "172.16.0.1:1234", "", false, http.StatusOK},
+		{"POST", "/minio/storage/data", "", "172.16.0.1:1234", "", false, http.StatusOK},
+		{"POST", "/minio/admin", "", "172.16.0.1:1234", "", false, http.StatusOK},
+		{"GET", "/minio/health/live", "", "172.16.0.1:1234", "", false, http.StatusOK},
+		// X-Forwarded-For is ignored unless the proxy is trusted.
+		{"GET", "/bucket/object", "", "172.16.0.1:1234", "10.1.2.3", false, http.StatusForbidden},
+		{"GET", "/bucket/object", "", "10.1.2.3:1234", "172.16.0.1", false, http.StatusOK},
+		{"GET", "/bucket/object", "", "172.16.0.1:1234", "10.1.2.3", true, http.StatusOK},
+		{"GET", "/bucket/object", "", "10.1.2.3:1234", "172.16.0.1", true, http.StatusForbidden},
+		// Only the entry added by the proxy is used, clients may send
+		// any other entries.
+		{"GET", "/bucket/object", "", "172.16.0.1:1234", "10.1.2.3, 172.16.0.2", true, http.StatusForbidden},
+		{"GET", "/bucket/object", "", "172.16.0.1:1234", "172.16.0.2, 10.1.2.3", true, http.StatusOK},
+		{"GET", "/bucket/object", "", "172.16.0.1:1234", "invalid", true, http.StatusForbidden},
+	}
+	for i, testCase := range testCases {
+		globalIsTrustedProxy = testCase.trustedProxy
+		req, err := http.NewRequest(testCase.method, testCase.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.RemoteAddr = testCase.remoteAddr
+		if testCase.adminOp != "" {
+			req.Header.Set(minioAdminOpHeader, testCase.adminOp)
+		}
+		if testCase.forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", testCase.forwardedFor)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedStatus {
+			t.Errorf("Test %d: expected status %d, got %d", i+1, testCase.expectedStatus, rec.Code)
+		}
+	}
+
+	// The admin operation header does not lift the S3 address ranges
+	// when the admin API is open to all clients. Changed ranges apply
+	// to the next requests.
+	serverConfig.SetIPAllowList(&ipAllowList{S3: []string{"10.0.0.0/8"}})
+	req, err := http.NewRequest("GET", "/bucket/object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.RemoteAddr = "172.16.0.1:1234"
+	req.Header.Set(minioAdminOpHeader, "x")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, rec.Code)
+	}
+
+	// Without address ranges all clients are allowed.
+	serverConfig.SetIPAllowList(nil)
+	req, err = http.NewRequest("GET", "/bucket/object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.RemoteAddr = "172.16.0.1:1234"
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+}
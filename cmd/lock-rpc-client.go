@@ -69,3 +69,10 @@ func (lockRPCClient *LockRPCClient) Expired(args dsync.LockArgs) (reply bool, er
 	err = lockRPCClient.AuthRPCClient.Call("Dsync.Expired", &lockArgs, &reply)
 	return reply, err
 }
+
+// ReleaseNodeLocks calls release node locks RPC.
+func (lockRPCClient *LockRPCClient) ReleaseNodeLocks(node, bootID string, held bool) (released int, err error) {
+	args := ReleaseNodeLocksArgs{Node: node, BootID: bootID, Held: held}
+	err = lockRPCClient.AuthRPCClient.Call("Dsync.ReleaseNodeLocks", &args, &released)
+	return released, err
+}
@@ -72,3 +72,25 @@ func getLongLivedLocks(m map[string][]lockRequesterInfo, interval time.Duration)
 	}
 	return rslt
 }
+
+// releaseNodeLocks removes the locks claimed by node with another boot ID
+// than bootID, or with bootID if held is set, returns the number of locks
+// removed.
+func (l *lockServer) releaseNodeLocks(node, bootID string, held bool) (released int) {
+	for name, lri := range l.lockMap {
+		var kept []lockRequesterInfo
+		for _, entry := range lri {
+			if entry.node == node && (entry.bootID == bootID) == held {
+				released++
+				continue
+			}
+			kept = append(kept, entry)
+		}
+		if len(kept) == 0 {
+			delete(l.lockMap, name)
+		} else {
+			l.lockMap[name] = kept
+		}
+	}
+	return released
+}
@@ -45,6 +45,7 @@ type lockRequesterInfo struct {
 	node          string    // Network address of client claiming lock
 	rpcPath       string    // RPC path of client claiming lock
 	uid           string    // Uid to uniquely identify request of client
+	bootID        string    // Boot ID of the process of client claiming lock
 	timestamp     time.Time // Timestamp set at the time of initialization
 	timeLastCheck time.Time // Timestamp for last check of validity of lock
 }
@@ -145,6 +146,7 @@ func (l *lockServer) Lock(args *LockArgs, reply *bool) error {
 				node:          args.LockArgs.ServerAddr,
 				rpcPath:       args.LockArgs.ServiceEndpoint,
 				uid:           args.LockArgs.UID,
+				bootID:        args.BootID,
 				timestamp:     time.Now().UTC(),
 				timeLastCheck: time.Now().UTC(),
 			},
@@ -186,6 +188,7 @@ func (l *lockServer) RLock(args *LockArgs, reply *bool) error {
 		node:          args.LockArgs.ServerAddr,
 		rpcPath:       args.LockArgs.ServiceEndpoint,
 		uid:           args.LockArgs.UID,
+		bootID:        args.BootID,
 		timestamp:     time.Now().UTC(),
 		timeLastCheck: time.Now().UTC(),
 	}
@@ -237,6 +240,19 @@ func (l *lockServer) ForceUnlock(args *LockArgs, reply *bool) error {
 	return nil
 }
 
+// ReleaseNodeLocks - rpc handler releasing the locks claimed by previous
+// processes of a restarted node, or by the current process of a node
+// shutting down, replies the number of locks released.
+func (l *lockServer) ReleaseNodeLocks(args *ReleaseNodeLocksArgs, reply *int) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+	*reply = l.releaseNodeLocks(args.Node, args.BootID, args.Held)
+	return nil
+}
+
 // Expired - rpc handler for expired lock status.
 func (l *lockServer) Expired(args *LockArgs, reply *bool) error {
 	l.mutex.Lock()
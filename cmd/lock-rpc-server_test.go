@@ -17,6 +17,8 @@
 package cmd
 
 import (
+	"net/http/httptest"
+	"net/rpc"
 	"net/url"
 	"runtime"
 	"sync"
@@ -444,6 +446,127 @@ func TestLockRpcServerExpired(t *testing.T) {
 	}
 }
 
+// Test release of the locks of a restarted node.
+func TestLockRpcServerReleaseNodeLocks(t *testing.T) {
+	testPath, locker, token := createLockTestServer(t)
+	defer removeAll(testPath)
+	defer func(bootID string) { globalNodeBootID = bootID }(globalNodeBootID)
+
+	// claim - claims a lock on resource for node with the current boot ID.
+	claim := func(resource, node, uid string, readLock bool) {
+		la := newLockArgs(dsync.LockArgs{
+			UID:             uid,
+			Resource:        resource,
+			ServerAddr:      node,
+			ServiceEndpoint: "rpc-path",
+		})
+		la.SetAuthToken(token)
+		la.SetRequestTime(time.Now().UTC())
+		var result bool
+		var err error
+		if readLock {
+			err = locker.RLock(&la, &result)
+		} else {
+			err = locker.Lock(&la, &result)
+		}
+		if err != nil || !result {
+			t.Fatalf("Expected lock on %s by %s to be granted, got %t, %v", resource, node, result, err)
+		}
+	}
+
+	// Locks claimed before the restart of node.
+	claim("name1", "node", "uid-1", false)
+	claim("name2", "node", "uid-2", true)
+	claim("name2", "other-node", "uid-3", true)
+	claim("name3", "other-node", "uid-4", false)
+
+	// Locks claimed by node after its restart have another boot ID.
+	globalNodeBootID = mustGetUUID()
+	claim("name2", "node", "uid-5", true)
+
+	args := ReleaseNodeLocksArgs{Node: "node", BootID: globalNodeBootID}
+	args.SetAuthToken(token)
+	args.SetRequestTime(time.Now().UTC())
+	var released int
+	if err := locker.ReleaseNodeLocks(&args, &released); err != nil {
+		t.Fatalf("Expected %#v, got %#v", nil, err)
+	}
+	if released != 2 {
+		t.Errorf("Expected 2 locks to be released, got %d", released)
+	}
+
+	expectedLockMap := map[string][]lockRequesterInfo{
+		"name2": {
+			{writer: false, node: "other-node", rpcPath: "rpc-path", uid: "uid-3"},
+			{writer: false, node: "node", rpcPath: "rpc-path", uid: "uid-5"},
+		},
+		"name3": {
+			{writer: true, node: "other-node", rpcPath: "rpc-path", uid: "uid-4"},
+		},
+	}
+	if len(locker.lockMap) != len(expectedLockMap) {
+		t.Fatalf("Expected locks %#v, got %#v", expectedLockMap, locker.lockMap)
+	}
+	for name, expectedLri := range expectedLockMap {
+		if !testLockEquality(expectedLri, locker.lockMap[name]) {
+			t.Errorf("Lock %s: Expected %#v, got %#v", name, expectedLri, locker.lockMap[name])
+		}
+	}
+
+	// Released lock is granted right away.
+	claim("name1", "other-node", "uid-6", false)
+
+	// Locks of the current process of node are released on shutdown.
+	args = ReleaseNodeLocksArgs{Node: "node", BootID: globalNodeBootID, Held: true}
+	args.SetAuthToken(token)
+	args.SetRequestTime(time.Now().UTC())
+	if err := locker.ReleaseNodeLocks(&args, &released); err != nil {
+		t.Fatalf("Expected %#v, got %#v", nil, err)
+	}
+	if released != 1 {
+		t.Errorf("Expected 1 lock to be released, got %d", released)
+	}
+	expectedLri := []lockRequesterInfo{{writer: false, node: "other-node", rpcPath: "rpc-path", uid: "uid-3"}}
+	if !testLockEquality(expectedLri, locker.lockMap["name2"]) {
+		t.Errorf("Lock name2: Expected %#v, got %#v", expectedLri, locker.lockMap["name2"])
+	}
+}
+
+// Test release of the locks of a restarted node on all lock servers.
+func TestReleaseNodeLocks(t *testing.T) {
+	testPath, locker, _ := createLockTestServer(t)
+	defer removeAll(testPath)
+
+	locker.lockMap["name"] = []lockRequesterInfo{{writer: true, node: "node", uid: "uid", bootID: "old-boot-id"}}
+
+	lockRPCServer := rpc.NewServer()
+	if err := lockRPCServer.RegisterName("Dsync", locker); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(lockRPCServer)
+	defer ts.Close()
+
+	creds := serverConfig.GetCredential()
+	newClient := func(serverAddr string) *LockRPCClient {
+		return newLockRPCClient(authConfig{
+			accessKey:        creds.AccessKey,
+			secretKey:        creds.SecretKey,
+			serverAddr:       serverAddr,
+			serviceEndpoint:  "/lock",
+			serviceName:      "Dsync",
+			disableReconnect: true,
+		})
+	}
+	// Unreachable lock servers are skipped.
+	clnts := []*LockRPCClient{newClient("127.0.0.1:" + getFreePort()), newClient(ts.Listener.Addr().String())}
+	if released := releaseNodeLocks(clnts, "node", "new-boot-id", false); released != 1 {
+		t.Errorf("Expected 1 lock to be released, got %d", released)
+	}
+	if _, ok := locker.lockMap["name"]; ok {
+		t.Errorf("Expected lock of previous process of node to be released")
+	}
+}
+
 // Test initialization of lock servers.
 func TestLockServers(t *testing.T) {
 	if runtime.GOOS == "windows" {
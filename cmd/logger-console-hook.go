@@ -40,6 +40,9 @@ func enableConsoleLogger() {
 
 	consoleLogger.Level = lvl
 	consoleLogger.Formatter = new(logrus.TextFormatter)
+	if globalIsLogJSON {
+		consoleLogger.Formatter = jsonFormatter{}
+	}
 	log.mu.Lock()
 	log.loggers = append(log.loggers, consoleLogger)
 	log.mu.Unlock()
@@ -17,13 +17,17 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"path"
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/fatih/color"
 )
 
 type fields map[string]interface{}
@@ -44,6 +48,46 @@ type logger struct {
 	// Add new loggers here.
 }
 
+// jsonFormatter - formats log entries as single line JSON objects with
+// level, time, message and error keys, along with the other fields.
+type jsonFormatter struct{}
+
+func (f jsonFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	data := make(logrus.Fields, len(entry.Data)+3)
+	for key, value := range entry.Data {
+		// Cause of errorIf() and fatalIf() is the error.
+		if key == "cause" {
+			key = "error"
+		}
+		data[key] = value
+	}
+	data["level"] = entry.Level.String()
+	data["time"] = entry.Time.UTC().Format(time.RFC3339Nano)
+	data["message"] = entry.Message
+
+	line, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal log entry to JSON, %s", err)
+	}
+	return append(line, '\n'), nil
+}
+
+// printJSONLine - prints a message as a single line JSON object to the
+// console regardless of the log level, used for startup information.
+func printJSONLine(level logrus.Level, msg string, data logrus.Fields) {
+	line, err := jsonFormatter{}.Format(&logrus.Entry{
+		Data:    data,
+		Time:    time.Now(),
+		Level:   level,
+		Message: msg,
+	})
+	if err != nil {
+		errorIf(err, "Unable to print %s", msg)
+		return
+	}
+	color.Output.Write(line)
+}
+
 // Get file, line, function name of the caller.
 func callerSource() string {
 	pc, file, line, success := runtime.Caller(2)
@@ -76,6 +120,42 @@ func errorIf(err error, msg string, data ...interface{}) {
 	}
 }
 
+// requestErrorIf - errorIf for errors of an API request, logged with
+// the ID of the request.
+func requestErrorIf(r *http.Request, err error, msg string, data ...interface{}) {
+	if err == nil || !isErrLogged(err) {
+		return
+	}
+	logRequestError(callerSource(), getRequestID(r), err, msg, data...)
+}
+
+// requestIDErrorIf - errorIf for errors of a peer RPC made for the
+// API request of requestID, empty if none.
+func requestIDErrorIf(requestID string, err error, msg string, data ...interface{}) {
+	if err == nil || !isErrLogged(err) {
+		return
+	}
+	logRequestError(callerSource(), requestID, err, msg, data...)
+}
+
+// logRequestError - logs err of the request of requestID at source.
+func logRequestError(source, requestID string, err error, msg string, data ...interface{}) {
+	fields := logrus.Fields{
+		"source": source,
+		"cause":  err.Error(),
+	}
+	if requestID != "" {
+		fields["requestID"] = requestID
+	}
+	if e, ok := err.(*Error); ok {
+		fields["stack"] = strings.Join(e.Trace(), " ")
+	}
+
+	for _, log := range log.loggers {
+		log.WithFields(fields).Errorf(msg, data...)
+	}
+}
+
 // fatalIf wrapper function which takes error and prints jsonic error messages.
 func fatalIf(err error, msg string, data ...interface{}) {
 	if err == nil || !isErrLogged(err) {
@@ -62,3 +62,54 @@ func TestLogger(t *testing.T) {
 		t.Fatal("Cause field has unexpected message", msg)
 	}
 }
+
+// Tests errors are logged as single line JSON objects by the console
+// logger in JSON mode.
+func TestConsoleLoggerJSON(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	defer func(loggers []*logrus.Logger, logJSON bool) {
+		log.mu.Lock()
+		log.loggers = loggers
+		log.mu.Unlock()
+		globalIsLogJSON = logJSON
+	}(log.loggers, globalIsLogJSON)
+	log.mu.Lock()
+	log.loggers = nil
+	log.mu.Unlock()
+
+	globalIsLogJSON = true
+	enableConsoleLogger()
+	if len(log.loggers) != 1 {
+		t.Fatalf("Expected console logger to be enabled, got %d loggers", len(log.loggers))
+	}
+	var buffer bytes.Buffer
+	log.loggers[0].Out = &buffer
+
+	errorIf(errors.New("Fake error"), "Failed with %s.", "error")
+	if bytes.Count(buffer.Bytes(), []byte("\n")) != 1 {
+		t.Fatalf("Expected a single line, got %q", buffer.String())
+	}
+	var entry map[string]interface{}
+	if err = json.Unmarshal(buffer.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON, got %q: %s", buffer.String(), err)
+	}
+	expectedEntry := map[string]string{
+		"level":   "error",
+		"message": "Failed with error.",
+		"error":   "Fake error",
+		"source":  "[logger_test.go:93:TestConsoleLoggerJSON()]",
+	}
+	for key, value := range expectedEntry {
+		if entry[key] != value {
+			t.Errorf("Expected %s to be %q, got %v", key, value, entry[key])
+		}
+	}
+	if logTime, ok := entry["time"].(string); !ok || logTime == "" {
+		t.Errorf("Expected time field, got %v", entry["time"])
+	}
+}
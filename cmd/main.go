@@ -40,7 +40,11 @@ var (
 		},
 		cli.BoolFlag{
 			Name:  "quiet",
-			Usage: "Disable startup information.",
+			Usage: "Disable startup information, warnings and errors are still printed.",
+		},
+		cli.BoolFlag{
+			Name:  "json",
+			Usage: "Print errors and startup information as single line JSON objects, also enabled by MINIO_LOG_JSON=on.",
 		},
 	}
 )
@@ -189,23 +193,6 @@ func minioInit(ctx *cli.Context) {
 	// Enable all loggers by now so we can use errorIf() and fatalIf()
 	enableLoggers()
 
-	// Fetch access keys from environment variables and update the config.
-	accessKey := os.Getenv("MINIO_ACCESS_KEY")
-	secretKey := os.Getenv("MINIO_SECRET_KEY")
-	if accessKey != "" && secretKey != "" {
-		// Set new credentials.
-		serverConfig.SetCredential(credential{
-			AccessKey: accessKey,
-			SecretKey: secretKey,
-		})
-	}
-	if !isAccessKeyValid(serverConfig.GetCredential().AccessKey) {
-		fatalIf(errInvalidArgument, "Invalid access key. Accept only a string starting with a alphabetic and containing from 5 to 20 characters.")
-	}
-	if !isSecretKeyValid(serverConfig.GetCredential().SecretKey) {
-		fatalIf(errInvalidArgument, "Invalid secret key. Accept only a string containing from 8 to 40 characters.")
-	}
-
 	// Init the error tracing module.
 	initError()
 
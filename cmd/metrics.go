@@ -0,0 +1,238 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	router "github.com/gorilla/mux"
+	"github.com/minio/dsync"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// httpStats - S3 request metrics of this server.
+type httpStats struct {
+	requests *prometheus.CounterVec
+	bytesIn  prometheus.Counter
+	bytesOut prometheus.Counter
+	duration *prometheus.HistogramVec
+}
+
+// newHTTPStats - initialize empty S3 request metrics.
+func newHTTPStats() *httpStats {
+	return &httpStats{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "minio_http_requests_total",
+			Help: "Total number of S3 requests by method and status code.",
+		}, []string{"method", "status"}),
+		bytesIn: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "minio_http_bytes_received_total",
+			Help: "Total number of bytes received by S3 requests.",
+		}),
+		bytesOut: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "minio_http_bytes_sent_total",
+			Help: "Total number of bytes sent by S3 requests.",
+		}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "minio_http_request_duration_seconds",
+			Help:    "Time taken by S3 requests by method.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method"}),
+	}
+}
+
+// record - counts a single S3 request.
+func (s *httpStats) record(method string, status int, bytesIn, bytesOut uint64, duration time.Duration) {
+	if status == 0 {
+		// Handlers which write nothing reply with 200 OK.
+		status = http.StatusOK
+	}
+	s.requests.WithLabelValues(method, strconv.Itoa(status)).Inc()
+	s.bytesIn.Add(float64(bytesIn))
+	s.bytesOut.Add(float64(bytesOut))
+	s.duration.WithLabelValues(method).Observe(duration.Seconds())
+}
+
+// Describe - implements prometheus.Collector.
+func (s *httpStats) Describe(ch chan<- *prometheus.Desc) {
+	s.requests.Describe(ch)
+	s.bytesIn.Describe(ch)
+	s.bytesOut.Describe(ch)
+	s.duration.Describe(ch)
+}
+
+// Collect - implements prometheus.Collector.
+func (s *httpStats) Collect(ch chan<- prometheus.Metric) {
+	s.requests.Collect(ch)
+	s.bytesIn.Collect(ch)
+	s.bytesOut.Collect(ch)
+	s.duration.Collect(ch)
+}
+
+// httpStatsCollector - collects the S3 request metrics of this server,
+// globalHTTPStats is looked up on every scrape as it may be replaced.
+type httpStatsCollector struct{}
+
+func (httpStatsCollector) Describe(ch chan<- *prometheus.Desc) { globalHTTPStats.Describe(ch) }
+func (httpStatsCollector) Collect(ch chan<- prometheus.Metric) { globalHTTPStats.Collect(ch) }
+
+// Online status of the disks of this server.
+var diskOnlineDesc = prometheus.NewDesc("minio_disk_online",
+	"Whether a disk is online (1) or offline (0).", []string{"disk"}, nil)
+
+// diskCollector - collects online status of the disks of this server.
+type diskCollector struct {
+	disks []StorageAPI
+}
+
+// Describe - implements prometheus.Collector.
+func (c diskCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- diskOnlineDesc
+}
+
+// Collect - implements prometheus.Collector.
+func (c diskCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, disk := range c.disks {
+		if disk == nil {
+			continue
+		}
+		online := 0.0
+		if _, err := disk.DiskInfo(); err == nil {
+			online = 1
+		}
+		ch <- prometheus.MustNewConstMetric(diskOnlineDesc, prometheus.GaugeValue, online, disk.String())
+	}
+}
+
+// newServerMetricsRegistry - returns a registry of all the metrics of
+// this server, disks are the storage disks whose status is exposed.
+// Sources of the metrics are looked up on every scrape.
+func newServerMetricsRegistry(disks []StorageAPI) *prometheus.Registry {
+	counter := func(name, help string, value func() float64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{Name: name, Help: help}, value)
+	}
+	gauge := func(name, help string, value func() float64) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help}, value)
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		tenantCollector{func() map[string]TenantStats { return globalTenantAccounting.Snapshot() }},
+
+		counter("minio_erasure_shard_reads_total", "Total number of erasure shards read by zone aware reads.", func() float64 {
+			shardReads, _ := globalEndpointZones.readStats()
+			return float64(shardReads)
+		}),
+		counter("minio_erasure_cross_zone_shard_reads_total", "Total number of erasure shards read from disks outside the local zone.", func() float64 {
+			_, crossZoneShardReads := globalEndpointZones.readStats()
+			return float64(crossZoneShardReads)
+		}),
+
+		counter("minio_lock_acquired_total", "Total number of namespace locks acquired.", func() float64 {
+			acquired, _ := globalNSMutex.lockWaitStats()
+			return float64(acquired)
+		}),
+		counter("minio_lock_wait_seconds_total", "Total time spent waiting for namespace locks.", func() float64 {
+			_, waitTime := globalNSMutex.lockWaitStats()
+			return waitTime.Seconds()
+		}),
+		counter("minio_lock_retries_total", "Total number of retries of distributed lock acquisition.", func() float64 {
+			return float64(dsync.LockRetries())
+		}),
+
+		gauge("minio_connections", "Number of open client connections.", func() float64 {
+			current, _, _ := globalConnLimiter.stats()
+			return float64(current)
+		}),
+		gauge("minio_connections_peak", "Highest number of open client connections since startup.", func() float64 {
+			_, peak, _ := globalConnLimiter.stats()
+			return float64(peak)
+		}),
+		counter("minio_connections_refused_total", "Total number of client connections refused by --max-connections.", func() float64 {
+			_, _, refused := globalConnLimiter.stats()
+			return float64(refused)
+		}),
+
+		counter("minio_read_ahead_hits_total", "Total number of blocks read ahead before the client asked for them.", func() float64 {
+			hits, _, _ := readAheadStats()
+			return float64(hits)
+		}),
+		counter("minio_read_ahead_misses_total", "Total number of blocks the client waited for with read-ahead enabled.", func() float64 {
+			_, misses, _ := readAheadStats()
+			return float64(misses)
+		}),
+		counter("minio_read_ahead_skips_total", "Total number of requests served without read-ahead as the read-ahead pool was exhausted.", func() float64 {
+			_, _, skips := readAheadStats()
+			return float64(skips)
+		}),
+
+		counter("minio_metadata_store_hits_total", "Total number of object metadata lookups served by the metadata store.", func() float64 {
+			hits, _ := globalMetaStore.lookupStats()
+			return float64(hits)
+		}),
+		counter("minio_metadata_store_misses_total", "Total number of object metadata lookups read from the disks with the metadata store enabled.", func() float64 {
+			_, misses := globalMetaStore.lookupStats()
+			return float64(misses)
+		}),
+
+		gauge("minio_laggard_heals_pending", "Number of objects written without laggard disks pending heal.", func() float64 {
+			pending, _, _ := laggardHealStats()
+			return float64(pending)
+		}),
+		counter("minio_laggard_heals_total", "Total number of objects written without laggard disks healed in background.", func() float64 {
+			_, healed, _ := laggardHealStats()
+			return float64(healed)
+		}),
+		counter("minio_laggard_heals_failed_total", "Total number of objects written without laggard disks which failed to be healed in background.", func() float64 {
+			_, _, failed := laggardHealStats()
+			return float64(failed)
+		}),
+
+		httpStatsCollector{},
+		diskCollector{disks},
+	)
+	return registry
+}
+
+// Metrics path.
+const metricsPath = "/metrics"
+
+// metricsHandler - serves metrics of this server to Prometheus.
+type metricsHandler struct {
+	handler http.Handler
+}
+
+func (m metricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Admin credentials are required unless disabled via command line.
+	if !globalIsMetricsAnonymous {
+		if s3Error := checkRequestAuthType(r, "", "", ""); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+	}
+	m.handler.ServeHTTP(w, r)
+}
+
+// registerMetricsRouter - registers authenticated Prometheus metrics endpoint.
+func registerMetricsRouter(mux *router.Router, srvCmdConfig serverCmdConfig) {
+	registry := newServerMetricsRegistry(srvCmdConfig.storageDisks)
+	metricsRouter := mux.NewRoute().PathPrefix(reservedBucket).Subrouter()
+	metricsRouter.Methods("GET").Path(metricsPath).Handler(metricsHandler{promhttp.HandlerFor(registry, promhttp.HandlerOpts{})})
+}
@@ -0,0 +1,168 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+)
+
+// Tests S3 requests are counted and scraped from /minio/metrics.
+func TestMetricsHandler(t *testing.T) {
+	ts, stop := startTestServerWithPeers(t, "XL")
+	defer stop()
+	globalHTTPStats = newHTTPStats()
+
+	bucketName, objectName := getRandomBucketName(), "object"
+	data := []byte("hello, world")
+	s3Requests := []struct {
+		method string
+		url    string
+		body   []byte
+	}{
+		{"PUT", getMakeBucketURL(ts.Server.URL, bucketName), nil},
+		{"PUT", getPutObjectURL(ts.Server.URL, bucketName, objectName), data},
+		{"GET", getGetObjectURL(ts.Server.URL, bucketName, objectName), nil},
+		{"GET", getGetObjectURL(ts.Server.URL, bucketName, "missing"), nil},
+	}
+	for i, s3Request := range s3Requests {
+		req, err := newTestSignedRequestV4(s3Request.method, s3Request.url, int64(len(s3Request.body)),
+			bytes.NewReader(s3Request.body), ts.AccessKey, ts.SecretKey)
+		if err != nil {
+			t.Fatalf("Request %d: %s", i+1, err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Request %d: %s", i+1, err)
+		}
+		resp.Body.Close()
+	}
+
+	metricsURL := ts.Server.URL + reservedBucket + metricsPath
+	scrape := func(signed bool) (int, string) {
+		var req *http.Request
+		var err error
+		if signed {
+			req, err = newTestSignedRequestV4("GET", metricsURL, 0, nil, ts.AccessKey, ts.SecretKey)
+		} else {
+			req, err = http.NewRequest("GET", metricsURL, nil)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, string(body)
+	}
+
+	// Admin credentials are required by default.
+	if status, _ := scrape(false); status != http.StatusForbidden {
+		t.Errorf("Expected anonymous scrape to be forbidden, got %d", status)
+	}
+	status, body := scrape(true)
+	if status != http.StatusOK {
+		t.Fatalf("Expected scrape to succeed, got %d: %s", status, body)
+	}
+	for _, metric := range []string{
+		`minio_http_requests_total{method="PUT",status="200"} 2`,
+		`minio_http_requests_total{method="GET",status="200"} 1`,
+		`minio_http_requests_total{method="GET",status="404"} 1`,
+		`minio_http_bytes_received_total 12`,
+		`minio_disk_online{disk="` + ts.Disks[0].Path + `"} 1`,
+		`minio_tenant_requests_total`,
+	} {
+		if !strings.Contains(body, metric) {
+			t.Errorf("Expected metric %s in:\n%s", metric, body)
+		}
+	}
+
+	// Access keys are not exposed by default.
+	if strings.Contains(body, `access_key="`+ts.AccessKey+`"`) {
+		t.Errorf("Expected access key to be hashed in:\n%s", body)
+	}
+
+	// Anonymous scrapes are optionally allowed.
+	defer func(anonymous bool) { globalIsMetricsAnonymous = anonymous }(globalIsMetricsAnonymous)
+	globalIsMetricsAnonymous = true
+	if status, _ = scrape(false); status != http.StatusOK {
+		t.Errorf("Expected anonymous scrape to succeed, got %d", status)
+	}
+}
+
+// gatherMetrics - returns the metrics of collectors in Prometheus text
+// exposition format.
+func gatherMetrics(t *testing.T, collectors ...prometheus.Collector) string {
+	registry := prometheus.NewRegistry()
+	for _, collector := range collectors {
+		if err := registry.Register(collector); err != nil {
+			t.Fatal(err)
+		}
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer := new(bytes.Buffer)
+	for _, family := range families {
+		if _, err = expfmt.MetricFamilyToText(buffer, family); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buffer.String()
+}
+
+// Tests S3 requests are counted by method and status, and their
+// duration observed by method.
+func TestHTTPStats(t *testing.T) {
+	stats := newHTTPStats()
+	stats.record("GET", 0, 0, 10, 50*time.Millisecond)
+	stats.record("GET", http.StatusNotFound, 0, 5, 2*time.Second)
+	stats.record("PUT", http.StatusOK, 20, 0, time.Millisecond)
+
+	metrics := gatherMetrics(t, stats)
+	for _, line := range []string{
+		"# TYPE minio_http_requests_total counter",
+		`minio_http_requests_total{method="GET",status="200"} 1`,
+		`minio_http_requests_total{method="GET",status="404"} 1`,
+		`minio_http_requests_total{method="PUT",status="200"} 1`,
+		`minio_http_bytes_received_total 20`,
+		`minio_http_bytes_sent_total 15`,
+		"# TYPE minio_http_request_duration_seconds histogram",
+		`minio_http_request_duration_seconds_bucket{method="GET",le="0.1"} 1`,
+		`minio_http_request_duration_seconds_bucket{method="GET",le="2.5"} 2`,
+		`minio_http_request_duration_seconds_count{method="GET"} 2`,
+		`minio_http_request_duration_seconds_count{method="PUT"} 1`,
+	} {
+		if !strings.Contains(metrics, line+"\n") {
+			t.Errorf("Expected %q in metrics output:\n%s", line, metrics)
+		}
+	}
+}
@@ -21,6 +21,8 @@ import (
 	"net/url"
 	pathutil "path"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/minio/dsync"
 )
@@ -28,18 +30,32 @@ import (
 // Global name space lock.
 var globalNSMutex *nsLockMap
 
+// Boot ID of this process, distinguishes the locks it claims from the
+// ones claimed by previous processes of this node.
+var globalNodeBootID = mustGetUUID()
+
+// Lock servers of a distributed setup, the index of the one of this
+// node and the address of this node in the locks it claims, its locks
+// are released from them on shutdown.
+var (
+	globalLockClnts   []*LockRPCClient
+	globalLockOwnNode int
+	globalLockNode    string
+)
+
 // Initialize distributed locking only in case of distributed setup.
 // Returns if the setup is distributed or not on success.
 func initDsyncNodes(eps []*url.URL) error {
 	cred := serverConfig.GetCredential()
 	// Initialize rpc lock client information only if this instance is a distributed setup.
 	clnts := make([]dsync.NetLocker, len(eps))
+	lockClnts := make([]*LockRPCClient, len(eps))
 	myNode := -1
 	for index, ep := range eps {
 		if ep == nil {
 			return errInvalidArgument
 		}
-		clnts[index] = newLockRPCClient(authConfig{
+		lockClnts[index] = newLockRPCClient(authConfig{
 			accessKey:       cred.AccessKey,
 			secretKey:       cred.SecretKey,
 			serverAddr:      ep.Host,
@@ -47,12 +63,41 @@ func initDsyncNodes(eps []*url.URL) error {
 			secureConn:      globalIsSSL,
 			serviceName:     "Dsync",
 		})
+		clnts[index] = lockClnts[index]
 		if isLocalStorage(ep) && myNode == -1 {
 			myNode = index
 		}
 	}
 
-	return dsync.Init(clnts, myNode)
+	if err := dsync.Init(clnts, myNode); err != nil {
+		return err
+	}
+
+	// Locks claimed by a previous process of this node are stale,
+	// release them instead of waiting for lock maintenance. Locks
+	// of this process have another boot ID and are kept.
+	globalLockClnts, globalLockOwnNode = lockClnts, myNode
+	if myNode >= 0 {
+		globalLockNode = lockClnts[myNode].ServerAddr()
+		go releaseNodeLocks(globalLockClnts, globalLockNode, globalNodeBootID, false)
+	}
+	return nil
+}
+
+// releaseNodeLocks - releases the locks claimed by node with another
+// boot ID than bootID, or with bootID if held is set, on all lock
+// servers. Lock servers which cannot be reached are skipped, their
+// stale locks are released by lock maintenance.
+func releaseNodeLocks(clnts []*LockRPCClient, node, bootID string, held bool) (released int) {
+	for _, clnt := range clnts {
+		n, err := clnt.ReleaseNodeLocks(node, bootID, held)
+		if err != nil {
+			errorIf(err, "Unable to release stale locks of %s on %s.", node, clnt.ServerAddr())
+			continue
+		}
+		released += n
+	}
+	return released
 }
 
 // initNSLock - initialize name space lock map.
@@ -90,6 +135,12 @@ type nsLock struct {
 // nsLockMap - namespace lock map, provides primitives to Lock,
 // Unlock, RLock and RUnlock.
 type nsLockMap struct {
+	// Number of locks acquired and total time spent waiting for
+	// them in nanoseconds, updated atomically. Kept first for 64-bit
+	// alignment.
+	lockAcquired uint64
+	lockWaitTime uint64
+
 	// Lock counter used for lock debugging.
 	counters     *lockStat
 	debugLockMap map[nsParam]*debugLockInfoPerVolumePath // Info for instrumentation on locks.
@@ -101,8 +152,18 @@ type nsLockMap struct {
 	lockMapMutex sync.Mutex
 }
 
-// Lock the namespace resource.
-func (n *nsLockMap) lock(volume, path string, lockSource, opsID string, readLock bool) {
+// lockWaitStats - returns the number of locks acquired and total time
+// spent waiting for them.
+func (n *nsLockMap) lockWaitStats() (acquired uint64, waitTime time.Duration) {
+	if n == nil {
+		return 0, 0
+	}
+	return atomic.LoadUint64(&n.lockAcquired), time.Duration(atomic.LoadUint64(&n.lockWaitTime))
+}
+
+// Lock the namespace resource, waits at most timeout unless it is
+// zero. Returns false if the lock was not acquired in time.
+func (n *nsLockMap) lock(volume, path string, lockSource, opsID string, readLock bool, timeout time.Duration) (locked bool) {
 	var nsLk *nsLock
 	n.lockMapMutex.Lock()
 
@@ -134,11 +195,15 @@ func (n *nsLockMap) lock(volume, path string, lockSource, opsID string, readLock
 	n.lockMapMutex.Unlock()
 
 	// Locking here can block.
-	if readLock {
-		nsLk.RLock()
-	} else {
-		nsLk.Lock()
+	start := time.Now().UTC()
+	if !lockWithTimeout(nsLk.RWLocker, readLock, timeout) {
+		n.lockMapMutex.Lock()
+		n.releaseRef(param, nsLk, opsID)
+		n.lockMapMutex.Unlock()
+		return false
 	}
+	atomic.AddUint64(&n.lockAcquired, 1)
+	atomic.AddUint64(&n.lockWaitTime, uint64(time.Since(start)))
 
 	// Changing the status of the operation from blocked to
 	// running.  change the state of the lock to be running (from
@@ -146,6 +211,77 @@ func (n *nsLockMap) lock(volume, path string, lockSource, opsID string, readLock
 	if err := n.statusBlockedToRunning(param, lockSource, opsID, readLock); err != nil {
 		errorIf(err, "Failed to set the lock state to running")
 	}
+	return true
+}
+
+// lockWithTimeout - locks l, waits at most timeout unless it is zero.
+// Returns false if the lock was not acquired in time. Distributed locks
+// stop retrying then, local locks are released as soon as acquired.
+func lockWithTimeout(l RWLocker, readLock bool, timeout time.Duration) bool {
+	if dl, ok := l.(*dsync.DRWMutex); ok {
+		if readLock {
+			return dl.GetRLock(timeout)
+		}
+		return dl.GetLock(timeout)
+	}
+	lockFn, unlockFn := l.Lock, l.Unlock
+	if readLock {
+		lockFn, unlockFn = l.RLock, l.RUnlock
+	}
+	if timeout <= 0 {
+		lockFn()
+		return true
+	}
+
+	lockedCh := make(chan struct{})
+	go func() {
+		lockFn()
+		close(lockedCh)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-lockedCh:
+		return true
+	case <-timer.C:
+		// Waiting for the lock cannot be canceled.
+		go func() {
+			<-lockedCh
+			unlockFn()
+		}()
+		return false
+	}
+}
+
+// releaseRef - drops the reference of opsID to the lock of param,
+// removes the lock from the map if unreferenced. lockMapMutex must
+// be held.
+func (n *nsLockMap) releaseRef(param nsParam, nsLk *nsLock, opsID string) {
+	if nsLk.ref == 0 {
+		errorIf(errors.New("Namespace reference count cannot be 0"),
+			"Invalid reference count detected")
+	}
+	if nsLk.ref != 0 {
+		nsLk.ref--
+
+		// delete the lock state entry for given operation ID.
+		err := n.deleteLockInfoEntryForOps(param, opsID)
+		if err != nil {
+			errorIf(err, "Failed to delete lock info entry")
+		}
+	}
+	if nsLk.ref == 0 {
+		// Remove from the map if there are no more references.
+		delete(n.lockMap, param)
+
+		// delete the lock state entry for given
+		// <volume, path> pair.
+		err := n.deleteLockInfoEntryForVolumePath(param)
+		if err != nil {
+			errorIf(err, "Failed to delete lock info entry")
+		}
+	}
 }
 
 // Unlock the namespace resource.
@@ -162,30 +298,7 @@ func (n *nsLockMap) unlock(volume, path, opsID string, readLock bool) {
 		} else {
 			nsLk.Unlock()
 		}
-		if nsLk.ref == 0 {
-			errorIf(errors.New("Namespace reference count cannot be 0"),
-				"Invalid reference count detected")
-		}
-		if nsLk.ref != 0 {
-			nsLk.ref--
-
-			// delete the lock state entry for given operation ID.
-			err := n.deleteLockInfoEntryForOps(param, opsID)
-			if err != nil {
-				errorIf(err, "Failed to delete lock info entry")
-			}
-		}
-		if nsLk.ref == 0 {
-			// Remove from the map if there are no more references.
-			delete(n.lockMap, param)
-
-			// delete the lock state entry for given
-			// <volume, path> pair.
-			err := n.deleteLockInfoEntryForVolumePath(param)
-			if err != nil {
-				errorIf(err, "Failed to delete lock info entry")
-			}
-		}
+		n.releaseRef(param, nsLk, opsID)
 	}
 }
 
@@ -195,7 +308,7 @@ func (n *nsLockMap) Lock(volume, path, opsID string) {
 	readLock := false // This is a write lock.
 
 	lockSource := callerSource() // Useful for debugging
-	n.lock(volume, path, lockSource, opsID, readLock)
+	n.lock(volume, path, lockSource, opsID, readLock, 0)
 }
 
 // Unlock - unlocks any previously acquired write locks.
@@ -209,7 +322,7 @@ func (n *nsLockMap) RLock(volume, path, opsID string) {
 	readLock := true
 
 	lockSource := callerSource() // Useful for debugging
-	n.lock(volume, path, lockSource, opsID, readLock)
+	n.lock(volume, path, lockSource, opsID, readLock, 0)
 }
 
 // RUnlock - unlocks any previously acquired read locks.
@@ -254,6 +367,26 @@ func (n *nsLockMap) ForceUnlock(volume, path string) {
 	}
 }
 
+// forceUnlockAll - forcefully unlocks all locks held or waited for by
+// this server, used on shutdown. In distributed mode only the locks
+// claimed by this process are released from the lock servers, unlike
+// ForceUnlock which releases a lock whoever holds it.
+func (n *nsLockMap) forceUnlockAll() {
+	if n == nil {
+		return
+	}
+	if n.isDistXL && globalLockNode != "" {
+		releaseNodeLocks(globalLockClnts, globalLockNode, globalNodeBootID, true)
+	}
+
+	n.lockMapMutex.Lock()
+	defer n.lockMapMutex.Unlock()
+	for param := range n.lockMap {
+		delete(n.lockMap, param)
+		errorIf(n.deleteLockInfoEntryForVolumePath(param), "Failed to delete lock info entry")
+	}
+}
+
 // lockInstance - frontend/top-level interface for namespace locks.
 type lockInstance struct {
 	n                   *nsLockMap
@@ -271,7 +404,19 @@ func (n *nsLockMap) NewNSLock(volume, path string) *lockInstance {
 func (li *lockInstance) Lock() {
 	lockSource := callerSource()
 	readLock := false
-	li.n.lock(li.volume, li.path, lockSource, li.opsID, readLock)
+	li.n.lock(li.volume, li.path, lockSource, li.opsID, readLock, 0)
+}
+
+// GetLock - block until write lock is taken or timeout expires,
+// returns OperationTimedOut in the latter case. Zero timeout waits
+// indefinitely.
+func (li *lockInstance) GetLock(timeout time.Duration) error {
+	lockSource := callerSource()
+	readLock := false
+	if !li.n.lock(li.volume, li.path, lockSource, li.opsID, readLock, timeout) {
+		return traceError(OperationTimedOut{Bucket: li.volume, Object: li.path})
+	}
+	return nil
 }
 
 // Unlock - block until write lock is released.
@@ -284,7 +429,19 @@ func (li *lockInstance) Unlock() {
 func (li *lockInstance) RLock() {
 	lockSource := callerSource()
 	readLock := true
-	li.n.lock(li.volume, li.path, lockSource, li.opsID, readLock)
+	li.n.lock(li.volume, li.path, lockSource, li.opsID, readLock, 0)
+}
+
+// GetRLock - block until read lock is taken or timeout expires,
+// returns OperationTimedOut in the latter case. Zero timeout waits
+// indefinitely.
+func (li *lockInstance) GetRLock(timeout time.Duration) error {
+	lockSource := callerSource()
+	readLock := true
+	if !li.n.lock(li.volume, li.path, lockSource, li.opsID, readLock, timeout) {
+		return traceError(OperationTimedOut{Bucket: li.volume, Object: li.path})
+	}
+	return nil
 }
 
 // RUnlock - block until read lock is released.
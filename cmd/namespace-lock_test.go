@@ -17,10 +17,13 @@
 package cmd
 
 import (
+	"net/http"
 	"strconv"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/minio/dsync"
 )
 
 // Tests functionality provided by namespace lock.
@@ -414,3 +417,142 @@ func TestNamespaceForceUnlockTest(t *testing.T) {
 	// Clean up lock.
 	globalNSMutex.ForceUnlock("bucket", "object")
 }
+
+// Tests all locks held are released on shutdown.
+func TestNamespaceForceUnlockAll(t *testing.T) {
+	for _, object := range []string{"object1", "object2"} {
+		lock := globalNSMutex.NewNSLock("bucket", object)
+		lock.Lock()
+	}
+	globalNSMutex.forceUnlockAll()
+
+	ch := make(chan struct{}, 1)
+	go func() {
+		for _, object := range []string{"object1", "object2"} {
+			lock := globalNSMutex.NewNSLock("bucket", object)
+			lock.Lock()
+			lock.Unlock()
+		}
+		ch <- struct{}{}
+	}()
+	select {
+	case <-ch:
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("Locks not released.")
+	}
+
+	// Nil lock map is a no-op.
+	var n *nsLockMap
+	n.forceUnlockAll()
+}
+
+// Tests time spent waiting for locks is accounted.
+func TestNamespaceLockWaitStats(t *testing.T) {
+	initNSLock(false)
+
+	lock := globalNSMutex.NewNSLock("bucket", "object")
+	lock.Lock()
+	acquired, _ := globalNSMutex.lockWaitStats()
+	if acquired != 1 {
+		t.Fatalf("Expected 1 lock acquired, got %d", acquired)
+	}
+
+	doneCh := make(chan struct{})
+	go func() {
+		anotherLock := globalNSMutex.NewNSLock("bucket", "object")
+		anotherLock.Lock()
+		anotherLock.Unlock()
+		close(doneCh)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	lock.Unlock()
+	<-doneCh
+
+	acquired, waitTime := globalNSMutex.lockWaitStats()
+	if acquired != 2 {
+		t.Fatalf("Expected 2 locks acquired, got %d", acquired)
+	}
+	if waitTime < 50*time.Millisecond {
+		t.Fatalf("Expected at least 50ms spent waiting for locks, got %s", waitTime)
+	}
+}
+
+// Tests acquisition of a held lock times out as configured.
+func TestNamespaceLockTimeout(t *testing.T) {
+	initNSLock(false)
+
+	lock := globalNSMutex.NewNSLock("bucket", "object")
+	if err := lock.GetLock(time.Second); err != nil {
+		t.Fatalf("Expected lock to be acquired, got %v", err)
+	}
+
+	// Write and read locks time out while the lock is held.
+	anotherLock := globalNSMutex.NewNSLock("bucket", "object")
+	start := time.Now()
+	err := anotherLock.GetLock(50 * time.Millisecond)
+	if _, ok := errorCause(err).(OperationTimedOut); !ok {
+		t.Fatalf("Expected OperationTimedOut, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected to wait 50ms for the lock, waited %s", elapsed)
+	}
+	if toAPIErrorCode(err) != ErrOperationTimedOut {
+		t.Errorf("Expected API error ErrOperationTimedOut, got %v", toAPIErrorCode(err))
+	}
+	if getAPIError(ErrOperationTimedOut).HTTPStatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected timeouts to be retryable with 503")
+	}
+	err = globalNSMutex.NewNSLock("bucket", "object").GetRLock(50 * time.Millisecond)
+	if _, ok := errorCause(err).(OperationTimedOut); !ok {
+		t.Fatalf("Expected OperationTimedOut, got %v", err)
+	}
+
+	// Requests which timed out are not accounted anymore.
+	globalNSMutex.lockMapMutex.Lock()
+	ref := globalNSMutex.lockMap[nsParam{"bucket", "object"}].ref
+	blocked := globalNSMutex.counters.blocked
+	globalNSMutex.lockMapMutex.Unlock()
+	if ref != 1 {
+		t.Errorf("Expected only the holder to reference the lock, got %d references", ref)
+	}
+	if blocked != 0 {
+		t.Errorf("Expected no blocked locks, got %d", blocked)
+	}
+
+	// The lock is acquired once released.
+	lock.Unlock()
+	if err = anotherLock.GetLock(time.Second); err != nil {
+		t.Fatalf("Expected released lock to be acquired, got %v", err)
+	}
+	anotherLock.Unlock()
+	if len(globalNSMutex.lockMap) != 0 {
+		t.Errorf("Expected locks to be released, got %v", globalNSMutex.lockMap)
+	}
+}
+
+// Tests validation of the back-off between retries of distributed
+// lock acquisition.
+func TestSetLockRetryBackoff(t *testing.T) {
+	defer dsync.SetRetryBackoff(dsync.RetryBackoff{})
+
+	testCases := []struct {
+		backoff dsync.RetryBackoff
+		valid   bool
+	}{
+		{dsync.RetryBackoff{}, true},
+		{dsync.RetryBackoff{Base: 10 * time.Millisecond, Max: time.Second, Jitter: 0.5}, true},
+		{dsync.RetryBackoff{Base: time.Second, Max: time.Second}, true},
+		{dsync.RetryBackoff{Base: -time.Second, Max: time.Second}, false},
+		{dsync.RetryBackoff{Base: time.Second, Max: time.Millisecond}, false},
+		{dsync.RetryBackoff{Base: time.Millisecond, Max: time.Second, Jitter: 1.5}, false},
+	}
+	for i, testCase := range testCases {
+		err := dsync.SetRetryBackoff(testCase.backoff)
+		if testCase.valid && err != nil {
+			t.Errorf("Test %d: Expected back-off to be valid, got %v", i+1, err)
+		}
+		if !testCase.valid && err == nil {
+			t.Errorf("Test %d: Expected back-off to be invalid", i+1)
+		}
+	}
+}
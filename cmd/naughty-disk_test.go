@@ -136,6 +136,13 @@ func (d *naughtyDisk) AppendFile(volume, path string, buf []byte) error {
 	return d.disk.AppendFile(volume, path, buf)
 }
 
+func (d *naughtyDisk) TruncateFile(volume, path string, size int64) error {
+	if err := d.calcError(); err != nil {
+		return err
+	}
+	return d.disk.TruncateFile(volume, path, size)
+}
+
 func (d *naughtyDisk) RenameFile(srcVolume, srcPath, dstVolume, dstPath string) error {
 	if err := d.calcError(); err != nil {
 		return err
@@ -78,7 +78,12 @@ func (rpcClient *RPCClient) dial() (netRPCClient *rpc.Client, err error) {
 		}
 
 		// ServerName in tls.Config needs to be specified to support SNI certificates.
-		conn, err = tls.Dial("tcp", rpcClient.serverAddr, &tls.Config{ServerName: hostname, RootCAs: globalRootCAs})
+		// Servers requiring client certificates are presented the certificate of this server.
+		conn, err = tls.Dial("tcp", rpcClient.serverAddr, &tls.Config{
+			ServerName:   hostname,
+			RootCAs:      globalRootCAs,
+			Certificates: globalRPCClientCerts,
+		})
 	} else {
 		// Dial with a timeout.
 		conn, err = net.DialTimeout("tcp", rpcClient.serverAddr, defaultDialTimeout)
@@ -17,7 +17,10 @@
 package cmd
 
 import (
+	"fmt"
 	"io/ioutil"
+	"sync"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/streadway/amqp"
@@ -39,23 +42,118 @@ type amqpNotify struct {
 	AutoDeleted  bool   `json:"autoDeleted"`
 }
 
+const (
+	// Maximum number of events held for a durable target while its
+	// broker is unreachable, further events are dropped.
+	amqpMaxPendingEvents = 10000
+
+	// Wait between attempts to reconnect to a broker.
+	amqpReconnectInterval = time.Second
+)
+
+// amqpChannel - publishing side of an AMQP channel, implemented by
+// amqpChannelConn and replaced by mocks in tests.
+type amqpChannel interface {
+	ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error
+	Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+	Close() error
+}
+
+// amqpChannelConn - AMQP channel owning its connection, closing the
+// channel closes the connection as well.
+type amqpChannelConn struct {
+	*amqp.Channel
+	conn *amqp.Connection
+}
+
+func (c amqpChannelConn) Close() error {
+	c.Channel.Close()
+	return c.conn.Close()
+}
+
+// amqpDial - connects to the broker at url and opens a channel.
+var amqpDial = func(url string) (amqpChannel, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return amqpChannelConn{Channel: ch, conn: conn}, nil
+}
+
 type amqpConn struct {
 	params amqpNotify
-	*amqp.Connection
+
+	mu sync.Mutex
+	// Channel events are published on, nil while disconnected.
+	channel amqpChannel
+	// Set while reconnecting in background.
+	reconnecting bool
+	// Events held while disconnected, only for durable targets.
+	pending           [][]byte
+	reconnectInterval time.Duration
+	// Set once the target is no longer in use.
+	closed bool
+}
+
+// connectAMQP - opens a channel to the broker and declares the exchange
+// events are published to.
+func connectAMQP(params amqpNotify) (amqpChannel, error) {
+	ch, err := amqpDial(params.URL)
+	if err != nil {
+		return nil, err
+	}
+	err = ch.ExchangeDeclare(
+		params.Exchange,
+		params.ExchangeType,
+		params.Durable,
+		params.AutoDeleted,
+		params.Internal,
+		params.NoWait,
+		nil,
+	)
+	if err != nil {
+		ch.Close()
+		return nil, err
+	}
+	return ch, nil
 }
 
 // dialAMQP - dials and returns an amqpConnection instance,
 // for sending notifications. Returns error if amqp logger
 // is not enabled.
-func dialAMQP(amqpL amqpNotify) (amqpConn, error) {
+func dialAMQP(amqpL amqpNotify) (*amqpConn, error) {
 	if !amqpL.Enable {
-		return amqpConn{}, errNotifyNotEnabled
+		return nil, errNotifyNotEnabled
 	}
-	conn, err := amqp.Dial(amqpL.URL)
+	ch, err := connectAMQP(amqpL)
 	if err != nil {
-		return amqpConn{}, err
+		return nil, err
 	}
-	return amqpConn{Connection: conn, params: amqpL}, nil
+	return &amqpConn{
+		params:            amqpL,
+		channel:           ch,
+		reconnectInterval: amqpReconnectInterval,
+	}, nil
+}
+
+// Close closes the channel to the broker, if connected, and stops
+// reconnecting.
+func (q *amqpConn) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.pending = nil
+	if q.channel == nil {
+		return nil
+	}
+	err := q.channel.Close()
+	q.channel = nil
+	return err
 }
 
 func newAMQPNotify(accountID string) (*logrus.Logger, error) {
@@ -82,63 +180,110 @@ func newAMQPNotify(accountID string) (*logrus.Logger, error) {
 	return amqpLog, nil
 }
 
-// Fire is called when an event should be sent to the message broker.
-func (q amqpConn) Fire(entry *logrus.Entry) error {
-	ch, err := q.Connection.Channel()
-	if err != nil {
-		// Any other error other than connection closed, return.
-		if err != amqp.ErrClosed {
-			return err
-		}
-		// Attempt to connect again.
-		var conn *amqp.Connection
-		conn, err = amqp.Dial(q.params.URL)
-		if err != nil {
-			return err
-		}
-		ch, err = conn.Channel()
-		if err != nil {
-			return err
-		}
+// publish - publishes an event on ch, durable targets publish persistent
+// messages such that the broker keeps them across restarts.
+func (q *amqpConn) publish(ch amqpChannel, body []byte) error {
+	msg := amqp.Publishing{
+		ContentType: "application/json",
+		Body:        body,
 	}
-	defer ch.Close()
-
-	err = ch.ExchangeDeclare(
-		q.params.Exchange,
-		q.params.ExchangeType,
-		q.params.Durable,
-		q.params.AutoDeleted,
-		q.params.Internal,
-		q.params.NoWait,
-		nil,
-	)
-	if err != nil {
-		return err
+	if q.params.Durable {
+		msg.DeliveryMode = amqp.Persistent
 	}
-
-	body, err := entry.String()
-	if err != nil {
-		return err
-	}
-
-	err = ch.Publish(
+	return ch.Publish(
 		q.params.Exchange,
 		q.params.RoutingKey,
 		q.params.Mandatory,
 		q.params.Immediate,
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        []byte(body),
-		})
+		msg,
+	)
+}
+
+// Fire is called when an event should be sent to the message broker.
+// Once the connection to the broker is lost it is re-established in
+// background, meanwhile events of durable targets are held in memory
+// and events of other targets are dropped.
+func (q *amqpConn) Fire(entry *logrus.Entry) error {
+	body, err := entry.Reader()
 	if err != nil {
 		return err
 	}
 
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return fmt.Errorf("Dropped event for AMQP exchange %s, target is closed", q.params.Exchange)
+	}
+	if q.channel != nil {
+		if err = q.publish(q.channel, body.Bytes()); err == nil {
+			return nil
+		}
+		// Connection to the broker is lost.
+		q.channel.Close()
+		q.channel = nil
+	}
+	if !q.reconnecting {
+		q.reconnecting = true
+		go q.reconnect()
+	}
+
+	if !q.params.Durable {
+		return fmt.Errorf("Dropped event for AMQP exchange %s, broker is unreachable", q.params.Exchange)
+	}
+	if len(q.pending) >= amqpMaxPendingEvents {
+		return fmt.Errorf("Dropped event for AMQP exchange %s, %d events already pending", q.params.Exchange, amqpMaxPendingEvents)
+	}
+	q.pending = append(q.pending, body.Bytes())
 	return nil
 }
 
+// reconnect - reconnects to the broker until it succeeds or the target
+// is closed, then publishes the events held meanwhile in the order they
+// were fired.
+func (q *amqpConn) reconnect() {
+	for {
+		time.Sleep(q.reconnectInterval)
+
+		q.mu.Lock()
+		closed := q.closed
+		q.mu.Unlock()
+		if closed {
+			return
+		}
+
+		ch, err := connectAMQP(q.params)
+		if err != nil {
+			continue
+		}
+
+		q.mu.Lock()
+		if q.closed {
+			q.mu.Unlock()
+			ch.Close()
+			return
+		}
+		for len(q.pending) > 0 {
+			if err = q.publish(ch, q.pending[0]); err != nil {
+				break
+			}
+			q.pending = q.pending[1:]
+		}
+		if err != nil {
+			q.mu.Unlock()
+			ch.Close()
+			continue
+		}
+		q.pending = nil
+		q.channel = ch
+		q.reconnecting = false
+		q.mu.Unlock()
+		return
+	}
+}
+
 // Levels is available logging levels.
-func (q amqpConn) Levels() []logrus.Level {
+func (q *amqpConn) Levels() []logrus.Level {
 	return []logrus.Level{
 		logrus.InfoLevel,
 	}
@@ -126,6 +126,11 @@ func closeNATS(conn natsIOConn) {
 	}
 }
 
+// Close - closes the connection to the NATS server.
+func (n natsIOConn) Close() {
+	closeNATS(n)
+}
+
 func newNATSNotify(accountID string) (*logrus.Logger, error) {
 	natsL := serverConfig.GetNATSNotifyByID(accountID)
 
@@ -17,11 +17,14 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/Sirupsen/logrus"
@@ -30,11 +33,34 @@ import (
 type webhookNotify struct {
 	Enable   bool   `json:"enable"`
 	Endpoint string `json:"endpoint"`
+	// Optional value of the Authorization header sent with every event.
+	Authorization string `json:"authorization,omitempty"`
 }
 
+const (
+	// Maximum number of events waiting to be delivered to a webhook,
+	// further events are dropped until the queue drains.
+	webhookQueueSize = 10000
+
+	// Number of attempts made to deliver an event before giving up.
+	webhookMaxAttempts = 3
+
+	// Wait before the first retry, doubled for every retry after it.
+	webhookRetryInterval = time.Second
+)
+
 type httpConn struct {
 	*http.Client
-	Endpoint string
+	Endpoint      string
+	Authorization string
+
+	// Serialized events waiting to be posted.
+	queue         chan []byte
+	retryInterval time.Duration
+
+	// Closed once the target is no longer in use.
+	doneCh    chan struct{}
+	closeOnce sync.Once
 }
 
 // Lookup endpoint address by successfully dialing.
@@ -67,7 +93,7 @@ func newWebhookNotify(accountID string) (*logrus.Logger, error) {
 		return nil, err
 	}
 
-	conn := httpConn{
+	conn := &httpConn{
 		// Configure aggressive timeouts for client posts.
 		Client: &http.Client{
 			Transport: &http.Transport{
@@ -80,9 +106,17 @@ func newWebhookNotify(accountID string) (*logrus.Logger, error) {
 				ExpectContinueTimeout: 2 * time.Second,
 			},
 		},
-		Endpoint: rNotify.Endpoint,
+		Endpoint:      rNotify.Endpoint,
+		Authorization: rNotify.Authorization,
+		queue:         make(chan []byte, webhookQueueSize),
+		retryInterval: webhookRetryInterval,
+		doneCh:        make(chan struct{}),
 	}
 
+	// Events are posted in the background so that the S3 requests
+	// generating them never wait on the webhook.
+	go conn.deliver()
+
 	notifyLog := logrus.New()
 	notifyLog.Out = ioutil.Discard
 
@@ -95,14 +129,58 @@ func newWebhookNotify(accountID string) (*logrus.Logger, error) {
 	return notifyLog, nil
 }
 
-// Fire is called when an event should be sent to the message broker.
-func (n httpConn) Fire(entry *logrus.Entry) error {
+// Fire is called when an event should be sent to the webhook. The event
+// is only queued here, delivery happens asynchronously in deliver().
+func (n *httpConn) Fire(entry *logrus.Entry) error {
 	body, err := entry.Reader()
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequest("POST", n.Endpoint, body)
+	select {
+	case <-n.doneCh:
+		return fmt.Errorf("Dropped event for %s, target is closed", n.Endpoint)
+	default:
+	}
+	select {
+	case n.queue <- body.Bytes():
+		return nil
+	default:
+		return fmt.Errorf("Unable to queue event for %s, %d events already pending", n.Endpoint, webhookQueueSize)
+	}
+}
+
+// deliver posts queued events to the webhook in order, retrying each
+// failed post up to webhookMaxAttempts times.
+func (n *httpConn) deliver() {
+	for {
+		var body []byte
+		select {
+		case body = <-n.queue:
+		case <-n.doneCh:
+			return
+		}
+		interval := n.retryInterval
+		err := n.post(body)
+		for attempt := 1; err != nil && attempt < webhookMaxAttempts; attempt++ {
+			time.Sleep(interval)
+			interval *= 2
+			err = n.post(body)
+		}
+		errorIf(err, "Unable to deliver event to webhook %s.", n.Endpoint)
+	}
+}
+
+// Close stops delivery of the queued events, done once the target is
+// no longer in use.
+func (n *httpConn) Close() error {
+	n.closeOnce.Do(func() { close(n.doneCh) })
+	return nil
+}
+
+// post sends a single serialized event to the webhook.
+func (n *httpConn) post(body []byte) error {
+	req, err := http.NewRequest("POST", n.Endpoint, bytes.NewReader(body))
 	if err != nil {
 		return err
 	}
@@ -113,12 +191,20 @@ func (n httpConn) Fire(entry *logrus.Entry) error {
 	// Set proper server user-agent.
 	req.Header.Set("User-Agent", globalServerUserAgent)
 
+	if n.Authorization != "" {
+		req.Header.Set("Authorization", n.Authorization)
+	}
+
 	// Initiate the http request.
 	resp, err := n.Do(req)
 	if err != nil {
 		return err
 	}
 
+	// Drain the body so that the connection can be reused.
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
 	if resp.StatusCode != http.StatusOK &&
 		resp.StatusCode != http.StatusAccepted &&
 		resp.StatusCode != http.StatusContinue {
@@ -129,7 +215,7 @@ func (n httpConn) Fire(entry *logrus.Entry) error {
 }
 
 // Levels are Required for logrus hook implementation
-func (httpConn) Levels() []logrus.Level {
+func (*httpConn) Levels() []logrus.Level {
 	return []logrus.Level{
 		logrus.InfoLevel,
 	}
@@ -17,12 +17,17 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"path"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 )
@@ -77,3 +82,198 @@ func TestNewWebHookNotify(t *testing.T) {
 		"EventType": "s3:ObjectCreated:Put",
 	}).Info()
 }
+
+// Tests that an upload is notified to a configured webhook, in the
+// background and without waiting for the webhook to answer.
+func TestWebhookNotifyPutObject(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(fsDir)
+
+	type webhookRequest struct {
+		authorization string
+		body          []byte
+	}
+	received := make(chan webhookRequest, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		// Hold the response until the upload has completed.
+		<-release
+		received <- webhookRequest{r.Header.Get("Authorization"), body}
+	}))
+	defer server.Close()
+	// Unblock any pending webhook post before the server is closed.
+	defer func() {
+		select {
+		case <-release:
+		default:
+			close(release)
+		}
+	}()
+
+	serverConfig.SetWebhookNotifyByID("1", webhookNotify{
+		Enable:        true,
+		Endpoint:      server.URL,
+		Authorization: "Bearer webhook-token",
+	})
+
+	bucketName := getRandomBucketName()
+	if err = obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+	ncfg := notificationConfig{
+		QueueConfigs: []queueConfig{{
+			ServiceConfig: ServiceConfig{
+				Events: []string{"s3:ObjectCreated:*"},
+				ID:     "1",
+			},
+			QueueARN: "arn:minio:sqs:us-east-1:1:webhook",
+		}},
+	}
+	if err = persistNotificationConfig(bucketName, &ncfg, obj); err != nil {
+		t.Fatal(err)
+	}
+	if err = initEventNotifier(obj); err != nil {
+		t.Fatal(err)
+	}
+
+	apiRouter := initTestAPIEndPoints(obj, []string{"PutObject"})
+	credentials := serverConfig.GetCredential()
+	data := []byte("hello, webhook")
+	req, err := newTestSignedRequestV4("PUT", getPutObjectURL("", bucketName, "object"),
+		int64(len(data)), bytes.NewReader(data), credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	// The webhook is still blocked, so this only returns if
+	// the event is delivered asynchronously.
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected upload to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+	close(release)
+
+	select {
+	case r := <-received:
+		if r.authorization != "Bearer webhook-token" {
+			t.Errorf("Expected Authorization header %q, got %q", "Bearer webhook-token", r.authorization)
+		}
+		var event struct {
+			EventType string
+			Key       string
+			Records   []NotificationEvent
+		}
+		if err = json.Unmarshal(r.body, &event); err != nil {
+			t.Fatalf("Unable to decode event %s: %v", string(r.body), err)
+		}
+		if event.EventType != "s3:ObjectCreated:Put" {
+			t.Errorf("Expected event type s3:ObjectCreated:Put, got %s", event.EventType)
+		}
+		if event.Key != path.Join(bucketName, "object") {
+			t.Errorf("Expected key %s, got %s", path.Join(bucketName, "object"), event.Key)
+		}
+		if len(event.Records) != 1 {
+			t.Fatalf("Expected 1 record, got %d", len(event.Records))
+		}
+		record := event.Records[0]
+		if record.EventName != "s3:ObjectCreated:Put" || record.S3.Bucket.Name != bucketName ||
+			record.S3.Object.Key != "object" || record.S3.Object.Size != int64(len(data)) {
+			t.Errorf("Unexpected event record %+v", record)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timed out waiting for the webhook event")
+	}
+}
+
+// Tests that failed webhook posts are retried a bounded number of times.
+func TestWebhookDeliveryRetry(t *testing.T) {
+	testCases := []struct {
+		failures         int32
+		expectedRequests int32
+	}{
+		// Delivered at the first attempt.
+		{0, 1},
+		// Delivered after retrying.
+		{webhookMaxAttempts - 1, webhookMaxAttempts},
+		// Given up after the last attempt.
+		{webhookMaxAttempts + 5, webhookMaxAttempts},
+	}
+
+	for i, testCase := range testCases {
+		var requests int32
+		done := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := atomic.AddInt32(&requests, 1)
+			if n == testCase.expectedRequests {
+				defer close(done)
+			}
+			if n <= testCase.failures {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			}
+		}))
+
+		conn := &httpConn{
+			Client:        &http.Client{},
+			Endpoint:      server.URL,
+			queue:         make(chan []byte, 1),
+			retryInterval: time.Millisecond,
+			doneCh:        make(chan struct{}),
+		}
+		go conn.deliver()
+		conn.queue <- []byte(`{"EventType":"s3:ObjectCreated:Put"}`)
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Test %d: timed out waiting for %d requests", i+1, testCase.expectedRequests)
+		}
+		// Give deliver a chance to make an unexpected extra attempt.
+		time.Sleep(50 * time.Millisecond)
+		conn.Close()
+		server.Close()
+
+		if n := atomic.LoadInt32(&requests); n != testCase.expectedRequests {
+			t.Errorf("Test %d: expected %d requests, got %d", i+1, testCase.expectedRequests, n)
+		}
+	}
+}
+
+// Tests closed webhook targets drop events and stop posting them.
+func TestWebhookClose(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer server.Close()
+
+	conn := &httpConn{
+		Client:        &http.Client{},
+		Endpoint:      server.URL,
+		queue:         make(chan []byte, 1),
+		retryInterval: time.Millisecond,
+		doneCh:        make(chan struct{}),
+	}
+	go conn.deliver()
+	notifyLog := logrus.New()
+	notifyLog.Formatter = new(logrus.JSONFormatter)
+	notifyLog.Hooks.Add(conn)
+	closeQueueTargets(map[string]*logrus.Logger{"webhook": notifyLog})
+
+	if err := conn.Fire(logrus.NewEntry(notifyLog)); err == nil {
+		t.Error("Expected events fired at a closed target to be dropped")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Errorf("Expected no events posted once closed, got %d", n)
+	}
+}
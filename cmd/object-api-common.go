@@ -22,6 +22,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	humanize "github.com/dustin/go-humanize"
 )
@@ -48,8 +49,16 @@ func init() {
 	globalObjLayerMutex = &sync.Mutex{}
 }
 
-// House keeping code for FS/XL and distributed Minio setup.
-func houseKeeping(storageDisks []StorageAPI) error {
+// Temporary files modified more recently are kept by houseKeeping, unless
+// configured otherwise.
+const defaultTmpCleanupAge = 24 * time.Hour
+
+// House keeping code for FS/XL and distributed Minio setup, purges
+// temporary files of local disks left behind by operations interrupted
+// by a crash. Only entries last modified longer than olderThan ago are
+// purged, as other servers of a distributed setup may be writing to
+// them, and entries named after an active multipart upload are kept.
+func houseKeeping(storageDisks []StorageAPI, olderThan time.Duration) error {
 	var wg = &sync.WaitGroup{}
 
 	// Initialize errs to collect errors inside go-routine.
@@ -69,8 +78,8 @@ func houseKeeping(storageDisks []StorageAPI) error {
 			// Indicate this wait group is done.
 			defer wg.Done()
 
-			// Cleanup all temp entries upon start.
-			err := cleanupDir(disk, minioMetaTmpBucket, "")
+			// Cleanup old temp entries upon start.
+			err := cleanupTmpEntries(disk, olderThan)
 			if err != nil {
 				if !isErrIgnored(errorCause(err), errDiskNotFound, errVolumeNotFound, errFileNotFound) {
 					errs[index] = err
@@ -94,6 +103,131 @@ func houseKeeping(storageDisks []StorageAPI) error {
 	return nil
 }
 
+// cleanupTmpEntries - deletes the entries of the temporary directory of
+// disk which are older than olderThan and do not belong to an active
+// multipart upload.
+func cleanupTmpEntries(disk StorageAPI, olderThan time.Duration) error {
+	entries, err := disk.ListDir(minioMetaTmpBucket, "")
+	if err != nil {
+		return traceError(err)
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	uploadIDs, err := listActiveUploadIDs(disk)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if uploadIDs[strings.TrimSuffix(entry, slashSeparator)] {
+			continue
+		}
+		modTime, err := lastModTime(disk, minioMetaTmpBucket, entry)
+		if err != nil {
+			return err
+		}
+		if time.Since(modTime) < olderThan {
+			continue
+		}
+		if strings.HasSuffix(entry, slashSeparator) {
+			err = cleanupDir(disk, minioMetaTmpBucket, entry)
+		} else {
+			err = traceError(disk.DeleteFile(minioMetaTmpBucket, entry))
+		}
+		if err != nil && !isErrIgnored(errorCause(err), errFileNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// lastModTime - returns the latest modification time of the files under
+// entryPath, or of entryPath itself if it is a file. Empty directories
+// have a zero modification time.
+func lastModTime(disk StorageAPI, volume, entryPath string) (time.Time, error) {
+	if !strings.HasSuffix(entryPath, slashSeparator) {
+		fi, err := disk.StatFile(volume, entryPath)
+		if err == errFileNotFound {
+			// Removed meanwhile, nothing left to purge.
+			return time.Time{}, nil
+		}
+		return fi.ModTime, traceError(err)
+	}
+
+	entries, err := disk.ListDir(volume, entryPath)
+	if err == errFileNotFound {
+		return time.Time{}, nil
+	} else if err != nil {
+		return time.Time{}, traceError(err)
+	}
+	var modTime time.Time
+	for _, entry := range entries {
+		entryModTime, err := lastModTime(disk, volume, pathJoin(entryPath, entry))
+		if err != nil {
+			return time.Time{}, err
+		}
+		if entryModTime.After(modTime) {
+			modTime = entryModTime
+		}
+	}
+	return modTime, nil
+}
+
+// listActiveUploadIDs - returns the IDs of the multipart uploads of all
+// objects listed in the uploads.json files of disk.
+func listActiveUploadIDs(disk StorageAPI) (map[string]bool, error) {
+	uploadIDs := make(map[string]bool)
+
+	var walkFunc func(string) error
+	walkFunc = func(dirPath string) error {
+		entries, err := disk.ListDir(minioMetaMultipartBucket, dirPath)
+		if err == errFileNotFound || err == errVolumeNotFound {
+			return nil
+		} else if err != nil {
+			return traceError(err)
+		}
+
+		// Upload IDs of an object are listed in its uploads.json,
+		// upload directories hold parts only and are not walked.
+		uploadDirs := make(map[string]bool)
+		for _, entry := range entries {
+			if entry != uploadsJSONFile {
+				continue
+			}
+			uploads, rerr := readUploadsJSON(dirPath, "", disk)
+			if rerr != nil {
+				// Keep all the entries which may be uploads.
+				errorIf(rerr, "Unable to read uploads of %s.", dirPath)
+				for _, dirEntry := range entries {
+					uploadIDs[strings.TrimSuffix(dirEntry, slashSeparator)] = true
+				}
+				continue
+			}
+			for _, upload := range uploads.Uploads {
+				uploadIDs[upload.UploadID] = true
+				uploadDirs[upload.UploadID+slashSeparator] = true
+			}
+		}
+
+		// Recurse into objects with this object as prefix.
+		for _, entry := range entries {
+			if !strings.HasSuffix(entry, slashSeparator) || uploadDirs[entry] {
+				continue
+			}
+			if err = walkFunc(pathJoin(dirPath, entry)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walkFunc(""); err != nil {
+		return nil, err
+	}
+	return uploadIDs, nil
+}
+
 // Check if a network path is local to this node.
 func isLocalStorage(ep *url.URL) bool {
 	if ep.Host == "" {
@@ -178,7 +312,7 @@ func getPath(ep *url.URL) string {
 // Depending on the disk type network or local, initialize storage API.
 func newStorageAPI(ep *url.URL) (storage StorageAPI, err error) {
 	if isLocalStorage(ep) {
-		return newPosix(getPath(ep))
+		return newLocalStorage(getPath(ep))
 	}
 	return newStorageRPC(ep)
 }
@@ -228,6 +362,20 @@ func initMetaVolume(storageDisks []StorageAPI) error {
 					return
 				}
 			}
+			err = disk.MakeVol(minioMetaTrashBucket)
+			if err != nil {
+				if !isErrIgnored(err, initMetaVolIgnoredErrs...) {
+					errs[index] = err
+					return
+				}
+			}
+			err = disk.MakeVol(minioMetaHealBucket)
+			if err != nil {
+				if !isErrIgnored(err, initMetaVolIgnoredErrs...) {
+					errs[index] = err
+					return
+				}
+			}
 		}(index, disk)
 	}
 
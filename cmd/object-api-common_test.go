@@ -17,9 +17,12 @@
 package cmd
 
 import (
+	"io/ioutil"
+	"os"
 	"runtime"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestHouseKeeping(t *testing.T) {
@@ -91,7 +94,7 @@ func TestHouseKeeping(t *testing.T) {
 		{nilDiskStorage, nil},
 	}
 	for i, test := range testCases {
-		actualErr := errorCause(houseKeeping(test.store))
+		actualErr := errorCause(houseKeeping(test.store, 0))
 		if actualErr != test.expectedErr {
 			t.Errorf("Test %d - actual error is %#v, expected error was %#v",
 				i+1, actualErr, test.expectedErr)
@@ -99,6 +102,93 @@ func TestHouseKeeping(t *testing.T) {
 	}
 }
 
+// Tests houseKeeping only purges old temporary files which do not
+// belong to active multipart uploads.
+func TestHouseKeepingAge(t *testing.T) {
+	fsDir, err := ioutil.TempDir(globalTestTmpDir, "minio-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(fsDir)
+
+	disk, err := newPosix(fsDir)
+	if err != nil {
+		t.Fatalf("Failed to create a local disk-based storage layer <ERROR> %v", err)
+	}
+	for _, volume := range []string{minioMetaBucket, minioMetaTmpBucket, minioMetaMultipartBucket} {
+		if err = disk.MakeVol(volume); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// An active upload of an object and one of an object below it.
+	uploadID, nestedUploadID := mustGetUUID(), mustGetUUID()
+	for object, id := range map[string]string{"object": uploadID, "object/nested": nestedUploadID} {
+		uploads := newUploadsV1("xl")
+		uploads.AddUploadID(id, time.Now().UTC())
+		if err = writeUploadJSON(&uploads, pathJoin("bucket", object, uploadsJSONFile), mustGetUUID(), disk); err != nil {
+			t.Fatal(err)
+		}
+		if err = disk.AppendFile(minioMetaMultipartBucket, pathJoin("bucket", object, id, "part.1"), []byte("part")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oldTime := time.Now().Add(-48 * time.Hour)
+	testCases := []struct {
+		files  []string
+		old    []bool
+		purged bool
+	}{
+		// Test case - 1.
+		// Old file.
+		{[]string{"old-file"}, []bool{true}, true},
+		// Test case - 2.
+		// Old directory.
+		{[]string{"old-dir/part.1", "old-dir/sub/xl.json"}, []bool{true, true}, true},
+		// Test case - 3.
+		// Recent file.
+		{[]string{"new-file"}, []bool{false}, false},
+		// Test case - 4.
+		// Directory with a recent file.
+		{[]string{"mixed-dir/part.1", "mixed-dir/part.2"}, []bool{true, false}, false},
+		// Test case - 5.
+		// Old append file of an active upload.
+		{[]string{uploadID}, []bool{true}, false},
+		// Test case - 6.
+		// Old directory of an active upload of a nested object.
+		{[]string{nestedUploadID + "/part.1"}, []bool{true}, false},
+	}
+	for i, testCase := range testCases {
+		for j, file := range testCase.files {
+			if err = disk.AppendFile(minioMetaTmpBucket, file, []byte("hello")); err != nil {
+				t.Fatalf("Test %d: %v", i+1, err)
+			}
+			if testCase.old[j] {
+				if err = os.Chtimes(pathJoin(fsDir, minioMetaTmpBucket, file), oldTime, oldTime); err != nil {
+					t.Fatalf("Test %d: %v", i+1, err)
+				}
+			}
+		}
+	}
+
+	if err = houseKeeping([]StorageAPI{disk}, 24*time.Hour); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	for i, testCase := range testCases {
+		for _, file := range testCase.files {
+			_, serr := disk.StatFile(minioMetaTmpBucket, file)
+			if testCase.purged && serr != errFileNotFound {
+				t.Errorf("Test %d: Expected %s to be purged, got %v", i+1, file, serr)
+			}
+			if !testCase.purged && serr != nil {
+				t.Errorf("Test %d: Expected %s to be kept, got %v", i+1, file, serr)
+			}
+		}
+	}
+}
+
 // Test getPath() - the path that needs to be passed to newPosix()
 func TestGetPath(t *testing.T) {
 	globalMinioHost = ""
@@ -37,6 +37,12 @@ type StorageInfo struct {
 	Total int64
 	// Free available disk space.
 	Free int64
+	// Disk space and free space of all the disks summed up, more than
+	// Total and Free with erasure coding or disks of different sizes.
+	RawTotal int64
+	RawFree  int64
+	// Recent read and write latency of each disk.
+	Disks []DiskLatency
 	// Backend type.
 	Backend struct {
 		// Represents various backend types, currently on FS and XL.
@@ -47,9 +53,54 @@ type StorageInfo struct {
 		OfflineDisks int // Offline disks during server startup.
 		ReadQuorum   int // Minimum disks required for successful read operations.
 		WriteQuorum  int // Minimum disks required for successful write operations.
+		AvoidedDisks int // Online disks excluded from new writes.
+		PendingHeals int // Objects written without laggard disks yet to be healed.
+		DataBlocks   int // Data blocks objects are erasure coded with.
+		ParityBlocks int // Parity blocks objects are erasure coded with.
 	}
 }
 
+// LatencyPercentiles - percentiles of the latency of recent disk
+// operations, zero if there were none.
+type LatencyPercentiles struct {
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+}
+
+// DiskLatency - represents recent latency of a disk, a slowly failing
+// disk stays online but its latency grows.
+type DiskLatency struct {
+	// Disk endpoint.
+	Disk string
+	// Latency of reads and writes of file data.
+	Read  LatencyPercentiles
+	Write LatencyPercentiles
+}
+
+// HealStatus - represents the state of an object found by healing.
+type HealStatus string
+
+// Enum for the states of healed objects.
+const (
+	// All the parts of the object are present and valid.
+	HealStatusHealthy HealStatus = "healthy"
+	// Missing or corrupted parts were rebuilt.
+	HealStatusHealed HealStatus = "healed"
+	// Too few valid parts are left to rebuild the object.
+	HealStatusCorrupt HealStatus = "corrupt"
+)
+
+// PendingHealInfo - object written without laggard disks which is yet
+// to be healed on them.
+type PendingHealInfo struct {
+	Bucket string `json:"bucket"`
+	Object string `json:"object"`
+	// Set once healing in background gave up, the object is healed
+	// again at the next start or by HealObjects.
+	Failed bool `json:"failed"`
+}
+
 // BucketInfo - represents bucket metadata.
 type BucketInfo struct {
 	// Name of the bucket.
@@ -169,7 +220,7 @@ type ListMultipartsInfo struct {
 	// next occurrence of the string specified by delimiter.
 	CommonPrefixes []string
 
-	EncodingType string // Not supported yet.
+	EncodingType string // Encoding type requested for keys in the response, only "url" is supported.
 }
 
 // ListObjectsInfo - container for list objects.
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"time"
 )
 
 // Converts underlying storage error. Convenience function written to
@@ -152,6 +153,24 @@ func (e BucketNotEmpty) Error() string {
 	return "Bucket not empty: " + e.Bucket
 }
 
+// BucketQuotaExceeded bucket has reached its quota.
+type BucketQuotaExceeded GenericError
+
+func (e BucketQuotaExceeded) Error() string {
+	return "Bucket quota exceeded: " + e.Bucket
+}
+
+// ObjectRetained object is retained in WORM mode.
+type ObjectRetained struct {
+	Bucket      string
+	Object      string
+	RetainUntil time.Time
+}
+
+func (e ObjectRetained) Error() string {
+	return "Object is retained until " + e.RetainUntil.Format(time.RFC3339) + ": " + e.Bucket + "#" + e.Object
+}
+
 // ObjectNotFound object does not exist.
 type ObjectNotFound GenericError
 
@@ -166,6 +185,27 @@ func (e ObjectExistsAsDirectory) Error() string {
 	return "Object exists on : " + e.Bucket + " as directory " + e.Object
 }
 
+// ObjectCorrupted object content does not match its checksum.
+type ObjectCorrupted GenericError
+
+func (e ObjectCorrupted) Error() string {
+	return "Object content does not match its checksum: " + e.Bucket + "#" + e.Object
+}
+
+// OperationTimedOut lock on the object could not be acquired in time.
+type OperationTimedOut GenericError
+
+func (e OperationTimedOut) Error() string {
+	return "Operation timed out acquiring lock: " + e.Bucket + "#" + e.Object
+}
+
+// MultipartEncryptionNotSupported multipart upload of an object to be encrypted.
+type MultipartEncryptionNotSupported GenericError
+
+func (e MultipartEncryptionNotSupported) Error() string {
+	return "Multipart uploads cannot be encrypted: " + e.Bucket + "#" + e.Object
+}
+
 //PrefixAccessDenied object access is denied.
 type PrefixAccessDenied GenericError
 
@@ -266,6 +306,19 @@ func (e InvalidRange) Error() string {
 	return fmt.Sprintf("The requested range \"bytes %d-%d/%d\" is not satisfiable.", e.offsetBegin, e.offsetEnd, e.resourceSize)
 }
 
+// InvalidTruncateLength - requested truncate length is negative or
+// larger than the current size of the object.
+type InvalidTruncateLength struct {
+	Bucket string
+	Object string
+	Length int64
+	Size   int64
+}
+
+func (e InvalidTruncateLength) Error() string {
+	return fmt.Sprintf("Cannot truncate object %s/%s of size %d to %d bytes", e.Bucket, e.Object, e.Size, e.Length)
+}
+
 // ObjectTooLarge error returned when the size of the object > max object size allowed (5G) per request.
 type ObjectTooLarge GenericError
 
@@ -371,3 +424,23 @@ func isErrObjectNotFound(err error) bool {
 	}
 	return false
 }
+
+// Check if error type is BucketNotFound.
+func isErrBucketNotFound(err error) bool {
+	err = errorCause(err)
+	switch err.(type) {
+	case BucketNotFound:
+		return true
+	}
+	return false
+}
+
+// Check if error type is InvalidUploadID.
+func isErrInvalidUploadID(err error) bool {
+	err = errorCause(err)
+	switch err.(type) {
+	case InvalidUploadID:
+		return true
+	}
+	return false
+}
@@ -23,15 +23,35 @@ import (
 )
 
 // Checks on GetObject arguments, bucket and object.
+//
+// An object name with a trailing slash refers to the directory marker
+// of that prefix and never to the object without the slash, i.e. GET
+// on "a/b/" does not return the object "a/b". Directory markers cannot
+// be created on Minio, so such requests always fail with ObjectNotFound.
 func checkGetObjArgs(bucket, object string) error {
+	if IsValidBucketName(bucket) && isDirMarkerName(object) {
+		return traceError(ObjectNotFound{Bucket: bucket, Object: object})
+	}
 	return checkBucketAndObjectNames(bucket, object)
 }
 
+// isDirMarkerName - returns true if object name refers to the
+// directory marker of a prefix.
+func isDirMarkerName(object string) bool {
+	return len(object) > 1 && strings.HasSuffix(object, slashSeparator) &&
+		IsValidObjectName(strings.TrimSuffix(object, slashSeparator))
+}
+
 // Checks on DeleteObject arguments, bucket and object.
 func checkDelObjArgs(bucket, object string) error {
 	return checkBucketAndObjectNames(bucket, object)
 }
 
+// Checks on TruncateObject arguments, bucket and object.
+func checkTruncateObjArgs(bucket, object string) error {
+	return checkBucketAndObjectNames(bucket, object)
+}
+
 // Checks bucket and object name validity, returns nil if both are valid.
 func checkBucketAndObjectNames(bucket, object string) error {
 	// Verify if bucket is valid.
@@ -62,12 +82,6 @@ func checkListObjsArgs(bucket, prefix, marker, delimiter string, obj ObjectLayer
 			Object: prefix,
 		})
 	}
-	// Verify if delimiter is anything other than '/', which we do not support.
-	if delimiter != "" && delimiter != slashSeparator {
-		return traceError(UnsupportedDelimiter{
-			Delimiter: delimiter,
-		})
-	}
 	// Verify if marker has prefix.
 	if marker != "" && !strings.HasPrefix(marker, prefix) {
 		return traceError(InvalidMarkerPrefixCombination{
@@ -83,6 +97,13 @@ func checkListMultipartArgs(bucket, prefix, keyMarker, uploadIDMarker, delimiter
 	if err := checkListObjsArgs(bucket, prefix, keyMarker, delimiter, obj); err != nil {
 		return err
 	}
+	// Verify if delimiter is anything other than '/', which we do not
+	// support for multipart uploads.
+	if delimiter != "" && delimiter != slashSeparator {
+		return traceError(UnsupportedDelimiter{
+			Delimiter: delimiter,
+		})
+	}
 	if uploadIDMarker != "" {
 		if strings.HasSuffix(keyMarker, slashSeparator) {
 			return traceError(InvalidUploadIDKeyCombination{
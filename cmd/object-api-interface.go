@@ -37,6 +37,7 @@ type ObjectLayer interface {
 	PutObject(bucket, object string, size int64, data io.Reader, metadata map[string]string, sha256sum string) (objInfo ObjectInfo, err error)
 	CopyObject(srcBucket, srcObject, destBucket, destObject string, metadata map[string]string) (objInfo ObjectInfo, err error)
 	DeleteObject(bucket, object string) error
+	TruncateObject(bucket, object string, size int64) (objInfo ObjectInfo, err error)
 
 	// Multipart operations.
 	ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (result ListMultipartsInfo, err error)
@@ -49,5 +50,9 @@ type ObjectLayer interface {
 	// Healing operations.
 	HealBucket(bucket string) error
 	HealObject(bucket, object string) error
+	VerifyHealObject(bucket, object string) (HealStatus, error)
 	ListObjectsHeal(bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error)
+	RebuildBucketIndex() (BucketIndexReport, error)
+	DrainObject(bucket, object string) (bool, error)
+	ListPendingHeals() ([]PendingHealInfo, error)
 }
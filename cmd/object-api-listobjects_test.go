@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"fmt"
 	"io/ioutil"
+	"reflect"
 	"strconv"
 	"strings"
 	"testing"
@@ -430,10 +431,9 @@ func testListObjects(obj ObjectLayer, instanceType string, t TestErrHandler) {
 		{"volatile-bucket-1", "", "", "", 0, ListObjectsInfo{}, BucketNotFound{Bucket: "volatile-bucket-1"}, false},
 		{"volatile-bucket-2", "", "", "", 0, ListObjectsInfo{}, BucketNotFound{Bucket: "volatile-bucket-2"}, false},
 		{"volatile-bucket-3", "", "", "", 0, ListObjectsInfo{}, BucketNotFound{Bucket: "volatile-bucket-3"}, false},
-		// Valid, existing bucket, but sending invalid delimeter values (9-10).
-		// Empty string < "" > and forward slash < / > are the ony two valid arguments for delimeter.
-		{"test-bucket-list-object", "", "", "*", 0, ListObjectsInfo{}, fmt.Errorf("delimiter '%s' is not supported", "*"), false},
-		{"test-bucket-list-object", "", "", "-", 0, ListObjectsInfo{}, fmt.Errorf("delimiter '%s' is not supported", "-"), false},
+		// Valid, existing bucket, with delimiters other than forward slash < / > (9-10).
+		{"test-bucket-list-object", "", "", "*", 0, ListObjectsInfo{}, nil, true},
+		{"test-bucket-list-object", "", "", "-", 0, ListObjectsInfo{}, nil, true},
 		// Testing for failure cases with both perfix and marker (11).
 		// The prefix and marker combination to be valid it should satisy strings.HasPrefix(marker, prefix).
 		{"test-bucket-list-object", "asia", "europe-object", "", 0, ListObjectsInfo{}, fmt.Errorf("Invalid combination of marker '%s' and prefix '%s'", "europe-object", "asia"), false},
@@ -569,6 +569,70 @@ func testListObjects(obj ObjectLayer, instanceType string, t TestErrHandler) {
 	}
 }
 
+// Wrapper for calling ListObjects tests with delimiters other than '/'
+// for both XL multiple disks and single node setup.
+func TestListObjectsDelimiter(t *testing.T) {
+	ExecObjectLayerTest(t, testListObjectsDelimiter)
+}
+
+// Tests keys are grouped into common prefixes by any delimiter.
+func testListObjectsDelimiter(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	for _, object := range []string{"a:b:c", "a:b:d", "a:e", "b", "c:d", "c:e:f", "dir/x:y", "z"} {
+		if _, err := obj.PutObject(bucket, object, int64(len(object)), bytes.NewBufferString(object), nil, ""); err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+	}
+
+	testCases := []struct {
+		prefix, marker, delimiter string
+		maxKeys                   int
+		expectedObjects           []string
+		expectedPrefixes          []string
+		expectedIsTruncated       bool
+	}{
+		// Keys with the delimiter after the prefix are grouped (1-4).
+		{"", "", ":", 1000, []string{"b", "z"}, []string{"a:", "c:", "dir/x:"}, false},
+		{"a:", "", ":", 1000, []string{"a:e"}, []string{"a:b:"}, false},
+		{"a:b:", "", ":", 1000, []string{"a:b:c", "a:b:d"}, nil, false},
+		{"a", "", ":", 1000, nil, []string{"a:"}, false},
+		// Multi-character delimiter (5).
+		{"a:", "", "b:", 1000, []string{"a:e"}, []string{"a:b:"}, false},
+		// Delimiter not found in any key (6).
+		{"c", "", "#", 1000, []string{"c:d", "c:e:f"}, nil, false},
+		// Pages of keys and prefixes, a prefix as marker skips all
+		// of its keys (7-10).
+		{"", "", ":", 2, []string{"b"}, []string{"a:"}, true},
+		{"", "b", ":", 2, nil, []string{"c:", "dir/x:"}, true},
+		{"", "dir/x:", ":", 2, []string{"z"}, nil, false},
+		{"", "a:", ":", 1, []string{"b"}, nil, true},
+		// Delimiter '/' is unchanged (11).
+		{"", "", "/", 1000, []string{"a:b:c", "a:b:d", "a:e", "b", "c:d", "c:e:f", "z"}, []string{"dir/"}, false},
+	}
+	for i, testCase := range testCases {
+		result, err := obj.ListObjects(bucket, testCase.prefix, testCase.marker, testCase.delimiter, testCase.maxKeys)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Expected to pass, but failed with: <ERROR> %s", i+1, instanceType, err)
+		}
+		var objects []string
+		for _, objInfo := range result.Objects {
+			objects = append(objects, objInfo.Name)
+		}
+		if !reflect.DeepEqual(objects, testCase.expectedObjects) {
+			t.Errorf("Test %d: %s: Expected objects %v, got %v", i+1, instanceType, testCase.expectedObjects, objects)
+		}
+		if !reflect.DeepEqual(result.Prefixes, testCase.expectedPrefixes) {
+			t.Errorf("Test %d: %s: Expected prefixes %v, got %v", i+1, instanceType, testCase.expectedPrefixes, result.Prefixes)
+		}
+		if result.IsTruncated != testCase.expectedIsTruncated {
+			t.Errorf("Test %d: %s: Expected IsTruncated to be %v, got %v", i+1, instanceType, testCase.expectedIsTruncated, result.IsTruncated)
+		}
+	}
+}
+
 // Initialize FS backend for the benchmark.
 func initFSObjectsB(disk string, t *testing.B) (obj ObjectLayer) {
 	endPoints, err := parseStorageEndpoints([]string{disk})
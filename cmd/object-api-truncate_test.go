@@ -0,0 +1,175 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Wrapper for calling TruncateObject tests for both XL multiple disks and single node setup.
+func TestTruncateObject(t *testing.T) {
+	ExecObjectLayerTest(t, testTruncateObject)
+}
+
+// ObjectLayer.TruncateObject is called with a decreasing set of
+// lengths, data and md5sum of the object are validated after each call.
+func testTruncateObject(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucketName := getRandomBucketName()
+	objectName := "test-object"
+	if err := obj.MakeBucket(bucketName); err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+
+	data := generateBytesData(blockSizeV1 + humanize.KiByte)
+	if _, err := obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+
+	// Truncating beyond the object size is not allowed.
+	if _, err := obj.TruncateObject(bucketName, objectName, int64(len(data))+1); err == nil {
+		t.Fatalf("%s: Expected truncate beyond object size to fail", instanceType)
+	} else if _, ok := errorCause(err).(InvalidTruncateLength); !ok {
+		t.Fatalf("%s: Expected InvalidTruncateLength, got %v", instanceType, err)
+	}
+
+	testCases := []int64{
+		// Same size, nothing changes.
+		int64(len(data)),
+		// Non-aligned length within the last block.
+		blockSizeV1 + 10,
+		// Block aligned length.
+		blockSizeV1,
+		// Non-aligned length within the first block.
+		100,
+		// Empty object.
+		0,
+	}
+	for i, size := range testCases {
+		objInfo, err := obj.TruncateObject(bucketName, objectName, size)
+		if err != nil {
+			t.Fatalf("%s: Test %d: Unexpected error %s", instanceType, i+1, err)
+		}
+		if objInfo.Size != size {
+			t.Errorf("%s: Test %d: Expected size %d, got %d", instanceType, i+1, size, objInfo.Size)
+		}
+		if expected := getMD5Hash(data[:size]); objInfo.MD5Sum != expected {
+			t.Errorf("%s: Test %d: Expected md5sum %s, got %s", instanceType, i+1, expected, objInfo.MD5Sum)
+		}
+		buffer := new(bytes.Buffer)
+		if err = obj.GetObject(bucketName, objectName, 0, size, buffer); err != nil {
+			t.Fatalf("%s: Test %d: Unexpected error %s", instanceType, i+1, err)
+		}
+		if !bytes.Equal(buffer.Bytes(), data[:size]) {
+			t.Errorf("%s: Test %d: Truncated data mismatch", instanceType, i+1)
+		}
+	}
+}
+
+// Tests truncating a multipart object on XL removes trailing parts.
+func TestXLTruncateMultipartObject(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	uploadID, err := obj.NewMultipartUpload(bucket, object, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := generateBytesData(10 * humanize.MiByte)
+	var parts []completePart
+	for i, partData := range [][]byte{data[:5*humanize.MiByte], data[5*humanize.MiByte:]} {
+		md5Hex, perr := obj.PutObjectPart(bucket, object, uploadID, i+1, int64(len(partData)), bytes.NewReader(partData), "", "")
+		if perr != nil {
+			t.Fatal(perr)
+		}
+		parts = append(parts, completePart{PartNumber: i + 1, ETag: md5Hex})
+	}
+	if _, err = obj.CompleteMultipartUpload(bucket, object, uploadID, parts); err != nil {
+		t.Fatal(err)
+	}
+
+	// Shard of the retained part is truncated in place.
+	shardPath := filepath.Join(fsDirs[0], bucket, object, "part.1")
+	shardInfo, err := os.Stat(shardPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	size := int64(humanize.MiByte + 7)
+	objInfo, err := obj.TruncateObject(bucket, object, size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedMD5, err := getCompleteMultipartMD5([]completePart{{PartNumber: 1, ETag: getMD5Hash(data[:size])}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.MD5Sum != expectedMD5 {
+		t.Errorf("Expected md5sum %s, got %s", expectedMD5, objInfo.MD5Sum)
+	}
+
+	xl := obj.(*xlObjects)
+	xlMeta, err := readXLMeta(xl.storageDisks[0], bucket, object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(xlMeta.Parts) != 1 || xlMeta.Parts[0].Size != size {
+		t.Errorf("Expected a single part of size %d, got %v", size, xlMeta.Parts)
+	}
+	if len(xlMeta.Erasure.Checksum) != 1 {
+		t.Errorf("Expected a single checksum, got %v", xlMeta.Erasure.Checksum)
+	}
+	if len(xlMeta.Parts) == 1 && xlMeta.Parts[0].Name != "part.1" {
+		t.Errorf("Expected the truncated part to keep its name, got %s", xlMeta.Parts[0].Name)
+	}
+	newShardInfo, err := os.Stat(shardPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !os.SameFile(shardInfo, newShardInfo) || newShardInfo.Size() >= shardInfo.Size() {
+		t.Error("Expected the shard of the retained part to be truncated in place")
+	}
+	if _, err = xl.storageDisks[0].StatFile(bucket, pathJoin(object, "part.2")); errorCause(err) != errFileNotFound {
+		t.Errorf("Expected part.2 to be removed, got %v", err)
+	}
+
+	buffer := new(bytes.Buffer)
+	if err = obj.GetObject(bucket, object, 0, size, buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buffer.Bytes(), data[:size]) {
+		t.Error("Truncated data mismatch")
+	}
+}
@@ -37,6 +37,10 @@ const (
 	minioMetaMultipartBucket = minioMetaBucket + "/" + mpartMetaPrefix
 	// Minio Tmp meta prefix.
 	minioMetaTmpBucket = minioMetaBucket + "/tmp"
+	// Minio Trash meta prefix, holds objects deleted asynchronously.
+	minioMetaTrashBucket = minioMetaBucket + "/trash"
+	// Minio Heal meta prefix, holds records of objects pending heal.
+	minioMetaHealBucket = minioMetaBucket + "/heal"
 )
 
 // validBucket regexp.
@@ -127,6 +131,23 @@ func pathJoin(elem ...string) string {
 	return path.Join(elem...) + trailingSlash
 }
 
+// groupByDelimiter - returns the common prefix of an entry of a
+// recursive listing at prefix, up to and including the first delimiter
+// after prefix, empty if the entry has none. Entries are skipped if
+// their common prefix was listed last, or before or at the marker, as
+// with S3 listings.
+func groupByDelimiter(entry, prefix, marker, delimiter, lastPrefix string) (commonPrefix string, skip bool) {
+	i := strings.Index(strings.TrimPrefix(entry, prefix), delimiter)
+	if i < 0 {
+		return "", false
+	}
+	commonPrefix = entry[:len(prefix)+i+len(delimiter)]
+	if commonPrefix == lastPrefix || strings.HasPrefix(marker, commonPrefix) {
+		return "", true
+	}
+	return commonPrefix, false
+}
+
 // mustGetUUID - get a random UUID.
 func mustGetUUID() string {
 	uuid, err := uuid.New()
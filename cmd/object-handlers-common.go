@@ -52,12 +52,12 @@ func checkCopyObjectPreconditions(w http.ResponseWriter, r *http.Request, objInf
 			w.Header().Set("ETag", "\""+objInfo.MD5Sum+"\"")
 		}
 	}
-	// x-amz-copy-source-if-modified-since: Return the object only if it has been modified
-	// since the specified time otherwise return 412 (precondition failed).
-	ifModifiedSinceHeader := r.Header.Get("x-amz-copy-source-if-modified-since")
-	if ifModifiedSinceHeader != "" {
-		if !ifModifiedSince(objInfo.ModTime, ifModifiedSinceHeader) {
-			// If the object is not modified since the specified time.
+	// x-amz-copy-source-if-match : Return the object only if its entity tag (ETag) is the
+	// same as the one specified; otherwise return a 412 (precondition failed).
+	ifMatchETagHeader := r.Header.Get("x-amz-copy-source-if-match")
+	if ifMatchETagHeader != "" {
+		if objInfo.MD5Sum != "" && !isETagMatch(objInfo.MD5Sum, ifMatchETagHeader) {
+			// If the object ETag does not match with the specified ETag.
 			writeHeaders()
 			writeErrorResponse(w, ErrPreconditionFailed, r.URL)
 			return true
@@ -66,8 +66,10 @@ func checkCopyObjectPreconditions(w http.ResponseWriter, r *http.Request, objInf
 
 	// x-amz-copy-source-if-unmodified-since : Return the object only if it has not been
 	// modified since the specified time, otherwise return a 412 (precondition failed).
+	// As in S3 it is not evaluated if x-amz-copy-source-if-match is set and matched,
+	// invalid times are ignored.
 	ifUnmodifiedSinceHeader := r.Header.Get("x-amz-copy-source-if-unmodified-since")
-	if ifUnmodifiedSinceHeader != "" {
+	if ifUnmodifiedSinceHeader != "" && ifMatchETagHeader == "" && isHTTPTimeValid(ifUnmodifiedSinceHeader) {
 		if ifModifiedSince(objInfo.ModTime, ifUnmodifiedSinceHeader) {
 			// If the object is modified since the specified time.
 			writeHeaders()
@@ -76,29 +78,31 @@ func checkCopyObjectPreconditions(w http.ResponseWriter, r *http.Request, objInf
 		}
 	}
 
-	// x-amz-copy-source-if-match : Return the object only if its entity tag (ETag) is the
-	// same as the one specified; otherwise return a 412 (precondition failed).
-	ifMatchETagHeader := r.Header.Get("x-amz-copy-source-if-match")
-	if ifMatchETagHeader != "" {
-		if objInfo.MD5Sum != "" && !isETagEqual(objInfo.MD5Sum, ifMatchETagHeader) {
-			// If the object ETag does not match with the specified ETag.
+	// x-amz-copy-source-if-none-match : Return the object only if its entity tag (ETag) is
+	// different from the one specified otherwise, return a 412 (precondition failed).
+	ifNoneMatchETagHeader := r.Header.Get("x-amz-copy-source-if-none-match")
+	if ifNoneMatchETagHeader != "" {
+		if objInfo.MD5Sum != "" && isETagMatch(objInfo.MD5Sum, ifNoneMatchETagHeader) {
+			// If the object ETag matches with the specified ETag.
 			writeHeaders()
 			writeErrorResponse(w, ErrPreconditionFailed, r.URL)
 			return true
 		}
 	}
 
-	// If-None-Match : Return the object only if its entity tag (ETag) is different from the
-	// one specified otherwise, return a 304 (not modified).
-	ifNoneMatchETagHeader := r.Header.Get("x-amz-copy-source-if-none-match")
-	if ifNoneMatchETagHeader != "" {
-		if objInfo.MD5Sum != "" && isETagEqual(objInfo.MD5Sum, ifNoneMatchETagHeader) {
-			// If the object ETag matches with the specified ETag.
+	// x-amz-copy-source-if-modified-since: Return the object only if it has been modified
+	// since the specified time otherwise return 412 (precondition failed), invalid times
+	// are ignored.
+	ifModifiedSinceHeader := r.Header.Get("x-amz-copy-source-if-modified-since")
+	if ifModifiedSinceHeader != "" && isHTTPTimeValid(ifModifiedSinceHeader) {
+		if !ifModifiedSince(objInfo.ModTime, ifModifiedSinceHeader) {
+			// If the object is not modified since the specified time.
 			writeHeaders()
 			writeErrorResponse(w, ErrPreconditionFailed, r.URL)
 			return true
 		}
 	}
+
 	// Object content should be written to http.ResponseWriter
 	return false
 }
@@ -198,6 +202,42 @@ func ifModifiedSince(objTime time.Time, givenTimeStr string) bool {
 	return false
 }
 
+// isIfRangeMatch returns true if the validator of If-Range, an ETag or
+// a date, matches the object or if there is no validator. As in RFC
+// 7233 only strong validators match: weak ETags never do and dates
+// have to be the exact modification time.
+func isIfRangeMatch(ifRange string, objInfo ObjectInfo) bool {
+	if ifRange == "" {
+		return true
+	}
+	if givenTime, err := time.Parse(http.TimeFormat, ifRange); err == nil {
+		return objInfo.ModTime.UTC().Truncate(time.Second).Equal(givenTime)
+	}
+	if strings.HasPrefix(ifRange, "W/") || objInfo.MD5Sum == "" {
+		return false
+	}
+	return isETagEqual(objInfo.MD5Sum, ifRange)
+}
+
+// isHTTPTimeValid returns true if timeStr is a valid time in the format
+// of HTTP headers.
+func isHTTPTimeValid(timeStr string) bool {
+	_, err := time.Parse(http.TimeFormat, timeStr)
+	return err == nil
+}
+
+// isETagMatch returns true if etag is one of the comma separated ETags
+// of the conditional header value, or if the value is "*".
+func isETagMatch(etag, headerValue string) bool {
+	for _, headerETag := range strings.Split(headerValue, ",") {
+		headerETag = strings.TrimSpace(headerETag)
+		if headerETag == "*" || isETagEqual(etag, headerETag) {
+			return true
+		}
+	}
+	return false
+}
+
 // canonicalizeETag returns ETag with leading and trailing double-quotes removed,
 // if any present
 func canonicalizeETag(etag string) string {
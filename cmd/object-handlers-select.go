@@ -0,0 +1,334 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	mux "github.com/gorilla/mux"
+)
+
+const (
+	// Maximum size of a SelectObjectContent request body.
+	maxSelectRequestSize = 256 * 1024
+
+	// Selected records are sent once they add up to this many bytes.
+	selectRecordsMessageSize = 64 * 1024
+)
+
+// selectCSVInput - format of the CSV object queried.
+type selectCSVInput struct {
+	// USE, IGNORE or NONE, whether the first line holds column names.
+	FileHeaderInfo  string
+	RecordDelimiter string
+	FieldDelimiter  string
+	QuoteCharacter  string
+	Comments        string
+}
+
+// selectCSVOutput - format of the CSV records returned.
+type selectCSVOutput struct {
+	// ALWAYS or ASNEEDED, whether all fields are quoted.
+	QuoteFields     string
+	RecordDelimiter string
+	FieldDelimiter  string
+	QuoteCharacter  string
+}
+
+// selectObjectContentRequest - body of a SelectObjectContent request.
+type selectObjectContentRequest struct {
+	XMLName            xml.Name `xml:"SelectObjectContentRequest"`
+	Expression         string
+	ExpressionType     string
+	InputSerialization struct {
+		CompressionType string
+		CSV             *selectCSVInput
+	}
+	OutputSerialization struct {
+		CSV *selectCSVOutput
+	}
+}
+
+// isSingleRune - returns true if s is empty or a single character.
+func isSingleRune(s string) bool {
+	return utf8.RuneCountInString(s) <= 1
+}
+
+// validate - verifies the input and output formats are supported.
+func (req selectObjectContentRequest) validate() APIErrorCode {
+	if !strings.EqualFold(req.ExpressionType, "SQL") {
+		return ErrInvalidExpressionType
+	}
+	in, out := req.InputSerialization.CSV, req.OutputSerialization.CSV
+	if in == nil || out == nil {
+		return ErrUnsupportedSelectSerialization
+	}
+	if c := req.InputSerialization.CompressionType; c != "" && !strings.EqualFold(c, "NONE") {
+		return ErrUnsupportedSelectSerialization
+	}
+	switch strings.ToUpper(in.FileHeaderInfo) {
+	case "", "NONE", "IGNORE", "USE":
+	default:
+		return ErrUnsupportedSelectSerialization
+	}
+	switch strings.ToUpper(out.QuoteFields) {
+	case "", "ASNEEDED", "ALWAYS":
+	default:
+		return ErrUnsupportedSelectSerialization
+	}
+	// Record delimiters are \r\n or a single byte, see newSelectCSVReader.
+	if in.RecordDelimiter != "\r\n" && len(in.RecordDelimiter) > 1 {
+		return ErrUnsupportedSelectSerialization
+	}
+	if !isSingleRune(in.FieldDelimiter) || in.FieldDelimiter == `"` || in.FieldDelimiter == "\n" || in.FieldDelimiter == "\r" {
+		return ErrUnsupportedSelectSerialization
+	}
+	// Input is parsed by encoding/csv, which only supports double quotes.
+	if in.QuoteCharacter != "" && in.QuoteCharacter != `"` {
+		return ErrUnsupportedSelectSerialization
+	}
+	if !isSingleRune(in.Comments) {
+		return ErrUnsupportedSelectSerialization
+	}
+	if !isSingleRune(out.FieldDelimiter) || !isSingleRune(out.QuoteCharacter) {
+		return ErrUnsupportedSelectSerialization
+	}
+	return ErrNone
+}
+
+// byteReplaceReader - io.Reader replacing every old byte with new.
+type byteReplaceReader struct {
+	io.Reader
+	old, new byte
+}
+
+func (r byteReplaceReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	for i := 0; i < n; i++ {
+		if p[i] == r.old {
+			p[i] = r.new
+		}
+	}
+	return n, err
+}
+
+// newSelectCSVReader - returns a reader of the records of a CSV object.
+func newSelectCSVReader(r io.Reader, in selectCSVInput) *csv.Reader {
+	// Records are split by encoding/csv on newlines, which also
+	// handles \r\n, other delimiters are translated into newlines.
+	if d := in.RecordDelimiter; len(d) == 1 && d != "\n" {
+		r = byteReplaceReader{r, d[0], '\n'}
+	}
+	csvReader := csv.NewReader(r)
+	if in.FieldDelimiter != "" {
+		csvReader.Comma, _ = utf8.DecodeRuneInString(in.FieldDelimiter)
+	}
+	if in.Comments != "" {
+		csvReader.Comment, _ = utf8.DecodeRuneInString(in.Comments)
+	}
+	// Records may have different numbers of fields.
+	csvReader.FieldsPerRecord = -1
+	return csvReader
+}
+
+// writeRecord - appends fields formatted as a CSV record to buf.
+func (out selectCSVOutput) writeRecord(buf *bytes.Buffer, fields []string) {
+	fieldDelimiter, recordDelimiter, quote := out.FieldDelimiter, out.RecordDelimiter, out.QuoteCharacter
+	if fieldDelimiter == "" {
+		fieldDelimiter = ","
+	}
+	if recordDelimiter == "" {
+		recordDelimiter = "\n"
+	}
+	if quote == "" {
+		quote = `"`
+	}
+	always := strings.EqualFold(out.QuoteFields, "ALWAYS")
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteString(fieldDelimiter)
+		}
+		if always || strings.Contains(field, fieldDelimiter) || strings.Contains(field, quote) ||
+			strings.Contains(field, recordDelimiter) || strings.ContainsAny(field, "\r\n") {
+			buf.WriteString(quote)
+			buf.WriteString(strings.Replace(field, quote, quote+quote, -1))
+			buf.WriteString(quote)
+		} else {
+			buf.WriteString(field)
+		}
+	}
+	buf.WriteString(recordDelimiter)
+}
+
+// countingReader - io.Reader counting the bytes read.
+type countingReader struct {
+	io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// SelectObjectContentHandler - POST /bucket/object?select&select-type=2
+// ----------
+// Filters the records of a CSV object with a SQL expression, see
+// select-sql.go for the supported subset of SQL. The object is streamed
+// from the object layer and selected records are sent as they are found,
+// in the event stream framing of S3.
+func (api objectAPIHandlers) SelectObjectContentHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:GetObject", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	var selectReq selectObjectContentRequest
+	if err := xml.NewDecoder(io.LimitReader(r.Body, maxSelectRequestSize)).Decode(&selectReq); err != nil {
+		requestErrorIf(r, err, "Unable to parse select request.")
+		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		return
+	}
+	if s3Error := selectReq.validate(); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+	query, err := parseSQLSelect(selectReq.Expression)
+	if err != nil {
+		requestErrorIf(r, err, "Unable to parse select expression %s.", selectReq.Expression)
+		writeErrorResponse(w, ErrUnsupportedSQLStructure, r.URL)
+		return
+	}
+	in, out := *selectReq.InputSerialization.CSV, *selectReq.OutputSerialization.CSV
+
+	// Lock the object before reading.
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	if err := objectLock.GetRLock(globalLockTimeout); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	defer objectLock.RUnlock()
+
+	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		requestErrorIf(r, err, "Unable to fetch object info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Objects encrypted with a customer key are only read with the key.
+	stream, s3Error := sseCustomerRequestHeaders.readStream(r.Header, objInfo.UserDefined, 0)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Stream the object through a pipe, closing the read end once
+	// done stops GetObject early, e.g. when the LIMIT is reached.
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		pw.CloseWithError(objectAPI.GetObject(bucket, object, 0, objInfo.Size, newDecryptWriter(stream, pw)))
+	}()
+	scanned := &countingReader{Reader: pr}
+	csvReader := newSelectCSVReader(scanned, in)
+
+	// Columns are resolved against the header before responding, such
+	// that unknown columns are reported as a regular error.
+	var header []string
+	switch strings.ToUpper(in.FileHeaderInfo) {
+	case "USE", "IGNORE":
+		record, rerr := csvReader.Read()
+		if rerr != nil && rerr != io.EOF {
+			requestErrorIf(r, rerr, "Unable to read header of %s/%s.", bucket, object)
+			writeErrorResponse(w, toAPIErrorCode(rerr), r.URL)
+			return
+		}
+		if strings.EqualFold(in.FileHeaderInfo, "USE") {
+			header = record
+		}
+	}
+	if err = query.resolve(header); err != nil {
+		requestErrorIf(r, err, "Unable to resolve columns of select expression %s.", selectReq.Expression)
+		writeErrorResponse(w, ErrUnsupportedSQLStructure, r.URL)
+		return
+	}
+
+	setCommonHeaders(w)
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.WriteHeader(http.StatusOK)
+	flusher := w.(http.Flusher)
+
+	var buf bytes.Buffer
+	var returned, selected int64
+	sendRecords := func() {
+		if buf.Len() == 0 {
+			return
+		}
+		w.Write(newRecordsMessage(buf.Bytes()))
+		flusher.Flush()
+		returned += int64(buf.Len())
+		buf.Reset()
+	}
+	for query.limit < 0 || selected < query.limit {
+		record, rerr := csvReader.Read()
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			// Records selected so far are sent, followed by the
+			// error ending the response.
+			requestErrorIf(r, rerr, "Unable to read records of %s/%s.", bucket, object)
+			sendRecords()
+			code := "InternalError"
+			if _, ok := rerr.(*csv.ParseError); ok {
+				code = "CSVParsingError"
+			}
+			w.Write(newErrorMessage(code, rerr.Error()))
+			return
+		}
+		if !query.matches(record) {
+			continue
+		}
+		selected++
+		out.writeRecord(&buf, query.project(record))
+		if buf.Len() >= selectRecordsMessageSize {
+			sendRecords()
+		}
+	}
+	sendRecords()
+	w.Write(newStatsMessage(scanned.n, scanned.n, returned))
+	w.Write(newEndMessage())
+	flusher.Flush()
+}
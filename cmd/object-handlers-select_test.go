@@ -0,0 +1,219 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// eventStreamMessage - decoded event stream message.
+type eventStreamMessage struct {
+	headers map[string]string
+	payload []byte
+}
+
+// decodeEventStream - decodes all the messages of an event stream,
+// verifying lengths and checksums.
+func decodeEventStream(data []byte) ([]eventStreamMessage, error) {
+	var msgs []eventStreamMessage
+	for len(data) > 0 {
+		if len(data) < 16 {
+			return nil, fmt.Errorf("Truncated message of %d bytes", len(data))
+		}
+		totalLen := int(binary.BigEndian.Uint32(data[0:]))
+		hdrsLen := int(binary.BigEndian.Uint32(data[4:]))
+		if totalLen > len(data) || 16+hdrsLen > totalLen {
+			return nil, fmt.Errorf("Invalid lengths %d and %d", totalLen, hdrsLen)
+		}
+		if crc32.ChecksumIEEE(data[:8]) != binary.BigEndian.Uint32(data[8:]) {
+			return nil, fmt.Errorf("Prelude checksum mismatch")
+		}
+		if crc32.ChecksumIEEE(data[:totalLen-4]) != binary.BigEndian.Uint32(data[totalLen-4:]) {
+			return nil, fmt.Errorf("Message checksum mismatch")
+		}
+		msg := eventStreamMessage{headers: make(map[string]string)}
+		hdrs := data[12 : 12+hdrsLen]
+		for len(hdrs) > 0 {
+			nameLen := int(hdrs[0])
+			name := string(hdrs[1 : 1+nameLen])
+			hdrs = hdrs[1+nameLen:]
+			if hdrs[0] != eventStreamStringHeader {
+				return nil, fmt.Errorf("Unexpected type %d of header %s", hdrs[0], name)
+			}
+			valueLen := int(binary.BigEndian.Uint16(hdrs[1:]))
+			msg.headers[name] = string(hdrs[3 : 3+valueLen])
+			hdrs = hdrs[3+valueLen:]
+		}
+		msg.payload = data[12+hdrsLen : totalLen-4]
+		msgs = append(msgs, msg)
+		data = data[totalLen:]
+	}
+	return msgs, nil
+}
+
+// selectRequestBody - returns the body of a SelectObjectContent request.
+func selectRequestBody(expression, inputCSV, outputCSV string) []byte {
+	return []byte(`<SelectObjectContentRequest>` +
+		`<Expression>` + expression + `</Expression>` +
+		`<ExpressionType>SQL</ExpressionType>` +
+		`<InputSerialization><CSV>` + inputCSV + `</CSV></InputSerialization>` +
+		`<OutputSerialization><CSV>` + outputCSV + `</CSV></OutputSerialization>` +
+		`</SelectObjectContentRequest>`)
+}
+
+// Tests filtering CSV objects with SelectObjectContent.
+func TestSelectObjectContentHandler(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(fsDir)
+	initNSLock(false)
+
+	bucketName := getRandomBucketName()
+	if err = obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+	objects := map[string]string{
+		"people.csv": "name,age,city\nalice,34,Paris\nbob,9,Berlin\n\"carol, jr\",41,Berlin\ndave,28,Oslo\n",
+		"people.tsv": "# comment\tline\r\nalice\t34\tParis\r\nbob\t9\tBerlin\r\ncarol\t41\tBerlin\r\n",
+		"broken.csv": "name,age\nalice,34\nbob,\"9\n",
+	}
+	for name, data := range objects {
+		if _, err = obj.PutObject(bucketName, name, int64(len(data)), bytes.NewReader([]byte(data)), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	apiRouter := initTestAPIEndPoints(obj, []string{"SelectObjectContent"})
+	credentials := serverConfig.GetCredential()
+
+	testCases := []struct {
+		object         string
+		body           []byte
+		expectedStatus int
+		expected       string
+		expectedError  string
+	}{
+		// Test case - 1.
+		// Filter and project with the column names of the header.
+		{"people.csv", selectRequestBody("SELECT name, age FROM S3Object WHERE age > 30",
+			"<FileHeaderInfo>USE</FileHeaderInfo>", ""),
+			http.StatusOK, "alice,34\n\"carol, jr\",41\n", ""},
+		// Test case - 2.
+		// Skipped header, positional columns, limit and custom output.
+		{"people.csv", selectRequestBody("SELECT s._1, s._3 FROM S3Object s WHERE s._3 = 'Berlin' LIMIT 1",
+			"<FileHeaderInfo>IGNORE</FileHeaderInfo>",
+			"<FieldDelimiter>;</FieldDelimiter><RecordDelimiter>|</RecordDelimiter><QuoteFields>ALWAYS</QuoteFields>"),
+			http.StatusOK, `"bob";"Berlin"|`, ""},
+		// Test case - 3.
+		// Custom field delimiter, \r\n records and comments.
+		{"people.tsv", selectRequestBody("SELECT _1 FROM S3Object WHERE _2 &lt; 40",
+			"<FieldDelimiter>&#9;</FieldDelimiter><RecordDelimiter>&#13;&#10;</RecordDelimiter><Comments>#</Comments>", ""),
+			http.StatusOK, "alice\nbob\n", ""},
+		// Test case - 4.
+		// No records selected.
+		{"people.csv", selectRequestBody("SELECT * FROM S3Object WHERE city = 'Rome'",
+			"<FileHeaderInfo>USE</FileHeaderInfo>", ""),
+			http.StatusOK, "", ""},
+		// Test case - 5.
+		// Records before a malformed one are sent, followed by an error.
+		{"broken.csv", selectRequestBody("SELECT name FROM S3Object",
+			"<FileHeaderInfo>USE</FileHeaderInfo>", ""),
+			http.StatusOK, "alice\n", "CSVParsingError"},
+		// Test case - 6.
+		// Unknown column.
+		{"people.csv", selectRequestBody("SELECT country FROM S3Object",
+			"<FileHeaderInfo>USE</FileHeaderInfo>", ""),
+			http.StatusBadRequest, "", ""},
+		// Test case - 7.
+		// Unsupported SQL.
+		{"people.csv", selectRequestBody("SELECT COUNT(*) FROM S3Object", "", ""),
+			http.StatusBadRequest, "", ""},
+		// Test case - 8.
+		// Unsupported quote character.
+		{"people.csv", selectRequestBody("SELECT * FROM S3Object", "<QuoteCharacter>'</QuoteCharacter>", ""),
+			http.StatusBadRequest, "", ""},
+		// Test case - 9.
+		// Malformed request.
+		{"people.csv", []byte("<SelectObjectContentRequest>"), http.StatusBadRequest, "", ""},
+		// Test case - 10.
+		// Missing object.
+		{"missing.csv", selectRequestBody("SELECT * FROM S3Object", "", ""), http.StatusNotFound, "", ""},
+	}
+
+	for i, testCase := range testCases {
+		req, rerr := newTestSignedRequestV4("POST", getSelectObjectContentURL("", bucketName, testCase.object),
+			int64(len(testCase.body)), bytes.NewReader(testCase.body), credentials.AccessKey, credentials.SecretKey)
+		if rerr != nil {
+			t.Fatalf("Test %d: %v", i+1, rerr)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedStatus {
+			t.Errorf("Test %d: Expected status %d, got %d: %s", i+1, testCase.expectedStatus, rec.Code, rec.Body)
+			continue
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+
+		msgs, derr := decodeEventStream(rec.Body.Bytes())
+		if derr != nil {
+			t.Errorf("Test %d: Unable to decode response: %v", i+1, derr)
+			continue
+		}
+		var records bytes.Buffer
+		var errorCode string
+		var ended bool
+		for _, msg := range msgs {
+			if ended {
+				t.Errorf("Test %d: Unexpected message after End", i+1)
+			}
+			switch {
+			case msg.headers[":message-type"] == "error":
+				errorCode = msg.headers[":error-code"]
+				ended = true
+			case msg.headers[":event-type"] == "Records":
+				records.Write(msg.payload)
+			case msg.headers[":event-type"] == "End":
+				ended = true
+			}
+		}
+		if records.String() != testCase.expected {
+			t.Errorf("Test %d: Expected records %q, got %q", i+1, testCase.expected, records.String())
+		}
+		if errorCode != testCase.expectedError {
+			t.Errorf("Test %d: Expected error %q, got %q", i+1, testCase.expectedError, errorCode)
+		}
+		if !ended {
+			t.Errorf("Test %d: Response did not end with End or an error", i+1)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	// Metadata key of the tags of an object, URL query encoded. Not
+	// returned as a header, only their count is.
+	objectTaggingMetaKey = "X-Minio-Internal-Tagging"
+
+	// Header returning the number of tags of an object.
+	objectTaggingCountHeader = "X-Amz-Tagging-Count"
+
+	// Limits of tags of an object, as in S3.
+	maxObjectTags     = 10
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+
+	// Maximum size of a tagging request body.
+	maxObjectTaggingSize = 64 * 1024
+)
+
+// objectTag - a key and value tagging an object.
+type objectTag struct {
+	Key   string
+	Value string
+}
+
+// objectTagging - tag set of an object, request of PutObjectTagging
+// and response of GetObjectTagging.
+type objectTagging struct {
+	XMLName xml.Name    `xml:"Tagging"`
+	TagSet  []objectTag `xml:"TagSet>Tag"`
+}
+
+// validateObjectTags - verifies tags do not exceed the limits of S3 and
+// that their keys are unique.
+func validateObjectTags(tags []objectTag) APIErrorCode {
+	if len(tags) > maxObjectTags {
+		return ErrTooManyTags
+	}
+	keys := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		if tag.Key == "" || utf8.RuneCountInString(tag.Key) > maxTagKeyLength ||
+			utf8.RuneCountInString(tag.Value) > maxTagValueLength {
+			return ErrInvalidTag
+		}
+		if _, ok := keys[tag.Key]; ok {
+			return ErrInvalidTag
+		}
+		keys[tag.Key] = struct{}{}
+	}
+	return ErrNone
+}
+
+// encodeObjectTags - returns tags URL query encoded, as in x-amz-tagging,
+// sorted by key. Empty if there are no tags.
+func encodeObjectTags(tags []objectTag) string {
+	values := make(url.Values, len(tags))
+	for _, tag := range tags {
+		values.Set(tag.Key, tag.Value)
+	}
+	return values.Encode()
+}
+
+// parseObjectTags - parses URL query encoded tags, as in x-amz-tagging,
+// and validates them.
+func parseObjectTags(encoded string) ([]objectTag, APIErrorCode) {
+	values, err := url.ParseQuery(encoded)
+	if err != nil {
+		return nil, ErrInvalidTag
+	}
+	keys := make([]string, 0, len(values))
+	for key, vals := range values {
+		// Each key must be given once.
+		if len(vals) != 1 {
+			return nil, ErrInvalidTag
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tags := make([]objectTag, 0, len(keys))
+	for _, key := range keys {
+		tags = append(tags, objectTag{Key: key, Value: values.Get(key)})
+	}
+	if s3Error := validateObjectTags(tags); s3Error != ErrNone {
+		return nil, s3Error
+	}
+	return tags, ErrNone
+}
+
+// getObjectTags - returns the tags saved in the metadata of an object.
+func getObjectTags(metadata map[string]string) []objectTag {
+	encoded, ok := metadata[objectTaggingMetaKey]
+	if !ok {
+		return nil
+	}
+	// Saved tags are always valid.
+	tags, _ := parseObjectTags(encoded)
+	return tags
+}
+
+// setObjectTagsFromHeader - saves into metadata the tags of the
+// x-amz-tagging header, if set.
+func setObjectTagsFromHeader(header http.Header, metadata map[string]string) APIErrorCode {
+	delete(metadata, objectTaggingMetaKey)
+	if _, ok := header[http.CanonicalHeaderKey("X-Amz-Tagging")]; !ok {
+		return ErrNone
+	}
+	tags, s3Error := parseObjectTags(header.Get("X-Amz-Tagging"))
+	if s3Error != ErrNone {
+		return s3Error
+	}
+	if len(tags) > 0 {
+		metadata[objectTaggingMetaKey] = encodeObjectTags(tags)
+	}
+	return ErrNone
+}
+
+// Check if the tagging REPLACE is requested, COPY is the default.
+func isTaggingReplace(h http.Header) bool {
+	return h.Get("X-Amz-Tagging-Directive") == "REPLACE"
+}
+
+// Check if the tagging directive is valid, if set.
+func isTaggingDirectiveValid(h http.Header) bool {
+	if _, ok := h[http.CanonicalHeaderKey("X-Amz-Tagging-Directive")]; ok {
+		return isTaggingReplace(h) || h.Get("X-Amz-Tagging-Directive") == "COPY"
+	}
+	return true
+}
+
+// setObjectTagsHeader - sets the tag count header of an object with tags.
+func setObjectTagsHeader(w http.ResponseWriter, metadata map[string]string) {
+	if tags := getObjectTags(metadata); len(tags) > 0 {
+		w.Header().Set(objectTaggingCountHeader, strconv.Itoa(len(tags)))
+	}
+}
+
+// updateObjectTags - replaces the tags of an object by tags, no tags
+// removes them. Only the metadata of the object is updated.
+func updateObjectTags(objectAPI ObjectLayer, bucket, object string, tags []objectTag) (ObjectInfo, error) {
+	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	metadata := make(map[string]string, len(objInfo.UserDefined)+2)
+	for k, v := range objInfo.UserDefined {
+		metadata[k] = v
+	}
+	// Metadata of the object is replaced as a whole, keep its ETag.
+	metadata["md5Sum"] = objInfo.MD5Sum
+	delete(metadata, objectTaggingMetaKey)
+	if len(tags) > 0 {
+		metadata[objectTaggingMetaKey] = encodeObjectTags(tags)
+	}
+	return objectAPI.CopyObject(bucket, object, bucket, object, metadata)
+}
+
+// GetObjectTaggingHandler - GET Object tagging
+// ----------
+// Returns the tag set of an object.
+func (api objectAPIHandlers) GetObjectTaggingHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:GetObjectTagging", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	if err := objectLock.GetRLock(globalLockTimeout); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	defer objectLock.RUnlock()
+
+	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		requestErrorIf(r, err, "Unable to fetch object info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// An object without tags has an empty tag set.
+	response := objectTagging{TagSet: getObjectTags(objInfo.UserDefined)}
+	if response.TagSet == nil {
+		response.TagSet = []objectTag{}
+	}
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
+
+// PutObjectTaggingHandler - PUT Object tagging
+// ----------
+// Replaces the tag set of an object, the object itself is not modified.
+func (api objectAPIHandlers) PutObjectTaggingHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:PutObjectTagging", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	var tagging objectTagging
+	if err := xml.NewDecoder(io.LimitReader(r.Body, maxObjectTaggingSize)).Decode(&tagging); err != nil {
+		requestErrorIf(r, err, "Unable to parse tagging request.")
+		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		return
+	}
+	if s3Error := validateObjectTags(tagging.TagSet); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	if err := objectLock.GetLock(globalLockTimeout); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	defer objectLock.Unlock()
+
+	if _, err := updateObjectTags(objectAPI, bucket, object, tagging.TagSet); err != nil {
+		requestErrorIf(r, err, "Unable to update tags of %s/%s.", bucket, object)
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// DeleteObjectTaggingHandler - DELETE Object tagging
+// ----------
+// Removes the tag set of an object, the object itself is not modified.
+func (api objectAPIHandlers) DeleteObjectTaggingHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:DeleteObjectTagging", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	if err := objectLock.GetLock(globalLockTimeout); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	defer objectLock.Unlock()
+
+	if _, err := updateObjectTags(objectAPI, bucket, object, nil); err != nil {
+		requestErrorIf(r, err, "Unable to remove tags of %s/%s.", bucket, object)
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	writeSuccessNoContent(w)
+}
@@ -0,0 +1,249 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// Tests validation of tags.
+func TestParseObjectTags(t *testing.T) {
+	testCases := []struct {
+		encoded         string
+		expectedTags    []objectTag
+		expectedS3Error APIErrorCode
+	}{
+		// Test case - 1.
+		// No tags.
+		{"", []objectTag{}, ErrNone},
+		// Test case - 2.
+		// Tags are sorted by key.
+		{"project=alpha&cost-center=42", []objectTag{{"cost-center", "42"}, {"project", "alpha"}}, ErrNone},
+		// Test case - 3.
+		// Empty values are valid.
+		{"key=", []objectTag{{"key", ""}}, ErrNone},
+		// Test case - 4.
+		// Duplicate keys.
+		{"key=1&key=2", nil, ErrInvalidTag},
+		// Test case - 5.
+		// Key too long.
+		{string(bytes.Repeat([]byte("k"), maxTagKeyLength+1)) + "=value", nil, ErrInvalidTag},
+		// Test case - 6.
+		// Value too long.
+		{"key=" + string(bytes.Repeat([]byte("v"), maxTagValueLength+1)), nil, ErrInvalidTag},
+		// Test case - 7.
+		// Too many tags.
+		{"k0=v&k1=v&k2=v&k3=v&k4=v&k5=v&k6=v&k7=v&k8=v&k9=v&k10=v", nil, ErrTooManyTags},
+		// Test case - 8.
+		// Invalid encoding.
+		{"key=%zz", nil, ErrInvalidTag},
+	}
+	for i, testCase := range testCases {
+		tags, s3Error := parseObjectTags(testCase.encoded)
+		if s3Error != testCase.expectedS3Error {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedS3Error, s3Error)
+			continue
+		}
+		if s3Error == ErrNone && !reflect.DeepEqual(tags, testCase.expectedTags) {
+			t.Errorf("Test %d: Expected tags %v, got %v", i+1, testCase.expectedTags, tags)
+		}
+	}
+}
+
+// Wrapper for calling object tagging handler tests for both XL multiple disks and single node setup.
+func TestObjectTaggingHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testObjectTaggingHandlers, []string{"GetObjectTagging", "PutObjectTagging",
+		"DeleteObjectTagging", "CopyObject", "PutObject", "HeadObject"})
+}
+
+func testObjectTaggingHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	data := []byte("hello")
+
+	// do - signs and sends a request with headers, returns the recorded response.
+	do := func(method, urlStr string, body []byte, headers map[string]string) *httptest.ResponseRecorder {
+		req, err := newTestSignedRequestV4(method, urlStr, int64(len(body)), bytes.NewReader(body),
+			credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s %s: <ERROR> %v", instanceType, method, urlStr, err)
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// getTags - returns the tags of object returned by GetObjectTagging.
+	getTags := func(object string) []objectTag {
+		rec := do("GET", "/"+bucketName+"/"+object+"?tagging", nil, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Expected GetObjectTagging to succeed, got %d: %s", instanceType, rec.Code, rec.Body)
+		}
+		var tagging objectTagging
+		if err := xml.Unmarshal(rec.Body.Bytes(), &tagging); err != nil {
+			t.Fatalf("%s: Failed to parse GetObjectTagging response: <ERROR> %v", instanceType, err)
+		}
+		return tagging.TagSet
+	}
+
+	// putTagging - returns the request body of PutObjectTagging with n tags.
+	putTagging := func(n int) []byte {
+		var tagging objectTagging
+		for i := 0; i < n; i++ {
+			tagging.TagSet = append(tagging.TagSet, objectTag{Key: fmt.Sprintf("key-%d", i), Value: "value"})
+		}
+		body, err := xml.Marshal(tagging)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return body
+	}
+
+	// Tags are set along with the object.
+	rec := do("PUT", "/"+bucketName+"/object", data, map[string]string{"X-Amz-Tagging": "project=alpha&cost-center=42"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected PutObject to succeed, got %d: %s", instanceType, rec.Code, rec.Body)
+	}
+	expectedTags := []objectTag{{"cost-center", "42"}, {"project", "alpha"}}
+	if tags := getTags("object"); !reflect.DeepEqual(tags, expectedTags) {
+		t.Errorf("%s: Expected tags %v, got %v", instanceType, expectedTags, tags)
+	}
+
+	// Only the count of tags is returned as a header.
+	rec = do("HEAD", "/"+bucketName+"/object", nil, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected HeadObject to succeed, got %d", instanceType, rec.Code)
+	}
+	if count := rec.Header().Get("X-Amz-Tagging-Count"); count != "2" {
+		t.Errorf("%s: Expected X-Amz-Tagging-Count 2, got `%s`", instanceType, count)
+	}
+	if tagging := rec.Header().Get(objectTaggingMetaKey); tagging != "" {
+		t.Errorf("%s: Expected tags not to be returned as a header, got `%s`", instanceType, tagging)
+	}
+
+	// More than 10 tags are rejected along with the object.
+	rec = do("PUT", "/"+bucketName+"/too-many-tags", data,
+		map[string]string{"X-Amz-Tagging": "k0=v&k1=v&k2=v&k3=v&k4=v&k5=v&k6=v&k7=v&k8=v&k9=v&k10=v"})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("%s: Expected PutObject with 11 tags to fail with %d, got %d", instanceType, http.StatusBadRequest, rec.Code)
+	}
+	if _, err := obj.GetObjectInfo(bucketName, "too-many-tags"); err == nil {
+		t.Errorf("%s: Expected object with 11 tags not to be created", instanceType)
+	}
+
+	// Up to 10 tags replace the tags of the object, its ETag is kept.
+	objInfo, err := obj.GetObjectInfo(bucketName, "object")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec = do("PUT", "/"+bucketName+"/object?tagging", putTagging(maxObjectTags), nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected PutObjectTagging with 10 tags to succeed, got %d: %s", instanceType, rec.Code, rec.Body)
+	}
+	if tags := getTags("object"); len(tags) != maxObjectTags {
+		t.Errorf("%s: Expected %d tags, got %v", instanceType, maxObjectTags, tags)
+	}
+	newObjInfo, err := obj.GetObjectInfo(bucketName, "object")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newObjInfo.MD5Sum != objInfo.MD5Sum {
+		t.Errorf("%s: Expected ETag %s to be kept, got %s", instanceType, objInfo.MD5Sum, newObjInfo.MD5Sum)
+	}
+	// The content is kept as well, tags are set in place.
+	var buffer bytes.Buffer
+	if err = obj.GetObject(bucketName, "object", 0, int64(len(data)), &buffer); err != nil {
+		t.Fatalf("%s: Expected object to be readable once tagged, got %v", instanceType, err)
+	}
+	if !bytes.Equal(buffer.Bytes(), data) {
+		t.Errorf("%s: Expected content %q to be kept, got %q", instanceType, data, buffer.Bytes())
+	}
+
+	// More than 10 tags are rejected.
+	rec = do("PUT", "/"+bucketName+"/object?tagging", putTagging(maxObjectTags+1), nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("%s: Expected PutObjectTagging with 11 tags to fail with %d, got %d", instanceType, http.StatusBadRequest, rec.Code)
+	}
+	var apiErr APIErrorResponse
+	if err = xml.Unmarshal(rec.Body.Bytes(), &apiErr); err != nil {
+		t.Fatalf("%s: Failed to parse error response: <ERROR> %v", instanceType, err)
+	}
+	if apiErr.Code != getAPIError(ErrTooManyTags).Code {
+		t.Errorf("%s: Expected error code %s, got %s", instanceType, getAPIError(ErrTooManyTags).Code, apiErr.Code)
+	}
+	if tags := getTags("object"); len(tags) != maxObjectTags {
+		t.Errorf("%s: Expected the %d tags to be kept, got %v", instanceType, maxObjectTags, tags)
+	}
+
+	// Tags are copied by default, even if metadata is replaced.
+	rec = do("PUT", getCopyObjectURL("", bucketName, "object-copy"), nil, map[string]string{
+		"X-Amz-Copy-Source":        "/" + bucketName + "/object",
+		"X-Amz-Metadata-Directive": "REPLACE",
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected CopyObject to succeed, got %d: %s", instanceType, rec.Code, rec.Body)
+	}
+	if tags := getTags("object-copy"); len(tags) != maxObjectTags {
+		t.Errorf("%s: Expected %d tags to be copied, got %v", instanceType, maxObjectTags, tags)
+	}
+
+	// Tags are replaced with x-amz-tagging-directive REPLACE.
+	rec = do("PUT", getCopyObjectURL("", bucketName, "object-copy"), nil, map[string]string{
+		"X-Amz-Copy-Source":       "/" + bucketName + "/object",
+		"X-Amz-Tagging-Directive": "REPLACE",
+		"X-Amz-Tagging":           "project=beta",
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected CopyObject to succeed, got %d: %s", instanceType, rec.Code, rec.Body)
+	}
+	expectedTags = []objectTag{{"project", "beta"}}
+	if tags := getTags("object-copy"); !reflect.DeepEqual(tags, expectedTags) {
+		t.Errorf("%s: Expected tags %v, got %v", instanceType, expectedTags, tags)
+	}
+
+	// Unknown tagging directive.
+	rec = do("PUT", getCopyObjectURL("", bucketName, "object-copy"), nil, map[string]string{
+		"X-Amz-Copy-Source":       "/" + bucketName + "/object",
+		"X-Amz-Tagging-Directive": "Unknown",
+	})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("%s: Expected CopyObject with unknown tagging directive to fail with %d, got %d", instanceType, http.StatusBadRequest, rec.Code)
+	}
+
+	// Tags are removed.
+	rec = do("DELETE", "/"+bucketName+"/object?tagging", nil, nil)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("%s: Expected DeleteObjectTagging to succeed, got %d: %s", instanceType, rec.Code, rec.Body)
+	}
+	if tags := getTags("object"); len(tags) != 0 {
+		t.Errorf("%s: Expected no tags, got %v", instanceType, tags)
+	}
+
+	// Tags of a missing object.
+	rec = do("GET", "/"+bucketName+"/missing?tagging", nil, nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("%s: Expected GetObjectTagging of a missing object to fail with %d, got %d", instanceType, http.StatusNotFound, rec.Code)
+	}
+}
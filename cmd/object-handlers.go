@@ -17,8 +17,10 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/xml"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -26,6 +28,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	mux "github.com/gorilla/mux"
 )
@@ -97,12 +100,15 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 
 	// Lock the object before reading.
 	objectLock := globalNSMutex.NewNSLock(bucket, object)
-	objectLock.RLock()
+	if err := objectLock.GetRLock(globalLockTimeout); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
 	defer objectLock.RUnlock()
 
 	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
 	if err != nil {
-		errorIf(err, "Unable to fetch object info.")
+		requestErrorIf(r, err, "Unable to fetch object info.")
 		apiErr := toAPIErrorCode(err)
 		if apiErr == ErrNoSuchKey {
 			apiErr = errAllowableObjectNotFound(bucket, r)
@@ -111,10 +117,12 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// Get request range.
+	// Get request range, ignored if the object changed since the
+	// validator of If-Range was obtained, such that the whole object
+	// is returned instead.
 	var hrange *httpRange
 	rangeHeader := r.Header.Get("Range")
-	if rangeHeader != "" {
+	if rangeHeader != "" && isIfRangeMatch(r.Header.Get("If-Range"), objInfo) {
 		if hrange, err = parseRequestRange(rangeHeader, objInfo.Size); err != nil {
 			// Handle only errInvalidRange
 			// Ignore other parse error and treat it as regular Get request like Amazon S3.
@@ -124,7 +132,7 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 			}
 
 			// log the error.
-			errorIf(err, "Invalid request range")
+			requestErrorIf(r, err, "Invalid request range")
 		}
 	}
 
@@ -140,6 +148,15 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 		startOffset = hrange.offsetBegin
 		length = hrange.getLength()
 	}
+	// Objects encrypted with a customer key are only read with the
+	// key, the range is decrypted from its offset.
+	stream, s3Error := sseCustomerRequestHeaders.readStream(r.Header, objInfo.UserDefined, startOffset)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+	// Downloads are bounded by the egress bandwidth limit.
+	limitedW := globalEgressLimiter.writer(w)
 	// Indicates if any data was written to the http.ResponseWriter
 	dataWritten := false
 	// io.Writer type which keeps track if any data was written.
@@ -154,12 +171,12 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 
 			dataWritten = true
 		}
-		return w.Write(p)
+		return limitedW.Write(p)
 	})
 
 	// Reads the object at startOffset and writes to mw.
-	if err := objectAPI.GetObject(bucket, object, startOffset, length, writer); err != nil {
-		errorIf(err, "Unable to write to client.")
+	if err := objectAPI.GetObject(bucket, object, startOffset, length, newDecryptWriter(stream, writer)); err != nil {
+		requestErrorIf(r, err, "Unable to write to client.")
 		if !dataWritten {
 			// Error response only if no data has been written to client yet. i.e if
 			// partial data has already been written before an error
@@ -199,12 +216,15 @@ func (api objectAPIHandlers) HeadObjectHandler(w http.ResponseWriter, r *http.Re
 
 	// Lock the object before reading.
 	objectLock := globalNSMutex.NewNSLock(bucket, object)
-	objectLock.RLock()
+	if err := objectLock.GetRLock(globalLockTimeout); err != nil {
+		writeErrorResponseHeadersOnly(w, toAPIErrorCode(err))
+		return
+	}
 	defer objectLock.RUnlock()
 
 	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
 	if err != nil {
-		errorIf(err, "Unable to fetch object info.")
+		requestErrorIf(r, err, "Unable to fetch object info.")
 		apiErr := toAPIErrorCode(err)
 		if apiErr == ErrNoSuchKey {
 			apiErr = errAllowableObjectNotFound(bucket, r)
@@ -213,6 +233,12 @@ func (api objectAPIHandlers) HeadObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// Objects encrypted with a customer key are only read with the key.
+	if _, s3Error := sseCustomerRequestHeaders.readStream(r.Header, objInfo.UserDefined, 0); s3Error != ErrNone {
+		writeErrorResponseHeadersOnly(w, s3Error)
+		return
+	}
+
 	// Validate pre-conditions if any.
 	if checkPreconditions(w, r, objInfo) {
 		return
@@ -287,13 +313,22 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// Check if tagging directive is valid.
+	if !isTaggingDirectiveValid(r.Header) {
+		writeErrorResponse(w, ErrInvalidTaggingDirective, r.URL)
+		return
+	}
+
 	cpSrcDstSame := cpSrcPath == cpDestPath
 	// Hold write lock on destination since in both cases
 	// - if source and destination are same
 	// - if source and destination are different
 	// it is the sole mutating state.
 	objectDWLock := globalNSMutex.NewNSLock(dstBucket, dstObject)
-	objectDWLock.Lock()
+	if err := objectDWLock.GetLock(globalLockTimeout); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
 	defer objectDWLock.Unlock()
 
 	// if source and destination are different, we have to hold
@@ -303,14 +338,17 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		// Hold read locks on source object only if we are
 		// going to read data from source object.
 		objectSRLock := globalNSMutex.NewNSLock(srcBucket, srcObject)
-		objectSRLock.RLock()
+		if err := objectSRLock.GetRLock(globalLockTimeout); err != nil {
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
 		defer objectSRLock.RUnlock()
 
 	}
 
 	objInfo, err := objectAPI.GetObjectInfo(srcBucket, srcObject)
 	if err != nil {
-		errorIf(err, "Unable to fetch object info.")
+		requestErrorIf(r, err, "Unable to fetch object info.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -320,6 +358,31 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// Sources encrypted with a customer key are only read with the key
+	// of x-amz-copy-source-server-side-encryption-customer-key, copies
+	// are encrypted with the key of x-amz-server-side-encryption-customer-key.
+	srcKey, s3Error := sseCustomerCopySourceHeaders.parseKey(r.Header)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+	srcStream, s3Error := sseCustomerCopySourceHeaders.readStream(r.Header, objInfo.UserDefined, 0)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+	dstKey, s3Error := sseCustomerRequestHeaders.parseKey(r.Header)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+	srcCustomerMeta := make(map[string]string)
+	for _, key := range sseCustomerMetaKeys {
+		if value, ok := objInfo.UserDefined[key]; ok {
+			srcCustomerMeta[key] = value
+		}
+	}
+
 	/// maximum Upload size for object in a single CopyObject operation.
 	if isMaxObjectSize(objInfo.Size) {
 		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
@@ -332,28 +395,98 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 	// as multipart which doesn't have a standard md5sum, we just let
 	// CopyObject calculate a new one.
 	delete(defaultMeta, "md5Sum")
+	// Copies are modified at the time of copy.
+	delete(defaultMeta, modTimeMetaKey)
 
 	newMetadata := getCpObjMetadataFromHeader(r.Header, defaultMeta)
-	// Check if x-amz-metadata-directive was not set to REPLACE and source,
-	// desination are same objects.
-	if !isMetadataReplace(r.Header) && cpSrcDstSame {
+	if isMaxUserMetadataSize(newMetadata) {
+		writeErrorResponse(w, ErrMetadataTooLarge, r.URL)
+		return
+	}
+	// Tags of the source are copied independently of its metadata,
+	// unless x-amz-tagging-directive is REPLACE.
+	if isTaggingReplace(r.Header) {
+		if s3Error := setObjectTagsFromHeader(r.Header, newMetadata); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+	} else if srcTags, ok := objInfo.UserDefined[objectTaggingMetaKey]; ok {
+		newMetadata[objectTaggingMetaKey] = srcTags
+	}
+	// As in S3 the storage class is not copied, copies are of the
+	// STANDARD class unless x-amz-storage-class is set.
+	if s3Error := setStorageClassFromHeader(r.Header, newMetadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+	// As in S3 the encryption is not copied, copies are encrypted if
+	// x-amz-server-side-encryption is set or their bucket is encrypted.
+	if s3Error := setServerSideEncryptionFromHeader(r.Header, newMetadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+	deleteSSECustomerKey(newMetadata)
+	if dstKey != nil && newMetadata[amzServerSideEncryptionHeader] != "" {
+		writeErrorResponse(w, ErrIncompatibleEncryptionMethod, r.URL)
+		return
+	}
+	setObjectRetention(dstBucket, newMetadata)
+	// Check if neither x-amz-metadata-directive nor x-amz-tagging-directive
+	// was set to REPLACE nor the storage class or customer key changed
+	// and source, desination are same objects.
+	_, scSet := r.Header[amzStorageClassHeader]
+	sameKey := bytes.Equal(srcKey, dstKey)
+	if !isMetadataReplace(r.Header) && !isTaggingReplace(r.Header) && !scSet && sameKey && cpSrcDstSame {
 		// If x-amz-metadata-directive is not set to REPLACE then we need
 		// to error out if source and destination are same.
 		writeErrorResponse(w, ErrInvalidCopyDest, r.URL)
 		return
 	}
 
+	// Retained objects cannot be overwritten.
+	if err = checkObjectRetention(objectAPI, dstBucket, dstObject); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Copy must fit in the quota of the destination bucket.
+	quota, err := checkBucketQuota(objectAPI, dstBucket, dstObject, objInfo.Size)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
 	// Copy source object to destination, if source and destination
-	// object is same then only metadata is updated.
-	objInfo, err = objectAPI.CopyObject(srcBucket, srcObject, dstBucket, dstObject, newMetadata)
+	// object is same then only metadata is updated. Data encrypted with
+	// a customer key is streamed through the server unless the key of
+	// the object is kept. Large objects are copied part by part, unless
+	// the copy is encrypted as encrypted uploads are not supported.
+	switch {
+	case srcStream == nil && dstKey == nil && !cpSrcDstSame && isMultipartCopy(objInfo.Size) &&
+		!isEncryptionRequested(dstBucket, newMetadata):
+		objInfo, err = copyObjectParts(objectAPI, srcBucket, srcObject, objInfo.Size, dstBucket, dstObject, newMetadata)
+	case srcStream == nil && dstKey == nil:
+		objInfo, err = objectAPI.CopyObject(srcBucket, srcObject, dstBucket, dstObject, newMetadata)
+	case cpSrcDstSame && sameKey:
+		for key, value := range srcCustomerMeta {
+			newMetadata[key] = value
+		}
+		objInfo, err = objectAPI.CopyObject(srcBucket, srcObject, dstBucket, dstObject, newMetadata)
+	default:
+		objInfo, err = copyObjectWithCustomerKeys(objectAPI, srcBucket, srcObject, srcStream, objInfo.Size,
+			dstBucket, dstObject, dstKey, newMetadata)
+	}
 	if err != nil {
+		quota.cancel()
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
+	quota.commit(objInfo.Size)
 
 	md5Sum := objInfo.MD5Sum
 	response := generateCopyObjectResponse(md5Sum, objInfo.ModTime)
 	encodedSuccessResponse := encodeResponse(response)
+	setServerSideEncryptionHeader(w, objInfo)
 
 	// Write success response.
 	writeSuccessResponseXML(w, encodedSuccessResponse)
@@ -389,12 +522,72 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 	bucket := vars["bucket"]
 	object := vars["object"]
 
+	// Lock the object.
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	if err := objectLock.GetLock(globalLockTimeout); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	defer objectLock.Unlock()
+
+	objInfo, ok := putObject(objectAPI, w, r, bucket, object)
+	if !ok {
+		return
+	}
+	w.Header().Set("ETag", "\""+objInfo.MD5Sum+"\"")
+	setServerSideEncryptionHeader(w, objInfo)
+	writeSuccessResponseHeadersOnly(w)
+
+	// Notify object created event.
+	eventNotify(eventData{
+		Type:    ObjectCreatedPut,
+		Bucket:  bucket,
+		ObjInfo: objInfo,
+		ReqParams: map[string]string{
+			"sourceIPAddress": r.RemoteAddr,
+		},
+	})
+}
+
+// checkPutObjectAuth - authenticates an upload to bucket the way
+// putObject does without reading the payload.
+func checkPutObjectAuth(r *http.Request, bucket string) (s3Error APIErrorCode) {
+	switch getRequestAuthType(r) {
+	default:
+		// For all unknown auth types return error.
+		return ErrAccessDenied
+	case authTypeAnonymous:
+		return enforceBucketPolicy(bucket, "s3:PutObject", pathStyleURL(r))
+	case authTypeClientCert:
+		// Client certificate is already verified by the TLS handshake.
+		return ErrNone
+	case authTypeSignedV2, authTypePresignedV2:
+		s3Error = isReqAuthenticatedV2(r)
+	case authTypeStreamingSigned, authTypePresigned, authTypeSigned:
+		// Seed signature of streaming uploads signs the headers just
+		// like regular V4 signatures.
+		s3Error = reqSignatureV4Verify(r)
+	}
+	if s3Error != ErrNone {
+		requestErrorIf(r, errSignatureMismatch, dumpRequest(r))
+	}
+	return s3Error
+}
+
+// putObject - authenticates the request and creates an object from
+// the request body, the object lock is expected to be held by the
+// caller. Error responses are written to the client, returns false
+// if the object was not created.
+func putObject(objectAPI ObjectLayer, w http.ResponseWriter, r *http.Request, bucket, object string) (objInfo ObjectInfo, ok bool) {
+	// Uploads are bounded by the ingress bandwidth limit.
+	r.Body = globalIngressLimiter.reader(r.Body)
+
 	// Get Content-Md5 sent by client and verify if valid
 	md5Bytes, err := checkValidMD5(r.Header.Get("Content-Md5"))
 	if err != nil {
-		errorIf(err, "Unable to validate content-md5 format.")
+		requestErrorIf(r, err, "Unable to validate content-md5 format.")
 		writeErrorResponse(w, ErrInvalidDigest, r.URL)
-		return
+		return objInfo, false
 	}
 
 	/// if Content-Length is unknown/missing, deny the request
@@ -404,94 +597,218 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		sizeStr := r.Header.Get("x-amz-decoded-content-length")
 		size, err = strconv.ParseInt(sizeStr, 10, 64)
 		if err != nil {
-			errorIf(err, "Unable to parse `x-amz-decoded-content-length` into its integer value", sizeStr)
+			requestErrorIf(r, err, "Unable to parse `x-amz-decoded-content-length` into its integer value", sizeStr)
 			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
-			return
+			return objInfo, false
 		}
 	}
-	if size == -1 && !contains(r.TransferEncoding, "chunked") {
+	// HTTP/2 requests of unknown length have no transfer encoding,
+	// their body is framed like a chunked HTTP/1.1 body.
+	if size == -1 && !contains(r.TransferEncoding, "chunked") && r.ProtoMajor != 2 {
 		writeErrorResponse(w, ErrMissingContentLength, r.URL)
-		return
+		return objInfo, false
 	}
 
 	/// maximum Upload size for objects in a single operation
 	if isMaxObjectSize(size) {
 		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
-		return
+		return objInfo, false
 	}
 
 	// Extract metadata to be saved from incoming HTTP header.
 	metadata := extractMetadataFromHeader(r.Header)
+	if isMaxUserMetadataSize(metadata) {
+		writeErrorResponse(w, ErrMetadataTooLarge, r.URL)
+		return objInfo, false
+	}
+	// Tags are optionally set along with the object.
+	if s3Error := setObjectTagsFromHeader(r.Header, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return objInfo, false
+	}
+	if s3Error := setStorageClassFromHeader(r.Header, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return objInfo, false
+	}
+	if s3Error := setServerSideEncryptionFromHeader(r.Header, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return objInfo, false
+	}
+	// Objects are encrypted with the customer key of the request, if set.
+	customerKey, s3Error := sseCustomerRequestHeaders.parseKey(r.Header)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return objInfo, false
+	}
+	if customerKey != nil && metadata[amzServerSideEncryptionHeader] != "" {
+		writeErrorResponse(w, ErrIncompatibleEncryptionMethod, r.URL)
+		return objInfo, false
+	}
 	// Make sure we hex encode md5sum here.
 	metadata["md5Sum"] = hex.EncodeToString(md5Bytes)
+	// Modification time is optionally set by the client.
+	if s3Error := extractModTimeFromHeader(r.Header, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return objInfo, false
+	}
+	setObjectRetention(bucket, metadata)
 
 	sha256sum := ""
 
-	// Lock the object.
-	objectLock := globalNSMutex.NewNSLock(bucket, object)
-	objectLock.Lock()
-	defer objectLock.Unlock()
+	// Objects are only created in existing buckets within their quota
+	// and never overwrite retained objects, checked once the request
+	// is authenticated and before the payload is read. Clients sending
+	// Expect: 100-continue are only asked for the payload of uploads
+	// which are not rejected upfront.
+	var quota quotaReservation
+	putObjectWithinQuota := func(reader io.Reader) (ObjectInfo, error) {
+		if _, err = globalBucketCache.getBucketInfo(objectAPI, bucket); err != nil {
+			return ObjectInfo{}, err
+		}
+		if err = checkObjectRetention(objectAPI, bucket, object); err != nil {
+			return ObjectInfo{}, err
+		}
+		if quota, err = checkBucketQuota(objectAPI, bucket, object, size); err != nil {
+			return ObjectInfo{}, err
+		}
+		if customerKey != nil {
+			if reader, err = newSSECustomerReader(customerKey, reader, size, metadata, sha256sum); err != nil {
+				return ObjectInfo{}, traceError(err)
+			}
+			return objectAPI.PutObject(bucket, object, size, reader, metadata, "")
+		}
+		return objectAPI.PutObject(bucket, object, size, reader, metadata, sha256sum)
+	}
 
-	var objInfo ObjectInfo
 	switch rAuthType {
 	default:
 		// For all unknown auth types return error.
 		writeErrorResponse(w, ErrAccessDenied, r.URL)
-		return
+		return objInfo, false
 	case authTypeAnonymous:
 		// http://docs.aws.amazon.com/AmazonS3/latest/dev/using-with-s3-actions.html
-		if s3Error := enforceBucketPolicy(bucket, "s3:PutObject", r.URL); s3Error != ErrNone {
+		if s3Error := enforceBucketPolicy(bucket, "s3:PutObject", pathStyleURL(r)); s3Error != ErrNone {
 			writeErrorResponse(w, s3Error, r.URL)
-			return
+			return objInfo, false
 		}
 		// Create anonymous object.
-		objInfo, err = objectAPI.PutObject(bucket, object, size, r.Body, metadata, sha256sum)
+		objInfo, err = putObjectWithinQuota(r.Body)
+	case authTypeClientCert:
+		// Client certificate is already verified by the TLS handshake.
+		objInfo, err = putObjectWithinQuota(r.Body)
 	case authTypeStreamingSigned:
 		// Initialize stream signature verifier.
 		reader, s3Error := newSignV4ChunkedReader(r)
 		if s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			requestErrorIf(r, errSignatureMismatch, dumpRequest(r))
 			writeErrorResponse(w, s3Error, r.URL)
-			return
+			return objInfo, false
 		}
-		objInfo, err = objectAPI.PutObject(bucket, object, size, reader, metadata, sha256sum)
+		objInfo, err = putObjectWithinQuota(reader)
 	case authTypeSignedV2, authTypePresignedV2:
 		s3Error := isReqAuthenticatedV2(r)
 		if s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			requestErrorIf(r, errSignatureMismatch, dumpRequest(r))
 			writeErrorResponse(w, s3Error, r.URL)
-			return
+			return objInfo, false
 		}
-		objInfo, err = objectAPI.PutObject(bucket, object, size, r.Body, metadata, sha256sum)
+		objInfo, err = putObjectWithinQuota(r.Body)
 	case authTypePresigned, authTypeSigned:
 		if s3Error := reqSignatureV4Verify(r); s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			requestErrorIf(r, errSignatureMismatch, dumpRequest(r))
 			writeErrorResponse(w, s3Error, r.URL)
-			return
+			return objInfo, false
 		}
 		if !skipContentSha256Cksum(r) {
 			sha256sum = r.Header.Get("X-Amz-Content-Sha256")
 		}
 		// Create object.
-		objInfo, err = objectAPI.PutObject(bucket, object, size, r.Body, metadata, sha256sum)
+		objInfo, err = putObjectWithinQuota(r.Body)
 	}
 	if err != nil {
-		errorIf(err, "Unable to create an object.")
+		quota.cancel()
+		requestErrorIf(r, err, "Unable to create an object.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return objInfo, false
+	}
+	quota.commit(objInfo.Size)
+	return objInfo, true
+}
+
+// Maximum attempts to generate a key not used by an existing object.
+const maxAssignKeyAttempts = 3
+
+// PutObjectAssignKeyHandler - creates an object under a key generated
+// by the server.
+// ----------
+// This is a minio extension and is not part of the S3 API. Key is a
+// random UUID, optionally with the requested prefix, and is never the
+// key of an existing object.
+//
+// POST /bucket?assign-key[&prefix=prefix]
+func (api objectAPIHandlers) PutObjectAssignKeyHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
 		return
 	}
-	w.Header().Set("ETag", "\""+objInfo.MD5Sum+"\"")
-	writeSuccessResponseHeadersOnly(w)
 
-	// Notify object created event.
-	eventNotify(eventData{
-		Type:    ObjectCreatedPut,
-		Bucket:  bucket,
-		ObjInfo: objInfo,
-		ReqParams: map[string]string{
-			"sourceIPAddress": r.RemoteAddr,
-		},
-	})
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	prefix := r.URL.Query().Get("prefix")
+
+	// Authenticate before looking for a free key, the payload is
+	// verified by putObject while the object is created.
+	if s3Error := checkPutObjectAuth(r, bucket); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	for i := 0; i < maxAssignKeyAttempts; i++ {
+		object := prefix + mustGetUUID()
+
+		objectLock := globalNSMutex.NewNSLock(bucket, object)
+		if err := objectLock.GetLock(globalLockTimeout); err != nil {
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+		_, err := objectAPI.GetObjectInfo(bucket, object)
+		if err == nil {
+			// Key is already taken, try another one.
+			objectLock.Unlock()
+			continue
+		}
+		if !isErrObjectNotFound(errorCause(err)) {
+			objectLock.Unlock()
+			requestErrorIf(r, err, "Unable to check for an existing object.")
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+
+		objInfo, ok := putObject(objectAPI, w, r, bucket, object)
+		objectLock.Unlock()
+		if !ok {
+			return
+		}
+
+		response := generateAssignKeyResponse(bucket, object, objInfo.MD5Sum)
+		encodedSuccessResponse := encodeResponse(response)
+		w.Header().Set("ETag", "\""+objInfo.MD5Sum+"\"")
+		setServerSideEncryptionHeader(w, objInfo)
+		writeSuccessResponseXML(w, encodedSuccessResponse)
+
+		// Notify object created event.
+		eventNotify(eventData{
+			Type:    ObjectCreatedPut,
+			Bucket:  bucket,
+			ObjInfo: objInfo,
+			ReqParams: map[string]string{
+				"sourceIPAddress": r.RemoteAddr,
+			},
+		})
+		return
+	}
+	writeErrorResponse(w, ErrInternalError, r.URL)
 }
 
 /// Multipart objectAPIHandlers
@@ -516,10 +833,34 @@ func (api objectAPIHandlers) NewMultipartUploadHandler(w http.ResponseWriter, r
 
 	// Extract metadata that needs to be saved.
 	metadata := extractMetadataFromHeader(r.Header)
+	if isMaxUserMetadataSize(metadata) {
+		writeErrorResponse(w, ErrMetadataTooLarge, r.URL)
+		return
+	}
+	if s3Error := setStorageClassFromHeader(r.Header, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+	if s3Error := setServerSideEncryptionFromHeader(r.Header, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+	// Objects are not encrypted with customer keys with multipart uploads.
+	customerKey, s3Error := sseCustomerRequestHeaders.parseKey(r.Header)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+	if customerKey != nil {
+		writeErrorResponse(w, ErrMultipartEncryptionNotSupported, r.URL)
+		return
+	}
+	// Retention of the object starts once the upload is initiated.
+	setObjectRetention(bucket, metadata)
 
 	uploadID, err := objectAPI.NewMultipartUpload(bucket, object, metadata)
 	if err != nil {
-		errorIf(err, "Unable to initiate new multipart upload id.")
+		requestErrorIf(r, err, "Unable to initiate new multipart upload id.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -531,6 +872,128 @@ func (api objectAPIHandlers) NewMultipartUploadHandler(w http.ResponseWriter, r
 	writeSuccessResponseXML(w, encodedSuccessResponse)
 }
 
+// CopyObjectPartHandler - uploads a part by copying data from an existing object as data source.
+// ----------
+// The part is read from a range of the source object, given by
+// x-amz-copy-source-range, or the whole object, and written to the
+// multipart upload by the server without passing through the client.
+func (api objectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	dstBucket := vars["bucket"]
+	dstObject := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, dstBucket, "s3:PutObject", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Copy source path.
+	cpSrcPath, err := url.QueryUnescape(r.Header.Get("X-Amz-Copy-Source"))
+	if err != nil {
+		// Save unescaped string as is.
+		cpSrcPath = r.Header.Get("X-Amz-Copy-Source")
+	}
+
+	srcBucket, srcObject := path2BucketAndObject(cpSrcPath)
+	// If source object is empty or bucket is empty, reply back invalid copy source.
+	if srcObject == "" || srcBucket == "" {
+		writeErrorResponse(w, ErrInvalidCopySource, r.URL)
+		return
+	}
+
+	uploadID := r.URL.Query().Get("uploadId")
+	partIDString := r.URL.Query().Get("partNumber")
+
+	partID, err := strconv.Atoi(partIDString)
+	if err != nil {
+		writeErrorResponse(w, ErrInvalidPart, r.URL)
+		return
+	}
+
+	// check partID with maximum part ID for multipart objects
+	if isMaxPartID(partID) {
+		writeErrorResponse(w, ErrInvalidMaxParts, r.URL)
+		return
+	}
+
+	// Hold read lock on source object while its data is copied.
+	objectSRLock := globalNSMutex.NewNSLock(srcBucket, srcObject)
+	if err := objectSRLock.GetRLock(globalLockTimeout); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	defer objectSRLock.RUnlock()
+
+	objInfo, err := objectAPI.GetObjectInfo(srcBucket, srcObject)
+	if err != nil {
+		requestErrorIf(r, err, "Unable to fetch object info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Verify before x-amz-copy-source preconditions before continuing with CopyObjectPart.
+	if checkCopyObjectPreconditions(w, r, objInfo) {
+		return
+	}
+
+	// Get the range to copy, the whole object by default.
+	var startOffset int64
+	length := objInfo.Size
+	if rangeHeader := r.Header.Get("x-amz-copy-source-range"); rangeHeader != "" {
+		hrange, rerr := parseCopyPartRange(rangeHeader, objInfo.Size)
+		if rerr != nil {
+			requestErrorIf(r, rerr, "Unable to parse range %s.", rangeHeader)
+			if rerr == errInvalidRange {
+				writeErrorResponse(w, ErrInvalidCopyPartRangeSource, r.URL)
+			} else {
+				writeErrorResponse(w, ErrInvalidCopyPartRange, r.URL)
+			}
+			return
+		}
+		startOffset, length = hrange.offsetBegin, hrange.getLength()
+	}
+
+	/// maximum Upload size for multipart objects in a single operation
+	if isMaxPartSize(length) {
+		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
+		return
+	}
+
+	// Sources encrypted with a customer key are decrypted with the key
+	// of x-amz-copy-source-server-side-encryption-customer-key.
+	srcStream, s3Error := sseCustomerCopySourceHeaders.readStream(r.Header, objInfo.UserDefined, startOffset)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Stream the range of the source object into the part, the read
+	// end is closed if the part fails early, stopping GetObject.
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		pipeWriter.CloseWithError(objectAPI.GetObject(srcBucket, srcObject, startOffset, length, newDecryptWriter(srcStream, pipeWriter)))
+	}()
+	partMD5, err := objectAPI.PutObjectPart(dstBucket, dstObject, uploadID, partID, length, pipeReader, "", "")
+	pipeReader.CloseWithError(err)
+	if err != nil {
+		requestErrorIf(r, err, "Unable to copy object part.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	response := generateCopyObjectPartResponse(partMD5, time.Now().UTC())
+	encodedSuccessResponse := encodeResponse(response)
+
+	// Write success response.
+	writeSuccessResponseXML(w, encodedSuccessResponse)
+}
+
 // PutObjectPartHandler - Upload part
 func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -543,6 +1006,9 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		return
 	}
 
+	// Uploads are bounded by the ingress bandwidth limit.
+	r.Body = globalIngressLimiter.reader(r.Body)
+
 	// get Content-Md5 sent by client and verify if valid
 	md5Bytes, err := checkValidMD5(r.Header.Get("Content-Md5"))
 	if err != nil {
@@ -559,7 +1025,7 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		sizeStr := r.Header.Get("x-amz-decoded-content-length")
 		size, err = strconv.ParseInt(sizeStr, 10, 64)
 		if err != nil {
-			errorIf(err, "Unable to parse `x-amz-decoded-content-length` into its integer value", sizeStr)
+			requestErrorIf(r, err, "Unable to parse `x-amz-decoded-content-length` into its integer value", sizeStr)
 			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 			return
 		}
@@ -570,7 +1036,7 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 	}
 
 	/// maximum Upload size for multipart objects in a single operation
-	if isMaxObjectSize(size) {
+	if isMaxPartSize(size) {
 		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
 		return
 	}
@@ -590,6 +1056,10 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		return
 	}
 
+	// Stale uploads are not aborted while receiving parts.
+	globalActiveUploads.add(uploadID)
+	defer globalActiveUploads.done(uploadID)
+
 	var partMD5 string
 	incomingMD5 := hex.EncodeToString(md5Bytes)
 	sha256sum := ""
@@ -600,17 +1070,20 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		return
 	case authTypeAnonymous:
 		// http://docs.aws.amazon.com/AmazonS3/latest/dev/mpuAndPermissions.html
-		if s3Error := enforceBucketPolicy(bucket, "s3:PutObject", r.URL); s3Error != ErrNone {
+		if s3Error := enforceBucketPolicy(bucket, "s3:PutObject", pathStyleURL(r)); s3Error != ErrNone {
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
 		// No need to verify signature, anonymous request access is already allowed.
 		partMD5, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, r.Body, incomingMD5, sha256sum)
+	case authTypeClientCert:
+		// Client certificate is already verified by the TLS handshake.
+		partMD5, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, r.Body, incomingMD5, sha256sum)
 	case authTypeStreamingSigned:
 		// Initialize stream signature verifier.
 		reader, s3Error := newSignV4ChunkedReader(r)
 		if s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			requestErrorIf(r, errSignatureMismatch, dumpRequest(r))
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
@@ -618,14 +1091,14 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 	case authTypeSignedV2, authTypePresignedV2:
 		s3Error := isReqAuthenticatedV2(r)
 		if s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			requestErrorIf(r, errSignatureMismatch, dumpRequest(r))
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
 		partMD5, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, r.Body, incomingMD5, sha256sum)
 	case authTypePresigned, authTypeSigned:
 		if s3Error := reqSignatureV4Verify(r); s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			requestErrorIf(r, errSignatureMismatch, dumpRequest(r))
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
@@ -636,7 +1109,7 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		partMD5, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, r.Body, incomingMD5, sha256sum)
 	}
 	if err != nil {
-		errorIf(err, "Unable to create object part.")
+		requestErrorIf(r, err, "Unable to create object part.")
 		// Verify if the underlying error is signature mismatch.
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
@@ -667,7 +1140,7 @@ func (api objectAPIHandlers) AbortMultipartUploadHandler(w http.ResponseWriter,
 
 	uploadID, _, _, _ := getObjectResources(r.URL.Query())
 	if err := objectAPI.AbortMultipartUpload(bucket, object, uploadID); err != nil {
-		errorIf(err, "Unable to abort multipart upload.")
+		requestErrorIf(r, err, "Unable to abort multipart upload.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -702,7 +1175,7 @@ func (api objectAPIHandlers) ListObjectPartsHandler(w http.ResponseWriter, r *ht
 	}
 	listPartsInfo, err := objectAPI.ListObjectParts(bucket, object, uploadID, partNumberMarker, maxParts)
 	if err != nil {
-		errorIf(err, "Unable to list uploaded parts.")
+		requestErrorIf(r, err, "Unable to list uploaded parts.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -736,13 +1209,13 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 	var md5Sum string
 	completeMultipartBytes, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		errorIf(err, "Unable to complete multipart upload.")
+		requestErrorIf(r, err, "Unable to complete multipart upload.")
 		writeErrorResponse(w, ErrInternalError, r.URL)
 		return
 	}
 	complMultipartUpload := &completeMultipartUpload{}
 	if err = xml.Unmarshal(completeMultipartBytes, complMultipartUpload); err != nil {
-		errorIf(err, "Unable to parse complete multipart upload XML.")
+		requestErrorIf(r, err, "Unable to parse complete multipart upload XML.")
 		writeErrorResponse(w, ErrMalformedXML, r.URL)
 		return
 	}
@@ -754,6 +1227,12 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 		writeErrorResponse(w, ErrInvalidPartOrder, r.URL)
 		return
 	}
+	// Parts beyond the maximum allowed could have never been uploaded.
+	if len(complMultipartUpload.Parts) > globalMaxPartID ||
+		isMaxPartID(complMultipartUpload.Parts[len(complMultipartUpload.Parts)-1].PartNumber) {
+		writeErrorResponse(w, ErrInvalidPart, r.URL)
+		return
+	}
 
 	// Complete parts.
 	var completeParts []completePart
@@ -765,13 +1244,36 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 
 	// Hold write lock on the object.
 	destLock := globalNSMutex.NewNSLock(bucket, object)
-	destLock.Lock()
+	if err = destLock.GetLock(globalLockTimeout); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
 	defer destLock.Unlock()
 
+	// Retained objects cannot be overwritten.
+	if err = checkObjectRetention(objectAPI, bucket, object); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Completed object must fit in the quota of the bucket.
+	var size int64
+	quota := quotaReservation{bucket: bucket}
+	if getBucketQuota(bucket) != 0 {
+		if size, err = getCompletedObjectSize(objectAPI, bucket, object, uploadID, completeParts); err == nil {
+			quota, err = checkBucketQuota(objectAPI, bucket, object, size)
+		}
+		if err != nil {
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+	}
+
 	md5Sum, err = objectAPI.CompleteMultipartUpload(bucket, object, uploadID, completeParts)
 	if err != nil {
+		quota.cancel()
 		err = errorCause(err)
-		errorIf(err, "Unable to complete multipart upload.")
+		requestErrorIf(r, err, "Unable to complete multipart upload.")
 		switch oErr := err.(type) {
 		case PartTooSmall:
 			// Write part too small error.
@@ -783,13 +1285,15 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 		return
 	}
 
+	quota.commit(size)
+
 	// Get object location.
 	location := getLocation(r)
 	// Generate complete multipart response.
 	response := generateCompleteMultpartUploadResponse(bucket, object, location, md5Sum)
 	encodedSuccessResponse := encodeResponse(response)
 	if err != nil {
-		errorIf(err, "Unable to parse CompleteMultipartUpload response")
+		requestErrorIf(r, err, "Unable to parse CompleteMultipartUpload response")
 		writeErrorResponse(w, ErrInternalError, r.URL)
 		return
 	}
@@ -803,7 +1307,7 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 	// Fetch object info for notifications.
 	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
 	if err != nil {
-		errorIf(err, "Unable to fetch object info for \"%s\"", path.Join(bucket, object))
+		requestErrorIf(r, err, "Unable to fetch object info for \"%s\"", path.Join(bucket, object))
 		return
 	}
 
@@ -838,9 +1342,20 @@ func (api objectAPIHandlers) DeleteObjectHandler(w http.ResponseWriter, r *http.
 	}
 
 	objectLock := globalNSMutex.NewNSLock(bucket, object)
-	objectLock.Lock()
+	if err := objectLock.GetLock(globalLockTimeout); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
 	defer objectLock.Unlock()
 
+	// Retained objects cannot be deleted.
+	if err := checkObjectRetention(objectAPI, bucket, object); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	size := getTrackedObjectSize(objectAPI, bucket, object)
+
 	/// http://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectDELETE.html
 	/// Ignore delete object errors, since we are suppposed to reply
 	/// only 204.
@@ -848,6 +1363,7 @@ func (api objectAPIHandlers) DeleteObjectHandler(w http.ResponseWriter, r *http.
 		writeSuccessNoContent(w)
 		return
 	}
+	globalBucketUsage.add(bucket, -size)
 	writeSuccessNoContent(w)
 
 	// Notify object deleted event.
@@ -862,3 +1378,59 @@ func (api objectAPIHandlers) DeleteObjectHandler(w http.ResponseWriter, r *http.
 		},
 	})
 }
+
+// TruncateObjectHandler - truncates an object to the requested length.
+// ----------
+// This is a minio extension and is not part of the S3 API, only the
+// tail of an object can be removed.
+//
+// POST /bucket/object?truncate=length
+func (api objectAPIHandlers) TruncateObjectHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:PutObject", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	length, err := strconv.ParseInt(vars["length"], 10, 64)
+	if err != nil || length < 0 {
+		writeErrorResponse(w, ErrInvalidTruncateLength, r.URL)
+		return
+	}
+
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	if err := objectLock.GetLock(globalLockTimeout); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	defer objectLock.Unlock()
+
+	// Retained objects cannot be modified.
+	if err = checkObjectRetention(objectAPI, bucket, object); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	size := getTrackedObjectSize(objectAPI, bucket, object)
+	objInfo, err := objectAPI.TruncateObject(bucket, object, length)
+	if err != nil {
+		requestErrorIf(r, err, "Unable to truncate object.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	if size != 0 {
+		globalBucketUsage.add(bucket, objInfo.Size-size)
+	}
+
+	w.Header().Set("ETag", "\""+objInfo.MD5Sum+"\"")
+	writeSuccessResponseHeadersOnly(w)
+}
@@ -18,16 +18,22 @@ package cmd
 
 import (
 	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/xml"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/http/httptrace"
 	"net/url"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	humanize "github.com/dustin/go-humanize"
 )
@@ -219,6 +225,9 @@ func testAPIGetObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 	}{
 		// case - 1.
 		{bucketName, objectName, int64(len(bytesData[0].byteData)), bytesData[0].byteData, make(map[string]string)},
+		// case - 2.
+		// Empty object.
+		{bucketName, "empty-object", 0, []byte{}, make(map[string]string)},
 	}
 	sha256sum := ""
 	// iterate through the above set of inputs and upload the object.
@@ -241,6 +250,8 @@ func testAPIGetObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 		// expected output.
 		expectedContent    []byte // expected response body.
 		expectedRespStatus int    // expected response status body.
+		// expected Content-Range header, verified only if set.
+		expectedContentRange string
 	}{
 		// Test case - 1.
 		// Fetching the entire object and validating its contents.
@@ -316,6 +327,56 @@ func testAPIGetObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 			expectedContent:    encodeResponse(getAPIErrorResponse(getAPIError(ErrInvalidAccessKeyID), getGetObjectURL("", bucketName, objectName))),
 			expectedRespStatus: http.StatusForbidden,
 		},
+		// Test case - 7.
+		// Probing the first byte of the object.
+		{
+			bucketName: bucketName,
+			objectName: objectName,
+			byteRange:  "bytes=0-0",
+			accessKey:  credentials.AccessKey,
+			secretKey:  credentials.SecretKey,
+
+			expectedContent:      bytesData[0].byteData[:1],
+			expectedRespStatus:   http.StatusPartialContent,
+			expectedContentRange: fmt.Sprintf("bytes 0-0/%d", len(bytesData[0].byteData)),
+		},
+		// Test case - 8.
+		// Fetching the last byte of the object.
+		{
+			bucketName: bucketName,
+			objectName: objectName,
+			byteRange:  "bytes=-1",
+			accessKey:  credentials.AccessKey,
+			secretKey:  credentials.SecretKey,
+
+			expectedContent:      bytesData[0].byteData[len(bytesData[0].byteData)-1:],
+			expectedRespStatus:   http.StatusPartialContent,
+			expectedContentRange: fmt.Sprintf("bytes %d-%d/%d", len(bytesData[0].byteData)-1, len(bytesData[0].byteData)-1, len(bytesData[0].byteData)),
+		},
+		// Test case - 9.
+		// Probing the first byte of an empty object.
+		{
+			bucketName: bucketName,
+			objectName: "empty-object",
+			byteRange:  "bytes=0-0",
+			accessKey:  credentials.AccessKey,
+			secretKey:  credentials.SecretKey,
+
+			expectedContent:    encodeResponse(getAPIErrorResponse(getAPIError(ErrInvalidRange), getGetObjectURL("", bucketName, "empty-object"))),
+			expectedRespStatus: http.StatusRequestedRangeNotSatisfiable,
+		},
+		// Test case - 10.
+		// Fetching the last byte of an empty object.
+		{
+			bucketName: bucketName,
+			objectName: "empty-object",
+			byteRange:  "bytes=-1",
+			accessKey:  credentials.AccessKey,
+			secretKey:  credentials.SecretKey,
+
+			expectedContent:    encodeResponse(getAPIErrorResponse(getAPIError(ErrInvalidRange), getGetObjectURL("", bucketName, "empty-object"))),
+			expectedRespStatus: http.StatusRequestedRangeNotSatisfiable,
+		},
 	}
 
 	// Iterating over the cases, fetching the object validating the response.
@@ -348,6 +409,14 @@ func testAPIGetObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 		if !bytes.Equal(testCase.expectedContent, actualContent) {
 			t.Errorf("Test %d: %s: Object content differs from expected value.: %s", i+1, instanceType, string(actualContent))
 		}
+		if testCase.expectedContentRange != "" {
+			if contentRange := rec.Header().Get("Content-Range"); contentRange != testCase.expectedContentRange {
+				t.Errorf("Test %d: %s: Expected Content-Range %q, got %q", i+1, instanceType, testCase.expectedContentRange, contentRange)
+			}
+			if contentLength := rec.Header().Get("Content-Length"); contentLength != strconv.Itoa(len(testCase.expectedContent)) {
+				t.Errorf("Test %d: %s: Expected Content-Length %d, got %s", i+1, instanceType, len(testCase.expectedContent), contentLength)
+			}
+		}
 
 		// Verify response of the V2 signed HTTP request.
 		// initialize HTTP NewRecorder, this records any mutations to response writer inside the handler.
@@ -413,6 +482,78 @@ func testAPIGetObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 	ExecObjectLayerAPINilTest(t, nilBucket, nilObject, instanceType, apiRouter, nilReq)
 }
 
+// Wrapper for calling GetObject If-Range tests for both XL multiple disks and single node setup.
+func TestAPIGetObjectIfRange(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPIGetObjectIfRange, []string{"GetObject"})
+}
+
+func testAPIGetObjectIfRange(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	data := []byte("0123456789")
+	objInfo, err := obj.PutObject(bucketName, "object", int64(len(data)), bytes.NewReader(data), nil, "")
+	if err != nil {
+		t.Fatalf("%s: Failed to put object: <ERROR> %s", instanceType, err)
+	}
+	lastModified := objInfo.ModTime.UTC().Format(http.TimeFormat)
+	otherModified := objInfo.ModTime.Add(-time.Hour).UTC().Format(http.TimeFormat)
+
+	testCases := []struct {
+		byteRange          string
+		ifRange            string
+		expectedContent    []byte
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Range without If-Range.
+		{"bytes=2-4", "", data[2:5], http.StatusPartialContent},
+		// Test case - 2.
+		// If-Range with matching ETag returns the range.
+		{"bytes=2-4", "\"" + objInfo.MD5Sum + "\"", data[2:5], http.StatusPartialContent},
+		// Test case - 3.
+		// If-Range with changed ETag returns the whole object.
+		{"bytes=2-4", "\"" + strings.Repeat("0", 32) + "\"", data, http.StatusOK},
+		// Test case - 4.
+		// If-Range with weak ETag never matches.
+		{"bytes=2-4", "W/\"" + objInfo.MD5Sum + "\"", data, http.StatusOK},
+		// Test case - 5.
+		// If-Range with matching Last-Modified returns the range.
+		{"bytes=2-4", lastModified, data[2:5], http.StatusPartialContent},
+		// Test case - 6.
+		// If-Range with changed Last-Modified returns the whole object.
+		{"bytes=2-4", otherModified, data, http.StatusOK},
+		// Test case - 7.
+		// Unsatisfiable range is ignored if the object changed.
+		{"bytes=20-30", otherModified, data, http.StatusOK},
+		// Test case - 8.
+		// If-Range without Range returns the whole object.
+		{"", lastModified, data, http.StatusOK},
+	}
+
+	for i, testCase := range testCases {
+		req, err := newTestSignedRequestV4("GET", getGetObjectURL("", bucketName, "object"),
+			0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for Get Object: <ERROR> %v", i+1, instanceType, err)
+		}
+		if testCase.byteRange != "" {
+			req.Header.Set("Range", testCase.byteRange)
+		}
+		if testCase.ifRange != "" {
+			req.Header.Set("If-Range", testCase.ifRange)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+			continue
+		}
+		if !bytes.Equal(rec.Body.Bytes(), testCase.expectedContent) {
+			t.Errorf("Test %d: %s: Expected content `%s`, but found `%s`", i+1, instanceType, testCase.expectedContent, rec.Body.Bytes())
+		}
+	}
+}
+
 // Wrapper for calling PutObject API handler tests using streaming signature v4 for both XL multiple disks and FS single drive setup.
 func TestAPIPutObjectStreamSigV4Handler(t *testing.T) {
 	defer DetectTestLeak(t)()
@@ -704,6 +845,12 @@ func testAPIPutObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 	copySourceHeader.Set("X-Amz-Copy-Source", "somewhere")
 	invalidMD5Header := http.Header{}
 	invalidMD5Header.Set("Content-Md5", "42")
+	modTimeHeader := http.Header{}
+	modTimeHeader.Set(minioModTimeHeader, "2016-01-02T15:04:05Z")
+	futureModTimeHeader := http.Header{}
+	futureModTimeHeader.Set(minioModTimeHeader, time.Now().Add(24*time.Hour).UTC().Format(time.RFC3339))
+	invalidModTimeHeader := http.Header{}
+	invalidModTimeHeader.Set(minioModTimeHeader, "Sat, 02 Jan 2016 15:04:05 GMT")
 
 	addCustomHeaders := func(req *http.Request, customHeaders http.Header) {
 		for k, values := range customHeaders {
@@ -798,6 +945,42 @@ func testAPIPutObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 			fault:              MissingContentLength,
 			expectedRespStatus: http.StatusLengthRequired,
 		},
+		// Test case - 7.
+		// Test Case with modification time set by the client.
+		{
+			bucketName:         bucketName,
+			objectName:         objectName,
+			headers:            modTimeHeader,
+			data:               bytesData,
+			dataLen:            len(bytesData),
+			accessKey:          credentials.AccessKey,
+			secretKey:          credentials.SecretKey,
+			expectedRespStatus: http.StatusOK,
+		},
+		// Test case - 8.
+		// Test Case with modification time in the future.
+		{
+			bucketName:         bucketName,
+			objectName:         objectName,
+			headers:            futureModTimeHeader,
+			data:               bytesData,
+			dataLen:            len(bytesData),
+			accessKey:          credentials.AccessKey,
+			secretKey:          credentials.SecretKey,
+			expectedRespStatus: http.StatusBadRequest,
+		},
+		// Test case - 9.
+		// Test Case with modification time not in RFC 3339 format.
+		{
+			bucketName:         bucketName,
+			objectName:         objectName,
+			headers:            invalidModTimeHeader,
+			data:               bytesData,
+			dataLen:            len(bytesData),
+			accessKey:          credentials.AccessKey,
+			secretKey:          credentials.SecretKey,
+			expectedRespStatus: http.StatusBadRequest,
+		},
 	}
 	// Iterating over the cases, fetching the object validating the response.
 	for i, testCase := range testCases {
@@ -840,6 +1023,30 @@ func testAPIPutObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 				t.Errorf("Test %d: %s: Data Mismatch: Data fetched back from the uploaded object doesn't match the original one.", i+1, instanceType)
 			}
 			buffer.Reset()
+
+			// Modification time is the one requested, if any.
+			if modTime := testCase.headers.Get(minioModTimeHeader); modTime != "" {
+				objInfo, oerr := obj.GetObjectInfo(testCase.bucketName, testCase.objectName)
+				if oerr != nil {
+					t.Fatalf("Test %d: %s: Failed to stat the object: <ERROR> %s", i+1, instanceType, oerr)
+				}
+				if objInfo.ModTime.UTC().Format(time.RFC3339) != modTime {
+					t.Errorf("Test %d: %s: Expected modification time %s, got %s", i+1, instanceType, modTime, objInfo.ModTime)
+				}
+				if objInfo.UserDefined[modTimeMetaKey] != modTime {
+					t.Errorf("Test %d: %s: Expected modification time %s in user defined metadata, got %s", i+1, instanceType, modTime, objInfo.UserDefined[modTimeMetaKey])
+				}
+				// Modification time of `xl.json` remains the time of upload.
+				if xl, ok := obj.(*xlObjects); ok {
+					xlStat, _, serr := xl.readXLMetaStat(testCase.bucketName, testCase.objectName)
+					if serr != nil {
+						t.Fatalf("Test %d: %s: Failed to read xl.json: <ERROR> %s", i+1, instanceType, serr)
+					}
+					if time.Since(xlStat.ModTime) > time.Minute {
+						t.Errorf("Test %d: %s: Expected xl.json modification time to be the time of upload, got %s", i+1, instanceType, xlStat.ModTime)
+					}
+				}
+			}
 		}
 
 		// Verify response of the V2 signed HTTP request.
@@ -1248,6 +1455,116 @@ func testAPICopyObjectHandler(obj ObjectLayer, instanceType, bucketName string,
 
 }
 
+// Wrapper for calling Copy Object precondition tests for both XL multiple disks and single node setup.
+func TestAPICopyObjectPreconditions(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPICopyObjectPreconditions, []string{"CopyObject"})
+}
+
+func testAPICopyObjectPreconditions(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	data := []byte("hello")
+	objInfo, err := obj.PutObject(bucketName, "source", int64(len(data)), bytes.NewReader(data), nil, "")
+	if err != nil {
+		t.Fatalf("%s: Failed to put object: <ERROR> %s", instanceType, err)
+	}
+	otherBucketName := getRandomBucketName()
+	if err = obj.MakeBucket(otherBucketName); err != nil {
+		t.Fatalf("%s: Failed to make bucket: <ERROR> %s", instanceType, err)
+	}
+
+	etag := "\"" + objInfo.MD5Sum + "\""
+	otherETag := "\"" + strings.Repeat("0", 32) + "\""
+	before := objInfo.ModTime.Add(-time.Hour).UTC().Format(http.TimeFormat)
+	after := objInfo.ModTime.Add(time.Hour).UTC().Format(http.TimeFormat)
+
+	testCases := []struct {
+		headers            map[string]string
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// x-amz-copy-source-if-match with matching ETag.
+		{map[string]string{"X-Amz-Copy-Source-If-Match": etag}, http.StatusOK},
+		// Test case - 2.
+		// x-amz-copy-source-if-match with non-matching ETag.
+		{map[string]string{"X-Amz-Copy-Source-If-Match": otherETag}, http.StatusPreconditionFailed},
+		// Test case - 3.
+		// x-amz-copy-source-if-match with one of several ETags matching.
+		{map[string]string{"X-Amz-Copy-Source-If-Match": otherETag + ", " + etag}, http.StatusOK},
+		// Test case - 4.
+		// x-amz-copy-source-if-match with any ETag.
+		{map[string]string{"X-Amz-Copy-Source-If-Match": "*"}, http.StatusOK},
+		// Test case - 5.
+		// x-amz-copy-source-if-none-match with non-matching ETag.
+		{map[string]string{"X-Amz-Copy-Source-If-None-Match": otherETag}, http.StatusOK},
+		// Test case - 6.
+		// x-amz-copy-source-if-none-match with matching ETag.
+		{map[string]string{"X-Amz-Copy-Source-If-None-Match": etag}, http.StatusPreconditionFailed},
+		// Test case - 7.
+		// x-amz-copy-source-if-modified-since before the modification.
+		{map[string]string{"X-Amz-Copy-Source-If-Modified-Since": before}, http.StatusOK},
+		// Test case - 8.
+		// x-amz-copy-source-if-modified-since after the modification.
+		{map[string]string{"X-Amz-Copy-Source-If-Modified-Since": after}, http.StatusPreconditionFailed},
+		// Test case - 9.
+		// x-amz-copy-source-if-unmodified-since after the modification.
+		{map[string]string{"X-Amz-Copy-Source-If-Unmodified-Since": after}, http.StatusOK},
+		// Test case - 10.
+		// x-amz-copy-source-if-unmodified-since before the modification.
+		{map[string]string{"X-Amz-Copy-Source-If-Unmodified-Since": before}, http.StatusPreconditionFailed},
+		// Test case - 11.
+		// Invalid x-amz-copy-source-if-unmodified-since is ignored.
+		{map[string]string{"X-Amz-Copy-Source-If-Unmodified-Since": "invalid"}, http.StatusOK},
+		// Test case - 12.
+		// Invalid x-amz-copy-source-if-modified-since is ignored.
+		{map[string]string{"X-Amz-Copy-Source-If-Modified-Since": "invalid"}, http.StatusOK},
+		// Test case - 13.
+		// x-amz-copy-source-if-unmodified-since is not evaluated if
+		// x-amz-copy-source-if-match matches.
+		{map[string]string{
+			"X-Amz-Copy-Source-If-Match":            etag,
+			"X-Amz-Copy-Source-If-Unmodified-Since": before,
+		}, http.StatusOK},
+		// Test case - 14.
+		// x-amz-copy-source-if-none-match fails even if
+		// x-amz-copy-source-if-modified-since holds.
+		{map[string]string{
+			"X-Amz-Copy-Source-If-None-Match":     etag,
+			"X-Amz-Copy-Source-If-Modified-Since": before,
+		}, http.StatusPreconditionFailed},
+	}
+
+	for _, dstBucketName := range []string{bucketName, otherBucketName} {
+		for i, testCase := range testCases {
+			dstObjectName := fmt.Sprintf("copy-%d", i+1)
+			req, err := newTestSignedRequestV4("PUT", getCopyObjectURL("", dstBucketName, dstObjectName),
+				0, nil, credentials.AccessKey, credentials.SecretKey)
+			if err != nil {
+				t.Fatalf("Test %d: Failed to create HTTP request for copy Object: <ERROR> %v", i+1, err)
+			}
+			req.Header.Set("X-Amz-Copy-Source", "/"+bucketName+"/source")
+			for k, v := range testCase.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			apiRouter.ServeHTTP(rec, req)
+			if rec.Code != testCase.expectedRespStatus {
+				t.Errorf("Test %d: %s: %s: Expected the response status to be `%d`, but instead found `%d`",
+					i+1, instanceType, dstBucketName, testCase.expectedRespStatus, rec.Code)
+			}
+
+			// The copy exists only if the preconditions are met.
+			_, err = obj.GetObjectInfo(dstBucketName, dstObjectName)
+			if testCase.expectedRespStatus == http.StatusOK && err != nil {
+				t.Errorf("Test %d: %s: %s: Expected the object to be copied: <ERROR> %v", i+1, instanceType, dstBucketName, err)
+			}
+			if testCase.expectedRespStatus != http.StatusOK && err == nil {
+				t.Errorf("Test %d: %s: %s: Expected the object not to be copied", i+1, instanceType, dstBucketName)
+			}
+		}
+	}
+}
+
 // Wrapper for calling NewMultipartUpload tests for both XL multiple disks and single node setup.
 // First register the HTTP handler for NewMutlipartUpload, then a HTTP request for NewMultipart upload is made.
 // The UploadID from the response body is parsed and its existence is asserted with an attempt to ListParts using it.
@@ -2918,3 +3235,852 @@ func testAPIListObjectPartsHandler(obj ObjectLayer, instanceType, bucketName str
 	// `ExecObjectLayerAPINilTest` sets the Object Layer to `nil` and calls the handler.
 	ExecObjectLayerAPINilTest(t, nilBucket, nilObject, instanceType, apiRouter, nilReq)
 }
+
+// Wrapper for calling TruncateObject HTTP handler tests for both XL multiple disks and single node setup.
+func TestAPITruncateObjectHandler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPITruncateObjectHandler, []string{"TruncateObject"})
+}
+
+func testAPITruncateObjectHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	objectName := "test-object"
+	data := generateBytesData(6 * humanize.MiByte)
+	if _, err := obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatalf("Minio %s: Error uploading object: <ERROR> %v", instanceType, err)
+	}
+
+	testCases := []struct {
+		objectName string
+		length     string
+		accessKey  string
+
+		expectedRespStatus int
+		expectedSize       int64
+	}{
+		// Test case - 1.
+		// Truncate to a non-aligned length.
+		{objectName, "1000", credentials.AccessKey, http.StatusOK, 1000},
+		// Test case - 2.
+		// Truncate beyond the object size.
+		{objectName, "2000", credentials.AccessKey, http.StatusBadRequest, 1000},
+		// Test case - 3.
+		// Invalid length.
+		{objectName, "-1", credentials.AccessKey, http.StatusBadRequest, 1000},
+		// Test case - 4.
+		// Object doesn't exist.
+		{"non-existent-object", "0", credentials.AccessKey, http.StatusNotFound, 1000},
+		// Test case - 5.
+		// Invalid AccessKey.
+		{objectName, "0", "Invalid-AccessKey", http.StatusForbidden, 1000},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("POST", getTruncateObjectURL("", bucketName, testCase.objectName, testCase.length),
+			0, nil, testCase.accessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request for Truncate Object: <ERROR> %v", i+1, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Minio %s: Case %d: Expected the response status to be `%d`, but instead found `%d`", instanceType, i+1, testCase.expectedRespStatus, rec.Code)
+		}
+
+		objInfo, err := obj.GetObjectInfo(bucketName, objectName)
+		if err != nil {
+			t.Fatalf("Minio %s: Case %d: %v", instanceType, i+1, err)
+		}
+		if objInfo.Size != testCase.expectedSize {
+			t.Errorf("Minio %s: Case %d: Expected size %d, got %d", instanceType, i+1, testCase.expectedSize, objInfo.Size)
+		}
+		if rec.Code == http.StatusOK && rec.Header().Get("ETag") != "\""+getMD5Hash(data[:testCase.expectedSize])+"\"" {
+			t.Errorf("Minio %s: Case %d: Unexpected ETag %s", instanceType, i+1, rec.Header().Get("ETag"))
+		}
+	}
+}
+
+// listTmpEntries - returns entries in the temporary bucket of the
+// first disk of an object layer.
+func listTmpEntries(obj ObjectLayer) ([]string, error) {
+	switch objLayer := obj.(type) {
+	case fsObjects:
+		return objLayer.storage.ListDir(minioMetaTmpBucket, "")
+	case *xlObjects:
+		return objLayer.storageDisks[0].ListDir(minioMetaTmpBucket, "")
+	}
+	return nil, nil
+}
+
+// TestAPIPutObjectContentMD5Handler - Tests PutObject and PutObjectPart
+// handlers validate the body against the Content-Md5 header.
+func TestAPIPutObjectContentMD5Handler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPIPutObjectContentMD5Handler, []string{"PutObject", "PutObjectPart"})
+}
+
+func testAPIPutObjectContentMD5Handler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	objectName := "test-object"
+	uploadID, err := obj.NewMultipartUpload(bucketName, objectName, nil)
+	if err != nil {
+		t.Fatalf("Minio %s: <ERROR> %s", instanceType, err)
+	}
+
+	data := []byte("hello world")
+	dataMD5 := md5.Sum(data)
+	otherMD5 := md5.Sum([]byte("hello"))
+
+	testCases := []struct {
+		contentMD5 string
+
+		expectedRespStatus int
+		expectedErrCode    string
+	}{
+		// Test case - 1.
+		// Content-Md5 of a different content.
+		{base64.StdEncoding.EncodeToString(otherMD5[:]), http.StatusBadRequest, "BadDigest"},
+		// Test case - 2.
+		// Content-Md5 which is not a 128 bit digest.
+		{base64.StdEncoding.EncodeToString(dataMD5[:8]), http.StatusBadRequest, "InvalidDigest"},
+		// Test case - 3.
+		// Correct Content-Md5.
+		{base64.StdEncoding.EncodeToString(dataMD5[:]), http.StatusOK, ""},
+	}
+
+	for i, testCase := range testCases {
+		for _, targetURL := range []string{
+			getPutObjectURL("", bucketName, objectName),
+			getPutObjectPartURL("", bucketName, objectName, uploadID, "1"),
+		} {
+			rec := httptest.NewRecorder()
+			req, err := newTestRequest("PUT", targetURL, int64(len(data)), bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+			}
+			req.Header.Set("Content-Md5", testCase.contentMD5)
+			if err = signRequestV4(req, credentials.AccessKey, credentials.SecretKey); err != nil {
+				t.Fatalf("Test %d: %s: Failed to sign HTTP request: <ERROR> %v", i+1, instanceType, err)
+			}
+			apiRouter.ServeHTTP(rec, req)
+
+			if rec.Code != testCase.expectedRespStatus {
+				t.Fatalf("Test %d: %s: %s: Expected the response status to be `%d`, but instead found `%d`",
+					i+1, instanceType, targetURL, testCase.expectedRespStatus, rec.Code)
+			}
+			if testCase.expectedErrCode != "" {
+				var errXML APIErrorResponse
+				if err = xml.Unmarshal(rec.Body.Bytes(), &errXML); err != nil {
+					t.Fatalf("Test %d: %s: Failed to unmarshal error response: <ERROR> %v", i+1, instanceType, err)
+				}
+				if errXML.Code != testCase.expectedErrCode {
+					t.Errorf("Test %d: %s: Expected error code `%s`, got `%s`", i+1, instanceType, testCase.expectedErrCode, errXML.Code)
+				}
+			} else if etag := rec.Header().Get("ETag"); etag != "\""+hex.EncodeToString(dataMD5[:])+"\"" {
+				t.Errorf("Test %d: %s: Unexpected ETag %s", i+1, instanceType, etag)
+			}
+		}
+
+		// Rejected uploads must not leave any data behind.
+		if testCase.expectedErrCode != "" {
+			if _, err = obj.GetObjectInfo(bucketName, objectName); !isErrObjectNotFound(err) {
+				t.Errorf("Test %d: %s: Expected object to not exist, got %v", i+1, instanceType, err)
+			}
+			listPartsInfo, err := obj.ListObjectParts(bucketName, objectName, uploadID, 0, 1000)
+			if err != nil {
+				t.Fatalf("Test %d: %s: <ERROR> %v", i+1, instanceType, err)
+			}
+			if len(listPartsInfo.Parts) != 0 {
+				t.Errorf("Test %d: %s: Expected no parts to be uploaded, got %v", i+1, instanceType, listPartsInfo.Parts)
+			}
+			entries, err := listTmpEntries(obj)
+			if err != nil {
+				t.Fatalf("Test %d: %s: <ERROR> %v", i+1, instanceType, err)
+			}
+			if len(entries) != 0 {
+				t.Errorf("Test %d: %s: Expected no temporary files, got %v", i+1, instanceType, entries)
+			}
+		}
+	}
+}
+
+// TestAPIMultipartLimitsHandler - Tests configured multipart limits are
+// enforced by PutObjectPart and CompleteMultipartUpload handlers.
+func TestAPIMultipartLimitsHandler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	defer func(maxParts int, minSize, maxSize int64) {
+		globalMaxPartID, globalMinPartSize, globalMaxPartSize = maxParts, minSize, maxSize
+	}(globalMaxPartID, globalMinPartSize, globalMaxPartSize)
+	globalMaxPartID, globalMinPartSize, globalMaxPartSize = 2, 5, 10
+
+	ExecObjectLayerAPITest(t, testAPIMultipartLimitsHandler, []string{"PutObjectPart", "CompleteMultipart"})
+}
+
+func testAPIMultipartLimitsHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	objectName := "test-object"
+	uploadID, err := obj.NewMultipartUpload(bucketName, objectName, nil)
+	if err != nil {
+		t.Fatalf("Minio %s: <ERROR> %s", instanceType, err)
+	}
+
+	// execRequest - sends a signed request and validates the S3 error code.
+	execRequest := func(method, targetURL string, data []byte, expectedErrCode string) {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(method, targetURL, int64(len(data)), bytes.NewReader(data),
+			credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if expectedErrCode == "" {
+			if rec.Code != http.StatusOK {
+				t.Fatalf("%s: %s: Expected success, got %d %s", instanceType, targetURL, rec.Code, rec.Body.String())
+			}
+			return
+		}
+		var errXML APIErrorResponse
+		if err = xml.Unmarshal(rec.Body.Bytes(), &errXML); err != nil {
+			t.Fatalf("%s: Failed to unmarshal error response: <ERROR> %v", instanceType, err)
+		}
+		if errXML.Code != expectedErrCode {
+			t.Errorf("%s: %s: Expected error code `%s`, got `%s`", instanceType, targetURL, expectedErrCode, errXML.Code)
+		}
+	}
+
+	// completeRequestBody - returns complete multipart upload request
+	// body for the given parts data.
+	completeRequestBody := func(partData ...[]byte) []byte {
+		completeUploads := &completeMultipartUpload{}
+		for i, data := range partData {
+			completeUploads.Parts = append(completeUploads.Parts, completePart{PartNumber: i + 1, ETag: getMD5Hash(data)})
+		}
+		completeBytes, err := xml.Marshal(completeUploads)
+		if err != nil {
+			t.Fatalf("Error XML encoding of parts: <ERROR> %s.", err)
+		}
+		return completeBytes
+	}
+
+	completeURL := getCompleteMultipartUploadURL("", bucketName, objectName, uploadID)
+
+	// Part number beyond the maximum number of parts.
+	execRequest("PUT", getPutObjectPartURL("", bucketName, objectName, uploadID, "3"), []byte("abcde"), "InvalidArgument")
+	// Part larger than the maximum part size.
+	execRequest("PUT", getPutObjectPartURL("", bucketName, objectName, uploadID, "1"), []byte("abcdefghijk"), "EntityTooLarge")
+
+	// Non-final part smaller than the minimum part size.
+	execRequest("PUT", getPutObjectPartURL("", bucketName, objectName, uploadID, "1"), []byte("abc"), "")
+	execRequest("PUT", getPutObjectPartURL("", bucketName, objectName, uploadID, "2"), []byte("de"), "")
+	execRequest("POST", completeURL, completeRequestBody([]byte("abc"), []byte("de")), "EntityTooSmall")
+
+	// More parts than the maximum number of parts.
+	execRequest("POST", completeURL, completeRequestBody([]byte("abc"), []byte("de"), []byte("f")), "InvalidPart")
+
+	// Final part is allowed to be smaller than the minimum part size.
+	execRequest("PUT", getPutObjectPartURL("", bucketName, objectName, uploadID, "1"), []byte("abcde"), "")
+	execRequest("POST", completeURL, completeRequestBody([]byte("abcde"), []byte("de")), "")
+}
+
+// Wrapper for calling CopyObjectPart HTTP handler tests for both XL multiple disks and single node setup.
+func TestAPICopyObjectPartHandler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	defer func(minSize int64) {
+		globalMinPartSize = minSize
+	}(globalMinPartSize)
+	globalMinPartSize = humanize.KiByte
+
+	ExecObjectLayerAPITest(t, testAPICopyObjectPartHandler, []string{"CopyObjectPart", "CompleteMultipart", "GetObject"})
+}
+
+func testAPICopyObjectPartHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	srcObject := "src-object"
+	objectName := "test-object"
+	srcData := generateBytesData(6 * humanize.KiByte)
+	if _, err := obj.PutObject(bucketName, srcObject, int64(len(srcData)), bytes.NewReader(srcData), nil, ""); err != nil {
+		t.Fatalf("Minio %s: <ERROR> %s", instanceType, err)
+	}
+	uploadID, err := obj.NewMultipartUpload(bucketName, objectName, nil)
+	if err != nil {
+		t.Fatalf("Minio %s: <ERROR> %s", instanceType, err)
+	}
+
+	// execRequest - sends a signed request with the given headers and
+	// validates the S3 error code, the response is returned.
+	execRequest := func(method, targetURL string, data []byte, header http.Header, expectedErrCode string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(method, targetURL, int64(len(data)), bytes.NewReader(data),
+			credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		// Copy headers are signed as they are set after signing.
+		if err = signRequestV4(req, credentials.AccessKey, credentials.SecretKey); err != nil {
+			t.Fatalf("%s: Failed to sign HTTP request: <ERROR> %v", instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if expectedErrCode == "" {
+			if rec.Code != http.StatusOK {
+				t.Fatalf("%s: %s: Expected success, got %d %s", instanceType, targetURL, rec.Code, rec.Body.String())
+			}
+			return rec
+		}
+		var errXML APIErrorResponse
+		if err = xml.Unmarshal(rec.Body.Bytes(), &errXML); err != nil {
+			t.Fatalf("%s: Failed to unmarshal error response: <ERROR> %v", instanceType, err)
+		}
+		if errXML.Code != expectedErrCode {
+			t.Errorf("%s: %s: Expected error code `%s`, got `%s`", instanceType, targetURL, expectedErrCode, errXML.Code)
+		}
+		return rec
+	}
+
+	// copyPart - copies the range of the source object into part
+	// partNumber, returning the ETag of the part.
+	copyPart := func(partNumber, copySource, copyRange, expectedErrCode string) string {
+		header := http.Header{"X-Amz-Copy-Source": {copySource}}
+		if copyRange != "" {
+			header.Set("X-Amz-Copy-Source-Range", copyRange)
+		}
+		rec := execRequest("PUT", getPutObjectPartURL("", bucketName, objectName, uploadID, partNumber), nil, header, expectedErrCode)
+		if expectedErrCode != "" {
+			return ""
+		}
+		var response CopyObjectPartResponse
+		if err := xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%s: Failed to unmarshal copy part response: <ERROR> %v", instanceType, err)
+		}
+		if response.LastModified == "" {
+			t.Errorf("%s: Expected LastModified of the copied part", instanceType)
+		}
+		return strings.Trim(response.ETag, "\"")
+	}
+
+	copySource := url.QueryEscape("/" + bucketName + "/" + srcObject)
+
+	// Invalid copy sources and ranges.
+	copyPart("1", url.QueryEscape("/"+bucketName+"/missing-object"), "", "NoSuchKey")
+	copyPart("1", url.QueryEscape("/"+bucketName+"/"), "", "InvalidArgument")
+	copyPart("1", copySource, "bytes=1024-", "InvalidArgument")
+	copyPart("1", copySource, "bytes=4096-1024", "InvalidArgument")
+	copyPart("1", copySource, fmt.Sprintf("bytes=1024-%d", len(srcData)), "InvalidArgument")
+	// Unknown upload.
+	execRequest("PUT", getPutObjectPartURL("", bucketName, objectName, "unknown-upload-id", "1"), nil,
+		http.Header{"X-Amz-Copy-Source": {copySource}}, "NoSuchUpload")
+
+	// A range of the source object followed by the whole of it.
+	firstPart := srcData[1024:3072]
+	etag1 := copyPart("1", copySource, "bytes=1024-3071", "")
+	if etag1 != getMD5Hash(firstPart) {
+		t.Errorf("%s: Expected ETag %s of the first part, got %s", instanceType, getMD5Hash(firstPart), etag1)
+	}
+	etag2 := copyPart("2", copySource, "", "")
+	if etag2 != getMD5Hash(srcData) {
+		t.Errorf("%s: Expected ETag %s of the second part, got %s", instanceType, getMD5Hash(srcData), etag2)
+	}
+
+	completeUploads := &completeMultipartUpload{
+		Parts: []completePart{{PartNumber: 1, ETag: etag1}, {PartNumber: 2, ETag: etag2}},
+	}
+	completeBytes, err := xml.Marshal(completeUploads)
+	if err != nil {
+		t.Fatalf("Error XML encoding of parts: <ERROR> %s.", err)
+	}
+	execRequest("POST", getCompleteMultipartUploadURL("", bucketName, objectName, uploadID), completeBytes, nil, "")
+
+	// The assembled object is the range followed by the source object.
+	rec := execRequest("GET", getGetObjectURL("", bucketName, objectName), nil, nil, "")
+	if expected := append(append([]byte{}, firstPart...), srcData...); !bytes.Equal(rec.Body.Bytes(), expected) {
+		t.Errorf("%s: Assembled object of %d bytes does not match the %d bytes copied", instanceType, rec.Body.Len(), len(expected))
+	}
+}
+
+// Wrapper for calling PutObjectAssignKey HTTP handler tests for both XL multiple disks and single node setup.
+func TestAPIPutObjectAssignKeyHandler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPIPutObjectAssignKeyHandler, []string{"PutObjectAssignKey"})
+}
+
+func testAPIPutObjectAssignKeyHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	data := []byte("hello world")
+	testCases := []struct {
+		bucketName string
+		prefix     string
+		accessKey  string
+
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Key is assigned without a prefix.
+		{bucketName, "", credentials.AccessKey, http.StatusOK},
+		// Test case - 2.
+		// Key is assigned with a prefix.
+		{bucketName, "uploads/", credentials.AccessKey, http.StatusOK},
+		// Test case - 3.
+		// Bucket doesn't exist.
+		{"non-existent-bucket", "", credentials.AccessKey, http.StatusNotFound},
+		// Test case - 4.
+		// Invalid AccessKey.
+		{bucketName, "", "Invalid-AccessKey", http.StatusForbidden},
+		// Test case - 5.
+		// Invalid AccessKey is rejected before the bucket is looked up.
+		{"non-existent-bucket", "", "Invalid-AccessKey", http.StatusForbidden},
+	}
+
+	assignedKeys := make(map[string]bool)
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("POST", getAssignKeyURL("", testCase.bucketName, testCase.prefix),
+			int64(len(data)), bytes.NewReader(data), testCase.accessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request for Put Object Assign Key: <ERROR> %v", i+1, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Minio %s: Case %d: Expected the response status to be `%d`, but instead found `%d`", instanceType, i+1, testCase.expectedRespStatus, rec.Code)
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+
+		response := AssignKeyResponse{}
+		if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Minio %s: Case %d: Unable to parse response: <ERROR> %v", instanceType, i+1, err)
+		}
+		if !strings.HasPrefix(response.Key, testCase.prefix) || len(response.Key) == len(testCase.prefix) {
+			t.Errorf("Minio %s: Case %d: Expected a key with prefix `%s`, got `%s`", instanceType, i+1, testCase.prefix, response.Key)
+		}
+		if assignedKeys[response.Key] {
+			t.Errorf("Minio %s: Case %d: Key `%s` was assigned twice", instanceType, i+1, response.Key)
+		}
+		assignedKeys[response.Key] = true
+
+		etag := "\"" + getMD5Hash(data) + "\""
+		if response.ETag != etag || rec.Header().Get("ETag") != etag {
+			t.Errorf("Minio %s: Case %d: Expected ETag %s, got %s", instanceType, i+1, etag, response.ETag)
+		}
+		var buffer bytes.Buffer
+		if err = obj.GetObject(testCase.bucketName, response.Key, 0, int64(len(data)), &buffer); err != nil {
+			t.Fatalf("Minio %s: Case %d: Failed to fetch the assigned key: <ERROR> %v", instanceType, i+1, err)
+		}
+		if !bytes.Equal(buffer.Bytes(), data) {
+			t.Errorf("Minio %s: Case %d: Unexpected content of the assigned key", instanceType, i+1)
+		}
+	}
+}
+
+// Request body recording whether it was read.
+type readTrackingBody struct {
+	io.Reader
+	read bool
+}
+
+func (b *readTrackingBody) Read(p []byte) (int, error) {
+	b.read = true
+	return b.Reader.Read(p)
+}
+
+func (b *readTrackingBody) Close() error {
+	return nil
+}
+
+// Tests PutObject with Expect: 100-continue is rejected without the
+// client being told to send the body, and accepted otherwise.
+func TestPutObjectExpectContinue(t *testing.T) {
+	// Test server sets the address of this server.
+	defer func(addr, host, port string) {
+		globalMinioAddr, globalMinioHost, globalMinioPort = addr, host, port
+	}(globalMinioAddr, globalMinioHost, globalMinioPort)
+
+	ts := StartTestServer(t, "XL")
+	defer ts.Stop()
+
+	bucketName := getRandomBucketName()
+	if err := ts.Obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+
+	// Client waits for the 100 Continue before sending the body.
+	client := &http.Client{Transport: &http.Transport{ExpectContinueTimeout: time.Minute}}
+
+	data := bytes.Repeat([]byte("a"), humanize.MiByte)
+
+	// Bucket whose quota is smaller than the upload.
+	quotaBucketName := getRandomBucketName()
+	if err := ts.Obj.MakeBucket(quotaBucketName); err != nil {
+		t.Fatal(err)
+	}
+	if err := setBucketQuota(quotaBucketName, humanize.KiByte); err != nil {
+		t.Fatal(err)
+	}
+	defer setBucketQuota(quotaBucketName, 0)
+
+	testCases := []struct {
+		bucketName         string
+		accessKey          string
+		secretKey          string
+		expectedRespStatus int
+		expectedBodyRead   bool
+	}{
+		// Invalid signature.
+		{bucketName, ts.AccessKey, "wrong-secret-key", http.StatusForbidden, false},
+		// Anonymous request without a bucket policy.
+		{bucketName, "", "", http.StatusForbidden, false},
+		// Bucket does not exist.
+		{"non-existent-bucket", ts.AccessKey, ts.SecretKey, http.StatusNotFound, false},
+		// Upload exceeds the quota of the bucket.
+		{quotaBucketName, ts.AccessKey, ts.SecretKey, http.StatusInsufficientStorage, false},
+		// Valid request.
+		{bucketName, ts.AccessKey, ts.SecretKey, http.StatusOK, true},
+	}
+	for i, testCase := range testCases {
+		req, err := newTestSignedRequestV4("PUT", getPutObjectURL(ts.Server.URL, testCase.bucketName, "object"),
+			int64(len(data)), bytes.NewReader(data), testCase.accessKey, testCase.secretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		body := &readTrackingBody{Reader: bytes.NewReader(data)}
+		req.Body = body
+		req.Header.Set("Expect", "100-continue")
+
+		// Rejected requests get their final response without an
+		// interim 100 Continue.
+		got100Continue := false
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+			Got100Continue: func() { got100Continue = true },
+		}))
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != testCase.expectedRespStatus {
+			t.Errorf("Test %d: expected status %d, got %d", i+1, testCase.expectedRespStatus, resp.StatusCode)
+		}
+		if got100Continue != testCase.expectedBodyRead {
+			t.Errorf("Test %d: expected 100 Continue to be sent %t", i+1, testCase.expectedBodyRead)
+		}
+		if body.read != testCase.expectedBodyRead {
+			t.Errorf("Test %d: expected body read to be %t", i+1, testCase.expectedBodyRead)
+		}
+	}
+
+	// Signature of requests whose payload is verified by the handler,
+	// such as PutBucketPolicy, is verified before reading it as well.
+	policy := []byte(`{"Version":"2012-10-17","Statement":[]}`)
+	req, err := newTestSignedRequestV4("PUT", getPutPolicyURL(ts.Server.URL, bucketName),
+		int64(len(policy)), bytes.NewReader(policy), ts.AccessKey, "wrong-secret-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := &readTrackingBody{Reader: bytes.NewReader(policy)}
+	req.Body = body
+	req.Header.Set("Expect", "100-continue")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, resp.StatusCode)
+	}
+	if body.read {
+		t.Error("Expected body of a rejected PutBucketPolicy not to be read")
+	}
+}
+
+// TestAPIUserMetadataSizeHandler - Tests the configured maximum size of
+// user metadata is enforced by PutObject, PostPolicy, CopyObject and
+// NewMultipartUpload handlers.
+func TestAPIUserMetadataSizeHandler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPIUserMetadataSizeHandler, []string{"CopyObject", "PutObject", "PostPolicy", "NewMultipart"})
+}
+
+func testAPIUserMetadataSizeHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// Metadata key "Key" leaves 13 bytes for its value.
+	serverConfig.SetMaxUserMetadataSize(16)
+	defer serverConfig.SetMaxUserMetadataSize(0)
+
+	data := []byte("hello world")
+	if _, err := obj.PutObject(bucketName, "source-object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatalf("%s: Failed to create source object: <ERROR> %v", instanceType, err)
+	}
+
+	// checkResponse - validates the status and S3 error code of a response.
+	checkResponse := func(rec *httptest.ResponseRecorder, testName string, expectedErrCode string) {
+		if expectedErrCode == "" {
+			if rec.Code != http.StatusOK && rec.Code != http.StatusNoContent {
+				t.Errorf("%s: %s: Expected success, got %d %s", instanceType, testName, rec.Code, rec.Body.String())
+			}
+			return
+		}
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: %s: Expected the response status to be `%d`, but instead found `%d`",
+				instanceType, testName, http.StatusBadRequest, rec.Code)
+		}
+		var errXML APIErrorResponse
+		if err := xml.Unmarshal(rec.Body.Bytes(), &errXML); err != nil {
+			t.Fatalf("%s: %s: Failed to unmarshal error response: <ERROR> %v", instanceType, testName, err)
+		}
+		if errXML.Code != expectedErrCode {
+			t.Errorf("%s: %s: Expected error code `%s`, got `%s`", instanceType, testName, expectedErrCode, errXML.Code)
+		}
+	}
+
+	testCases := []struct {
+		metaValue       string
+		expectedErrCode string
+	}{
+		// Test case - 1.
+		// Metadata of exactly the maximum size.
+		{"1234567890123", ""},
+		// Test case - 2.
+		// Metadata one byte over the maximum size.
+		{"12345678901234", "MetadataTooLarge"},
+	}
+
+	for i, testCase := range testCases {
+		// PutObject.
+		rec := httptest.NewRecorder()
+		req, err := newTestRequest("PUT", getPutObjectURL("", bucketName, "test-object"), int64(len(data)), bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		req.Header.Set("X-Amz-Meta-Key", testCase.metaValue)
+		if err = signRequestV4(req, credentials.AccessKey, credentials.SecretKey); err != nil {
+			t.Fatalf("Test %d: %s: Failed to sign HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		checkResponse(rec, fmt.Sprintf("Test %d: PutObject", i+1), testCase.expectedErrCode)
+
+		// CopyObject replacing the metadata of the source object.
+		rec = httptest.NewRecorder()
+		req, err = newTestRequest("PUT", getCopyObjectURL("", bucketName, "copy-object"), 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		req.Header.Set("X-Amz-Copy-Source", url.QueryEscape("/"+bucketName+"/source-object"))
+		req.Header.Set("X-Amz-Metadata-Directive", "REPLACE")
+		req.Header.Set("X-Amz-Meta-Key", testCase.metaValue)
+		if err = signRequestV4(req, credentials.AccessKey, credentials.SecretKey); err != nil {
+			t.Fatalf("Test %d: %s: Failed to sign HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		checkResponse(rec, fmt.Sprintf("Test %d: CopyObject", i+1), testCase.expectedErrCode)
+
+		// NewMultipartUpload.
+		rec = httptest.NewRecorder()
+		req, err = newTestRequest("POST", getNewMultipartURL("", bucketName, "multipart-object"), 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		req.Header.Set("X-Amz-Meta-Key", testCase.metaValue)
+		if err = signRequestV4(req, credentials.AccessKey, credentials.SecretKey); err != nil {
+			t.Fatalf("Test %d: %s: Failed to sign HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		checkResponse(rec, fmt.Sprintf("Test %d: NewMultipartUpload", i+1), testCase.expectedErrCode)
+
+		// PostPolicy, the form already carries the 8 bytes of
+		// X-Amz-Meta-Uuid: 1234, replaced here by a value of the
+		// same size as the other requests.
+		rec = httptest.NewRecorder()
+		now := time.Now().UTC()
+		policy := buildGenericPolicy(now, credentials.AccessKey, bucketName, "post-object", false)
+		req, err = newPostRequestV4Generic("", bucketName, "post-object", data, credentials.AccessKey, credentials.SecretKey,
+			now, policy, map[string]string{"x-amz-meta-uuid": testCase.metaValue[1:]}, false, false)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		checkResponse(rec, fmt.Sprintf("Test %d: PostPolicy", i+1), testCase.expectedErrCode)
+	}
+}
+
+// TestAPIMultipartETagHandler - Tests ETags of multipart uploads follow
+// the S3 format, MD5 of the concatenated MD5s of the parts followed
+// by the number of parts, and ETags of single uploads are plain MD5s.
+func TestAPIMultipartETagHandler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	defer func(minSize int64) { globalMinPartSize = minSize }(globalMinPartSize)
+	globalMinPartSize = 5
+
+	ExecObjectLayerAPITest(t, testAPIMultipartETagHandler, []string{"CompleteMultipart", "HeadObject", "GetObject", "PutObject"})
+}
+
+func testAPIMultipartETagHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// do - sends a signed request, returns the recorded response.
+	do := func(method, targetURL string, data []byte) *httptest.ResponseRecorder {
+		req, err := newTestSignedRequestV4(method, targetURL, int64(len(data)), bytes.NewReader(data),
+			credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Minio %s: Failed to create HTTP request for %s %s: <ERROR> %v", instanceType, method, targetURL, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	objectName := "multipart-object"
+	parts := [][]byte{bytes.Repeat([]byte("a"), 1024), []byte("last part")}
+
+	// Expected ETag computed with the S3 formula.
+	var partMD5s []byte
+	for _, part := range parts {
+		sum := md5.Sum(part)
+		partMD5s = append(partMD5s, sum[:]...)
+	}
+	sum := md5.Sum(partMD5s)
+	expectedETag := "\"" + hex.EncodeToString(sum[:]) + "-2\""
+
+	uploadID, err := obj.NewMultipartUpload(bucketName, objectName, nil)
+	if err != nil {
+		t.Fatalf("Minio %s: <ERROR> %s", instanceType, err)
+	}
+	var complete completeMultipartUpload
+	for i, part := range parts {
+		md5Hex, perr := obj.PutObjectPart(bucketName, objectName, uploadID, i+1, int64(len(part)), bytes.NewReader(part), "", "")
+		if perr != nil {
+			t.Fatalf("Minio %s: <ERROR> %s", instanceType, perr)
+		}
+		complete.Parts = append(complete.Parts, completePart{PartNumber: i + 1, ETag: "\"" + md5Hex + "\""})
+	}
+	completeBytes, err := xml.Marshal(complete)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := do("POST", getCompleteMultipartUploadURL("", bucketName, objectName, uploadID), completeBytes)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Minio %s: Expected CompleteMultipartUpload to succeed, got %d: %s", instanceType, rec.Code, rec.Body)
+	}
+	var response CompleteMultipartUploadResponse
+	if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Minio %s: Failed to parse CompleteMultipartUpload response: <ERROR> %v", instanceType, err)
+	}
+	if response.ETag != expectedETag {
+		t.Errorf("Minio %s: Expected CompleteMultipartUpload ETag %s, got %s", instanceType, expectedETag, response.ETag)
+	}
+
+	// The same ETag is returned by HEAD and GET.
+	for _, method := range []string{"HEAD", "GET"} {
+		rec = do(method, getGetObjectURL("", bucketName, objectName), nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Minio %s: Expected %s to succeed, got %d", instanceType, method, rec.Code)
+		}
+		if etag := rec.Header().Get("ETag"); etag != expectedETag {
+			t.Errorf("Minio %s: Expected %s ETag %s, got %s", instanceType, method, expectedETag, etag)
+		}
+	}
+
+	// Single uploads have the plain MD5 as ETag.
+	data := []byte("single part")
+	rec = do("PUT", getPutObjectURL("", bucketName, "single-object"), data)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Minio %s: Expected PutObject to succeed, got %d: %s", instanceType, rec.Code, rec.Body)
+	}
+	singleSum := md5.Sum(data)
+	expectedETag = "\"" + hex.EncodeToString(singleSum[:]) + "\""
+	for _, method := range []string{"PUT", "HEAD"} {
+		if method == "HEAD" {
+			rec = do("HEAD", getHeadObjectURL("", bucketName, "single-object"), nil)
+		}
+		if etag := rec.Header().Get("ETag"); etag != expectedETag {
+			t.Errorf("Minio %s: Expected %s ETag %s, got %s", instanceType, method, expectedETag, etag)
+		}
+	}
+}
+
+// Wrapper for calling tests of the content headers served back on download for both XL multiple disks and single node setup.
+func TestAPIObjectContentHeadersHandler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPIObjectContentHeadersHandler, []string{"PutObject", "GetObject", "HeadObject"})
+}
+
+func testAPIObjectContentHeadersHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	objectName := "report"
+	data := []byte("hello")
+	headers := map[string]string{
+		"Content-Disposition": "attachment; filename=\"report.txt\"",
+		"Content-Encoding":    "identity",
+		"Content-Language":    "en-US",
+		"Cache-Control":       "max-age=3600",
+	}
+
+	req, err := newTestSignedRequestV4("PUT", getPutObjectURL("", bucketName, objectName),
+		int64(len(data)), bytes.NewReader(data), credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatalf("Minio %s: Failed to create HTTP request for PutObject: <ERROR> %v", instanceType, err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Minio %s: Expected PutObject to succeed, got %d: %s", instanceType, rec.Code, rec.Body)
+	}
+
+	// Headers set at upload are served back by GET and HEAD.
+	for _, method := range []string{"GET", "HEAD"} {
+		req, err = newTestSignedRequestV4(method, getGetObjectURL("", bucketName, objectName),
+			0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Minio %s: Failed to create HTTP request for %s: <ERROR> %v", instanceType, method, err)
+		}
+		rec = httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Minio %s: Expected %s to succeed, got %d", instanceType, method, rec.Code)
+		}
+		for k, v := range headers {
+			if value := rec.Header().Get(k); value != v {
+				t.Errorf("Minio %s: Expected %s to return %s `%s`, got `%s`", instanceType, method, k, v, value)
+			}
+		}
+	}
+
+	// response-* parameters of a presigned URL override the stored headers.
+	req, err = newTestRequest("GET", getGetObjectURL("", bucketName, objectName)+
+		"?response-content-disposition=inline&response-cache-control=no-cache", 0, nil)
+	if err != nil {
+		t.Fatalf("Minio %s: Failed to create HTTP request for GetObject: <ERROR> %v", instanceType, err)
+	}
+	if err = preSignV4(req, credentials.AccessKey, credentials.SecretKey, int64(10*60)); err != nil {
+		t.Fatalf("Minio %s: Failed to presign GetObject request: <ERROR> %v", instanceType, err)
+	}
+	rec = httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Minio %s: Expected presigned GetObject to succeed, got %d: %s", instanceType, rec.Code, rec.Body)
+	}
+	if !bytes.Equal(rec.Body.Bytes(), data) {
+		t.Errorf("Minio %s: Expected object content %s, got %s", instanceType, data, rec.Body)
+	}
+	for k, v := range map[string]string{
+		"Content-Disposition": "inline",
+		"Cache-Control":       "no-cache",
+		"Content-Language":    headers["Content-Language"],
+	} {
+		if value := rec.Header().Get(k); value != v {
+			t.Errorf("Minio %s: Expected presigned GetObject to return %s `%s`, got `%s`", instanceType, k, v, value)
+		}
+	}
+}
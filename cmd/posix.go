@@ -734,6 +734,63 @@ func (s *posix) AppendFile(volume, path string, buf []byte) (err error) {
 	return err
 }
 
+// TruncateFile - truncates the file at path to the requested size,
+// size cannot be larger than the current size of the file.
+func (s *posix) TruncateFile(volume, path string, size int64) (err error) {
+	defer func() {
+		if err == syscall.EIO {
+			atomic.AddInt32(&s.ioErrCount, 1)
+		}
+	}()
+
+	if s.ioErrCount > maxAllowedIOError {
+		return errFaultyDisk
+	}
+
+	if size < 0 {
+		return errInvalidArgument
+	}
+
+	if err = s.checkDiskFound(); err != nil {
+		return err
+	}
+
+	volumeDir, err := s.getVolDir(volume)
+	if err != nil {
+		return err
+	}
+	// Stat a volume entry.
+	_, err = os.Stat(preparePath(volumeDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errVolumeNotFound
+		}
+		return err
+	}
+
+	filePath := pathJoin(volumeDir, path)
+	if err = checkPathLength(preparePath(filePath)); err != nil {
+		return err
+	}
+	st, err := os.Stat(preparePath(filePath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errFileNotFound
+		} else if isSysErrNotDir(err) {
+			return errFileAccessDenied
+		}
+		return err
+	}
+	if !st.Mode().IsRegular() {
+		return errIsNotRegular
+	}
+	// Growing a file is not allowed.
+	if size > st.Size() {
+		return errInvalidArgument
+	}
+	return os.Truncate(preparePath(filePath), size)
+}
+
 // StatFile - get file info.
 func (s *posix) StatFile(volume, path string) (file FileInfo, err error) {
 	defer func() {
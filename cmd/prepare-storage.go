@@ -18,10 +18,12 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
 	"net/url"
 	"time"
 
 	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/disk"
 )
 
 /*
@@ -231,6 +233,11 @@ func retryFormattingDisks(firstDisk bool, endpoints []*url.URL, storageDisks []S
 				}
 				return nil
 			} // Check if this is a XL or distributed XL, anything > 1 is considered XL backend.
+			// Formatted disks must agree on the XL backend format
+			// version, disks are upgraded only if requested.
+			if err := checkFormatXLVersions(storageDisks, formatConfigs, globalAutoFormatUpgrade); err != nil {
+				return err
+			}
 			// Pre-emptively check if one of the formatted disks
 			// is invalid. This function returns success for the
 			// most part unless one of the formats is not consistent
@@ -239,6 +246,11 @@ func retryFormattingDisks(firstDisk bool, endpoints []*url.URL, storageDisks []S
 			if err := checkFormatXLValues(formatConfigs); err != nil {
 				return err
 			}
+			// Formatted disks must be erasure coded with the
+			// parity of the configured storage class.
+			if err := checkFormatXLParity(formatConfigs, globalStorageClassParity); err != nil {
+				return err
+			}
 			switch prepForInitXL(firstDisk, sErrs, len(storageDisks)) {
 			case Abort:
 				return errCorruptedFormat
@@ -294,11 +306,40 @@ func initStorageDisks(endpoints []*url.URL) ([]StorageAPI, error) {
 		if err != nil && err != errDiskNotFound {
 			return nil, err
 		}
+		if storage != nil && isLocalStorage(ep) {
+			if err = checkNetworkDisk(getPath(ep)); err != nil {
+				return nil, err
+			}
+		}
 		storageDisks[index] = storage
 	}
 	return storageDisks, nil
 }
 
+// getDiskFSType - returns the filesystem type of the disk at diskPath.
+// Variable to allow tests to mock it.
+var getDiskFSType = func(diskPath string) (string, error) {
+	info, err := disk.GetInfo(diskPath)
+	return info.FSType, err
+}
+
+// checkNetworkDisk - warns if the local disk at diskPath is on a network
+// or FUSE filesystem, erasure coding over such disks does not give the
+// durability of independent disks. Returns an error instead if such
+// disks are rejected.
+func checkNetworkDisk(diskPath string) error {
+	fsType, err := getDiskFSType(diskPath)
+	if err != nil || !disk.IsNetworkFSType(fsType) {
+		// Detection is only advisory.
+		return nil
+	}
+	if globalRejectNetworkDisks {
+		return fmt.Errorf("Disk %s is on a %s network filesystem", diskPath, fsType)
+	}
+	console.Println(colorRed(fmt.Sprintf("WARNING: Disk %s is on a %s network filesystem, durability of data is not guaranteed.", diskPath, fsType)))
+	return nil
+}
+
 // Format disks before initialization object layer.
 func waitForFormatDisks(firstDisk bool, endpoints []*url.URL, storageDisks []StorageAPI) (formattedDisks []StorageAPI, err error) {
 	if len(endpoints) == 0 {
@@ -312,6 +353,21 @@ func waitForFormatDisks(firstDisk bool, endpoints []*url.URL, storageDisks []Sto
 		return nil, errInvalidArgument
 	}
 
+	// Disks beyond a single erasure set form multiple erasure sets,
+	// each formatted on its own.
+	if setCount := getErasureSetCount(len(storageDisks)); setCount > 1 {
+		setSize := len(storageDisks) / setCount
+		for i := 0; i < setCount; i++ {
+			var setDisks []StorageAPI
+			setDisks, err = waitForFormatDisks(firstDisk, endpoints[i*setSize:(i+1)*setSize], storageDisks[i*setSize:(i+1)*setSize])
+			if err != nil {
+				return nil, err
+			}
+			formattedDisks = append(formattedDisks, setDisks...)
+		}
+		return formattedDisks, nil
+	}
+
 	// Retryable disks before formatting, we need to have a larger
 	// retry window so that we wait enough amount of time before
 	// the disks come online.
@@ -342,6 +398,7 @@ func waitForFormatDisks(firstDisk bool, endpoints []*url.URL, storageDisks []Sto
 			maxRetryAttempts: globalStorageRetryThreshold,
 			retryUnit:        time.Millisecond,
 			retryCap:         time.Millisecond * 5, // 5 milliseconds.
+			latency:          newDiskLatency(),
 		}
 	}
 
@@ -16,7 +16,10 @@
 
 package cmd
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 func (action InitActions) String() string {
 	switch action {
@@ -140,3 +143,67 @@ func TestPrepForInitXL(t *testing.T) {
 		}
 	}
 }
+
+// Tests disks on network filesystems are detected, and rejected only if requested.
+func TestCheckNetworkDisk(t *testing.T) {
+	defer func(getFSType func(string) (string, error)) {
+		getDiskFSType = getFSType
+		globalRejectNetworkDisks = false
+	}(getDiskFSType)
+
+	disks, err := getRandomDisks(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(disks)
+	endpoints, err := parseStorageEndpoints(disks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		fsType     string
+		fsTypeErr  error
+		reject     bool
+		shouldPass bool
+	}{
+		// Test case - 1.
+		// Local filesystem.
+		{"EXT4", nil, true, true},
+		// Test case - 2.
+		// Network filesystem, only warned about by default.
+		{"NFS", nil, false, true},
+		// Test case - 3.
+		// Network filesystem, rejected.
+		{"NFS", nil, true, false},
+		// Test case - 4.
+		// FUSE filesystem, rejected.
+		{"FUSE", nil, true, false},
+		// Test case - 5.
+		// Detection failure does not block startup.
+		{"", errors.New("statfs failed"), true, true},
+	}
+	for i, testCase := range testCases {
+		var checkedPaths []string
+		getDiskFSType = func(diskPath string) (string, error) {
+			checkedPaths = append(checkedPaths, diskPath)
+			return testCase.fsType, testCase.fsTypeErr
+		}
+		globalRejectNetworkDisks = testCase.reject
+
+		err = checkNetworkDisk(disks[0])
+		if testCase.shouldPass != (err == nil) {
+			t.Errorf("Test %d: Expected to pass %t, got %v", i+1, testCase.shouldPass, err)
+		}
+
+		// Disks are checked when initialized.
+		checkedPaths = nil
+		_, err = initStorageDisks(endpoints)
+		if testCase.shouldPass != (err == nil) {
+			t.Errorf("Test %d: Expected initialization to pass %t, got %v", i+1, testCase.shouldPass, err)
+		}
+		if len(checkedPaths) == 0 {
+			t.Errorf("Test %d: Expected filesystem type of disks to be checked", i+1)
+		}
+	}
+}
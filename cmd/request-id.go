@@ -0,0 +1,67 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Response header carrying the request ID as well, S3 uses it for the
+// ID of the host serving the request.
+const responseHostIDKey = "x-amz-id-2"
+
+// requestIDContextKey - key of the request ID in the context of a request.
+type requestIDContextKey struct{}
+
+// Requests handled by this server so far, makes request IDs generated
+// within the same nanosecond unique.
+var requestCounter uint64
+
+// newRequestID - returns a unique ID, the hexadecimal time of the
+// request followed by a counter of requests.
+func newRequestID() string {
+	return fmt.Sprintf("%s%04X", mustGetRequestID(time.Now().UTC()), atomic.AddUint64(&requestCounter, 1)&0xFFFF)
+}
+
+// getRequestID - returns the ID of a request, empty if the request did
+// not go through the request ID handler.
+func getRequestID(r *http.Request) string {
+	requestID, _ := r.Context().Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+// requestIDHandler - assigns a unique ID to each request, returned in
+// the x-amz-request-id and x-amz-id-2 headers of its response and
+// logged with errors of the request.
+type requestIDHandler struct {
+	handler http.Handler
+}
+
+func setRequestIDHandler(h http.Handler) http.Handler {
+	return requestIDHandler{h}
+}
+
+func (h requestIDHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	requestID := newRequestID()
+	w.Header().Set(responseRequestIDKey, requestID)
+	w.Header().Set(responseHostIDKey, requestID)
+	h.handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDContextKey{}, requestID)))
+}
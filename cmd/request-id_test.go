@@ -0,0 +1,239 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// captureLogs - logs errors to a buffer until the returned function
+// restores the loggers.
+func captureLogs() (*bytes.Buffer, func()) {
+	var buffer bytes.Buffer
+	testLog := logrus.New()
+	testLog.Out = &buffer
+	testLog.Formatter = new(logrus.JSONFormatter)
+
+	log.mu.Lock()
+	loggers := log.loggers
+	log.loggers = []*logrus.Logger{testLog}
+	log.mu.Unlock()
+	return &buffer, func() {
+		log.mu.Lock()
+		log.loggers = loggers
+		log.mu.Unlock()
+	}
+}
+
+// parseLogs - returns the log entries of a buffer.
+func parseLogs(t *testing.T, buffer *bytes.Buffer) []logrus.Fields {
+	var entries []logrus.Fields
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry logrus.Fields
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Failed to parse log line %q: <ERROR> %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// Tests requests get a unique ID, returned in response headers and
+// logged with errors of the request.
+func TestRequestIDHandler(t *testing.T) {
+	buffer, restore := captureLogs()
+	defer restore()
+
+	handler := setRequestIDHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestErrorIf(r, errors.New("first error"), "Failed once.")
+		requestErrorIf(r, errors.New("second error"), "Failed twice.")
+		writeErrorResponse(w, ErrInternalError, r.URL)
+	}))
+
+	seenIDs := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		buffer.Reset()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/bucket/object", nil))
+
+		requestID := rec.Header().Get(responseRequestIDKey)
+		if requestID == "" {
+			t.Fatal("Expected x-amz-request-id header to be set")
+		}
+		if hostID := rec.Header().Get(responseHostIDKey); hostID != requestID {
+			t.Errorf("Expected x-amz-id-2 to be %q, got %q", requestID, hostID)
+		}
+		if seenIDs[requestID] {
+			t.Errorf("Expected unique request IDs, got %q twice", requestID)
+		}
+		seenIDs[requestID] = true
+
+		entries := parseLogs(t, buffer)
+		if len(entries) != 2 {
+			t.Fatalf("Expected 2 log entries, got %d", len(entries))
+		}
+		for _, entry := range entries {
+			if entry["requestID"] != requestID {
+				t.Errorf("Expected log entry of request %q, got %v", requestID, entry["requestID"])
+			}
+		}
+	}
+
+	// Errors logged outside of requests have no request ID.
+	buffer.Reset()
+	requestErrorIf(httptest.NewRequest("GET", "/", nil), errors.New("error"), "Failed.")
+	if entries := parseLogs(t, buffer); len(entries) != 1 || entries[0]["requestID"] != nil {
+		t.Errorf("Expected a log entry without request ID, got %v", entries)
+	}
+}
+
+// Tests responses of the server carry the request ID.
+func TestRequestIDHeaders(t *testing.T) {
+	ts, stop := startTestServerWithPeers(t, "FS")
+	defer stop()
+
+	seenIDs := make(map[string]bool)
+	testCases := []struct {
+		method, url string
+	}{
+		// Successful and failed requests.
+		{"GET", getListBucketURL(ts.Server.URL)},
+		{"HEAD", getHEADBucketURL(ts.Server.URL, "missing-bucket")},
+	}
+	for _, testCase := range testCases {
+		method, url := testCase.method, testCase.url
+		req, err := newTestSignedRequestV4(method, url, 0, nil, ts.AccessKey, ts.SecretKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		requestID := resp.Header.Get(responseRequestIDKey)
+		if requestID == "" || seenIDs[requestID] {
+			t.Errorf("Expected a unique request ID for %s %s, got %q", method, url, requestID)
+		}
+		seenIDs[requestID] = true
+		if hostID := resp.Header.Get(responseHostIDKey); hostID != requestID {
+			t.Errorf("Expected x-amz-id-2 of %s %s to be %q, got %q", method, url, requestID, hostID)
+		}
+	}
+}
+
+// failingBucketMetaState - peer failing all updates, recording the
+// request IDs of the updates.
+type failingBucketMetaState struct {
+	localBucketMetaState
+	mu         sync.Mutex
+	requestIDs []string
+}
+
+func (f *failingBucketMetaState) record(requestID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requestIDs = append(f.requestIDs, requestID)
+	return errors.New("peer unavailable")
+}
+
+func (f *failingBucketMetaState) UpdateBucketNotification(args *SetBucketNotificationPeerArgs) error {
+	return f.record(args.RequestID)
+}
+
+func (f *failingBucketMetaState) UpdateBucketListener(args *SetBucketListenerPeerArgs) error {
+	return f.record(args.RequestID)
+}
+
+func (f *failingBucketMetaState) UpdateBucketPolicy(args *SetBucketPolicyPeerArgs) error {
+	return f.record(args.RequestID)
+}
+
+// Tests peer updates forward the ID of the API request they are made
+// for, logged by both the sending and the receiving server.
+func TestS3PeersRequestID(t *testing.T) {
+	defer func(peers s3Peers) { globalS3Peers = peers }(globalS3Peers)
+	peer := &failingBucketMetaState{}
+	globalS3Peers = s3Peers{{"192.168.1.11:9000", peer}}
+
+	buffer, restore := captureLogs()
+	defer restore()
+
+	requestID := newRequestID()
+	S3PeersUpdateBucketNotification("bucket", nil, requestID)
+	S3PeersUpdateBucketListener("bucket", nil, requestID)
+	S3PeersUpdateBucketPolicy("bucket", policyChange{IsRemove: true}, requestID)
+	if len(peer.requestIDs) != 3 {
+		t.Fatalf("Expected 3 updates, got %d", len(peer.requestIDs))
+	}
+	for _, id := range peer.requestIDs {
+		if id != requestID {
+			t.Errorf("Expected update of request %q, got %q", requestID, id)
+		}
+	}
+	entries := parseLogs(t, buffer)
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 log entries, got %d", len(entries))
+	}
+	for _, entry := range entries {
+		if entry["requestID"] != requestID {
+			t.Errorf("Expected log entry of request %q, got %v", requestID, entry["requestID"])
+		}
+	}
+
+	// The ID is sent over RPC and logged by the peer failing the update.
+	ts, disks := StartTestS3PeerRPCServer(t)
+	defer ts.Stop()
+	defer removeRoots(disks)
+
+	client := newAuthRPCClient(authConfig{
+		serverAddr:      ts.Server.Listener.Addr().String(),
+		accessKey:       ts.AccessKey,
+		secretKey:       ts.SecretKey,
+		serviceEndpoint: path.Join(reservedBucket, s3Path),
+		serviceName:     "S3",
+	})
+	defer client.Close()
+
+	buffer.Reset()
+	args := SetBucketPolicyPeerArgs{
+		AuthRPCArgs: AuthRPCArgs{RequestID: requestID},
+		Bucket:      "bucket",
+		PChBytes:    []byte("invalid policy change"),
+	}
+	if err := client.Call("S3.SetBucketPolicyPeer", &args, &AuthRPCReply{}); err == nil {
+		t.Fatal("Expected update with an invalid policy change to fail")
+	}
+	entries = parseLogs(t, buffer)
+	if len(entries) != 1 || entries[0]["requestID"] != requestID {
+		t.Errorf("Expected the peer to log the error of request %q, got %v", requestID, entries)
+	}
+}
@@ -41,6 +41,9 @@ type retryStorage struct {
 	maxRetryAttempts int
 	retryUnit        time.Duration
 	retryCap         time.Duration
+
+	// Recent latency of reads and writes, nil if not tracked.
+	latency *diskLatency
 }
 
 // String representation of remoteStorage.
@@ -132,6 +135,9 @@ func (f retryStorage) PrepareFile(volume, path string, length int64) (err error)
 
 // AppendFile - a retryable implementation of append to a file.
 func (f retryStorage) AppendFile(volume, path string, buffer []byte) (err error) {
+	if f.latency != nil {
+		defer f.latency.recordWrite(time.Now())
+	}
 	err = f.remoteStorage.AppendFile(volume, path, buffer)
 	if err == errDiskNotFound {
 		err = f.reInit()
@@ -142,6 +148,18 @@ func (f retryStorage) AppendFile(volume, path string, buffer []byte) (err error)
 	return err
 }
 
+// TruncateFile - a retryable implementation of truncating a file.
+func (f retryStorage) TruncateFile(volume, path string, size int64) (err error) {
+	err = f.remoteStorage.TruncateFile(volume, path, size)
+	if err == errDiskNotFound {
+		err = f.reInit()
+		if err == nil {
+			return f.remoteStorage.TruncateFile(volume, path, size)
+		}
+	}
+	return err
+}
+
 // StatFile - a retryable implementation of stating a file.
 func (f retryStorage) StatFile(volume, path string) (fileInfo FileInfo, err error) {
 	fileInfo, err = f.remoteStorage.StatFile(volume, path)
@@ -156,6 +174,9 @@ func (f retryStorage) StatFile(volume, path string) (fileInfo FileInfo, err erro
 
 // ReadAll - a retryable implementation of reading all the content from a file.
 func (f retryStorage) ReadAll(volume, path string) (buf []byte, err error) {
+	if f.latency != nil {
+		defer f.latency.recordRead(time.Now())
+	}
 	buf, err = f.remoteStorage.ReadAll(volume, path)
 	if err == errDiskNotFound {
 		err = f.reInit()
@@ -168,6 +189,9 @@ func (f retryStorage) ReadAll(volume, path string) (buf []byte, err error) {
 
 // ReadFile - a retryable implementation of reading at offset from a file.
 func (f retryStorage) ReadFile(volume, path string, offset int64, buffer []byte) (m int64, err error) {
+	if f.latency != nil {
+		defer f.latency.recordRead(time.Now())
+	}
 	m, err = f.remoteStorage.ReadFile(volume, path, offset, buffer)
 	if err == errDiskNotFound {
 		err = f.reInit()
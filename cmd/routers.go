@@ -28,16 +28,20 @@ func newObjectLayerFn() ObjectLayer {
 	return globalObjectAPI
 }
 
-// newObjectLayer - initialize any object layer depending on the number of disks.
-func newObjectLayer(storageDisks []StorageAPI) (ObjectLayer, error) {
+// newObjectLayer - initialize any object layer depending on the number of disks,
+// erasure coded objects use parityBlocks, zero for half the disks of a set.
+func newObjectLayer(storageDisks []StorageAPI, parityBlocks int) (ObjectLayer, error) {
 	var objAPI ObjectLayer
 	var err error
 	if len(storageDisks) == 1 {
 		// Initialize FS object layer.
 		objAPI, err = newFSObjects(storageDisks[0])
+	} else if len(storageDisks) > maxErasureBlocks {
+		// Initialize XL object layer on multiple erasure sets.
+		objAPI, err = newXLSets(storageDisks, parityBlocks)
 	} else {
 		// Initialize XL object layer.
-		objAPI, err = newXLObjects(storageDisks)
+		objAPI, err = newXLObjects(storageDisks, parityBlocks)
 	}
 	if err != nil {
 		return nil, err
@@ -116,6 +120,13 @@ func configureServerHandler(srvCmdConfig serverCmdConfig) (http.Handler, error)
 		return nil, err
 	}
 
+	// Add Prometheus metrics router, before the web router which
+	// serves all other paths under the reserved bucket.
+	registerMetricsRouter(mux, srvCmdConfig)
+
+	// Add health check router.
+	registerHealthRouter(mux)
+
 	// Register web router when its enabled.
 	if globalIsBrowserEnabled {
 		if err := registerWebRouter(mux); err != nil {
@@ -152,6 +163,22 @@ func configureServerHandler(srvCmdConfig serverCmdConfig) (http.Handler, error)
 		// routes them accordingly. Client receives a HTTP error for
 		// invalid/unsupported signatures.
 		setAuthHandler,
+		// Compresses listings and other XML responses for clients
+		// accepting it.
+		setCompressHandler,
+		// Accounts requests, bytes in/out and errors per access key.
+		setTenantAccountingHandler,
+		// Rejects mutating S3 API requests in read-only mode.
+		setReadOnlyHandler,
+		// Rejects requests of clients outside of the allowed address
+		// ranges, before any other handler but the request ID one.
+		setIPAllowListHandler,
+		// Logs requests slower than the configured threshold, with
+		// the ID of the request.
+		setSlowRequestHandler,
+		// Assigns a unique ID to all requests, including rejected
+		// ones, and returns it in response headers.
+		setRequestIDHandler,
 		// Add new handlers here.
 	}
 
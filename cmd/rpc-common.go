@@ -41,6 +41,10 @@ type AuthRPCArgs struct {
 	// Request time to be verified by the server for every RPC call.
 	// This is an addition check over Authentication token for time drifting.
 	RequestTime time.Time
+
+	// ID of the API request the call is made for, empty if none,
+	// logged by the server with errors of the call.
+	RequestID string
 }
 
 // SetAuthToken - sets the token to the supplied value.
@@ -104,8 +108,21 @@ type LoginRPCReply struct {
 type LockArgs struct {
 	AuthRPCArgs
 	LockArgs dsync.LockArgs
+	// Boot ID of the process claiming the lock, locks of previous
+	// processes of a node are released when it restarts.
+	BootID string
 }
 
 func newLockArgs(args dsync.LockArgs) LockArgs {
-	return LockArgs{LockArgs: args}
+	return LockArgs{LockArgs: args, BootID: globalNodeBootID}
+}
+
+// ReleaseNodeLocksArgs represents arguments for releasing the locks
+// claimed by previous processes of a restarted node, or by the current
+// process of a node shutting down.
+type ReleaseNodeLocksArgs struct {
+	AuthRPCArgs
+	Node   string // Network address of the node, as in dsync.LockArgs.
+	BootID string // Boot ID of the current process of the node, its locks are kept.
+	Held   bool   // Release the locks claimed under BootID instead.
 }
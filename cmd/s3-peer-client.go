@@ -69,6 +69,7 @@ func makeS3Peers(eps []*url.URL) s3Peers {
 				serviceEndpoint: path.Join(reservedBucket, s3Path),
 				secureConn:      globalIsSSL,
 				serviceName:     "S3",
+				retry:           newPeerRPCRetryPolicy(),
 			}
 
 			ret = append(ret, s3Peer{
@@ -152,13 +153,14 @@ func (s3p s3Peers) SendUpdate(peerIndex []int, args BucketUpdater) []error {
 }
 
 // S3PeersUpdateBucketNotification - Sends Update Bucket notification
-// request to all peers. Currently we log an error and continue.
-func S3PeersUpdateBucketNotification(bucket string, ncfg *notificationConfig) {
-	setBNPArgs := &SetBucketNotificationPeerArgs{Bucket: bucket, NCfg: ncfg}
+// request to all peers on behalf of the API request of requestID,
+// empty if none. Currently we log an error and continue.
+func S3PeersUpdateBucketNotification(bucket string, ncfg *notificationConfig, requestID string) {
+	setBNPArgs := &SetBucketNotificationPeerArgs{AuthRPCArgs: AuthRPCArgs{RequestID: requestID}, Bucket: bucket, NCfg: ncfg}
 	errs := globalS3Peers.SendUpdate(nil, setBNPArgs)
 	for idx, err := range errs {
-		errorIf(
-			err,
+		requestIDErrorIf(
+			requestID, err,
 			"Error sending update bucket notification to %s - %v",
 			globalS3Peers[idx].addr, err,
 		)
@@ -166,13 +168,14 @@ func S3PeersUpdateBucketNotification(bucket string, ncfg *notificationConfig) {
 }
 
 // S3PeersUpdateBucketListener - Sends Update Bucket listeners request
-// to all peers. Currently we log an error and continue.
-func S3PeersUpdateBucketListener(bucket string, lcfg []listenerConfig) {
-	setBLPArgs := &SetBucketListenerPeerArgs{Bucket: bucket, LCfg: lcfg}
+// to all peers on behalf of the API request of requestID, empty if
+// none. Currently we log an error and continue.
+func S3PeersUpdateBucketListener(bucket string, lcfg []listenerConfig, requestID string) {
+	setBLPArgs := &SetBucketListenerPeerArgs{AuthRPCArgs: AuthRPCArgs{RequestID: requestID}, Bucket: bucket, LCfg: lcfg}
 	errs := globalS3Peers.SendUpdate(nil, setBLPArgs)
 	for idx, err := range errs {
-		errorIf(
-			err,
+		requestIDErrorIf(
+			requestID, err,
 			"Error sending update bucket listener to %s - %v",
 			globalS3Peers[idx].addr, err,
 		)
@@ -180,18 +183,19 @@ func S3PeersUpdateBucketListener(bucket string, lcfg []listenerConfig) {
 }
 
 // S3PeersUpdateBucketPolicy - Sends update bucket policy request to
-// all peers. Currently we log an error and continue.
-func S3PeersUpdateBucketPolicy(bucket string, pCh policyChange) {
+// all peers on behalf of the API request of requestID, empty if none.
+// Currently we log an error and continue.
+func S3PeersUpdateBucketPolicy(bucket string, pCh policyChange, requestID string) {
 	byts, err := json.Marshal(pCh)
 	if err != nil {
-		errorIf(err, "Failed to marshal policyChange - this is a BUG!")
+		requestIDErrorIf(requestID, err, "Failed to marshal policyChange - this is a BUG!")
 		return
 	}
-	setBPPArgs := &SetBucketPolicyPeerArgs{Bucket: bucket, PChBytes: byts}
+	setBPPArgs := &SetBucketPolicyPeerArgs{AuthRPCArgs: AuthRPCArgs{RequestID: requestID}, Bucket: bucket, PChBytes: byts}
 	errs := globalS3Peers.SendUpdate(nil, setBPPArgs)
 	for idx, err := range errs {
-		errorIf(
-			err,
+		requestIDErrorIf(
+			requestID, err,
 			"Error sending update bucket policy to %s - %v",
 			globalS3Peers[idx].addr, err,
 		)
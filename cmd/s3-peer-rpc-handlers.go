@@ -16,6 +16,8 @@
 
 package cmd
 
+import "time"
+
 // SetBucketNotificationPeerArgs - Arguments collection to SetBucketNotificationPeer RPC
 // call
 type SetBucketNotificationPeerArgs struct {
@@ -40,7 +42,11 @@ func (s3 *s3PeerAPIHandlers) SetBucketNotificationPeer(args *SetBucketNotificati
 		return err
 	}
 
-	return s3.bms.UpdateBucketNotification(args)
+	// Errors are logged on this server too, with the ID of the API
+	// request the update is made for.
+	err := s3.bms.UpdateBucketNotification(args)
+	requestIDErrorIf(args.RequestID, err, "Unable to update bucket notification of %s.", args.Bucket)
+	return err
 }
 
 // SetBucketListenerPeerArgs - Arguments collection to SetBucketListenerPeer RPC call
@@ -66,7 +72,9 @@ func (s3 *s3PeerAPIHandlers) SetBucketListenerPeer(args *SetBucketListenerPeerAr
 		return err
 	}
 
-	return s3.bms.UpdateBucketListener(args)
+	err := s3.bms.UpdateBucketListener(args)
+	requestIDErrorIf(args.RequestID, err, "Unable to update bucket listeners of %s.", args.Bucket)
+	return err
 }
 
 // EventArgs - Arguments collection for Event RPC call
@@ -114,5 +122,78 @@ func (s3 *s3PeerAPIHandlers) SetBucketPolicyPeer(args *SetBucketPolicyPeerArgs,
 		return err
 	}
 
-	return s3.bms.UpdateBucketPolicy(args)
+	err := s3.bms.UpdateBucketPolicy(args)
+	requestIDErrorIf(args.RequestID, err, "Unable to update bucket policy of %s.", args.Bucket)
+	return err
+}
+
+// InvalidateBucketCachePeerArgs - Arguments collection for
+// InvalidateBucketCachePeer RPC call
+type InvalidateBucketCachePeerArgs struct {
+	// For Auth
+	AuthRPCArgs
+
+	Bucket string
+}
+
+// BucketUpdate - implements bucket cache invalidation, the underlying
+// operation is a network call removing the bucket from the cache of
+// the peers.
+func (s *InvalidateBucketCachePeerArgs) BucketUpdate(client BucketMetaState) error {
+	return client.InvalidateBucketCache(s)
+}
+
+// tell receiving server to forget a created or deleted bucket
+func (s3 *s3PeerAPIHandlers) InvalidateBucketCachePeer(args *InvalidateBucketCachePeerArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return s3.bms.InvalidateBucketCache(args)
+}
+
+// InvalidateObjectMetaPeerArgs - Arguments collection for
+// InvalidateObjectMetaPeer RPC call
+type InvalidateObjectMetaPeerArgs struct {
+	// For Auth
+	AuthRPCArgs
+
+	Bucket string
+	Object string
+}
+
+// BucketUpdate - implements metadata store invalidation, the underlying
+// operation is a network call removing the object from the metadata
+// store of the peers.
+func (s *InvalidateObjectMetaPeerArgs) BucketUpdate(client BucketMetaState) error {
+	return client.InvalidateObjectMeta(s)
+}
+
+// tell receiving server to forget cached metadata of a written object
+func (s3 *s3PeerAPIHandlers) InvalidateObjectMetaPeer(args *InvalidateObjectMetaPeerArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return s3.bms.InvalidateObjectMeta(args)
+}
+
+// ServerTimeArgs - Arguments collection for ServerTime RPC call
+type ServerTimeArgs struct{}
+
+// ServerTimeReply - Reply of ServerTime RPC call
+type ServerTimeReply struct {
+	ServerTime time.Time
+}
+
+// tell the current time of receiving server, used to detect clock skew
+// between servers. Not authenticated as servers with skewed clocks fail
+// to login.
+func (s3 *s3PeerAPIHandlers) ServerTime(args *ServerTimeArgs, reply *ServerTimeReply) error {
+	serverTime, err := s3.bms.ServerTime()
+	if err != nil {
+		return err
+	}
+	reply.ServerTime = serverTime
+	return nil
 }
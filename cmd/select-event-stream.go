@@ -0,0 +1,100 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+)
+
+// Type of string values of event stream headers.
+const eventStreamStringHeader = 7
+
+// eventStreamHeader - name and string value of a header of an event
+// stream message.
+type eventStreamHeader struct {
+	name  string
+	value string
+}
+
+// newEventStreamMessage - encodes a message of the event stream framing
+// used by SelectObjectContent responses. A message is made of its total
+// length, the length of its headers and a CRC of these two, 4 bytes each,
+// followed by the headers, the payload and a CRC of all the preceding
+// bytes. Lengths are big endian and CRCs are CRC32 checksums.
+func newEventStreamMessage(headers []eventStreamHeader, payload []byte) []byte {
+	var hdrs bytes.Buffer
+	for _, h := range headers {
+		hdrs.WriteByte(byte(len(h.name)))
+		hdrs.WriteString(h.name)
+		hdrs.WriteByte(eventStreamStringHeader)
+		binary.Write(&hdrs, binary.BigEndian, uint16(len(h.value)))
+		hdrs.WriteString(h.value)
+	}
+
+	totalLen := 4 + 4 + 4 + hdrs.Len() + len(payload) + 4
+	msg := make([]byte, 12, totalLen)
+	binary.BigEndian.PutUint32(msg[0:], uint32(totalLen))
+	binary.BigEndian.PutUint32(msg[4:], uint32(hdrs.Len()))
+	binary.BigEndian.PutUint32(msg[8:], crc32.ChecksumIEEE(msg[:8]))
+	msg = append(msg, hdrs.Bytes()...)
+	msg = append(msg, payload...)
+	var crc [4]byte
+	binary.BigEndian.PutUint32(crc[:], crc32.ChecksumIEEE(msg))
+	return append(msg, crc[:]...)
+}
+
+// newRecordsMessage - returns a Records event carrying selected records.
+func newRecordsMessage(payload []byte) []byte {
+	return newEventStreamMessage([]eventStreamHeader{
+		{":event-type", "Records"},
+		{":content-type", "application/octet-stream"},
+		{":message-type", "event"},
+	}, payload)
+}
+
+// newStatsMessage - returns a Stats event with the number of bytes
+// scanned, processed and returned.
+func newStatsMessage(scanned, processed, returned int64) []byte {
+	payload := fmt.Sprintf("<Stats><BytesScanned>%d</BytesScanned><BytesProcessed>%d</BytesProcessed><BytesReturned>%d</BytesReturned></Stats>",
+		scanned, processed, returned)
+	return newEventStreamMessage([]eventStreamHeader{
+		{":event-type", "Stats"},
+		{":content-type", "text/xml"},
+		{":message-type", "event"},
+	}, []byte(payload))
+}
+
+// newEndMessage - returns the End event closing a successful response.
+func newEndMessage() []byte {
+	return newEventStreamMessage([]eventStreamHeader{
+		{":event-type", "End"},
+		{":message-type", "event"},
+	}, nil)
+}
+
+// newErrorMessage - returns an error message ending a response which
+// failed after it was started.
+func newErrorMessage(code, message string) []byte {
+	return newEventStreamMessage([]eventStreamHeader{
+		{":error-code", code},
+		{":error-message", message},
+		{":message-type", "error"},
+	}, nil)
+}
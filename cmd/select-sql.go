@@ -0,0 +1,534 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// This file implements the subset of SQL supported by SelectObjectContent
+//
+//   SELECT * | column [, column ...] FROM S3Object [[AS] alias]
+//       [WHERE condition] [LIMIT number]
+//
+// Columns are referred to by name, when the header of the CSV object is
+// used, or by position as _1, _2 and so on, optionally qualified by the
+// alias. Conditions compare columns and literals with =, !=, <>, <, <=,
+// > and >=, combined with AND, OR, NOT and parentheses. Values are
+// compared as numbers if both are numbers, as strings otherwise.
+
+// sqlTokenType - type of a token of a SQL expression.
+type sqlTokenType int
+
+const (
+	sqlTokenEOF sqlTokenType = iota
+	sqlTokenIdent
+	sqlTokenQuotedIdent
+	sqlTokenString
+	sqlTokenNumber
+	sqlTokenOperator
+)
+
+// sqlToken - token of a SQL expression.
+type sqlToken struct {
+	typ   sqlTokenType
+	value string
+}
+
+// isKeyword - returns true if the token is the given keyword.
+func (t sqlToken) isKeyword(keyword string) bool {
+	return t.typ == sqlTokenIdent && strings.EqualFold(t.value, keyword)
+}
+
+// isOperator - returns true if the token is the given operator.
+func (t sqlToken) isOperator(op string) bool {
+	return t.typ == sqlTokenOperator && t.value == op
+}
+
+// tokenizeSQL - splits a SQL expression into tokens.
+func tokenizeSQL(query string) ([]sqlToken, error) {
+	var tokens []sqlToken
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '\'' || c == '"':
+			// String literal or quoted identifier, quotes are
+			// escaped by doubling them.
+			var value []rune
+			j := i + 1
+			for ; j < len(runes); j++ {
+				if runes[j] == c {
+					if j+1 < len(runes) && runes[j+1] == c {
+						value = append(value, c)
+						j++
+						continue
+					}
+					break
+				}
+				value = append(value, runes[j])
+			}
+			if j == len(runes) {
+				return nil, fmt.Errorf("Unterminated quote at position %d", i)
+			}
+			typ := sqlTokenString
+			if c == '"' {
+				typ = sqlTokenQuotedIdent
+			}
+			tokens = append(tokens, sqlToken{typ, string(value)})
+			i = j + 1
+		case unicode.IsDigit(c):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, sqlToken{sqlTokenNumber, string(runes[i:j])})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, sqlToken{sqlTokenIdent, string(runes[i:j])})
+			i = j
+		default:
+			op := string(c)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "<=", ">=", "<>", "!=":
+					op = two
+				}
+			}
+			switch op {
+			case "=", "<", ">", "<=", ">=", "<>", "!=", "(", ")", ",", ".", "*", "-":
+			default:
+				return nil, fmt.Errorf("Unexpected character %q at position %d", c, i)
+			}
+			tokens = append(tokens, sqlToken{sqlTokenOperator, op})
+			i += len([]rune(op))
+		}
+	}
+	return append(tokens, sqlToken{typ: sqlTokenEOF}), nil
+}
+
+// sqlColumn - reference to a field of a record, by name or by position.
+type sqlColumn struct {
+	qualifier string // Alias qualifying the column, if any.
+	name      string
+	quoted    bool // Quoted names are case sensitive.
+	index     int  // Position of the field, set by resolve.
+}
+
+func (c *sqlColumn) String() string {
+	if c.quoted {
+		return `"` + c.name + `"`
+	}
+	return c.name
+}
+
+// value - returns the field of record referred to, false if the record
+// has no such field.
+func (c *sqlColumn) value(record []string) (string, bool) {
+	if c.index < 0 || c.index >= len(record) {
+		return "", false
+	}
+	return record[c.index], true
+}
+
+// sqlOperand - column or literal compared by a condition.
+type sqlOperand struct {
+	column  *sqlColumn
+	literal string
+}
+
+func (o sqlOperand) value(record []string) (string, bool) {
+	if o.column != nil {
+		return o.column.value(record)
+	}
+	return o.literal, true
+}
+
+// sqlCondition - condition evaluated against each record.
+type sqlCondition interface {
+	matches(record []string) bool
+}
+
+type sqlAnd struct{ left, right sqlCondition }
+
+func (c sqlAnd) matches(record []string) bool {
+	return c.left.matches(record) && c.right.matches(record)
+}
+
+type sqlOr struct{ left, right sqlCondition }
+
+func (c sqlOr) matches(record []string) bool {
+	return c.left.matches(record) || c.right.matches(record)
+}
+
+type sqlNot struct{ cond sqlCondition }
+
+func (c sqlNot) matches(record []string) bool {
+	return !c.cond.matches(record)
+}
+
+type sqlComparison struct {
+	op          string
+	left, right sqlOperand
+}
+
+// matches - compares the operands as numbers if both are numbers, as
+// strings otherwise. Comparisons with missing fields never match.
+func (c sqlComparison) matches(record []string) bool {
+	left, ok := c.left.value(record)
+	if !ok {
+		return false
+	}
+	right, ok := c.right.value(record)
+	if !ok {
+		return false
+	}
+	var cmp int
+	leftNum, lerr := strconv.ParseFloat(strings.TrimSpace(left), 64)
+	rightNum, rerr := strconv.ParseFloat(strings.TrimSpace(right), 64)
+	if lerr == nil && rerr == nil {
+		switch {
+		case leftNum < rightNum:
+			cmp = -1
+		case leftNum > rightNum:
+			cmp = 1
+		}
+	} else {
+		cmp = strings.Compare(left, right)
+	}
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case "!=", "<>":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+// sqlSelect - parsed SELECT statement.
+type sqlSelect struct {
+	alias   string
+	columns []*sqlColumn // Projected columns, nil for *.
+	where   sqlCondition // nil matches all the records.
+	limit   int64        // Negative if unlimited.
+
+	// All columns referred to, resolved once the header is known.
+	refs []*sqlColumn
+}
+
+// sqlParser - recursive descent parser of SELECT statements.
+type sqlParser struct {
+	tokens []sqlToken
+	pos    int
+	refs   []*sqlColumn
+}
+
+func (p *sqlParser) peek() sqlToken {
+	return p.tokens[p.pos]
+}
+
+func (p *sqlParser) next() sqlToken {
+	t := p.tokens[p.pos]
+	if t.typ != sqlTokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *sqlParser) expectKeyword(keyword string) error {
+	if t := p.next(); !t.isKeyword(keyword) {
+		return fmt.Errorf("Expected %s, found %q", keyword, t.value)
+	}
+	return nil
+}
+
+// Keywords which cannot be used as an alias or an unquoted column name.
+var sqlReservedWords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "LIMIT": true,
+	"AND": true, "OR": true, "NOT": true, "AS": true,
+}
+
+func isSQLReserved(t sqlToken) bool {
+	return t.typ == sqlTokenIdent && sqlReservedWords[strings.ToUpper(t.value)]
+}
+
+// parseSQLSelect - parses a SELECT statement over S3Object.
+func parseSQLSelect(query string) (*sqlSelect, error) {
+	tokens, err := tokenizeSQL(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &sqlParser{tokens: tokens}
+	stmt := &sqlSelect{limit: -1}
+
+	if err = p.expectKeyword("SELECT"); err != nil {
+		return nil, err
+	}
+	if p.peek().isOperator("*") {
+		p.next()
+	} else {
+		for {
+			column, cerr := p.parseColumn()
+			if cerr != nil {
+				return nil, cerr
+			}
+			stmt.columns = append(stmt.columns, column)
+			if !p.peek().isOperator(",") {
+				break
+			}
+			p.next()
+		}
+	}
+
+	if err = p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+	if err = p.expectKeyword("S3Object"); err != nil {
+		return nil, err
+	}
+	if p.peek().isKeyword("AS") {
+		p.next()
+		if t := p.peek(); t.typ != sqlTokenIdent || isSQLReserved(t) {
+			return nil, fmt.Errorf("Expected alias after AS, found %q", t.value)
+		}
+	}
+	if t := p.peek(); t.typ == sqlTokenIdent && !isSQLReserved(t) {
+		stmt.alias = p.next().value
+	}
+
+	if p.peek().isKeyword("WHERE") {
+		p.next()
+		if stmt.where, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peek().isKeyword("LIMIT") {
+		p.next()
+		t := p.next()
+		if t.typ != sqlTokenNumber {
+			return nil, fmt.Errorf("Expected number after LIMIT, found %q", t.value)
+		}
+		if stmt.limit, err = strconv.ParseInt(t.value, 10, 64); err != nil {
+			return nil, fmt.Errorf("Invalid LIMIT %s", t.value)
+		}
+	}
+
+	if t := p.next(); t.typ != sqlTokenEOF {
+		return nil, fmt.Errorf("Unexpected %q", t.value)
+	}
+
+	stmt.refs = p.refs
+	for _, column := range stmt.refs {
+		if column.qualifier != "" && !strings.EqualFold(column.qualifier, stmt.alias) &&
+			!strings.EqualFold(column.qualifier, "S3Object") {
+			return nil, fmt.Errorf("Unknown alias %s of column %s", column.qualifier, column)
+		}
+	}
+	return stmt, nil
+}
+
+// parseColumn - parses a column optionally qualified by an alias.
+func (p *sqlParser) parseColumn() (*sqlColumn, error) {
+	column := &sqlColumn{index: -1}
+	name := func() error {
+		t := p.next()
+		switch {
+		case t.typ == sqlTokenQuotedIdent:
+			column.name, column.quoted = t.value, true
+		case t.typ == sqlTokenIdent && !isSQLReserved(t):
+			column.name = t.value
+		default:
+			return fmt.Errorf("Expected column name, found %q", t.value)
+		}
+		return nil
+	}
+	if err := name(); err != nil {
+		return nil, err
+	}
+	if p.peek().isOperator(".") {
+		p.next()
+		column.qualifier = column.name
+		if err := name(); err != nil {
+			return nil, err
+		}
+	}
+	p.refs = append(p.refs, column)
+	return column, nil
+}
+
+// parseOr - parses conditions combined with OR.
+func (p *sqlParser) parseOr() (sqlCondition, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = sqlOr{left, right}
+	}
+	return left, nil
+}
+
+// parseAnd - parses conditions combined with AND.
+func (p *sqlParser) parseAnd() (sqlCondition, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("AND") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = sqlAnd{left, right}
+	}
+	return left, nil
+}
+
+// parseNot - parses a negated condition, a condition in parentheses or
+// a comparison.
+func (p *sqlParser) parseNot() (sqlCondition, error) {
+	if p.peek().isKeyword("NOT") {
+		p.next()
+		cond, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return sqlNot{cond}, nil
+	}
+	if p.peek().isOperator("(") {
+		p.next()
+		cond, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); !t.isOperator(")") {
+			return nil, fmt.Errorf("Expected ), found %q", t.value)
+		}
+		return cond, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	op := p.next()
+	if op.typ != sqlTokenOperator {
+		return nil, fmt.Errorf("Expected comparison operator, found %q", op.value)
+	}
+	switch op.value {
+	case "=", "!=", "<>", "<", "<=", ">", ">=":
+	default:
+		return nil, fmt.Errorf("Expected comparison operator, found %q", op.value)
+	}
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return sqlComparison{op.value, left, right}, nil
+}
+
+// parseOperand - parses a column, a string or a number.
+func (p *sqlParser) parseOperand() (sqlOperand, error) {
+	t := p.peek()
+	switch {
+	case t.typ == sqlTokenString:
+		p.next()
+		return sqlOperand{literal: t.value}, nil
+	case t.typ == sqlTokenNumber:
+		p.next()
+		return sqlOperand{literal: t.value}, nil
+	case t.isOperator("-"):
+		p.next()
+		if n := p.next(); n.typ == sqlTokenNumber {
+			return sqlOperand{literal: "-" + n.value}, nil
+		}
+		return sqlOperand{}, fmt.Errorf("Expected number after -")
+	}
+	column, err := p.parseColumn()
+	if err != nil {
+		return sqlOperand{}, err
+	}
+	return sqlOperand{column: column}, nil
+}
+
+// resolve - resolves the columns referred to into positions of the
+// fields of a record, header holds the column names of the object, if
+// any. Positional names _1, _2 and so on are always allowed.
+func (s *sqlSelect) resolve(header []string) error {
+	for _, column := range s.refs {
+		column.index = -1
+		for i, name := range header {
+			if name == column.name || (!column.quoted && strings.EqualFold(name, column.name)) {
+				column.index = i
+				break
+			}
+		}
+		if column.index >= 0 {
+			continue
+		}
+		if !column.quoted && strings.HasPrefix(column.name, "_") {
+			if n, err := strconv.Atoi(column.name[1:]); err == nil && n > 0 {
+				column.index = n - 1
+				continue
+			}
+		}
+		return fmt.Errorf("Column %s not found", column)
+	}
+	return nil
+}
+
+// matches - returns true if record satisfies the WHERE condition.
+func (s *sqlSelect) matches(record []string) bool {
+	return s.where == nil || s.where.matches(record)
+}
+
+// project - returns the fields of record selected, missing fields
+// are returned empty.
+func (s *sqlSelect) project(record []string) []string {
+	if s.columns == nil {
+		return record
+	}
+	fields := make([]string, len(s.columns))
+	for i, column := range s.columns {
+		fields[i], _ = column.value(record)
+	}
+	return fields
+}
@@ -0,0 +1,118 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Tests parsing and evaluating select expressions.
+func TestParseSQLSelect(t *testing.T) {
+	header := []string{"name", "age", "city"}
+	records := [][]string{
+		{"alice", "34", "Paris"},
+		{"bob", "9", "Berlin"},
+		{"carol", "41", "Berlin"},
+		{"dave", "", "O'Hare"},
+	}
+
+	testCases := []struct {
+		query     string
+		header    []string
+		expected  [][]string
+		expectErr bool
+	}{
+		// Test case - 1.
+		// All records and fields.
+		{"SELECT * FROM S3Object", header, records, false},
+		// Test case - 2.
+		// Numeric comparison, 9 is less than 30 although "9" > "30".
+		{"select name, age from S3Object where age > 30", header,
+			[][]string{{"alice", "34"}, {"carol", "41"}}, false},
+		// Test case - 3.
+		// Aliased and positional columns, string comparison.
+		{"SELECT s._1 FROM S3Object AS s WHERE s.city = 'Berlin'", header,
+			[][]string{{"bob"}, {"carol"}}, false},
+		// Test case - 4.
+		// AND, OR, NOT and parentheses.
+		{"SELECT name FROM S3Object s WHERE NOT (city = 'Paris' OR age < 10) AND age <> ''", header,
+			[][]string{{"carol"}}, false},
+		// Test case - 5.
+		// Escaped quotes and quoted column names.
+		{`SELECT "name" FROM S3Object WHERE city = 'O''Hare'`, header,
+			[][]string{{"dave"}}, false},
+		// Test case - 6.
+		// LIMIT is applied by the handler, parsing only.
+		{"SELECT * FROM S3Object LIMIT 2", header, records, false},
+		// Test case - 7.
+		// Positional columns without a header.
+		{"SELECT _3 FROM S3Object WHERE _2 >= 34", nil,
+			[][]string{{"Paris"}, {"Berlin"}}, false},
+		// Test case - 8.
+		// Unknown column.
+		{"SELECT country FROM S3Object", header, nil, true},
+		// Test case - 9.
+		// Quoted column names are case sensitive.
+		{`SELECT "NAME" FROM S3Object`, header, nil, true},
+		// Test case - 10.
+		// Unknown alias.
+		{"SELECT t.name FROM S3Object s", header, nil, true},
+		// Test case - 11.
+		// Other tables are not supported.
+		{"SELECT * FROM employees", header, nil, true},
+		// Test case - 12.
+		// Unterminated string.
+		{"SELECT * FROM S3Object WHERE city = 'Paris", header, nil, true},
+		// Test case - 13.
+		// Trailing tokens.
+		{"SELECT * FROM S3Object WHERE age > 30 age", header, nil, true},
+		// Test case - 14.
+		// Invalid limit.
+		{"SELECT * FROM S3Object LIMIT ten", header, nil, true},
+	}
+
+	for i, testCase := range testCases {
+		stmt, err := parseSQLSelect(testCase.query)
+		if err == nil {
+			err = stmt.resolve(testCase.header)
+		}
+		if testCase.expectErr {
+			if err == nil {
+				t.Errorf("Test %d: Expected %q to fail", i+1, testCase.query)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d: Unexpected error for %q: %v", i+1, testCase.query, err)
+			continue
+		}
+		var selected [][]string
+		for _, record := range records {
+			if stmt.matches(record) {
+				selected = append(selected, stmt.project(record))
+			}
+		}
+		if !reflect.DeepEqual(selected, testCase.expected) {
+			t.Errorf("Test %d: Expected %q to select %v, got %v", i+1, testCase.query, testCase.expected, selected)
+		}
+	}
+
+	if stmt, err := parseSQLSelect("SELECT * FROM S3Object LIMIT 2"); err != nil || stmt.limit != 2 {
+		t.Errorf("Expected LIMIT 2 to be parsed, got %v", err)
+	}
+}
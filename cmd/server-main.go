@@ -17,26 +17,208 @@
 package cmd
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/url"
 	"os"
 	"path"
+	"path/filepath"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"runtime"
 
 	"github.com/minio/cli"
+	"github.com/minio/dsync"
+	"github.com/minio/mc/pkg/console"
 )
 
 var serverFlags = []cli.Flag{
 	cli.StringFlag{
 		Name:  "address",
 		Value: ":9000",
-		Usage: `Bind to a specific IP:PORT. Defaults to ":9000".`,
+		Usage: `Bind to a specific IP:PORT, or to several comma separated IP:PORTs. Defaults to ":9000".`,
+	},
+	cli.BoolFlag{
+		Name:  "read-only",
+		Usage: "Serve only reads, mutating S3 API requests are rejected with MethodNotAllowed.",
+	},
+	cli.BoolFlag{
+		Name:  "auto-format-upgrade",
+		Usage: "Upgrade disks on an older backend format version, if a quorum of disks is on the newer version.",
+	},
+	cli.StringFlag{
+		Name:  "fs-wal-dir",
+		Usage: "Enable a write-ahead log in this directory for crash consistency of FS mode.",
+	},
+	cli.BoolFlag{
+		Name:  "ignore-signature-region",
+		Usage: "Accept signatures calculated for any region. Only for internal deployments where region is not used.",
+	},
+	cli.IntFlag{
+		Name:  "max-parts",
+		Value: maxPartID,
+		Usage: "Maximum number of parts per multipart upload.",
+	},
+	cli.StringFlag{
+		Name:  "min-part-size",
+		Value: "5MiB",
+		Usage: "Minimum size of all the parts of a multipart upload except the last part.",
+	},
+	cli.StringFlag{
+		Name:  "max-part-size",
+		Value: "5GiB",
+		Usage: "Maximum size of a part of a multipart upload.",
+	},
+	cli.StringFlag{
+		Name:  "copy-part-size",
+		Value: "64MiB",
+		Usage: "Copy objects larger than this on the server as multipart uploads of parts of this size, streamed one part at a time.",
+	},
+	cli.BoolFlag{
+		Name:  "async-delete",
+		Usage: "Reclaim space of deleted objects in background in erasure coded mode.",
+	},
+	cli.BoolFlag{
+		Name:  "require-full-mesh",
+		Usage: "Fail startup of distributed setup if any of the remote endpoints is unreachable.",
+	},
+	cli.BoolFlag{
+		Name:  "reject-network-disks",
+		Usage: "Fail startup if a local disk is on a network or FUSE filesystem, such as NFS, instead of only warning.",
+	},
+	cli.BoolFlag{
+		Name:  "self-test",
+		Usage: "Write, read back and delete a canary object on each local disk at startup, failing startup if any disk errors.",
+	},
+	cli.DurationFlag{
+		Name:  "write-laggard-timeout",
+		Usage: "Complete writes without disks slower than this once write quorum is reached, their shards are healed in background. Disabled by default.",
+	},
+	cli.BoolFlag{
+		Name:  "startup-timing",
+		Usage: "Log the duration of each phase of the startup sequence along with a summary.",
+	},
+	cli.DurationFlag{
+		Name:  "lock-backoff-base",
+		Usage: "Initial delay between retries of distributed lock acquisition, grows exponentially up to --lock-backoff-max. Uses built-in randomized back-off by default.",
+	},
+	cli.DurationFlag{
+		Name:  "lock-backoff-max",
+		Value: time.Second,
+		Usage: "Maximum delay between retries of distributed lock acquisition.",
+	},
+	cli.Float64Flag{
+		Name:  "lock-backoff-jitter",
+		Value: 0.5,
+		Usage: "Fraction of each delay between retries of distributed lock acquisition which is randomized, between 0 and 1.",
+	},
+	cli.DurationFlag{
+		Name:  "lock-timeout",
+		Value: 30 * time.Second,
+		Usage: "Time requests wait for locks on buckets and objects before failing with 503. 0 waits indefinitely.",
+	},
+	cli.IntFlag{
+		Name:  "peer-rpc-retries",
+		Value: 3,
+		Usage: "Number of retries with exponential back-off of calls to peers failing with connection errors. 0 disables retries.",
+	},
+	cli.DurationFlag{
+		Name:  "peer-rpc-timeout",
+		Value: 10 * time.Second,
+		Usage: "Time after which calls to peers failing with connection errors are not retried anymore.",
+	},
+	cli.IntFlag{
+		Name:  "max-connections",
+		Usage: "Maximum number of open client connections, new connections beyond it are refused with 503 until others close. Connections between servers are exempt. Defaults to half the limit of open files, 0 means unlimited.",
+	},
+	cli.IntFlag{
+		Name:  "read-ahead-blocks",
+		Usage: "Number of erasure blocks read ahead of the client by GetObject in erasure coded mode. 0 disables read-ahead.",
+	},
+	cli.IntFlag{
+		Name:  "read-ahead-max-blocks",
+		Value: 100,
+		Usage: "Maximum number of erasure blocks buffered by read-ahead of all GetObject requests, requests beyond it are served without read-ahead.",
+	},
+	cli.StringFlag{
+		Name:  "metadata-store-dir",
+		Usage: "Directory on a fast local disk to cache object metadata in, for faster listings and HEAD requests in erasure coded mode.",
+	},
+	cli.DurationFlag{
+		Name:  "shutdown-timeout",
+		Value: 5 * time.Second,
+		Usage: "Time in-flight requests are drained for on SIGTERM or service stop, before their connections are closed.",
+	},
+	cli.DurationFlag{
+		Name:  "read-header-timeout",
+		Value: 30 * time.Second,
+		Usage: "Time clients have to send the headers of a request before their connection is closed. 0 disables the timeout.",
+	},
+	cli.DurationFlag{
+		Name:  "read-timeout",
+		Usage: "Time clients have to send a whole request including its body. Disabled by default such that large uploads are not cut off.",
+	},
+	cli.DurationFlag{
+		Name:  "write-timeout",
+		Usage: "Time to send a whole response including its body. Disabled by default such that large downloads are not cut off.",
+	},
+	cli.DurationFlag{
+		Name:  "idle-timeout",
+		Value: 5 * time.Minute,
+		Usage: "Time keep-alive connections are kept open waiting for the next request. 0 disables the timeout.",
+	},
+	cli.BoolFlag{
+		Name:  "metrics-anonymous",
+		Usage: "Serve Prometheus metrics on /minio/metrics without admin credentials.",
+	},
+	cli.BoolFlag{
+		Name:  "trusted-proxy",
+		Usage: "Take client addresses checked against ipAllowList of config from X-Forwarded-For, only behind a reverse proxy setting it.",
+	},
+	cli.BoolFlag{
+		Name:  "require-client-cert",
+		Usage: "Require TLS client certificates signed by a CA in the client-CAs certs dir on all connections, including those between servers.",
+	},
+	cli.BoolFlag{
+		Name:  "compress",
+		Usage: "Compress XML responses such as listings with gzip or deflate for clients accepting it. Objects are never compressed.",
+	},
+	cli.BoolFlag{
+		Name:  "h2c",
+		Usage: "Serve HTTP/2 on cleartext connections to clients with prior knowledge, HTTP/2 over TLS is always enabled.",
+	},
+	cli.StringFlag{
+		Name:  "bandwidth",
+		Usage: "Maximum aggregate bandwidth of object downloads per second, e.g. 100MiB. Unlimited by default.",
+	},
+	cli.StringFlag{
+		Name:  "bandwidth-ingress",
+		Usage: "Maximum aggregate bandwidth of object uploads per second, e.g. 100MiB. Unlimited by default.",
+	},
+	cli.DurationFlag{
+		Name:  "lifecycle-interval",
+		Value: time.Hour,
+		Usage: "Interval between scans deleting objects expired by the lifecycle rules of their bucket.",
+	},
+	cli.StringFlag{
+		Name:  "endpoints-file",
+		Usage: "Read the endpoints from this file, one per line, instead of from the command line.",
+	},
+	cli.BoolFlag{
+		Name:  "validate",
+		Usage: "Validate the command line, ports and certificates, print a summary and exit without starting the server.",
+	},
+	cli.StringFlag{
+		Name:  "fs-checksum",
+		Usage: "Checksum objects in FS mode with this algorithm, blake2b or sha256, verified on reads of whole objects. Disabled by default.",
 	},
 }
 
@@ -50,6 +232,7 @@ var serverCmd = cli.Command{
 
 USAGE:
   minio {{.Name}} [FLAGS] PATH [PATH...]
+  minio {{.Name}} [FLAGS] --endpoints-file FILE
 
 FLAGS:
   {{range .Flags}}{{.}}
@@ -58,10 +241,20 @@ ENVIRONMENT VARIABLES:
   ACCESS:
      MINIO_ACCESS_KEY: Custom username or access key of 5 to 20 characters in length.
      MINIO_SECRET_KEY: Custom password or secret key of 8 to 40 characters in length.
+     MINIO_ACCESS_KEY_FILE, MINIO_SECRET_KEY_FILE: Files to read the access and secret keys from instead, e.g. Docker secrets.
 
   BROWSER:
      MINIO_BROWSER: To disable web browser access, set this value to "off".
 
+  REGION:
+     MINIO_REGION: Region of the server, requests are signed for it and it is the location of all buckets. Defaults to the region in config.json.
+
+  ADDRESS:
+     MINIO_ADDRESS: Bind to a specific IP:PORT, or to several comma separated IP:PORTs, used unless --address is set.
+
+  STORAGE CLASS:
+     MINIO_STORAGE_CLASS_STANDARD: Parity of erasure coded objects as EC:<parity>, between 2 and half the disks of an erasure set (default).
+
 EXAMPLES:
   1. Start minio server on "/home/shared" directory.
       $ minio {{.Name}} /home/shared
@@ -69,17 +262,38 @@ EXAMPLES:
   2. Start minio server bound to a specific IP:PORT.
       $ minio {{.Name}} --address 192.168.1.101:9000 /home/shared
 
-  3. Start erasure coded minio server on a 12 disks server.
+  3. Start minio server on "/home/shared" directory with a write-ahead log on a separate disk.
+      $ minio {{.Name}} --fs-wal-dir /mnt/wal /home/shared
+
+  4. Start erasure coded minio server on a 12 disks server.
       $ minio {{.Name}} /mnt/export1/ /mnt/export2/ /mnt/export3/ /mnt/export4/ \
           /mnt/export5/ /mnt/export6/ /mnt/export7/ /mnt/export8/ /mnt/export9/ \
           /mnt/export10/ /mnt/export11/ /mnt/export12/
 
-  4. Start erasure coded distributed minio server on a 4 node setup with 1 drive each. Run following commands on all the 4 nodes.
+  5. Start erasure coded distributed minio server on a 4 node setup with 1 drive each. Run following commands on all the 4 nodes.
       $ export MINIO_ACCESS_KEY=minio
       $ export MINIO_SECRET_KEY=miniostorage
       $ minio {{.Name}} http://192.168.1.11/mnt/export/ http://192.168.1.12/mnt/export/ \
           http://192.168.1.13/mnt/export/ http://192.168.1.14/mnt/export/
 
+  6. Start minio server on "/home/shared" directory serving only reads.
+      $ minio {{.Name}} --read-only /home/shared
+
+  7. Start minio server bound to both an IPv4 and an IPv6 address.
+      $ minio {{.Name}} --address 192.168.1.101:9000,[2001:db8::101]:9000 /home/shared
+
+  8. Start minio server serving HTTP/2 without TLS to proxies in front of it.
+      $ minio {{.Name}} --h2c /home/shared
+
+  9. Start minio server limiting all object downloads together to 50MiB per second.
+      $ minio {{.Name}} --bandwidth 50MiB /home/shared
+
+  10. Validate the command line of an erasure coded minio server without starting it.
+      $ minio {{.Name}} --validate /mnt/export{1...12}
+
+  11. Start erasure coded minio server on the endpoints listed in a file.
+      $ minio {{.Name}} --endpoints-file /etc/minio/endpoints
+
 `,
 }
 
@@ -87,24 +301,46 @@ type serverCmdConfig struct {
 	serverAddr   string
 	endpoints    []*url.URL
 	storageDisks []StorageAPI
+	zones        *endpointZones
 }
 
 // Parse an array of end-points (from the command line)
 func parseStorageEndpoints(eps []string) (endpoints []*url.URL, err error) {
+	endpoints, _, err = parseStorageEndpointsWithZones(eps)
+	return endpoints, err
+}
+
+// Parse an array of end-points (from the command line) along with the
+// zones they are tagged with.
+func parseStorageEndpointsWithZones(args []string) (endpoints []*url.URL, zones []string, err error) {
+	var eps []string
+	for _, arg := range args {
+		var expanded []string
+		expanded, err = expandEndpointEllipses(arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		eps = append(eps, expanded...)
+	}
 	for _, ep := range eps {
 		if ep == "" {
-			return nil, errInvalidArgument
+			return nil, nil, errInvalidArgument
 		}
 		var u *url.URL
 		u, err = url.Parse(ep)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+		var zone string
+		zone, err = parseEndpointZone(u)
+		if err != nil {
+			return nil, nil, err
 		}
 		if u.Host != "" {
 			_, port, err := net.SplitHostPort(u.Host)
 			// Ignore the missing port error as the default port can be globalMinioPort.
 			if err != nil && !strings.Contains(err.Error(), "missing port in address") {
-				return nil, err
+				return nil, nil, err
 			}
 
 			if globalMinioHost == "" {
@@ -112,7 +348,7 @@ func parseStorageEndpoints(eps []string) (endpoints []*url.URL, err error) {
 				// we return error as port is configurable only
 				// using "--address :port"
 				if port != "" {
-					return nil, fmt.Errorf("Invalid Argument %s, port configurable using --address :<port>", u.Host)
+					return nil, nil, fmt.Errorf("Invalid Argument %s, port configurable using --address :<port>", u.Host)
 				}
 				u.Host = net.JoinHostPort(u.Host, globalMinioPort)
 			} else {
@@ -120,13 +356,85 @@ func parseStorageEndpoints(eps []string) (endpoints []*url.URL, err error) {
 				// i.e if "--address host:port" is specified
 				// port info in u.Host is mandatory else return error.
 				if port == "" {
-					return nil, fmt.Errorf("Invalid Argument %s, port mandatory when --address <host>:<port> is used", u.Host)
+					return nil, nil, fmt.Errorf("Invalid Argument %s, port mandatory when --address <host>:<port> is used", u.Host)
 				}
 			}
 		}
 		endpoints = append(endpoints, u)
+		zones = append(zones, zone)
 	}
-	return endpoints, nil
+	return endpoints, zones, nil
+}
+
+// Matches ranges of the form `{1...24}` in endpoints.
+var endpointEllipsisRegexp = regexp.MustCompile(`\{([0-9]+)\.\.\.([0-9]+)\}`)
+
+// expandEndpointEllipses - expands ranges of the form `{1...24}` in an
+// endpoint, e.g. `/mnt/d{1...3}` into `/mnt/d1`, `/mnt/d2` and
+// `/mnt/d3`. Ranges starting with a zero padded number are padded to
+// its width.
+func expandEndpointEllipses(ep string) ([]string, error) {
+	loc := endpointEllipsisRegexp.FindStringSubmatchIndex(ep)
+	if loc == nil {
+		return []string{ep}, nil
+	}
+	startStr, endStr := ep[loc[2]:loc[3]], ep[loc[4]:loc[5]]
+	start, err := strconv.Atoi(startStr)
+	if err != nil {
+		return nil, err
+	}
+	end, err := strconv.Atoi(endStr)
+	if err != nil {
+		return nil, err
+	}
+	if start > end {
+		return nil, fmt.Errorf("Invalid range %s in %s", ep[loc[0]:loc[1]], ep)
+	}
+	width := 0
+	if len(startStr) > 1 && startStr[0] == '0' {
+		width = len(startStr)
+	}
+
+	// Ranges following this one are expanded recursively.
+	suffixes, err := expandEndpointEllipses(ep[loc[1]:])
+	if err != nil {
+		return nil, err
+	}
+	var eps []string
+	for i := start; i <= end; i++ {
+		for _, suffix := range suffixes {
+			eps = append(eps, fmt.Sprintf("%s%0*d%s", ep[:loc[0]], width, i, suffix))
+		}
+	}
+	return eps, nil
+}
+
+// getEnvCredential - returns credentials set in the environment by
+// MINIO_ACCESS_KEY and MINIO_SECRET_KEY, or read from the files named
+// by MINIO_ACCESS_KEY_FILE and MINIO_SECRET_KEY_FILE such as mounted
+// Docker and Kubernetes secrets. Returns false if either is not set.
+func getEnvCredential() (cred credential, isEnvCred bool, err error) {
+	if cred.AccessKey, err = getEnvOrFile("MINIO_ACCESS_KEY"); err != nil {
+		return credential{}, false, err
+	}
+	if cred.SecretKey, err = getEnvOrFile("MINIO_SECRET_KEY"); err != nil {
+		return credential{}, false, err
+	}
+	return cred, cred.AccessKey != "" && cred.SecretKey != "", nil
+}
+
+// getEnvOrFile - returns contents of the file named by env variable
+// key_FILE without trailing whitespace if set, the value of env
+// variable key otherwise.
+func getEnvOrFile(key string) (string, error) {
+	if file := os.Getenv(key + "_FILE"); file != "" {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRightFunc(string(data), unicode.IsSpace), nil
+	}
+	return os.Getenv(key), nil
 }
 
 // initServerConfig initialize server config.
@@ -139,22 +447,60 @@ func initServerConfig(c *cli.Context) {
 	loadRootCAs()
 
 	// When credentials inherited from the env, server cmd has to save them in the disk
-	if os.Getenv("MINIO_ACCESS_KEY") != "" && os.Getenv("MINIO_SECRET_KEY") != "" {
-		// Env credentials are already loaded in serverConfig, just save in the disk
+	cred, isEnvCred, err := getEnvCredential()
+	fatalIf(err, "Unable to read credentials from the environment.")
+	if isEnvCred {
+		serverConfig.SetCredential(cred)
 		err = serverConfig.Save()
 		fatalIf(err, "Unable to save credentials in the disk.")
 	}
+	// Region inherited from the env is saved in the disk as well.
+	if region := os.Getenv("MINIO_REGION"); region != "" {
+		serverConfig.SetRegion(region)
+		err = serverConfig.Save()
+		fatalIf(err, "Unable to save region in the disk.")
+	}
+	if !isAccessKeyValid(serverConfig.GetCredential().AccessKey) {
+		fatalIf(errInvalidArgument, "Invalid access key. Accept only a string starting with a alphabetic and containing from 5 to 20 characters.")
+	}
+	if !isSecretKeyValid(serverConfig.GetCredential().SecretKey) {
+		fatalIf(errInvalidArgument, "Invalid secret key. Accept only a string containing from 8 to 40 characters.")
+	}
+
+	// Fail early on a malformed bucket template, instead of on the
+	// first bucket creation.
+	fatalIf(serverConfig.GetBucketTemplate().validate(), "Invalid bucket template in config.")
+	fatalIf(serverConfig.GetWORMBuckets().validate(), "Invalid retention period of WORM buckets in config.")
+	fatalIf(serverConfig.GetBucketLifecycles().validate(), "Invalid lifecycle rules of buckets in config.")
+	fatalIf(serverConfig.GetBucketCorsAll().validate(), "Invalid CORS rules of buckets in config.")
+	fatalIf(serverConfig.GetIPAllowList().validate(), "Invalid address ranges of allowed clients in config.")
+
+	// Disks avoided before a restart stay excluded from new writes.
+	loadAvoidedDisks()
+
+	// Limits tuned automatically are overridden through the env.
+	maxOpenFiles, err := parseMaxOpenFiles(os.Getenv("MINIO_MAX_OPEN_FILES"))
+	fatalIf(err, "Invalid MINIO_MAX_OPEN_FILES.")
+	maxMemory, err := parseMaxMemory(os.Getenv("MINIO_MAX_MEMORY"))
+	fatalIf(err, "Invalid MINIO_MAX_MEMORY.")
+	if msg := getLimitsWarning(maxOpenFiles, maxMemory); msg != "" {
+		console.Print(colorRed(msg))
+	}
 
 	// Set maxOpenFiles, This is necessary since default operating
 	// system limits of 1024, 2048 are not enough for Minio server.
-	setMaxOpenFiles()
+	err = setMaxOpenFiles(maxOpenFiles)
+	if maxOpenFiles > 0 {
+		fatalIf(err, "Unable to set maximum open files to %d.", maxOpenFiles)
+	}
 
 	// Set maxMemory, This is necessary since default operating
 	// system limits might be changed and we need to make sure we
 	// do not crash the server so the set the maxCacheSize appropriately.
-	setMaxMemory()
+	setMaxMemory(maxMemory)
 
-	// Do not fail if this is not allowed, lower limits are fine as well.
+	// Do not fail if automatic tuning is not allowed, lower limits
+	// are fine as well.
 }
 
 // Validate if input disks are sufficient for initializing XL.
@@ -162,20 +508,25 @@ func checkSufficientDisks(eps []*url.URL) error {
 	// Verify total number of disks.
 	total := len(eps)
 	if total > maxErasureBlocks {
-		return errXLMaxDisks
+		// Local disks beyond maximum erasure blocks are grouped
+		// into erasure sets, distributed locking supports up to
+		// maximum erasure blocks nodes.
+		if isDistributedSetup(eps) {
+			return errXLMaxDisks
+		}
+		if total%getErasureSetCount(total) != 0 {
+			return errXLSetDisks
+		}
+		return nil
 	}
 	if total < minErasureBlocks {
 		return errXLMinDisks
 	}
 
-	// isEven function to verify if a given number if even.
-	isEven := func(number int) bool {
-		return number%2 == 0
-	}
-
-	// Verify if we have even number of disks.
-	// only combination of 4, 6, 8, 10, 12, 14, 16 are supported.
-	if !isEven(total) {
+	// Any number of local disks is supported, odd numbers get one
+	// more data than parity block. Distributed locking needs an even
+	// number of nodes.
+	if total%2 != 0 && isDistributedSetup(eps) {
 		return errXLNumDisks
 	}
 
@@ -239,40 +590,128 @@ func checkEndpointsSyntax(eps []*url.URL, disks []string) error {
 	return nil
 }
 
+// getServerAddress - returns address the server binds to, set by
+// `--address` or else by MINIO_ADDRESS env, defaults to ":9000".
+func getServerAddress(c *cli.Context) string {
+	if !c.IsSet("address") {
+		if serverAddr, ok := os.LookupEnv("MINIO_ADDRESS"); ok {
+			return serverAddr
+		}
+	}
+	return c.String("address")
+}
+
+// Returned when endpoints are given both as arguments and by a file.
+var errEndpointsFileAndArgs = errors.New("Endpoints given both on the command line and by --endpoints-file, only one of them is allowed")
+
+// readEndpointsFile - returns the endpoints listed in file, one per
+// line. Blank lines and lines starting with '#' are ignored.
+func readEndpointsFile(file string) (endpoints []string, err error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		endpoints = append(endpoints, line)
+	}
+	return endpoints, nil
+}
+
+// getServerEndpointArgs - returns the endpoints of the server, given as
+// arguments or else read from the file set by `--endpoints-file`.
+func getServerEndpointArgs(c *cli.Context) ([]string, error) {
+	file := c.String("endpoints-file")
+	if file == "" {
+		return c.Args(), nil
+	}
+	if c.Args().Present() {
+		return nil, errEndpointsFileAndArgs
+	}
+	return readEndpointsFile(file)
+}
+
+// splitServerAddress - returns all the addresses the server binds to,
+// several addresses are comma separated, e.g. "10.0.0.1:9000,[fd00::1]:9000".
+// The first address identifies this server among the endpoints of a
+// distributed setup.
+func splitServerAddress(serverAddr string) (serverAddrs []string) {
+	for _, addr := range strings.Split(serverAddr, ",") {
+		serverAddrs = append(serverAddrs, strings.TrimSpace(addr))
+	}
+	return serverAddrs
+}
+
 // Make sure all the command line parameters are OK and exit in case of invalid parameters.
 func checkServerSyntax(c *cli.Context) {
-	serverAddr := c.String("address")
+	fatalIf(validateServerSyntax(c), "Invalid command line arguments.")
+}
 
+// validateServerSyntax - returns an error if any of the command line
+// parameters is invalid.
+func validateServerSyntax(c *cli.Context) error {
+	serverAddrs := splitServerAddress(getServerAddress(c))
+	for _, addr := range serverAddrs {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return fmt.Errorf("Unable to parse %s: %s", addr, err)
+		}
+	}
+	if err := checkDuplicateStrings(serverAddrs); err != nil {
+		return fmt.Errorf("Duplicate entries in %s: %s", strings.Join(serverAddrs, ","), err)
+	}
+
+	serverAddr := serverAddrs[0]
 	host, portStr, err := net.SplitHostPort(serverAddr)
-	fatalIf(err, "Unable to parse %s.", serverAddr)
+	if err != nil {
+		return fmt.Errorf("Unable to parse %s: %s", serverAddr, err)
+	}
 
 	// Verify syntax for all the XL disks.
-	disks := c.Args()
+	disks, err := getServerEndpointArgs(c)
+	if err != nil {
+		return err
+	}
 	endpoints, err := parseStorageEndpoints(disks)
-	fatalIf(err, "Unable to parse storage endpoints %s", strings.Join(disks, " "))
+	if err != nil {
+		return fmt.Errorf("Unable to parse storage endpoints %s: %s", strings.Join(disks, " "), err)
+	}
+	if len(endpoints) == 0 {
+		return fmt.Errorf("No storage endpoints supplied")
+	}
 
 	// Validate if endpoints follow the expected syntax.
-	err = checkEndpointsSyntax(endpoints, disks)
-	fatalIf(err, "Invalid endpoints found %s", strings.Join(disks, " "))
+	if err = checkEndpointsSyntax(endpoints, disks); err != nil {
+		return fmt.Errorf("Invalid endpoints found %s: %s", strings.Join(disks, " "), err)
+	}
 
 	// Validate for duplicate endpoints are supplied.
-	err = checkDuplicateEndpoints(endpoints)
-	fatalIf(err, "Duplicate entries in %s", strings.Join(disks, " "))
+	if err = checkDuplicateEndpoints(endpoints); err != nil {
+		return fmt.Errorf("Duplicate entries in %s: %s", strings.Join(disks, " "), err)
+	}
+
+	// Validate paths of local endpoints do not overlap.
+	if err = checkOverlappingEndpoints(endpoints); err != nil {
+		return err
+	}
 
 	if len(endpoints) > 1 {
 		// Validate if we have sufficient disks for XL setup.
-		err = checkSufficientDisks(endpoints)
-		fatalIf(err, "Invalid number of disks supplied.")
+		if err = checkSufficientDisks(endpoints); err != nil {
+			return fmt.Errorf("Invalid number of disks supplied: %s", err)
+		}
 	} else {
 		// Validate if we have invalid disk for FS setup.
 		if endpoints[0].Host != "" && endpoints[0].Scheme != "" {
-			fatalIf(errInvalidArgument, "%s, FS setup expects a filesystem path", endpoints[0])
+			return fmt.Errorf("%s, FS setup expects a filesystem path", endpoints[0])
 		}
 	}
 
 	if !isDistributedSetup(endpoints) {
 		// for FS and singlenode-XL validation is done, return.
-		return
+		return nil
 	}
 
 	// Rest of the checks applies only to distributed XL setup.
@@ -280,7 +719,7 @@ func checkServerSyntax(c *cli.Context) {
 		// We are here implies --address host:port is passed, hence the user is trying
 		// to run one minio process per export disk.
 		if portStr == "" {
-			fatalIf(errInvalidArgument, "Port missing, Host:Port should be specified for --address")
+			return fmt.Errorf("Port missing, Host:Port should be specified for --address")
 		}
 		foundCnt := 0
 		for _, ep := range endpoints {
@@ -290,20 +729,21 @@ func checkServerSyntax(c *cli.Context) {
 		}
 		if foundCnt == 0 {
 			// --address host:port should be available in the XL disk list.
-			fatalIf(errInvalidArgument, "%s is not available in %s", serverAddr, strings.Join(disks, " "))
+			return fmt.Errorf("%s is not available in %s", serverAddr, strings.Join(disks, " "))
 		}
 		if foundCnt > 1 {
 			// --address host:port should match exactly one entry in the XL disk list.
-			fatalIf(errInvalidArgument, "%s matches % entries in %s", serverAddr, foundCnt, strings.Join(disks, " "))
+			return fmt.Errorf("%s matches %d entries in %s", serverAddr, foundCnt, strings.Join(disks, " "))
 		}
 	}
 
 	for _, ep := range endpoints {
 		if ep.Scheme == "https" && !globalIsSSL {
 			// Certificates should be provided for https configuration.
-			fatalIf(errInvalidArgument, "Certificates not provided for secure configuration")
+			return fmt.Errorf("Certificates not provided for secure configuration")
 		}
 	}
+	return nil
 }
 
 // Checks if any of the endpoints supplied is local to this server.
@@ -353,22 +793,142 @@ func getHostPort(address string) (host, port string, err error) {
 
 // serverMain handler called for 'minio server' command.
 func serverMain(c *cli.Context) {
-	if !c.Args().Present() || c.Args().First() == "help" {
+	if (!c.Args().Present() && c.String("endpoints-file") == "") || c.Args().First() == "help" {
 		cli.ShowCommandHelpAndExit(c, "server", 1)
 	}
 
+	// Uptime reported by the admin API is counted from here.
+	globalBootTime = time.Now().UTC()
+
 	// Initialization routine, such as config loading, enable logging, ..
 	minioInit(c)
 
+	// Only validate the command line if requested, the exit status
+	// tells whether it is valid.
+	if c.Bool("validate") {
+		if !printValidationSummary(validateServer(c)) {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Check for minio updates from dl.minio.io
 	checkUpdate()
 
-	// Server address.
-	serverAddr := c.String("address")
+	// Server addresses, the first one identifies this server.
+	serverAddrs := splitServerAddress(getServerAddress(c))
+	serverAddr := serverAddrs[0]
+
+	// Upgrade backend format of lagging disks only if requested.
+	globalAutoFormatUpgrade = c.Bool("auto-format-upgrade")
+
+	// Write-ahead log for FS mode, disabled by default.
+	globalFSWALDir = c.String("fs-wal-dir")
+
+	// Region of the signature is not validated only if requested.
+	globalIgnoreSignatureRegion = c.Bool("ignore-signature-region")
+
+	// Limits of multipart uploads.
+	err := setMultipartLimits(c.Int("max-parts"), c.String("min-part-size"), c.String("max-part-size"))
+	fatalIf(err, "Invalid multipart upload limits.")
+
+	// Large objects are copied in parts of this size.
+	err = setCopyPartSize(c.String("copy-part-size"))
+	fatalIf(err, "Invalid copy part size.")
+
+	// Deleted objects are reclaimed in background only if requested.
+	globalAsyncDelete = c.Bool("async-delete")
+
+	// Unreachable remote endpoints fail startup only if requested.
+	globalRequireFullMesh = c.Bool("require-full-mesh")
+
+	// Disks on network filesystems fail startup only if requested.
+	globalRejectNetworkDisks = c.Bool("reject-network-disks")
+
+	// Disks are verified to be writable at startup only if requested.
+	globalIsSelfTest = c.Bool("self-test")
+
+	// Writes wait for all the disks unless requested.
+	globalWriteLaggardTimeout = c.Duration("write-laggard-timeout")
+
+	// Distributed locks are retried with built-in back-off unless requested.
+	globalLockRetryBackoff = dsync.RetryBackoff{
+		Base:   c.Duration("lock-backoff-base"),
+		Max:    c.Duration("lock-backoff-max"),
+		Jitter: c.Float64("lock-backoff-jitter"),
+	}
+	fatalIf(dsync.SetRetryBackoff(globalLockRetryBackoff), "Invalid lock retry back-off.")
+	globalLockTimeout = c.Duration("lock-timeout")
+	if globalLockTimeout < 0 {
+		fatalIf(errInvalidArgument, "Invalid value for --lock-timeout.")
+	}
+
+	// Calls to peers failing with connection errors are retried.
+	globalPeerRPCRetries = c.Int("peer-rpc-retries")
+	if globalPeerRPCRetries < 0 {
+		fatalIf(errInvalidArgument, "Invalid value for --peer-rpc-retries.")
+	}
+	globalPeerRPCTimeout = c.Duration("peer-rpc-timeout")
+	if globalPeerRPCTimeout <= 0 {
+		fatalIf(errInvalidArgument, "Invalid value for --peer-rpc-timeout.")
+	}
+
+	// Total number of open client connections is optionally bounded.
+	globalMaxConnections = c.Int("max-connections")
+	if !c.IsSet("max-connections") {
+		globalMaxConnections = defaultMaxConnections(getMaxOpenFiles())
+	}
+	if globalMaxConnections < 0 {
+		fatalIf(errInvalidArgument, "Invalid value for --max-connections.")
+	}
+
+	// Sequential reads optionally read ahead of the client.
+	globalReadAheadBlocks = c.Int("read-ahead-blocks")
+	if globalReadAheadBlocks < 0 {
+		fatalIf(errInvalidArgument, "Invalid value for --read-ahead-blocks.")
+	}
+	globalReadAheadMaxBlocks = c.Int("read-ahead-max-blocks")
+	if globalReadAheadMaxBlocks < 0 {
+		fatalIf(errInvalidArgument, "Invalid value for --read-ahead-max-blocks.")
+	}
+	initReadAheadPool(globalReadAheadMaxBlocks)
+
+	// Mutating requests are optionally rejected.
+	globalIsReadOnly = c.Bool("read-only")
+
+	// Prometheus metrics are optionally served without credentials.
+	globalIsMetricsAnonymous = c.Bool("metrics-anonymous")
+
+	// Client addresses are optionally taken from a reverse proxy.
+	globalIsTrustedProxy = c.Bool("trusted-proxy")
+
+	// HTTP/2 is optionally served without TLS.
+	globalIsH2CEnabled = c.Bool("h2c")
+
+	// Listings are optionally compressed.
+	globalIsCompressionEnabled = c.Bool("compress")
+
+	// Object metadata is optionally cached on a fast local disk.
+	globalMetadataStoreDir = c.String("metadata-store-dir")
+	if globalMetadataStoreDir != "" {
+		globalMetaStore, err = newMetaStore(globalMetadataStoreDir)
+		fatalIf(err, "Unable to open metadata store %s.", globalMetadataStoreDir)
+	}
+
+	// Objects in FS mode are optionally checksummed.
+	globalFSChecksumAlgo, err = parseFSChecksumAlgo(c.String("fs-checksum"))
+	fatalIf(err, "Invalid value for --fs-checksum.")
+
+	// Durations of startup phases are logged only if requested.
+	globalStartupTiming = c.Bool("startup-timing")
+	startupTimer := newStartupTimer(globalStartupTiming)
 
-	var err error
 	globalMinioHost, globalMinioPort, err = getHostPort(serverAddr)
 	fatalIf(err, "Unable to extract host and port %s", serverAddr)
+	for _, addr := range serverAddrs[1:] {
+		_, _, err = getHostPort(addr)
+		fatalIf(err, "Unable to extract host and port %s", addr)
+	}
 
 	// Check server syntax and exit in case of errors.
 	// Done after globalMinioHost and globalMinioPort is set as parseStorageEndpoints()
@@ -376,8 +936,11 @@ func serverMain(c *cli.Context) {
 	checkServerSyntax(c)
 
 	// Disks to be used in server init.
-	endpoints, err := parseStorageEndpoints(c.Args())
-	fatalIf(err, "Unable to parse storage endpoints %s", c.Args())
+	endpointArgs, err := getServerEndpointArgs(c)
+	fatalIf(err, "Unable to read storage endpoints.")
+	endpoints, endpointZoneTags, err := parseStorageEndpointsWithZones(endpointArgs)
+	fatalIf(err, "Unable to parse storage endpoints %s", strings.Join(endpointArgs, " "))
+	zones := newEndpointZones(endpoints, endpointZoneTags)
 
 	// Should exit gracefully if none of the endpoints passed
 	// as command line args are local to this server.
@@ -391,14 +954,63 @@ func serverMain(c *cli.Context) {
 	// on all nodes.
 	sort.Sort(byHostPath(endpoints))
 
+	// Parity of erasure coded objects is optionally lowered for more
+	// usable capacity.
+	globalStorageClassParity, err = parseStorageClass(os.Getenv("MINIO_STORAGE_CLASS_STANDARD"), len(endpoints))
+	fatalIf(err, "Invalid storage class MINIO_STORAGE_CLASS_STANDARD.")
+
+	// Buckets are addressed in the host as well if a domain is set.
+	globalDomainName, err = parseDomainName(os.Getenv("MINIO_DOMAIN"))
+	fatalIf(err, "Invalid domain MINIO_DOMAIN.")
+
+	// Objects are encrypted by the server if a master key is set.
+	globalSSEMasterKey, err = parseSSEMasterKey(os.Getenv("MINIO_SSE_MASTER_KEY"))
+	fatalIf(err, "Invalid master key MINIO_SSE_MASTER_KEY.")
+	fatalIf(checkEncryptedBuckets(serverConfig), "Invalid encrypted buckets configuration.")
+
+	// I/O operations of each local disk are optionally limited, such
+	// that parallel requests queue rather than overload a disk.
+	globalDiskIOConcurrency, err = parseDiskIOConcurrency(os.Getenv("MINIO_DISK_IO_CONCURRENCY"))
+	fatalIf(err, "Invalid disk I/O concurrency MINIO_DISK_IO_CONCURRENCY.")
+
+	phaseDone := startupTimer.timePhase("initStorageDisks")
 	storageDisks, err := initStorageDisks(endpoints)
+	phaseDone()
 	fatalIf(err, "Unable to initialize storage disk(s).")
 
-	// Cleanup objects that weren't successfully written into the namespace.
-	fatalIf(houseKeeping(storageDisks), "Unable to purge temporary files.")
+	// Complete FS operations interrupted by a crash, this is done
+	// before temporary files of such operations are purged.
+	if globalFSWALDir != "" {
+		globalFSWAL, err = initFSWAL(globalFSWALDir, storageDisks)
+		fatalIf(err, "Unable to initialize write-ahead log at %s.", globalFSWALDir)
+	}
 
 	// Initialize server config.
+	phaseDone = startupTimer.timePhase("initServerConfig")
 	initServerConfig(c)
+	phaseDone()
+
+	// Client certificates are optionally required, verified against
+	// the client CAs loaded with the server config.
+	globalIsClientCertRequired = c.Bool("require-client-cert")
+	if globalIsClientCertRequired {
+		if !globalIsSSL {
+			fatalIf(errInvalidArgument, "--require-client-cert requires TLS to be configured.")
+		}
+		if globalClientCAs == nil {
+			fatalIf(errInvalidArgument, "--require-client-cert requires CA certificates in %s.",
+				filepath.Join(mustGetCertsPath(), globalMinioCertsClientCADir))
+		}
+		// Servers present their own certificate to each other.
+		cert, err := tls.LoadX509KeyPair(mustGetCertFile(), mustGetKeyFile())
+		fatalIf(err, "Unable to load the server certificate as client certificate.")
+		globalRPCClientCerts = []tls.Certificate{cert}
+	}
+
+	// Cleanup objects that weren't successfully written into the namespace.
+	phaseDone = startupTimer.timePhase("houseKeeping")
+	fatalIf(houseKeeping(storageDisks, serverConfig.GetTmpCleanupAge()), "Unable to purge temporary files.")
+	phaseDone()
 
 	// First disk argument check if it is local.
 	firstDisk := isLocalStorage(endpoints[0])
@@ -411,29 +1023,62 @@ func serverMain(c *cli.Context) {
 		serverAddr:   serverAddr,
 		endpoints:    endpoints,
 		storageDisks: storageDisks,
+		zones:        zones,
 	}
+	globalEndpointZones = srvConfig.zones
 
 	// Configure server.
+	phaseDone = startupTimer.timePhase("configureServerHandler")
 	handler, err := configureServerHandler(srvConfig)
+	phaseDone()
 	fatalIf(err, "Unable to configure one of server's RPC services.")
 
-	// Set nodes for dsync for distributed setup.
-	if globalIsDistXL {
-		fatalIf(initDsyncNodes(endpoints), "Unable to initialize distributed locking")
-	}
-
 	// Initialize name space lock.
 	initNSLock(globalIsDistXL)
 
 	// Initialize a new HTTP server.
-	apiServer := NewServerMux(serverAddr, handler)
+	// In-flight requests are drained on shutdown for the requested time.
+	globalShutdownTimeout = c.Duration("shutdown-timeout")
+	if globalShutdownTimeout < 0 {
+		fatalIf(errInvalidArgument, "Invalid value for --shutdown-timeout.")
+	}
+
+	// Slow clients are disconnected, large transfers are not cut off
+	// unless requested.
+	globalReadHeaderTimeout = c.Duration("read-header-timeout")
+	globalReadTimeout = c.Duration("read-timeout")
+	globalWriteTimeout = c.Duration("write-timeout")
+	globalIdleTimeout = c.Duration("idle-timeout")
+	for _, name := range []string{"read-header-timeout", "read-timeout", "write-timeout", "idle-timeout"} {
+		if c.Duration(name) < 0 {
+			fatalIf(errInvalidArgument, "Invalid value for --%s.", name)
+		}
+	}
+
+	// Object downloads and uploads share a bandwidth limit each.
+	globalEgressLimiter, err = parseBandwidthLimit(c.String("bandwidth"))
+	fatalIf(err, "Invalid value for --bandwidth.")
+	globalIngressLimiter, err = parseBandwidthLimit(c.String("bandwidth-ingress"))
+	fatalIf(err, "Invalid value for --bandwidth-ingress.")
+
+	globalLifecycleInterval = c.Duration("lifecycle-interval")
+	if globalLifecycleInterval <= 0 {
+		fatalIf(errInvalidArgument, "Invalid value for --lifecycle-interval.")
+	}
+
+	apiServer := NewServerMux(serverAddrs, handler)
+	globalConnLimiter = apiServer.connLimiter
+	// Connections between servers are never refused.
+	if globalIsDistXL {
+		globalConnLimiter.exemptPeers(endpoints)
+	}
 
 	// Set the global minio addr for this server.
 	globalMinioAddr = getLocalAddress(srvConfig)
 
 	// Determine API endpoints where we are going to serve the S3 API from.
-	apiEndPoints, err := finalizeAPIEndpoints(apiServer.Server)
-	fatalIf(err, "Unable to finalize API endpoints for %s", apiServer.Server.Addr)
+	apiEndPoints, err := finalizeAPIEndpoints(serverAddrs)
+	fatalIf(err, "Unable to finalize API endpoints for %s", strings.Join(serverAddrs, ","))
 
 	// Set the global API endpoints value.
 	globalAPIEndpoints = apiEndPoints
@@ -453,21 +1098,75 @@ func serverMain(c *cli.Context) {
 		fatalIf(apiServer.ListenAndServe(cert, key), "Failed to start minio server.")
 	}()
 
+	// Verify connectivity to all the remote endpoints before forming
+	// the cluster, done once this server listens such that peers
+	// checking their connectivity concurrently can reach it. Peers
+	// which are down are only waited for if a full mesh is required,
+	// otherwise waiting for them is left to formatting of disks.
+	if globalIsDistXL {
+		if globalRequireFullMesh {
+			err = checkEndpointsReachable(globalMinioAddr, endpoints, endpointReachabilityTimeout)
+			fatalIf(err, "Unable to reach all the remote endpoints.")
+		} else {
+			checkEndpointsReachable(globalMinioAddr, endpoints, 0)
+		}
+
+		// Servers with skewed clocks reject each other's RPC calls,
+		// such as locking and formatting of disks.
+		err = checkPeersClockSkew(globalS3Peers, maxPeerClockSkew)
+		fatalIf(err, "Clocks of the servers are not in sync, please verify NTP is running on all the servers.")
+
+		// Set nodes for dsync once the peers are reachable, stale
+		// locks of this node are released on them right away.
+		fatalIf(initDsyncNodes(endpoints), "Unable to initialize distributed locking")
+	}
+
 	// Wait for formatting of disks.
+	phaseDone = startupTimer.timePhase("waitForFormatDisks")
 	formattedDisks, err := waitForFormatDisks(firstDisk, endpoints, storageDisks)
+	phaseDone()
 	fatalIf(err, "formatting storage disks failed")
 
 	// Once formatted, initialize object layer.
-	newObject, err := newObjectLayer(formattedDisks)
+	phaseDone = startupTimer.timePhase("newObjectLayer")
+	newObject, err := newObjectLayer(formattedDisks, globalStorageClassParity)
+	phaseDone()
 	fatalIf(err, "intializing object layer failed")
 
+	// Formatted disks may still be read-only or have bad sectors.
+	if globalIsSelfTest {
+		phaseDone = startupTimer.timePhase("selfTestLocalDisks")
+		err = selfTestLocalDisks(endpoints, storageDisks)
+		phaseDone()
+		fatalIf(err, "Startup self-test of disks failed.")
+	}
+
+	if globalSSEMasterKey != nil {
+		newObject = newSSEObjects(newObject, globalSSEMasterKey)
+	}
+
 	globalObjLayerMutex.Lock()
 	globalObjectAPI = newObject
 	globalObjLayerMutex.Unlock()
 
+	// Apply changes of the config file on SIGHUP for the lifetime of
+	// the process.
+	reloadConfigOnSignal(nil)
+
+	// Delete objects expired by lifecycle rules for the lifetime of
+	// the process.
+	go startLifecycleScanner(newObject, globalLifecycleInterval, nil)
+
+	// Abort multipart uploads without activity for the lifetime of the
+	// process.
+	go startStaleUploadsCleanup(newObject, staleUploadsCleanupInterval, nil)
+
 	// Prints the formatted startup message once object layer is initialized.
 	printStartupMessage(apiEndPoints)
 
+	// Prints durations of the startup phases, if requested.
+	startupTimer.printSummary()
+
 	// Waits on the server.
 	<-globalServiceDoneCh
 }
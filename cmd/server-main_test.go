@@ -19,9 +19,13 @@ package cmd
 import (
 	"errors"
 	"flag"
+	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
+	"reflect"
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/minio/cli"
@@ -119,14 +123,39 @@ func TestFinalizeAPIEndpoints(t *testing.T) {
 	}
 
 	for i, test := range testCases {
-		endPoints, err := finalizeAPIEndpoints(&http.Server{
-			Addr: test.addr,
-		})
+		endPoints, err := finalizeAPIEndpoints([]string{test.addr})
 		if err != nil && len(endPoints) <= 0 {
 			t.Errorf("Test case %d returned with no API end points for %s",
 				i+1, test.addr)
 		}
 	}
+
+	// All the addresses are reported, IPv6 hosts within brackets.
+	endPoints, err := finalizeAPIEndpoints([]string{"127.0.0.1:9000", "[::1]:9000", "127.0.0.1:9000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"http://127.0.0.1:9000", "http://[::1]:9000"}
+	if !reflect.DeepEqual(endPoints, expected) {
+		t.Errorf("Expected %v, got %v", expected, endPoints)
+	}
+}
+
+// Tests several comma separated server addresses are split.
+func TestSplitServerAddress(t *testing.T) {
+	testCases := []struct {
+		serverAddr    string
+		expectedAddrs []string
+	}{
+		{":9000", []string{":9000"}},
+		{"127.0.0.1:9000,[::1]:9000", []string{"127.0.0.1:9000", "[::1]:9000"}},
+		{"10.0.0.1:9000, 10.0.1.1:9001", []string{"10.0.0.1:9000", "10.0.1.1:9001"}},
+	}
+	for i, testCase := range testCases {
+		if addrs := splitServerAddress(testCase.serverAddr); !reflect.DeepEqual(addrs, testCase.expectedAddrs) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expectedAddrs, addrs)
+		}
+	}
 }
 
 // Tests all the expected input disks for function checkSufficientDisks.
@@ -193,19 +222,45 @@ func TestCheckSufficientDisks(t *testing.T) {
 			xlDisks[0:16],
 			nil,
 		},
-		// Larger than maximum number of disks > 16.
+		// Disks beyond maximum not dividing into erasure sets.
 		{
 			xlDisks,
-			errXLMaxDisks,
+			errXLSetDisks,
 		},
 		// Lesser than minimum number of disks < 6.
 		{
 			xlDisks[0:3],
 			errXLMinDisks,
 		},
-		// Odd number of disks, not divisible by '2'.
+		// Odd number of disks '5'.
+		{
+			xlDisks[0:5],
+			nil,
+		},
+		// Odd number of disks '7'.
+		{
+			xlDisks[0:7],
+			nil,
+		},
+		// Odd number of disks '9'.
+		{
+			xlDisks[0:9],
+			nil,
+		},
+		// Odd number of disks '11'.
 		{
 			append(xlDisks[0:10], xlDisks[11]),
+			nil,
+		},
+		// Odd number of disks in a distributed setup.
+		{
+			[]string{
+				"http://10.1.10.1/mnt/disk1",
+				"http://10.1.10.2/mnt/disk1",
+				"http://10.1.10.3/mnt/disk1",
+				"http://10.1.10.4/mnt/disk1",
+				"http://10.1.10.5/mnt/disk1",
+			},
 			errXLNumDisks,
 		},
 	}
@@ -338,6 +393,145 @@ func TestCheckServerSyntax(t *testing.T) {
 	}
 }
 
+// Tests endpoints are read from --endpoints-file, exclusive of arguments.
+func TestGetServerEndpointArgs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minio-endpoints-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(dir)
+	disks, err := getRandomDisks(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(disks)
+
+	// writeFile - writes an endpoints file with lines and returns its path.
+	writeFile := func(name string, lines ...string) string {
+		file := filepath.Join(dir, name)
+		if err = ioutil.WriteFile(file, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+	validFile := writeFile("valid", "# Disks of this server.", disks[0], "", "  "+disks[1]+"  ", disks[2], disks[3], "")
+	duplicateFile := writeFile("duplicate", disks[0], disks[1], disks[2], disks[0])
+
+	testCases := []struct {
+		args          []string
+		expectedDisks []string
+		expectedErr   error
+		// Endpoints are valid and unique.
+		valid bool
+	}{
+		// Test case - 1.
+		// Endpoints as arguments.
+		{disks, disks, nil, true},
+		// Test case - 2.
+		// Endpoints from a file, comments and blank lines are skipped.
+		{[]string{"--endpoints-file", validFile}, disks, nil, true},
+		// Test case - 3.
+		// Duplicate endpoints from a file.
+		{[]string{"--endpoints-file", duplicateFile}, []string{disks[0], disks[1], disks[2], disks[0]}, nil, false},
+		// Test case - 4.
+		// Both a file and arguments.
+		{[]string{"--endpoints-file", validFile, disks[0]}, nil, errEndpointsFileAndArgs, false},
+	}
+	for i, testCase := range testCases {
+		serverFlagSet := flag.NewFlagSet("server", 0)
+		serverFlagSet.String("endpoints-file", "", "")
+		if err = serverFlagSet.Parse(testCase.args); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		ctx := cli.NewContext(cli.NewApp(), serverFlagSet, serverFlagSet)
+
+		endpointArgs, err := getServerEndpointArgs(ctx)
+		if err != testCase.expectedErr {
+			t.Fatalf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if err != nil {
+			continue
+		}
+		if !reflect.DeepEqual(endpointArgs, testCase.expectedDisks) {
+			t.Errorf("Test %d: Expected endpoints %v, got %v", i+1, testCase.expectedDisks, endpointArgs)
+		}
+
+		// Endpoints of a file are validated as arguments are.
+		endpoints, err := parseStorageEndpoints(endpointArgs)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		err = checkEndpointsSyntax(endpoints, endpointArgs)
+		if err == nil {
+			err = checkDuplicateEndpoints(endpoints)
+		}
+		if testCase.valid != (err == nil) {
+			t.Errorf("Test %d: Expected valid %t, got error %v", i+1, testCase.valid, err)
+		}
+	}
+
+	// A missing file.
+	serverFlagSet := flag.NewFlagSet("server", 0)
+	serverFlagSet.String("endpoints-file", "", "")
+	if err = serverFlagSet.Parse([]string{"--endpoints-file", filepath.Join(dir, "missing")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = getServerEndpointArgs(cli.NewContext(cli.NewApp(), serverFlagSet, serverFlagSet)); !os.IsNotExist(err) {
+		t.Errorf("Expected error for a missing file, got %v", err)
+	}
+}
+
+// Tests server address is taken from --address before MINIO_ADDRESS.
+func TestGetServerAddress(t *testing.T) {
+	defer func(addr string, ok bool) {
+		if ok {
+			os.Setenv("MINIO_ADDRESS", addr)
+		} else {
+			os.Unsetenv("MINIO_ADDRESS")
+		}
+	}(os.LookupEnv("MINIO_ADDRESS"))
+
+	testCases := []struct {
+		args         []string
+		setEnv       bool
+		env          string
+		expectedAddr string
+		success      bool
+	}{
+		// Default address.
+		{nil, false, "", ":9000", true},
+		// Address from env.
+		{nil, true, ":9001", ":9001", true},
+		// Flag takes precedence over env.
+		{[]string{"--address", ":9002"}, true, ":9001", ":9002", true},
+		{[]string{"--address", ":9000"}, true, ":9001", ":9000", true},
+		// Empty and malformed env values.
+		{nil, true, "", "", false},
+		{nil, true, "localhost", "localhost", false},
+		{nil, true, ":0", ":0", false},
+	}
+	for i, testCase := range testCases {
+		os.Unsetenv("MINIO_ADDRESS")
+		if testCase.setEnv {
+			os.Setenv("MINIO_ADDRESS", testCase.env)
+		}
+		serverFlagSet := flag.NewFlagSet("server", 0)
+		serverFlagSet.String("address", ":9000", "")
+		if err := serverFlagSet.Parse(testCase.args); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		ctx := cli.NewContext(cli.NewApp(), serverFlagSet, serverFlagSet)
+
+		serverAddr := getServerAddress(ctx)
+		if serverAddr != testCase.expectedAddr {
+			t.Errorf("Test %d: expected address %q, got %q", i+1, testCase.expectedAddr, serverAddr)
+		}
+		if _, _, err := getHostPort(serverAddr); testCase.success != (err == nil) {
+			t.Errorf("Test %d: unexpected error %v", i+1, err)
+		}
+	}
+}
+
 func TestIsDistributedSetup(t *testing.T) {
 	var testCases []struct {
 		disks  []string
@@ -421,6 +615,153 @@ func TestInitServerConfig(t *testing.T) {
 	}
 }
 
+// Tests credentials are read from the env and from files named by it.
+func TestGetEnvCredential(t *testing.T) {
+	envVars := []string{"MINIO_ACCESS_KEY", "MINIO_SECRET_KEY", "MINIO_ACCESS_KEY_FILE", "MINIO_SECRET_KEY_FILE"}
+	for _, envVar := range envVars {
+		val, ok := os.LookupEnv(envVar)
+		defer func(envVar, val string, ok bool) {
+			if ok {
+				os.Setenv(envVar, val)
+			} else {
+				os.Unsetenv(envVar)
+			}
+		}(envVar, val, ok)
+	}
+
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal("Failed to set up test config")
+	}
+	defer removeAll(root)
+
+	accessKeyFile := pathJoin(root, "access_key")
+	secretKeyFile := pathJoin(root, "secret_key")
+	if err = ioutil.WriteFile(accessKeyFile, []byte("minio-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(secretKeyFile, []byte("minio123-file \r\n\t\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		env          map[string]string
+		expectedCred credential
+		isEnvCred    bool
+		success      bool
+	}{
+		// Nothing set.
+		{nil, credential{}, false, true},
+		// Only one of the keys set.
+		{map[string]string{"MINIO_ACCESS_KEY": "minio"}, credential{AccessKey: "minio"}, false, true},
+		// Keys from env.
+		{map[string]string{"MINIO_ACCESS_KEY": "minio", "MINIO_SECRET_KEY": "minio123"},
+			credential{AccessKey: "minio", SecretKey: "minio123"}, true, true},
+		// Keys from files, trailing whitespace is trimmed.
+		{map[string]string{"MINIO_ACCESS_KEY_FILE": accessKeyFile, "MINIO_SECRET_KEY_FILE": secretKeyFile},
+			credential{AccessKey: "minio-file", SecretKey: "minio123-file"}, true, true},
+		// Files take precedence over env.
+		{map[string]string{"MINIO_ACCESS_KEY": "minio", "MINIO_SECRET_KEY": "minio123", "MINIO_SECRET_KEY_FILE": secretKeyFile},
+			credential{AccessKey: "minio", SecretKey: "minio123-file"}, true, true},
+		// Missing file.
+		{map[string]string{"MINIO_ACCESS_KEY": "minio", "MINIO_SECRET_KEY_FILE": pathJoin(root, "missing")},
+			credential{}, false, false},
+	}
+	for i, testCase := range testCases {
+		for _, envVar := range envVars {
+			os.Unsetenv(envVar)
+		}
+		for envVar, val := range testCase.env {
+			os.Setenv(envVar, val)
+		}
+		cred, isEnvCred, err := getEnvCredential()
+		if testCase.success != (err == nil) {
+			t.Errorf("Test %d: unexpected error %v", i+1, err)
+			continue
+		}
+		if cred != testCase.expectedCred || isEnvCred != testCase.isEnvCred {
+			t.Errorf("Test %d: expected %#v (%t), got %#v (%t)", i+1, testCase.expectedCred,
+				testCase.isEnvCred, cred, isEnvCred)
+		}
+	}
+
+	// Credentials read from files are loaded into the config and saved.
+	for _, envVar := range envVars {
+		os.Unsetenv(envVar)
+	}
+	os.Setenv("MINIO_ACCESS_KEY_FILE", accessKeyFile)
+	os.Setenv("MINIO_SECRET_KEY_FILE", secretKeyFile)
+	initServerConfig(&cli.Context{})
+	expectedCred := credential{AccessKey: "minio-file", SecretKey: "minio123-file"}
+	if cred := serverConfig.GetCredential(); cred != expectedCred {
+		t.Fatalf("Expected credentials %#v, got %#v", expectedCred, cred)
+	}
+	if _, err = initConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if cred := serverConfig.GetCredential(); cred != expectedCred {
+		t.Errorf("Expected saved credentials %#v, got %#v", expectedCred, cred)
+	}
+}
+
+// Tests region is read from the env and signatures validate only
+// for the region of the server.
+func TestInitServerConfigRegion(t *testing.T) {
+	val, ok := os.LookupEnv("MINIO_REGION")
+	defer func() {
+		if ok {
+			os.Setenv("MINIO_REGION", val)
+		} else {
+			os.Unsetenv("MINIO_REGION")
+		}
+	}()
+
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal("Failed to set up test config")
+	}
+	defer removeAll(root)
+
+	// signedRequest - returns a request signed for the given region.
+	signedRequest := func(region string) *http.Request {
+		confRegion := serverConfig.GetRegion()
+		defer serverConfig.SetRegion(confRegion)
+		serverConfig.SetRegion(region)
+		cred := serverConfig.GetCredential()
+		req, err := newTestSignedRequestV4("GET", "http://127.0.0.1:9000/bucket", 0, nil, cred.AccessKey, cred.SecretKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return req
+	}
+
+	testCases := []struct {
+		envRegion      string
+		expectedRegion string
+	}{
+		// Empty region keeps the region in config.
+		{"", "us-east-1"},
+		{"eu-central-1", "eu-central-1"},
+	}
+	for i, testCase := range testCases {
+		os.Setenv("MINIO_REGION", testCase.envRegion)
+		initServerConfig(&cli.Context{})
+		if _, err = initConfig(); err != nil {
+			t.Fatal(err)
+		}
+		if region := serverConfig.GetRegion(); region != testCase.expectedRegion {
+			t.Fatalf("Test %d: Expected saved region %s, got %s", i+1, testCase.expectedRegion, region)
+		}
+		for _, signRegion := range []string{"us-east-1", "eu-central-1"} {
+			s3Error := reqSignatureV4Verify(signedRequest(signRegion))
+			if (signRegion == testCase.expectedRegion) != (s3Error == ErrNone) {
+				t.Errorf("Test %d: Request signed for %s with server region %s, got %s",
+					i+1, signRegion, testCase.expectedRegion, getAPIError(s3Error).Code)
+			}
+		}
+	}
+}
+
 // Tests isAnyEndpointLocal function with inputs such that it returns true and false respectively.
 func TestIsAnyEndpointLocal(t *testing.T) {
 	testCases := []struct {
@@ -19,6 +19,7 @@ package cmd
 import (
 	"bufio"
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"io"
 	"net"
@@ -27,6 +28,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"golang.org/x/net/http2"
 )
 
 // The value chosen below is longest word chosen
@@ -72,10 +75,14 @@ func NewConnMux(c net.Conn) *ConnMux {
 }
 
 // PeekProtocol - reads the first bytes, then checks if it is similar
-// to one of the default http methods
+// to one of the default http methods. Returns empty if the first
+// bytes were not received before the read deadline.
 func (c *ConnMux) PeekProtocol() string {
 	buf, err := c.bufrw.Peek(maxHTTPVerbLen)
 	if err != nil {
+		if nerr, ok := err.(net.Error); ok && nerr.Timeout() {
+			return ""
+		}
 		if err != io.EOF {
 			errorIf(err, "Unable to peek into the protocol")
 		}
@@ -100,12 +107,14 @@ func (c *ConnMux) Read(b []byte) (int, error) {
 	return c.bufrw.Read(b)
 }
 
-// Close the connection.
+// Close the connection, the underlying connection is closed even if
+// flushing fails.
 func (c *ConnMux) Close() (err error) {
-	if err = c.bufrw.Flush(); err != nil {
-		return err
+	err = c.bufrw.Flush()
+	if cerr := c.Conn.Close(); err == nil {
+		err = cerr
 	}
-	return c.Conn.Close()
+	return err
 }
 
 // ListenerMux wraps the standard net.Listener to inspect
@@ -143,6 +152,14 @@ type ListenerMux struct {
 	// Cond is used to signal Close when there are no references to the listener.
 	cond *sync.Cond
 	refs int
+	// limiter bounds the number of open connections, nil means unlimited.
+	limiter *connLimiter
+	// serveH2C serves cleartext HTTP/2 connections, nil means they
+	// are handed to the HTTP/1.x server like any other connection.
+	serveH2C func(net.Conn)
+	// peekTimeout bounds the wait for the first bytes of connections,
+	// zero means no timeout.
+	peekTimeout time.Duration
 }
 
 // ListenerMuxAcceptRes contains then final net.Conn data (wrapper by tls or not) to be sent to the http handler
@@ -152,12 +169,15 @@ type ListenerMuxAcceptRes struct {
 }
 
 // newListenerMux listens and wraps accepted connections with tls after protocol peeking
-func newListenerMux(listener net.Listener, config *tls.Config) *ListenerMux {
+func newListenerMux(listener net.Listener, config *tls.Config, limiter *connLimiter, serveH2C func(net.Conn), peekTimeout time.Duration) *ListenerMux {
 	l := ListenerMux{
 		Listener:    listener,
 		config:      config,
 		cond:        sync.NewCond(&sync.Mutex{}),
 		acceptResCh: make(chan ListenerMuxAcceptRes),
+		limiter:     limiter,
+		serveH2C:    serveH2C,
+		peekTimeout: peekTimeout,
 	}
 	// Start listening, wrap connections with tls when needed
 	go func() {
@@ -168,13 +188,36 @@ func newListenerMux(listener net.Listener, config *tls.Config) *ListenerMux {
 				l.acceptResCh <- ListenerMuxAcceptRes{err: err}
 				return
 			}
+			// Refuse connections beyond the limit until others close.
+			if conn = l.limiter.limitConn(conn); conn == nil {
+				continue
+			}
 			// Wrap the connection with ConnMux to be able to peek the data in the incoming connection
 			// and decide if we need to wrap the connection itself with a TLS or not
 			go func(conn net.Conn) {
 				connMux := NewConnMux(conn)
-				if connMux.PeekProtocol() == "tls" {
+				// Connections sending nothing are closed like the
+				// ones sending their headers slowly.
+				if l.peekTimeout > 0 {
+					conn.SetReadDeadline(time.Now().Add(l.peekTimeout))
+				}
+				protocol := connMux.PeekProtocol()
+				if protocol == "" {
+					conn.Close()
+					return
+				}
+				conn.SetReadDeadline(time.Time{})
+				switch protocol {
+				case "tls":
 					l.acceptResCh <- ListenerMuxAcceptRes{conn: tls.Server(connMux, l.config)}
-				} else {
+				case "http2":
+					// Cleartext HTTP/2 with prior knowledge.
+					if l.serveH2C != nil {
+						l.serveH2C(connMux)
+						return
+					}
+					l.acceptResCh <- ListenerMuxAcceptRes{conn: connMux}
+				default:
 					l.acceptResCh <- ListenerMuxAcceptRes{conn: connMux}
 				}
 			}(conn)
@@ -238,29 +281,51 @@ func (l *ListenerMux) Accept() (net.Conn, error) {
 // ServerMux - the main mux server
 type ServerMux struct {
 	*http.Server
+	addrs           []string // all addresses listened on, Addr is the first
 	listeners       []*ListenerMux
 	WaitGroup       *sync.WaitGroup
 	GracefulTimeout time.Duration
 	mu              sync.Mutex // guards closed, conns, and listener
 	closed          bool
 	conns           map[net.Conn]http.ConnState // except terminal states
+	connLimiter     *connLimiter
+	h2cEnabled      bool           // serve HTTP/2 on cleartext connections
+	clientCAs       *x509.CertPool // verify required client certificates against, nil if not required
 }
 
-// NewServerMux constructor to create a ServerMux
-func NewServerMux(addr string, handler http.Handler) *ServerMux {
+// NewServerMux constructor to create a ServerMux listening on one or
+// more addresses, all of them served by the same handler.
+func NewServerMux(addrs []string, handler http.Handler) *ServerMux {
 	m := &ServerMux{
 		Server: &http.Server{
-			Addr: addr,
-			// Do not add any timeouts Golang net.Conn
-			// closes connections right after 10mins even
-			// if they are not idle.
-			Handler:        handler,
-			MaxHeaderBytes: 1 << 20,
+			Addr: addrs[0],
+			// Clients sending headers slowly are disconnected after
+			// --read-header-timeout, idle keep-alive connections after
+			// --idle-timeout. Read and write timeouts bound the whole
+			// request including its body, they are disabled unless
+			// set such that large uploads and downloads are not cut
+			// off.
+			ReadHeaderTimeout: globalReadHeaderTimeout,
+			ReadTimeout:       globalReadTimeout,
+			WriteTimeout:      globalWriteTimeout,
+			IdleTimeout:       globalIdleTimeout,
+			Handler:           handler,
+			MaxHeaderBytes:    1 << 20,
 		},
+		addrs:     addrs,
 		WaitGroup: &sync.WaitGroup{},
-		// Wait for 5 seconds for new incoming connnections, otherwise
-		// forcibly close them during graceful stop or restart.
-		GracefulTimeout: 5 * time.Second,
+		// Wait for in-flight requests to complete for
+		// --shutdown-timeout, otherwise forcibly close their
+		// connections during graceful stop or restart.
+		GracefulTimeout: globalShutdownTimeout,
+		// Limit total number of open client connections.
+		connLimiter: newConnLimiter(globalMaxConnections),
+		// Serve HTTP/2 on cleartext connections when --h2c is set.
+		h2cEnabled: globalIsH2CEnabled,
+	}
+	// Require client certificates when --require-client-cert is set.
+	if globalIsClientCertRequired {
+		m.clientCAs = globalClientCAs
 	}
 
 	// Track connection state
@@ -271,7 +336,7 @@ func NewServerMux(addr string, handler http.Handler) *ServerMux {
 }
 
 // Initialize listeners on all ports.
-func initListeners(serverAddr string, tls *tls.Config) ([]*ListenerMux, error) {
+func initListeners(serverAddr string, tls *tls.Config, limiter *connLimiter, serveH2C func(net.Conn), peekTimeout time.Duration) ([]*ListenerMux, error) {
 	host, port, err := net.SplitHostPort(serverAddr)
 	if err != nil {
 		return nil, err
@@ -283,7 +348,7 @@ func initListeners(serverAddr string, tls *tls.Config) ([]*ListenerMux, error) {
 		if err != nil {
 			return nil, err
 		}
-		listeners = append(listeners, newListenerMux(listener, tls))
+		listeners = append(listeners, newListenerMux(listener, tls, limiter, serveH2C, peekTimeout))
 		return listeners, nil
 	}
 	var addrs []string
@@ -304,7 +369,7 @@ func initListeners(serverAddr string, tls *tls.Config) ([]*ListenerMux, error) {
 		if err != nil {
 			return nil, err
 		}
-		listeners = append(listeners, newListenerMux(listener, tls))
+		listeners = append(listeners, newListenerMux(listener, tls, limiter, serveH2C, peekTimeout))
 	}
 	return listeners, nil
 }
@@ -318,29 +383,35 @@ func (m *ServerMux) ListenAndServe(certFile, keyFile string) (err error) {
 	config := &tls.Config{} // Always instantiate.
 
 	if tlsEnabled {
-		// Configure TLS in the server
-		if config.NextProtos == nil {
-			config.NextProtos = []string{"http/1.1", "h2"}
-		}
-		config.Certificates = make([]tls.Certificate, 1)
-		config.Certificates[0], err = tls.LoadX509KeyPair(certFile, keyFile)
+		// Configure TLS in the server, HTTP/2 is preferred over
+		// HTTP/1.1 by clients supporting both.
+		config.NextProtos = []string{http2.NextProtoTLS, "http/1.1"}
+		config.PreferServerCipherSuites = true
+		// Renewed certificates are served to new connections
+		// without a restart.
+		var reloader *certReloader
+		reloader, err = newCertReloader(certFile, keyFile)
 		if err != nil {
 			return err
 		}
+		config.GetCertificate = reloader.GetCertificate
+		reloadDoneCh := make(chan struct{})
+		defer close(reloadDoneCh)
+		go reloader.reloadOnSignal(reloadDoneCh)
+		// Connections of clients without a certificate signed by
+		// one of the client CAs fail the TLS handshake. The port is
+		// shared by all APIs, servers of a distributed setup present
+		// globalRPCClientCerts to each other to pass it.
+		if m.clientCAs != nil {
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+			config.ClientCAs = m.clientCAs
+		}
 	}
 
 	go m.handleServiceSignals()
 
-	listeners, err := initListeners(m.Server.Addr, config)
-	if err != nil {
-		return err
-	}
-
-	m.mu.Lock()
-	m.listeners = listeners
-	m.mu.Unlock()
-
 	// All http requests start to be processed by httpHandler
+	handler := m.Server.Handler
 	httpHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if tlsEnabled && r.TLS == nil {
 			// TLS is enabled but Request is not TLS configured
@@ -356,16 +427,51 @@ func (m *ServerMux) ListenAndServe(certFile, keyFile string) (err error) {
 			http.Redirect(w, r, u.String(), http.StatusTemporaryRedirect)
 		} else {
 			// Execute registered handlers
-			m.Server.Handler.ServeHTTP(w, r)
+			handler.ServeHTTP(w, r)
 		}
 	})
 
+	// Connections are served by this server, such that their states
+	// are tracked and Close drains in-flight requests.
+	m.Server.Handler = httpHandler
+
+	// Serve HTTP/2 on TLS connections negotiating it, and optionally
+	// on cleartext connections.
+	h2Server := &http2.Server{}
+	if err = http2.ConfigureServer(m.Server, h2Server); err != nil {
+		return err
+	}
+	var serveH2C func(net.Conn)
+	if m.h2cEnabled {
+		serveH2C = func(c net.Conn) {
+			m.serveH2C(h2Server, c)
+		}
+	}
+
+	var listeners []*ListenerMux
+	for _, addr := range m.addrs {
+		var addrListeners []*ListenerMux
+		addrListeners, err = initListeners(addr, config, m.connLimiter, serveH2C, m.ReadHeaderTimeout)
+		if err != nil {
+			// Release addresses already listened on.
+			for _, listener := range listeners {
+				listener.Close()
+			}
+			return err
+		}
+		listeners = append(listeners, addrListeners...)
+	}
+
+	m.mu.Lock()
+	m.listeners = listeners
+	m.mu.Unlock()
+
 	var wg = &sync.WaitGroup{}
 	for _, listener := range listeners {
 		wg.Add(1)
 		go func(listener *ListenerMux) {
 			defer wg.Done()
-			serr := http.Serve(listener, httpHandler)
+			serr := m.Server.Serve(listener)
 			// Do not print the error if the listener is closed.
 			if !listener.IsClosed() {
 				errorIf(serr, "Unable to serve incoming requests.")
@@ -377,6 +483,19 @@ func (m *ServerMux) ListenAndServe(certFile, keyFile string) (err error) {
 	return nil
 }
 
+// serveH2C - serves a cleartext HTTP/2 connection, its state is
+// tracked like the state of connections served by the HTTP/1.x server.
+func (m *ServerMux) serveH2C(h2Server *http2.Server, c net.Conn) {
+	m.Server.ConnState(c, http.StateNew)
+	m.Server.ConnState(c, http.StateActive)
+	defer m.Server.ConnState(c, http.StateClosed)
+
+	h2Server.ServeConn(c, &http2.ServeConnOpts{
+		BaseConfig: m.Server,
+		Handler:    m.Server.Handler,
+	})
+}
+
 // Close initiates the graceful shutdown
 func (m *ServerMux) Close() error {
 	m.mu.Lock()
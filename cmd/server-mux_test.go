@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bufio"
+	"bytes"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/tls"
@@ -30,11 +31,16 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"os/exec"
 	"runtime"
 	"sync"
+	"syscall"
 	"testing"
 	"time"
+
+	"golang.org/x/net/http2"
 )
 
 func TestListenerAcceptAfterClose(t *testing.T) {
@@ -52,6 +58,96 @@ func TestListenerAcceptAfterClose(t *testing.T) {
 	wg.Wait()
 }
 
+// Tests connections beyond the limit are refused until others close.
+func TestListenerMuxConnLimit(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	limiter := newConnLimiter(1)
+	l := newListenerMux(ln, &tls.Config{}, limiter, nil, 0)
+	defer l.Close()
+
+	dial := func() net.Conn {
+		conn, derr := net.Dial("tcp", l.Addr().String())
+		if derr != nil {
+			t.Fatal(derr)
+		}
+		// Protocol is peeked before the connection is accepted.
+		if _, derr = conn.Write([]byte("GET / HTTP/1.1\r\n")); derr != nil {
+			t.Fatal(derr)
+		}
+		return conn
+	}
+
+	client1 := dial()
+	defer client1.Close()
+	server1, err := l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Second connection is refused with a 503, the first one proceeds.
+	client2 := dial()
+	defer client2.Close()
+	client2.SetReadDeadline(time.Now().Add(5 * time.Second))
+	resp, err := http.ReadResponse(bufio.NewReader(client2), nil)
+	if err != nil {
+		t.Fatalf("Expected a response to the connection beyond the limit, got %v", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status %d, got %d", http.StatusServiceUnavailable, resp.StatusCode)
+	}
+	if _, err = server1.Write([]byte("ok")); err != nil {
+		t.Fatalf("Expected the first connection to proceed, got %v", err)
+	}
+	if _, err = client1.Read(make([]byte, 2)); err != nil {
+		t.Fatalf("Expected the first connection to proceed, got %v", err)
+	}
+
+	// TLS connections beyond the limit are closed without response.
+	tlsClient, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tlsClient.Close()
+	if _, err = tlsClient.Write([]byte{tlsHandshakeRecord, 3, 1}); err != nil {
+		t.Fatal(err)
+	}
+	tlsClient.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if n, rerr := tlsClient.Read(make([]byte, 1)); rerr == nil {
+		t.Fatalf("Expected TLS connection beyond the limit to be closed, read %d bytes", n)
+	}
+
+	// Closing the first connection lets a new one in.
+	server1.Close()
+	client3 := dial()
+	defer client3.Close()
+	server3, err := l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server3.Close()
+
+	current, peak, refused := limiter.stats()
+	if current != 1 || peak != 1 || refused != 2 {
+		t.Fatalf("Expected 1 current, 1 peak and 2 refused connections, got %d, %d and %d", current, peak, refused)
+	}
+
+	// Connections from servers are exempt from the limit.
+	limiter.exemptPeers([]*url.URL{{Scheme: "http", Host: "127.0.0.1:9000", Path: "/disk1"}})
+	client4 := dial()
+	defer client4.Close()
+	server4, err := l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server4.Close()
+	if current, _, _ = limiter.stats(); current != 1 {
+		t.Fatalf("Expected connections of servers not to be counted, got %d current connections", current)
+	}
+}
+
 func runTest(t *testing.T) {
 	const connectionsBeforeClose = 1
 
@@ -60,7 +156,7 @@ func runTest(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	ln = newListenerMux(ln, &tls.Config{})
+	ln = newListenerMux(ln, &tls.Config{}, nil, nil, 0)
 
 	addr := ln.Addr().String()
 	waitForListener := make(chan error)
@@ -135,7 +231,7 @@ func TestInitListeners(t *testing.T) {
 		},
 	}
 	for i, testCase := range testCases {
-		listeners, err := initListeners(testCase.serverAddr, &tls.Config{})
+		listeners, err := initListeners(testCase.serverAddr, &tls.Config{}, nil, nil, 0)
 		if testCase.shouldPass {
 			if err != nil {
 				t.Fatalf("Test %d: Unable to initialize listeners %s", i+1, err)
@@ -152,7 +248,7 @@ func TestInitListeners(t *testing.T) {
 	}
 	// Windows doesn't have 'localhost' hostname.
 	if runtime.GOOS != "windows" {
-		listeners, err := initListeners("localhost:"+getFreePort(), &tls.Config{})
+		listeners, err := initListeners("localhost:"+getFreePort(), &tls.Config{}, nil, nil, 0)
 		if err != nil {
 			t.Fatalf("Test 3: Unable to initialize listeners %s", err)
 		}
@@ -166,7 +262,7 @@ func TestInitListeners(t *testing.T) {
 
 func TestClose(t *testing.T) {
 	// Create ServerMux
-	m := NewServerMux("", nil)
+	m := NewServerMux([]string{""}, nil)
 
 	if err := m.Close(); err != nil {
 		t.Error("Server errored while trying to Close", err)
@@ -183,7 +279,7 @@ func TestServerMux(t *testing.T) {
 	defer ts.Close()
 
 	// Create ServerMux
-	m := NewServerMux("", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	m := NewServerMux([]string{""}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, "hello")
 	}))
 
@@ -237,7 +333,7 @@ func TestServerCloseBlocking(t *testing.T) {
 	defer ts.Close()
 
 	// Create ServerMux
-	m := NewServerMux("", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	m := NewServerMux([]string{""}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, "hello")
 	}))
 
@@ -297,7 +393,7 @@ func TestListenAndServePlain(t *testing.T) {
 	globalServiceSignalCh = make(chan serviceSignal, 1)
 
 	// Create ServerMux and when we receive a request we stop waiting
-	m := NewServerMux(addr, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	m := NewServerMux([]string{addr}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, "hello")
 		once.Do(func() { close(wait) })
 	}))
@@ -345,7 +441,7 @@ func TestListenAndServeTLS(t *testing.T) {
 	globalServiceDoneCh = make(chan struct{}, 1)
 
 	// Create ServerMux and when we receive a request we stop waiting
-	m := NewServerMux(addr, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	m := NewServerMux([]string{addr}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, "hello")
 		once.Do(func() { close(wait) })
 	}))
@@ -470,3 +566,564 @@ func generateTestCert(host string) error {
 	keyOut.Close()
 	return nil
 }
+
+// Env variable, holding the address to listen on, under which the
+// test binary serves for TestGracefulShutdown.
+const gracefulShutdownTestEnv = "_MINIO_GRACEFUL_SHUTDOWN_TEST_ADDR"
+
+// Serves requests until SIGTERM when run by TestGracefulShutdown,
+// `/slow` responds to a request after a second.
+func TestGracefulShutdownHelper(t *testing.T) {
+	addr := os.Getenv(gracefulShutdownTestEnv)
+	if addr == "" {
+		return
+	}
+	globalShutdownTimeout = 10 * time.Second
+	m := NewServerMux([]string{addr}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			time.Sleep(time.Second)
+		}
+		fmt.Fprint(w, "done")
+	}))
+	go m.ListenAndServe("", "")
+	<-globalServiceDoneCh
+	os.Exit(0)
+}
+
+// Tests in-flight requests are drained on SIGTERM before the server
+// exits.
+func TestGracefulShutdown(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGTERM is not supported on windows")
+	}
+	addr := net.JoinHostPort("127.0.0.1", getFreePort())
+	cmd := exec.Command(os.Args[0], "-test.run=^TestGracefulShutdownHelper$")
+	cmd.Env = append(os.Environ(), gracefulShutdownTestEnv+"="+addr)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	exitCh := make(chan error, 1)
+	go func() { exitCh <- cmd.Wait() }()
+
+	// Wait for the server to accept requests.
+	client := http.Client{Timeout: 100 * time.Millisecond}
+	for i := 0; ; i++ {
+		resp, err := client.Get("http://" + addr)
+		if err == nil {
+			resp.Body.Close()
+			break
+		}
+		if i == 100 {
+			cmd.Process.Kill()
+			t.Fatalf("Server did not start, %s", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	// Slow request is in-flight once its headers are received.
+	resp, err := http.Get("http://" + addr + "/slow")
+	if err != nil {
+		cmd.Process.Kill()
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if err = cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("In-flight request failed, %s", err)
+	}
+	if string(body) != "done" {
+		t.Errorf("Expected in-flight request to complete, got %q", body)
+	}
+
+	select {
+	case err = <-exitCh:
+		if err != nil {
+			t.Errorf("Expected server to exit cleanly, %s", err)
+		}
+	case <-time.After(10 * time.Second):
+		cmd.Process.Kill()
+		t.Fatal("Server did not exit")
+	}
+
+	// New connections are refused after shutdown.
+	if _, err = client.Get("http://" + addr); err == nil {
+		t.Error("Expected server to stop accepting connections")
+	}
+}
+
+// Tests a server listening on several addresses serves S3 requests on
+// all of them.
+func TestListenAndServeMultipleAddrs(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(fsDir)
+
+	// Initialize done channel specifically for each tests.
+	globalServiceDoneCh = make(chan struct{}, 1)
+	// Initialize signal channel specifically for each tests.
+	globalServiceSignalCh = make(chan serviceSignal, 1)
+
+	// IPv6 loopback address along with the IPv4 one if available,
+	// otherwise a second port of the IPv4 loopback address.
+	secondHost := "::1"
+	if l, lerr := net.Listen("tcp", "[::1]:0"); lerr != nil {
+		secondHost = "127.0.0.1"
+	} else {
+		l.Close()
+	}
+	addrs := []string{
+		net.JoinHostPort("127.0.0.1", getFreePort()),
+		net.JoinHostPort(secondHost, getFreePort()),
+	}
+
+	m := NewServerMux(addrs, initTestAPIEndPoints(obj, []string{"ListBuckets"}))
+	errc := make(chan error, 1)
+	go func() { errc <- m.ListenAndServe("", "") }()
+	defer m.Close()
+
+	credentials := serverConfig.GetCredential()
+	for _, addr := range addrs {
+		var resp *http.Response
+		for i := 0; ; i++ {
+			req, err := newTestSignedRequestV4("GET", "http://"+addr+"/", 0, nil,
+				credentials.AccessKey, credentials.SecretKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err = http.DefaultClient.Do(req)
+			if err == nil {
+				break
+			}
+			select {
+			case serr := <-errc:
+				t.Fatalf("Server failed to start, %v", serr)
+			default:
+			}
+			if i == 100 {
+				t.Fatalf("Server did not accept requests on %s, %s", addr, err)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("%s: Expected ListBuckets to succeed, got %s", addr, resp.Status)
+		}
+	}
+}
+
+// Tests objects are uploaded, with and without chunked signatures, and
+// read by ranges over HTTP/2 on TLS and cleartext connections.
+func TestListenAndServeHTTP2(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(fsDir)
+
+	bucketName := getRandomBucketName()
+	if err = obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = createCertsPath(); err != nil {
+		t.Fatal(err)
+	}
+	certFile := mustGetCertFile()
+	keyFile := mustGetKeyFile()
+	defer os.RemoveAll(certFile)
+	defer os.RemoveAll(keyFile)
+	if err = generateTestCert("127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(h2c bool) { globalIsH2CEnabled = h2c }(globalIsH2CEnabled)
+
+	testCases := []struct {
+		scheme    string
+		certFile  string
+		keyFile   string
+		transport *http2.Transport
+	}{
+		{"https", certFile, keyFile, &http2.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}},
+		{"http", "", "", &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+				return net.Dial(network, addr)
+			},
+		}},
+	}
+
+	credentials := serverConfig.GetCredential()
+	data := bytes.Repeat([]byte("0123456789"), 10*1024)
+	// Initialize done channel specifically for each tests.
+	globalServiceDoneCh = make(chan struct{}, 1)
+	// Initialize signal channel specifically for each tests.
+	globalServiceSignalCh = make(chan serviceSignal, 1)
+
+	for i, testCase := range testCases {
+		globalIsH2CEnabled = testCase.scheme == "http"
+		addr := net.JoinHostPort("127.0.0.1", getFreePort())
+		m := NewServerMux([]string{addr}, initTestAPIEndPoints(obj, []string{"PutObject", "GetObject"}))
+		errc := make(chan error, 1)
+		go func() { errc <- m.ListenAndServe(testCase.certFile, testCase.keyFile) }()
+
+		client := &http.Client{Transport: testCase.transport}
+		objectURL := testCase.scheme + "://" + addr + "/" + bucketName + "/object"
+		do := func(req *http.Request, expectedStatus int) []byte {
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("Test %d: %s %s: %s", i+1, req.Method, req.URL, err)
+			}
+			defer resp.Body.Close()
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Test %d: %s", i+1, err)
+			}
+			if resp.ProtoMajor != 2 {
+				t.Errorf("Test %d: Expected HTTP/2 to be negotiated, got %s", i+1, resp.Proto)
+			}
+			if resp.StatusCode != expectedStatus {
+				t.Errorf("Test %d: %s %s: Expected %d, got %s: %s", i+1, req.Method, req.URL, expectedStatus, resp.Status, body)
+			}
+			return body
+		}
+
+		// Wait for the server to accept connections.
+		for j := 0; ; j++ {
+			conn, derr := net.Dial("tcp", addr)
+			if derr == nil {
+				conn.Close()
+				break
+			}
+			select {
+			case serr := <-errc:
+				t.Fatalf("Test %d: Server failed to start, %v", i+1, serr)
+			default:
+			}
+			if j == 100 {
+				t.Fatalf("Test %d: Server did not accept connections, %s", i+1, derr)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		req, err := newTestSignedRequestV4("PUT", objectURL, int64(len(data)), bytes.NewReader(data),
+			credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		do(req, http.StatusOK)
+
+		req, err = newTestStreamingSignedRequest("PUT", objectURL+"-chunked", int64(len(data)), 64*1024,
+			bytes.NewReader(data), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		do(req, http.StatusOK)
+
+		// Body of unknown length is sent without a content length.
+		req, err = newTestSignedRequestV4("PUT", objectURL+"-unknown-length", int64(len(data)), bytes.NewReader(data),
+			credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.ContentLength = -1
+		do(req, http.StatusOK)
+
+		for _, objectName := range []string{"object", "object-chunked", "object-unknown-length"} {
+			req, err = newTestSignedRequestV4("GET", testCase.scheme+"://"+addr+"/"+bucketName+"/"+objectName, 0, nil,
+				credentials.AccessKey, credentials.SecretKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if body := do(req, http.StatusOK); !bytes.Equal(body, data) {
+				t.Errorf("Test %d: %s: Downloaded object differs from the uploaded one", i+1, objectName)
+			}
+
+			req, err = newTestSignedRequestV4("GET", testCase.scheme+"://"+addr+"/"+bucketName+"/"+objectName, 0, nil,
+				credentials.AccessKey, credentials.SecretKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Range", "bytes=10-19")
+			if body := do(req, http.StatusPartialContent); !bytes.Equal(body, data[10:20]) {
+				t.Errorf("Test %d: %s: Expected range %q, got %q", i+1, objectName, data[10:20], body)
+			}
+		}
+
+		testCase.transport.CloseIdleConnections()
+		m.Close()
+	}
+}
+
+// generateTestClientCert creates a certificate for client
+// authentication with subject commonName, signed by the CA certificate
+// caCert with the key caKey, or self signed if caCert is nil. Returns
+// the certificate and its key.
+func generateTestClientCert(commonName string, caCert *x509.Certificate, caKey *rsa.PrivateKey) (*x509.Certificate, *rsa.PrivateKey, error) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
+	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: []string{"Minio Test Cert"},
+			CommonName:   commonName,
+		},
+		NotBefore: time.Now().UTC(),
+		NotAfter:  time.Now().UTC().Add(time.Minute * 1),
+
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+	if caCert == nil {
+		template.IsCA = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		caCert, caKey = template, priv
+	}
+
+	derBytes, err := x509.CreateCertificate(rand.Reader, template, caCert, &priv.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(derBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, priv, nil
+}
+
+func TestListenAndServeClientCert(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(fsDir)
+
+	bucketName := getRandomBucketName()
+	if err = obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = createCertsPath(); err != nil {
+		t.Fatal(err)
+	}
+	certFile := mustGetCertFile()
+	keyFile := mustGetKeyFile()
+	defer os.RemoveAll(certFile)
+	defer os.RemoveAll(keyFile)
+	if err = generateTestCert("127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+
+	caCert, caKey, err := generateTestClientCert("Minio Test Client CA", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	untrustedCACert, untrustedCAKey, err := generateTestClientCert("Minio Untrusted Client CA", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newClientCert := func(commonName string, caCert *x509.Certificate, caKey *rsa.PrivateKey) []tls.Certificate {
+		cert, key, gerr := generateTestClientCert(commonName, caCert, caKey)
+		if gerr != nil {
+			t.Fatal(gerr)
+		}
+		return []tls.Certificate{{Certificate: [][]byte{cert.Raw}, PrivateKey: key}}
+	}
+
+	defer func(required bool, clientCAs *x509.CertPool) {
+		globalIsClientCertRequired, globalClientCAs = required, clientCAs
+	}(globalIsClientCertRequired, globalClientCAs)
+	globalIsClientCertRequired = true
+	globalClientCAs = x509.NewCertPool()
+	globalClientCAs.AddCert(caCert)
+
+	// Requests of the client with common name "owner" are
+	// authenticated as the owner of the server credentials.
+	serverConfig.SetClientCertIdentity("owner", serverConfig.GetCredential().AccessKey)
+
+	// Initialize done channel specifically for each tests.
+	globalServiceDoneCh = make(chan struct{}, 1)
+	// Initialize signal channel specifically for each tests.
+	globalServiceSignalCh = make(chan serviceSignal, 1)
+
+	addr := net.JoinHostPort("127.0.0.1", getFreePort())
+	m := NewServerMux([]string{addr}, initTestAPIEndPoints(obj, []string{"PutObject"}))
+	errc := make(chan error, 1)
+	go func() { errc <- m.ListenAndServe(certFile, keyFile) }()
+	defer m.Close()
+
+	// Wait for the server to accept connections.
+	for i := 0; ; i++ {
+		conn, derr := net.Dial("tcp", addr)
+		if derr == nil {
+			conn.Close()
+			break
+		}
+		select {
+		case serr := <-errc:
+			t.Fatalf("Server failed to start, %v", serr)
+		default:
+		}
+		if i == 100 {
+			t.Fatalf("Server did not accept connections, %s", derr)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	testCases := []struct {
+		certs          []tls.Certificate
+		expectedStatus int // zero if the TLS handshake is expected to fail
+	}{
+		// Test case - 1.
+		// No client certificate.
+		{nil, 0},
+		// Test case - 2.
+		// Client certificate not signed by a client CA.
+		{newClientCert("owner", untrustedCACert, untrustedCAKey), 0},
+		// Test case - 3.
+		// Client certificate of the owner.
+		{newClientCert("owner", caCert, caKey), http.StatusOK},
+		// Test case - 4.
+		// Client certificate of an unknown identity, anonymous.
+		{newClientCert("stranger", caCert, caKey), http.StatusForbidden},
+	}
+
+	data := []byte("hello")
+	for i, testCase := range testCases {
+		client := &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: true,
+					Certificates:       testCase.certs,
+				},
+			},
+		}
+		req, err := http.NewRequest("PUT", "https://"+addr+"/"+bucketName+"/object", bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if testCase.expectedStatus == 0 {
+			if err == nil {
+				resp.Body.Close()
+				t.Errorf("Test %d: Expected the TLS handshake to fail, got %s", i+1, resp.Status)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != testCase.expectedStatus {
+			t.Errorf("Test %d: Expected %d, got %s", i+1, testCase.expectedStatus, resp.Status)
+		}
+	}
+}
+
+// Tests clients sending their headers slowly are disconnected after
+// the read header timeout.
+func TestListenAndServeReadHeaderTimeout(t *testing.T) {
+	// Reset so that we don't affect other tests.
+	defer func(timeout time.Duration) { globalReadHeaderTimeout = timeout }(globalReadHeaderTimeout)
+	globalReadHeaderTimeout = 200 * time.Millisecond
+
+	addr := net.JoinHostPort("127.0.0.1", getFreePort())
+	globalServiceDoneCh = make(chan struct{}, 1)
+	globalServiceSignalCh = make(chan serviceSignal, 1)
+	m := NewServerMux([]string{addr}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "hello")
+	}))
+	if m.ReadHeaderTimeout != 200*time.Millisecond {
+		t.Fatalf("Expected read header timeout of 200ms, got %s", m.ReadHeaderTimeout)
+	}
+	go m.ListenAndServe("", "")
+	defer m.Close()
+
+	// Wait until the server is accepting connections.
+	client := http.Client{Timeout: time.Second}
+	for {
+		resp, err := client.Get("http://" + addr)
+		if err == nil {
+			resp.Body.Close()
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	testCases := []string{
+		// Test case - 1.
+		// Client sending nothing.
+		"",
+		// Test case - 2.
+		// Client stalling in the middle of its headers.
+		"GET / HTTP/1.1\r\nHost: " + addr + "\r\n",
+	}
+	for i, testCase := range testCases {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		start := time.Now()
+		if _, err = conn.Write([]byte(testCase)); err != nil {
+			t.Fatal(err)
+		}
+		// The server closes the connection, at most after a reply.
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, err = ioutil.ReadAll(conn)
+		if nerr, ok := err.(net.Error); ok && nerr.Timeout() {
+			t.Errorf("Test %d: Expected stalled connection to be closed", i+1)
+		}
+		if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+			t.Errorf("Test %d: Expected connection to be kept for 200ms, closed after %s", i+1, elapsed)
+		}
+		conn.Close()
+	}
+
+	// Clients sending their headers in time are served.
+	resp, err := client.Get("http://" + addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected %d, got %s", http.StatusOK, resp.Status)
+	}
+}
@@ -26,13 +26,21 @@ import (
 
 // For all unixes we need to bump allowed number of open files to a
 // higher value than its usual default of '1024'. The reasoning is
-// that this value is too small for a server.
-func setMaxOpenFiles() error {
+// that this value is too small for a server. A non zero maxOpenFiles
+// is set instead, raising it above the hard limit requires privileges.
+func setMaxOpenFiles(maxOpenFiles uint64) error {
 	var rLimit syscall.Rlimit
 	err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rLimit)
 	if err != nil {
 		return err
 	}
+	if maxOpenFiles > 0 {
+		rLimit.Cur = maxOpenFiles
+		if rLimit.Max < maxOpenFiles {
+			rLimit.Max = maxOpenFiles
+		}
+		return syscall.Setrlimit(syscall.RLIMIT_NOFILE, &rLimit)
+	}
 	// Set the current limit to Max, it is usually around 4096.
 	// TO increase this limit further user has to manually edit
 	// `/etc/security/limits.conf`
@@ -40,12 +48,27 @@ func setMaxOpenFiles() error {
 	return syscall.Setrlimit(syscall.RLIMIT_NOFILE, &rLimit)
 }
 
+// getMaxOpenFiles - returns the current limit of open files of the
+// process, zero if unknown.
+func getMaxOpenFiles() uint64 {
+	var rLimit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rLimit); err != nil {
+		return 0
+	}
+	return uint64(rLimit.Cur)
+}
+
 // Set max memory used by minio as a process, this value is usually
 // set to 'unlimited' but we need to validate additionally to verify
 // if any hard limit is set by the user, in such a scenario would need
 // to reset the global max cache size to be 80% of the hardlimit set
 // by the user. This is done to honor the system limits and not crash.
-func setMaxMemory() error {
+// A non zero maxMemory sizes the cache to half of it instead.
+func setMaxMemory(maxMemory uint64) error {
+	if maxMemory > 0 {
+		globalMaxCacheSize = maxMemory / 2
+		return nil
+	}
 	var rLimit syscall.Rlimit
 	err := syscall.Getrlimit(syscall.RLIMIT_AS, &rLimit)
 	if err != nil {
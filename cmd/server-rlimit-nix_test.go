@@ -0,0 +1,58 @@
+// +build !windows,!plan9
+
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"syscall"
+	"testing"
+)
+
+// Tests MINIO_MAX_OPEN_FILES is applied instead of the hard limit.
+func TestSetMaxOpenFilesOverride(t *testing.T) {
+	var rLimit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rLimit); err != nil {
+		t.Fatal(err)
+	}
+	defer syscall.Setrlimit(syscall.RLIMIT_NOFILE, &rLimit)
+	if rLimit.Max < minSafeOpenFiles {
+		t.Skip("Hard limit of open files is too low")
+	}
+
+	if err := setMaxOpenFiles(minSafeOpenFiles); err != nil {
+		t.Fatal(err)
+	}
+	var applied syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &applied); err != nil {
+		t.Fatal(err)
+	}
+	if applied.Cur != minSafeOpenFiles {
+		t.Fatalf("Expected open files limit of %d, got %d", minSafeOpenFiles, applied.Cur)
+	}
+
+	// Without override the limit is raised to the hard limit.
+	if err := setMaxOpenFiles(0); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &applied); err != nil {
+		t.Fatal(err)
+	}
+	if applied.Cur != applied.Max {
+		t.Fatalf("Expected open files limit of %d, got %d", applied.Max, applied.Cur)
+	}
+}
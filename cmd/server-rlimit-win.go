@@ -20,14 +20,23 @@ package cmd
 
 import "github.com/minio/minio/pkg/sys"
 
-func setMaxOpenFiles() error {
+func setMaxOpenFiles(maxOpenFiles uint64) error {
 	// Golang uses Win32 file API (CreateFile, WriteFile, ReadFile,
 	// CloseHandle, etc.), then you don't have a limit on open files
 	// (well, you do but it is based on your resources like memory).
 	return nil
 }
 
-func setMaxMemory() error {
+// getMaxOpenFiles - open files are not limited on windows.
+func getMaxOpenFiles() uint64 {
+	return 0
+}
+
+func setMaxMemory(maxMemory uint64) error {
+	if maxMemory > 0 {
+		globalMaxCacheSize = maxMemory / 2
+		return nil
+	}
 	// Make sure globalMaxCacheSize is less than RAM size.
 	stats, err := sys.GetStats()
 	if err != nil && err != sys.ErrNotImplemented {
@@ -0,0 +1,91 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+const (
+	// Open files below which the server runs out of file
+	// descriptors under moderate load.
+	minSafeOpenFiles = 4096
+
+	// Memory below which the object cache is too small to be useful.
+	minSafeMemory = 1 * humanize.GiByte
+)
+
+// parseMaxOpenFiles - returns the maximum number of open files set by
+// MINIO_MAX_OPEN_FILES, zero if value is empty to raise the limit to
+// the hard limit of the system.
+func parseMaxOpenFiles(value string) (uint64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	maxOpenFiles, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || maxOpenFiles == 0 {
+		return 0, fmt.Errorf("Invalid maximum open files %s, expected a positive number", value)
+	}
+	return maxOpenFiles, nil
+}
+
+// parseMaxMemory - returns the memory in bytes set by MINIO_MAX_MEMORY
+// such as "16GiB", zero if value is empty to size the object cache
+// from the memory of the system.
+func parseMaxMemory(value string) (uint64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	maxMemory, err := humanize.ParseBytes(value)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid maximum memory %s, %s", value, err)
+	}
+	if maxMemory == 0 {
+		return 0, fmt.Errorf("Maximum memory must be at least 1 byte")
+	}
+	return maxMemory, nil
+}
+
+// getLimitsWarning - returns a warning for limits set below safe
+// minimums, empty if none is. Zero limits are tuned automatically.
+func getLimitsWarning(maxOpenFiles, maxMemory uint64) string {
+	var msg string
+	if maxOpenFiles > 0 && maxOpenFiles < minSafeOpenFiles {
+		msg += fmt.Sprintf("MINIO_MAX_OPEN_FILES %d is below %d, requests may fail with too many open files.\n",
+			maxOpenFiles, minSafeOpenFiles)
+	}
+	if maxMemory > 0 && maxMemory < minSafeMemory {
+		msg += fmt.Sprintf("MINIO_MAX_MEMORY %s is below %s, the object cache is of little use.\n",
+			humanize.IBytes(maxMemory), humanize.IBytes(minSafeMemory))
+	}
+	return msg
+}
+
+// defaultMaxConnections - returns the default limit of open client
+// connections for a limit of maxOpenFiles, half of the files are left
+// to disks and connections between servers. Zero, unlimited, if the
+// limit of open files is unknown or unlimited.
+func defaultMaxConnections(maxOpenFiles uint64) int {
+	if maxOpenFiles == 0 || maxOpenFiles > math.MaxInt32 {
+		return 0
+	}
+	return int(maxOpenFiles / 2)
+}
@@ -0,0 +1,120 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Tests parsing of MINIO_MAX_OPEN_FILES and MINIO_MAX_MEMORY.
+func TestParseLimits(t *testing.T) {
+	openFilesCases := []struct {
+		value    string
+		expected uint64
+		success  bool
+	}{
+		{"", 0, true},
+		{"65536", 65536, true},
+		{"0", 0, false},
+		{"-1", 0, false},
+		{"many", 0, false},
+	}
+	for i, test := range openFilesCases {
+		maxOpenFiles, err := parseMaxOpenFiles(test.value)
+		if (err == nil) != test.success {
+			t.Errorf("Test %d - expected success %t, got error %v", i+1, test.success, err)
+		}
+		if maxOpenFiles != test.expected {
+			t.Errorf("Test %d - expected %d, got %d", i+1, test.expected, maxOpenFiles)
+		}
+	}
+
+	memoryCases := []struct {
+		value    string
+		expected uint64
+		success  bool
+	}{
+		{"", 0, true},
+		{"16GiB", 16 * humanize.GiByte, true},
+		{"512MB", 512 * humanize.MByte, true},
+		{"0", 0, false},
+		{"lots", 0, false},
+	}
+	for i, test := range memoryCases {
+		maxMemory, err := parseMaxMemory(test.value)
+		if (err == nil) != test.success {
+			t.Errorf("Test %d - expected success %t, got error %v", i+1, test.success, err)
+		}
+		if maxMemory != test.expected {
+			t.Errorf("Test %d - expected %d, got %d", i+1, test.expected, maxMemory)
+		}
+	}
+}
+
+// Tests warnings on limits below safe minimums.
+func TestLimitsWarning(t *testing.T) {
+	if msg := getLimitsWarning(0, 0); msg != "" {
+		t.Errorf("Expected no warning for automatic limits, got %s", msg)
+	}
+	if msg := getLimitsWarning(minSafeOpenFiles, minSafeMemory); msg != "" {
+		t.Errorf("Expected no warning for safe limits, got %s", msg)
+	}
+	msg := getLimitsWarning(1024, 0)
+	if !strings.Contains(msg, "MINIO_MAX_OPEN_FILES") || strings.Contains(msg, "MINIO_MAX_MEMORY") {
+		t.Errorf("Expected warning on open files only, got %s", msg)
+	}
+	msg = getLimitsWarning(0, 256*humanize.MiByte)
+	if strings.Contains(msg, "MINIO_MAX_OPEN_FILES") || !strings.Contains(msg, "MINIO_MAX_MEMORY") {
+		t.Errorf("Expected warning on memory only, got %s", msg)
+	}
+}
+
+// Tests the object cache is sized from MINIO_MAX_MEMORY.
+func TestSetMaxMemoryOverride(t *testing.T) {
+	defer func(cacheSize uint64) { globalMaxCacheSize = cacheSize }(globalMaxCacheSize)
+
+	if err := setMaxMemory(4 * humanize.GiByte); err != nil {
+		t.Fatal(err)
+	}
+	if globalMaxCacheSize != 2*humanize.GiByte {
+		t.Fatalf("Expected cache size of %d, got %d", 2*humanize.GiByte, globalMaxCacheSize)
+	}
+}
+
+// Tests the default limit of open client connections.
+func TestDefaultMaxConnections(t *testing.T) {
+	testCases := []struct {
+		maxOpenFiles uint64
+		expected     int
+	}{
+		// Unknown limit of open files.
+		{0, 0},
+		{1024, 512},
+		{65536, 32768},
+		// Unlimited open files.
+		{math.MaxUint64, 0},
+	}
+	for i, test := range testCases {
+		if maxConns := defaultMaxConnections(test.maxOpenFiles); maxConns != test.expected {
+			t.Errorf("Test %d - expected %d, got %d", i+1, test.expected, maxConns)
+		}
+	}
+}
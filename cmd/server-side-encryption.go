@@ -0,0 +1,594 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+)
+
+const (
+	// S3 header requesting and reporting server-side encryption.
+	amzServerSideEncryptionHeader = "X-Amz-Server-Side-Encryption"
+
+	// Only S3 server-side encryption algorithm supported.
+	sseAlgorithmAES256 = "AES256"
+
+	// Metadata keys of the key of an encrypted object, sealed with the
+	// master key, and of the initialization vector of its data.
+	sseSealedKeyMetaKey = "X-Minio-Internal-Server-Side-Encryption-Sealed-Key"
+	sseIVMetaKey        = "X-Minio-Internal-Server-Side-Encryption-Iv"
+
+	// Size of master keys and of keys of objects, AES-256.
+	sseKeySize = 32
+)
+
+// Metadata describing the encryption of an object.
+var sseMetaKeys = []string{amzServerSideEncryptionHeader, sseSealedKeyMetaKey, sseIVMetaKey}
+
+// deleteSealedKey - removes the key of another object from metadata,
+// keys are never shared by objects.
+func deleteSealedKey(metadata map[string]string) {
+	delete(metadata, sseSealedKeyMetaKey)
+	delete(metadata, sseIVMetaKey)
+}
+
+// errInvalidSSEMasterKey - master key is not hex encoded 256 bits.
+var errInvalidSSEMasterKey = errors.New("Master key must be 64 hex characters")
+
+// errSSEUnsealKey - key of an object cannot be unsealed, e.g. it was
+// sealed with another master key.
+var errSSEUnsealKey = errors.New("Unable to unseal the key of the object with the master key")
+
+// parseSSEMasterKey - returns the master key of hex encoded key, nil
+// if empty for server-side encryption disabled.
+func parseSSEMasterKey(key string) ([]byte, error) {
+	if key == "" {
+		return nil, nil
+	}
+	masterKey, err := hex.DecodeString(key)
+	if err != nil || len(masterKey) != sseKeySize {
+		return nil, errInvalidSSEMasterKey
+	}
+	return masterKey, nil
+}
+
+// checkEncryptedBuckets - verifies a master key is set if buckets of
+// config are encrypted by default.
+func checkEncryptedBuckets(config *serverConfigV13) error {
+	if len(config.GetEncryptedBuckets()) > 0 && globalSSEMasterKey == nil {
+		return errors.New("Encrypted buckets require MINIO_SSE_MASTER_KEY to be set")
+	}
+	return nil
+}
+
+// setServerSideEncryptionFromHeader - saves into metadata the
+// server-side encryption of x-amz-server-side-encryption, if set.
+// Objects are otherwise encrypted if their bucket is.
+func setServerSideEncryptionFromHeader(header http.Header, metadata map[string]string) APIErrorCode {
+	delete(metadata, amzServerSideEncryptionHeader)
+	if _, ok := header[amzServerSideEncryptionHeader]; !ok {
+		return ErrNone
+	}
+	if header.Get(amzServerSideEncryptionHeader) != sseAlgorithmAES256 {
+		return ErrInvalidEncryptionMethod
+	}
+	if globalSSEMasterKey == nil {
+		return ErrServerSideEncryptionNotConfigured
+	}
+	metadata[amzServerSideEncryptionHeader] = sseAlgorithmAES256
+	return ErrNone
+}
+
+// setServerSideEncryptionHeader - sets x-amz-server-side-encryption, or
+// the algorithm and key MD5 of SSE-C, of the response if objInfo is
+// encrypted.
+func setServerSideEncryptionHeader(w http.ResponseWriter, objInfo ObjectInfo) {
+	for _, key := range []string{amzServerSideEncryptionHeader, sseCustomerAlgorithmHeader, sseCustomerKeyMD5Header} {
+		if value, ok := objInfo.UserDefined[key]; ok {
+			w.Header().Set(key, value)
+		}
+	}
+}
+
+// isObjectEncrypted - returns true if the data of an object with
+// metadata is encrypted.
+func isObjectEncrypted(metadata map[string]string) bool {
+	_, ok := metadata[sseSealedKeyMetaKey]
+	return ok
+}
+
+// isEncryptionRequested - returns true if an object of bucket with
+// metadata is to be encrypted.
+func isEncryptionRequested(bucket string, metadata map[string]string) bool {
+	// Objects encrypted with a customer key are not encrypted again.
+	if isObjectCustomerEncrypted(metadata) {
+		return false
+	}
+	return metadata[amzServerSideEncryptionHeader] == sseAlgorithmAES256 ||
+		serverConfig.IsBucketEncrypted(bucket)
+}
+
+// sealKey - encrypts key with masterKey, the nonce is prepended.
+func sealKey(masterKey, key []byte) (string, error) {
+	block, err := aes.NewCipher(masterKey)
+	if err != nil {
+		return "", err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, key, nil)), nil
+}
+
+// unsealKey - decrypts a key sealed with masterKey by sealKey.
+func unsealKey(masterKey []byte, sealedKey string) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(sealedKey)
+	if err != nil {
+		return nil, errSSEUnsealKey
+	}
+	block, err := aes.NewCipher(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errSSEUnsealKey
+	}
+	key, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, errSSEUnsealKey
+	}
+	return key, nil
+}
+
+// newCTRStream - returns the AES-CTR key stream of key and iv starting
+// at offset of the data, such that ranges are decrypted independently.
+func newCTRStream(key, iv []byte, offset int64) (cipher.Stream, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, errSSEUnsealKey
+	}
+
+	// Counter of the block of offset, the counter is a 128 bits
+	// big endian integer wrapping around as in crypto/cipher.
+	counter := make([]byte, aes.BlockSize)
+	copy(counter, iv)
+	blocks := uint64(offset / aes.BlockSize)
+	for i := aes.BlockSize - 1; i >= 0 && blocks > 0; i-- {
+		blocks += uint64(counter[i])
+		counter[i] = byte(blocks)
+		blocks >>= 8
+	}
+
+	stream := cipher.NewCTR(block, counter)
+	// Skip the key stream of the offset within its block.
+	skip := make([]byte, offset%aes.BlockSize)
+	stream.XORKeyStream(skip, skip)
+	return stream, nil
+}
+
+// sseVerifyReader - computes the MD5 and SHA256 sums of the content of
+// an object before it is encrypted and verifies them against the sums
+// sent by the client, once size bytes are read or at EOF if the size
+// is unknown.
+type sseVerifyReader struct {
+	reader io.Reader
+	size   int64
+	read   int64
+
+	md5Hex    string
+	md5Hash   hash.Hash
+	sha256sum string
+	sha256    hash.Hash
+}
+
+func newSSEVerifyReader(reader io.Reader, size int64, md5Hex, sha256sum string) *sseVerifyReader {
+	return &sseVerifyReader{
+		reader:    reader,
+		size:      size,
+		md5Hex:    md5Hex,
+		md5Hash:   md5.New(),
+		sha256sum: sha256sum,
+		sha256:    sha256.New(),
+	}
+}
+
+func (r *sseVerifyReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.md5Hash.Write(p[:n])
+	r.sha256.Write(p[:n])
+	r.read += int64(n)
+	// Incomplete bodies are reported by the object layer.
+	if (err == io.EOF && r.size < 0) || (r.size >= 0 && r.read == r.size) {
+		if verr := r.verify(); verr != nil {
+			return n, verr
+		}
+	}
+	return n, err
+}
+
+// verify - returns an error if the content read differs from the
+// content sent by the client. Errors are traced by the object layer
+// reading from r, such that their cause is the error itself.
+func (r *sseVerifyReader) verify() error {
+	if r.md5Hex != "" {
+		if md5Hex := hex.EncodeToString(r.md5Hash.Sum(nil)); md5Hex != r.md5Hex {
+			return BadDigest{r.md5Hex, md5Hex}
+		}
+	}
+	if r.sha256sum != "" && hex.EncodeToString(r.sha256.Sum(nil)) != r.sha256sum {
+		return SHA256Mismatch{}
+	}
+	return nil
+}
+
+// sseObjects - object layer encrypting objects of encrypted buckets,
+// or uploaded with x-amz-server-side-encryption, with a key of their
+// own sealed with the master key. Objects are encrypted with AES-CTR
+// such that their size is kept and ranges are decrypted independently,
+// their ETag is the MD5 sum of the encrypted data.
+type sseObjects struct {
+	ObjectLayer
+	masterKey []byte
+}
+
+// newSSEObjects - returns objAPI encrypting objects with masterKey.
+func newSSEObjects(objAPI ObjectLayer, masterKey []byte) ObjectLayer {
+	return sseObjects{
+		ObjectLayer: objAPI,
+		masterKey:   masterKey,
+	}
+}
+
+// newObjectStream - returns the key stream of the data of an encrypted
+// object with metadata starting at offset.
+func (s sseObjects) newObjectStream(metadata map[string]string, offset int64) (cipher.Stream, error) {
+	key, err := unsealKey(s.masterKey, metadata[sseSealedKeyMetaKey])
+	if err != nil {
+		return nil, traceError(err)
+	}
+	iv, err := base64.StdEncoding.DecodeString(metadata[sseIVMetaKey])
+	if err != nil {
+		return nil, traceError(errSSEUnsealKey)
+	}
+	stream, err := newCTRStream(key, iv, offset)
+	if err != nil {
+		return nil, traceError(err)
+	}
+	return stream, nil
+}
+
+// PutObject - creates an object, encrypted if requested. The content
+// sent by the client is verified before it is encrypted.
+func (s sseObjects) PutObject(bucket, object string, size int64, data io.Reader, metadata map[string]string, sha256sum string) (ObjectInfo, error) {
+	deleteSealedKey(metadata)
+	if !isEncryptionRequested(bucket, metadata) {
+		return s.ObjectLayer.PutObject(bucket, object, size, data, metadata, sha256sum)
+	}
+
+	key := make([]byte, sseKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return ObjectInfo{}, traceError(err)
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return ObjectInfo{}, traceError(err)
+	}
+	sealedKey, err := sealKey(s.masterKey, key)
+	if err != nil {
+		return ObjectInfo{}, traceError(err)
+	}
+	stream, err := newCTRStream(key, iv, 0)
+	if err != nil {
+		return ObjectInfo{}, traceError(err)
+	}
+
+	reader := newSSEVerifyReader(data, size, metadata["md5Sum"], sha256sum)
+	// The object layer saves the MD5 sum of the encrypted data.
+	delete(metadata, "md5Sum")
+	metadata[amzServerSideEncryptionHeader] = sseAlgorithmAES256
+	metadata[sseSealedKeyMetaKey] = sealedKey
+	metadata[sseIVMetaKey] = base64.StdEncoding.EncodeToString(iv)
+	return s.ObjectLayer.PutObject(bucket, object, size, cipher.StreamReader{S: stream, R: reader}, metadata, "")
+}
+
+// GetObject - writes length bytes of an object at startOffset,
+// decrypted if it is encrypted.
+func (s sseObjects) GetObject(bucket, object string, startOffset int64, length int64, writer io.Writer) error {
+	objInfo, err := s.ObjectLayer.GetObjectInfo(bucket, object)
+	if err != nil {
+		return err
+	}
+	if !isObjectEncrypted(objInfo.UserDefined) {
+		return s.ObjectLayer.GetObject(bucket, object, startOffset, length, writer)
+	}
+	stream, err := s.newObjectStream(objInfo.UserDefined, startOffset)
+	if err != nil {
+		return err
+	}
+	return s.ObjectLayer.GetObject(bucket, object, startOffset, length, cipher.StreamWriter{S: stream, W: writer})
+}
+
+// CopyObject - copies an object, the copy is encrypted with a key of
+// its own if requested. Copies onto the source only update its
+// metadata and keep the encryption of its data.
+func (s sseObjects) CopyObject(srcBucket, srcObject, destBucket, destObject string, metadata map[string]string) (ObjectInfo, error) {
+	srcInfo, err := s.ObjectLayer.GetObjectInfo(srcBucket, srcObject)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	if srcBucket == destBucket && srcObject == destObject {
+		for _, key := range sseMetaKeys {
+			delete(metadata, key)
+			if value, ok := srcInfo.UserDefined[key]; ok {
+				metadata[key] = value
+			}
+		}
+		return s.ObjectLayer.CopyObject(srcBucket, srcObject, destBucket, destObject, metadata)
+	}
+	deleteSealedKey(metadata)
+	if !isObjectEncrypted(srcInfo.UserDefined) && !isEncryptionRequested(destBucket, metadata) {
+		return s.ObjectLayer.CopyObject(srcBucket, srcObject, destBucket, destObject, metadata)
+	}
+
+	// Data of the source is decrypted and encrypted again if needed.
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		pipeWriter.CloseWithError(s.GetObject(srcBucket, srcObject, 0, srcInfo.Size, pipeWriter))
+	}()
+	objInfo, err := s.PutObject(destBucket, destObject, srcInfo.Size, pipeReader, metadata, "")
+	// Unblock the source if the copy failed.
+	pipeReader.CloseWithError(err)
+	return objInfo, err
+}
+
+// NewMultipartUpload - initiates a multipart upload, uploads of
+// objects to be encrypted are not supported.
+func (s sseObjects) NewMultipartUpload(bucket, object string, metadata map[string]string) (string, error) {
+	deleteSealedKey(metadata)
+	if isEncryptionRequested(bucket, metadata) {
+		return "", traceError(MultipartEncryptionNotSupported{Bucket: bucket, Object: object})
+	}
+	return s.ObjectLayer.NewMultipartUpload(bucket, object, metadata)
+}
+
+const (
+	// SSE-C headers of requests, the algorithm and key MD5 are saved
+	// with objects and sent back in responses as with S3.
+	sseCustomerAlgorithmHeader = "X-Amz-Server-Side-Encryption-Customer-Algorithm"
+	sseCustomerKeyHeader       = "X-Amz-Server-Side-Encryption-Customer-Key"
+	sseCustomerKeyMD5Header    = "X-Amz-Server-Side-Encryption-Customer-Key-Md5"
+
+	// SSE-C headers of the source of CopyObject and CopyObjectPart.
+	sseCopySourceCustomerAlgorithmHeader = "X-Amz-Copy-Source-Server-Side-Encryption-Customer-Algorithm"
+	sseCopySourceCustomerKeyHeader       = "X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key"
+	sseCopySourceCustomerKeyMD5Header    = "X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key-Md5"
+
+	// Metadata keys of the key of an object encrypted with a customer
+	// key, sealed with the customer key, and of the initialization
+	// vector of its data. Customer keys are never saved.
+	sseCustomerSealedKeyMetaKey = "X-Minio-Internal-Server-Side-Encryption-Customer-Sealed-Key"
+	sseCustomerIVMetaKey        = "X-Minio-Internal-Server-Side-Encryption-Customer-Iv"
+)
+
+// Metadata describing the encryption of an object with a customer key.
+var sseCustomerMetaKeys = []string{
+	sseCustomerAlgorithmHeader, sseCustomerKeyMD5Header,
+	sseCustomerSealedKeyMetaKey, sseCustomerIVMetaKey,
+}
+
+// errSSECustomerEncryptedObject - object is encrypted with a customer
+// key which is not part of the request.
+var errSSECustomerEncryptedObject = errors.New("Object is encrypted with a customer provided key")
+
+// sseCustomerHeaders - names of the SSE-C headers of a request.
+type sseCustomerHeaders struct {
+	algorithm string
+	key       string
+	keyMD5    string
+}
+
+var (
+	// SSE-C headers of the object created or read.
+	sseCustomerRequestHeaders = sseCustomerHeaders{
+		algorithm: sseCustomerAlgorithmHeader,
+		key:       sseCustomerKeyHeader,
+		keyMD5:    sseCustomerKeyMD5Header,
+	}
+	// SSE-C headers of the source object of a copy.
+	sseCustomerCopySourceHeaders = sseCustomerHeaders{
+		algorithm: sseCopySourceCustomerAlgorithmHeader,
+		key:       sseCopySourceCustomerKeyHeader,
+		keyMD5:    sseCopySourceCustomerKeyMD5Header,
+	}
+)
+
+// parseKey - returns the customer key of header, nil if no SSE-C
+// header is set. The key is only accepted over TLS and if its MD5 sum
+// is the one sent by the client.
+func (h sseCustomerHeaders) parseKey(header http.Header) ([]byte, APIErrorCode) {
+	_, algorithmSet := header[h.algorithm]
+	_, keySet := header[h.key]
+	_, keyMD5Set := header[h.keyMD5]
+	if !algorithmSet && !keySet && !keyMD5Set {
+		return nil, ErrNone
+	}
+	if !globalIsSSL {
+		return nil, ErrInsecureSSECustomerRequest
+	}
+	if header.Get(h.algorithm) != sseAlgorithmAES256 {
+		return nil, ErrInvalidSSECustomerAlgorithm
+	}
+	if header.Get(h.key) == "" {
+		return nil, ErrMissingSSECustomerKey
+	}
+	key, err := base64.StdEncoding.DecodeString(header.Get(h.key))
+	if err != nil || len(key) != sseKeySize {
+		return nil, ErrInvalidSSECustomerKey
+	}
+	if header.Get(h.keyMD5) == "" {
+		return nil, ErrMissingSSECustomerKeyMD5
+	}
+	keyMD5, err := base64.StdEncoding.DecodeString(header.Get(h.keyMD5))
+	if sum := md5.Sum(key); err != nil || !bytes.Equal(keyMD5, sum[:]) {
+		return nil, ErrSSECustomerKeyMD5Mismatch
+	}
+	return key, ErrNone
+}
+
+// readStream - returns the key stream decrypting, from offset, an
+// object with metadata with the customer key of header, nil if the
+// object is not encrypted with a customer key.
+func (h sseCustomerHeaders) readStream(header http.Header, metadata map[string]string, offset int64) (cipher.Stream, APIErrorCode) {
+	customerKey, s3Error := h.parseKey(header)
+	if s3Error != ErrNone {
+		return nil, s3Error
+	}
+	if !isObjectCustomerEncrypted(metadata) {
+		if customerKey != nil {
+			return nil, ErrInvalidEncryptionParameters
+		}
+		return nil, ErrNone
+	}
+	if customerKey == nil {
+		return nil, ErrSSEEncryptedObject
+	}
+	key, err := unsealKey(customerKey, metadata[sseCustomerSealedKeyMetaKey])
+	if err != nil {
+		return nil, ErrSSECustomerKeyMismatch
+	}
+	iv, err := base64.StdEncoding.DecodeString(metadata[sseCustomerIVMetaKey])
+	if err != nil {
+		errorIf(err, "Unable to decode the iv of an encrypted object.")
+		return nil, ErrInternalError
+	}
+	stream, err := newCTRStream(key, iv, offset)
+	if err != nil {
+		errorIf(err, "Unable to decrypt an encrypted object.")
+		return nil, ErrInternalError
+	}
+	return stream, ErrNone
+}
+
+// isObjectCustomerEncrypted - returns true if the data of an object
+// with metadata is encrypted with a customer key.
+func isObjectCustomerEncrypted(metadata map[string]string) bool {
+	_, ok := metadata[sseCustomerSealedKeyMetaKey]
+	return ok
+}
+
+// deleteSSECustomerKey - removes the encryption with a customer key of
+// another object from metadata.
+func deleteSSECustomerKey(metadata map[string]string) {
+	for _, key := range sseCustomerMetaKeys {
+		delete(metadata, key)
+	}
+}
+
+// newSSECustomerWriteStream - saves into metadata a new key of an object
+// sealed with customerKey and returns the key stream encrypting its data.
+func newSSECustomerWriteStream(customerKey []byte, metadata map[string]string) (cipher.Stream, error) {
+	key := make([]byte, sseKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+	sealedKey, err := sealKey(customerKey, key)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := newCTRStream(key, iv, 0)
+	if err != nil {
+		return nil, err
+	}
+	keyMD5 := md5.Sum(customerKey)
+	metadata[sseCustomerAlgorithmHeader] = sseAlgorithmAES256
+	metadata[sseCustomerKeyMD5Header] = base64.StdEncoding.EncodeToString(keyMD5[:])
+	metadata[sseCustomerSealedKeyMetaKey] = sealedKey
+	metadata[sseCustomerIVMetaKey] = base64.StdEncoding.EncodeToString(iv)
+	return stream, nil
+}
+
+// newSSECustomerReader - returns data of size encrypted with a new key
+// sealed with customerKey, saved into metadata. The content sent by the
+// client is verified before it is encrypted, the MD5 sum of metadata is
+// removed as the object layer computes the sum of the encrypted data.
+func newSSECustomerReader(customerKey []byte, data io.Reader, size int64, metadata map[string]string, sha256sum string) (io.Reader, error) {
+	stream, err := newSSECustomerWriteStream(customerKey, metadata)
+	if err != nil {
+		return nil, err
+	}
+	reader := newSSEVerifyReader(data, size, metadata["md5Sum"], sha256sum)
+	delete(metadata, "md5Sum")
+	return cipher.StreamReader{S: stream, R: reader}, nil
+}
+
+// newDecryptWriter - returns writer decrypting the data written with
+// stream, writer itself if stream is nil.
+func newDecryptWriter(stream cipher.Stream, writer io.Writer) io.Writer {
+	if stream == nil {
+		return writer
+	}
+	return cipher.StreamWriter{S: stream, W: writer}
+}
+
+// copyObjectWithCustomerKeys - copies an object of size through the
+// server, its data is decrypted with srcStream and the copy encrypted
+// with dstKey, if set.
+func copyObjectWithCustomerKeys(objAPI ObjectLayer, srcBucket, srcObject string, srcStream cipher.Stream, size int64,
+	destBucket, destObject string, dstKey []byte, metadata map[string]string) (ObjectInfo, error) {
+	pipeReader, pipeWriter := io.Pipe()
+	var reader io.Reader = pipeReader
+	if dstKey != nil {
+		var err error
+		if reader, err = newSSECustomerReader(dstKey, pipeReader, size, metadata, ""); err != nil {
+			return ObjectInfo{}, traceError(err)
+		}
+	}
+	go func() {
+		pipeWriter.CloseWithError(objAPI.GetObject(srcBucket, srcObject, 0, size, newDecryptWriter(srcStream, pipeWriter)))
+	}()
+	objInfo, err := objAPI.PutObject(destBucket, destObject, size, reader, metadata, "")
+	// Unblock the source if the copy failed.
+	pipeReader.CloseWithError(err)
+	return objInfo, err
+}
@@ -0,0 +1,477 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Tests parsing of master keys.
+func TestParseSSEMasterKey(t *testing.T) {
+	testCases := []struct {
+		key         string
+		keySize     int
+		expectedErr error
+	}{
+		{"", 0, nil},
+		{strings.Repeat("ab", 32), sseKeySize, nil},
+		{strings.Repeat("ab", 16), 0, errInvalidSSEMasterKey},
+		{strings.Repeat("zz", 32), 0, errInvalidSSEMasterKey},
+	}
+	for i, testCase := range testCases {
+		key, err := parseSSEMasterKey(testCase.key)
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if len(key) != testCase.keySize {
+			t.Errorf("Test %d: Expected key of %d bytes, got %d", i+1, testCase.keySize, len(key))
+		}
+	}
+}
+
+// Tests keys of objects are only unsealed with the master key sealing them.
+func TestSealKey(t *testing.T) {
+	masterKey := bytes.Repeat([]byte{1}, sseKeySize)
+	key := bytes.Repeat([]byte{2}, sseKeySize)
+
+	sealedKey, err := sealKey(masterKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsealedKey, err := unsealKey(masterKey, sealedKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(unsealedKey, key) {
+		t.Fatal("Expected the unsealed key to be the key sealed")
+	}
+
+	if _, err = unsealKey(bytes.Repeat([]byte{3}, sseKeySize), sealedKey); err != errSSEUnsealKey {
+		t.Errorf("Expected %v with another master key, got %v", errSSEUnsealKey, err)
+	}
+	if _, err = unsealKey(masterKey, "not-base64"); err != errSSEUnsealKey {
+		t.Errorf("Expected %v with an invalid sealed key, got %v", errSSEUnsealKey, err)
+	}
+}
+
+// Tests ranges of the data are decrypted independently, including
+// when the counter wraps around.
+func TestNewCTRStream(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, sseKeySize)
+	ivs := [][]byte{
+		bytes.Repeat([]byte{0}, aes.BlockSize),
+		bytes.Repeat([]byte{0xff}, aes.BlockSize),
+		append(bytes.Repeat([]byte{0}, aes.BlockSize-1), 0xfe),
+	}
+	plaintext := make([]byte, 1000)
+	if _, err := rand.Read(plaintext); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, iv := range ivs {
+		stream, err := newCTRStream(key, iv, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ciphertext := make([]byte, len(plaintext))
+		stream.XORKeyStream(ciphertext, plaintext)
+
+		for _, offset := range []int64{0, 1, 15, 16, 17, 513, 999} {
+			stream, err = newCTRStream(key, iv, offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data := make([]byte, int64(len(ciphertext))-offset)
+			stream.XORKeyStream(data, ciphertext[offset:])
+			if !bytes.Equal(data, plaintext[offset:]) {
+				t.Errorf("Test %d: Expected data at offset %d to be decrypted", i+1, offset)
+			}
+		}
+	}
+
+	if _, err := newCTRStream(key, []byte("short"), 0); err == nil {
+		t.Error("Expected an error with an invalid iv")
+	}
+}
+
+// Tests x-amz-server-side-encryption of requests.
+func TestSetServerSideEncryptionFromHeader(t *testing.T) {
+	defer func(key []byte) { globalSSEMasterKey = key }(globalSSEMasterKey)
+
+	testCases := []struct {
+		masterKey    []byte
+		value        string
+		expectedErr  APIErrorCode
+		expectedMeta map[string]string
+	}{
+		{nil, "", ErrNone, map[string]string{}},
+		{nil, sseAlgorithmAES256, ErrServerSideEncryptionNotConfigured, map[string]string{}},
+		{bytes.Repeat([]byte{1}, sseKeySize), "aws:kms", ErrInvalidEncryptionMethod, map[string]string{}},
+		{bytes.Repeat([]byte{1}, sseKeySize), sseAlgorithmAES256, ErrNone,
+			map[string]string{amzServerSideEncryptionHeader: sseAlgorithmAES256}},
+	}
+	for i, testCase := range testCases {
+		globalSSEMasterKey = testCase.masterKey
+		header := http.Header{}
+		if testCase.value != "" {
+			header.Set(amzServerSideEncryptionHeader, testCase.value)
+		}
+		// Metadata copied from another object is not kept.
+		metadata := map[string]string{amzServerSideEncryptionHeader: sseAlgorithmAES256}
+		if err := setServerSideEncryptionFromHeader(header, metadata); err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+			continue
+		}
+		if testCase.expectedErr != ErrNone {
+			continue
+		}
+		if len(metadata) != len(testCase.expectedMeta) ||
+			metadata[amzServerSideEncryptionHeader] != testCase.expectedMeta[amzServerSideEncryptionHeader] {
+			t.Errorf("Test %d: Expected metadata %v, got %v", i+1, testCase.expectedMeta, metadata)
+		}
+	}
+}
+
+// Wrapper for calling server-side encryption tests for both XL and FS.
+func TestServerSideEncryption(t *testing.T) {
+	ExecObjectLayerTest(t, testServerSideEncryption)
+}
+
+// Tests objects of encrypted buckets are encrypted on disk and
+// decrypted transparently.
+func testServerSideEncryption(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	defer func(key []byte) { globalSSEMasterKey = key }(globalSSEMasterKey)
+	globalSSEMasterKey = bytes.Repeat([]byte{1}, sseKeySize)
+	sseObj := newSSEObjects(obj, globalSSEMasterKey)
+
+	encryptedBucket, plainBucket := "encrypted-bucket", "plain-bucket"
+	for _, bucket := range []string{encryptedBucket, plainBucket} {
+		if err := obj.MakeBucket(bucket); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+	serverConfig.SetBucketEncryption(encryptedBucket, true)
+	defer serverConfig.SetBucketEncryption(encryptedBucket, false)
+
+	plaintext := bytes.Repeat([]byte("minio server-side encryption "), 100)
+	size := int64(len(plaintext))
+
+	// getObject - returns length bytes of object at offset read with objAPI.
+	getObject := func(objAPI ObjectLayer, bucket, object string, offset, length int64) []byte {
+		var buffer bytes.Buffer
+		if err := objAPI.GetObject(bucket, object, offset, length, &buffer); err != nil {
+			t.Fatalf("%s: Unable to read %s/%s: %v", instanceType, bucket, object, err)
+		}
+		return buffer.Bytes()
+	}
+
+	// verifyEncrypted - verifies object is encrypted on disk and
+	// decrypted when read, as a whole or a range.
+	verifyEncrypted := func(bucket, object string) {
+		if bytes.Equal(getObject(obj, bucket, object, 0, size), plaintext) {
+			t.Errorf("%s: Expected %s/%s to be encrypted on disk", instanceType, bucket, object)
+		}
+		if !bytes.Equal(getObject(sseObj, bucket, object, 0, size), plaintext) {
+			t.Errorf("%s: Expected %s/%s to be decrypted", instanceType, bucket, object)
+		}
+		if !bytes.Equal(getObject(sseObj, bucket, object, 33, 100), plaintext[33:133]) {
+			t.Errorf("%s: Expected a range of %s/%s to be decrypted", instanceType, bucket, object)
+		}
+	}
+
+	// Objects of encrypted buckets are encrypted, the content sent is
+	// verified before it is encrypted.
+	objInfo, err := sseObj.PutObject(encryptedBucket, "object", size, bytes.NewReader(plaintext),
+		map[string]string{"md5Sum": getMD5Hash(plaintext)}, getSHA256Hash(plaintext))
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if objInfo.Size != size || objInfo.UserDefined[amzServerSideEncryptionHeader] != sseAlgorithmAES256 {
+		t.Errorf("%s: Expected an encrypted object of %d bytes, got %v", instanceType, size, objInfo)
+	}
+	verifyEncrypted(encryptedBucket, "object")
+
+	_, err = sseObj.PutObject(encryptedBucket, "bad-digest", size, bytes.NewReader(plaintext),
+		map[string]string{"md5Sum": getMD5Hash([]byte("other"))}, "")
+	if _, ok := errorCause(err).(BadDigest); !ok {
+		t.Errorf("%s: Expected BadDigest, got %v", instanceType, err)
+	}
+
+	// Objects of other buckets are only encrypted if requested.
+	if _, err = sseObj.PutObject(plainBucket, "plain", size, bytes.NewReader(plaintext), map[string]string{}, ""); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if !bytes.Equal(getObject(obj, plainBucket, "plain", 0, size), plaintext) {
+		t.Errorf("%s: Expected object of a bucket not encrypted to be kept as is", instanceType)
+	}
+	_, err = sseObj.PutObject(plainBucket, "requested", size, bytes.NewReader(plaintext),
+		map[string]string{amzServerSideEncryptionHeader: sseAlgorithmAES256}, "")
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	verifyEncrypted(plainBucket, "requested")
+
+	// Copies are encrypted with a key of their own.
+	if _, err = sseObj.CopyObject(plainBucket, "plain", encryptedBucket, "copy", map[string]string{}); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	verifyEncrypted(encryptedBucket, "copy")
+	if _, err = sseObj.CopyObject(encryptedBucket, "object", plainBucket, "decrypted", map[string]string{}); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if !bytes.Equal(getObject(obj, plainBucket, "decrypted", 0, size), plaintext) {
+		t.Errorf("%s: Expected copy of an encrypted object to a bucket not encrypted to be decrypted", instanceType)
+	}
+
+	// Copies onto the source keep its encryption.
+	_, err = sseObj.CopyObject(encryptedBucket, "object", encryptedBucket, "object", map[string]string{"X-Amz-Meta-Key": "value"})
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	verifyEncrypted(encryptedBucket, "object")
+
+	// Objects are not encrypted with multipart uploads.
+	_, err = sseObj.NewMultipartUpload(encryptedBucket, "multipart", map[string]string{})
+	if _, ok := errorCause(err).(MultipartEncryptionNotSupported); !ok {
+		t.Errorf("%s: Expected MultipartEncryptionNotSupported, got %v", instanceType, err)
+	}
+
+	// Keys of objects are not in the response headers.
+	objInfo, err = sseObj.GetObjectInfo(encryptedBucket, "object")
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	w := httptest.NewRecorder()
+	setObjectHeaders(w, objInfo, nil)
+	if sse := w.Header().Get(amzServerSideEncryptionHeader); sse != sseAlgorithmAES256 {
+		t.Errorf("%s: Expected %s: %s, got %s", instanceType, amzServerSideEncryptionHeader, sseAlgorithmAES256, sse)
+	}
+	for key := range w.Header() {
+		if strings.HasPrefix(key, minioInternalMetaPrefix) {
+			t.Errorf("%s: Expected internal metadata %s not to be sent", instanceType, key)
+		}
+	}
+}
+
+// setSSECustomerKey - sets the SSE-C headers h of key on header.
+func setSSECustomerKey(header http.Header, h sseCustomerHeaders, key []byte) {
+	keyMD5 := md5.Sum(key)
+	header.Set(h.algorithm, sseAlgorithmAES256)
+	header.Set(h.key, base64.StdEncoding.EncodeToString(key))
+	header.Set(h.keyMD5, base64.StdEncoding.EncodeToString(keyMD5[:]))
+}
+
+// Tests parsing of SSE-C headers.
+func TestSSECustomerParseKey(t *testing.T) {
+	defer func(isSSL bool) { globalIsSSL = isSSL }(globalIsSSL)
+	globalIsSSL = true
+
+	key := bytes.Repeat([]byte{1}, sseKeySize)
+	keyMD5 := md5.Sum(key)
+	encodedKey := base64.StdEncoding.EncodeToString(key)
+	encodedKeyMD5 := base64.StdEncoding.EncodeToString(keyMD5[:])
+	shortKey := base64.StdEncoding.EncodeToString(key[:16])
+
+	testCases := []struct {
+		algorithm, key, keyMD5 string
+		expectedErr            APIErrorCode
+	}{
+		{"", "", "", ErrNone},
+		{sseAlgorithmAES256, encodedKey, encodedKeyMD5, ErrNone},
+		{"aws:kms", encodedKey, encodedKeyMD5, ErrInvalidSSECustomerAlgorithm},
+		{sseAlgorithmAES256, "", encodedKeyMD5, ErrMissingSSECustomerKey},
+		{sseAlgorithmAES256, shortKey, encodedKeyMD5, ErrInvalidSSECustomerKey},
+		{sseAlgorithmAES256, encodedKey, "", ErrMissingSSECustomerKeyMD5},
+		{sseAlgorithmAES256, encodedKey, base64.StdEncoding.EncodeToString(key[:16]), ErrSSECustomerKeyMD5Mismatch},
+	}
+	for i, testCase := range testCases {
+		header := http.Header{}
+		for k, v := range map[string]string{
+			sseCustomerAlgorithmHeader: testCase.algorithm,
+			sseCustomerKeyHeader:       testCase.key,
+			sseCustomerKeyMD5Header:    testCase.keyMD5,
+		} {
+			if v != "" {
+				header.Set(k, v)
+			}
+		}
+		parsedKey, err := sseCustomerRequestHeaders.parseKey(header)
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+			continue
+		}
+		if err == ErrNone && testCase.key != "" && !bytes.Equal(parsedKey, key) {
+			t.Errorf("Test %d: Expected the key of the headers", i+1)
+		}
+	}
+
+	// Customer keys are only sent over TLS.
+	globalIsSSL = false
+	header := http.Header{}
+	setSSECustomerKey(header, sseCustomerRequestHeaders, key)
+	if _, err := sseCustomerRequestHeaders.parseKey(header); err != ErrInsecureSSECustomerRequest {
+		t.Errorf("Expected %v without TLS, got %v", ErrInsecureSSECustomerRequest, err)
+	}
+}
+
+// Wrapper for calling SSE-C handler tests for both XL and FS.
+func TestAPISSECustomerHandlers(t *testing.T) {
+	defer DetectTestLeak(t)()
+	// Handlers are registered in the order of registerAPIRouter, such
+	// that copy requests do not match PutObject.
+	ExecObjectLayerAPITest(t, testAPISSECustomerHandlers, []string{"HeadObject", "GetObject", "CopyObject", "PutObject"})
+}
+
+// Tests objects uploaded with a customer key are only read with the
+// same key, which is never saved.
+func testAPISSECustomerHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	defer func(isSSL bool) { globalIsSSL = isSSL }(globalIsSSL)
+	globalIsSSL = true
+
+	key := bytes.Repeat([]byte{1}, sseKeySize)
+	otherKey := bytes.Repeat([]byte{2}, sseKeySize)
+	objectName := "secret"
+	data := bytes.Repeat([]byte("customer key "), 100)
+
+	// do - sends a signed request to the router, with the SSE-C headers
+	// h of key if set.
+	do := func(method, url string, body []byte, h sseCustomerHeaders, key []byte, header http.Header) *httptest.ResponseRecorder {
+		req, err := newTestSignedRequestV4(method, url, int64(len(body)), bytes.NewReader(body),
+			credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Minio %s: Failed to create HTTP request for %s: <ERROR> %v", instanceType, method, err)
+		}
+		for k := range header {
+			req.Header.Set(k, header.Get(k))
+		}
+		if key != nil {
+			setSSECustomerKey(req.Header, h, key)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// getErrorCode - returns the code of an S3 error response.
+	getErrorCode := func(rec *httptest.ResponseRecorder) string {
+		var apiErr APIErrorResponse
+		if err := xml.Unmarshal(rec.Body.Bytes(), &apiErr); err != nil {
+			t.Fatalf("Minio %s: Failed to parse error response: <ERROR> %v", instanceType, err)
+		}
+		return apiErr.Code
+	}
+
+	objectURL := getPutObjectURL("", bucketName, objectName)
+	rec := do("PUT", objectURL, data, sseCustomerRequestHeaders, key, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Minio %s: Expected PutObject to succeed, got %d: %s", instanceType, rec.Code, rec.Body)
+	}
+	if algorithm := rec.Header().Get(sseCustomerAlgorithmHeader); algorithm != sseAlgorithmAES256 {
+		t.Errorf("Minio %s: Expected %s %s, got %s", instanceType, sseCustomerAlgorithmHeader, sseAlgorithmAES256, algorithm)
+	}
+
+	// Data is encrypted and the key is not saved.
+	var buffer bytes.Buffer
+	if err := obj.GetObject(bucketName, objectName, 0, int64(len(data)), &buffer); err != nil {
+		t.Fatalf("Minio %s: %v", instanceType, err)
+	}
+	if bytes.Equal(buffer.Bytes(), data) {
+		t.Errorf("Minio %s: Expected object to be encrypted on disk", instanceType)
+	}
+	objInfo, err := obj.GetObjectInfo(bucketName, objectName)
+	if err != nil {
+		t.Fatalf("Minio %s: %v", instanceType, err)
+	}
+	for k, v := range objInfo.UserDefined {
+		if k == sseCustomerKeyHeader || v == base64.StdEncoding.EncodeToString(key) {
+			t.Errorf("Minio %s: Expected the customer key not to be saved, found in %s", instanceType, k)
+		}
+	}
+
+	// Objects are read with the same key, including ranges.
+	rec = do("GET", objectURL, nil, sseCustomerRequestHeaders, key, nil)
+	if rec.Code != http.StatusOK || !bytes.Equal(rec.Body.Bytes(), data) {
+		t.Errorf("Minio %s: Expected GetObject to return the object, got %d", instanceType, rec.Code)
+	}
+	rec = do("GET", objectURL, nil, sseCustomerRequestHeaders, key, http.Header{"Range": {"bytes=20-99"}})
+	if rec.Code != http.StatusPartialContent || !bytes.Equal(rec.Body.Bytes(), data[20:100]) {
+		t.Errorf("Minio %s: Expected GetObject to return a range of the object, got %d", instanceType, rec.Code)
+	}
+	rec = do("HEAD", objectURL, nil, sseCustomerRequestHeaders, key, nil)
+	if rec.Code != http.StatusOK || rec.Header().Get(sseCustomerKeyMD5Header) == "" {
+		t.Errorf("Minio %s: Expected HeadObject to succeed with the key MD5, got %d", instanceType, rec.Code)
+	}
+
+	// Reads without the key or with another key fail.
+	rec = do("GET", objectURL, nil, sseCustomerRequestHeaders, nil, nil)
+	if rec.Code != http.StatusBadRequest || getErrorCode(rec) != "InvalidRequest" {
+		t.Errorf("Minio %s: Expected GetObject without the key to fail, got %d: %s", instanceType, rec.Code, rec.Body)
+	}
+	rec = do("GET", objectURL, nil, sseCustomerRequestHeaders, otherKey, nil)
+	if rec.Code != http.StatusForbidden || getErrorCode(rec) != "AccessDenied" {
+		t.Errorf("Minio %s: Expected GetObject with another key to fail, got %d: %s", instanceType, rec.Code, rec.Body)
+	}
+	for _, readKey := range [][]byte{nil, otherKey} {
+		if rec = do("HEAD", objectURL, nil, sseCustomerRequestHeaders, readKey, nil); rec.Code == http.StatusOK {
+			t.Errorf("Minio %s: Expected HeadObject without the key to fail", instanceType)
+		}
+	}
+
+	// Copies read the source with its key and are encrypted with the key
+	// of the request.
+	copyURL := getCopyObjectURL("", bucketName, "copy")
+	copySource := http.Header{"X-Amz-Copy-Source": {"/" + bucketName + "/" + objectName}}
+	if rec = do("PUT", copyURL, nil, sseCustomerRequestHeaders, nil, copySource); rec.Code != http.StatusBadRequest {
+		t.Errorf("Minio %s: Expected CopyObject without the key of the source to fail, got %d", instanceType, rec.Code)
+	}
+	header := http.Header{"X-Amz-Copy-Source": copySource["X-Amz-Copy-Source"]}
+	setSSECustomerKey(header, sseCustomerCopySourceHeaders, key)
+	rec = do("PUT", copyURL, nil, sseCustomerRequestHeaders, otherKey, header)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Minio %s: Expected CopyObject to succeed, got %d: %s", instanceType, rec.Code, rec.Body)
+	}
+	if rec = do("GET", copyURL, nil, sseCustomerRequestHeaders, key, nil); rec.Code != http.StatusForbidden {
+		t.Errorf("Minio %s: Expected copy not to be read with the key of the source, got %d", instanceType, rec.Code)
+	}
+	rec = do("GET", copyURL, nil, sseCustomerRequestHeaders, otherKey, nil)
+	if rec.Code != http.StatusOK || !bytes.Equal(rec.Body.Bytes(), data) {
+		t.Errorf("Minio %s: Expected copy to be read with its key, got %d", instanceType, rec.Code)
+	}
+
+	// Copies without a key are not encrypted.
+	plainURL := getCopyObjectURL("", bucketName, "plain")
+	if rec = do("PUT", plainURL, nil, sseCustomerRequestHeaders, nil, header); rec.Code != http.StatusOK {
+		t.Fatalf("Minio %s: Expected CopyObject to succeed, got %d: %s", instanceType, rec.Code, rec.Body)
+	}
+	rec = do("GET", plainURL, nil, sseCustomerRequestHeaders, nil, nil)
+	if rec.Code != http.StatusOK || !bytes.Equal(rec.Body.Bytes(), data) {
+		t.Errorf("Minio %s: Expected copy without a key to be read, got %d", instanceType, rec.Code)
+	}
+	if rec = do("GET", plainURL, nil, sseCustomerRequestHeaders, key, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("Minio %s: Expected read of an object not encrypted with a key to fail, got %d", instanceType, rec.Code)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Sirupsen/logrus"
 	humanize "github.com/dustin/go-humanize"
 	"github.com/minio/mc/pkg/console"
 )
@@ -44,29 +45,32 @@ func getFormatStr(strLen int, padding int) string {
 
 // Prints the formatted startup message.
 func printStartupMessage(apiEndPoints []string) {
-	// If quiet flag is set do not print startup message.
-	if globalQuiet {
-		return
-	}
-
-	// Prints credential, region and browser access.
-	printServerCommonMsg(apiEndPoints)
-
-	// Prints `mc` cli configuration message chooses
-	// first endpoint as default.
-	printCLIAccessMsg(apiEndPoints[0])
-
-	// Prints documentation message.
-	printObjectAPIMsg()
-
-	// Object layer is initialized then print StorageInfo.
-	objAPI := newObjectLayerFn()
-	if objAPI != nil {
-		printStorageInfo(objAPI.StorageInfo())
+	switch {
+	case globalIsLogJSON:
+		// Startup information is a single line in JSON mode, even
+		// if quiet flag is set.
+		printStartupJSONMsg(apiEndPoints)
+	// If quiet flag is set print only warnings, not the startup banner.
+	case !globalQuiet:
+		// Prints credential, region and browser access.
+		printServerCommonMsg(apiEndPoints)
+
+		// Prints `mc` cli configuration message chooses
+		// first endpoint as default.
+		printCLIAccessMsg(apiEndPoints[0])
+
+		// Prints documentation message.
+		printObjectAPIMsg()
+
+		// Object layer is initialized then print StorageInfo.
+		objAPI := newObjectLayerFn()
+		if objAPI != nil {
+			printStorageInfo(objAPI.StorageInfo())
+		}
 	}
 
-	// SSL is configured reads certification chain, prints
-	// authority and expiry.
+	// SSL is configured reads certification chain, warns of
+	// certificates about to expire.
 	if globalIsSSL {
 		certs, err := readCertificateChain()
 		fatalIf(err, "Unable to read certificate chain.")
@@ -74,6 +78,26 @@ func printStartupMessage(apiEndPoints []string) {
 	}
 }
 
+// Prints startup information as a single line JSON object, secret key
+// is left out as the line is meant for log aggregators.
+func printStartupJSONMsg(apiEndpoints []string) {
+	data := logrus.Fields{
+		"endpoints": apiEndpoints,
+		"accessKey": serverConfig.GetCredential().AccessKey,
+		"region":    serverConfig.GetRegion(),
+	}
+	if objAPI := newObjectLayerFn(); objAPI != nil {
+		storageInfo := objAPI.StorageInfo()
+		data["free"] = storageInfo.Free
+		data["total"] = storageInfo.Total
+		if storageInfo.Backend.Type == XL {
+			data["onlineDisks"] = storageInfo.Backend.OnlineDisks
+			data["offlineDisks"] = storageInfo.Backend.OfflineDisks
+		}
+	}
+	printJSONLine(logrus.InfoLevel, "Minio server started.", data)
+}
+
 // Prints common server startup message. Prints credential, region and browser access.
 func printServerCommonMsg(apiEndpoints []string) {
 	// Get saved credentials.
@@ -142,8 +166,9 @@ func getStorageInfoMsg(storageInfo StorageInfo) string {
 		humanize.IBytes(uint64(storageInfo.Free)),
 		humanize.IBytes(uint64(storageInfo.Total)))
 	if storageInfo.Backend.Type == XL {
-		diskInfo := fmt.Sprintf(" %d Online, %d Offline. ", storageInfo.Backend.OnlineDisks, storageInfo.Backend.OfflineDisks)
-		if maxDiskFailures := storageInfo.Backend.ReadQuorum - storageInfo.Backend.OfflineDisks; maxDiskFailures >= 0 {
+		diskInfo := fmt.Sprintf(" %d Online, %d Offline. %d Data, %d Parity. ", storageInfo.Backend.OnlineDisks, storageInfo.Backend.OfflineDisks,
+			storageInfo.Backend.DataBlocks, storageInfo.Backend.ParityBlocks)
+		if maxDiskFailures := storageInfo.Backend.OnlineDisks - storageInfo.Backend.ReadQuorum; maxDiskFailures >= 0 {
 			diskInfo += fmt.Sprintf("We can withstand [%d] more drive failure(s).", maxDiskFailures)
 		}
 		msg += colorBlue("\nStatus:") + fmt.Sprintf(getFormatStr(len(diskInfo), 8), diskInfo)
@@ -177,5 +202,18 @@ func getCertificateChainMsg(certs []*x509.Certificate) string {
 
 // Prints the certificate expiry message.
 func printCertificateMsg(certs []*x509.Certificate) {
-	console.Println(getCertificateChainMsg(certs))
+	if globalIsLogJSON {
+		for i := len(certs) - 1; i >= 0; i-- {
+			if certs[i].NotAfter.Before(time.Now().UTC().Add(globalMinioCertExpireWarnDays)) {
+				printJSONLine(logrus.WarnLevel, "Certificate will expire soon.", logrus.Fields{
+					"subject": certs[i].Subject.CommonName,
+					"expiry":  certs[i].NotAfter,
+				})
+			}
+		}
+		return
+	}
+	if msg := getCertificateChainMsg(certs); msg != "" {
+		console.Println(msg)
+	}
 }
@@ -17,14 +17,18 @@
 package cmd
 
 import (
+	"bytes"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 	"testing"
 	"time"
 
 	humanize "github.com/dustin/go-humanize"
+	"github.com/fatih/color"
 )
 
 // Tests if we generate storage info.
@@ -38,10 +42,14 @@ func TestStorageInfoMsg(t *testing.T) {
 			OfflineDisks int
 			ReadQuorum   int
 			WriteQuorum  int
-		}{XL, 7, 1, 4, 5},
+			AvoidedDisks int
+			PendingHeals int
+			DataBlocks   int
+			ParityBlocks int
+		}{XL, 7, 1, 4, 5, 0, 0, 4, 4},
 	}
 
-	if msg := getStorageInfoMsg(infoStorage); !strings.Contains(msg, "2.0 GiB Free, 10 GiB Total") || !strings.Contains(msg, "7 Online, 1 Offline") {
+	if msg := getStorageInfoMsg(infoStorage); !strings.Contains(msg, "2.0 GiB Free, 10 GiB Total") || !strings.Contains(msg, "7 Online, 1 Offline") || !strings.Contains(msg, "4 Data, 4 Parity") || !strings.Contains(msg, "[3] more drive failure(s)") {
 		t.Fatal("Unexpected storage info message, found:", msg)
 	}
 }
@@ -130,3 +138,87 @@ func TestPrintStartupMessage(t *testing.T) {
 	apiEndpoints := []string{"127.0.0.1:9000"}
 	printStartupMessage(apiEndpoints)
 }
+
+// Tests the startup banner is not printed in quiet mode, unlike
+// warnings of certificates about to expire.
+func TestPrintStartupMessageQuiet(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	if err = createCertsPath(); err != nil {
+		t.Fatal(err)
+	}
+	// Test certificate expires within a minute.
+	if err = generateTestCert("127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(output io.Writer, quiet, ssl bool) {
+		color.Output, globalQuiet, globalIsSSL = output, quiet, ssl
+	}(color.Output, globalQuiet, globalIsSSL)
+	globalIsSSL = true
+
+	cred := serverConfig.GetCredential()
+	for _, quiet := range []bool{false, true} {
+		globalQuiet = quiet
+		stdout := &bytes.Buffer{}
+		color.Output = stdout
+		printStartupMessage([]string{"https://127.0.0.1:9000"})
+
+		for _, banner := range []string{"Endpoint:", cred.SecretKey, "Command-line Access:", mcQuickStartGuide} {
+			if quiet == strings.Contains(stdout.String(), banner) {
+				t.Errorf("quiet %t: Unexpected presence of %q in %q", quiet, banner, stdout.String())
+			}
+		}
+		if !strings.Contains(stdout.String(), "will expire on") {
+			t.Errorf("quiet %t: Expected certificate expiry warning, got %q", quiet, stdout.String())
+		}
+	}
+}
+
+// Tests startup information is a single line JSON object in JSON mode,
+// even if quiet flag is set.
+func TestPrintStartupMessageJSON(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	defer func(output io.Writer, quiet, logJSON bool) {
+		color.Output, globalQuiet, globalIsLogJSON = output, quiet, logJSON
+	}(color.Output, globalQuiet, globalIsLogJSON)
+	globalIsLogJSON = true
+
+	cred := serverConfig.GetCredential()
+	for _, quiet := range []bool{false, true} {
+		globalQuiet = quiet
+		stdout := &bytes.Buffer{}
+		color.Output = stdout
+		printStartupMessage([]string{"http://127.0.0.1:9000"})
+
+		if bytes.Count(stdout.Bytes(), []byte("\n")) != 1 {
+			t.Fatalf("quiet %t: Expected a single line, got %q", quiet, stdout.String())
+		}
+		var entry struct {
+			Level     string   `json:"level"`
+			Message   string   `json:"message"`
+			Endpoints []string `json:"endpoints"`
+			AccessKey string   `json:"accessKey"`
+			Region    string   `json:"region"`
+		}
+		if err = json.Unmarshal(stdout.Bytes(), &entry); err != nil {
+			t.Fatalf("quiet %t: Expected valid JSON, got %q: %s", quiet, stdout.String(), err)
+		}
+		if entry.Level != "info" || entry.Message == "" || entry.AccessKey != cred.AccessKey || entry.Region != "us-east-1" ||
+			len(entry.Endpoints) != 1 || entry.Endpoints[0] != "http://127.0.0.1:9000" {
+			t.Errorf("quiet %t: Unexpected startup information %+v", quiet, entry)
+		}
+		if strings.Contains(stdout.String(), cred.SecretKey) {
+			t.Errorf("quiet %t: Expected secret key to be left out, got %q", quiet, stdout.String())
+		}
+	}
+}
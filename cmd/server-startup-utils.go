@@ -19,7 +19,6 @@ package cmd
 import (
 	"fmt"
 	"net"
-	"net/http"
 )
 
 // getListenIPs - gets all the ips to listen on.
@@ -48,23 +47,32 @@ func getListenIPs(serverAddr string) (hosts []string, port string, err error) {
 	return hosts, port, nil
 }
 
-// Finalizes the API endpoints based on the host list and port.
-func finalizeAPIEndpoints(apiServer *http.Server) (endPoints []string, err error) {
+// Finalizes the API endpoints based on the host list and port of all
+// the addresses the server listens on.
+func finalizeAPIEndpoints(serverAddrs []string) (endPoints []string, err error) {
 	// Verify current scheme.
 	scheme := "http"
 	if globalIsSSL {
 		scheme = "https"
 	}
 
-	// Get list of listen ips and port.
-	hosts, port, err1 := getListenIPs(apiServer.Addr)
-	if err1 != nil {
-		return nil, err1
-	}
+	seenEndPoints := make(map[string]bool)
+	for _, serverAddr := range serverAddrs {
+		// Get list of listen ips and port.
+		hosts, port, err1 := getListenIPs(serverAddr)
+		if err1 != nil {
+			return nil, err1
+		}
 
-	// Construct proper endpoints.
-	for _, host := range hosts {
-		endPoints = append(endPoints, fmt.Sprintf("%s://%s:%s", scheme, host, port))
+		// Construct proper endpoints, addresses listening on all
+		// interfaces may share some of them.
+		for _, host := range hosts {
+			endPoint := fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(host, port))
+			if !seenEndPoints[endPoint] {
+				seenEndPoints[endPoint] = true
+				endPoints = append(endPoints, endPoint)
+			}
+		}
 	}
 
 	// Success.
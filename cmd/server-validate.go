@@ -0,0 +1,128 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+)
+
+// validationCheck - outcome of one of the checks of `minio server --validate`.
+type validationCheck struct {
+	name string
+	err  error
+}
+
+// validateServer - runs all the checks of the command line done at
+// startup, without initializing disks or listening, such that a
+// deployment can be verified beforehand.
+func validateServer(c *cli.Context) (checks []validationCheck) {
+	addCheck := func(name string, err error) bool {
+		checks = append(checks, validationCheck{name, err})
+		return err == nil
+	}
+
+	// Ports of all the addresses must be free, the first address
+	// identifies this server among the endpoints.
+	var err error
+	for i, addr := range splitServerAddress(getServerAddress(c)) {
+		var host, port string
+		if host, port, err = getHostPort(addr); err != nil {
+			err = fmt.Errorf("%s: %s", addr, err)
+			break
+		}
+		if i == 0 {
+			globalMinioHost, globalMinioPort = host, port
+		}
+	}
+	addCheck("Address and port availability", err)
+
+	// Endpoints are validated only with a valid syntax.
+	if !addCheck("Command line syntax", validateServerSyntax(c)) {
+		return checks
+	}
+
+	endpointArgs, err := getServerEndpointArgs(c)
+	if err != nil {
+		addCheck("Storage endpoints", err)
+		return checks
+	}
+	endpoints, err := parseStorageEndpoints(endpointArgs)
+	if err == nil && !isAnyEndpointLocal(endpoints) {
+		err = errors.New("None of the disks passed as command line args are local to this server")
+	}
+	addCheck(fmt.Sprintf("Storage endpoints (%d disks)", len(endpoints)), err)
+
+	addCheck("TLS certificates", validateCertificates(c.Bool("require-client-cert")))
+	return checks
+}
+
+// validateCertificates - verifies the certificate and private key of
+// the server are a valid and unexpired pair if TLS is configured, and
+// that client CAs exist if client certificates are required.
+func validateCertificates(requireClientCert bool) error {
+	if isCertFileExists() != isKeyFileExists() {
+		return fmt.Errorf("Both %s and %s are required for TLS", globalMinioCertFile, globalMinioKeyFile)
+	}
+	if isSSL() {
+		cert, err := tls.LoadX509KeyPair(mustGetCertFile(), mustGetKeyFile())
+		if err != nil {
+			return err
+		}
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return err
+		}
+		if time.Now().After(leaf.NotAfter) {
+			return fmt.Errorf("Certificate %s expired on %s", leaf.Subject.CommonName, leaf.NotAfter)
+		}
+	}
+	if !requireClientCert {
+		return nil
+	}
+	if !isSSL() {
+		return errors.New("--require-client-cert requires TLS to be configured")
+	}
+	if len(mustGetClientCAFiles()) == 0 {
+		return fmt.Errorf("--require-client-cert requires CA certificates in %s",
+			filepath.Join(mustGetCertsPath(), globalMinioCertsClientCADir))
+	}
+	return nil
+}
+
+// printValidationSummary - prints the outcome of each check, returns
+// true if all of them passed.
+func printValidationSummary(checks []validationCheck) (passed bool) {
+	passed = true
+	console.Println(colorBlue("\nValidation of the server command line:"))
+	for _, check := range checks {
+		status := colorGreen("OK")
+		if check.err != nil {
+			status = colorRed(fmt.Sprintf("FAILED (%s)", check.err))
+			passed = false
+		}
+		console.Println(fmt.Sprintf("   %s  %s", check.name, status))
+	}
+	return passed
+}
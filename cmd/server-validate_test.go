@@ -0,0 +1,129 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"flag"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/minio/cli"
+)
+
+// Tests validation of valid and invalid server command lines.
+func TestValidateServer(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal("Failed to set up test config")
+	}
+	defer removeAll(root)
+	if err = createCertsPath(); err != nil {
+		t.Fatal(err)
+	}
+	// Reset so that we don't affect other tests.
+	defer func() {
+		globalMinioHost, globalMinioPort = "", "9000"
+	}()
+
+	disks, err := getRandomDisks(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(disks)
+
+	// A port already in use.
+	l, err := net.Listen("tcp", ":"+getFreePort())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	_, busyPort, _ := net.SplitHostPort(l.Addr().String())
+
+	testCases := []struct {
+		args []string
+		// Only a certificate file, without private key.
+		certOnly bool
+		// Name of the check expected to fail, all pass if empty.
+		failedCheck string
+	}{
+		// Test case - 1.
+		// Valid erasure coded setup.
+		{[]string{"--address", ":" + getFreePort(), disks[0], disks[1], disks[2], disks[3]}, false, ""},
+		// Test case - 2.
+		// Valid FS setup.
+		{[]string{"--address", ":" + getFreePort(), disks[4]}, false, ""},
+		// Test case - 3.
+		// Port in use.
+		{[]string{"--address", ":" + busyPort, disks[4]}, false, "Address and port availability"},
+		// Test case - 4.
+		// Insufficient disks.
+		{[]string{"--address", ":" + getFreePort(), disks[0], disks[1], disks[2]}, false, "Command line syntax"},
+		// Test case - 5.
+		// Duplicate disks.
+		{[]string{"--address", ":" + getFreePort(), disks[0], disks[0], disks[1], disks[2]}, false, "Command line syntax"},
+		// Test case - 6.
+		// Certificate without private key.
+		{[]string{"--address", ":" + getFreePort(), disks[4]}, true, "TLS certificates"},
+		// Test case - 7.
+		// Client certificates without TLS.
+		{[]string{"--address", ":" + getFreePort(), "--require-client-cert", disks[4]}, false, "TLS certificates"},
+	}
+	for i, testCase := range testCases {
+		os.Remove(mustGetCertFile())
+		if testCase.certOnly {
+			if err = ioutil.WriteFile(mustGetCertFile(), []byte("cert"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		serverFlagSet := flag.NewFlagSet("server", 0)
+		serverFlagSet.String("address", ":9000", "")
+		serverFlagSet.Bool("require-client-cert", false, "")
+		if err = serverFlagSet.Parse(testCase.args); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		ctx := cli.NewContext(cli.NewApp(), serverFlagSet, serverFlagSet)
+		globalMinioHost, globalMinioPort = "", "9000"
+
+		checks := validateServer(ctx)
+		var failedChecks []string
+		for _, check := range checks {
+			if check.err != nil {
+				failedChecks = append(failedChecks, check.name)
+			}
+		}
+		if testCase.failedCheck == "" {
+			if len(failedChecks) != 0 {
+				t.Errorf("Test %d: Expected all checks to pass, failed %v", i+1, checks)
+			}
+			if !printValidationSummary(checks) {
+				t.Errorf("Test %d: Expected summary to pass", i+1)
+			}
+			continue
+		}
+		if len(failedChecks) == 0 || !strings.HasPrefix(failedChecks[0], testCase.failedCheck) {
+			t.Errorf("Test %d: Expected check %q to fail, failed %v", i+1, testCase.failedCheck, failedChecks)
+		}
+		if printValidationSummary(checks) {
+			t.Errorf("Test %d: Expected summary to fail", i+1)
+		}
+	}
+	os.Remove(mustGetCertFile())
+}
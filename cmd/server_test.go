@@ -229,6 +229,82 @@ func (s *TestSuiteCommon) TestBucketPolicy(c *C) {
 	c.Assert(response.StatusCode, Equals, http.StatusNotFound)
 }
 
+// TestBucketPolicyPublicRead - Sets a public-read policy with the S3
+// API and verifies anonymous reads are allowed while anonymous writes
+// are still denied.
+func (s *TestSuiteCommon) TestBucketPolicyPublicRead(c *C) {
+	// Public-read bucket policy.
+	bucketPolicyBuf := `{"Version":"2012-10-17","Statement":[{"Action":["s3:ListBucket"],"Effect":"Allow","Principal":{"AWS":["*"]},"Resource":["arn:aws:s3:::%s"],"Sid":""},{"Action":["s3:GetObject"],"Effect":"Allow","Principal":{"AWS":["*"]},"Resource":["arn:aws:s3:::%s/*"],"Sid":""}]}`
+
+	// generate a random bucket Name.
+	bucketName := getRandomBucketName()
+	bucketPolicyStr := fmt.Sprintf(bucketPolicyBuf, bucketName, bucketName)
+	// HTTP request to create the bucket.
+	request, err := newTestSignedRequest("PUT", getMakeBucketURL(s.endPoint, bucketName),
+		0, nil, s.accessKey, s.secretKey, s.signer)
+	c.Assert(err, IsNil)
+
+	client := http.Client{Transport: s.transport}
+	response, err := client.Do(request)
+	c.Assert(err, IsNil)
+	c.Assert(response.StatusCode, Equals, http.StatusOK)
+
+	// Upload an object with credentials.
+	objectName := "public-object"
+	request, err = newTestSignedRequest("PUT", getPutObjectURL(s.endPoint, bucketName, objectName),
+		int64(len("hello world")), bytes.NewReader([]byte("hello world")), s.accessKey, s.secretKey, s.signer)
+	c.Assert(err, IsNil)
+	response, err = client.Do(request)
+	c.Assert(err, IsNil)
+	c.Assert(response.StatusCode, Equals, http.StatusOK)
+
+	// Anonymous reads are denied without a policy.
+	response, err = client.Get(getGetObjectURL(s.endPoint, bucketName, objectName))
+	c.Assert(err, IsNil)
+	verifyError(c, response, "AccessDenied", "Access Denied.", http.StatusForbidden)
+
+	// Put the public-read policy.
+	request, err = newTestSignedRequest("PUT", getPutPolicyURL(s.endPoint, bucketName),
+		int64(len(bucketPolicyStr)), bytes.NewReader([]byte(bucketPolicyStr)), s.accessKey, s.secretKey, s.signer)
+	c.Assert(err, IsNil)
+	response, err = client.Do(request)
+	c.Assert(err, IsNil)
+	c.Assert(response.StatusCode, Equals, http.StatusNoContent)
+
+	// Anonymous GET of the object succeeds.
+	response, err = client.Get(getGetObjectURL(s.endPoint, bucketName, objectName))
+	c.Assert(err, IsNil)
+	c.Assert(response.StatusCode, Equals, http.StatusOK)
+	responseBody, err := ioutil.ReadAll(response.Body)
+	c.Assert(err, IsNil)
+	c.Assert(string(responseBody), Equals, "hello world")
+
+	// Anonymous listing of the bucket succeeds.
+	response, err = client.Get(getListObjectsV1URL(s.endPoint, bucketName, "1000"))
+	c.Assert(err, IsNil)
+	c.Assert(response.StatusCode, Equals, http.StatusOK)
+
+	// Anonymous PUT is still denied.
+	request, err = newTestRequest("PUT", getPutObjectURL(s.endPoint, bucketName, "anonymous-object"),
+		int64(len("hello world")), bytes.NewReader([]byte("hello world")))
+	c.Assert(err, IsNil)
+	response, err = client.Do(request)
+	c.Assert(err, IsNil)
+	verifyError(c, response, "AccessDenied", "Access Denied.", http.StatusForbidden)
+
+	// Anonymous reads are denied again once the policy is deleted.
+	request, err = newTestSignedRequest("DELETE", getDeletePolicyURL(s.endPoint, bucketName), 0, nil,
+		s.accessKey, s.secretKey, s.signer)
+	c.Assert(err, IsNil)
+	response, err = client.Do(request)
+	c.Assert(err, IsNil)
+	c.Assert(response.StatusCode, Equals, http.StatusNoContent)
+
+	response, err = client.Get(getGetObjectURL(s.endPoint, bucketName, objectName))
+	c.Assert(err, IsNil)
+	verifyError(c, response, "AccessDenied", "Access Denied.", http.StatusForbidden)
+}
+
 // TestDeleteBucket - validates DELETE bucket operation.
 func (s *TestSuiteCommon) TestDeleteBucket(c *C) {
 	bucketName := getRandomBucketName()
@@ -1500,7 +1576,8 @@ func (s *TestSuiteCommon) TestListObjectsHandler(c *C) {
 	getContent, err = ioutil.ReadAll(response.Body)
 	c.Assert(err, IsNil)
 	c.Assert(strings.Contains(string(getContent), "<Key>bar</Key>"), Equals, true)
-	c.Assert(strings.Contains(string(getContent), "<Owner><ID></ID><DisplayName></DisplayName></Owner>"), Equals, true)
+	// Owner is omitted unless fetch-owner is set.
+	c.Assert(strings.Contains(string(getContent), "<Owner>"), Equals, false)
 
 	// create listObjectsV2 request with valid parameters and fetch-owner activated
 	request, err = newTestSignedRequest("GET", getListObjectsV2URL(s.endPoint, bucketName, "1000", "true"),
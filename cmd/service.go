@@ -105,6 +105,10 @@ func (m *ServerMux) handleServiceSignals() error {
 			if err := m.Close(); err != nil {
 				errorIf(err, "Unable to close server gracefully")
 			}
+			// Requests which did not complete in time may still hold
+			// namespace locks, release them so that other servers
+			// are not blocked.
+			globalNSMutex.forceUnlockAll()
 			objAPI := newObjectLayerFn()
 			if objAPI == nil {
 				// Server not initialized yet, exit happily.
@@ -87,10 +87,11 @@ func doesPresignV2SignatureMatch(r *http.Request) APIErrorCode {
 
 	// url.RawPath will be valid if path has any encoded characters, if not it will
 	// be empty - in which case we need to consider url.Path (bug in net/http?)
-	encodedResource := r.URL.RawPath
+	reqURL := pathStyleURL(r)
+	encodedResource := reqURL.RawPath
 	encodedQuery := r.URL.RawQuery
 	if encodedResource == "" {
-		splits := strings.Split(r.URL.Path, "?")
+		splits := strings.Split(reqURL.Path, "?")
 		if len(splits) > 0 {
 			encodedResource = splits[0]
 		}
@@ -150,6 +151,7 @@ func doesPresignV2SignatureMatch(r *http.Request) APIErrorCode {
 	if gotSignature != expectedSignature {
 		return ErrSignatureDoesNotMatch
 	}
+	setRequestTenant(r, cred.AccessKey)
 
 	return ErrNone
 }
@@ -215,9 +217,10 @@ func doesSignV2Match(r *http.Request) APIErrorCode {
 	// Encode path:
 	//   url.RawPath will be valid if path has any encoded characters, if not it will
 	//   be empty - in which case we need to consider url.Path (bug in net/http?)
-	encodedResource := r.URL.RawPath
+	reqURL := pathStyleURL(r)
+	encodedResource := reqURL.RawPath
 	if encodedResource == "" {
-		splits := strings.Split(r.URL.Path, "?")
+		splits := strings.Split(reqURL.Path, "?")
 		if len(splits) > 0 {
 			encodedResource = getURLEncodedName(splits[0])
 		}
@@ -230,6 +233,7 @@ func doesSignV2Match(r *http.Request) APIErrorCode {
 	if v2Auth != expectedAuth {
 		return ErrSignatureDoesNotMatch
 	}
+	setRequestTenant(r, serverConfig.GetCredential().AccessKey)
 
 	return ErrNone
 }
@@ -59,6 +59,21 @@ func isValidRegion(reqRegion string, confRegion string) bool {
 	return reqRegion == confRegion
 }
 
+// getSignatureRegion - validates the region of a credential scope
+// against the server region and returns the region to calculate the
+// signature with. When region validation is disabled any region is
+// accepted and the signature is calculated with the region as sent
+// by the client.
+func getSignatureRegion(reqRegion string, confRegion string) (string, APIErrorCode) {
+	if globalIgnoreSignatureRegion {
+		return reqRegion, ErrNone
+	}
+	if !isValidRegion(reqRegion, confRegion) {
+		return "", ErrInvalidRegion
+	}
+	return confRegion, ErrNone
+}
+
 // sumHMAC calculate hmac between two input byte array.
 func sumHMAC(key []byte, data []byte) []byte {
 	hash := hmac.New(sha256.New, key)
@@ -98,6 +98,38 @@ func TestIsValidRegion(t *testing.T) {
 	}
 }
 
+// Tests validate the region used for calculating the signature with and
+// without region validation.
+func TestGetSignatureRegion(t *testing.T) {
+	defer func(ignore bool) { globalIgnoreSignatureRegion = ignore }(globalIgnoreSignatureRegion)
+
+	testCases := []struct {
+		reqRegion       string
+		confRegion      string
+		ignoreRegion    bool
+		expectedRegion  string
+		expectedErrCode APIErrorCode
+	}{
+		{"us-east-1", "us-east-1", false, "us-east-1", ErrNone},
+		{"US", "us-east-1", false, "us-east-1", ErrNone},
+		{"us-east-1", "eu-west-1", false, "", ErrInvalidRegion},
+		// Region of the credential scope is used as is.
+		{"us-east-1", "eu-west-1", true, "us-east-1", ErrNone},
+		{"eu-west-1", "eu-west-1", true, "eu-west-1", ErrNone},
+	}
+
+	for i, testCase := range testCases {
+		globalIgnoreSignatureRegion = testCase.ignoreRegion
+		region, errCode := getSignatureRegion(testCase.reqRegion, testCase.confRegion)
+		if errCode != testCase.expectedErrCode {
+			t.Errorf("Test %d: Expected error code %d, got %d", i+1, testCase.expectedErrCode, errCode)
+		}
+		if region != testCase.expectedRegion {
+			t.Errorf("Test %d: Expected region `%s`, got `%s`", i+1, testCase.expectedRegion, region)
+		}
+	}
+}
+
 // Tests validate the URL path encoder.
 func TestGetURLEncodedName(t *testing.T) {
 	testCases := []struct {
@@ -42,6 +42,9 @@ const (
 	signV4Algorithm = "AWS4-HMAC-SHA256"
 	iso8601Format   = "20060102T150405Z"
 	yyyymmdd        = "20060102"
+
+	// Default maximum X-Amz-Expires of presigned URLs, S3 allows a week.
+	defaultMaxPresignExpiry = 7 * 24 * time.Hour
 )
 
 // getCanonicalHeaders generate a list of request headers with their values
@@ -176,9 +179,9 @@ func doesPolicySignatureV4Match(formValues map[string]string) APIErrorCode {
 	}
 
 	// Verify if the region is valid.
-	sRegion := credHeader.scope.region
-	if !isValidRegion(sRegion, region) {
-		return ErrInvalidRegion
+	region, errCode := getSignatureRegion(credHeader.scope.region, region)
+	if errCode != ErrNone {
+		return errCode
 	}
 
 	// Parse date string.
@@ -216,6 +219,11 @@ func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region s
 		return err
 	}
 
+	// Verify if expiry is within the maximum allowed.
+	if pSignValues.Expires > serverConfig.GetMaxPresignExpiry() {
+		return ErrMaximumExpires
+	}
+
 	// Verify if the access key id matches.
 	if pSignValues.Credential.accessKey != cred.AccessKey {
 		return ErrInvalidAccessKeyID
@@ -233,8 +241,9 @@ func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region s
 	if region == "" {
 		region = sRegion
 	}
-	if !isValidRegion(sRegion, region) {
-		return ErrInvalidRegion
+	region, err = getSignatureRegion(sRegion, region)
+	if err != ErrNone {
+		return err
 	}
 
 	// Extract all the signed headers along with its values.
@@ -322,6 +331,7 @@ func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region s
 	if req.URL.Query().Get("X-Amz-Signature") != newSignature {
 		return ErrSignatureDoesNotMatch
 	}
+	setRequestTenant(r, cred.AccessKey)
 	return ErrNone
 }
 
@@ -383,8 +393,9 @@ func doesSignatureMatch(hashedPayload string, r *http.Request, region string) AP
 		region = sRegion
 	}
 	// Should validate region, only if region is set.
-	if !isValidRegion(sRegion, region) {
-		return ErrInvalidRegion
+	region, errCode = getSignatureRegion(sRegion, region)
+	if errCode != ErrNone {
+		return errCode
 	}
 
 	// Extract date, if not present throw error.
@@ -419,6 +430,7 @@ func doesSignatureMatch(hashedPayload string, r *http.Request, region string) AP
 	if newSignature != signV4Values.Signature {
 		return ErrSignatureDoesNotMatch
 	}
+	setRequestTenant(r, cred.AccessKey)
 
 	// Return error none.
 	return ErrNone
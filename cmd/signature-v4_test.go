@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -99,6 +100,38 @@ func TestDoesPolicySignatureMatch(t *testing.T) {
 	}
 }
 
+// Tests signatures calculated for a region other than the server
+// region are accepted only when region validation is disabled.
+func TestDoesPolicySignatureMatchIgnoreRegion(t *testing.T) {
+	defer func(ignore bool) { globalIgnoreSignatureRegion = ignore }(globalIgnoreSignatureRegion)
+
+	credentialTemplate := "%s/%s/%s/s3/aws4_request"
+	now := time.Now().UTC()
+	cred := serverConfig.GetCredential()
+	form := map[string]string{
+		"X-Amz-Credential": fmt.Sprintf(credentialTemplate, cred.AccessKey, now.Format(yyyymmdd), "eu-west-1"),
+		"X-Amz-Date":       now.Format(iso8601Format),
+		"X-Amz-Signature":  getSignature(getSigningKey(cred.SecretKey, now, "eu-west-1"), "policy"),
+		"Policy":           "policy",
+	}
+
+	globalIgnoreSignatureRegion = false
+	if code := doesPolicySignatureMatch(form); code != ErrInvalidRegion {
+		t.Errorf("Expected %s, got %s", niceError(ErrInvalidRegion), niceError(code))
+	}
+
+	globalIgnoreSignatureRegion = true
+	if code := doesPolicySignatureMatch(form); code != ErrNone {
+		t.Errorf("Expected %s, got %s", niceError(ErrNone), niceError(code))
+	}
+
+	// Everything other than the region is still validated.
+	form["X-Amz-Signature"] = getSignature(getSigningKey(cred.SecretKey, now, "us-east-1"), "policy")
+	if code := doesPolicySignatureMatch(form); code != ErrSignatureDoesNotMatch {
+		t.Errorf("Expected %s, got %s", niceError(ErrSignatureDoesNotMatch), niceError(code))
+	}
+}
+
 func TestDoesPresignedSignatureMatch(t *testing.T) {
 	rootPath, err := newTestConfig("us-east-1")
 	if err != nil {
@@ -332,3 +365,65 @@ func TestDoesPresignedSignatureMatch(t *testing.T) {
 		}
 	}
 }
+
+// Tests presigned URLs expire at most after a week unless the maximum
+// expiry is raised in config.
+func TestDoesPresignedSignatureMatchMaxExpiry(t *testing.T) {
+	rootPath, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	cred := serverConfig.GetCredential()
+	week := int64(7 * 24 * 60 * 60)
+	testCases := []struct {
+		maxExpiry time.Duration
+		expires   int64
+		expected  APIErrorCode
+	}{
+		// S3 default of a week.
+		{0, week, ErrNone},
+		{0, week + 1, ErrMaximumExpires},
+		// Raised to 30 days.
+		{30 * 24 * time.Hour, week, ErrNone},
+		{30 * 24 * time.Hour, week + 1, ErrNone},
+		{30 * 24 * time.Hour, 30*24*60*60 + 1, ErrMaximumExpires},
+		// Lowered to an hour.
+		{time.Hour, 3600, ErrNone},
+		{time.Hour, 3601, ErrMaximumExpires},
+	}
+	for i, testCase := range testCases {
+		serverConfig.SetMaxPresignExpiry(testCase.maxExpiry)
+		req, err := http.NewRequest("GET", "http://127.0.0.1:9000/bucket/object", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = preSignV4(req, cred.AccessKey, cred.SecretKey, testCase.expires); err != nil {
+			t.Fatal(err)
+		}
+		if s3Error := doesPresignedSignatureMatch(unsignedPayload, req, serverConfig.GetRegion()); s3Error != testCase.expected {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, getAPIError(testCase.expected).Code, getAPIError(s3Error).Code)
+		}
+	}
+
+	// Expiry is still honored with the raised maximum.
+	serverConfig.SetMaxPresignExpiry(30 * 24 * time.Hour)
+	defer serverConfig.SetMaxPresignExpiry(0)
+	req, err := http.NewRequest("GET", "http://127.0.0.1:9000/bucket/object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := req.URL.Query()
+	date := time.Now().UTC().Add(-8 * 24 * time.Hour)
+	query.Set("X-Amz-Algorithm", signV4Algorithm)
+	query.Set("X-Amz-Date", date.Format(iso8601Format))
+	query.Set("X-Amz-Expires", strconv.FormatInt(week, 10))
+	query.Set("X-Amz-SignedHeaders", "host")
+	query.Set("X-Amz-Credential", cred.AccessKey+"/"+getScope(date, serverConfig.GetRegion()))
+	query.Set("X-Amz-Signature", "signature")
+	req.URL.RawQuery = query.Encode()
+	if s3Error := doesPresignedSignatureMatch(unsignedPayload, req, serverConfig.GetRegion()); s3Error != ErrExpiredPresignRequest {
+		t.Errorf("Expected expired presigned URL to be rejected, got %s", getAPIError(s3Error).Code)
+	}
+}
@@ -0,0 +1,72 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// slowRequestHandler - logs completed requests which took longer than
+// the slow request threshold of the config, read for every request
+// such that config reloads apply to the next requests.
+type slowRequestHandler struct {
+	handler http.Handler
+}
+
+func setSlowRequestHandler(h http.Handler) http.Handler {
+	return slowRequestHandler{h}
+}
+
+func (h slowRequestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	threshold := serverConfig.GetSlowRequestThreshold()
+	if threshold <= 0 {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	start := time.Now()
+	aw := &accountingResponseWriter{ResponseWriter: w}
+	h.handler.ServeHTTP(aw, r)
+	if duration := time.Since(start); duration > threshold {
+		logSlowRequest(r, aw.status, duration)
+	}
+}
+
+// logSlowRequest - logs method, resource, response status and duration
+// of a slow request, along with its ID.
+func logSlowRequest(r *http.Request, status int, duration time.Duration) {
+	if status == 0 {
+		// Nothing written, replied with 200 OK.
+		status = http.StatusOK
+	}
+	fields := logrus.Fields{
+		"method":   r.Method,
+		"resource": r.URL.Path,
+		"status":   status,
+		"duration": duration.String(),
+	}
+	if requestID := getRequestID(r); requestID != "" {
+		fields["requestID"] = requestID
+	}
+
+	for _, log := range log.loggers {
+		log.WithFields(fields).Warnf("Slow request %s %s took %s.", r.Method, r.URL.Path, duration)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Tests only requests slower than the threshold are logged, and
+// threshold changes of a config reload apply to the next requests.
+func TestSlowRequestHandler(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	var buffer bytes.Buffer
+	testLog := logrus.New()
+	testLog.Out = &buffer
+	testLog.Formatter = new(logrus.JSONFormatter)
+	defer func(loggers []*logrus.Logger) {
+		log.mu.Lock()
+		log.loggers = loggers
+		log.mu.Unlock()
+	}(log.loggers)
+	log.mu.Lock()
+	log.loggers = []*logrus.Logger{testLog}
+	log.mu.Unlock()
+
+	delay := 50 * time.Millisecond
+	handler := setRequestIDHandler(setSlowRequestHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("slow") != "" {
+			time.Sleep(delay)
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})))
+	serve := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", target, nil))
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("Expected status %d, got %d", http.StatusNotFound, rec.Code)
+		}
+		return rec
+	}
+
+	// Slow requests are not logged by default.
+	serve("/bucket/object?slow=1")
+	if buffer.Len() != 0 {
+		t.Fatalf("Expected no slow request logged by default, got %q", buffer.String())
+	}
+
+	newCfg := *serverConfig
+	newCfg.SlowRequestThreshold = int64(delay/time.Millisecond) / 2
+	if err = newCfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err = reloadConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Requests under the threshold are not logged.
+	serve("/bucket/object")
+	if buffer.Len() != 0 {
+		t.Fatalf("Expected no fast request logged, got %q", buffer.String())
+	}
+
+	rec := serve("/bucket/object?slow=1")
+	if bytes.Count(buffer.Bytes(), []byte("\n")) != 1 {
+		t.Fatalf("Expected a single slow request logged, got %q", buffer.String())
+	}
+	var entry map[string]interface{}
+	if err = json.Unmarshal(buffer.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON, got %q: %s", buffer.String(), err)
+	}
+	expectedEntry := map[string]interface{}{
+		"level":     "warning",
+		"method":    "GET",
+		"resource":  "/bucket/object",
+		"status":    float64(http.StatusNotFound),
+		"requestID": rec.Header().Get(responseRequestIDKey),
+	}
+	for key, value := range expectedEntry {
+		if entry[key] != value {
+			t.Errorf("Expected %s to be %v, got %v", key, value, entry[key])
+		}
+	}
+	durationStr, _ := entry["duration"].(string)
+	if duration, err := time.ParseDuration(durationStr); err != nil || duration < delay {
+		t.Errorf("Expected duration of at least %s, got %v", delay, entry["duration"])
+	}
+
+	// Slow requests are no longer logged once disabled.
+	buffer.Reset()
+	serverConfig.SetSlowRequestThreshold(0)
+	serve("/bucket/object?slow=1")
+	if buffer.Len() != 0 {
+		t.Fatalf("Expected no slow request logged once disabled, got %q", buffer.String())
+	}
+}
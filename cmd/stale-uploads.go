@@ -0,0 +1,199 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// Incomplete multipart uploads without activity for longer are
+	// aborted, unless configured otherwise.
+	defaultStaleUploadsExpiry = 7 * 24 * time.Hour
+
+	// Interval between scans for stale multipart uploads.
+	staleUploadsCleanupInterval = time.Hour
+
+	// Time to wait for the lock of a stale upload, uploads locked for
+	// longer are in use and skipped.
+	staleUploadLockTimeout = time.Second
+)
+
+// activeUploads - counts the parts being received by this server for
+// each multipart upload.
+type activeUploads struct {
+	mu    sync.Mutex
+	parts map[string]int
+}
+
+func newActiveUploads() *activeUploads {
+	return &activeUploads{parts: make(map[string]int)}
+}
+
+// add - records a part of uploadID is being received.
+func (a *activeUploads) add(uploadID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.parts[uploadID]++
+}
+
+// done - records a part of uploadID is no longer being received.
+func (a *activeUploads) done(uploadID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.parts[uploadID]--
+	if a.parts[uploadID] <= 0 {
+		delete(a.parts, uploadID)
+	}
+}
+
+// isActive - returns true if any part of uploadID is being received.
+func (a *activeUploads) isActive(uploadID string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.parts[uploadID] > 0
+}
+
+// Parts being received by this server, stale uploads receiving parts
+// are not aborted.
+var globalActiveUploads = newActiveUploads()
+
+// startStaleUploadsCleanup - aborts stale multipart uploads every
+// interval until doneCh is closed. Servers of a distributed setup share
+// the uploads, only the server of the first endpoint cleans them up.
+func startStaleUploadsCleanup(objAPI ObjectLayer, interval time.Duration, doneCh <-chan struct{}) {
+	if globalIsDistXL && globalLockOwnNode != 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			abortStaleUploads(objAPI, serverConfig.GetStaleUploadsExpiry(), time.Now().UTC())
+		case <-doneCh:
+			return
+		}
+	}
+}
+
+// abortStaleUploads - aborts multipart uploads of all buckets without
+// activity for expiry at now.
+func abortStaleUploads(objAPI ObjectLayer, expiry time.Duration, now time.Time) {
+	buckets, err := objAPI.ListBuckets()
+	if err != nil {
+		errorIf(err, "Unable to list buckets.")
+		return
+	}
+	for _, bucket := range buckets {
+		err = abortBucketStaleUploads(objAPI, bucket.Name, expiry, now)
+		if isErrBucketNotFound(err) {
+			continue
+		}
+		errorIf(err, "Unable to abort stale uploads of bucket %s.", bucket.Name)
+	}
+}
+
+// uploadLockedObjects - object layers listing the parts of and
+// aborting multipart uploads whose lock is held by the caller.
+type uploadLockedObjects interface {
+	isUploadIDExists(bucket, object, uploadID string) bool
+	listObjectParts(bucket, object, uploadID string, partNumberMarker, maxParts int) (ListPartsInfo, error)
+	abortMultipartUpload(bucket, object, uploadID string) error
+}
+
+// abortBucketStaleUploads - aborts multipart uploads of bucket without
+// activity for expiry at now. Parts of the aborted uploads are removed.
+func abortBucketStaleUploads(objAPI ObjectLayer, bucket string, expiry time.Duration, now time.Time) error {
+	if sseObjAPI, ok := objAPI.(sseObjects); ok {
+		objAPI = sseObjAPI.ObjectLayer
+	}
+	lockedObjAPI, ok := objAPI.(uploadLockedObjects)
+	if !ok {
+		return traceError(NotImplemented{})
+	}
+
+	keyMarker, uploadIDMarker := "", ""
+	for {
+		result, err := objAPI.ListMultipartUploads(bucket, "", keyMarker, uploadIDMarker, "", maxUploadsList)
+		if err != nil {
+			return err
+		}
+		for _, upload := range result.Uploads {
+			if now.Sub(upload.Initiated) < expiry || globalActiveUploads.isActive(upload.UploadID) {
+				continue
+			}
+			if err = abortStaleUpload(lockedObjAPI, bucket, upload, expiry, now); err != nil {
+				return err
+			}
+		}
+		if !result.IsTruncated {
+			return nil
+		}
+		keyMarker, uploadIDMarker = result.NextKeyMarker, result.NextUploadIDMarker
+	}
+}
+
+// abortStaleUpload - aborts upload if it had no activity for expiry at
+// now. The upload is locked meanwhile, such that no part is committed
+// and it is not completed concurrently, uploads locked elsewhere are
+// skipped.
+func abortStaleUpload(objAPI uploadLockedObjects, bucket string, upload uploadMetadata, expiry time.Duration, now time.Time) error {
+	uploadIDLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket,
+		pathJoin(bucket, upload.Object, upload.UploadID))
+	if uploadIDLock.GetLock(staleUploadLockTimeout) != nil {
+		return nil
+	}
+	defer uploadIDLock.Unlock()
+
+	// Completed or aborted meanwhile.
+	if !objAPI.isUploadIDExists(bucket, upload.Object, upload.UploadID) {
+		return nil
+	}
+	lastActivity, err := uploadLastActivity(objAPI, bucket, upload)
+	if err != nil {
+		return err
+	}
+	if now.Sub(lastActivity) < expiry {
+		return nil
+	}
+	return objAPI.abortMultipartUpload(bucket, upload.Object, upload.UploadID)
+}
+
+// uploadLastActivity - returns when upload was initiated or last
+// received a part, whichever is later. The lock of the upload is held
+// by the caller.
+func uploadLastActivity(objAPI uploadLockedObjects, bucket string, upload uploadMetadata) (time.Time, error) {
+	lastActivity := upload.Initiated
+	partNumberMarker := 0
+	for {
+		result, err := objAPI.listObjectParts(bucket, upload.Object, upload.UploadID, partNumberMarker, maxPartsList)
+		if err != nil {
+			return time.Time{}, err
+		}
+		for _, part := range result.Parts {
+			if part.LastModified.After(lastActivity) {
+				lastActivity = part.LastModified
+			}
+		}
+		if !result.IsTruncated {
+			return lastActivity, nil
+		}
+		partNumberMarker = result.NextPartNumberMarker
+	}
+}
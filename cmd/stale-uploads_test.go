@@ -0,0 +1,102 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// Wrapper for calling abortBucketStaleUploads tests for both XL
+// multiple disks and single node setup.
+func TestAbortStaleUploads(t *testing.T) {
+	ExecObjectLayerTest(t, testAbortStaleUploads)
+}
+
+// Tests only uploads without activity for the expiry are aborted.
+func testAbortStaleUploads(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	// Uploads are initiated one after the other, such that each of
+	// them is more recent than the previous one.
+	uploadIDs := make(map[string]string)
+	newUpload := func(object string) {
+		uploadID, err := obj.NewMultipartUpload(bucket, object, nil)
+		if err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+		uploadIDs[object] = uploadID
+		time.Sleep(10 * time.Millisecond)
+	}
+	newUpload("active")
+	newUpload("receiving")
+	newUpload("locked")
+	newUpload("old")
+	if _, err := obj.PutObjectPart(bucket, "active", uploadIDs["active"], 1, 4, bytes.NewReader([]byte("abcd")), "", ""); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	newUpload("fresh")
+
+	result, err := obj.ListMultipartUploads(bucket, "", "", "", "", maxUploadsList)
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	var oldInitiated time.Time
+	for _, upload := range result.Uploads {
+		if upload.Object == "old" {
+			oldInitiated = upload.Initiated
+		}
+	}
+
+	// Uploads whose last activity is no more recent than the old
+	// upload are stale, unless receiving a part or locked, e.g. by
+	// another server.
+	globalActiveUploads.add(uploadIDs["receiving"])
+	defer globalActiveUploads.done(uploadIDs["receiving"])
+	uploadIDLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket, pathJoin(bucket, "locked", uploadIDs["locked"]))
+	uploadIDLock.RLock()
+	defer uploadIDLock.RUnlock()
+	expiry := 7 * 24 * time.Hour
+	if err = abortBucketStaleUploads(obj, bucket, expiry, oldInitiated.Add(expiry)); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	result, err = obj.ListMultipartUploads(bucket, "", "", "", "", maxUploadsList)
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	remaining := make(map[string]bool)
+	for _, upload := range result.Uploads {
+		remaining[upload.Object] = true
+	}
+	for _, object := range []string{"active", "receiving", "locked", "fresh"} {
+		if !remaining[object] {
+			t.Errorf("%s: Expected upload of %s to be kept", instanceType, object)
+		}
+	}
+	if remaining["old"] {
+		t.Errorf("%s: Expected upload of old to be aborted", instanceType)
+	}
+	if _, err = obj.ListObjectParts(bucket, "old", uploadIDs["old"], 0, maxPartsList); !isErrInvalidUploadID(err) {
+		t.Errorf("%s: Expected parts of the aborted upload to be removed, got %v", instanceType, err)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/minio/mc/pkg/console"
+)
+
+// startupPhase - wall-clock duration of a single phase of the startup
+// sequence.
+type startupPhase struct {
+	name     string
+	duration time.Duration
+}
+
+// startupTimer - records durations of the startup sequence phases,
+// all methods are no-ops unless enabled.
+type startupTimer struct {
+	enabled bool
+	start   time.Time
+	phases  []startupPhase
+}
+
+// newStartupTimer - initializes a startup timer, total duration is
+// measured from now.
+func newStartupTimer(enabled bool) *startupTimer {
+	return &startupTimer{
+		enabled: enabled,
+		start:   time.Now().UTC(),
+	}
+}
+
+// timePhase - starts timing a phase, returns a function to be called
+// once the phase is complete which logs and records its duration.
+func (t *startupTimer) timePhase(name string) func() {
+	if !t.enabled {
+		return func() {}
+	}
+	start := time.Now().UTC()
+	return func() {
+		duration := time.Since(start)
+		t.phases = append(t.phases, startupPhase{name, duration})
+		console.Println(colorBlue("Startup phase ") + colorBold(name) + fmt.Sprintf(" took %s", duration))
+	}
+}
+
+// summary - returns a table of all the recorded phases along with the
+// total duration of the startup sequence.
+func (t *startupTimer) summary() string {
+	nameLen := len("Total")
+	for _, phase := range t.phases {
+		if len(phase.name) > nameLen {
+			nameLen = len(phase.name)
+		}
+	}
+	total := time.Since(t.start)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%-*s  %12s  %6s\n", nameLen, "Phase", "Duration", "Share")
+	for _, phase := range t.phases {
+		share := float64(0)
+		if total > 0 {
+			share = 100 * float64(phase.duration) / float64(total)
+		}
+		fmt.Fprintf(&buf, "%-*s  %12s  %5.1f%%\n", nameLen, phase.name, phase.duration, share)
+	}
+	fmt.Fprintf(&buf, "%-*s  %12s", nameLen, "Total", total)
+	return buf.String()
+}
+
+// printSummary - prints the summary table of the startup sequence.
+func (t *startupTimer) printSummary() {
+	if !t.enabled {
+		return
+	}
+	console.Println(colorBlue("\nStartup timing:"))
+	console.Println(t.summary())
+}
@@ -0,0 +1,57 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Tests durations of startup phases are recorded and summarized.
+func TestStartupTimer(t *testing.T) {
+	disabled := newStartupTimer(false)
+	disabled.timePhase("houseKeeping")()
+	if len(disabled.phases) != 0 {
+		t.Fatalf("Expected no phases recorded when disabled, got %v", disabled.phases)
+	}
+
+	timer := newStartupTimer(true)
+	for _, name := range []string{"initStorageDisks", "waitForFormatDisks"} {
+		phaseDone := timer.timePhase(name)
+		time.Sleep(time.Millisecond)
+		phaseDone()
+	}
+	if len(timer.phases) != 2 {
+		t.Fatalf("Expected 2 phases recorded, got %v", timer.phases)
+	}
+	for _, phase := range timer.phases {
+		if phase.duration < time.Millisecond {
+			t.Errorf("Expected phase %s to take at least 1ms, got %s", phase.name, phase.duration)
+		}
+	}
+
+	lines := strings.Split(timer.summary(), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected header, 2 phases and total in summary, got %q", lines)
+	}
+	for i, prefix := range []string{"Phase", "initStorageDisks", "waitForFormatDisks", "Total"} {
+		if !strings.HasPrefix(lines[i], prefix) {
+			t.Errorf("Expected line %d of summary to start with %s, got %q", i+1, prefix, lines[i])
+		}
+	}
+}
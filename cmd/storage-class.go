@@ -0,0 +1,92 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Prefix of erasure code storage classes, EC:<parity>.
+	storageClassECPrefix = "EC:"
+
+	// Minimum parity blocks of a storage class.
+	minStorageClassParity = 2
+)
+
+const (
+	// Header and metadata key of the S3 storage class of an object,
+	// returned as a header on GET and HEAD.
+	amzStorageClassHeader = "X-Amz-Storage-Class"
+
+	// S3 storage classes of objects, objects of all the classes are
+	// erasure coded with the parity of the server.
+	standardStorageClass          = "STANDARD"
+	reducedRedundancyStorageClass = "REDUCED_REDUNDANCY"
+)
+
+// setStorageClassFromHeader - saves into metadata the S3 storage class
+// of the x-amz-storage-class header, if set. Objects without a storage
+// class are of the STANDARD class.
+func setStorageClassFromHeader(header http.Header, metadata map[string]string) APIErrorCode {
+	delete(metadata, amzStorageClassHeader)
+	if _, ok := header[amzStorageClassHeader]; !ok {
+		return ErrNone
+	}
+	switch sc := header.Get(amzStorageClassHeader); sc {
+	case standardStorageClass, reducedRedundancyStorageClass:
+		metadata[amzStorageClassHeader] = sc
+		return ErrNone
+	}
+	return ErrInvalidStorageClass
+}
+
+// getObjectStorageClass - returns the S3 storage class saved in the
+// metadata of an object.
+func getObjectStorageClass(metadata map[string]string) string {
+	if sc, ok := metadata[amzStorageClassHeader]; ok {
+		return sc
+	}
+	return standardStorageClass
+}
+
+// parseStorageClass - returns parity blocks of storage class sc on a
+// number of disks, zero if sc is empty for the default of half the
+// disks of an erasure set. Parity must be between 2 and half the disks
+// of an erasure set.
+func parseStorageClass(sc string, disks int) (int, error) {
+	if sc == "" {
+		return 0, nil
+	}
+	if disks == 1 {
+		return 0, errors.New("Storage class is only supported by erasure coded setups")
+	}
+	parity, err := strconv.Atoi(strings.TrimPrefix(sc, storageClassECPrefix))
+	if !strings.HasPrefix(sc, storageClassECPrefix) || err != nil {
+		return 0, fmt.Errorf("Unsupported storage class %s, expected %s<parity>", sc, storageClassECPrefix)
+	}
+	maxParity := disks / getErasureSetCount(disks) / 2
+	if parity < minStorageClassParity || parity > maxParity {
+		return 0, fmt.Errorf("Parity %d of storage class %s must be between %d and %d",
+			parity, sc, minStorageClassParity, maxParity)
+	}
+	return parity, nil
+}
@@ -0,0 +1,196 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests parsing and validation of storage classes.
+func TestParseStorageClass(t *testing.T) {
+	testCases := []struct {
+		sc             string
+		disks          int
+		expectedParity int
+		success        bool
+	}{
+		// Default parity.
+		{"", 1, 0, true},
+		{"", 8, 0, true},
+		// Parity between 2 and half the disks of an erasure set.
+		{"EC:2", 4, 2, true},
+		{"EC:2", 8, 2, true},
+		{"EC:4", 8, 4, true},
+		{"EC:3", 7, 3, true},
+		{"EC:6", 24, 6, true},
+		{"EC:1", 8, 0, false},
+		{"EC:0", 8, 0, false},
+		{"EC:5", 8, 0, false},
+		{"EC:4", 7, 0, false},
+		{"EC:7", 24, 0, false},
+		{"EC:2", 2, 0, false},
+		// FS mode has no parity.
+		{"EC:2", 1, 0, false},
+		// Malformed storage classes.
+		{"EC:", 8, 0, false},
+		{"EC:two", 8, 0, false},
+		{"ec:2", 8, 0, false},
+		{"2", 8, 0, false},
+		{"REDUCED_REDUNDANCY", 8, 0, false},
+	}
+	for i, testCase := range testCases {
+		parity, err := parseStorageClass(testCase.sc, testCase.disks)
+		if testCase.success != (err == nil) {
+			t.Errorf("Test %d: %s on %d disks, unexpected error %v", i+1, testCase.sc, testCase.disks, err)
+			continue
+		}
+		if parity != testCase.expectedParity {
+			t.Errorf("Test %d: expected parity %d, got %d", i+1, testCase.expectedParity, parity)
+		}
+	}
+}
+
+// Tests validation of the x-amz-storage-class header.
+func TestSetStorageClassFromHeader(t *testing.T) {
+	testCases := []struct {
+		header          http.Header
+		expectedSC      string
+		expectedS3Error APIErrorCode
+	}{
+		// Test case - 1.
+		// No storage class.
+		{http.Header{}, standardStorageClass, ErrNone},
+		// Test case - 2.
+		{http.Header{"X-Amz-Storage-Class": []string{"STANDARD"}}, standardStorageClass, ErrNone},
+		// Test case - 3.
+		{http.Header{"X-Amz-Storage-Class": []string{"REDUCED_REDUNDANCY"}}, reducedRedundancyStorageClass, ErrNone},
+		// Test case - 4.
+		// Unsupported storage class.
+		{http.Header{"X-Amz-Storage-Class": []string{"GLACIER"}}, "", ErrInvalidStorageClass},
+		// Test case - 5.
+		// Storage classes are case sensitive.
+		{http.Header{"X-Amz-Storage-Class": []string{"standard"}}, "", ErrInvalidStorageClass},
+	}
+	for i, testCase := range testCases {
+		// A storage class in metadata, such as of the source of a copy, is replaced.
+		metadata := map[string]string{amzStorageClassHeader: reducedRedundancyStorageClass}
+		s3Error := setStorageClassFromHeader(testCase.header, metadata)
+		if s3Error != testCase.expectedS3Error {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedS3Error, s3Error)
+			continue
+		}
+		if s3Error == ErrNone && getObjectStorageClass(metadata) != testCase.expectedSC {
+			t.Errorf("Test %d: Expected storage class %s, got %s", i+1, testCase.expectedSC, getObjectStorageClass(metadata))
+		}
+	}
+}
+
+// Wrapper for calling storage class handler tests for both XL multiple disks and single node setup.
+func TestAPIStorageClass(t *testing.T) {
+	// Handlers are registered in the order of registerAPIRouter, such
+	// that copy requests do not match PutObject.
+	ExecObjectLayerAPITest(t, testAPIStorageClass, []string{"HeadObject", "GetObject", "CopyObject", "PutObject", "ListObjectsV1"})
+}
+
+func testAPIStorageClass(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	data := []byte("hello")
+
+	// do - signs and sends a request with headers, returns the recorded response.
+	do := func(method, urlStr string, body []byte, headers map[string]string) *httptest.ResponseRecorder {
+		req, err := newTestSignedRequestV4(method, urlStr, int64(len(body)), bytes.NewReader(body),
+			credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s %s: <ERROR> %v", instanceType, method, urlStr, err)
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// listStorageClasses - returns the storage classes of objects listed by ListObjects.
+	listStorageClasses := func() map[string]string {
+		rec := do("GET", "/"+bucketName, nil, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Expected ListObjects to succeed, got %d: %s", instanceType, rec.Code, rec.Body)
+		}
+		var resp ListObjectsResponse
+		if err := xml.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: Failed to parse ListObjects response: <ERROR> %v", instanceType, err)
+		}
+		classes := make(map[string]string)
+		for _, object := range resp.Contents {
+			classes[object.Key] = object.StorageClass
+		}
+		return classes
+	}
+
+	rec := do("PUT", "/"+bucketName+"/rrs", data, map[string]string{amzStorageClassHeader: reducedRedundancyStorageClass})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected PutObject to succeed, got %d: %s", instanceType, rec.Code, rec.Body)
+	}
+	rec = do("PUT", "/"+bucketName+"/standard", data, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected PutObject to succeed, got %d: %s", instanceType, rec.Code, rec.Body)
+	}
+
+	// Storage class is returned on HEAD and GET.
+	for _, method := range []string{"HEAD", "GET"} {
+		rec = do(method, "/"+bucketName+"/rrs", nil, nil)
+		if sc := rec.Header().Get(amzStorageClassHeader); sc != reducedRedundancyStorageClass {
+			t.Errorf("%s: Expected %s storage class %s, got `%s`", instanceType, method, reducedRedundancyStorageClass, sc)
+		}
+		rec = do(method, "/"+bucketName+"/standard", nil, nil)
+		if sc := rec.Header().Get(amzStorageClassHeader); sc != "" {
+			t.Errorf("%s: Expected %s without storage class, got `%s`", instanceType, method, sc)
+		}
+	}
+
+	// Storage class is listed.
+	classes := listStorageClasses()
+	if classes["rrs"] != reducedRedundancyStorageClass || classes["standard"] != standardStorageClass {
+		t.Errorf("%s: Unexpected listed storage classes %v", instanceType, classes)
+	}
+
+	// Unsupported storage class is rejected.
+	rec = do("PUT", "/"+bucketName+"/glacier", data, map[string]string{amzStorageClassHeader: "GLACIER"})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("%s: Expected PutObject with invalid storage class to fail with %d, got %d", instanceType, http.StatusBadRequest, rec.Code)
+	}
+	if _, err := obj.GetObjectInfo(bucketName, "glacier"); err == nil {
+		t.Errorf("%s: Expected object with invalid storage class not to be created", instanceType)
+	}
+
+	// Storage class of an object is changed by copying it onto itself.
+	rec = do("PUT", getCopyObjectURL("", bucketName, "rrs"), nil, map[string]string{
+		"X-Amz-Copy-Source":   "/" + bucketName + "/rrs",
+		amzStorageClassHeader: standardStorageClass,
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected CopyObject to succeed, got %d: %s", instanceType, rec.Code, rec.Body)
+	}
+	if classes = listStorageClasses(); classes["rrs"] != standardStorageClass {
+		t.Errorf("%s: Expected storage class %s after copy, got %s", instanceType, standardStorageClass, classes["rrs"])
+	}
+}
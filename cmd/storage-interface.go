@@ -39,6 +39,7 @@ type StorageAPI interface {
 	ReadFile(volume string, path string, offset int64, buf []byte) (n int64, err error)
 	PrepareFile(volume string, path string, len int64) (err error)
 	AppendFile(volume string, path string, buf []byte) (err error)
+	TruncateFile(volume string, path string, size int64) (err error)
 	RenameFile(srcVolume, srcPath, dstVolume, dstPath string) error
 	StatFile(volume string, path string) (file FileInfo, err error)
 	DeleteFile(volume string, path string) (err error)
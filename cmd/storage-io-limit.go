@@ -0,0 +1,198 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/minio/minio/pkg/disk"
+)
+
+// errInvalidDiskIOConcurrency - concurrency limit is not a positive integer.
+var errInvalidDiskIOConcurrency = errors.New("Concurrency limit must be a positive integer")
+
+// parseDiskIOConcurrency - returns the maximum number of concurrent I/O
+// operations per disk, zero if unlimited.
+func parseDiskIOConcurrency(limit string) (int, error) {
+	if limit == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(limit)
+	if err != nil || n <= 0 {
+		return 0, errInvalidDiskIOConcurrency
+	}
+	return n, nil
+}
+
+// Semaphores of the local disks by path, a disk is used both by the
+// object layer and by the storage RPC server serving other servers.
+var (
+	diskIOLimitsMu sync.Mutex
+	diskIOLimits   = make(map[string]chan struct{})
+)
+
+// getDiskIOLimit - returns the semaphore of the disk at diskPath, nil if
+// the I/O concurrency is unlimited.
+func getDiskIOLimit(diskPath string) chan struct{} {
+	if globalDiskIOConcurrency <= 0 {
+		return nil
+	}
+	diskIOLimitsMu.Lock()
+	defer diskIOLimitsMu.Unlock()
+	limit, ok := diskIOLimits[diskPath]
+	if !ok {
+		limit = make(chan struct{}, globalDiskIOConcurrency)
+		diskIOLimits[diskPath] = limit
+	}
+	return limit
+}
+
+// newLocalStorage - returns the local disk at path, its I/O operations
+// limited to the concurrency limit of the disk.
+func newLocalStorage(path string) (StorageAPI, error) {
+	storage, err := newPosix(path)
+	if err != nil {
+		return nil, err
+	}
+	return newIOLimitStorage(storage, getDiskIOLimit(storage.String())), nil
+}
+
+// ioLimitStorage - StorageAPI running at most cap(limit) operations on
+// disk at once, other operations wait for one to complete. Operations
+// never call one another, such that callers holding a slot, e.g. heal
+// or multipart uploads running many operations, cannot deadlock.
+type ioLimitStorage struct {
+	disk  StorageAPI
+	limit chan struct{}
+}
+
+// newIOLimitStorage - returns disk limited by the semaphore limit, disk
+// itself if limit is nil.
+func newIOLimitStorage(disk StorageAPI, limit chan struct{}) StorageAPI {
+	if limit == nil {
+		return disk
+	}
+	return &ioLimitStorage{disk: disk, limit: limit}
+}
+
+// acquire - waits for a slot of the disk.
+func (l *ioLimitStorage) acquire() {
+	l.limit <- struct{}{}
+}
+
+// release - frees the slot of an operation.
+func (l *ioLimitStorage) release() {
+	<-l.limit
+}
+
+func (l *ioLimitStorage) String() string {
+	return l.disk.String()
+}
+
+func (l *ioLimitStorage) Init() error {
+	return l.disk.Init()
+}
+
+func (l *ioLimitStorage) Close() error {
+	return l.disk.Close()
+}
+
+func (l *ioLimitStorage) DiskInfo() (disk.Info, error) {
+	l.acquire()
+	defer l.release()
+	return l.disk.DiskInfo()
+}
+
+func (l *ioLimitStorage) MakeVol(volume string) error {
+	l.acquire()
+	defer l.release()
+	return l.disk.MakeVol(volume)
+}
+
+func (l *ioLimitStorage) ListVols() ([]VolInfo, error) {
+	l.acquire()
+	defer l.release()
+	return l.disk.ListVols()
+}
+
+func (l *ioLimitStorage) StatVol(volume string) (VolInfo, error) {
+	l.acquire()
+	defer l.release()
+	return l.disk.StatVol(volume)
+}
+
+func (l *ioLimitStorage) DeleteVol(volume string) error {
+	l.acquire()
+	defer l.release()
+	return l.disk.DeleteVol(volume)
+}
+
+func (l *ioLimitStorage) ListDir(volume, dirPath string) ([]string, error) {
+	l.acquire()
+	defer l.release()
+	return l.disk.ListDir(volume, dirPath)
+}
+
+func (l *ioLimitStorage) ReadFile(volume string, path string, offset int64, buf []byte) (int64, error) {
+	l.acquire()
+	defer l.release()
+	return l.disk.ReadFile(volume, path, offset, buf)
+}
+
+func (l *ioLimitStorage) PrepareFile(volume string, path string, length int64) error {
+	l.acquire()
+	defer l.release()
+	return l.disk.PrepareFile(volume, path, length)
+}
+
+func (l *ioLimitStorage) AppendFile(volume string, path string, buf []byte) error {
+	l.acquire()
+	defer l.release()
+	return l.disk.AppendFile(volume, path, buf)
+}
+
+func (l *ioLimitStorage) TruncateFile(volume string, path string, size int64) error {
+	l.acquire()
+	defer l.release()
+	return l.disk.TruncateFile(volume, path, size)
+}
+
+func (l *ioLimitStorage) RenameFile(srcVolume, srcPath, dstVolume, dstPath string) error {
+	l.acquire()
+	defer l.release()
+	return l.disk.RenameFile(srcVolume, srcPath, dstVolume, dstPath)
+}
+
+func (l *ioLimitStorage) StatFile(volume string, path string) (FileInfo, error) {
+	l.acquire()
+	defer l.release()
+	return l.disk.StatFile(volume, path)
+}
+
+func (l *ioLimitStorage) DeleteFile(volume string, path string) error {
+	l.acquire()
+	defer l.release()
+	return l.disk.DeleteFile(volume, path)
+}
+
+func (l *ioLimitStorage) ReadAll(volume string, path string) ([]byte, error) {
+	l.acquire()
+	defer l.release()
+	return l.disk.ReadAll(volume, path)
+}
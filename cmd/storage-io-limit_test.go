@@ -0,0 +1,220 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Tests parsing of MINIO_DISK_IO_CONCURRENCY.
+func TestParseDiskIOConcurrency(t *testing.T) {
+	testCases := []struct {
+		limit         string
+		expectedLimit int
+		expectedErr   error
+	}{
+		{"", 0, nil},
+		{"1", 1, nil},
+		{"32", 32, nil},
+		{"0", 0, errInvalidDiskIOConcurrency},
+		{"-4", 0, errInvalidDiskIOConcurrency},
+		{"many", 0, errInvalidDiskIOConcurrency},
+	}
+	for i, testCase := range testCases {
+		limit, err := parseDiskIOConcurrency(testCase.limit)
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if limit != testCase.expectedLimit {
+			t.Errorf("Test %d: Expected limit %d, got %d", i+1, testCase.expectedLimit, limit)
+		}
+	}
+}
+
+// concurrencyDisk - mock disk recording the maximum number of
+// operations running at once, operations last for delay.
+type concurrencyDisk struct {
+	StorageAPI
+	delay   time.Duration
+	running int32
+	max     int32
+}
+
+// operation - runs an operation, recording how many run at once.
+func (d *concurrencyDisk) operation() {
+	running := atomic.AddInt32(&d.running, 1)
+	for {
+		max := atomic.LoadInt32(&d.max)
+		if running <= max || atomic.CompareAndSwapInt32(&d.max, max, running) {
+			break
+		}
+	}
+	time.Sleep(d.delay)
+	atomic.AddInt32(&d.running, -1)
+}
+
+func (d *concurrencyDisk) ReadFile(volume, path string, offset int64, buf []byte) (int64, error) {
+	d.operation()
+	return int64(len(buf)), nil
+}
+
+func (d *concurrencyDisk) AppendFile(volume, path string, buf []byte) error {
+	d.operation()
+	return nil
+}
+
+func (d *concurrencyDisk) StatFile(volume, path string) (FileInfo, error) {
+	d.operation()
+	return FileInfo{Volume: volume, Name: path}, nil
+}
+
+// Tests no more operations than the limit run at once on a disk.
+func TestIOLimitStorage(t *testing.T) {
+	for _, limit := range []int{1, 3, 8} {
+		mockDisk := &concurrencyDisk{delay: 5 * time.Millisecond}
+		disk := newIOLimitStorage(mockDisk, make(chan struct{}, limit))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 32; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				switch i % 3 {
+				case 0:
+					disk.ReadFile("bucket", "object", 0, make([]byte, 1))
+				case 1:
+					disk.AppendFile("bucket", "object", []byte("a"))
+				default:
+					disk.StatFile("bucket", "object")
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		if max := atomic.LoadInt32(&mockDisk.max); max > int32(limit) {
+			t.Errorf("Expected at most %d concurrent operations, got %d", limit, max)
+		}
+	}
+
+	// Disks are not wrapped if unlimited.
+	mockDisk := &concurrencyDisk{}
+	if disk := newIOLimitStorage(mockDisk, nil); disk != StorageAPI(mockDisk) {
+		t.Error("Expected disk without limit not to be wrapped")
+	}
+}
+
+// Tests disks share their semaphore by path and are unlimited by default.
+func TestGetDiskIOLimit(t *testing.T) {
+	defer func(limit int) { globalDiskIOConcurrency = limit }(globalDiskIOConcurrency)
+
+	globalDiskIOConcurrency = 0
+	if limit := getDiskIOLimit("/mnt/disk1"); limit != nil {
+		t.Fatal("Expected disks to be unlimited by default")
+	}
+
+	globalDiskIOConcurrency = 4
+	limit := getDiskIOLimit("/mnt/disk1")
+	if cap(limit) != 4 {
+		t.Fatalf("Expected a limit of 4 operations, got %d", cap(limit))
+	}
+	if getDiskIOLimit("/mnt/disk1") != limit {
+		t.Error("Expected the same disk to share its limit")
+	}
+	if getDiskIOLimit("/mnt/disk2") == limit {
+		t.Error("Expected other disks to have a limit of their own")
+	}
+}
+
+// Tests multipart uploads and heal complete with a single operation at
+// once per disk.
+func TestIOLimitStorageNoDeadlock(t *testing.T) {
+	defer func(limit int) { globalDiskIOConcurrency = limit }(globalDiskIOConcurrency)
+	globalDiskIOConcurrency = 1
+
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	bucket, object := "bucket", "object"
+	healedDir := ""
+	doneCh := make(chan error, 1)
+	go func() {
+		doneCh <- func() error {
+			if err := obj.MakeBucket(bucket); err != nil {
+				return err
+			}
+			uploadID, err := obj.NewMultipartUpload(bucket, object, nil)
+			if err != nil {
+				return err
+			}
+			var parts []completePart
+			for i, part := range [][]byte{bytes.Repeat([]byte("a"), 5*humanize.MiByte), []byte("b")} {
+				md5Hex, err := obj.PutObjectPart(bucket, object, uploadID, i+1, int64(len(part)), bytes.NewReader(part), "", "")
+				if err != nil {
+					return err
+				}
+				parts = append(parts, completePart{PartNumber: i + 1, ETag: md5Hex})
+			}
+			if _, err = obj.CompleteMultipartUpload(bucket, object, uploadID, parts); err != nil {
+				return err
+			}
+
+			// Heal the object removed from one of its disks.
+			for _, dir := range fsDirs {
+				if _, err = os.Stat(filepath.Join(dir, bucket, object)); err == nil {
+					healedDir = dir
+					break
+				}
+			}
+			if healedDir == "" {
+				return errFileNotFound
+			}
+			if err = os.RemoveAll(filepath.Join(healedDir, bucket, object)); err != nil {
+				return err
+			}
+			return obj.HealObject(bucket, object)
+		}()
+	}()
+
+	select {
+	case err = <-doneCh:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Minute):
+		t.Fatal("Multipart upload and heal did not complete with a limit of 1 operation per disk")
+	}
+	if _, err = os.Stat(filepath.Join(healedDir, bucket, object, xlMetaJSONFile)); err != nil {
+		t.Errorf("Expected the object to be healed, got %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Number of most recent operations latency percentiles are computed
+// over, per disk and kind of operation.
+const diskLatencyWindow = 256
+
+// latencyRing - ring buffer of the most recent latencies, recording
+// overwrites the oldest sample without allocating.
+type latencyRing struct {
+	samples [diskLatencyWindow]time.Duration
+	next    int
+	count   int
+}
+
+// record - adds a sample, replacing the oldest one once full.
+func (r *latencyRing) record(latency time.Duration) {
+	r.samples[r.next] = latency
+	r.next = (r.next + 1) % diskLatencyWindow
+	if r.count < diskLatencyWindow {
+		r.count++
+	}
+}
+
+// byDuration - sorts latencies in increasing order.
+type byDuration []time.Duration
+
+func (d byDuration) Len() int           { return len(d) }
+func (d byDuration) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+func (d byDuration) Less(i, j int) bool { return d[i] < d[j] }
+
+// percentile - nearest rank percentile p of sorted latencies.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (len(sorted)*p + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// percentiles - latency percentiles of samples, zero if no samples
+// were recorded.
+func percentiles(samples []time.Duration) LatencyPercentiles {
+	if len(samples) == 0 {
+		return LatencyPercentiles{}
+	}
+	sort.Sort(byDuration(samples))
+	return LatencyPercentiles{
+		P50: percentile(samples, 50),
+		P90: percentile(samples, 90),
+		P99: percentile(samples, 99),
+	}
+}
+
+// diskLatency - recent latency of reads and writes of a disk.
+type diskLatency struct {
+	mutex *sync.Mutex
+	read  latencyRing
+	write latencyRing
+}
+
+// newDiskLatency - initialize latency tracking without samples.
+func newDiskLatency() *diskLatency {
+	return &diskLatency{mutex: &sync.Mutex{}}
+}
+
+// recordRead - records the latency of a read started at start.
+func (l *diskLatency) recordRead(start time.Time) {
+	latency := time.Since(start)
+	l.mutex.Lock()
+	l.read.record(latency)
+	l.mutex.Unlock()
+}
+
+// recordWrite - records the latency of a write started at start.
+func (l *diskLatency) recordWrite(start time.Time) {
+	latency := time.Since(start)
+	l.mutex.Lock()
+	l.write.record(latency)
+	l.mutex.Unlock()
+}
+
+// stats - read and write latency percentiles of recent operations.
+func (l *diskLatency) stats() (read, write LatencyPercentiles) {
+	var readSamples, writeSamples [diskLatencyWindow]time.Duration
+	l.mutex.Lock()
+	nRead := copy(readSamples[:], l.read.samples[:l.read.count])
+	nWrite := copy(writeSamples[:], l.write.samples[:l.write.count])
+	l.mutex.Unlock()
+	return percentiles(readSamples[:nRead]), percentiles(writeSamples[:nWrite])
+}
+
+// getDisksLatency - returns recent latency of the disks tracking it,
+// offline disks and disks not tracking latency are skipped.
+func getDisksLatency(disks []StorageAPI) []DiskLatency {
+	var disksLatency []DiskLatency
+	for _, disk := range disks {
+		retryDisk, ok := disk.(*retryStorage)
+		if !ok || retryDisk.latency == nil {
+			continue
+		}
+		read, write := retryDisk.latency.stats()
+		disksLatency = append(disksLatency, DiskLatency{
+			Disk:  retryDisk.String(),
+			Read:  read,
+			Write: write,
+		})
+	}
+	return disksLatency
+}
@@ -0,0 +1,132 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+// Tests latency percentiles of the ring buffer.
+func TestLatencyRing(t *testing.T) {
+	latency := newDiskLatency()
+	if read, write := latency.stats(); read != (LatencyPercentiles{}) || write != (LatencyPercentiles{}) {
+		t.Fatalf("Expected no latency without samples, got %v %v", read, write)
+	}
+
+	// 1ms to 100ms, in reverse order.
+	for i := 100; i > 0; i-- {
+		latency.read.record(time.Duration(i) * time.Millisecond)
+	}
+	expected := LatencyPercentiles{
+		P50: 50 * time.Millisecond,
+		P90: 90 * time.Millisecond,
+		P99: 99 * time.Millisecond,
+	}
+	if read, _ := latency.stats(); read != expected {
+		t.Fatalf("Expected %v, got %v", expected, read)
+	}
+
+	// Once full the oldest samples are replaced.
+	for i := 0; i < diskLatencyWindow; i++ {
+		latency.read.record(time.Second)
+	}
+	expected = LatencyPercentiles{P50: time.Second, P90: time.Second, P99: time.Second}
+	if read, _ := latency.stats(); read != expected {
+		t.Fatalf("Expected %v, got %v", expected, read)
+	}
+
+	// Recording does not allocate.
+	start := time.Now()
+	if allocs := testing.AllocsPerRun(100, func() { latency.recordWrite(start) }); allocs != 0 {
+		t.Fatalf("Expected no allocations recording latency, got %v", allocs)
+	}
+}
+
+// delayedStorage - storage whose reads and writes take delay.
+type delayedStorage struct {
+	StorageAPI
+	delay time.Duration
+}
+
+func (d delayedStorage) String() string {
+	return "delayed-disk"
+}
+
+func (d delayedStorage) ReadAll(volume, path string) ([]byte, error) {
+	time.Sleep(d.delay)
+	return []byte("data"), nil
+}
+
+func (d delayedStorage) ReadFile(volume, path string, offset int64, buf []byte) (int64, error) {
+	time.Sleep(d.delay)
+	return int64(len(buf)), nil
+}
+
+func (d delayedStorage) AppendFile(volume, path string, buf []byte) error {
+	return nil
+}
+
+// Tests latency of disks reported by storage info.
+func TestGetDisksLatency(t *testing.T) {
+	delay := 20 * time.Millisecond
+	slowDisk := &retryStorage{
+		remoteStorage: delayedStorage{delay: delay},
+		latency:       newDiskLatency(),
+	}
+	fastDisk := &retryStorage{
+		remoteStorage: delayedStorage{},
+		latency:       newDiskLatency(),
+	}
+	// Disks without latency tracking are skipped.
+	untrackedDisk := &retryStorage{
+		remoteStorage: delayedStorage{delay: delay},
+	}
+	disks := []StorageAPI{slowDisk, nil, fastDisk, untrackedDisk}
+
+	for _, disk := range disks {
+		if disk == nil {
+			continue
+		}
+		for i := 0; i < 5; i++ {
+			if _, err := disk.ReadAll("bucket", "object"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := disk.ReadFile("bucket", "object", 0, make([]byte, 4)); err != nil {
+				t.Fatal(err)
+			}
+			if err := disk.AppendFile("bucket", "object", []byte("data")); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	disksLatency := getDisksLatency(disks)
+	if len(disksLatency) != 2 {
+		t.Fatalf("Expected latency of 2 disks, got %d", len(disksLatency))
+	}
+	slow, fast := disksLatency[0], disksLatency[1]
+	if slow.Read.P50 < delay || slow.Read.P99 < delay {
+		t.Errorf("Expected read latency of at least %s, got %v", delay, slow.Read)
+	}
+	if fast.Read.P99 >= delay {
+		t.Errorf("Expected read latency below %s, got %v", delay, fast.Read)
+	}
+	if slow.Write.P99 >= delay || fast.Write.P99 >= delay {
+		t.Errorf("Expected write latency below %s, got %v %v", delay, slow.Write, fast.Write)
+	}
+}
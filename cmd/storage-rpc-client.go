@@ -316,6 +316,31 @@ func (n *networkStorage) AppendFile(volume, path string, buffer []byte) (err err
 	return nil
 }
 
+// TruncateFile - truncates a file at remote network path.
+func (n *networkStorage) TruncateFile(volume, path string, size int64) (err error) {
+	defer func() {
+		if err == errDiskNotFound {
+			atomic.AddInt32(&n.networkIOErrCount, 1)
+		}
+	}()
+
+	// Take remote disk offline if the total network errors.
+	// are more than maximum allowable IO error limit.
+	if n.networkIOErrCount > maxAllowedNetworkIOError {
+		return errFaultyRemoteDisk
+	}
+
+	reply := AuthRPCReply{}
+	if err = n.rpcClient.Call("Storage.TruncateFileHandler", &TruncateFileArgs{
+		Vol:  volume,
+		Path: path,
+		Size: size,
+	}, &reply); err != nil {
+		return toStorageErr(err)
+	}
+	return nil
+}
+
 // StatFile - get latest Stat information for a file at path.
 func (n *networkStorage) StatFile(volume, path string) (fileInfo FileInfo, err error) {
 	defer func() {
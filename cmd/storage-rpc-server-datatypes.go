@@ -91,6 +91,21 @@ type AppendFileArgs struct {
 	Buffer []byte
 }
 
+// TruncateFileArgs represents truncate file RPC arguments.
+type TruncateFileArgs struct {
+	// Authentication token generated by Login.
+	AuthRPCArgs
+
+	// Name of the volume.
+	Vol string
+
+	// Name of the path.
+	Path string
+
+	// Size to which the file is truncated.
+	Size int64
+}
+
 // StatFileArgs represents stat file RPC arguments.
 type StatFileArgs struct {
 	// Authentication token generated by Login.
@@ -178,6 +178,15 @@ func (s *storageServer) AppendFileHandler(args *AppendFileArgs, reply *AuthRPCRe
 	return s.storage.AppendFile(args.Vol, args.Path, args.Buffer)
 }
 
+// TruncateFileHandler - truncate file handler is rpc wrapper to truncate file.
+func (s *storageServer) TruncateFileHandler(args *TruncateFileArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return s.storage.TruncateFile(args.Vol, args.Path, args.Size)
+}
+
 // DeleteFileHandler - delete file handler is rpc wrapper to delete file.
 func (s *storageServer) DeleteFileHandler(args *DeleteFileArgs, reply *AuthRPCReply) error {
 	if err := args.IsAuthenticated(); err != nil {
@@ -204,7 +213,7 @@ func newRPCServer(srvConfig serverCmdConfig) (servers []*storageServer, err erro
 			// Get the posix path.
 			path := getPath(ep)
 			var storage StorageAPI
-			storage, err = newPosix(path)
+			storage, err = newLocalStorage(path)
 			if err != nil && err != errDiskNotFound {
 				return nil, err
 			}
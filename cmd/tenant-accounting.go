@@ -0,0 +1,273 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/sha256-simd"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TenantStats - request accounting of a single tenant (access key).
+type TenantStats struct {
+	Requests uint64 `json:"requests"`
+	Errors   uint64 `json:"errors"`
+	BytesIn  uint64 `json:"bytesIn"`
+	BytesOut uint64 `json:"bytesOut"`
+}
+
+// add - accumulates stats from other.
+func (s *TenantStats) add(other TenantStats) {
+	s.Requests += other.Requests
+	s.Errors += other.Errors
+	s.BytesIn += other.BytesIn
+	s.BytesOut += other.BytesOut
+}
+
+// Tenant name under which anonymous and unauthenticated requests
+// are accounted.
+const anonymousTenant = "anonymous"
+
+// tenantAccounting - per access key request accounting of this server.
+type tenantAccounting struct {
+	mutex   *sync.RWMutex
+	tenants map[string]*TenantStats
+}
+
+// newTenantAccounting - initialize an empty tenant accounting.
+func newTenantAccounting() *tenantAccounting {
+	return &tenantAccounting{
+		mutex:   &sync.RWMutex{},
+		tenants: make(map[string]*TenantStats),
+	}
+}
+
+// record - accounts a single request of a tenant, once maxTenants
+// are tracked requests of new tenants are accounted as otherTenant.
+func (t *tenantAccounting) record(tenant string, bytesIn, bytesOut uint64, isError bool) {
+	t.mutex.RLock()
+	stats, ok := t.tenants[tenant]
+	t.mutex.RUnlock()
+	if !ok {
+		t.mutex.Lock()
+		if stats, ok = t.tenants[tenant]; !ok && len(t.tenants) >= maxTenants {
+			tenant = otherTenant
+			stats, ok = t.tenants[tenant]
+		}
+		if !ok {
+			stats = &TenantStats{}
+			t.tenants[tenant] = stats
+		}
+		t.mutex.Unlock()
+	}
+	atomic.AddUint64(&stats.Requests, 1)
+	if isError {
+		atomic.AddUint64(&stats.Errors, 1)
+	}
+	atomic.AddUint64(&stats.BytesIn, bytesIn)
+	atomic.AddUint64(&stats.BytesOut, bytesOut)
+}
+
+// Snapshot - returns a copy of current stats of all tenants.
+func (t *tenantAccounting) Snapshot() map[string]TenantStats {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	snapshot := make(map[string]TenantStats, len(t.tenants))
+	for tenant, stats := range t.tenants {
+		snapshot[tenant] = TenantStats{
+			Requests: atomic.LoadUint64(&stats.Requests),
+			Errors:   atomic.LoadUint64(&stats.Errors),
+			BytesIn:  atomic.LoadUint64(&stats.BytesIn),
+			BytesOut: atomic.LoadUint64(&stats.BytesOut),
+		}
+	}
+	return snapshot
+}
+
+// Tenant name under which requests of new access keys are accounted
+// once maxTenants access keys are tracked.
+const otherTenant = "other"
+
+// Maximum number of access keys accounted separately.
+const maxTenants = 1000
+
+// tenantContextKey - context key of the requestTenant of a request.
+type tenantContextKey struct{}
+
+// requestTenant - access key a request was authenticated with, set
+// only once the request signature is verified.
+type requestTenant struct {
+	accessKey atomic.Value
+}
+
+// get - returns the tenant of the request, requests which are not
+// authenticated are accounted as anonymous.
+func (t *requestTenant) get() string {
+	if accessKey, ok := t.accessKey.Load().(string); ok && accessKey != "" {
+		return accessKey
+	}
+	return anonymousTenant
+}
+
+// setRequestTenant - records the access key a request was
+// authenticated with, called by signature verification on success.
+func setRequestTenant(r *http.Request, accessKey string) {
+	if tenant, ok := r.Context().Value(tenantContextKey{}).(*requestTenant); ok {
+		tenant.accessKey.Store(accessKey)
+	}
+}
+
+// tenantLabel - returns the label used for a tenant in metrics, the
+// access key is hashed for privacy unless disabled.
+func tenantLabel(tenant string) string {
+	if !globalTenantLabelHash || tenant == anonymousTenant || tenant == otherTenant {
+		return tenant
+	}
+	sum := sha256.Sum256([]byte(tenant))
+	return hex.EncodeToString(sum[:8])
+}
+
+// countingReadCloser - counts bytes read from the request body.
+type countingReadCloser struct {
+	io.ReadCloser
+	n uint64
+}
+
+func (c *countingReadCloser) Read(p []byte) (n int, err error) {
+	n, err = c.ReadCloser.Read(p)
+	c.n += uint64(n)
+	return n, err
+}
+
+// accountingResponseWriter - counts bytes written to the client and
+// captures the response status.
+type accountingResponseWriter struct {
+	http.ResponseWriter
+	status int
+	n      uint64
+}
+
+func (a *accountingResponseWriter) WriteHeader(status int) {
+	if a.status == 0 {
+		a.status = status
+	}
+	a.ResponseWriter.WriteHeader(status)
+}
+
+func (a *accountingResponseWriter) Write(p []byte) (n int, err error) {
+	if a.status == 0 {
+		a.status = http.StatusOK
+	}
+	n, err = a.ResponseWriter.Write(p)
+	a.n += uint64(n)
+	return n, err
+}
+
+// Flush - implements http.Flusher, used by streaming responses.
+func (a *accountingResponseWriter) Flush() {
+	if flusher, ok := a.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// tenantAccountingHandler - accounts requests, bytes in/out and errors
+// per access key, requests are accounted to the access key only once
+// their signature is verified by the API handlers.
+type tenantAccountingHandler struct {
+	handler http.Handler
+}
+
+func setTenantAccountingHandler(h http.Handler) http.Handler {
+	return tenantAccountingHandler{h}
+}
+
+func (t tenantAccountingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Internal RPC and browser requests are not accounted.
+	if strings.HasPrefix(r.URL.Path, reservedBucket+"/") {
+		t.handler.ServeHTTP(w, r)
+		return
+	}
+
+	tenant := &requestTenant{}
+	r = r.WithContext(context.WithValue(r.Context(), tenantContextKey{}, tenant))
+	if isRequestClientCert(r) {
+		// Client certificate is already verified by the TLS handshake.
+		tenant.accessKey.Store(getClientCertIdentity(r))
+	}
+	var body *countingReadCloser
+	if r.Body != nil {
+		body = &countingReadCloser{ReadCloser: r.Body}
+		r.Body = body
+	}
+	aw := &accountingResponseWriter{ResponseWriter: w}
+	start := time.Now().UTC()
+	t.handler.ServeHTTP(aw, r)
+
+	var bytesIn uint64
+	if body != nil {
+		bytesIn = body.n
+	}
+	globalTenantAccounting.record(tenant.get(), bytesIn, aw.n, aw.status >= http.StatusBadRequest)
+	globalHTTPStats.record(r.Method, aw.status, bytesIn, aw.n, time.Since(start))
+}
+
+// Tenant accounting metrics, each a counter labeled by access key.
+var tenantMetrics = []struct {
+	desc  *prometheus.Desc
+	value func(TenantStats) uint64
+}{
+	{newTenantDesc("minio_tenant_requests_total", "Total number of requests per access key."), func(s TenantStats) uint64 { return s.Requests }},
+	{newTenantDesc("minio_tenant_errors_total", "Total number of failed requests per access key."), func(s TenantStats) uint64 { return s.Errors }},
+	{newTenantDesc("minio_tenant_bytes_received_total", "Total number of bytes received per access key."), func(s TenantStats) uint64 { return s.BytesIn }},
+	{newTenantDesc("minio_tenant_bytes_sent_total", "Total number of bytes sent per access key."), func(s TenantStats) uint64 { return s.BytesOut }},
+}
+
+func newTenantDesc(name, help string) *prometheus.Desc {
+	return prometheus.NewDesc(name, help, []string{"access_key"}, nil)
+}
+
+// tenantCollector - collects tenant accounting returned by snapshot as
+// Prometheus counters labeled by access key.
+type tenantCollector struct {
+	snapshot func() map[string]TenantStats
+}
+
+// Describe - implements prometheus.Collector.
+func (c tenantCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range tenantMetrics {
+		ch <- metric.desc
+	}
+}
+
+// Collect - implements prometheus.Collector.
+func (c tenantCollector) Collect(ch chan<- prometheus.Metric) {
+	tenants := c.snapshot()
+	for _, metric := range tenantMetrics {
+		for tenant, stats := range tenants {
+			ch <- prometheus.MustNewConstMetric(metric.desc, prometheus.CounterValue,
+				float64(metric.value(stats)), tenantLabel(tenant))
+		}
+	}
+}
@@ -0,0 +1,213 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	router "github.com/gorilla/mux"
+)
+
+// Tests accounting of requests per access key.
+func TestTenantAccountingHandler(t *testing.T) {
+	resetTestGlobals()
+	// initialize NSLock.
+	initNSLock(false)
+
+	rootPath, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	handler := setTenantAccountingHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if getRequestAuthType(r) != authTypeAnonymous && checkRequestAuthType(r, "bucket", "", "us-east-1") != ErrNone {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if _, err := ioutil.ReadAll(r.Body); err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasSuffix(r.URL.Path, "missing") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("hello"))
+	}))
+
+	cred := serverConfig.GetCredential()
+	newRequest := func(signer string, urlStr string, body []byte) *http.Request {
+		var req *http.Request
+		switch signer {
+		case "v4":
+			req, err = newTestSignedRequestV4("PUT", urlStr, int64(len(body)), bytes.NewReader(body), cred.AccessKey, cred.SecretKey)
+		case "v2":
+			req, err = newTestSignedRequestV2("PUT", urlStr, int64(len(body)), bytes.NewReader(body), cred.AccessKey, cred.SecretKey)
+		case "unknown":
+			req, err = newTestSignedRequestV4("PUT", urlStr, int64(len(body)), bytes.NewReader(body), "unknownaccesskey", cred.SecretKey)
+		case "forged":
+			req, err = newTestSignedRequestV4("PUT", urlStr, int64(len(body)), bytes.NewReader(body), cred.AccessKey, "forgedsecretkey")
+		default:
+			req, err = newTestRequest("PUT", urlStr, int64(len(body)), bytes.NewReader(body))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		return req
+	}
+
+	requests := []*http.Request{
+		newRequest("v4", "http://127.0.0.1:9000/bucket/object", []byte("abcd")),
+		newRequest("v2", "http://127.0.0.1:9000/bucket/object", []byte("ab")),
+		newRequest("v4", "http://127.0.0.1:9000/bucket/missing", nil),
+		newRequest("anonymous", "http://127.0.0.1:9000/bucket/object", []byte("a")),
+		newRequest("unknown", "http://127.0.0.1:9000/bucket/object", nil),
+		// Valid access key with a wrong signature is not accounted to it.
+		newRequest("forged", "http://127.0.0.1:9000/bucket/object", nil),
+		// Internal requests are not accounted.
+		newRequest("v4", "http://127.0.0.1:9000/minio/admin", nil),
+	}
+	for _, req := range requests {
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	expected := map[string]TenantStats{
+		cred.AccessKey:  {Requests: 3, Errors: 1, BytesIn: 6, BytesOut: 10},
+		anonymousTenant: {Requests: 3, Errors: 2, BytesIn: 1, BytesOut: 5},
+	}
+	tenants := globalTenantAccounting.Snapshot()
+	if len(tenants) != len(expected) {
+		t.Fatalf("Expected %d tenants, got %v", len(expected), tenants)
+	}
+	for tenant, stats := range expected {
+		if tenants[tenant] != stats {
+			t.Errorf("Tenant %s: expected %#v, got %#v", tenant, stats, tenants[tenant])
+		}
+	}
+}
+
+// Tests that the number of accounted access keys is bounded.
+func TestTenantAccountingBound(t *testing.T) {
+	accounting := newTenantAccounting()
+	for i := 0; i < maxTenants+10; i++ {
+		accounting.record(fmt.Sprintf("accesskey%d", i), 1, 1, false)
+	}
+	// Tenants already accounted keep being accounted separately.
+	accounting.record("accesskey0", 1, 1, false)
+
+	tenants := accounting.Snapshot()
+	if len(tenants) != maxTenants+1 {
+		t.Fatalf("Expected %d tenants, got %d", maxTenants+1, len(tenants))
+	}
+	if stats := tenants[otherTenant]; stats.Requests != 10 {
+		t.Errorf("Expected 10 requests accounted as %s, got %d", otherTenant, stats.Requests)
+	}
+	if stats := tenants["accesskey0"]; stats.Requests != 2 {
+		t.Errorf("Expected 2 requests of accesskey0, got %d", stats.Requests)
+	}
+}
+
+// Tests Prometheus exposition of tenant accounting.
+func TestTenantMetrics(t *testing.T) {
+	defer func(hash bool) { globalTenantLabelHash = hash }(globalTenantLabelHash)
+
+	tenants := map[string]TenantStats{
+		"minio":         {Requests: 5, Errors: 2, BytesIn: 10, BytesOut: 20},
+		anonymousTenant: {Requests: 1},
+	}
+
+	collector := tenantCollector{func() map[string]TenantStats { return tenants }}
+	globalTenantLabelHash = false
+	metrics := gatherMetrics(t, collector)
+	for _, line := range []string{
+		"# TYPE minio_tenant_requests_total counter",
+		`minio_tenant_requests_total{access_key="minio"} 5`,
+		`minio_tenant_errors_total{access_key="minio"} 2`,
+		`minio_tenant_bytes_received_total{access_key="minio"} 10`,
+		`minio_tenant_bytes_sent_total{access_key="minio"} 20`,
+		`minio_tenant_requests_total{access_key="anonymous"} 1`,
+	} {
+		if !strings.Contains(metrics, line+"\n") {
+			t.Errorf("Expected %q in metrics output:\n%s", line, metrics)
+		}
+	}
+
+	globalTenantLabelHash = true
+	metrics = gatherMetrics(t, collector)
+	if strings.Contains(metrics, `access_key="minio"`) {
+		t.Errorf("Expected access key to be hashed:\n%s", metrics)
+	}
+	if !strings.Contains(metrics, `minio_tenant_requests_total{access_key="`+tenantLabel("minio")+`"} 5`) {
+		t.Errorf("Expected hashed access key label:\n%s", metrics)
+	}
+}
+
+// Tests tenant accounting management REST API.
+func TestTenantAccountingAdminHandler(t *testing.T) {
+	resetTestGlobals()
+	// initialize NSLock.
+	initNSLock(false)
+
+	rootPath, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	eps, err := parseStorageEndpoints([]string{"http://localhost"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	globalTenantAccounting.record("minio", 10, 20, true)
+
+	adminRouter := router.NewRouter()
+	registerAdminRouter(adminRouter)
+
+	req, err := newTestRequest("GET", "/?accounting", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(minioAdminOpHeader, "tenants")
+	cred := serverConfig.GetCredential()
+	if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	adminRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, rec.Code)
+	}
+
+	var tenants map[string]TenantStats
+	if err = json.NewDecoder(rec.Body).Decode(&tenants); err != nil {
+		t.Fatal(err)
+	}
+	expected := TenantStats{Requests: 1, Errors: 1, BytesIn: 10, BytesOut: 20}
+	if tenants["minio"] != expected {
+		t.Errorf("Expected %#v, got %#v", expected, tenants["minio"])
+	}
+}
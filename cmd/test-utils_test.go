@@ -66,7 +66,7 @@ func init() {
 	color.Output = ioutil.Discard
 
 	// Enable caching.
-	setMaxMemory()
+	setMaxMemory(0)
 }
 
 func prepareFS() (ObjectLayer, string, error) {
@@ -329,6 +329,22 @@ func StartTestServer(t TestErrHandler, instanceType string) TestServer {
 	return testServer
 }
 
+// Starts the test server along with the admin peers of its disks, returns
+// the TestServer instance and a function stopping it. The address of the
+// server and the admin peers are global, they are restored once stopped.
+func startTestServerWithPeers(t TestErrHandler, instanceType string) (TestServer, func()) {
+	addr, host, port := globalMinioAddr, globalMinioHost, globalMinioPort
+	peers := globalAdminPeers
+
+	testServer := StartTestServer(t, instanceType)
+	initGlobalAdminPeers(testServer.Disks)
+	return testServer, func() {
+		testServer.Stop()
+		globalAdminPeers = peers
+		globalMinioAddr, globalMinioHost, globalMinioPort = addr, host, port
+	}
+}
+
 // Initializes storage RPC endpoints.
 // The object Layer will be a temp back used for testing purpose.
 func initTestStorageRPCEndPoint(srvCmdConfig serverCmdConfig) http.Handler {
@@ -473,6 +489,19 @@ func resetGlobalEventnotify() {
 	globalEventNotifier = nil
 }
 
+// reset global avoided disks.
+func resetGlobalAvoidedDisks() {
+	globalAvoidedDisks = newAvoidedDisks()
+}
+
+// reset global tenant accounting.
+func resetGlobalTenantAccounting() {
+	globalTenantAccounting = newTenantAccounting()
+	globalHTTPStats = newHTTPStats()
+	globalBucketUsage = newBucketUsage()
+	globalBucketCache = newBucketInfoCache()
+}
+
 // Resets all the globals used modified in tests.
 // Resetting ensures that the changes made to globals by one test doesn't affect others.
 func resetTestGlobals() {
@@ -486,6 +515,10 @@ func resetTestGlobals() {
 	resetGlobalNSLock()
 	// Reset global event notifier.
 	resetGlobalEventnotify()
+	// Reset global avoided disks.
+	resetGlobalAvoidedDisks()
+	// Reset global tenant accounting.
+	resetGlobalTenantAccounting()
 }
 
 // Configure the server for the test run.
@@ -1397,11 +1430,36 @@ func getGetObjectURL(endPoint, bucketName, objectName string) string {
 	return makeTestTargetURL(endPoint, bucketName, objectName, url.Values{})
 }
 
+// return URL for selecting the content of an object.
+func getSelectObjectContentURL(endPoint, bucketName, objectName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("select", "")
+	queryValue.Set("select-type", "2")
+	return makeTestTargetURL(endPoint, bucketName, objectName, queryValue)
+}
+
 // return URL for deleting the object from the bucket.
 func getDeleteObjectURL(endPoint, bucketName, objectName string) string {
 	return makeTestTargetURL(endPoint, bucketName, objectName, url.Values{})
 }
 
+// return URL for truncating the object.
+func getTruncateObjectURL(endPoint, bucketName, objectName, length string) string {
+	queryValue := url.Values{}
+	queryValue.Set("truncate", length)
+	return makeTestTargetURL(endPoint, bucketName, objectName, queryValue)
+}
+
+// return URL for creating an object under a key assigned by the server.
+func getAssignKeyURL(endPoint, bucketName, prefix string) string {
+	queryValue := url.Values{}
+	queryValue.Set("assign-key", "")
+	if prefix != "" {
+		queryValue.Set("prefix", prefix)
+	}
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
 // return URL for deleting multiple objects from a bucket.
 func getMultiDeleteObjectURL(endPoint, bucketName string) string {
 	queryValue := url.Values{}
@@ -1625,7 +1683,7 @@ func initObjectLayer(endpoints []*url.URL) (ObjectLayer, []StorageAPI, error) {
 		return nil, nil, err
 	}
 
-	objLayer, err := newObjectLayer(formattedDisks)
+	objLayer, err := newObjectLayer(formattedDisks, globalStorageClassParity)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1635,6 +1693,11 @@ func initObjectLayer(endpoints []*url.URL) (ObjectLayer, []StorageAPI, error) {
 	if xl, ok := objLayer.(*xlObjects); ok {
 		xl.objCacheEnabled = false
 	}
+	if s, ok := objLayer.(*xlSets); ok {
+		for _, xl := range s.sets {
+			xl.objCacheEnabled = false
+		}
+	}
 
 	// Success.
 	return objLayer, formattedDisks, nil
@@ -2032,15 +2095,36 @@ func registerBucketLevelFunc(bucket *router.Router, api objectAPIHandlers, apiFu
 		case "GetObject":
 			// Register GetObject handler.
 			bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.GetObjectHandler)
+		case "SelectObjectContent":
+			// Register SelectObjectContent handler.
+			bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(api.SelectObjectContentHandler).Queries("select", "", "select-type", "2")
 		case "PutObject":
 			// Register PutObject handler.
 			bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(api.PutObjectHandler)
 		case "DeleteObject":
 			// Register Delete Object handler.
 			bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(api.DeleteObjectHandler)
+		case "TruncateObject":
+			// Register Truncate Object handler.
+			bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(api.TruncateObjectHandler).Queries("truncate", "{length:.*}")
+		case "PutObjectAssignKey":
+			// Register Put Object Assign Key handler.
+			bucket.Methods("POST").HandlerFunc(api.PutObjectAssignKeyHandler).Queries("assign-key", "")
+		case "CopyObjectPart":
+			// Register CopyObjectPart handler.
+			bucket.Methods("PUT").Path("/{object:.+}").HeadersRegexp("X-Amz-Copy-Source", ".*?(\\/|%2F).*?").HandlerFunc(api.CopyObjectPartHandler).Queries("partNumber", "{partNumber:[0-9]+}", "uploadId", "{uploadId:.*}")
 		case "CopyObject":
 			// Register Copy Object  handler.
 			bucket.Methods("PUT").Path("/{object:.+}").HeadersRegexp("X-Amz-Copy-Source", ".*?(\\/|%2F).*?").HandlerFunc(api.CopyObjectHandler)
+		case "GetObjectTagging":
+			// Register GetObjectTagging handler.
+			bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.GetObjectTaggingHandler).Queries("tagging", "")
+		case "PutObjectTagging":
+			// Register PutObjectTagging handler.
+			bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(api.PutObjectTaggingHandler).Queries("tagging", "")
+		case "DeleteObjectTagging":
+			// Register DeleteObjectTagging handler.
+			bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(api.DeleteObjectTaggingHandler).Queries("tagging", "")
 		case "PutBucketPolicy":
 			// Register PutBucket Policy handler.
 			bucket.Methods("PUT").HandlerFunc(api.PutBucketPolicyHandler).Queries("policy", "")
@@ -2086,12 +2170,24 @@ func registerBucketLevelFunc(bucket *router.Router, api objectAPIHandlers, apiFu
 		case "ListenBucketNotification":
 			// Register ListenBucketNotification Handler.
 			bucket.Methods("GET").HandlerFunc(api.ListenBucketNotificationHandler).Queries("events", "{events:.*}")
+		case "ListObjectsV2":
+			// Register ListObjectsV2 Handler.
+			bucket.Methods("GET").HandlerFunc(api.ListObjectsV2Handler).Queries("list-type", "2")
+		case "ListObjectsV1":
+			// Register ListObjectsV1 Handler.
+			bucket.Methods("GET").HandlerFunc(api.ListObjectsV1Handler)
 		}
 	}
 }
 
 // registerAPIFunctions helper function to add API functions identified by name to the routers.
 func registerAPIFunctions(muxRouter *router.Router, objLayer ObjectLayer, apiFunctions ...string) {
+	// All object storage operations are registered as HTTP handlers on `objectAPIHandlers`.
+	// When the handlers get a HTTP request they use the underlyting ObjectLayer to perform operations.
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = objLayer
+	globalObjLayerMutex.Unlock()
+
 	if len(apiFunctions) == 0 {
 		// Register all api endpoints by default.
 		registerAPIRouter(muxRouter)
@@ -2102,12 +2198,6 @@ func registerAPIFunctions(muxRouter *router.Router, objLayer ObjectLayer, apiFun
 	// Bucket router.
 	bucketRouter := apiRouter.PathPrefix("/{bucket}").Subrouter()
 
-	// All object storage operations are registered as HTTP handlers on `objectAPIHandlers`.
-	// When the handlers get a HTTP request they use the underlyting ObjectLayer to perform operations.
-	globalObjLayerMutex.Lock()
-	globalObjectAPI = objLayer
-	globalObjLayerMutex.Unlock()
-
 	api := objectAPIHandlers{
 		ObjectAPI: newObjectLayerFn,
 	}
@@ -2125,12 +2215,9 @@ func initTestAPIEndPoints(objLayer ObjectLayer, apiFunctions []string) http.Hand
 	// initialize a new mux router.
 	// goriilla/mux is the library used to register all the routes and handle them.
 	muxRouter := router.NewRouter()
-	if len(apiFunctions) > 0 {
-		// Iterate the list of API functions requested for and register them in mux HTTP handler.
-		registerAPIFunctions(muxRouter, objLayer, apiFunctions...)
-		return muxRouter
-	}
-	registerAPIRouter(muxRouter)
+	// Iterate the list of API functions requested for and register them in mux HTTP handler,
+	// all of them if none are requested.
+	registerAPIFunctions(muxRouter, objLayer, apiFunctions...)
 	return muxRouter
 }
 
@@ -42,6 +42,9 @@ var errDataTooSmall = errors.New("Object size smaller than expected")
 // errServerNotInitialized - server not initialized.
 var errServerNotInitialized = errors.New("Server not initialized, please try again")
 
+// errServerReadOnly - server rejects mutating requests.
+var errServerReadOnly = errors.New("Server is in read-only mode")
+
 // errServerVersionMismatch - server versions do not match.
 var errServerVersionMismatch = errors.New("Server versions do not match")
 
@@ -17,12 +17,14 @@
 package cmd
 
 import (
+	"crypto/md5"
 	"encoding/base64"
 	"encoding/xml"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"strings"
 
 	"encoding/json"
@@ -129,6 +131,30 @@ func checkDuplicateEndpoints(endpoints []*url.URL) error {
 	return checkDuplicateStrings(strs)
 }
 
+// checkOverlappingEndpoints - returns an error naming both paths if the
+// path of a local endpoint is inside the path of another, such disks
+// would overwrite each other's data. Remote endpoints are not checked.
+func checkOverlappingEndpoints(endpoints []*url.URL) error {
+	var paths []string
+	for _, ep := range endpoints {
+		if isLocalStorage(ep) {
+			paths = append(paths, filepath.Clean(getPath(ep)))
+		}
+	}
+	for i, parent := range paths {
+		prefix := parent
+		if !strings.HasSuffix(prefix, string(filepath.Separator)) {
+			prefix += string(filepath.Separator)
+		}
+		for j, child := range paths {
+			if i != j && strings.HasPrefix(child, prefix) {
+				return fmt.Errorf("Disk path %s is inside disk path %s, disks cannot overlap", child, parent)
+			}
+		}
+	}
+	return nil
+}
+
 // Find local node through the command line arguments. Returns in `host:port` format.
 func getLocalAddress(srvCmdConfig serverCmdConfig) string {
 	if !globalIsDistXL {
@@ -156,8 +182,16 @@ func xmlDecoder(body io.Reader, v interface{}, size int64) error {
 }
 
 // checkValidMD5 - verify if valid md5, returns md5 in bytes.
-func checkValidMD5(md5 string) ([]byte, error) {
-	return base64.StdEncoding.DecodeString(strings.TrimSpace(md5))
+func checkValidMD5(md5Str string) ([]byte, error) {
+	md5Bytes, err := base64.StdEncoding.DecodeString(strings.TrimSpace(md5Str))
+	if err != nil {
+		return nil, err
+	}
+	// Anything other than a 128 bit digest can never match the content.
+	if len(md5Bytes) != 0 && len(md5Bytes) != md5.Size {
+		return nil, errInvalidArgument
+	}
+	return md5Bytes, nil
 }
 
 /// http://docs.aws.amazon.com/AmazonS3/latest/dev/UploadingObjects.html
@@ -168,6 +202,10 @@ const (
 	minPartSize = 5 * humanize.MiByte
 	// maximum Part ID for multipart upload is 10000 (Acceptable values range from 1 to 10000 inclusive)
 	maxPartID = 10000
+	// default maximum size of user metadata per object is 2KiB
+	defaultMaxUserMetadataSize = 2 * humanize.KiByte
+	// default size of the parts of server-side copies is 64MiB
+	defaultCopyPartSize = 64 * humanize.MiByte
 )
 
 // isMaxObjectSize - verify if max object size
@@ -177,12 +215,60 @@ func isMaxObjectSize(size int64) bool {
 
 // Check if part size is more than or equal to minimum allowed size.
 func isMinAllowedPartSize(size int64) bool {
-	return size >= minPartSize
+	return size >= globalMinPartSize
+}
+
+// isMaxPartSize - verify if part size is greater than the maximum allowed size.
+func isMaxPartSize(size int64) bool {
+	return size > globalMaxPartSize
 }
 
 // isMaxPartNumber - Check if part ID is greater than the maximum allowed ID.
 func isMaxPartID(partID int) bool {
-	return partID > maxPartID
+	return partID > globalMaxPartID
+}
+
+// setMultipartLimits - validates and sets the maximum number of parts
+// per upload and the allowed part sizes. Parts are limited to at most
+// maxPartID parts of at most maxObjectSize each, as allowed by S3.
+func setMultipartLimits(maxParts int, minPartSizeStr, maxPartSizeStr string) error {
+	if maxParts < 1 || maxParts > maxPartID {
+		return fmt.Errorf("Maximum number of parts should be between 1 and %d", maxPartID)
+	}
+	minSize, err := humanize.ParseBytes(minPartSizeStr)
+	if err != nil {
+		return fmt.Errorf("Invalid minimum part size %s, %s", minPartSizeStr, err)
+	}
+	maxSize, err := humanize.ParseBytes(maxPartSizeStr)
+	if err != nil {
+		return fmt.Errorf("Invalid maximum part size %s, %s", maxPartSizeStr, err)
+	}
+	if maxSize > maxObjectSize {
+		return fmt.Errorf("Maximum part size cannot be more than %s", humanize.IBytes(maxObjectSize))
+	}
+	if minSize == 0 || minSize > maxSize {
+		return fmt.Errorf("Minimum part size should be between 1 byte and the maximum part size %s", humanize.IBytes(maxSize))
+	}
+	globalMaxPartID = maxParts
+	globalMinPartSize = int64(minSize)
+	globalMaxPartSize = int64(maxSize)
+	return nil
+}
+
+// setCopyPartSize - validates and sets the size of the parts of
+// server-side copies of large objects, which should be allowed by the
+// configured multipart limits.
+func setCopyPartSize(copyPartSizeStr string) error {
+	size, err := humanize.ParseBytes(copyPartSizeStr)
+	if err != nil {
+		return fmt.Errorf("Invalid copy part size %s, %s", copyPartSizeStr, err)
+	}
+	if int64(size) < globalMinPartSize || int64(size) > globalMaxPartSize {
+		return fmt.Errorf("Copy part size should be between the minimum part size %s and the maximum part size %s",
+			humanize.IBytes(uint64(globalMinPartSize)), humanize.IBytes(uint64(globalMaxPartSize)))
+	}
+	globalCopyPartSize = int64(size)
+	return nil
 }
 
 func contains(stringList []string, element string) bool {
@@ -101,6 +101,40 @@ func TestCheckDuplicates(t *testing.T) {
 	}
 }
 
+// Tests overlapping paths of local endpoints are detected.
+func TestCheckOverlappingEndpoints(t *testing.T) {
+	testCases := []struct {
+		endpoints  []string
+		shouldPass bool
+	}{
+		// Test 1 - sibling paths.
+		{[]string{"/mnt/d1", "/mnt/d2", "/mnt/d10", "/mnt/d1-sub"}, true},
+		// Test 2 - nested path.
+		{[]string{"/mnt/d1", "/mnt/d2", "/mnt/d1/sub", "/mnt/d3"}, false},
+		// Test 3 - nested path given first, trailing separators.
+		{[]string{"/mnt/d1/sub/", "/mnt/d1/", "/mnt/d2", "/mnt/d3"}, false},
+		// Test 4 - nested path of a remote endpoint is another disk.
+		{[]string{"/mnt/d1", "http://192.0.2.1:9000/mnt/d1/sub"}, true},
+	}
+	for i, testCase := range testCases {
+		var endpoints []*url.URL
+		for _, ep := range testCase.endpoints {
+			u, err := url.Parse(ep)
+			if err != nil {
+				t.Fatal(err)
+			}
+			endpoints = append(endpoints, u)
+		}
+		err := checkOverlappingEndpoints(endpoints)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Expected to pass, got %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected overlapping paths to be detected", i+1)
+		}
+	}
+}
+
 // Tests maximum object size.
 func TestMaxObjectSize(t *testing.T) {
 	sizes := []struct {
@@ -220,6 +254,88 @@ func TestMaxPartID(t *testing.T) {
 	}
 }
 
+// Tests validation of configured multipart limits.
+func TestSetMultipartLimits(t *testing.T) {
+	defer func(maxParts int, minSize, maxSize int64) {
+		globalMaxPartID, globalMinPartSize, globalMaxPartSize = maxParts, minSize, maxSize
+	}(globalMaxPartID, globalMinPartSize, globalMaxPartSize)
+
+	testCases := []struct {
+		maxParts    int
+		minPartSize string
+		maxPartSize string
+		shouldPass  bool
+	}{
+		// Test - 1 defaults as in S3.
+		{maxPartID, "5MiB", "5GiB", true},
+		// Test - 2 lower limits.
+		{100, "16MiB", "1GiB", true},
+		// Test - 3, 4 maximum number of parts out of range.
+		{0, "5MiB", "5GiB", false},
+		{maxPartID + 1, "5MiB", "5GiB", false},
+		// Test - 5, 6 invalid sizes.
+		{maxPartID, "5 apples", "5GiB", false},
+		{maxPartID, "5MiB", "", false},
+		// Test - 7 part larger than a single PUT.
+		{maxPartID, "5MiB", "6GiB", false},
+		// Test - 8, 9 invalid minimum part size.
+		{maxPartID, "2GiB", "1GiB", false},
+		{maxPartID, "0", "1GiB", false},
+	}
+
+	for i, testCase := range testCases {
+		err := setMultipartLimits(testCase.maxParts, testCase.minPartSize, testCase.maxPartSize)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Unexpected error %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected to fail", i+1)
+		}
+	}
+
+	if err := setMultipartLimits(100, "16MiB", "1GiB"); err != nil {
+		t.Fatal(err)
+	}
+	if !isMaxPartID(101) || isMinAllowedPartSize(16*1024*1024-1) || !isMaxPartSize(1024*1024*1024+1) {
+		t.Errorf("Configured multipart limits not applied")
+	}
+}
+
+// Tests validation of the configured copy part size.
+func TestSetCopyPartSize(t *testing.T) {
+	defer func(copyPartSize int64) { globalCopyPartSize = copyPartSize }(globalCopyPartSize)
+
+	testCases := []struct {
+		copyPartSize string
+		shouldPass   bool
+	}{
+		// Test - 1, 2 valid part sizes.
+		{"64MiB", true},
+		{"5MiB", true},
+		// Test - 3 invalid size.
+		{"64 apples", false},
+		// Test - 4, 5 sizes out of the multipart limits.
+		{"1MiB", false},
+		{"6GiB", false},
+	}
+	for i, testCase := range testCases {
+		err := setCopyPartSize(testCase.copyPartSize)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Unexpected error %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected to fail", i+1)
+		}
+	}
+
+	if err := setCopyPartSize("16MiB"); err != nil {
+		t.Fatal(err)
+	}
+	if globalCopyPartSize != 16*1024*1024 {
+		t.Errorf("Configured copy part size not applied")
+	}
+}
+
 // Tests extracting bucket and objectname from various types of URL paths.
 func TestURL2BucketObjectName(t *testing.T) {
 	testCases := []struct {
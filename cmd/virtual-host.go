@@ -0,0 +1,74 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// parseDomainName - validates the domain of virtual-hosted-style
+// requests set by MINIO_DOMAIN, empty disables them.
+func parseDomainName(domain string) (string, error) {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	if domain == "" {
+		return "", nil
+	}
+	if strings.ContainsAny(domain, ":/") || strings.HasPrefix(domain, ".") || strings.Contains(domain, "..") {
+		return "", fmt.Errorf("Invalid domain %s, expected a host name such as example.com", domain)
+	}
+	return domain, nil
+}
+
+// getVirtualHostBucket - returns the bucket addressed by the host of
+// a virtual-hosted-style request, such as bucket.example.com, empty
+// for path-style requests.
+func getVirtualHostBucket(r *http.Request) string {
+	if globalDomainName == "" {
+		return ""
+	}
+	host := r.Host
+	if r.URL.IsAbs() {
+		host = r.URL.Host
+	}
+	// Slice off any port information, the same as the router.
+	if i := strings.Index(host, ":"); i != -1 {
+		host = host[:i]
+	}
+	if !strings.HasSuffix(host, "."+globalDomainName) {
+		return ""
+	}
+	return strings.TrimSuffix(host, "."+globalDomainName)
+}
+
+// pathStyleURL - returns the URL of r with the bucket in its path, as
+// for path-style requests, such that the resource can be derived from
+// the path for both styles.
+func pathStyleURL(r *http.Request) *url.URL {
+	bucket := getVirtualHostBucket(r)
+	if bucket == "" {
+		return r.URL
+	}
+	u := *r.URL
+	u.Path = slashSeparator + bucket + r.URL.Path
+	if u.RawPath != "" {
+		u.RawPath = slashSeparator + bucket + r.URL.RawPath
+	}
+	return &u
+}
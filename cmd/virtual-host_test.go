@@ -0,0 +1,181 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Tests validation of the domain of `MINIO_DOMAIN`.
+func TestParseDomainName(t *testing.T) {
+	testCases := []struct {
+		domain         string
+		expectedDomain string
+		shouldPass     bool
+	}{
+		// Test case - 1.
+		// Virtual-hosted-style requests disabled.
+		{"", "", true},
+		// Test case - 2.
+		{"example.com", "example.com", true},
+		// Test case - 3.
+		// Trailing dot and case are ignored.
+		{"S3.Example.com.", "s3.example.com", true},
+		// Test case - 4.
+		// Port is not part of the domain.
+		{"example.com:9000", "", false},
+		// Test case - 5.
+		{"http://example.com", "", false},
+		// Test case - 6.
+		{".example.com", "", false},
+	}
+	for i, testCase := range testCases {
+		domain, err := parseDomainName(testCase.domain)
+		if testCase.shouldPass && (err != nil || domain != testCase.expectedDomain) {
+			t.Errorf("Test %d: Expected %s, got %s, %v", i+1, testCase.expectedDomain, domain, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected %s to be invalid", i+1, testCase.domain)
+		}
+	}
+}
+
+// Tests resolution of the bucket of virtual-hosted-style requests.
+func TestGetVirtualHostBucket(t *testing.T) {
+	defer func() { globalDomainName = "" }()
+
+	testCases := []struct {
+		domain       string
+		urlStr       string
+		bucket       string
+		pathStyleURL string
+	}{
+		// Test case - 1.
+		// Virtual-hosted-style requests disabled.
+		{"", "http://bucket.example.com/object", "", "/object"},
+		// Test case - 2.
+		{"example.com", "http://bucket.example.com/object", "bucket", "/bucket/object"},
+		// Test case - 3.
+		// Port is ignored.
+		{"example.com", "http://bucket.example.com:9000/dir/object", "bucket", "/bucket/dir/object"},
+		// Test case - 4.
+		// Dots in bucket names.
+		{"example.com", "http://my.bucket.example.com/", "my.bucket", "/my.bucket/"},
+		// Test case - 5.
+		// Path-style request to the domain itself.
+		{"example.com", "http://example.com/bucket/object", "", "/bucket/object"},
+		// Test case - 6.
+		// Path-style request to another host.
+		{"example.com", "http://localhost:9000/bucket/object", "", "/bucket/object"},
+		// Test case - 7.
+		// Host only ending with the domain.
+		{"example.com", "http://bucketexample.com/object", "", "/object"},
+	}
+	for i, testCase := range testCases {
+		globalDomainName = testCase.domain
+		req, err := http.NewRequest("GET", testCase.urlStr, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bucket := getVirtualHostBucket(req); bucket != testCase.bucket {
+			t.Errorf("Test %d: Expected bucket `%s`, got `%s`", i+1, testCase.bucket, bucket)
+		}
+		if u := pathStyleURL(req); u.Path != testCase.pathStyleURL {
+			t.Errorf("Test %d: Expected path %s, got %s", i+1, testCase.pathStyleURL, u.Path)
+		}
+	}
+}
+
+// Wrapper for calling virtual-hosted-style request tests for both XL multiple disks and single node setup.
+func TestVirtualHostRequests(t *testing.T) {
+	globalDomainName = "example.com"
+	defer func() { globalDomainName = "" }()
+	ExecObjectLayerAPITest(t, testVirtualHostRequests, nil)
+}
+
+func testVirtualHostRequests(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	data := []byte("hello")
+
+	// do - signs and sends a request, returns the recorded response.
+	do := func(method, urlStr string, body []byte) *httptest.ResponseRecorder {
+		req, err := newTestSignedRequestV4(method, urlStr, int64(len(body)), bytes.NewReader(body),
+			credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s %s: <ERROR> %v", instanceType, method, urlStr, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Object uploaded with virtual-hosted-style is read with both styles.
+	rec := do("PUT", "http://"+bucketName+".example.com/object", data)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected virtual-hosted-style PutObject to succeed, got %d: %s", instanceType, rec.Code, rec.Body)
+	}
+	for _, urlStr := range []string{
+		"http://" + bucketName + ".example.com/object",
+		"http://" + bucketName + ".example.com:9000/object",
+		"http://example.com/" + bucketName + "/object",
+		"http://localhost:9000/" + bucketName + "/object",
+	} {
+		rec = do("GET", urlStr, nil)
+		if rec.Code != http.StatusOK || !bytes.Equal(rec.Body.Bytes(), data) {
+			t.Errorf("%s: Expected GET %s to return the object, got %d: %s", instanceType, urlStr, rec.Code, rec.Body)
+		}
+	}
+
+	// Objects are listed with virtual-hosted-style.
+	rec = do("GET", "http://"+bucketName+".example.com/", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected virtual-hosted-style ListObjects to succeed, got %d: %s", instanceType, rec.Code, rec.Body)
+	}
+	if !strings.Contains(rec.Body.String(), "<Key>object</Key>") {
+		t.Errorf("%s: Expected object to be listed, got %s", instanceType, rec.Body)
+	}
+
+	// Buckets with dots are created with virtual-hosted-style.
+	rec = do("PUT", "http://my.new-bucket.example.com/", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected virtual-hosted-style PutBucket to succeed, got %d: %s", instanceType, rec.Code, rec.Body)
+	}
+	if _, err := obj.GetBucketInfo("my.new-bucket"); err != nil {
+		t.Errorf("%s: Expected bucket my.new-bucket to be created, got %v", instanceType, err)
+	}
+
+	// Hosts which are not valid bucket names are rejected.
+	for _, host := range []string{"my..bucket.example.com", "192.168.1.1.example.com", "ab.example.com"} {
+		rec = do("GET", "http://"+host+"/", nil)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: Expected GET on %s to fail with %d, got %d", instanceType, host, http.StatusBadRequest, rec.Code)
+			continue
+		}
+		var apiErr APIErrorResponse
+		if err := xml.Unmarshal(rec.Body.Bytes(), &apiErr); err != nil {
+			t.Fatalf("%s: Failed to parse error response: <ERROR> %v", instanceType, err)
+		}
+		if apiErr.Code != getAPIError(ErrInvalidBucketName).Code {
+			t.Errorf("%s: Expected error code %s for %s, got %s", instanceType, getAPIError(ErrInvalidBucketName).Code, host, apiErr.Code)
+		}
+	}
+}
@@ -121,10 +121,13 @@ func (web *webAPIHandlers) MakeBucket(r *http.Request, args *MakeBucketArgs, rep
 	if !isHTTPRequestValid(r) {
 		return toJSONError(errAuthentication)
 	}
-	bucketLock := globalNSMutex.NewNSLock(args.BucketName, "")
-	bucketLock.Lock()
-	defer bucketLock.Unlock()
-	if err := objectAPI.MakeBucket(args.BucketName); err != nil {
+	if globalIsReadOnly {
+		return toJSONError(errServerReadOnly)
+	}
+	if err := makeBucket(args.BucketName, objectAPI); err != nil {
+		return toJSONError(err, args.BucketName)
+	}
+	if err := applyBucketTemplate(args.BucketName, objectAPI, getRequestID(r)); err != nil {
 		return toJSONError(err, args.BucketName)
 	}
 	reply.UIVersion = miniobrowser.UIVersion
@@ -268,11 +271,20 @@ func (web *webAPIHandlers) RemoveObject(r *http.Request, args *RemoveObjectArgs,
 	if !isHTTPRequestValid(r) {
 		return toJSONError(errAuthentication)
 	}
+	if globalIsReadOnly {
+		return toJSONError(errServerReadOnly)
+	}
 
 	objectLock := globalNSMutex.NewNSLock(args.BucketName, args.ObjectName)
 	objectLock.Lock()
 	defer objectLock.Unlock()
 
+	// Retained objects cannot be deleted.
+	if err := checkObjectRetention(objectAPI, args.BucketName, args.ObjectName); err != nil {
+		return toJSONError(err, args.BucketName, args.ObjectName)
+	}
+
+	size := getTrackedObjectSize(objectAPI, args.BucketName, args.ObjectName)
 	if err := objectAPI.DeleteObject(args.BucketName, args.ObjectName); err != nil {
 		if isErrObjectNotFound(err) {
 			// Ignore object not found error.
@@ -281,6 +293,7 @@ func (web *webAPIHandlers) RemoveObject(r *http.Request, args *RemoveObjectArgs,
 		}
 		return toJSONError(err, args.BucketName, args.ObjectName)
 	}
+	globalBucketUsage.add(args.BucketName, -size)
 
 	// Notify object deleted event.
 	eventNotify(eventData{
@@ -316,7 +329,7 @@ func (web *webAPIHandlers) Login(r *http.Request, args *LoginArgs, reply *LoginR
 	if err != nil {
 		// Make sure to log errors related to browser login,
 		// for security and auditing reasons.
-		errorIf(err, "Unable to login request from %s", r.RemoteAddr)
+		requestErrorIf(r, err, "Unable to login request from %s", r.RemoteAddr)
 		return toJSONError(err)
 	}
 
@@ -382,7 +395,7 @@ func (web *webAPIHandlers) SetAuth(r *http.Request, args *SetAuthArgs, reply *Se
 	reply.PeerErrMsgs = make(map[string]string)
 	for svr, errVal := range errsMap {
 		tErr := fmt.Errorf("Unable to change credentials on %s: %v", svr, errVal)
-		errorIf(tErr, "Credentials change could not be propagated successfully!")
+		requestErrorIf(r, tErr, "Credentials change could not be propagated successfully!")
 		reply.PeerErrMsgs[svr] = errVal.Error()
 	}
 
@@ -439,6 +452,10 @@ func (web *webAPIHandlers) Upload(w http.ResponseWriter, r *http.Request) {
 		writeWebErrorResponse(w, errServerNotInitialized)
 		return
 	}
+	if globalIsReadOnly {
+		writeWebErrorResponse(w, errServerReadOnly)
+		return
+	}
 
 	vars := mux.Vars(r)
 	bucket := vars["bucket"]
@@ -456,18 +473,34 @@ func (web *webAPIHandlers) Upload(w http.ResponseWriter, r *http.Request) {
 
 	// Extract incoming metadata if any.
 	metadata := extractMetadataFromHeader(r.Header)
+	setObjectRetention(bucket, metadata)
 
 	// Lock the object.
 	objectLock := globalNSMutex.NewNSLock(bucket, object)
 	objectLock.Lock()
 	defer objectLock.Unlock()
 
+	// Retained objects cannot be overwritten.
+	if err := checkObjectRetention(objectAPI, bucket, object); err != nil {
+		writeWebErrorResponse(w, err)
+		return
+	}
+
+	// Upload must fit in the quota of the bucket.
+	quota, err := checkBucketQuota(objectAPI, bucket, object, r.ContentLength)
+	if err != nil {
+		writeWebErrorResponse(w, err)
+		return
+	}
+
 	sha256sum := ""
-	objInfo, err := objectAPI.PutObject(bucket, object, -1, r.Body, metadata, sha256sum)
+	objInfo, err := objectAPI.PutObject(bucket, object, -1, globalIngressLimiter.reader(r.Body), metadata, sha256sum)
 	if err != nil {
+		quota.cancel()
 		writeWebErrorResponse(w, err)
 		return
 	}
+	quota.commit(objInfo.Size)
 
 	// Notify object created event.
 	eventNotify(eventData{
@@ -511,8 +544,13 @@ func (web *webAPIHandlers) Download(w http.ResponseWriter, r *http.Request) {
 		writeWebErrorResponse(w, err)
 		return
 	}
+	// Customer keys are not sent by the browser.
+	if isObjectCustomerEncrypted(objInfo.UserDefined) {
+		writeWebErrorResponse(w, errSSECustomerEncryptedObject)
+		return
+	}
 	offset := int64(0)
-	err = objectAPI.GetObject(bucket, object, offset, objInfo.Size, w)
+	err = objectAPI.GetObject(bucket, object, offset, objInfo.Size, globalEgressLimiter.writer(w))
 	if err != nil {
 		/// No need to print error, response writer already written to.
 		return
@@ -637,6 +675,9 @@ func (web *webAPIHandlers) SetBucketPolicy(r *http.Request, args *SetBucketPolic
 	if !isHTTPRequestValid(r) {
 		return toJSONError(errAuthentication)
 	}
+	if globalIsReadOnly {
+		return toJSONError(errServerReadOnly)
+	}
 
 	bucketP := policy.BucketPolicy(args.Policy)
 	if !bucketP.IsValidBucketPolicy() {
@@ -651,7 +692,7 @@ func (web *webAPIHandlers) SetBucketPolicy(r *http.Request, args *SetBucketPolic
 	}
 	policyInfo.Statements = policy.SetPolicy(policyInfo.Statements, bucketP, args.BucketName, args.Prefix)
 	if len(policyInfo.Statements) == 0 {
-		err = persistAndNotifyBucketPolicyChange(args.BucketName, policyChange{true, nil}, objectAPI)
+		err = persistAndNotifyBucketPolicyChange(args.BucketName, policyChange{true, nil}, objectAPI, getRequestID(r))
 		if err != nil {
 			return toJSONError(err, args.BucketName)
 		}
@@ -664,7 +705,7 @@ func (web *webAPIHandlers) SetBucketPolicy(r *http.Request, args *SetBucketPolic
 	}
 
 	// Parse validate and save bucket policy.
-	if s3Error := parseAndPersistBucketPolicy(args.BucketName, data, objectAPI); s3Error != ErrNone {
+	if s3Error := parseAndPersistBucketPolicy(args.BucketName, data, objectAPI, getRequestID(r)); s3Error != ErrNone {
 		apiErr := getAPIError(s3Error)
 		var err error
 		if apiErr.Code == "XMinioPolicyNesting" {
@@ -728,10 +769,15 @@ func presignedGet(host, bucket, object string, expiry int64) string {
 	dateStr := date.Format(iso8601Format)
 	credential := fmt.Sprintf("%s/%s", accessKey, getScope(date, region))
 
-	var expiryStr = "604800" // Default set to be expire in 7days.
-	if expiry < 604800 && expiry > 0 {
-		expiryStr = strconv.FormatInt(expiry, 10)
+	// Default set to be expire in 7days, at most in the maximum
+	// expiry of presigned URLs.
+	if expiry <= 0 {
+		expiry = int64(defaultMaxPresignExpiry / time.Second)
 	}
+	if maxExpiry := int64(serverConfig.GetMaxPresignExpiry() / time.Second); expiry > maxExpiry {
+		expiry = maxExpiry
+	}
+	expiryStr := strconv.FormatInt(expiry, 10)
 	query := strings.Join([]string{
 		"X-Amz-Algorithm=" + signV4Algorithm,
 		"X-Amz-Credential=" + strings.Replace(credential, "/", "%2F", -1),
@@ -803,6 +849,12 @@ func toWebAPIError(err error) APIError {
 			HTTPStatusCode: http.StatusServiceUnavailable,
 			Description:    err.Error(),
 		}
+	} else if err == errServerReadOnly {
+		return APIError{
+			Code:           "MethodNotAllowed",
+			HTTPStatusCode: http.StatusMethodNotAllowed,
+			Description:    err.Error(),
+		}
 	} else if err == errInvalidAccessKeyLength {
 		return APIError{
 			Code:           "AccessDenied",
@@ -821,6 +873,12 @@ func toWebAPIError(err error) APIError {
 			HTTPStatusCode: http.StatusForbidden,
 			Description:    err.Error(),
 		}
+	} else if err == errSSECustomerEncryptedObject {
+		return APIError{
+			Code:           "InvalidRequest",
+			HTTPStatusCode: http.StatusBadRequest,
+			Description:    err.Error(),
+		}
 	}
 
 	// Convert error type to api error code.
@@ -850,6 +908,8 @@ func toWebAPIError(err error) APIError {
 		apiErrCode = ErrReadQuorum
 	case PolicyNesting:
 		apiErrCode = ErrPolicyNesting
+	case ObjectRetained:
+		apiErrCode = ErrObjectRetained
 	default:
 		// Log unexpected and unhandled errors.
 		errorIf(err, errUnexpected.Error())
@@ -1533,9 +1533,10 @@ func TestWebObjectLayerFaultyDisks(t *testing.T) {
 	if rec.Code != http.StatusOK {
 		t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
 	}
+	// Object metadata can not be read from a quorum of disks.
 	resp := string(rec.Body.Bytes())
-	if !strings.Contains(resp, "We encountered an internal error, please try again.") {
-		t.Fatalf("Unexpected error message, expected: `Invalid token`, found: `%s`", resp)
+	if !strings.Contains(resp, "Multiple disk failures, unable to reconstruct data.") {
+		t.Fatalf("Unexpected error message, expected: `Multiple disk failures`, found: `%s`", resp)
 	}
 
 	// Test authorization of Web.Upload
@@ -1549,12 +1550,14 @@ func TestWebObjectLayerFaultyDisks(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Cannot create upload request, %v", err)
 	}
+	rec = httptest.NewRecorder()
 	apiRouter.ServeHTTP(rec, req)
-	if rec.Code != http.StatusOK {
-		t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("Expected the response status to be 404, but instead found `%d`", rec.Code)
 	}
+	// Bucket can not be found on any of the disks.
 	resp = string(rec.Body.Bytes())
-	if !strings.Contains(resp, "We encountered an internal error, please try again.") {
-		t.Fatalf("Unexpected error message, expected: `Invalid token`, found: `%s`", resp)
+	if !strings.Contains(resp, "The specified bucket does not exist") {
+		t.Fatalf("Unexpected error message, expected: `The specified bucket does not exist`, found: `%s`", resp)
 	}
 }
@@ -0,0 +1,170 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/url"
+	"path"
+	"path/filepath"
+	"sync"
+)
+
+// avoidedDisks - set of disks which are excluded from placement of
+// new object shards. Avoided disks continue to serve reads, this
+// allows a slowly failing disk to be drained before replacement.
+type avoidedDisks struct {
+	mutex *sync.RWMutex
+	disks map[string]struct{}
+}
+
+// newAvoidedDisks - initialize an empty avoided disks set.
+func newAvoidedDisks() *avoidedDisks {
+	return &avoidedDisks{
+		mutex: &sync.RWMutex{},
+		disks: make(map[string]struct{}),
+	}
+}
+
+// Avoid - exclude disk from placement of new writes.
+func (a *avoidedDisks) Avoid(disk string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.disks[disk] = struct{}{}
+}
+
+// Unavoid - include a previously avoided disk back for new writes.
+func (a *avoidedDisks) Unavoid(disk string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	delete(a.disks, disk)
+}
+
+// Reset - replaces avoided disks with input disks.
+func (a *avoidedDisks) Reset(disks []string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.disks = make(map[string]struct{}, len(disks))
+	for _, disk := range disks {
+		a.disks[disk] = struct{}{}
+	}
+}
+
+// IsAvoided - returns true if disk is excluded from new writes.
+func (a *avoidedDisks) IsAvoided(disk StorageAPI) bool {
+	if disk == nil {
+		return false
+	}
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	_, ok := a.disks[disk.String()]
+	return ok
+}
+
+// isAvoidedEndpoint - returns true if disk corresponding to the
+// endpoint is excluded from new writes.
+func (a *avoidedDisks) isAvoidedEndpoint(ep *url.URL) bool {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	_, ok := a.disks[endpointDiskString(ep)]
+	return ok
+}
+
+// endpointDiskString - returns the stringified representation of the
+// StorageAPI which would be initialized for a given endpoint.
+func endpointDiskString(ep *url.URL) string {
+	if isLocalStorage(ep) {
+		diskPath, err := filepath.Abs(getPath(ep))
+		if err != nil {
+			return getPath(ep)
+		}
+		return diskPath
+	}
+	return ep.Host + ":" + path.Join(storageRPCPath, getPath(ep))
+}
+
+// isClusterEndpoint - returns true if the endpoint is one of the
+// disks of the erasure coded object layer.
+func isClusterEndpoint(objAPI ObjectLayer, ep *url.URL) bool {
+	diskString := endpointDiskString(ep)
+	switch obj := objAPI.(type) {
+	case *xlObjects:
+		for _, disk := range obj.storageDisks {
+			if disk != nil && disk.String() == diskString {
+				return true
+			}
+		}
+	case sseObjects:
+		return isClusterEndpoint(obj.ObjectLayer, ep)
+	case *xlSets:
+		for _, xl := range obj.sets {
+			if isClusterEndpoint(xl, ep) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// setDiskAvoided - parses the endpoint and excludes (or includes
+// back) the corresponding disk from placement of new writes, saved
+// in the config such that it survives restarts.
+func setDiskAvoided(endpoint string, avoid bool) error {
+	eps, err := parseStorageEndpoints([]string{endpoint})
+	if err != nil {
+		return err
+	}
+	disk := endpointDiskString(eps[0])
+	if avoid {
+		globalAvoidedDisks.Avoid(disk)
+	} else {
+		globalAvoidedDisks.Unavoid(disk)
+	}
+	serverConfig.SetDiskAvoided(disk, avoid)
+	return serverConfig.Save()
+}
+
+// loadAvoidedDisks - applies avoided disks saved in the config.
+func loadAvoidedDisks() {
+	globalAvoidedDisks.Reset(serverConfig.GetAvoidedDisks())
+}
+
+// excludeAvoidedDisks - returns a copy of input disks with all the
+// avoided disks set to nil, such that no new shards are placed on
+// them. Parity is satisfied by the remaining disks as long as write
+// quorum is available.
+func excludeAvoidedDisks(disks []StorageAPI) []StorageAPI {
+	newDisks := make([]StorageAPI, len(disks))
+	for index, disk := range disks {
+		if globalAvoidedDisks.IsAvoided(disk) {
+			continue
+		}
+		newDisks[index] = disk
+	}
+	return newDisks
+}
+
+// avoidedDisksCount - returns number of input disks excluded from
+// new writes.
+func avoidedDisksCount(disks []StorageAPI) int {
+	count := 0
+	for _, disk := range disks {
+		if globalAvoidedDisks.IsAvoided(disk) {
+			count++
+		}
+	}
+	return count
+}
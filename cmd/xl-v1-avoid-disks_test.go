@@ -0,0 +1,122 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Tests that avoided disks are excluded from new writes but are
+// still available for reads.
+func TestAvoidedDisksPutObject(t *testing.T) {
+	resetGlobalAvoidedDisks()
+	defer resetGlobalAvoidedDisks()
+
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	xl := obj.(*xlObjects)
+
+	// Avoid the first disk.
+	if err = setDiskAvoided(fsDirs[0], true); err != nil {
+		t.Fatal(err)
+	}
+	if avoidedDisksCount(xl.storageDisks) != 1 {
+		t.Fatalf("Expected exactly one avoided disk, found %d", avoidedDisksCount(xl.storageDisks))
+	}
+	if count := obj.StorageInfo().Backend.AvoidedDisks; count != 1 {
+		t.Fatalf("Expected storage info to report 1 avoided disk, found %d", count)
+	}
+
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Repeat([]byte("a"), 1024)
+	if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// No shards should be placed on the avoided disk.
+	for _, disk := range xl.storageDisks {
+		_, err = disk.StatFile(bucket, object+"/"+xlMetaJSONFile)
+		if globalAvoidedDisks.IsAvoided(disk) {
+			if err == nil {
+				t.Fatalf("Expected no shards on avoided disk %s", disk)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Expected shards on disk %s, failed with %s", disk, err)
+		}
+	}
+
+	// Object should still be readable.
+	var buf bytes.Buffer
+	if err = obj.GetObject(bucket, object, 0, int64(len(data)), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Fatal("Object content mismatch after avoiding a disk")
+	}
+
+	// Object should be listed whichever disk the listing is read from.
+	for i := 0; i < len(xl.storageDisks); i++ {
+		result, err := obj.ListObjects(bucket, "", "", "", 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Objects) != 1 {
+			t.Fatalf("Expected object to be listed, found %d objects", len(result.Objects))
+		}
+	}
+
+	// Avoided disks are saved in the config and restored on restart.
+	savedCfg, err := loadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if disks := savedCfg.AvoidedDisks; len(disks) != 1 {
+		t.Fatalf("Expected one avoided disk saved in config, found %v", disks)
+	}
+	resetGlobalAvoidedDisks()
+	loadAvoidedDisks()
+	if count := avoidedDisksCount(xl.storageDisks); count != 1 {
+		t.Fatalf("Expected avoided disk to be restored from config, found %d", count)
+	}
+
+	// Unavoid the disk, all disks should now be used for new writes.
+	if err = setDiskAvoided(fsDirs[0], false); err != nil {
+		t.Fatal(err)
+	}
+	if count := avoidedDisksCount(xl.storageDisks); count != 0 {
+		t.Fatalf("Expected no avoided disks, found %d", count)
+	}
+	if disks := serverConfig.GetAvoidedDisks(); len(disks) != 0 {
+		t.Fatalf("Expected no avoided disks in config, found %v", disks)
+	}
+}
@@ -42,6 +42,9 @@ func (xl xlObjects) MakeBucket(bucket string) error {
 	// Initialize list of errors.
 	var dErrs = make([]error, len(xl.storageDisks))
 
+	// Disks on which the bucket was created by this call.
+	var createdDisks = make([]StorageAPI, len(xl.storageDisks))
+
 	// Make a volume entry on all underlying storage disks.
 	for index, disk := range xl.storageDisks {
 		if disk == nil {
@@ -55,7 +58,9 @@ func (xl xlObjects) MakeBucket(bucket string) error {
 			err := disk.MakeVol(bucket)
 			if err != nil {
 				dErrs[index] = traceError(err)
+				return
 			}
+			createdDisks[index] = disk
 		}(index, disk)
 	}
 
@@ -64,8 +69,9 @@ func (xl xlObjects) MakeBucket(bucket string) error {
 
 	// Do we have write quorum?.
 	if !isDiskQuorum(dErrs, xl.writeQuorum) {
-		// Purge successfully created buckets if we don't have writeQuorum.
-		undoMakeBucket(xl.storageDisks, bucket)
+		// Purge successfully created buckets if we don't have
+		// writeQuorum, buckets which already existed are left alone.
+		undoMakeBucket(createdDisks, bucket)
 		return toObjectErr(traceError(errXLWriteQuorum), bucket)
 	}
 
@@ -76,16 +82,17 @@ func (xl xlObjects) MakeBucket(bucket string) error {
 	return nil
 }
 
-func (xl xlObjects) undoDeleteBucket(bucket string) {
+// undo delete bucket operation upon quorum failure.
+func undoDeleteBucket(storageDisks []StorageAPI, bucket string) {
 	// Initialize sync waitgroup.
 	var wg = &sync.WaitGroup{}
-	// Undo previous make bucket entry on all underlying storage disks.
-	for index, disk := range xl.storageDisks {
+	// Undo previous delete bucket entry on all underlying storage disks.
+	for index, disk := range storageDisks {
 		if disk == nil {
 			continue
 		}
 		wg.Add(1)
-		// Delete a bucket inside a go-routine.
+		// Make a bucket inside a go-routine.
 		go func(index int, disk StorageAPI) {
 			defer wg.Done()
 			_ = disk.MakeVol(bucket)
@@ -223,6 +230,9 @@ func (xl xlObjects) DeleteBucket(bucket string) error {
 	var wg = &sync.WaitGroup{}
 	var dErrs = make([]error, len(xl.storageDisks))
 
+	// Disks from which the bucket was deleted by this call.
+	var deletedDisks = make([]StorageAPI, len(xl.storageDisks))
+
 	// Remove a volume entry on all underlying storage disks.
 	for index, disk := range xl.storageDisks {
 		if disk == nil {
@@ -239,6 +249,7 @@ func (xl xlObjects) DeleteBucket(bucket string) error {
 				dErrs[index] = traceError(err)
 				return
 			}
+			deletedDisks[index] = disk
 			// Cleanup all the previously incomplete multiparts.
 			err = cleanupDir(disk, minioMetaMultipartBucket, bucket)
 			if err != nil {
@@ -254,11 +265,18 @@ func (xl xlObjects) DeleteBucket(bucket string) error {
 	wg.Wait()
 
 	if !isDiskQuorum(dErrs, xl.writeQuorum) {
-		xl.undoDeleteBucket(bucket)
+		undoDeleteBucket(deletedDisks, bucket)
 		return toObjectErr(traceError(errXLWriteQuorum), bucket)
 	}
 
 	if reducedErr := reduceWriteQuorumErrs(dErrs, bucketOpIgnoredErrs, xl.writeQuorum); reducedErr != nil {
+		// Bucket is not empty on a quorum of disks, restore it on
+		// the disks it was deleted from to keep all the disks
+		// consistent. Leftovers of a bucket not found on a quorum
+		// of disks stay deleted.
+		if errorCause(reducedErr) != errVolumeNotFound {
+			undoDeleteBucket(deletedDisks, bucket)
+		}
 		return toObjectErr(reducedErr, bucket)
 	}
 
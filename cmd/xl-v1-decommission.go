@@ -0,0 +1,90 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io"
+	"path"
+	"time"
+)
+
+// hasAvoidedShards - returns true if any of the avoided disks holds a
+// shard of the object.
+func (xl xlObjects) hasAvoidedShards(bucket, object string) bool {
+	for _, disk := range xl.storageDisks {
+		if !globalAvoidedDisks.IsAvoided(disk) {
+			continue
+		}
+		if _, err := disk.StatFile(bucket, path.Join(object, xlMetaJSONFile)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// DrainObject - relocates the shards of an object off the avoided
+// disks by writing the object again, avoided disks receive no shards
+// of the new copy and the old one is removed from all the disks.
+// Content, metadata and modification time of the object are kept.
+// Returns false if none of the avoided disks holds a shard of the
+// object.
+func (xl xlObjects) DrainObject(bucket, object string) (bool, error) {
+	if err := checkGetObjArgs(bucket, object); err != nil {
+		return false, err
+	}
+
+	// Lock the object, it is read and written again.
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	if !xl.hasAvoidedShards(bucket, object) {
+		return false, nil
+	}
+
+	objInfo, err := xl.GetObjectInfo(bucket, object)
+	if err != nil {
+		return false, err
+	}
+	metadata := make(map[string]string, len(objInfo.UserDefined)+1)
+	for key, value := range objInfo.UserDefined {
+		metadata[key] = value
+	}
+	metadata[modTimeMetaKey] = objInfo.ModTime.UTC().Format(time.RFC3339Nano)
+
+	// Stream the object into its new copy, the old copy is only
+	// removed once the new one is completely written.
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		pipeWriter.CloseWithError(xl.GetObject(bucket, object, 0, objInfo.Size, pipeWriter))
+	}()
+	_, err = xl.PutObject(bucket, object, objInfo.Size, pipeReader, metadata, "")
+	pipeReader.Close()
+	if err != nil {
+		return false, err
+	}
+
+	// md5Sum of multipart objects is not the md5 of their content,
+	// restore it such that the ETag of the object does not change.
+	if metadata["md5Sum"] != objInfo.MD5Sum {
+		metadata["md5Sum"] = objInfo.MD5Sum
+		if _, err = xl.CopyObject(bucket, object, bucket, object, metadata); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
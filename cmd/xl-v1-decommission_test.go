@@ -0,0 +1,142 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Tests that draining objects relocates their shards off the avoided
+// disk and objects remain readable once the disk is gone.
+func TestDrainObject(t *testing.T) {
+	resetGlobalAvoidedDisks()
+	defer resetGlobalAvoidedDisks()
+
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	xl := obj.(*xlObjects)
+
+	bucket := "bucket"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	objects := map[string][]byte{
+		"object":       bytes.Repeat([]byte("a"), 1024),
+		"dir/object":   bytes.Repeat([]byte("b"), blockSizeV1+1),
+		"empty-object": {},
+	}
+	for object, data := range objects {
+		if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), map[string]string{"content-type": "text/plain"}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Multipart objects keep their ETag.
+	multipartData := bytes.Repeat([]byte("c"), 2048)
+	objects["multipart-object"] = multipartData
+	uploadID, err := obj.NewMultipartUpload(bucket, "multipart-object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md5Hex, err := obj.PutObjectPart(bucket, "multipart-object", uploadID, 1, int64(len(multipartData)), bytes.NewReader(multipartData), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.CompleteMultipartUpload(bucket, "multipart-object", uploadID, []completePart{{PartNumber: 1, ETag: md5Hex}}); err != nil {
+		t.Fatal(err)
+	}
+
+	objInfos := make(map[string]ObjectInfo)
+	for object := range objects {
+		if objInfos[object], err = obj.GetObjectInfo(bucket, object); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Nothing to relocate while no disk is avoided.
+	if relocated, err := obj.DrainObject(bucket, "object"); err != nil || relocated {
+		t.Fatalf("Expected no relocation without avoided disks, got %v, %v", relocated, err)
+	}
+
+	if err = setDiskAvoided(fsDirs[0], true); err != nil {
+		t.Fatal(err)
+	}
+	for object := range objects {
+		relocated, err := obj.DrainObject(bucket, object)
+		if err != nil {
+			t.Fatalf("%s: %s", object, err)
+		}
+		if !relocated {
+			t.Fatalf("%s: Expected object to be relocated", object)
+		}
+		if _, err = xl.storageDisks[0].StatFile(bucket, object+"/"+xlMetaJSONFile); err == nil {
+			t.Fatalf("%s: Expected no shards left on the avoided disk", object)
+		}
+
+		// Objects are only relocated once.
+		if relocated, err = obj.DrainObject(bucket, object); err != nil || relocated {
+			t.Fatalf("%s: Expected no relocation of a drained object, got %v, %v", object, relocated, err)
+		}
+	}
+
+	// Remove the drained disk, objects are read from the remaining disks.
+	posixDisk, ok := xl.storageDisks[0].(*retryStorage)
+	if !ok {
+		t.Fatal("storage disk is not *retryStorage type")
+	}
+	xl.storageDisks[0] = newNaughtyDisk(posixDisk, nil, errDiskNotFound)
+	for object, data := range objects {
+		objInfo, err := obj.GetObjectInfo(bucket, object)
+		if err != nil {
+			t.Fatalf("%s: %s", object, err)
+		}
+		expected := objInfos[object]
+		if objInfo.Size != expected.Size || objInfo.MD5Sum != expected.MD5Sum ||
+			!objInfo.ModTime.Equal(expected.ModTime) || objInfo.ContentType != expected.ContentType {
+			t.Fatalf("%s: Expected object info %v, got %v", object, expected, objInfo)
+		}
+
+		var buf bytes.Buffer
+		if err = obj.GetObject(bucket, object, 0, int64(len(data)), &buf); err != nil {
+			t.Fatalf("%s: %s", object, err)
+		}
+		if !bytes.Equal(buf.Bytes(), data) {
+			t.Fatalf("%s: Object content mismatch after draining the disk", object)
+		}
+	}
+
+	// Draining is only supported in XL mode.
+	fsObj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(fsDir)
+	if _, err = fsObj.DrainObject(bucket, "object"); errorCause(err) != (NotImplemented{}) {
+		t.Fatalf("Expected NotImplemented, got %v", err)
+	}
+}
@@ -21,11 +21,15 @@ import "errors"
 // errXLMaxDisks - returned for reached maximum of disks.
 var errXLMaxDisks = errors.New("Number of disks are higher than supported maximum count '16'")
 
+// errXLSetDisks - returned for disks which do not divide into
+// erasure sets of equal size.
+var errXLSetDisks = errors.New("Number of disks beyond '16' should divide into erasure sets of equal size of up to '16' disks")
+
 // errXLMinDisks - returned for minimum number of disks.
 var errXLMinDisks = errors.New("Minimum '4' disks are required to enable erasure code")
 
-// errXLNumDisks - returned for odd number of disks.
-var errXLNumDisks = errors.New("Total number of disks should be multiples of '2'")
+// errXLNumDisks - returned for odd number of disks in a distributed setup.
+var errXLNumDisks = errors.New("Total number of disks should be multiples of '2' in a distributed setup")
 
 // errXLReadQuorum - did not meet read quorum.
 var errXLReadQuorum = errors.New("Read failed. Insufficient number of disks online")
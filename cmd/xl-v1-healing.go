@@ -71,6 +71,12 @@ func (xl xlObjects) HealBucket(bucket string) error {
 	return healBucketMetadata(xl.storageDisks, bucket, xl.readQuorum)
 }
 
+// RebuildBucketIndex - reconciles buckets on all the disks with their
+// metadata, see rebuildBucketIndex for details.
+func (xl xlObjects) RebuildBucketIndex() (BucketIndexReport, error) {
+	return rebuildBucketIndex(xl.storageDisks, xl.readQuorum, xl.writeQuorum)
+}
+
 // Heal bucket - create buckets on disks where it does not exist.
 func healBucket(storageDisks []StorageAPI, bucket string, writeQuorum int) error {
 	bucketLock := globalNSMutex.NewNSLock(bucket, "")
@@ -236,6 +242,12 @@ func healObject(storageDisks []StorageAPI, bucket string, object string, quorum
 		return pErr
 	}
 
+	return healObjectDisks(bucket, object, latestMeta, partsMetadata, errs, latestDisks, outDatedDisks)
+}
+
+// healObjectDisks - rebuilds the object on outDatedDisks from the
+// parts on latestDisks.
+func healObjectDisks(bucket, object string, latestMeta xlMetaV1, partsMetadata []xlMetaV1, errs []error, latestDisks, outDatedDisks []StorageAPI) error {
 	for index, disk := range outDatedDisks {
 		// Before healing outdated disks, we need to remove xl.json
 		// and part files from "bucket/object/" so that
@@ -336,6 +348,64 @@ func healObject(storageDisks []StorageAPI, bucket string, object string, quorum
 	return nil
 }
 
+// isValidObjectParts - returns true if all the parts of the object on
+// disk match their checksums in xlMeta.
+func isValidObjectParts(disk StorageAPI, bucket, object string, xlMeta xlMetaV1) bool {
+	for _, part := range xlMeta.Parts {
+		sumInfo := xlMeta.Erasure.GetCheckSumInfo(part.Name)
+		if !isValidBlock(disk, bucket, pathJoin(object, part.Name), sumInfo.Hash, sumInfo.Algorithm) {
+			return false
+		}
+	}
+	return true
+}
+
+// verifyHealObject - heals an object like healObject, also rebuilding
+// the parts which do not match their checksums from the other disks.
+// Returns HealStatusCorrupt when fewer disks than the data blocks have
+// a valid copy of the object, it cannot be healed then.
+func verifyHealObject(storageDisks []StorageAPI, bucket string, object string, quorum int) (HealStatus, error) {
+	partsMetadata, errs := readAllXLMetadata(storageDisks, bucket, object)
+	if reducedErr := reduceReadQuorumErrs(errs, nil, quorum); reducedErr != nil {
+		if errorCause(reducedErr) == errXLReadQuorum {
+			return HealStatusCorrupt, nil
+		}
+		return "", toObjectErr(reducedErr, bucket, object)
+	}
+
+	latestDisks, modTime := listOnlineDisks(storageDisks, partsMetadata, errs)
+	outDatedDisks := outDatedDisks(storageDisks, partsMetadata, errs)
+	latestMeta, err := pickValidXLMeta(partsMetadata, modTime)
+	if err != nil {
+		return HealStatusCorrupt, nil
+	}
+
+	// Disks with corrupted parts are healed like outdated disks.
+	validDisks := 0
+	for index, disk := range latestDisks {
+		if disk == nil {
+			continue
+		}
+		if !isValidObjectParts(disk, bucket, object, partsMetadata[index]) {
+			latestDisks[index] = nil
+			outDatedDisks[index] = disk
+			continue
+		}
+		validDisks++
+	}
+	if validDisks < latestMeta.Erasure.DataBlocks {
+		return HealStatusCorrupt, nil
+	}
+	if diskCount(outDatedDisks) == 0 {
+		return HealStatusHealthy, nil
+	}
+
+	if err = healObjectDisks(bucket, object, latestMeta, partsMetadata, errs, latestDisks, outDatedDisks); err != nil {
+		return "", err
+	}
+	return HealStatusHealed, nil
+}
+
 // HealObject heals a given object for all its missing entries.
 // FIXME: If an object object was deleted and one disk was down,
 // and later the disk comes back up again, heal on the object
@@ -351,5 +421,38 @@ func (xl xlObjects) HealObject(bucket, object string) error {
 	defer objectLock.RUnlock()
 
 	// Heal the object.
-	return healObject(xl.storageDisks, bucket, object, xl.readQuorum)
+	if err := healObject(xl.storageDisks, bucket, object, xl.readQuorum); err != nil {
+		return err
+	}
+
+	// Healed disks have a new `xl.json`.
+	xl.invalidateMeta(bucket, object)
+
+	// Object is no longer pending heal, if it was.
+	deleteLaggardHealRecord(xl.storageDisks, bucket, object)
+	return nil
+}
+
+// VerifyHealObject verifies all the parts of a given object against
+// their checksums and heals the missing and corrupted ones.
+func (xl xlObjects) VerifyHealObject(bucket, object string) (HealStatus, error) {
+	if err := checkGetObjArgs(bucket, object); err != nil {
+		return "", err
+	}
+
+	// Lock the object before healing.
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock.RLock()
+	defer objectLock.RUnlock()
+
+	status, err := verifyHealObject(xl.storageDisks, bucket, object, xl.readQuorum)
+	if status == HealStatusHealed {
+		// Healed disks have a new `xl.json`.
+		xl.invalidateMeta(bucket, object)
+	}
+	if err == nil && status != HealStatusCorrupt {
+		// Object is no longer pending heal, if it was.
+		deleteLaggardHealRecord(xl.storageDisks, bucket, object)
+	}
+	return status, err
 }
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 )
@@ -423,3 +424,99 @@ func TestQuickHeal(t *testing.T) {
 		t.Fatal("Got an unexpected error: ", err)
 	}
 }
+
+// corruptObjectPart - flips the bytes of the first part of an object
+// on disk, leaving its size unchanged.
+func corruptObjectPart(t *testing.T, disk StorageAPI, bucket, object string) {
+	partPath := pathJoin(object, "part.1")
+	buf, err := disk.ReadAll(bucket, partPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range buf {
+		buf[i] = ^buf[i]
+	}
+	if err = disk.DeleteFile(bucket, partPath); err != nil {
+		t.Fatal(err)
+	}
+	if err = disk.AppendFile(bucket, partPath, buf); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Tests verifying and healing objects with corrupted parts.
+func TestVerifyHealObject(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	bucket := "bucket"
+	object := "object"
+	data := bytes.Repeat([]byte("a"), 1024*1024)
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// All the parts are valid.
+	status, err := obj.VerifyHealObject(bucket, object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != HealStatusHealthy {
+		t.Fatalf("Expected %s, got %s", HealStatusHealthy, status)
+	}
+
+	// A corrupted part is rebuilt from parity.
+	corruptObjectPart(t, xl.storageDisks[0], bucket, object)
+	status, err = obj.VerifyHealObject(bucket, object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != HealStatusHealed {
+		t.Fatalf("Expected %s, got %s", HealStatusHealed, status)
+	}
+	xlMeta, err := readXLMeta(xl.storageDisks[0], bucket, object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isValidObjectParts(xl.storageDisks[0], bucket, object, xlMeta) {
+		t.Fatal("Expected corrupted part to be healed")
+	}
+	var buf bytes.Buffer
+	if err = obj.GetObject(bucket, object, 0, int64(len(data)), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Fatal("Healed object differs from the uploaded object")
+	}
+
+	// Corrupting more parts than the parity blocks leaves the object
+	// beyond repair.
+	for _, disk := range xl.storageDisks[:xl.parityBlocks+1] {
+		corruptObjectPart(t, disk, bucket, object)
+	}
+	status, err = obj.VerifyHealObject(bucket, object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != HealStatusCorrupt {
+		t.Fatalf("Expected %s, got %s", HealStatusCorrupt, status)
+	}
+
+	// Missing objects are reported as such.
+	if _, err = obj.VerifyHealObject(bucket, "missing"); err == nil {
+		t.Fatal("Expected healing a missing object to fail")
+	}
+}
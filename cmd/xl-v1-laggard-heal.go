@@ -0,0 +1,335 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// Attempts to heal an object written without laggard disks
+	// before giving up, its record is kept and it is healed again at
+	// the next start or by HealObjects.
+	laggardHealMaxAttempts = 5
+
+	// Interval between attempts to heal an object.
+	laggardHealRetryInterval = 1 * time.Second
+)
+
+// Number of objects pending heal, healed and given up on by the
+// laggard healers of this server.
+var laggardHealsPending, laggardHealsHealed, laggardHealsFailed int64
+
+// laggardHealStats - returns number of objects pending heal, healed
+// and given up on by the laggard healers of this server.
+func laggardHealStats() (pending, healed, failed int64) {
+	return atomic.LoadInt64(&laggardHealsPending), atomic.LoadInt64(&laggardHealsHealed), atomic.LoadInt64(&laggardHealsFailed)
+}
+
+// laggardHealEntry - object written without some of its disks.
+type laggardHealEntry struct {
+	bucket    string
+	object    string
+	tmpObject string
+	// Disks skipped while writing and their writes still in progress,
+	// unset for objects resumed from their records at startup.
+	disks  []StorageAPI
+	writes *sync.WaitGroup
+}
+
+// laggardHealer - heals objects written without laggard disks in
+// background. A single routine heals queued objects one at a time,
+// it exits once the queue is empty and is started again on demand.
+// Queued objects are recorded in `.minio.sys/heal` until healed,
+// such that they are not lost by a restart.
+type laggardHealer struct {
+	mutex   *sync.Mutex
+	wg      *sync.WaitGroup
+	queue   []laggardHealEntry
+	running bool
+
+	// Objects pending, healed and failed to be healed.
+	pending int
+	healed  int
+	failed  int
+}
+
+// newLaggardHealer - initialize a new idle laggard healer.
+func newLaggardHealer() *laggardHealer {
+	return &laggardHealer{
+		mutex: &sync.Mutex{},
+		wg:    &sync.WaitGroup{},
+	}
+}
+
+// enqueue - records an object to be healed on the disks it was
+// written to and queues it, starts healing in background if not
+// already running.
+func (h *laggardHealer) enqueue(xl xlObjects, entry laggardHealEntry) {
+	var disks []StorageAPI
+	for _, disk := range xl.storageDisks {
+		if disk != nil && !isLaggardDisk(entry.disks, disk) {
+			disks = append(disks, disk)
+		}
+	}
+	errorIf(saveLaggardHealRecord(disks, PendingHealInfo{Bucket: entry.bucket, Object: entry.object}),
+		"Unable to record %s/%s written without laggard disks", entry.bucket, entry.object)
+	h.push(xl, entry)
+}
+
+// resume - queues objects recorded by a previous run which were not
+// healed yet, including the ones given up on.
+func (h *laggardHealer) resume(xl xlObjects) {
+	for _, info := range readLaggardHealRecords(xl.storageDisks) {
+		h.push(xl, laggardHealEntry{bucket: info.Bucket, object: info.Object})
+	}
+}
+
+// push - queues an object to be healed, starts healing in background
+// if not already running.
+func (h *laggardHealer) push(xl xlObjects, entry laggardHealEntry) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.queue = append(h.queue, entry)
+	h.pending++
+	atomic.AddInt64(&laggardHealsPending, 1)
+	if h.running {
+		return
+	}
+	h.running = true
+	h.wg.Add(1)
+	go h.run(xl)
+}
+
+// run - heals queued objects until the queue is empty. Records of
+// healed objects are removed, objects given up on are recorded as
+// failed.
+func (h *laggardHealer) run(xl xlObjects) {
+	defer h.wg.Done()
+	for {
+		h.mutex.Lock()
+		if len(h.queue) == 0 {
+			h.running = false
+			h.mutex.Unlock()
+			return
+		}
+		entry := h.queue[0]
+		h.queue = h.queue[1:]
+		h.mutex.Unlock()
+
+		err := healLaggardEntry(xl, entry)
+		if err == nil {
+			deleteLaggardHealRecord(xl.storageDisks, entry.bucket, entry.object)
+		} else {
+			errorIf(err, "Unable to heal %s/%s written without laggard disks, it is healed again at the next start or by HealObjects", entry.bucket, entry.object)
+			errorIf(saveLaggardHealRecord(xl.storageDisks, PendingHealInfo{Bucket: entry.bucket, Object: entry.object, Failed: true}),
+				"Unable to record %s/%s as failed to be healed", entry.bucket, entry.object)
+		}
+
+		h.mutex.Lock()
+		h.pending--
+		atomic.AddInt64(&laggardHealsPending, -1)
+		if err != nil {
+			h.failed++
+			atomic.AddInt64(&laggardHealsFailed, 1)
+		} else {
+			h.healed++
+			atomic.AddInt64(&laggardHealsHealed, 1)
+		}
+		h.mutex.Unlock()
+	}
+}
+
+// stats - returns number of objects pending, healed and failed to be
+// healed.
+func (h *laggardHealer) stats() (pending, healed, failed int) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.pending, h.healed, h.failed
+}
+
+// wait - waits for the background routine to finish, if any.
+func (h *laggardHealer) wait() {
+	h.wg.Wait()
+}
+
+// isLaggardDisk - returns true if disk is one of the laggard disks.
+func isLaggardDisk(laggards []StorageAPI, disk StorageAPI) bool {
+	for _, laggard := range laggards {
+		if laggard == disk {
+			return true
+		}
+	}
+	return false
+}
+
+// healLaggardEntry - waits for the writes to laggard disks to finish,
+// removes the discarded temporary object from them and heals the
+// object, retrying up to laggardHealMaxAttempts times.
+func healLaggardEntry(xl xlObjects, entry laggardHealEntry) (err error) {
+	if entry.writes != nil {
+		entry.writes.Wait()
+	}
+	for _, disk := range entry.disks {
+		if disk == nil {
+			continue
+		}
+		errorIf(cleanupDir(disk, minioMetaTmpBucket, entry.tmpObject),
+			"Unable to remove %s from laggard disk %s", entry.tmpObject, disk)
+	}
+
+	for attempt := 1; attempt <= laggardHealMaxAttempts; attempt++ {
+		err = xl.HealObject(entry.bucket, entry.object)
+		if err == nil || isErrObjectNotFound(errorCause(err)) {
+			// Healed, or object was removed meanwhile.
+			return nil
+		}
+		if attempt < laggardHealMaxAttempts {
+			time.Sleep(laggardHealRetryInterval)
+		}
+	}
+	return err
+}
+
+// laggardHealRecordName - returns name of the record of an object
+// pending heal in `.minio.sys/heal`.
+func laggardHealRecordName(bucket, object string) string {
+	return getSHA256Hash([]byte(pathJoin(bucket, object))) + ".json"
+}
+
+// saveLaggardHealRecord - records an object pending heal on all the
+// disks in parallel, the record is written to a temporary file and
+// renamed into place. Fails only if none of the disks has the record.
+func saveLaggardHealRecord(disks []StorageAPI, info PendingHealInfo) error {
+	recordBytes, err := json.Marshal(info)
+	if err != nil {
+		return traceError(err)
+	}
+	name := laggardHealRecordName(info.Bucket, info.Object)
+
+	var wg = &sync.WaitGroup{}
+	var errs = make([]error, len(disks))
+	for index, disk := range disks {
+		if disk == nil {
+			errs[index] = errDiskNotFound
+			continue
+		}
+		wg.Add(1)
+		go func(index int, disk StorageAPI) {
+			defer wg.Done()
+			tmpName := mustGetUUID()
+			if errs[index] = disk.AppendFile(minioMetaTmpBucket, tmpName, recordBytes); errs[index] != nil {
+				return
+			}
+			if errs[index] = disk.RenameFile(minioMetaTmpBucket, tmpName, minioMetaHealBucket, name); errs[index] != nil {
+				disk.DeleteFile(minioMetaTmpBucket, tmpName)
+			}
+		}(index, disk)
+	}
+	wg.Wait()
+
+	for _, err = range errs {
+		if err == nil {
+			return nil
+		}
+	}
+	if err == nil {
+		err = errDiskNotFound
+	}
+	return traceError(err)
+}
+
+// deleteLaggardHealRecord - removes the record of a healed object
+// from all the disks in parallel.
+func deleteLaggardHealRecord(disks []StorageAPI, bucket, object string) {
+	name := laggardHealRecordName(bucket, object)
+	var wg = &sync.WaitGroup{}
+	for _, disk := range disks {
+		if disk == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(disk StorageAPI) {
+			defer wg.Done()
+			err := disk.DeleteFile(minioMetaHealBucket, name)
+			if err != nil && err != errFileNotFound && err != errVolumeNotFound && err != errDiskNotFound {
+				errorIf(err, "Unable to remove heal record of %s/%s from %s", bucket, object, disk)
+			}
+		}(disk)
+	}
+	wg.Wait()
+}
+
+// readLaggardHealRecords - returns objects pending heal recorded on
+// any of the disks, sorted by bucket and object. A record found
+// failed on any disk is reported failed.
+func readLaggardHealRecords(disks []StorageAPI) []PendingHealInfo {
+	records := make(map[string]PendingHealInfo)
+	for _, disk := range disks {
+		if disk == nil {
+			continue
+		}
+		names, err := disk.ListDir(minioMetaHealBucket, "")
+		if err != nil {
+			continue
+		}
+		for _, name := range names {
+			if record, ok := records[name]; ok && record.Failed {
+				continue
+			}
+			recordBytes, err := disk.ReadAll(minioMetaHealBucket, name)
+			if err != nil {
+				continue
+			}
+			var info PendingHealInfo
+			if err = json.Unmarshal(recordBytes, &info); err != nil {
+				continue
+			}
+			records[name] = info
+		}
+	}
+
+	infos := make([]PendingHealInfo, 0, len(records))
+	for _, info := range records {
+		infos = append(infos, info)
+	}
+	sort.Sort(byBucketObject(infos))
+	return infos
+}
+
+// byBucketObject - sorts objects pending heal by bucket and object.
+type byBucketObject []PendingHealInfo
+
+func (b byBucketObject) Len() int      { return len(b) }
+func (b byBucketObject) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byBucketObject) Less(i, j int) bool {
+	if b[i].Bucket != b[j].Bucket {
+		return b[i].Bucket < b[j].Bucket
+	}
+	return b[i].Object < b[j].Object
+}
+
+// ListPendingHeals - lists objects written without laggard disks
+// which are yet to be healed.
+func (xl xlObjects) ListPendingHeals() ([]PendingHealInfo, error) {
+	return readLaggardHealRecords(xl.storageDisks), nil
+}
@@ -0,0 +1,142 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// laggardDisk - blocks appends until released.
+type laggardDisk struct {
+	StorageAPI
+	release chan struct{}
+}
+
+func (d *laggardDisk) AppendFile(volume string, path string, buf []byte) error {
+	<-d.release
+	return d.StorageAPI.AppendFile(volume, path, buf)
+}
+
+// Tests writes complete without a laggard disk and the object is
+// healed on it in background.
+func TestXLPutObjectSkipLaggards(t *testing.T) {
+	defer func(timeout time.Duration) { globalWriteLaggardTimeout = timeout }(globalWriteLaggardTimeout)
+	globalWriteLaggardTimeout = 10 * time.Millisecond
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	laggard := &laggardDisk{StorageAPI: xl.storageDisks[0], release: make(chan struct{})}
+	xl.storageDisks[0] = laggard
+
+	data := bytes.Repeat([]byte("a"), 2*blockSizeV1+1)
+	if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = readXLMeta(laggard.StorageAPI, bucket, object); errorCause(err) != errFileNotFound {
+		t.Fatalf("Expected object to be written without the laggard disk, got %v", err)
+	}
+	if pending, _, _ := xl.laggards.stats(); pending != 1 {
+		t.Fatalf("Expected 1 object pending heal, got %d", pending)
+	}
+	if storageInfo := obj.StorageInfo(); storageInfo.Backend.PendingHeals != 1 {
+		t.Fatalf("Expected 1 pending heal in storage info, got %d", storageInfo.Backend.PendingHeals)
+	}
+	// Object pending heal is recorded on the disks.
+	expected := []PendingHealInfo{{Bucket: bucket, Object: object}}
+	if infos, err := obj.ListPendingHeals(); err != nil || !reflect.DeepEqual(infos, expected) {
+		t.Fatalf("Expected %v pending heals, got %v %v", expected, infos, err)
+	}
+
+	// Laggard disk catches up, object is healed on it.
+	close(laggard.release)
+	xl.laggards.wait()
+	if pending, healed, failed := xl.laggards.stats(); pending != 0 || healed != 1 || failed != 0 {
+		t.Fatalf("Expected 1 object healed, got %d pending, %d healed, %d failed", pending, healed, failed)
+	}
+	if _, err = readXLMeta(laggard.StorageAPI, bucket, object); err != nil {
+		t.Fatalf("Expected object to be healed on the laggard disk, got %v", err)
+	}
+	if entries, err := laggard.ListDir(minioMetaTmpBucket, ""); err != nil || len(entries) != 0 {
+		t.Fatalf("Expected no temporary objects left on the laggard disk, got %v %v", entries, err)
+	}
+	if infos, err := obj.ListPendingHeals(); err != nil || len(infos) != 0 {
+		t.Fatalf("Expected no pending heals, got %v %v", infos, err)
+	}
+
+	var buffer bytes.Buffer
+	if err = obj.GetObject(bucket, object, 0, int64(len(data)), &buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buffer.Bytes(), data) {
+		t.Fatal("Unexpected object content after heal")
+	}
+}
+
+// Tests objects recorded pending heal by a previous run are healed
+// once resumed.
+func TestLaggardHealResume(t *testing.T) {
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("abcd")
+	if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Object written without the first disk by a previous run, which
+	// healing gave up on.
+	if err = cleanupDir(xl.storageDisks[0], bucket, object); err != nil {
+		t.Fatal(err)
+	}
+	if err = saveLaggardHealRecord(xl.storageDisks[1:], PendingHealInfo{Bucket: bucket, Object: object, Failed: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	healer := newLaggardHealer()
+	healer.resume(*xl)
+	healer.wait()
+	if pending, healed, failed := healer.stats(); pending != 0 || healed != 1 || failed != 0 {
+		t.Fatalf("Expected 1 object healed, got %d pending, %d healed, %d failed", pending, healed, failed)
+	}
+	if _, err = readXLMeta(xl.storageDisks[0], bucket, object); err != nil {
+		t.Fatalf("Expected object to be healed on the first disk, got %v", err)
+	}
+	if infos, err := obj.ListPendingHeals(); err != nil || len(infos) != 0 {
+		t.Fatalf("Expected no pending heals, got %v %v", infos, err)
+	}
+}
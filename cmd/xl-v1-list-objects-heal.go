@@ -167,6 +167,13 @@ func (xl xlObjects) ListObjectsHeal(bucket, prefix, marker, delimiter string, ma
 	if err := checkListObjsArgs(bucket, prefix, marker, delimiter, xl); err != nil {
 		return ListObjectsInfo{}, err
 	}
+	// Verify if delimiter is anything other than '/', which we do not
+	// support for listing objects to be healed.
+	if delimiter != "" && delimiter != slashSeparator {
+		return ListObjectsInfo{}, traceError(UnsupportedDelimiter{
+			Delimiter: delimiter,
+		})
+	}
 
 	// With max keys of zero we have reached eof, return right here.
 	if maxKeys == 0 {
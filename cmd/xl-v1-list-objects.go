@@ -31,13 +31,14 @@ func (xl xlObjects) listObjects(bucket, prefix, marker, delimiter string, maxKey
 	if walkResultCh == nil {
 		endWalkCh = make(chan struct{})
 		isLeaf := xl.isObject
-		listDir := listDirFactory(isLeaf, xlTreeWalkIgnoredErrs, xl.getLoadBalancedDisks()...)
+		// Avoided disks miss objects written since they were avoided.
+		listDir := listDirFactory(isLeaf, xlTreeWalkIgnoredErrs, excludeAvoidedDisks(xl.getLoadBalancedDisks())...)
 		walkResultCh = startTreeWalk(bucket, prefix, marker, recursive, listDir, isLeaf, endWalkCh)
 	}
 
 	var objInfos []ObjectInfo
 	var eof bool
-	var nextMarker string
+	var nextMarker, lastPrefix string
 	for i := 0; i < maxKeys; {
 		walkResult, ok := <-walkResultCh
 		if !ok {
@@ -54,8 +55,18 @@ func (xl xlObjects) listObjects(bucket, prefix, marker, delimiter string, maxKey
 			return ListObjectsInfo{}, toObjectErr(walkResult.err, bucket, prefix)
 		}
 		entry := walkResult.entry
+		isPrefix := strings.HasSuffix(entry, slashSeparator)
+		if recursive && delimiter != "" {
+			commonPrefix, skip := groupByDelimiter(entry, prefix, marker, delimiter, lastPrefix)
+			if skip {
+				continue
+			}
+			if commonPrefix != "" {
+				entry, isPrefix, lastPrefix = commonPrefix, true, commonPrefix
+			}
+		}
 		var objInfo ObjectInfo
-		if strings.HasSuffix(entry, slashSeparator) {
+		if isPrefix {
 			// Object name needs to be full path.
 			objInfo.Bucket = bucket
 			objInfo.Name = entry
@@ -98,7 +109,8 @@ func (xl xlObjects) listObjects(bucket, prefix, marker, delimiter string, maxKey
 	return result, nil
 }
 
-// ListObjects - list all objects at prefix, delimited by '/'.
+// ListObjects - list all objects at prefix, optionally delimited by
+// delimiter.
 func (xl xlObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
 	if err := checkListObjsArgs(bucket, prefix, marker, delimiter, xl); err != nil {
 		return ListObjectsInfo{}, err
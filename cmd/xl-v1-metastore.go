@@ -0,0 +1,351 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+)
+
+// Name of the log file of the metadata store.
+const metaStoreLogFile = "metadata.log"
+
+// Number of records in the log of the metadata store, beyond twice
+// the number of live entries, after which the log is compacted.
+const metaStoreCompactSlack = 1000
+
+// Maximum number of entries kept by the metadata store, arbitrary
+// entries are evicted beyond.
+const metaStoreMaxEntries = 100000
+
+// metaStoreEntry - cached metadata of an object.
+type metaStoreEntry struct {
+	Bucket string `json:"b"`
+	Object string `json:"o"`
+	// Deleted is set for records removing an entry.
+	Deleted bool `json:"x,omitempty"`
+
+	Stat statInfo          `json:"stat,omitempty"`
+	Meta map[string]string `json:"meta,omitempty"`
+}
+
+// metaStore - log structured store of object metadata on a fast local
+// disk. Entries are kept in memory up to maxEntries, every change is
+// appended to the log which is replayed on startup and compacted once
+// it grows well beyond the live entries.
+type metaStore struct {
+	mutex      sync.Mutex
+	dir        string
+	log        *os.File
+	records    int
+	entries    map[string]metaStoreEntry
+	maxEntries int
+
+	// Set while the log is compacted, records appended meanwhile are
+	// kept in pending to be appended to the compacted log.
+	compacting bool
+	pending    []metaStoreEntry
+
+	hits, misses uint64
+}
+
+// newMetaStore - opens the metadata store in dir, creating it if
+// needed. Records which cannot be parsed, left behind by a crash while
+// appending, are skipped.
+func newMetaStore(dir string) (*metaStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	m := &metaStore{
+		dir:        dir,
+		entries:    make(map[string]metaStoreEntry),
+		maxEntries: metaStoreMaxEntries,
+	}
+	logPath := filepath.Join(dir, metaStoreLogFile)
+	if f, err := os.Open(logPath); err == nil {
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(nil, 16*1024*1024)
+		for scanner.Scan() {
+			var entry metaStoreEntry
+			if json.Unmarshal(scanner.Bytes(), &entry) != nil {
+				continue
+			}
+			m.apply(entry)
+		}
+		f.Close()
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	m.compacting = true
+	if err := m.compact(m.liveEntries()); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// metaStoreKey - key of an object in the store.
+func metaStoreKey(bucket, object string) string {
+	return bucket + slashSeparator + object
+}
+
+// apply - applies a record to the in memory entries, an arbitrary
+// entry is evicted if there are more than maxEntries.
+func (m *metaStore) apply(entry metaStoreEntry) {
+	m.records++
+	key := metaStoreKey(entry.Bucket, entry.Object)
+	if entry.Deleted {
+		delete(m.entries, key)
+		return
+	}
+	m.entries[key] = entry
+	if len(m.entries) <= m.maxEntries {
+		return
+	}
+	for evictKey := range m.entries {
+		if evictKey != key {
+			delete(m.entries, evictKey)
+			return
+		}
+	}
+}
+
+// appendRecord - applies a record and appends it to the log, the log
+// is compacted when it has grown too large. Failures are ignored as
+// the log only serves to restore the entries on startup.
+func (m *metaStore) appendRecord(entry metaStoreEntry) {
+	m.mutex.Lock()
+	m.apply(entry)
+	if data, err := json.Marshal(entry); err == nil {
+		_, err = m.log.Write(append(data, '\n'))
+		errorIf(err, "Unable to write to metadata store %s.", m.dir)
+	}
+	if m.compacting {
+		m.pending = append(m.pending, entry)
+		m.mutex.Unlock()
+		return
+	}
+	if m.records <= 2*len(m.entries)+metaStoreCompactSlack {
+		m.mutex.Unlock()
+		return
+	}
+	m.compacting = true
+	entries := m.liveEntries()
+	m.mutex.Unlock()
+
+	errorIf(m.compact(entries), "Unable to compact metadata store %s.", m.dir)
+}
+
+// liveEntries - returns a copy of the entries, mutex must be held.
+func (m *metaStore) liveEntries() []metaStoreEntry {
+	entries := make([]metaStoreEntry, 0, len(m.entries))
+	for _, entry := range m.entries {
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// compact - rewrites the log with the live entries only, followed by
+// the records appended while they were written. Only appending those
+// and replacing the log hold the mutex, the current log is kept until
+// then. compacting must be set by the caller, it is cleared once done.
+func (m *metaStore) compact(entries []metaStoreEntry) error {
+	logPath := filepath.Join(m.dir, metaStoreLogFile)
+	tmpPath := logPath + ".tmp"
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		m.mutex.Lock()
+		m.compacting, m.pending = false, nil
+		m.mutex.Unlock()
+		return err
+	}
+	w := bufio.NewWriter(f)
+	writeEntries := func(entries []metaStoreEntry) {
+		for _, entry := range entries {
+			data, merr := json.Marshal(entry)
+			if merr != nil {
+				continue
+			}
+			w.Write(append(data, '\n'))
+		}
+	}
+	writeEntries(entries)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	pending := m.pending
+	m.compacting, m.pending = false, nil
+	writeEntries(pending)
+	if err = w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmpPath, logPath); err != nil {
+		return err
+	}
+	if m.log != nil {
+		m.log.Close()
+	}
+	m.log, err = os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0600)
+	m.records = len(entries) + len(pending)
+	return err
+}
+
+// get - returns cached entry of an object.
+func (m *metaStore) get(bucket, object string) (entry metaStoreEntry, ok bool) {
+	if m == nil {
+		return entry, false
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	entry, ok = m.entries[metaStoreKey(bucket, object)]
+	return entry, ok
+}
+
+// put - caches metadata of an object.
+func (m *metaStore) put(entry metaStoreEntry) {
+	if m == nil {
+		return
+	}
+	m.appendRecord(entry)
+}
+
+// remove - removes cached metadata of an object, if any.
+func (m *metaStore) remove(bucket, object string) {
+	if m == nil {
+		return
+	}
+	if _, ok := m.get(bucket, object); !ok {
+		return
+	}
+	m.appendRecord(metaStoreEntry{Bucket: bucket, Object: object, Deleted: true})
+}
+
+// countLookup - accounts a lookup as hit or miss.
+func (m *metaStore) countLookup(hit bool) {
+	if m == nil {
+		return
+	}
+	if hit {
+		atomic.AddUint64(&m.hits, 1)
+	} else {
+		atomic.AddUint64(&m.misses, 1)
+	}
+}
+
+// lookupStats - returns number of lookups served by and missing the
+// store.
+func (m *metaStore) lookupStats() (hits, misses uint64) {
+	if m == nil {
+		return 0, 0
+	}
+	return atomic.LoadUint64(&m.hits), atomic.LoadUint64(&m.misses)
+}
+
+// Close - closes the log of the store.
+func (m *metaStore) Close() error {
+	if m == nil {
+		return nil
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.log.Close()
+}
+
+// invalidateMeta - removes cached metadata of an object from the
+// metadata store of this server and of all its peers, called by every
+// write of the object. Writes hold the write lock of the object while
+// lookups caching metadata hold its read lock, such that no lookup
+// caches metadata older than the write. Failures to reach a peer are
+// logged.
+func (xl xlObjects) invalidateMeta(bucket, object string) {
+	if xl.metaStore == nil {
+		return
+	}
+	xl.metaStore.remove(bucket, object)
+
+	// This server is not reached through RPC.
+	var peerIndex []int
+	for idx, peer := range globalS3Peers {
+		if peer.addr != globalMinioAddr {
+			peerIndex = append(peerIndex, idx)
+		}
+	}
+	if len(peerIndex) == 0 {
+		return
+	}
+
+	args := &InvalidateObjectMetaPeerArgs{Bucket: bucket, Object: object}
+	errs := globalS3Peers.SendUpdate(peerIndex, args)
+	for idx, err := range errs {
+		errorIf(
+			err,
+			"Error sending metadata store invalidation to %s - %v",
+			globalS3Peers[idx].addr, err,
+		)
+	}
+}
+
+// readObjectInfoCached - reads object info, served from the metadata
+// store if cached. Otherwise metadata is read using readMeta and
+// cached, entries are removed by writes of the object through any
+// server with invalidateMeta.
+func (xl xlObjects) readObjectInfoCached(bucket, object string, readMeta func() (statInfo, map[string]string, error)) (ObjectInfo, error) {
+	if xl.metaStore == nil {
+		xlStat, xlMetaMap, err := readMeta()
+		if err != nil {
+			return ObjectInfo{}, err
+		}
+		return newXLObjectInfo(bucket, object, xlStat, xlMetaMap), nil
+	}
+
+	entry, ok := xl.metaStore.get(bucket, object)
+	xl.metaStore.countLookup(ok)
+	if ok {
+		// newXLObjectInfo modifies the metadata map.
+		meta := make(map[string]string, len(entry.Meta))
+		for k, v := range entry.Meta {
+			meta[k] = v
+		}
+		return newXLObjectInfo(bucket, object, entry.Stat, meta), nil
+	}
+
+	xlStat, xlMetaMap, err := readMeta()
+	if err != nil {
+		if isErrObjectNotFound(err) || errorCause(err) == errFileNotFound {
+			xl.metaStore.remove(bucket, object)
+		}
+		return ObjectInfo{}, err
+	}
+	entry = metaStoreEntry{
+		Bucket: bucket,
+		Object: object,
+		Stat:   xlStat,
+		Meta:   make(map[string]string, len(xlMetaMap)),
+	}
+	for k, v := range xlMetaMap {
+		entry.Meta[k] = v
+	}
+	xl.metaStore.put(entry)
+	return newXLObjectInfo(bucket, object, xlStat, xlMetaMap), nil
+}
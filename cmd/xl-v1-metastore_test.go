@@ -0,0 +1,217 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests put, remove and replay of the metadata store log.
+func TestMetaStore(t *testing.T) {
+	dir, err := ioutil.TempDir(globalTestTmpDir, "minio-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(dir)
+
+	m, err := newMetaStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		m.put(metaStoreEntry{
+			Bucket: "bucket",
+			Object: fmt.Sprintf("object%d", i),
+			Stat:   statInfo{Size: int64(i)},
+			Meta:   map[string]string{"content-type": "text/plain"},
+		})
+	}
+	m.remove("bucket", "object1")
+	if _, ok := m.get("bucket", "object1"); ok {
+		t.Fatal("Expected object1 to be removed")
+	}
+	m.Close()
+
+	// Append a partially written record, as left by a crash.
+	f, err := os.OpenFile(filepath.Join(dir, metaStoreLogFile), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"b":"bucket","o":"obj`)
+	f.Close()
+
+	m, err = newMetaStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	if _, ok := m.get("bucket", "object1"); ok {
+		t.Fatal("Expected object1 to stay removed after reopen")
+	}
+	entry, ok := m.get("bucket", "object2")
+	if !ok {
+		t.Fatal("Expected object2 after reopen")
+	}
+	if entry.Stat.Size != 2 || entry.Meta["content-type"] != "text/plain" {
+		t.Errorf("Unexpected entry after reopen %#v", entry)
+	}
+	if m.records != 2 {
+		t.Errorf("Expected log to be compacted to 2 records, got %d", m.records)
+	}
+
+	// Entries beyond the maximum are evicted.
+	m.maxEntries = 2
+	m.put(metaStoreEntry{Bucket: "bucket", Object: "object3"})
+	if len(m.entries) != 2 {
+		t.Errorf("Expected 2 entries, got %d", len(m.entries))
+	}
+	if _, ok = m.get("bucket", "object3"); !ok {
+		t.Error("Expected the latest entry to be kept")
+	}
+
+	// Records appended while the log is compacted are kept.
+	m.mutex.Lock()
+	m.compacting = true
+	entries := m.liveEntries()
+	m.mutex.Unlock()
+	m.put(metaStoreEntry{Bucket: "bucket", Object: "object4"})
+	if err = m.compact(entries); err != nil {
+		t.Fatal(err)
+	}
+	if m.compacting || m.pending != nil {
+		t.Error("Expected compaction to be done")
+	}
+	m.Close()
+	m, err = newMetaStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	for _, object := range []string{"object3", "object4"} {
+		if _, ok = m.get("bucket", object); !ok {
+			t.Errorf("Expected %s after compaction and reopen", object)
+		}
+	}
+}
+
+// Tests object info is served from the metadata store until the object
+// is written.
+func TestXLMetaStore(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	dir, err := ioutil.TempDir(globalTestTmpDir, "minio-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(dir)
+	store, err := newMetaStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	defer func(m *metaStore) { globalMetaStore = m }(globalMetaStore)
+	globalMetaStore = store
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("hello")
+	if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), map[string]string{"content-type": "text/plain"}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// First lookup misses, the second is served by the store.
+	for i := 0; i < 2; i++ {
+		objInfo, err := obj.GetObjectInfo(bucket, object)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if objInfo.ContentType != "text/plain" || objInfo.Size != int64(len(data)) || objInfo.MD5Sum == "" {
+			t.Errorf("Lookup %d: unexpected object info %#v", i+1, objInfo)
+		}
+	}
+	if hits, misses := store.lookupStats(); hits != 1 || misses != 1 {
+		t.Errorf("Expected 1 hit and 1 miss, got %d and %d", hits, misses)
+	}
+
+	// Cached entries are trusted without reading the disks.
+	entry, ok := store.get(bucket, object)
+	if !ok {
+		t.Fatal("Expected object to be cached")
+	}
+	entry.Meta = map[string]string{"content-type": "cached"}
+	cache := func() {
+		store.put(entry)
+		if objInfo, _ := xl.getObjectInfo(bucket, object); objInfo.ContentType != "cached" {
+			t.Fatalf("Expected cached content type, got %s", objInfo.ContentType)
+		}
+	}
+	cache()
+
+	// Invalidation sent by a peer after a write through it.
+	bms := &localBucketMetaState{ObjectAPI: newObjectLayerFn}
+	if err = bms.InvalidateObjectMeta(&InvalidateObjectMetaPeerArgs{Bucket: bucket, Object: object}); err != nil {
+		t.Fatal(err)
+	}
+	if objInfo, _ := xl.getObjectInfo(bucket, object); objInfo.ContentType != "text/plain" {
+		t.Errorf("Expected content type from disks after peer invalidation, got %s", objInfo.ContentType)
+	}
+
+	// Healing rewrites `xl.json`.
+	cache()
+	if err = obj.HealObject(bucket, object); err != nil {
+		t.Fatal(err)
+	}
+	if objInfo, _ := xl.getObjectInfo(bucket, object); objInfo.ContentType != "text/plain" {
+		t.Errorf("Expected content type from disks after heal, got %s", objInfo.ContentType)
+	}
+
+	// Overwrites and deletes are reflected.
+	if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), map[string]string{"content-type": "text/html"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if objInfo, _ := obj.GetObjectInfo(bucket, object); objInfo.ContentType != "text/html" {
+		t.Errorf("Expected overwritten content type, got %s", objInfo.ContentType)
+	}
+	if err = obj.DeleteObject(bucket, object); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.GetObjectInfo(bucket, object); !isErrObjectNotFound(err) {
+		t.Errorf("Expected ObjectNotFound, got %v", err)
+	}
+	if _, ok = store.get(bucket, object); ok {
+		t.Error("Expected deleted object to be removed from the store")
+	}
+}
@@ -330,7 +330,8 @@ func (xl xlObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 		return "", err
 	}
 
-	onlineDisks = getOrderedDisks(xlMeta.Erasure.Distribution, onlineDisks)
+	// Avoided disks do not receive any new shards.
+	onlineDisks = getOrderedDisks(xlMeta.Erasure.Distribution, excludeAvoidedDisks(onlineDisks))
 	_ = getOrderedPartsMetadata(xlMeta.Erasure.Distribution, partsMetadata)
 
 	// Need a unique name for the part being written in minioMetaBucket to
@@ -379,7 +380,9 @@ func (xl xlObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 		}
 	}
 
-	// Erasure code data and write across all disks.
+	// Erasure code data and write across all disks. Unlike PutObject
+	// laggard disks are not skipped, the upload is not an object until
+	// completed so a part missing on a disk could not be healed.
 	sizeWritten, checkSums, err := erasureCreateFile(onlineDisks, minioMetaTmpBucket, tmpPartPath, teeReader, xlMeta.Erasure.BlockSize, xl.dataBlocks, xl.parityBlocks, bitRotAlgo, xl.writeQuorum)
 	if err != nil {
 		return "", toObjectErr(err, bucket, object)
@@ -704,6 +707,9 @@ func (xl xlObjects) CompleteMultipartUpload(bucket string, object string, upload
 		return "", toObjectErr(rErr, minioMetaMultipartBucket, uploadIDPath)
 	}
 
+	// Cached metadata of a previous version is stale now.
+	xl.invalidateMeta(bucket, object)
+
 	defer func() {
 		if xl.objCacheEnabled {
 			// A new complete multipart upload invalidates any
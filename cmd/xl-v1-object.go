@@ -76,10 +76,14 @@ func (xl xlObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 	cpMetadataOnly := strings.EqualFold(pathJoin(srcBucket, srcObject), pathJoin(dstBucket, dstObject))
 	if cpMetadataOnly {
 		xlMeta.Meta = metadata
-		partsMetadata := make([]xlMetaV1, len(xl.storageDisks))
-		// Update `xl.json` content on each disks.
+		// Each disk keeps its own `xl.json`, which holds the erasure
+		// index and checksums of its shards, only metadata is updated.
+		partsMetadata := getOrderedPartsMetadata(xlMeta.Erasure.Distribution, metaArr)
 		for index := range partsMetadata {
-			partsMetadata[index] = xlMeta
+			if onlineDisks[index] == nil {
+				continue
+			}
+			partsMetadata[index].Meta = metadata
 		}
 
 		tempObj := mustGetUUID()
@@ -92,13 +96,15 @@ func (xl xlObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 		if err = renameXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, srcBucket, srcObject, xl.writeQuorum); err != nil {
 			return ObjectInfo{}, toObjectErr(err, srcBucket, srcObject)
 		}
+		// Cached metadata is stale now.
+		xl.invalidateMeta(srcBucket, srcObject)
 
 		objInfo := ObjectInfo{
 			IsDir:           false,
 			Bucket:          srcBucket,
 			Name:            srcObject,
 			Size:            xlMeta.Stat.Size,
-			ModTime:         xlMeta.Stat.ModTime,
+			ModTime:         modTimeFromMetadata(xlMeta.Meta, xlMeta.Stat.ModTime),
 			MD5Sum:          xlMeta.Meta["md5Sum"],
 			ContentType:     xlMeta.Meta["content-type"],
 			ContentEncoding: xlMeta.Meta["content-encoding"],
@@ -247,11 +253,25 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 		}
 	}
 
+	// Read ahead blocks of sequential reads spanning more than a
+	// block while the client consumes the current one.
+	// Served without read-ahead if the read-ahead pool is exhausted.
+	var readAhead *readAheadWriter
+	if globalReadAheadBlocks > 0 && length > xlMeta.Erasure.BlockSize {
+		if readAhead = newReadAheadWriter(mw, globalReadAheadBlocks, xlMeta.Erasure.BlockSize); readAhead != nil {
+			defer readAhead.Close()
+			mw = readAhead
+		}
+	}
+
 	totalBytesRead := int64(0)
 
 	chunkSize := getChunkSize(xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks)
 	pool := bpool.NewBytePool(chunkSize, len(onlineDisks))
 
+	// Prefer reading shards from disks in the local zone.
+	preferred := globalEndpointZones.preferredDisks(onlineDisks)
+
 	// Read from all parts.
 	for ; partIndex <= lastPartIndex; partIndex++ {
 		if length == totalBytesRead {
@@ -287,7 +307,7 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 		}
 
 		// Start erasure decoding and writing to the client.
-		n, err := erasureReadFile(mw, onlineDisks, bucket, pathJoin(object, partName), partOffset, readSize, partSize, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks, checkSums, ckSumAlgo, pool)
+		n, err := erasureReadFilePreferred(mw, onlineDisks, bucket, pathJoin(object, partName), partOffset, readSize, partSize, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks, checkSums, ckSumAlgo, pool, preferred)
 		if err != nil {
 			errorIf(err, "Unable to read %s of the object `%s/%s`.", partName, bucket, object)
 			return toObjectErr(err, bucket, object)
@@ -301,38 +321,62 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 		partOffset = 0
 	} // End of read all parts loop.
 
+	// Write out blocks read ahead.
+	if readAhead != nil {
+		if err = readAhead.Close(); err != nil {
+			return traceError(err)
+		}
+	}
+
 	// Return success.
 	return nil
 }
 
 // GetObjectInfo - reads object metadata and replies back ObjectInfo.
+// Only `xl.json` is read from all disks and the latest metadata agreed
+// upon by a read quorum of disks is returned, data shards are never
+// read.
 func (xl xlObjects) GetObjectInfo(bucket, object string) (ObjectInfo, error) {
 	if err := checkGetObjArgs(bucket, object); err != nil {
 		return ObjectInfo{}, err
 	}
 
-	info, err := xl.getObjectInfo(bucket, object)
-	if err != nil {
-		return ObjectInfo{}, toObjectErr(err, bucket, object)
-	}
-	return info, nil
+	return xl.readObjectInfoCached(bucket, object, func() (statInfo, map[string]string, error) {
+		// Read metadata associated with the object from all disks.
+		metaArr, errs := readAllXLMetadata(xl.storageDisks, bucket, object)
+		if reducedErr := reduceReadQuorumErrs(errs, objectOpIgnoredErrs, xl.readQuorum); reducedErr != nil {
+			return statInfo{}, nil, toObjectErr(reducedErr, bucket, object)
+		}
+
+		// List all online disks.
+		_, modTime := listOnlineDisks(xl.storageDisks, metaArr, errs)
+
+		// Pick latest valid metadata.
+		xlMeta, err := pickValidXLMeta(metaArr, modTime)
+		if err != nil {
+			return statInfo{}, nil, toObjectErr(err, bucket, object)
+		}
+		return xlMeta.Stat, xlMeta.Meta, nil
+	})
 }
 
 // getObjectInfo - wrapper for reading object metadata and constructs ObjectInfo.
 func (xl xlObjects) getObjectInfo(bucket, object string) (objInfo ObjectInfo, err error) {
 	// returns xl meta map and stat info.
-	xlStat, xlMetaMap, err := xl.readXLMetaStat(bucket, object)
-	if err != nil {
-		// Return error.
-		return ObjectInfo{}, err
-	}
+	return xl.readObjectInfoCached(bucket, object, func() (statInfo, map[string]string, error) {
+		return xl.readXLMetaStat(bucket, object)
+	})
+}
 
-	objInfo = ObjectInfo{
+// newXLObjectInfo - constructs ObjectInfo from `xl.json` stat info
+// and metadata.
+func newXLObjectInfo(bucket, object string, xlStat statInfo, xlMetaMap map[string]string) ObjectInfo {
+	objInfo := ObjectInfo{
 		IsDir:           false,
 		Bucket:          bucket,
 		Name:            object,
 		Size:            xlStat.Size,
-		ModTime:         xlStat.ModTime,
+		ModTime:         modTimeFromMetadata(xlMetaMap, xlStat.ModTime),
 		MD5Sum:          xlMetaMap["md5Sum"],
 		ContentType:     xlMetaMap["content-type"],
 		ContentEncoding: xlMetaMap["content-encoding"],
@@ -344,7 +388,7 @@ func (xl xlObjects) getObjectInfo(bucket, object string) (objInfo ObjectInfo, er
 
 	delete(xlMetaMap, "md5Sum")
 	objInfo.UserDefined = xlMetaMap
-	return objInfo, nil
+	return objInfo
 }
 
 func undoRename(disks []StorageAPI, srcBucket, srcEntry, dstBucket, dstEntry string, isDir bool, errs []error) {
@@ -503,7 +547,8 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 	// Initialize xl meta.
 	xlMeta := newXLMetaV1(object, xl.dataBlocks, xl.parityBlocks)
 
-	onlineDisks := getOrderedDisks(xlMeta.Erasure.Distribution, xl.storageDisks)
+	// Avoided disks do not receive any new shards.
+	onlineDisks := getOrderedDisks(xlMeta.Erasure.Distribution, excludeAvoidedDisks(xl.storageDisks))
 
 	// Delete temporary object in the event of failure. If
 	// PutObject succeeded there would be no temporary object to
@@ -519,11 +564,20 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 		}
 	}
 
-	// Erasure code data and write across all disks.
-	sizeWritten, checkSums, err := erasureCreateFile(onlineDisks, minioMetaTmpBucket, tempErasureObj, teeReader, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks, bitRotAlgo, xl.writeQuorum)
+	// Erasure code data and write across all disks, laggard disks
+	// are skipped if requested and healed once the object is written.
+	laggardWrites := &sync.WaitGroup{}
+	sizeWritten, checkSums, writtenDisks, err := erasureCreateFileSkipLaggards(onlineDisks, minioMetaTmpBucket, tempErasureObj, teeReader, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks, bitRotAlgo, xl.writeQuorum, globalWriteLaggardTimeout, laggardWrites)
 	if err != nil {
 		return ObjectInfo{}, toObjectErr(err, minioMetaTmpBucket, tempErasureObj)
 	}
+	var laggardDisks []StorageAPI
+	for index, disk := range onlineDisks {
+		if disk != nil && writtenDisks[index] == nil {
+			laggardDisks = append(laggardDisks, disk)
+		}
+	}
+	onlineDisks = writtenDisks
 	// Should return IncompleteBody{} error when reader has fewer bytes
 	// than specified in request header.
 	if sizeWritten < size {
@@ -621,6 +675,20 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 		return ObjectInfo{}, toObjectErr(err, bucket, object)
 	}
 
+	// Heal shards of the laggard disks in background.
+	if len(laggardDisks) > 0 {
+		xl.laggards.enqueue(xl, laggardHealEntry{
+			bucket:    bucket,
+			object:    object,
+			tmpObject: tempObj,
+			disks:     laggardDisks,
+			writes:    laggardWrites,
+		})
+	}
+
+	// Cached metadata of a previous version is stale now.
+	xl.invalidateMeta(bucket, object)
+
 	// Once we have successfully renamed the object, Close the buffer which would
 	// save the object on cache.
 	if size > 0 && xl.objCacheEnabled && newBuffer != nil {
@@ -632,7 +700,7 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 		Bucket:          bucket,
 		Name:            object,
 		Size:            xlMeta.Stat.Size,
-		ModTime:         xlMeta.Stat.ModTime,
+		ModTime:         modTimeFromMetadata(xlMeta.Meta, xlMeta.Stat.ModTime),
 		MD5Sum:          xlMeta.Meta["md5Sum"],
 		ContentType:     xlMeta.Meta["content-type"],
 		ContentEncoding: xlMeta.Meta["content-encoding"],
@@ -691,16 +759,28 @@ func (xl xlObjects) DeleteObject(bucket, object string) (err error) {
 		return traceError(ObjectNotFound{bucket, object})
 	} // else proceed to delete the object.
 
-	// Delete the object on all disks.
-	err = xl.deleteObject(bucket, object)
-	if err != nil {
-		return toObjectErr(err, bucket, object)
+	if globalAsyncDelete {
+		// Move the object to trash on all disks, it is not visible
+		// to reads and listings anymore. Its shards are reclaimed
+		// in background.
+		err = renameObject(xl.storageDisks, bucket, object, minioMetaTrashBucket, mustGetUUID(), xl.writeQuorum)
+		if err != nil {
+			return toObjectErr(err, bucket, object)
+		}
+		xl.trash.trigger(xl.storageDisks)
+	} else {
+		// Delete the object on all disks.
+		err = xl.deleteObject(bucket, object)
+		if err != nil {
+			return toObjectErr(err, bucket, object)
+		}
 	}
 
 	if xl.objCacheEnabled {
 		// Delete from the cache.
 		xl.objCache.Delete(pathJoin(bucket, object))
 	}
+	xl.invalidateMeta(bucket, object)
 
 	// Success.
 	return nil
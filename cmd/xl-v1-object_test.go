@@ -23,6 +23,7 @@ import (
 	"os"
 	"path"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -362,3 +363,128 @@ func TestHealing(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// readCountingDisk - counts reads of `xl.json` and of data shards.
+type readCountingDisk struct {
+	StorageAPI
+	metaReads  int32
+	shardReads int32
+}
+
+func (d *readCountingDisk) countRead(filePath string) {
+	if path.Base(filePath) == xlMetaJSONFile {
+		atomic.AddInt32(&d.metaReads, 1)
+	} else {
+		atomic.AddInt32(&d.shardReads, 1)
+	}
+}
+
+func (d *readCountingDisk) ReadFile(volume string, filePath string, offset int64, buf []byte) (int64, error) {
+	d.countRead(filePath)
+	return d.StorageAPI.ReadFile(volume, filePath, offset, buf)
+}
+
+func (d *readCountingDisk) ReadAll(volume string, filePath string) ([]byte, error) {
+	d.countRead(filePath)
+	return d.StorageAPI.ReadAll(volume, filePath)
+}
+
+// Tests GetObjectInfo reads `xl.json` from a quorum of disks and
+// never reads data shards.
+func TestGetObjectInfoNoShardReads(t *testing.T) {
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Repeat([]byte("a"), 1*humanize.MiByte)
+	if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	disks := make([]*readCountingDisk, len(xl.storageDisks))
+	for i := range xl.storageDisks {
+		disks[i] = &readCountingDisk{StorageAPI: xl.storageDisks[i]}
+		xl.storageDisks[i] = disks[i]
+	}
+	// Disks below read quorum are offline.
+	for i := 0; i < len(xl.storageDisks)-xl.readQuorum; i++ {
+		xl.storageDisks[i] = nil
+	}
+
+	objInfo, err := obj.GetObjectInfo(bucket, object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.Size != int64(len(data)) {
+		t.Errorf("Expected size %d, got %d", len(data), objInfo.Size)
+	}
+
+	var metaReads int32
+	for i, disk := range disks {
+		if disk.shardReads != 0 {
+			t.Errorf("Disk %d: expected no data shard reads, got %d", i, disk.shardReads)
+		}
+		metaReads += disk.metaReads
+	}
+	if int(metaReads) < xl.readQuorum {
+		t.Errorf("Expected xl.json to be read from at least %d disks, got %d", xl.readQuorum, metaReads)
+	}
+
+	// One more disk offline, read quorum is lost.
+	xl.storageDisks[len(xl.storageDisks)-xl.readQuorum] = nil
+	if _, err = obj.GetObjectInfo(bucket, object); errorCause(err) != (InsufficientReadQuorum{}) {
+		t.Errorf("Expected %v, got %v", InsufficientReadQuorum{}, err)
+	}
+}
+
+// Tests copying an object onto itself only updates its metadata, each
+// disk keeping the erasure index and checksums of its own shards.
+func TestXLCopyObjectMetadataOnly(t *testing.T) {
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Repeat([]byte("abcd"), 1000)
+	if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	objInfo, err := obj.CopyObject(bucket, object, bucket, object, map[string]string{"content-type": "text/plain"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.ContentType != "text/plain" {
+		t.Errorf("Expected content type text/plain, got %s", objInfo.ContentType)
+	}
+
+	var buffer bytes.Buffer
+	if err = obj.GetObject(bucket, object, 0, int64(len(data)), &buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buffer.Bytes(), data) {
+		t.Error("Expected content to be kept by a metadata only copy")
+	}
+
+	xl := obj.(*xlObjects)
+	metaArr, _ := readAllXLMetadata(xl.storageDisks, bucket, object)
+	indices := make(map[int]bool)
+	for _, xlMeta := range metaArr {
+		indices[xlMeta.Erasure.Index] = true
+	}
+	if len(indices) != len(xl.storageDisks) {
+		t.Errorf("Expected %d distinct erasure indices, got %d", len(xl.storageDisks), len(indices))
+	}
+}
@@ -0,0 +1,196 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// Number of blocks already read ahead when the client asked for them,
+// number of blocks the client had to wait for and number of requests
+// served without read-ahead as the pool was exhausted.
+var readAheadHits, readAheadMisses, readAheadSkips uint64
+
+// readAheadStats - returns read-ahead hits, misses and skips.
+func readAheadStats() (hits, misses, skips uint64) {
+	return atomic.LoadUint64(&readAheadHits), atomic.LoadUint64(&readAheadMisses), atomic.LoadUint64(&readAheadSkips)
+}
+
+// Semaphore of the blocks buffered by read-ahead of all requests,
+// holds a slot per block in use.
+var readAheadPool chan struct{}
+
+// initReadAheadPool - bounds the blocks buffered by read-ahead of all
+// requests to blocks.
+func initReadAheadPool(blocks int) {
+	readAheadPool = make(chan struct{}, blocks)
+}
+
+// acquireReadAheadBlocks - reserves n blocks of the pool without
+// waiting, returns false if fewer than n blocks are free.
+func acquireReadAheadBlocks(n int) bool {
+	for i := 0; i < n; i++ {
+		select {
+		case readAheadPool <- struct{}{}:
+		default:
+			releaseReadAheadBlocks(i)
+			return false
+		}
+	}
+	return true
+}
+
+// releaseReadAheadBlocks - returns n blocks to the pool.
+func releaseReadAheadBlocks(n int) {
+	for i := 0; i < n; i++ {
+		<-readAheadPool
+	}
+}
+
+// readAheadWriter - decouples reading erasure blocks from disks from
+// writing them to the client. Data written is buffered into blocks of
+// blockSize, up to a window of blocks are read ahead while the client
+// consumes the current one. Memory is bounded by window + 2 blocks,
+// reserved from the read-ahead pool until closed.
+type readAheadWriter struct {
+	writer    io.Writer
+	blockSize int64
+	// Blocks reserved from the read-ahead pool.
+	reserved int
+
+	// Block being filled.
+	buf []byte
+	// Blocks read ahead, waiting to be written to the client.
+	blocks chan []byte
+	// Recycled blocks, a nil entry allows allocating a new block.
+	free chan []byte
+	// Closed when the client writer is done, err is set if it failed.
+	doneCh chan struct{}
+	err    error
+	closed bool
+}
+
+// newReadAheadWriter - returns a writer reading ahead window blocks of
+// blockSize before they are written to writer. Close must be called
+// to write out the remaining data and release its blocks. Returns nil
+// if the read-ahead pool has not enough blocks free, the request is
+// then served without read-ahead.
+func newReadAheadWriter(writer io.Writer, window int, blockSize int64) *readAheadWriter {
+	if !acquireReadAheadBlocks(window + 2) {
+		atomic.AddUint64(&readAheadSkips, 1)
+		return nil
+	}
+	r := &readAheadWriter{
+		writer:    writer,
+		blockSize: blockSize,
+		reserved:  window + 2,
+		blocks:    make(chan []byte, window),
+		free:      make(chan []byte, window+2),
+		doneCh:    make(chan struct{}),
+	}
+	for i := 0; i < window+2; i++ {
+		r.free <- nil
+	}
+	go r.writeBlocks()
+	return r
+}
+
+// writeBlocks - writes blocks read ahead to the client, accounting
+// whether each block was ready when the client asked for it.
+func (r *readAheadWriter) writeBlocks() {
+	defer close(r.doneCh)
+	for {
+		var buf []byte
+		var ok bool
+		select {
+		case buf, ok = <-r.blocks:
+			if ok {
+				atomic.AddUint64(&readAheadHits, 1)
+			}
+		default:
+			// Next block is still being read.
+			if buf, ok = <-r.blocks; ok {
+				atomic.AddUint64(&readAheadMisses, 1)
+			}
+		}
+		if !ok {
+			return
+		}
+		if _, err := r.writer.Write(buf); err != nil {
+			r.err = err
+			return
+		}
+		r.free <- buf[:0]
+	}
+}
+
+// send - queues the filled block to be written to the client.
+func (r *readAheadWriter) send() error {
+	select {
+	case r.blocks <- r.buf:
+		r.buf = nil
+		return nil
+	case <-r.doneCh:
+		return r.err
+	}
+}
+
+// Write - buffers p into blocks, blocks only if the window is full.
+func (r *readAheadWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		if r.buf == nil {
+			select {
+			case r.buf = <-r.free:
+			case <-r.doneCh:
+				return n, r.err
+			}
+			if r.buf == nil {
+				r.buf = make([]byte, 0, r.blockSize)
+			}
+		}
+		copied := copy(r.buf[len(r.buf):cap(r.buf)], p)
+		r.buf = r.buf[:len(r.buf)+copied]
+		p = p[copied:]
+		n += copied
+		if int64(len(r.buf)) == r.blockSize {
+			if err = r.send(); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// Close - writes out remaining data and waits for the client writer,
+// returns the error writing to the client if any. Blocks reserved
+// from the pool are released. Safe to be called more than once.
+func (r *readAheadWriter) Close() error {
+	if r.closed {
+		return r.err
+	}
+	r.closed = true
+	defer releaseReadAheadBlocks(r.reserved)
+	if len(r.buf) > 0 {
+		if err := r.send(); err != nil {
+			return err
+		}
+	}
+	close(r.blocks)
+	<-r.doneCh
+	return r.err
+}
@@ -0,0 +1,208 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Tests data written through read-ahead reaches the writer unchanged.
+func TestReadAheadWriter(t *testing.T) {
+	initReadAheadPool(10)
+	data := bytes.Repeat([]byte("abcdefghijklm"), 1000)
+	testCases := []struct {
+		window    int
+		blockSize int64
+		writeSize int
+	}{
+		{1, 1000, 100},
+		{2, 1000, 333},
+		{4, 1000, 2500},
+		{2, 13000, 13000},
+		{8, 7, 1},
+	}
+	for i, testCase := range testCases {
+		hits, misses, _ := readAheadStats()
+		var buf bytes.Buffer
+		w := newReadAheadWriter(&buf, testCase.window, testCase.blockSize)
+		for offset := 0; offset < len(data); offset += testCase.writeSize {
+			end := offset + testCase.writeSize
+			if end > len(data) {
+				end = len(data)
+			}
+			n, err := w.Write(data[offset:end])
+			if err != nil {
+				t.Fatalf("Test %d: %s", i+1, err)
+			}
+			if n != end-offset {
+				t.Fatalf("Test %d: expected %d bytes written, got %d", i+1, end-offset, n)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if !bytes.Equal(buf.Bytes(), data) {
+			t.Fatalf("Test %d: data mismatch", i+1)
+		}
+		newHits, newMisses, _ := readAheadStats()
+		blocks := (int64(len(data)) + testCase.blockSize - 1) / testCase.blockSize
+		if written := int64(newHits - hits + newMisses - misses); written != blocks {
+			t.Errorf("Test %d: expected %d blocks accounted, got %d", i+1, blocks, written)
+		}
+	}
+}
+
+// Writer failing after a number of bytes.
+type failingWriter struct {
+	n int
+}
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	if f.n < len(p) {
+		return 0, errors.New("client went away")
+	}
+	f.n -= len(p)
+	return len(p), nil
+}
+
+// Tests errors writing to the client are returned to the reader.
+func TestReadAheadWriterError(t *testing.T) {
+	initReadAheadPool(4)
+	w := newReadAheadWriter(&failingWriter{n: 10}, 2, 10)
+	var err error
+	for i := 0; i < 100 && err == nil; i++ {
+		_, err = w.Write(make([]byte, 10))
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err == nil || err.Error() != "client went away" {
+		t.Fatalf("Expected client error, got %v", err)
+	}
+	if err = w.Close(); err == nil {
+		t.Fatal("Expected client error on repeated close")
+	}
+	if len(readAheadPool) != 0 {
+		t.Fatalf("Expected all blocks released, %d in use", len(readAheadPool))
+	}
+}
+
+// Tests requests are served without read-ahead once the pool is
+// exhausted.
+func TestReadAheadWriterPoolExhausted(t *testing.T) {
+	initReadAheadPool(7)
+
+	var buf bytes.Buffer
+	w := newReadAheadWriter(&buf, 2, 10)
+	if w == nil {
+		t.Fatal("Expected read-ahead with enough blocks free")
+	}
+	// Only 3 blocks left, 4 are needed.
+	_, _, skips := readAheadStats()
+	if newReadAheadWriter(&buf, 2, 10) != nil {
+		t.Fatal("Expected no read-ahead with the pool exhausted")
+	}
+	if _, _, newSkips := readAheadStats(); newSkips != skips+1 {
+		t.Fatalf("Expected 1 request skipped, got %d", newSkips-skips)
+	}
+	if len(readAheadPool) != 4 {
+		t.Fatalf("Expected 4 blocks in use, got %d", len(readAheadPool))
+	}
+
+	// Blocks are released once closed.
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	w = newReadAheadWriter(&buf, 2, 10)
+	if w == nil {
+		t.Fatal("Expected read-ahead once blocks are released")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(readAheadPool) != 0 {
+		t.Fatalf("Expected all blocks released, %d in use", len(readAheadPool))
+	}
+}
+
+// Tests ranged GetObject with read-ahead enabled.
+func TestGetObjectReadAhead(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	defer func(blocks int) { globalReadAheadBlocks = blocks }(globalReadAheadBlocks)
+	globalReadAheadBlocks = 2
+	initReadAheadPool(4)
+
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Repeat([]byte("0123456789abcdef"), 3*blockSizeV1/16+1000)
+	if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		offset, length int64
+	}{
+		{0, int64(len(data))},
+		{1, int64(len(data)) - 1},
+		{blockSizeV1 - 5, blockSizeV1 + 10},
+		{humanize.MiByte, 2 * blockSizeV1},
+		{int64(len(data)) - blockSizeV1 - 1, blockSizeV1 + 1},
+	}
+	for i, testCase := range testCases {
+		var buf bytes.Buffer
+		if err = obj.GetObject(bucket, object, testCase.offset, testCase.length, &buf); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if !bytes.Equal(buf.Bytes(), data[testCase.offset:testCase.offset+testCase.length]) {
+			t.Errorf("Test %d: data mismatch", i+1)
+		}
+	}
+	if len(readAheadPool) != 0 {
+		t.Fatalf("Expected all blocks released, %d in use", len(readAheadPool))
+	}
+
+	// Requests are served without read-ahead with the pool exhausted.
+	initReadAheadPool(0)
+	_, _, skips := readAheadStats()
+	var buf bytes.Buffer
+	if err = obj.GetObject(bucket, object, 0, int64(len(data)), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Error("Data mismatch without read-ahead")
+	}
+	if _, _, newSkips := readAheadStats(); newSkips != skips+1 {
+		t.Fatalf("Expected 1 request served without read-ahead, got %d", newSkips-skips)
+	}
+}
@@ -0,0 +1,482 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"hash/crc32"
+	"io"
+	"sort"
+)
+
+// getErasureSetCount - returns number of erasure sets of at most
+// maxErasureBlocks disks each a number of disks is grouped into.
+func getErasureSetCount(disks int) int {
+	return (disks + maxErasureBlocks - 1) / maxErasureBlocks
+}
+
+// getErasureSets - splits disks into erasure sets of equal size.
+func getErasureSets(storageDisks []StorageAPI) [][]StorageAPI {
+	setCount := getErasureSetCount(len(storageDisks))
+	setSize := len(storageDisks) / setCount
+	sets := make([][]StorageAPI, setCount)
+	for i := range sets {
+		sets[i] = storageDisks[i*setSize : (i+1)*setSize]
+	}
+	return sets
+}
+
+// xlSets - implements object layer on more disks than a single
+// erasure set supports. Disks are grouped into erasure sets, buckets
+// are created on all sets and every object lives on the set its name
+// hashes to.
+type xlSets struct {
+	sets []*xlObjects
+}
+
+// newXLSets - initialize xl object layers on each erasure set of
+// formatted disks.
+func newXLSets(storageDisks []StorageAPI, parityBlocks int) (ObjectLayer, error) {
+	s := &xlSets{}
+	for _, setDisks := range getErasureSets(storageDisks) {
+		objAPI, err := newXLObjects(setDisks, parityBlocks)
+		if err != nil {
+			return nil, err
+		}
+		s.sets = append(s.sets, objAPI.(*xlObjects))
+	}
+	return s, nil
+}
+
+// getHashedSet - returns erasure set an object is placed on.
+func (s xlSets) getHashedSet(bucket, object string) *xlObjects {
+	key := pathJoin(bucket, object)
+	return s.sets[crc32.ChecksumIEEE([]byte(key))%uint32(len(s.sets))]
+}
+
+// Shutdown - shuts down all erasure sets.
+func (s xlSets) Shutdown() error {
+	for _, set := range s.sets {
+		if err := set.Shutdown(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StorageInfo - returns storage statistics summed up over all
+// erasure sets, quorums are those of a single set.
+func (s xlSets) StorageInfo() StorageInfo {
+	storageInfo := StorageInfo{Total: -1, Free: -1}
+	for i, set := range s.sets {
+		setInfo := set.StorageInfo()
+		if i == 0 {
+			storageInfo.Backend = setInfo.Backend
+			storageInfo.Backend.OnlineDisks = 0
+			storageInfo.Backend.OfflineDisks = 0
+			storageInfo.Backend.AvoidedDisks = 0
+			storageInfo.Backend.PendingHeals = 0
+		}
+		// A set without any disk online adds no space.
+		if setInfo.Total >= 0 {
+			if storageInfo.Total < 0 {
+				storageInfo.Total, storageInfo.Free = 0, 0
+			}
+			storageInfo.Total += setInfo.Total
+			storageInfo.Free += setInfo.Free
+			storageInfo.RawTotal += setInfo.RawTotal
+			storageInfo.RawFree += setInfo.RawFree
+		}
+		storageInfo.Disks = append(storageInfo.Disks, setInfo.Disks...)
+		storageInfo.Backend.OnlineDisks += setInfo.Backend.OnlineDisks
+		storageInfo.Backend.OfflineDisks += setInfo.Backend.OfflineDisks
+		storageInfo.Backend.AvoidedDisks += setInfo.Backend.AvoidedDisks
+		storageInfo.Backend.PendingHeals += setInfo.Backend.PendingHeals
+	}
+	return storageInfo
+}
+
+/// Bucket operations
+
+// MakeBucket - creates a bucket on all erasure sets, undoing it on
+// sets it was created on if any fails.
+func (s xlSets) MakeBucket(bucket string) error {
+	for i, set := range s.sets {
+		if err := set.MakeBucket(bucket); err != nil {
+			for _, created := range s.sets[:i] {
+				created.DeleteBucket(bucket)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// GetBucketInfo - returns bucket info from the first erasure set.
+func (s xlSets) GetBucketInfo(bucket string) (BucketInfo, error) {
+	return s.sets[0].GetBucketInfo(bucket)
+}
+
+// ListBuckets - lists buckets of the first erasure set.
+func (s xlSets) ListBuckets() ([]BucketInfo, error) {
+	return s.sets[0].ListBuckets()
+}
+
+// DeleteBucket - deletes a bucket on all erasure sets, only if it is
+// empty on all of them.
+func (s xlSets) DeleteBucket(bucket string) error {
+	for _, set := range s.sets {
+		result, err := set.ListObjects(bucket, "", "", "", 1)
+		if err != nil {
+			return err
+		}
+		if len(result.Objects) > 0 || len(result.Prefixes) > 0 {
+			return traceError(BucketNotEmpty{Bucket: bucket})
+		}
+	}
+	for i, set := range s.sets {
+		if err := set.DeleteBucket(bucket); err != nil {
+			for _, deleted := range s.sets[:i] {
+				deleted.MakeBucket(bucket)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// setListEntry - object or prefix listed by an erasure set.
+type setListEntry struct {
+	name     string
+	objInfo  ObjectInfo
+	isPrefix bool
+}
+
+// byListEntryName is a collection satisfying sort.Interface.
+type byListEntryName []setListEntry
+
+func (e byListEntryName) Len() int           { return len(e) }
+func (e byListEntryName) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
+func (e byListEntryName) Less(i, j int) bool { return e[i].name < e[j].name }
+
+// mergeListObjects - merges listings of erasure sets into a single
+// listing of at most maxKeys entries. Each set lists up to maxKeys
+// entries past the marker, so the first maxKeys of the merged entries
+// are the first maxKeys overall.
+func mergeListObjects(results []ListObjectsInfo, maxKeys int) ListObjectsInfo {
+	var entries []setListEntry
+	prefixes := make(map[string]bool)
+	var merged ListObjectsInfo
+	for _, result := range results {
+		merged.IsTruncated = merged.IsTruncated || result.IsTruncated
+		for _, objInfo := range result.Objects {
+			entries = append(entries, setListEntry{name: objInfo.Name, objInfo: objInfo})
+		}
+		for _, prefix := range result.Prefixes {
+			if prefixes[prefix] {
+				continue
+			}
+			prefixes[prefix] = true
+			entries = append(entries, setListEntry{name: prefix, isPrefix: true})
+		}
+	}
+	sort.Sort(byListEntryName(entries))
+	if len(entries) > maxKeys {
+		entries = entries[:maxKeys]
+		merged.IsTruncated = true
+	}
+	for _, entry := range entries {
+		if entry.isPrefix {
+			merged.Prefixes = append(merged.Prefixes, entry.name)
+		} else {
+			merged.Objects = append(merged.Objects, entry.objInfo)
+		}
+	}
+	if merged.IsTruncated && len(entries) > 0 {
+		merged.NextMarker = entries[len(entries)-1].name
+	}
+	return merged
+}
+
+// listObjects - lists objects across all erasure sets with list
+// function of a set.
+func (s xlSets) listObjects(maxKeys int, list func(set *xlObjects) (ListObjectsInfo, error)) (ListObjectsInfo, error) {
+	var results []ListObjectsInfo
+	for _, set := range s.sets {
+		result, err := list(set)
+		if err != nil {
+			return ListObjectsInfo{}, err
+		}
+		results = append(results, result)
+	}
+	if maxKeys < 0 || maxKeys > maxObjectList {
+		maxKeys = maxObjectList
+	}
+	return mergeListObjects(results, maxKeys), nil
+}
+
+// ListObjects - lists objects of a bucket across all erasure sets.
+func (s xlSets) ListObjects(bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
+	return s.listObjects(maxKeys, func(set *xlObjects) (ListObjectsInfo, error) {
+		return set.ListObjects(bucket, prefix, marker, delimiter, maxKeys)
+	})
+}
+
+/// Object operations
+
+// GetObject - reads an object from its erasure set.
+func (s xlSets) GetObject(bucket, object string, startOffset int64, length int64, writer io.Writer) error {
+	return s.getHashedSet(bucket, object).GetObject(bucket, object, startOffset, length, writer)
+}
+
+// GetObjectInfo - reads object metadata from its erasure set.
+func (s xlSets) GetObjectInfo(bucket, object string) (ObjectInfo, error) {
+	return s.getHashedSet(bucket, object).GetObjectInfo(bucket, object)
+}
+
+// PutObject - writes an object to its erasure set.
+func (s xlSets) PutObject(bucket, object string, size int64, data io.Reader, metadata map[string]string, sha256sum string) (ObjectInfo, error) {
+	return s.getHashedSet(bucket, object).PutObject(bucket, object, size, data, metadata, sha256sum)
+}
+
+// CopyObject - copies an object, objects on different erasure sets
+// are streamed from the source to the destination set.
+func (s xlSets) CopyObject(srcBucket, srcObject, destBucket, destObject string, metadata map[string]string) (ObjectInfo, error) {
+	srcSet := s.getHashedSet(srcBucket, srcObject)
+	destSet := s.getHashedSet(destBucket, destObject)
+	if srcSet == destSet {
+		return srcSet.CopyObject(srcBucket, srcObject, destBucket, destObject, metadata)
+	}
+
+	srcInfo, err := srcSet.GetObjectInfo(srcBucket, srcObject)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		pipeWriter.CloseWithError(srcSet.GetObject(srcBucket, srcObject, 0, srcInfo.Size, pipeWriter))
+	}()
+	objInfo, err := destSet.PutObject(destBucket, destObject, srcInfo.Size, pipeReader, metadata, "")
+	// Unblocks the reader if the write failed early.
+	pipeReader.Close()
+	return objInfo, err
+}
+
+// DeleteObject - deletes an object from its erasure set.
+func (s xlSets) DeleteObject(bucket, object string) error {
+	return s.getHashedSet(bucket, object).DeleteObject(bucket, object)
+}
+
+// TruncateObject - truncates an object on its erasure set.
+func (s xlSets) TruncateObject(bucket, object string, size int64) (ObjectInfo, error) {
+	return s.getHashedSet(bucket, object).TruncateObject(bucket, object, size)
+}
+
+/// Multipart operations
+
+// setUploadEntry - multipart upload or common prefix listed by an
+// erasure set.
+type setUploadEntry struct {
+	upload   uploadMetadata
+	isPrefix bool
+}
+
+// byUploadEntryName is a collection satisfying sort.Interface, uploads
+// of an object are ordered by their initiation.
+type byUploadEntryName []setUploadEntry
+
+func (e byUploadEntryName) Len() int      { return len(e) }
+func (e byUploadEntryName) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+func (e byUploadEntryName) Less(i, j int) bool {
+	if e[i].upload.Object != e[j].upload.Object {
+		return e[i].upload.Object < e[j].upload.Object
+	}
+	return e[i].upload.Initiated.Before(e[j].upload.Initiated)
+}
+
+// ListMultipartUploads - lists multipart uploads of a bucket across
+// all erasure sets, merged the same way as object listings.
+func (s xlSets) ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (ListMultipartsInfo, error) {
+	var entries []setUploadEntry
+	prefixes := make(map[string]bool)
+	merged := ListMultipartsInfo{
+		KeyMarker:      keyMarker,
+		UploadIDMarker: uploadIDMarker,
+		MaxUploads:     maxUploads,
+		Prefix:         prefix,
+		Delimiter:      delimiter,
+	}
+	for _, set := range s.sets {
+		result, err := set.ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter, maxUploads)
+		if err != nil {
+			return ListMultipartsInfo{}, err
+		}
+		merged.IsTruncated = merged.IsTruncated || result.IsTruncated
+		for _, upload := range result.Uploads {
+			entries = append(entries, setUploadEntry{upload: upload})
+		}
+		for _, commonPrefix := range result.CommonPrefixes {
+			if prefixes[commonPrefix] {
+				continue
+			}
+			prefixes[commonPrefix] = true
+			entries = append(entries, setUploadEntry{upload: uploadMetadata{Object: commonPrefix}, isPrefix: true})
+		}
+	}
+	sort.Stable(byUploadEntryName(entries))
+	if maxUploads < 0 || maxUploads > maxUploadsList {
+		maxUploads = maxUploadsList
+	}
+	if len(entries) > maxUploads {
+		entries = entries[:maxUploads]
+		merged.IsTruncated = true
+	}
+	for _, entry := range entries {
+		if entry.isPrefix {
+			merged.CommonPrefixes = append(merged.CommonPrefixes, entry.upload.Object)
+		} else {
+			merged.Uploads = append(merged.Uploads, entry.upload)
+		}
+	}
+	if merged.IsTruncated && len(entries) > 0 {
+		last := entries[len(entries)-1]
+		merged.NextKeyMarker = last.upload.Object
+		merged.NextUploadIDMarker = last.upload.UploadID
+	}
+	return merged, nil
+}
+
+// NewMultipartUpload - initiates a multipart upload on the erasure
+// set of the object.
+func (s xlSets) NewMultipartUpload(bucket, object string, metadata map[string]string) (string, error) {
+	return s.getHashedSet(bucket, object).NewMultipartUpload(bucket, object, metadata)
+}
+
+// PutObjectPart - writes a part on the erasure set of the object.
+func (s xlSets) PutObjectPart(bucket, object, uploadID string, partID int, size int64, data io.Reader, md5Hex string, sha256sum string) (string, error) {
+	return s.getHashedSet(bucket, object).PutObjectPart(bucket, object, uploadID, partID, size, data, md5Hex, sha256sum)
+}
+
+// ListObjectParts - lists parts on the erasure set of the object.
+func (s xlSets) ListObjectParts(bucket, object, uploadID string, partNumberMarker int, maxParts int) (ListPartsInfo, error) {
+	return s.getHashedSet(bucket, object).ListObjectParts(bucket, object, uploadID, partNumberMarker, maxParts)
+}
+
+// AbortMultipartUpload - aborts a multipart upload on the erasure set
+// of the object.
+func (s xlSets) AbortMultipartUpload(bucket, object, uploadID string) error {
+	return s.getHashedSet(bucket, object).AbortMultipartUpload(bucket, object, uploadID)
+}
+
+// listObjectParts - lists the parts of a multipart upload whose lock
+// is held by the caller, on the erasure set of the object.
+func (s xlSets) listObjectParts(bucket, object, uploadID string, partNumberMarker, maxParts int) (ListPartsInfo, error) {
+	return s.getHashedSet(bucket, object).listObjectParts(bucket, object, uploadID, partNumberMarker, maxParts)
+}
+
+// isUploadIDExists - returns if a multipart upload exists on the
+// erasure set of the object.
+func (s xlSets) isUploadIDExists(bucket, object, uploadID string) bool {
+	return s.getHashedSet(bucket, object).isUploadIDExists(bucket, object, uploadID)
+}
+
+// abortMultipartUpload - aborts a multipart upload whose lock is held
+// by the caller, on the erasure set of the object.
+func (s xlSets) abortMultipartUpload(bucket, object, uploadID string) error {
+	return s.getHashedSet(bucket, object).abortMultipartUpload(bucket, object, uploadID)
+}
+
+// CompleteMultipartUpload - completes a multipart upload on the
+// erasure set of the object.
+func (s xlSets) CompleteMultipartUpload(bucket, object, uploadID string, uploadedParts []completePart) (string, error) {
+	return s.getHashedSet(bucket, object).CompleteMultipartUpload(bucket, object, uploadID, uploadedParts)
+}
+
+/// Healing operations
+
+// HealBucket - heals a bucket on all erasure sets.
+func (s xlSets) HealBucket(bucket string) error {
+	for _, set := range s.sets {
+		if err := set.HealBucket(bucket); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HealObject - heals an object on its erasure set.
+func (s xlSets) HealObject(bucket, object string) error {
+	return s.getHashedSet(bucket, object).HealObject(bucket, object)
+}
+
+// VerifyHealObject - verifies and heals an object on its erasure set.
+func (s xlSets) VerifyHealObject(bucket, object string) (HealStatus, error) {
+	return s.getHashedSet(bucket, object).VerifyHealObject(bucket, object)
+}
+
+// DrainObject - relocates shards of an object off the avoided disks
+// of its erasure set.
+func (s xlSets) DrainObject(bucket, object string) (bool, error) {
+	return s.getHashedSet(bucket, object).DrainObject(bucket, object)
+}
+
+// ListPendingHeals - lists objects pending heal of all erasure sets.
+func (s xlSets) ListPendingHeals() ([]PendingHealInfo, error) {
+	var infos []PendingHealInfo
+	for _, set := range s.sets {
+		setInfos, err := set.ListPendingHeals()
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, setInfos...)
+	}
+	sort.Sort(byBucketObject(infos))
+	return infos, nil
+}
+
+// ListObjectsHeal - lists objects needing heal across all erasure sets.
+func (s xlSets) ListObjectsHeal(bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
+	return s.listObjects(maxKeys, func(set *xlObjects) (ListObjectsInfo, error) {
+		return set.ListObjectsHeal(bucket, prefix, marker, delimiter, maxKeys)
+	})
+}
+
+// RebuildBucketIndex - rebuilds bucket index of all erasure sets.
+func (s xlSets) RebuildBucketIndex() (BucketIndexReport, error) {
+	var report BucketIndexReport
+	seen := make(map[string]bool)
+	appendUnique := func(list []string, kind string, names []string) []string {
+		for _, name := range names {
+			if seen[kind+name] {
+				continue
+			}
+			seen[kind+name] = true
+			list = append(list, name)
+		}
+		return list
+	}
+	for _, set := range s.sets {
+		setReport, err := set.RebuildBucketIndex()
+		if err != nil {
+			return BucketIndexReport{}, err
+		}
+		report.Buckets = appendUnique(report.Buckets, "b", setReport.Buckets)
+		report.HealedBuckets = appendUnique(report.HealedBuckets, "h", setReport.HealedBuckets)
+		report.OrphanBuckets = appendUnique(report.OrphanBuckets, "o", setReport.OrphanBuckets)
+		report.OrphanMetadata = appendUnique(report.OrphanMetadata, "m", setReport.OrphanMetadata)
+	}
+	return report, nil
+}
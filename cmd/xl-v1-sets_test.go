@@ -0,0 +1,185 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// Tests expansion of ranges in endpoints.
+func TestExpandEndpointEllipses(t *testing.T) {
+	testCases := []struct {
+		ep       string
+		expected []string
+		success  bool
+	}{
+		{"/mnt/disk1", []string{"/mnt/disk1"}, true},
+		{"/mnt/d{1...3}", []string{"/mnt/d1", "/mnt/d2", "/mnt/d3"}, true},
+		{"/mnt/d{08...10}/x", []string{"/mnt/d08/x", "/mnt/d09/x", "/mnt/d10/x"}, true},
+		{"http://host{1...2}/mnt/d{1...2}", []string{
+			"http://host1/mnt/d1", "http://host1/mnt/d2",
+			"http://host2/mnt/d1", "http://host2/mnt/d2",
+		}, true},
+		{"/mnt/d{1..3}", []string{"/mnt/d{1..3}"}, true},
+		{"/mnt/d{3...1}", nil, false},
+	}
+	for i, testCase := range testCases {
+		eps, err := expandEndpointEllipses(testCase.ep)
+		if testCase.success != (err == nil) {
+			t.Errorf("Test %d: unexpected error %v", i+1, err)
+			continue
+		}
+		if !reflect.DeepEqual(eps, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, eps)
+		}
+	}
+}
+
+// Tests objects are spread evenly over erasure sets.
+func TestXLSetsDistribution(t *testing.T) {
+	s := xlSets{sets: make([]*xlObjects, 3)}
+	for i := range s.sets {
+		s.sets[i] = &xlObjects{}
+	}
+	counts := make(map[*xlObjects]int)
+	objects := 3000
+	for i := 0; i < objects; i++ {
+		set := s.getHashedSet("bucket", fmt.Sprintf("dir/object%d", i))
+		if set != s.getHashedSet("bucket", fmt.Sprintf("dir/object%d", i)) {
+			t.Fatal("Expected object to be placed deterministically")
+		}
+		counts[set]++
+	}
+	for i, set := range s.sets {
+		if counts[set] < objects/3*9/10 || counts[set] > objects/3*11/10 {
+			t.Errorf("Set %d: %d of %d objects, expected about %d", i+1, counts[set], objects, objects/3)
+		}
+	}
+}
+
+// Tests object layer on 24 disks forming two erasure sets.
+func TestXLSets(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	fsDirs, err := getRandomDisks(24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	endpoints, err := parseStorageEndpoints(fsDirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = checkSufficientDisks(endpoints); err != nil {
+		t.Fatal(err)
+	}
+	obj, _, err := initObjectLayer(endpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, ok := obj.(*xlSets)
+	if !ok {
+		t.Fatalf("Expected erasure sets, got %T", obj)
+	}
+
+	// Quorum is computed per set.
+	if len(s.sets) != 2 {
+		t.Fatalf("Expected 2 erasure sets, got %d", len(s.sets))
+	}
+	for i, set := range s.sets {
+		if len(set.storageDisks) != 12 || set.readQuorum != 6 || set.writeQuorum != 7 {
+			t.Errorf("Set %d: unexpected %d disks, read quorum %d, write quorum %d", i+1,
+				len(set.storageDisks), set.readQuorum, set.writeQuorum)
+		}
+	}
+	if info := obj.StorageInfo(); info.Backend.OnlineDisks != 24 || info.Backend.ReadQuorum != 6 {
+		t.Errorf("Unexpected storage info %#v", info.Backend)
+	}
+
+	bucket := "bucket"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	var objects []string
+	placed := make(map[*xlObjects]int)
+	for i := 0; i < 20; i++ {
+		object := fmt.Sprintf("object%02d", i)
+		data := []byte(object)
+		if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+		objects = append(objects, object)
+		placed[s.getHashedSet(bucket, object)]++
+	}
+	if len(placed) != 2 {
+		t.Fatalf("Expected objects on both erasure sets, got %v", placed)
+	}
+
+	// Listing is merged across sets, in order and paged.
+	var listed []string
+	marker := ""
+	for {
+		result, err := obj.ListObjects(bucket, "", marker, "", 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, objInfo := range result.Objects {
+			listed = append(listed, objInfo.Name)
+		}
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.NextMarker
+	}
+	if !reflect.DeepEqual(listed, objects) {
+		t.Errorf("Expected listing %v, got %v", objects, listed)
+	}
+
+	// Copies between sets are streamed.
+	for _, object := range objects {
+		dest := "copy-" + object
+		if s.getHashedSet(bucket, object) == s.getHashedSet(bucket, dest) {
+			continue
+		}
+		if _, err = obj.CopyObject(bucket, object, bucket, dest, nil); err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err = obj.GetObject(bucket, dest, 0, int64(len(object)), &buf); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != object {
+			t.Errorf("Expected copy %s to hold %s, got %s", dest, object, buf.String())
+		}
+		break
+	}
+
+	// Non empty buckets are not deleted on any set.
+	if _, ok = errorCause(obj.DeleteBucket(bucket)).(BucketNotEmpty); !ok {
+		t.Fatal("Expected BucketNotEmpty")
+	}
+	if _, err = obj.GetBucketInfo(bucket); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,123 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "sync"
+
+// trashReaper - reclaims objects moved to `.minio.sys/trash` by
+// asynchronous deletes. A single background routine runs at a time,
+// it exits once the trash is empty and is started again on demand.
+type trashReaper struct {
+	mutex   *sync.Mutex
+	wg      *sync.WaitGroup
+	running bool
+	// Set when more objects were trashed while reaping.
+	pending bool
+}
+
+// newTrashReaper - initialize a new idle trash reaper.
+func newTrashReaper() *trashReaper {
+	return &trashReaper{
+		mutex: &sync.Mutex{},
+		wg:    &sync.WaitGroup{},
+	}
+}
+
+// trigger - starts reaping trash on disks in background, if already
+// running trash is reaped once more before the routine exits.
+func (r *trashReaper) trigger(disks []StorageAPI) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.running {
+		r.pending = true
+		return
+	}
+	if !isTrashPending(disks) {
+		return
+	}
+	r.running = true
+	r.wg.Add(1)
+	go r.run(disks)
+}
+
+// run - reaps trash until no more objects are trashed meanwhile.
+func (r *trashReaper) run(disks []StorageAPI) {
+	defer r.wg.Done()
+	for {
+		reapTrash(disks)
+
+		r.mutex.Lock()
+		if !r.pending {
+			r.running = false
+			r.mutex.Unlock()
+			return
+		}
+		r.pending = false
+		r.mutex.Unlock()
+	}
+}
+
+// wait - waits for the background routine to finish, if any.
+func (r *trashReaper) wait() {
+	r.wg.Wait()
+}
+
+// isTrashPending - returns true if trash of any of the disks has
+// entries left to be reaped.
+func isTrashPending(disks []StorageAPI) bool {
+	for _, disk := range disks {
+		if disk == nil {
+			continue
+		}
+		entries, err := disk.ListDir(minioMetaTrashBucket, "")
+		if err == nil && len(entries) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// reapTrash - removes all trashed objects on all disks in parallel.
+// Objects are only trashed after a quorum rename, failures here leave
+// entries behind which are reaped on the next run or at startup.
+func reapTrash(disks []StorageAPI) {
+	var wg = &sync.WaitGroup{}
+	for _, disk := range disks {
+		if disk == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(disk StorageAPI) {
+			defer wg.Done()
+			entries, err := disk.ListDir(minioMetaTrashBucket, "")
+			if err != nil {
+				if err != errVolumeNotFound {
+					errorIf(err, "Unable to list trash on %s", disk)
+				}
+				return
+			}
+			for _, entry := range entries {
+				err = cleanupDir(disk, minioMetaTrashBucket, entry)
+				if err != nil && errorCause(err) != errFileNotFound {
+					errorIf(err, "Unable to reap %s from trash on %s", entry, disk)
+				}
+			}
+		}(disk)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,99 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Tests asynchronously deleted objects are invisible immediately and
+// trash is reaped in background.
+func TestXLAsyncDeleteObject(t *testing.T) {
+	defer func(asyncDelete bool) { globalAsyncDelete = asyncDelete }(globalAsyncDelete)
+	globalAsyncDelete = true
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	bucket, object := "bucket", "dir/object"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.PutObject(bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err = obj.DeleteObject(bucket, object); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = obj.GetObjectInfo(bucket, object); !isErrObjectNotFound(errorCause(err)) {
+		t.Errorf("Expected object to be not found, got %v", err)
+	}
+	result, err := obj.ListObjects(bucket, "", "", "", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 0 || len(result.Prefixes) != 0 {
+		t.Errorf("Expected no objects to be listed, got %v %v", result.Objects, result.Prefixes)
+	}
+
+	xl.trash.wait()
+	if isTrashPending(xl.storageDisks) {
+		t.Error("Expected trash to be reaped")
+	}
+}
+
+// Tests objects left in trash by a crash are reaped on startup.
+func TestXLTrashReapedOnStartup(t *testing.T) {
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.PutObject(bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Crash after the object was moved to trash, before it was reaped.
+	if err = renameObject(xl.storageDisks, bucket, object, minioMetaTrashBucket, mustGetUUID(), xl.writeQuorum); err != nil {
+		t.Fatal(err)
+	}
+	if !isTrashPending(xl.storageDisks) {
+		t.Fatal("Expected trash to have entries")
+	}
+
+	obj, err = newXLObjects(xl.storageDisks, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xl = obj.(*xlObjects)
+	xl.trash.wait()
+	if isTrashPending(xl.storageDisks) {
+		t.Error("Expected trash to be reaped on startup")
+	}
+}
@@ -0,0 +1,267 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// truncateFile - truncates file at path on all disks in parallel.
+func truncateFile(disks []StorageAPI, volume, path string, size int64, writeQuorum int) error {
+	var wg = &sync.WaitGroup{}
+	var tErrs = make([]error, len(disks))
+	for index, disk := range disks {
+		if disk == nil {
+			tErrs[index] = traceError(errDiskNotFound)
+			continue
+		}
+		wg.Add(1)
+		go func(index int, disk StorageAPI) {
+			defer wg.Done()
+			tErrs[index] = traceError(disk.TruncateFile(volume, path, size))
+		}(index, disk)
+	}
+
+	// Wait for all the truncates to finish.
+	wg.Wait()
+
+	// Do we have write quorum?.
+	if !isDiskQuorum(tErrs, writeQuorum) {
+		return traceError(errXLWriteQuorum)
+	}
+	return reduceWriteQuorumErrs(tErrs, objectOpIgnoredErrs, writeQuorum)
+}
+
+// TruncateObject - truncates an object to the requested size. Parts
+// beyond the new size are removed, the shards of the part holding the
+// new end of the object are truncated on each disk to the last
+// complete erasure block. When the new size is not block aligned,
+// the remaining partial block is read back, erasure coded again and
+// appended. Data before the new end is never rewritten, a crash
+// before `xl.json` is committed leaves shards which fail their bitrot
+// checksum and are healed.
+func (xl xlObjects) TruncateObject(bucket, object string, size int64) (ObjectInfo, error) {
+	if err := checkTruncateObjArgs(bucket, object); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	// Read metadata associated with the object from all disks.
+	metaArr, errs := readAllXLMetadata(xl.storageDisks, bucket, object)
+	// Do we have read quorum?
+	if !isDiskQuorum(errs, xl.readQuorum) {
+		return ObjectInfo{}, traceError(InsufficientReadQuorum{}, errs...)
+	}
+
+	if reducedErr := reduceReadQuorumErrs(errs, objectOpIgnoredErrs, xl.readQuorum); reducedErr != nil {
+		return ObjectInfo{}, toObjectErr(reducedErr, bucket, object)
+	}
+
+	// List all online disks.
+	onlineDisks, modTime := listOnlineDisks(xl.storageDisks, metaArr, errs)
+
+	// Pick latest valid metadata.
+	xlMeta, err := pickValidXLMeta(metaArr, modTime)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	if size < 0 || size > xlMeta.Stat.Size {
+		return ObjectInfo{}, traceError(InvalidTruncateLength{bucket, object, size, xlMeta.Stat.Size})
+	}
+
+	// Nothing to do.
+	if size == xlMeta.Stat.Size {
+		return xl.getObjectInfo(bucket, object)
+	}
+
+	// Reorder online disks and parts metadata based on erasure distribution order.
+	onlineDisks = getOrderedDisks(xlMeta.Erasure.Distribution, onlineDisks)
+	metaArr = getOrderedPartsMetadata(xlMeta.Erasure.Distribution, metaArr)
+
+	// Locate the part which holds the new end of the object.
+	var partIndex int
+	var partStart int64
+	for ; partIndex < len(xlMeta.Parts)-1; partIndex++ {
+		if size <= partStart+xlMeta.Parts[partIndex].Size {
+			break
+		}
+		partStart += xlMeta.Parts[partIndex].Size
+	}
+
+	parts := make([]objectPartInfo, partIndex+1)
+	copy(parts, xlMeta.Parts[:partIndex+1])
+	removedParts := xlMeta.Parts[partIndex+1:]
+	removedPartNames := make(map[string]struct{}, len(removedParts))
+	for _, removedPart := range removedParts {
+		removedPartNames[removedPart.Name] = struct{}{}
+	}
+
+	part := &parts[partIndex]
+	partPath := path.Join(object, part.Name)
+	newPartSize := size - partStart
+
+	var checkSums []string
+	if newPartSize < part.Size {
+		blockSize := xlMeta.Erasure.BlockSize
+		dataBlocks := xlMeta.Erasure.DataBlocks
+		chunkSize := getChunkSize(blockSize, dataBlocks)
+		fullBlocks := newPartSize / blockSize
+		tailSize := newPartSize % blockSize
+
+		// Read the retained portion of the part to calculate its
+		// new md5sum, the trailing partial block is saved to be
+		// erasure coded again.
+		md5Writer := md5.New()
+		if fullBlocks > 0 {
+			if err = xl.GetObject(bucket, object, partStart, fullBlocks*blockSize, md5Writer); err != nil {
+				return ObjectInfo{}, err
+			}
+		}
+		tailBuffer := new(bytes.Buffer)
+		if tailSize > 0 {
+			if err = xl.GetObject(bucket, object, partStart+fullBlocks*blockSize, tailSize, io.MultiWriter(md5Writer, tailBuffer)); err != nil {
+				return ObjectInfo{}, err
+			}
+		}
+
+		// Discard all the erasure blocks after the last complete block.
+		if err = truncateFile(onlineDisks, bucket, partPath, fullBlocks*chunkSize, xl.writeQuorum); err != nil {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
+
+		var tailShardSize int64
+		if tailSize > 0 {
+			enBlocks, err := encodeData(tailBuffer.Bytes(), dataBlocks, xlMeta.Erasure.ParityBlocks)
+			if err != nil {
+				return ObjectInfo{}, toObjectErr(err, bucket, object)
+			}
+			hashWriters := newHashWriters(len(onlineDisks), bitRotAlgo)
+			if err = appendFile(onlineDisks, bucket, partPath, enBlocks, hashWriters, xl.writeQuorum); err != nil {
+				return ObjectInfo{}, toObjectErr(err, bucket, object)
+			}
+			tailShardSize = int64(len(enBlocks[0]))
+		}
+
+		// Bitrot checksums cover the entire shard, recalculate them
+		// for this part only.
+		checkSums = make([]string, len(onlineDisks))
+		for index, disk := range onlineDisks {
+			if disk == nil {
+				continue
+			}
+			fi, err := disk.StatFile(bucket, partPath)
+			if err != nil || fi.Size != fullBlocks*chunkSize+tailShardSize {
+				// Disk is no longer consistent, do not update its `xl.json`.
+				onlineDisks[index] = nil
+				continue
+			}
+			sum, err := hashSum(disk, bucket, partPath, newHash(bitRotAlgo))
+			if err != nil {
+				onlineDisks[index] = nil
+				continue
+			}
+			checkSums[index] = hex.EncodeToString(sum)
+		}
+
+		part.Size = newPartSize
+		part.ETag = hex.EncodeToString(md5Writer.Sum(nil))
+	}
+
+	// Calculate the new md5sum of the object, multipart objects
+	// carry the md5sum of their parts md5sum.
+	md5Hex := part.ETag
+	if strings.Contains(xlMeta.Meta["md5Sum"], "-") {
+		completeParts := make([]completePart, len(parts))
+		for index, objPart := range parts {
+			completeParts[index] = completePart{PartNumber: objPart.Number, ETag: objPart.ETag}
+		}
+		if md5Hex, err = getCompleteMultipartMD5(completeParts); err != nil {
+			return ObjectInfo{}, err
+		}
+	}
+
+	modTime = time.Now().UTC()
+	for index, disk := range onlineDisks {
+		if disk == nil {
+			continue
+		}
+		metaArr[index].Parts = parts
+		metaArr[index].Stat.Size = size
+		metaArr[index].Stat.ModTime = modTime
+		metaArr[index].Meta = make(map[string]string, len(xlMeta.Meta))
+		for k, v := range xlMeta.Meta {
+			metaArr[index].Meta[k] = v
+		}
+		metaArr[index].Meta["md5Sum"] = md5Hex
+		// Truncated object is modified now, drop the modification
+		// time requested on upload.
+		delete(metaArr[index].Meta, modTimeMetaKey)
+
+		// Retain checksums of the remaining parts only.
+		var ckSums []checkSumInfo
+		for _, ckSum := range metaArr[index].Erasure.Checksum {
+			if _, ok := removedPartNames[ckSum.Name]; !ok {
+				ckSums = append(ckSums, ckSum)
+			}
+		}
+		metaArr[index].Erasure.Checksum = ckSums
+		if checkSums != nil {
+			metaArr[index].Erasure.AddCheckSumInfo(checkSumInfo{
+				Name:      part.Name,
+				Hash:      checkSums[index],
+				Algorithm: bitRotAlgo,
+			})
+		}
+	}
+
+	// Write unique `xl.json` for each disk and commit it.
+	tempXLMetaPath := mustGetUUID()
+	if err = writeUniqueXLMetadata(onlineDisks, minioMetaTmpBucket, tempXLMetaPath, metaArr, xl.writeQuorum); err != nil {
+		return ObjectInfo{}, toObjectErr(err, minioMetaTmpBucket, tempXLMetaPath)
+	}
+	if err = commitXLMetadata(onlineDisks, minioMetaTmpBucket, tempXLMetaPath, bucket, object, xl.writeQuorum); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	// Remove the parts which are no longer referenced, a failure
+	// here only leaves behind unused files.
+	for removedPart := range removedPartNames {
+		for _, disk := range xl.storageDisks {
+			if disk == nil {
+				continue
+			}
+			_ = disk.DeleteFile(bucket, path.Join(object, removedPart))
+		}
+	}
+
+	// Cached metadata of the object is stale now.
+	xl.invalidateMeta(bucket, object)
+
+	if xl.objCacheEnabled {
+		// Truncated object invalidates the cached content.
+		xl.objCache.Delete(path.Join(bucket, object))
+	}
+
+	return xl.getObjectInfo(bucket, object)
+}
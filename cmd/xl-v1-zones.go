@@ -0,0 +1,117 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"sync/atomic"
+)
+
+// Query parameter used to tag an endpoint with its zone,
+// e.g. `http://host1/mnt/disk1?zone=rack1`.
+const endpointZoneParam = "zone"
+
+// parseEndpointZone - returns the zone an endpoint is tagged with and
+// strips the tag from the endpoint, untagged endpoints return an
+// empty zone.
+func parseEndpointZone(u *url.URL) (string, error) {
+	if u.RawQuery == "" {
+		return "", nil
+	}
+	values, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return "", err
+	}
+	zone := values.Get(endpointZoneParam)
+	if len(values) != 1 || len(values[endpointZoneParam]) != 1 || zone == "" {
+		return "", fmt.Errorf("Invalid Argument %s, only a single non-empty %s tag is supported", u.RawQuery, endpointZoneParam)
+	}
+	u.RawQuery = ""
+	return zone, nil
+}
+
+// endpointZones - zones of all the disks, indexed by the stringified
+// representation of the disk, along with the zone of this server.
+// Reads prefer shards on disks in the local zone.
+type endpointZones struct {
+	zones     map[string]string
+	localZone string
+
+	// Number of shards read, in total and from disks outside the
+	// local zone.
+	shardReads          uint64
+	crossZoneShardReads uint64
+}
+
+// newEndpointZones - initializes zones of endpoints, zones[i] is the
+// zone endpoints[i] is tagged with. Local zone is the zone of the
+// first tagged local endpoint.
+func newEndpointZones(endpoints []*url.URL, zones []string) *endpointZones {
+	z := &endpointZones{zones: make(map[string]string)}
+	for i, ep := range endpoints {
+		if zones[i] == "" {
+			continue
+		}
+		z.zones[endpointDiskString(ep)] = zones[i]
+		if z.localZone == "" && isLocalStorage(ep) {
+			z.localZone = zones[i]
+		}
+	}
+	return z
+}
+
+// preferredDisks - returns which of the input disks are in the local
+// zone, returns nil if this server is not tagged with a zone.
+func (z *endpointZones) preferredDisks(disks []StorageAPI) []bool {
+	if z == nil || z.localZone == "" {
+		return nil
+	}
+	preferred := make([]bool, len(disks))
+	for index, disk := range disks {
+		if disk == nil {
+			continue
+		}
+		preferred[index] = z.zones[disk.String()] == z.localZone
+	}
+	return preferred
+}
+
+// countReads - accounts shards about to be read from readDisks.
+func (z *endpointZones) countReads(readDisks []StorageAPI, preferred []bool) {
+	if z == nil {
+		return
+	}
+	for index, disk := range readDisks {
+		if disk == nil {
+			continue
+		}
+		atomic.AddUint64(&z.shardReads, 1)
+		if !preferred[index] {
+			atomic.AddUint64(&z.crossZoneShardReads, 1)
+		}
+	}
+}
+
+// readStats - returns the number of shards read in total and from
+// disks outside the local zone.
+func (z *endpointZones) readStats() (shardReads, crossZoneShardReads uint64) {
+	if z == nil {
+		return 0, 0
+	}
+	return atomic.LoadUint64(&z.shardReads), atomic.LoadUint64(&z.crossZoneShardReads)
+}
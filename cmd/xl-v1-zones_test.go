@@ -0,0 +1,143 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/minio/pkg/bpool"
+)
+
+// Tests parsing of zone tags of endpoints.
+func TestParseStorageEndpointsWithZones(t *testing.T) {
+	testCases := []struct {
+		eps     []string
+		paths   []string
+		zones   []string
+		success bool
+	}{
+		{[]string{"/mnt/disk1", "/mnt/disk2"}, []string{"/mnt/disk1", "/mnt/disk2"}, []string{"", ""}, true},
+		{[]string{"/mnt/disk1?zone=rack1", "/mnt/disk2?zone=rack2"}, []string{"/mnt/disk1", "/mnt/disk2"}, []string{"rack1", "rack2"}, true},
+		{[]string{"http://host1/mnt/disk1?zone=rack1", "http://host2/mnt/disk1"}, []string{"/mnt/disk1", "/mnt/disk1"}, []string{"rack1", ""}, true},
+		{[]string{"/mnt/disk1?zone="}, nil, nil, false},
+		{[]string{"/mnt/disk1?rack=rack1"}, nil, nil, false},
+		{[]string{"/mnt/disk1?zone=rack1&zone=rack2"}, nil, nil, false},
+	}
+	for i, testCase := range testCases {
+		endpoints, zones, err := parseStorageEndpointsWithZones(testCase.eps)
+		if !testCase.success {
+			if err == nil {
+				t.Errorf("Test %d: Expected to fail", i+1)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: Unexpected error %v", i+1, err)
+		}
+		var paths []string
+		for _, ep := range endpoints {
+			if ep.RawQuery != "" {
+				t.Errorf("Test %d: Expected zone tag to be stripped from %s", i+1, ep)
+			}
+			paths = append(paths, ep.Path)
+		}
+		if !reflect.DeepEqual(paths, testCase.paths) {
+			t.Errorf("Test %d: Expected paths %v, got %v", i+1, testCase.paths, paths)
+		}
+		if !reflect.DeepEqual(zones, testCase.zones) {
+			t.Errorf("Test %d: Expected zones %v, got %v", i+1, testCase.zones, zones)
+		}
+	}
+}
+
+// Tests shards are read from the local zone, falling back to other
+// zones only when the local zone does not have enough shards.
+func TestErasureReadFilePreferred(t *testing.T) {
+	defer func(z *endpointZones) { globalEndpointZones = z }(globalEndpointZones)
+	globalEndpointZones = &endpointZones{}
+
+	dataBlocks, parityBlocks := 4, 4
+	setup, err := newErasureTestSetup(dataBlocks, parityBlocks, blockSizeV1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer setup.Remove()
+
+	data := make([]byte, 1*humanize.MiByte)
+	if _, err = rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	length := int64(len(data))
+	_, checkSums, err := erasureCreateFile(setup.disks, "testbucket", "testobject", bytes.NewReader(data), blockSizeV1, dataBlocks, parityBlocks, bitRotAlgo, dataBlocks+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := bpool.NewBytePool(getChunkSize(blockSizeV1, dataBlocks), len(setup.disks))
+
+	// Two data and two parity disks are in the local zone.
+	preferred := []bool{true, true, false, false, true, true, false, false}
+
+	readFile := func(failed int) []*readCountingDisk {
+		counting := make([]*readCountingDisk, len(setup.disks))
+		disks := make([]StorageAPI, len(setup.disks))
+		for i := range setup.disks {
+			counting[i] = &readCountingDisk{StorageAPI: setup.disks[i]}
+			disks[i] = counting[i]
+		}
+		if failed >= 0 {
+			disks[failed] = nil
+		}
+		buf := &bytes.Buffer{}
+		if _, err := erasureReadFilePreferred(buf, disks, "testbucket", "testobject", 0, length, length, blockSizeV1, dataBlocks, parityBlocks, checkSums, bitRotAlgo, pool, preferred); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), data) {
+			t.Fatal("Contents of the file read are not the same")
+		}
+		return counting
+	}
+
+	counting := readFile(-1)
+	for i, disk := range counting {
+		if !preferred[i] && disk.shardReads != 0 {
+			t.Errorf("Expected no reads from disk %d outside the local zone, got %d", i, disk.shardReads)
+		}
+	}
+	shardReads, crossZoneShardReads := globalEndpointZones.readStats()
+	if shardReads != uint64(dataBlocks) || crossZoneShardReads != 0 {
+		t.Fatalf("Expected %d shard reads and no cross zone reads, got %d and %d", dataBlocks, shardReads, crossZoneShardReads)
+	}
+
+	// With a local disk down a single shard is read from another zone.
+	counting = readFile(0)
+	if counting[2].shardReads == 0 {
+		t.Errorf("Expected reads from disk 2 outside the local zone")
+	}
+	for _, i := range []int{3, 6, 7} {
+		if counting[i].shardReads != 0 {
+			t.Errorf("Expected no reads from disk %d outside the local zone, got %d", i, counting[i].shardReads)
+		}
+	}
+	shardReads, crossZoneShardReads = globalEndpointZones.readStats()
+	if shardReads != uint64(2*dataBlocks) || crossZoneShardReads != 1 {
+		t.Fatalf("Expected %d shard reads and 1 cross zone read, got %d and %d", 2*dataBlocks, shardReads, crossZoneShardReads)
+	}
+}
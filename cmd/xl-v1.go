@@ -71,19 +71,50 @@ type xlObjects struct {
 
 	// Object cache enabled.
 	objCacheEnabled bool
+
+	// Reclaims objects moved to trash by asynchronous deletes.
+	trash *trashReaper
+
+	// Heals objects written without laggard disks.
+	laggards *laggardHealer
+
+	// Caches object metadata on a fast local disk, nil if disabled.
+	metaStore *metaStore
 }
 
 // list of all errors that can be ignored in tree walk operation in XL
 var xlTreeWalkIgnoredErrs = append(baseIgnoredErrs, errDiskAccessDenied, errVolumeNotFound, errFileNotFound)
 
-// newXLObjects - initialize new xl object layer.
-func newXLObjects(storageDisks []StorageAPI) (ObjectLayer, error) {
+// getDataParityBlocks - returns data and parity blocks objects are
+// erasure coded with on a number of disks. Parity defaults to half
+// the disks, an odd number of disks gets one more data block than
+// parity blocks.
+func getDataParityBlocks(disks, parity int) (dataBlocks, parityBlocks int) {
+	parityBlocks = parity
+	if parityBlocks == 0 {
+		parityBlocks = disks / 2
+	}
+	return disks - parityBlocks, parityBlocks
+}
+
+// newXLObjects - initialize new xl object layer, objects are erasure
+// coded with parity blocks, zero for half the disks.
+func newXLObjects(storageDisks []StorageAPI, parity int) (ObjectLayer, error) {
 	if storageDisks == nil {
 		return nil, errInvalidArgument
 	}
 
-	readQuorum := len(storageDisks) / 2
-	writeQuorum := len(storageDisks)/2 + 1
+	// Calculate data and parity blocks.
+	dataBlocks, parityBlocks := getDataParityBlocks(len(storageDisks), parity)
+
+	// Reads need data blocks to reconstruct objects, writes need
+	// data blocks too and a majority so that reads see the latest
+	// write.
+	readQuorum := dataBlocks
+	writeQuorum := dataBlocks
+	if dataBlocks == parityBlocks {
+		writeQuorum++
+	}
 
 	// Load saved XL format.json and validate.
 	newStorageDisks, err := loadFormatXL(storageDisks, readQuorum)
@@ -91,9 +122,6 @@ func newXLObjects(storageDisks []StorageAPI) (ObjectLayer, error) {
 		return nil, fmt.Errorf("Unable to recognize backend format, %s", err)
 	}
 
-	// Calculate data and parity blocks.
-	dataBlocks, parityBlocks := len(newStorageDisks)/2, len(newStorageDisks)/2
-
 	// Initialize list pool.
 	listPool := newTreeWalkPool(globalLookupTimeout)
 
@@ -107,6 +135,9 @@ func newXLObjects(storageDisks []StorageAPI) (ObjectLayer, error) {
 		dataBlocks:   dataBlocks,
 		parityBlocks: parityBlocks,
 		listPool:     listPool,
+		trash:        newTrashReaper(),
+		laggards:     newLaggardHealer(),
+		metaStore:    globalMetaStore,
 	}
 
 	// Object cache is enabled when _MINIO_CACHE env is missing.
@@ -129,10 +160,19 @@ func newXLObjects(storageDisks []StorageAPI) (ObjectLayer, error) {
 	}
 
 	// Figure out read and write quorum based on number of storage disks.
-	// READ and WRITE quorum is always set to (N/2) number of disks.
+	// READ quorum is set to data blocks, WRITE quorum to data blocks
+	// or (N/2)+1 disks, whichever is larger.
 	xl.readQuorum = readQuorum
 	xl.writeQuorum = writeQuorum
 
+	// Reclaim objects left in trash by a previous run, deletes which
+	// were interrupted by a crash or restart are completed here.
+	xl.trash.trigger(xl.storageDisks)
+
+	// Heal objects written without laggard disks by a previous run
+	// which were not healed yet.
+	xl.laggards.resume(*xl)
+
 	// Do a quick heal on the buckets themselves for any discrepancies.
 	if err := quickHeal(xl.storageDisks, xl.writeQuorum, xl.readQuorum); err != nil {
 		return xl, err
@@ -198,24 +238,43 @@ func sortValidDisksInfo(disksInfo []disk.Info) []disk.Info {
 }
 
 // Get an aggregated storage info across all disks.
-func getStorageInfo(disks []StorageAPI) StorageInfo {
+func getStorageInfo(disks []StorageAPI, dataBlocks, parityBlocks int) StorageInfo {
 	disksInfo, onlineDisks, offlineDisks := getDisksInfo(disks)
 
 	// Sort so that the first element is the smallest.
 	validDisksInfo := sortValidDisksInfo(disksInfo)
 	if len(validDisksInfo) == 0 {
-		return StorageInfo{
+		storageInfo := StorageInfo{
 			Total: -1,
 			Free:  -1,
 		}
+		storageInfo.Backend.Type = XL
+		storageInfo.Backend.OnlineDisks = onlineDisks
+		storageInfo.Backend.OfflineDisks = offlineDisks
+		return storageInfo
 	}
 
-	// Return calculated storage info, choose the lowest Total and
-	// Free as the total aggregated values. Total capacity is always
-	// the multiple of smallest disk among the disk list.
+	// Every object is striped over all the disks in equal shares, so
+	// writes fail once any disk is full and space of larger disks
+	// beyond that of the smallest one is never used. Total capacity
+	// is the multiple of the smallest disk and free space the multiple
+	// of the disk with least free space, of which data blocks take
+	// their share. Raw values sum up all the disks as they are.
+	minFree := validDisksInfo[0].Free
+	var rawTotal, rawFree int64
+	for _, diskInfo := range validDisksInfo {
+		if diskInfo.Free < minFree {
+			minFree = diskInfo.Free
+		}
+		rawTotal += diskInfo.Total
+		rawFree += diskInfo.Free
+	}
+	totalBlocks := int64(dataBlocks + parityBlocks)
 	storageInfo := StorageInfo{
-		Total: validDisksInfo[0].Total * int64(onlineDisks) / 2,
-		Free:  validDisksInfo[0].Free * int64(onlineDisks) / 2,
+		Total:    validDisksInfo[0].Total * int64(onlineDisks) * int64(dataBlocks) / totalBlocks,
+		Free:     minFree * int64(onlineDisks) * int64(dataBlocks) / totalBlocks,
+		RawTotal: rawTotal,
+		RawFree:  rawFree,
 	}
 
 	storageInfo.Backend.Type = XL
@@ -226,8 +285,13 @@ func getStorageInfo(disks []StorageAPI) StorageInfo {
 
 // StorageInfo - returns underlying storage statistics.
 func (xl xlObjects) StorageInfo() StorageInfo {
-	storageInfo := getStorageInfo(xl.storageDisks)
+	storageInfo := getStorageInfo(xl.storageDisks, xl.dataBlocks, xl.parityBlocks)
+	storageInfo.Disks = getDisksLatency(xl.storageDisks)
 	storageInfo.Backend.ReadQuorum = xl.readQuorum
 	storageInfo.Backend.WriteQuorum = xl.writeQuorum
+	storageInfo.Backend.AvoidedDisks = avoidedDisksCount(xl.storageDisks)
+	storageInfo.Backend.PendingHeals, _, _ = xl.laggards.stats()
+	storageInfo.Backend.DataBlocks = xl.dataBlocks
+	storageInfo.Backend.ParityBlocks = xl.parityBlocks
 	return storageInfo
 }
@@ -17,6 +17,8 @@
 package cmd
 
 import (
+	"bytes"
+	"fmt"
 	"path/filepath"
 	"reflect"
 	"testing"
@@ -60,7 +62,7 @@ func TestStorageInfo(t *testing.T) {
 		t.Fatal("Unexpected error: ", err)
 	}
 
-	objLayer, err = newXLObjects(storageDisks)
+	objLayer, err = newXLObjects(storageDisks, 0)
 	if err != nil {
 		t.Fatalf("Unable to initialize 'XL' object layer with ignored disks %s. error %s", fsDirs[:4], err)
 	}
@@ -125,6 +127,98 @@ func TestSortingValidDisks(t *testing.T) {
 	}
 }
 
+// diskInfoStorage - disk reporting fixed disk info.
+type diskInfoStorage struct {
+	StorageAPI
+	info disk.Info
+}
+
+func (d diskInfoStorage) String() string {
+	return fmt.Sprintf("disk of %d bytes", d.info.Total)
+}
+
+func (d diskInfoStorage) DiskInfo() (disk.Info, error) {
+	return d.info, nil
+}
+
+// Tests storage info of erasure sets of disks of different sizes.
+func TestStorageInfoMismatchedDisks(t *testing.T) {
+	const tb = 1 << 40
+	newDisks := func(infos ...disk.Info) []StorageAPI {
+		disks := make([]StorageAPI, len(infos))
+		for i, info := range infos {
+			if info.Total > 0 {
+				disks[i] = diskInfoStorage{info: info}
+			}
+		}
+		return disks
+	}
+	newSet := func(disks []StorageAPI) *xlObjects {
+		return &xlObjects{
+			storageDisks: disks,
+			dataBlocks:   len(disks) / 2,
+			parityBlocks: len(disks) / 2,
+			laggards:     newLaggardHealer(),
+		}
+	}
+
+	// Two 4TB and two 8TB disks, the 8TB disks hold 4TB more data each
+	// which is never used.
+	mixed := newDisks(
+		disk.Info{Total: 8 * tb, Free: 7 * tb},
+		disk.Info{Total: 4 * tb, Free: 3 * tb},
+		disk.Info{Total: 8 * tb, Free: 7 * tb},
+		disk.Info{Total: 4 * tb, Free: 2 * tb},
+	)
+	// Four 4TB disks of which one is offline.
+	degraded := newDisks(
+		disk.Info{Total: 4 * tb, Free: 4 * tb},
+		disk.Info{},
+		disk.Info{Total: 4 * tb, Free: 4 * tb},
+		disk.Info{Total: 4 * tb, Free: 4 * tb},
+	)
+	offline := newDisks(disk.Info{}, disk.Info{}, disk.Info{}, disk.Info{})
+
+	testCases := []struct {
+		objAPI                    ObjectLayer
+		total, free               int64
+		rawTotal, rawFree         int64
+		onlineDisks, offlineDisks int
+	}{
+		// Test case - 1.
+		// Capacity of the smallest disk, free space of the fullest disk.
+		{newSet(mixed), 8 * tb, 4 * tb, 24 * tb, 19 * tb, 4, 0},
+		// Test case - 2.
+		// Offline disk.
+		{newSet(degraded), 6 * tb, 6 * tb, 12 * tb, 12 * tb, 3, 1},
+		// Test case - 3.
+		// Sets are summed up.
+		{&xlSets{sets: []*xlObjects{newSet(mixed), newSet(degraded)}}, 14 * tb, 10 * tb, 36 * tb, 31 * tb, 7, 1},
+		// Test case - 4.
+		// Sets without online disks are skipped.
+		{&xlSets{sets: []*xlObjects{newSet(offline), newSet(mixed)}}, 8 * tb, 4 * tb, 24 * tb, 19 * tb, 4, 4},
+		// Test case - 5.
+		// No online disks at all.
+		{&xlSets{sets: []*xlObjects{newSet(offline)}}, -1, -1, 0, 0, 0, 4},
+	}
+
+	for i, testCase := range testCases {
+		info := testCase.objAPI.StorageInfo()
+		if info.Total != testCase.total || info.Free != testCase.free {
+			t.Errorf("Test %d: Expected total %d and free %d, got %d and %d",
+				i+1, testCase.total, testCase.free, info.Total, info.Free)
+		}
+		if info.RawTotal != testCase.rawTotal || info.RawFree != testCase.rawFree {
+			t.Errorf("Test %d: Expected raw total %d and raw free %d, got %d and %d",
+				i+1, testCase.rawTotal, testCase.rawFree, info.RawTotal, info.RawFree)
+		}
+		if info.Backend.OnlineDisks != testCase.onlineDisks || info.Backend.OfflineDisks != testCase.offlineDisks {
+			t.Errorf("Test %d: Expected %d online and %d offline disks, got %d and %d",
+				i+1, testCase.onlineDisks, testCase.offlineDisks, info.Backend.OnlineDisks, info.Backend.OfflineDisks)
+		}
+	}
+}
+
 // TestNewXL - tests initialization of all input disks
 // and constructs a valid `XL` object
 func TestNewXL(t *testing.T) {
@@ -140,7 +234,7 @@ func TestNewXL(t *testing.T) {
 	}
 
 	// No disks input.
-	_, err := newXLObjects(nil)
+	_, err := newXLObjects(nil, 0)
 	if err != errInvalidArgument {
 		t.Fatalf("Unable to initialize erasure, %s", err)
 	}
@@ -170,8 +264,168 @@ func TestNewXL(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Unable to format disks for erasure, %s", err)
 	}
-	_, err = newXLObjects(formattedDisks)
+	_, err = newXLObjects(formattedDisks, 0)
 	if err != nil {
 		t.Fatalf("Unable to initialize erasure, %s", err)
 	}
 }
+
+// Tests erasure coding on odd numbers of disks.
+func TestXLOddDisks(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	testCases := []struct {
+		disks                    int
+		dataBlocks, parityBlocks int
+	}{
+		{5, 3, 2},
+		{7, 4, 3},
+		{9, 5, 4},
+		{11, 6, 5},
+	}
+	for i, testCase := range testCases {
+		fsDirs, err := getRandomDisks(testCase.disks)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer removeRoots(fsDirs)
+		endpoints, err := parseStorageEndpoints(fsDirs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = checkSufficientDisks(endpoints); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		obj, _, err := initObjectLayer(endpoints)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		xl := obj.(*xlObjects)
+
+		info := obj.StorageInfo()
+		if info.Backend.DataBlocks != testCase.dataBlocks || info.Backend.ParityBlocks != testCase.parityBlocks {
+			t.Errorf("Test %d: expected %d data, %d parity blocks, got %d, %d", i+1,
+				testCase.dataBlocks, testCase.parityBlocks, info.Backend.DataBlocks, info.Backend.ParityBlocks)
+		}
+		// Reads reconstruct from data blocks, writes reach a
+		// majority which every read quorum overlaps with.
+		if xl.readQuorum != testCase.dataBlocks {
+			t.Errorf("Test %d: expected read quorum %d, got %d", i+1, testCase.dataBlocks, xl.readQuorum)
+		}
+		if xl.writeQuorum < xl.readQuorum || xl.readQuorum+xl.writeQuorum <= testCase.disks {
+			t.Errorf("Test %d: invalid read, write quorum %d, %d", i+1, xl.readQuorum, xl.writeQuorum)
+		}
+
+		bucket, object := "bucket", "object"
+		if err = obj.MakeBucket(bucket); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		data := bytes.Repeat([]byte("abcdefgh"), 1000)
+		if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+
+		// Objects are readable with all parity disks offline.
+		for j := 0; j < testCase.parityBlocks; j++ {
+			xl.storageDisks[j] = nil
+		}
+		var buf bytes.Buffer
+		if err = obj.GetObject(bucket, object, 0, int64(len(data)), &buf); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if !bytes.Equal(buf.Bytes(), data) {
+			t.Errorf("Test %d: data mismatch", i+1)
+		}
+	}
+}
+
+// Tests quorum of objects erasure coded with the parity of the
+// configured storage class.
+func TestXLStorageClass(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+	defer func(parity int) { globalStorageClassParity = parity }(globalStorageClassParity)
+
+	testCases := []struct {
+		disks, parity            int
+		dataBlocks, parityBlocks int
+		readQuorum, writeQuorum  int
+	}{
+		// Default parity of half the disks.
+		{8, 0, 4, 4, 4, 5},
+		{8, 4, 4, 4, 4, 5},
+		{5, 0, 3, 2, 3, 3},
+		// Lower parity, writes need all data blocks.
+		{8, 2, 6, 2, 6, 6},
+		{8, 3, 5, 3, 5, 5},
+		{16, 4, 12, 4, 12, 12},
+	}
+	for i, testCase := range testCases {
+		fsDirs, err := getRandomDisks(testCase.disks)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer removeRoots(fsDirs)
+		endpoints, err := parseStorageEndpoints(fsDirs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		globalStorageClassParity = testCase.parity
+		obj, _, err := initObjectLayer(endpoints)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		xl := obj.(*xlObjects)
+
+		info := obj.StorageInfo()
+		if info.Backend.DataBlocks != testCase.dataBlocks || info.Backend.ParityBlocks != testCase.parityBlocks {
+			t.Errorf("Test %d: expected %d data, %d parity blocks, got %d, %d", i+1,
+				testCase.dataBlocks, testCase.parityBlocks, info.Backend.DataBlocks, info.Backend.ParityBlocks)
+		}
+		if xl.readQuorum != testCase.readQuorum || xl.writeQuorum != testCase.writeQuorum {
+			t.Errorf("Test %d: expected read, write quorum %d, %d, got %d, %d", i+1,
+				testCase.readQuorum, testCase.writeQuorum, xl.readQuorum, xl.writeQuorum)
+		}
+
+		bucket, data := "bucket", bytes.Repeat([]byte("abcdefgh"), 1000)
+		if err = obj.MakeBucket(bucket); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if _, err = obj.PutObject(bucket, "object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+
+		// Objects are written and read with all parity disks offline
+		// but for one more disk only with half the disks as parity.
+		for j := 0; j < testCase.parityBlocks; j++ {
+			xl.storageDisks[j] = nil
+		}
+		var buf bytes.Buffer
+		if err = obj.GetObject(bucket, "object", 0, int64(len(data)), &buf); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if !bytes.Equal(buf.Bytes(), data) {
+			t.Errorf("Test %d: data mismatch", i+1)
+		}
+		_, err = obj.PutObject(bucket, "object2", int64(len(data)), bytes.NewReader(data), nil, "")
+		if (testCase.disks-testCase.parityBlocks >= testCase.writeQuorum) != (err == nil) {
+			t.Errorf("Test %d: unexpected write error %v with %d disks offline", i+1, err, testCase.parityBlocks)
+		}
+
+		// Restarting with another parity is refused.
+		globalStorageClassParity = 2
+		if testCase.parityBlocks == 2 {
+			globalStorageClassParity = 3
+		}
+		if _, _, err = initObjectLayer(endpoints); err == nil {
+			t.Errorf("Test %d: expected parity mismatch with the backend format", i+1)
+		}
+	}
+}
@@ -27,3 +27,28 @@ type Info struct {
 	Ffree  int64
 	FSType string
 }
+
+// networkFSTypes - filesystem types of Info.FSType which are not backed
+// by a local disk, but by the network or a user space FUSE daemon.
+var networkFSTypes = map[string]struct{}{
+	"NFS":    {},
+	"SMB":    {},
+	"CIFS":   {},
+	"SMB2":   {},
+	"FUSE":   {},
+	"9P":     {},
+	"AFS":    {},
+	"CODA":   {},
+	"NCP":    {},
+	"CEPH":   {},
+	"GFS2":   {},
+	"OCFS2":  {},
+	"LUSTRE": {},
+}
+
+// IsNetworkFSType returns true if the filesystem type fsType, as in
+// Info.FSType, is a network or FUSE filesystem.
+func IsNetworkFSType(fsType string) bool {
+	_, ok := networkFSTypes[fsType]
+	return ok
+}
@@ -37,6 +37,19 @@ var fsType2StringMap = map[string]string{
 	"ef51":     "EXT2OLD",
 	"ef53":     "EXT4",
 	"f15f":     "ecryptfs",
+	// Network and FUSE filesystems.
+	"517b":     "SMB",
+	"ff534d42": "CIFS",
+	"fe534d42": "SMB2",
+	"65735546": "FUSE",
+	"1021997":  "9P",
+	"5346414f": "AFS",
+	"73757245": "CODA",
+	"564c":     "NCP",
+	"c36400":   "CEPH",
+	"1161970":  "GFS2",
+	"7461636f": "OCFS2",
+	"bd00bd0":  "LUSTRE",
 }
 
 // getFSType returns the filesystem type of the underlying mounted filesystem
@@ -0,0 +1,67 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// TenantStats - request accounting of a single access key.
+type TenantStats struct {
+	Requests uint64 `json:"requests"`
+	Errors   uint64 `json:"errors"`
+	BytesIn  uint64 `json:"bytesIn"`
+	BytesOut uint64 `json:"bytesOut"`
+}
+
+// TenantAccounting - Calls Tenant Accounting Management API to fetch
+// request count, bytes in/out and error count per access key,
+// aggregated across all servers.
+func (adm *AdminClient) TenantAccounting() (map[string]TenantStats, error) {
+	queryVal := make(url.Values)
+	queryVal.Set("accounting", "")
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "tenants")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?accounting to fetch request accounting.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("Got HTTP Status: " + resp.Status)
+	}
+
+	var tenants map[string]TenantStats
+	if err = json.NewDecoder(resp.Body).Decode(&tenants); err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
@@ -0,0 +1,147 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package madmin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// BucketIndexReport - discrepancies found while rebuilding the bucket
+// index from the disks.
+type BucketIndexReport struct {
+	Buckets        []string `json:"buckets"`
+	HealedBuckets  []string `json:"healedBuckets"`
+	OrphanBuckets  []string `json:"orphanBuckets"`
+	OrphanMetadata []string `json:"orphanMetadata"`
+}
+
+// LifecycleRule - expires objects whose name starts with Prefix once
+// they have not been modified for Days days.
+type LifecycleRule struct {
+	Prefix string `json:"prefix"`
+	Days   int    `json:"days"`
+}
+
+// RebuildBucketIndex - Calls Rebuild Bucket Index Management API to
+// reconcile buckets on the disks with their metadata.
+func (adm *AdminClient) RebuildBucketIndex() (BucketIndexReport, error) {
+	queryVal := make(url.Values)
+	queryVal.Set("bucket-index", "")
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "rebuild")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?bucket-index to rebuild the bucket index.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return BucketIndexReport{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return BucketIndexReport{}, errors.New("Got HTTP Status: " + resp.Status)
+	}
+
+	var report BucketIndexReport
+	if err = json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return BucketIndexReport{}, err
+	}
+	return report, nil
+}
+
+// SetBucketQuota - Calls Set Bucket Quota Management API to limit the
+// number of bytes stored in bucket, zero removes the quota.
+func (adm *AdminClient) SetBucketQuota(bucket string, quota int64) error {
+	queryVal := make(url.Values)
+	queryVal.Set("bucket-quota", "")
+	queryVal.Set("bucket", bucket)
+	queryVal.Set("quota", strconv.FormatInt(quota, 10))
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "set")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?bucket-quota to set the bucket quota.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("Got HTTP Status: " + resp.Status)
+	}
+	return nil
+}
+
+// SetBucketLifecycle - Calls Set Bucket Lifecycle Management API to
+// delete objects of bucket once expired by any of rules, no rules
+// removes the rules of the bucket.
+func (adm *AdminClient) SetBucketLifecycle(bucket string, rules []LifecycleRule) error {
+	if rules == nil {
+		rules = []LifecycleRule{}
+	}
+	body, err := json.Marshal(rules)
+	if err != nil {
+		return err
+	}
+
+	queryVal := make(url.Values)
+	queryVal.Set("bucket-lifecycle", "")
+	queryVal.Set("bucket", bucket)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "set")
+
+	reqData := requestData{
+		queryValues:        queryVal,
+		customHeaders:      hdrs,
+		contentBody:        bytes.NewReader(body),
+		contentLength:      int64(len(body)),
+		contentSHA256Bytes: sum256(body),
+	}
+
+	// Execute POST on /?bucket-lifecycle to set the bucket lifecycle.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("Got HTTP Status: " + resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// avoidDisk - sends avoid or unavoid disk command for a given endpoint.
+func (adm *AdminClient) avoidDisk(endpoint string, op string) error {
+	queryVal := make(url.Values)
+	queryVal.Set("disk", "")
+	queryVal.Set("endpoint", endpoint)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, op)
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?disk to update avoided disks.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("Got HTTP Status: " + resp.Status)
+	}
+	return nil
+}
+
+// AvoidDisk - Calls Avoid Disk Management API to exclude the disk at
+// endpoint from placement of new writes, the disk continues to serve
+// reads.
+func (adm *AdminClient) AvoidDisk(endpoint string) error {
+	return adm.avoidDisk(endpoint, "avoid")
+}
+
+// UnavoidDisk - Calls Unavoid Disk Management API to include a
+// previously avoided disk back for placement of new writes.
+func (adm *AdminClient) UnavoidDisk(endpoint string) error {
+	return adm.avoidDisk(endpoint, "unavoid")
+}
+
+// DecommissionObjectResult - state of an object drained by
+// DecommissionDisk, Relocated is set if its shards were moved off the
+// disk and Error if the object could not be relocated.
+type DecommissionObjectResult struct {
+	Bucket    string `json:"bucket"`
+	Object    string `json:"object"`
+	Relocated bool   `json:"relocated"`
+	Error     string `json:"error,omitempty"`
+}
+
+// DecommissionDiskResponse - objects drained by DecommissionDisk,
+// draining continues from NextMarker when IsTruncated is set.
+type DecommissionDiskResponse struct {
+	Objects     []DecommissionObjectResult `json:"objects"`
+	Relocated   int                        `json:"relocated"`
+	IsTruncated bool                       `json:"isTruncated"`
+	NextMarker  string                     `json:"nextMarker,omitempty"`
+}
+
+// DecommissionDisk - Calls Decommission Disk Management API to avoid
+// the disk at endpoint for new writes and relocate up to maxKeys
+// objects held by it to the remaining disks, starting after marker.
+// Zero maxKeys relocates up to 1000 objects.
+func (adm *AdminClient) DecommissionDisk(endpoint, marker string, maxKeys int) (DecommissionDiskResponse, error) {
+	queryVal := make(url.Values)
+	queryVal.Set("disk", "")
+	queryVal.Set("endpoint", endpoint)
+	queryVal.Set("marker", marker)
+	if maxKeys > 0 {
+		queryVal.Set("max-keys", strconv.Itoa(maxKeys))
+	}
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "decommission")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?disk to drain the disk.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return DecommissionDiskResponse{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return DecommissionDiskResponse{}, errors.New("Got HTTP Status: " + resp.Status)
+	}
+
+	var response DecommissionDiskResponse
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return DecommissionDiskResponse{}, err
+	}
+	return response, nil
+}
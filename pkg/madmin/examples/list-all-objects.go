@@ -0,0 +1,61 @@
+// +build ignore
+
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"log"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func main() {
+	// Note: YOUR-ACCESSKEYID, YOUR-SECRETACCESSKEY and my-bucketname are
+	// dummy values, please replace them with original values.
+
+	// API requests are secure (HTTPS) if secure=true and insecure (HTTPS) otherwise.
+	// New returns an Minio Admin client object.
+	madmClnt, err := madmin.New("your-minio.example.com:9000", "YOUR-ACCESSKEYID", "YOUR-SECRETACCESSKEY", true)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	// List 1000 objects at a time at up to 500 objects per second.
+	marker := ""
+	for {
+		nextMarker := ""
+		for object := range madmClnt.ListAllObjects(marker, 1000, 500, doneCh) {
+			if object.Err != nil {
+				log.Fatalln(object.Err)
+			}
+			if object.NextMarker != "" {
+				nextMarker = object.NextMarker
+				continue
+			}
+			log.Printf("%s/%s %d %s\n", object.Bucket, object.Key, object.Size, object.ModTime)
+		}
+		if nextMarker == "" {
+			break
+		}
+		marker = nextMarker
+	}
+}
@@ -0,0 +1,242 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package madmin
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ObjectInfo - object listed by ListAllObjects, Err is set if the
+// listing failed. NextMarker is only set on the last value sent when
+// the listing is truncated by maxKeys, it carries no object.
+type ObjectInfo struct {
+	Bucket     string    `json:"bucket"`
+	Key        string    `json:"key"`
+	Size       int64     `json:"size"`
+	ModTime    time.Time `json:"modTime"`
+	NextMarker string    `json:"-"`
+	Err        error     `json:"-"`
+}
+
+// listObjectsRecord - single record of the list objects response,
+// the last record carries the end of listing fields.
+type listObjectsRecord struct {
+	ObjectInfo
+	IsTruncated *bool  `json:"isTruncated"`
+	NextMarker  string `json:"nextMarker"`
+}
+
+// ListAllObjects - Calls List Objects Management API to list objects
+// of all buckets in lexical order after marker, of the form
+// bucket/object. Objects are sent on the returned channel as they are
+// received from the server, listing stops when doneCh is closed. Zero
+// maxKeys lists all the objects, otherwise listing continues from the
+// NextMarker of the last value sent. Zero rate lists at the default
+// rate of the server, 1000 objects per second.
+func (adm *AdminClient) ListAllObjects(marker string, maxKeys, rate int, doneCh <-chan struct{}) <-chan ObjectInfo {
+	objectCh := make(chan ObjectInfo)
+	go func() {
+		defer close(objectCh)
+
+		queryVal := make(url.Values)
+		queryVal.Set("object", "")
+		if marker != "" {
+			queryVal.Set("marker", marker)
+		}
+		if maxKeys > 0 {
+			queryVal.Set("max-keys", strconv.Itoa(maxKeys))
+		}
+		if rate > 0 {
+			queryVal.Set("rate", strconv.Itoa(rate))
+		}
+
+		hdrs := make(http.Header)
+		hdrs.Set(minioAdminOpHeader, "list")
+
+		reqData := requestData{
+			queryValues:   queryVal,
+			customHeaders: hdrs,
+		}
+
+		// Execute GET on /?object to list all objects.
+		resp, err := adm.executeMethod("GET", reqData)
+		defer closeResponse(resp)
+		if err == nil && resp.StatusCode != http.StatusOK {
+			err = errors.New("Got HTTP Status: " + resp.Status)
+		}
+		if err != nil {
+			select {
+			case objectCh <- ObjectInfo{Err: err}:
+			case <-doneCh:
+			}
+			return
+		}
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			var record listObjectsRecord
+			if err = json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				break
+			}
+			if record.IsTruncated != nil {
+				// End of listing, truncated listings continue from
+				// the next marker.
+				if *record.IsTruncated {
+					select {
+					case objectCh <- ObjectInfo{NextMarker: record.NextMarker}:
+					case <-doneCh:
+					}
+				}
+				return
+			}
+			select {
+			case objectCh <- record.ObjectInfo:
+			case <-doneCh:
+				return
+			}
+		}
+		if err == nil {
+			err = scanner.Err()
+		}
+		if err == nil {
+			err = errors.New("Listing ended unexpectedly")
+		}
+		select {
+		case objectCh <- ObjectInfo{Err: err}:
+		case <-doneCh:
+		}
+	}()
+	return objectCh
+}
+
+// HealStatus - state of an object found by HealObjects.
+type HealStatus string
+
+// States of objects healed by HealObjects.
+const (
+	// All the parts of the object are present and valid.
+	HealStatusHealthy HealStatus = "healthy"
+	// Missing or corrupted parts were rebuilt.
+	HealStatusHealed HealStatus = "healed"
+	// Too few valid parts are left to rebuild the object.
+	HealStatusCorrupt HealStatus = "corrupt"
+)
+
+// HealObjectResult - state of an object healed by HealObjects, Error
+// is set instead of Status if the object could not be healed.
+type HealObjectResult struct {
+	Object string     `json:"object"`
+	Status HealStatus `json:"status,omitempty"`
+	Error  string     `json:"error,omitempty"`
+}
+
+// HealObjectsResponse - objects healed by HealObjects, healing
+// continues from NextMarker when IsTruncated is set.
+type HealObjectsResponse struct {
+	Objects     []HealObjectResult `json:"objects"`
+	IsTruncated bool               `json:"isTruncated"`
+	NextMarker  string             `json:"nextMarker,omitempty"`
+}
+
+// HealObjects - Calls Heal Objects Management API to verify and heal
+// up to maxKeys objects of bucket matching prefix, starting after
+// marker. Zero maxKeys heals up to 1000 objects.
+func (adm *AdminClient) HealObjects(bucket, prefix, marker string, maxKeys int) (HealObjectsResponse, error) {
+	queryVal := make(url.Values)
+	queryVal.Set("object", "")
+	queryVal.Set("bucket", bucket)
+	queryVal.Set("prefix", prefix)
+	queryVal.Set("marker", marker)
+	if maxKeys > 0 {
+		queryVal.Set("max-keys", strconv.Itoa(maxKeys))
+	}
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "heal")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?object to heal objects.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return HealObjectsResponse{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return HealObjectsResponse{}, errors.New("Got HTTP Status: " + resp.Status)
+	}
+
+	var response HealObjectsResponse
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return HealObjectsResponse{}, err
+	}
+	return response, nil
+}
+
+// PendingHealInfo - object written without laggard disks which is yet
+// to be healed on them, Failed is set once healing in background gave
+// up on it.
+type PendingHealInfo struct {
+	Bucket string `json:"bucket"`
+	Object string `json:"object"`
+	Failed bool   `json:"failed"`
+}
+
+// ListPendingHeals - Calls List Pending Heals Management API to list
+// objects written without laggard disks which are yet to be healed.
+func (adm *AdminClient) ListPendingHeals() ([]PendingHealInfo, error) {
+	queryVal := make(url.Values)
+	queryVal.Set("object", "")
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "heal")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?object to list objects pending heal.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("Got HTTP Status: " + resp.Status)
+	}
+
+	var infos []PendingHealInfo
+	if err = json.NewDecoder(resp.Body).Decode(&infos); err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
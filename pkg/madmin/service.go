@@ -23,6 +23,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 // BackendType - represents different backend types.
@@ -39,12 +40,36 @@ const (
 	// Add your own backend.
 )
 
+// LatencyPercentiles - percentiles of the latency of recent disk
+// operations, zero if there were none.
+type LatencyPercentiles struct {
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+}
+
+// DiskLatency - recent latency of a disk, a slowly failing disk stays
+// online but its latency grows.
+type DiskLatency struct {
+	// Disk endpoint.
+	Disk string
+	// Latency of reads and writes of file data.
+	Read  LatencyPercentiles
+	Write LatencyPercentiles
+}
+
 // ServiceStatusMetadata - represents total capacity of underlying storage.
 type ServiceStatusMetadata struct {
 	// Total disk space.
 	Total int64
 	// Free available disk space.
 	Free int64
+	// Disk space and free space of all the disks summed up, more than
+	// Total and Free with erasure coding or disks of different sizes.
+	RawTotal int64
+	RawFree  int64
+	// Recent read and write latency of each disk.
+	Disks []DiskLatency
 	// Backend type.
 	Backend struct {
 		// Represents various backend types, currently on FS and XL.
@@ -55,6 +80,10 @@ type ServiceStatusMetadata struct {
 		OfflineDisks int // Offline disks during server startup.
 		ReadQuorum   int // Minimum disks required for successful read operations.
 		WriteQuorum  int // Minimum disks required for successful write operations.
+		AvoidedDisks int // Online disks excluded from new writes.
+		PendingHeals int // Objects written without laggard disks yet to be healed.
+		DataBlocks   int // Data blocks objects are erasure coded with.
+		ParityBlocks int // Parity blocks objects are erasure coded with.
 	}
 }
 
@@ -117,3 +146,45 @@ func (adm *AdminClient) ServiceRestart() error {
 	}
 	return nil
 }
+
+// ServerInfo - build information and uptime of a single server.
+type ServerInfo struct {
+	// Address of the server, as in the endpoints.
+	Addr       string        `json:"addr"`
+	Version    string        `json:"version"`
+	ReleaseTag string        `json:"releaseTag"`
+	CommitID   string        `json:"commitID"`
+	GoVersion  string        `json:"goVersion"`
+	BootTime   time.Time     `json:"bootTime"`
+	Uptime     time.Duration `json:"uptime"`
+	// Set if the server could not be reached.
+	Error string `json:"error,omitempty"`
+}
+
+// ServerInfo - Call Service Info API to fetch build information and
+// uptime of each server of the cluster.
+func (adm *AdminClient) ServerInfo() ([]ServerInfo, error) {
+	reqData := requestData{}
+	reqData.queryValues = make(url.Values)
+	reqData.queryValues.Set("service", "")
+	reqData.customHeaders = make(http.Header)
+	reqData.customHeaders.Set(minioAdminOpHeader, "info")
+
+	// Execute GET on /?service to fetch server info.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("Got HTTP Status: " + resp.Status)
+	}
+
+	var infos []ServerInfo
+	if err = json.NewDecoder(resp.Body).Decode(&infos); err != nil {
+		return nil, err
+	}
+	return infos, nil
+}